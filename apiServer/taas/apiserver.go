@@ -2,37 +2,78 @@ package main
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/models"
 	"github.com/portworx/torpedo/apiServer/taas/utils"
 	"log"
 )
 
 // We will define all API calls here.
 // Once Gin Server starts, it will initialise all APIs it contains.
+// Routes are listed in models.Routes, which the OpenAPI spec and the Go client are generated from,
+// and are mapped here to their handlers by operation ID. Each route requires the role set in its entry,
+// and the routes acting on a cluster act on the session selected by the X-TaaS-Session header.
 // Future work : To have segregated APIs based on need -> We will have to create multiple main calls for initialising.
 func main() {
+	if err := utils.InitJobs(); err != nil {
+		log.Fatalf("Failed to init jobs: %v", err)
+	}
+	if err := utils.InitSessions(); err != nil {
+		log.Fatalf("Failed to init sessions: %v", err)
+	}
+	authz, err := utils.NewAuthorizer()
+	if err != nil {
+		log.Fatalf("Failed to init authentication: %v", err)
+	}
+	handlers := map[string]gin.HandlerFunc{
+		"listJobs":                  utils.Jobs.ListJobs,
+		"getJob":                    utils.Jobs.GetJob,
+		"getJobLogs":                utils.Jobs.GetJobLogs,
+		"getJobEvents":              utils.Jobs.GetJobEvents,
+		"cancelJob":                 utils.Jobs.CancelJob,
+		"submitInitTorpedo":         utils.SubmitInitTorpedo,
+		"submitRebootNode":          utils.SubmitRebootNode,
+		"submitCollectSupport":      utils.SubmitCollectSupport,
+		"submitScheduleApps":        utils.SubmitScheduleApps,
+		"submitUpgradeStork":        utils.SubmitUpgradeStork,
+		"submitRunHelmCmd":          utils.SubmitRunHelmCmd,
+		"submitRunTriggers":         utils.SubmitRunTriggers,
+		"listTriggers":              utils.ListTriggers,
+		"listSessions":              utils.Sessions.ListSessions,
+		"createSession":             utils.Sessions.CreateSession,
+		"getSession":                utils.Sessions.GetSession,
+		"deleteSession":             utils.Sessions.DeleteSession,
+		"getOpenAPISpec":            utils.GetOpenAPISpec,
+		"deleteNamespace":           utils.DeleteNS,
+		"createNamespace":           utils.CreateNS,
+		"initTorpedo":               utils.InitializeDrivers,
+		"getNodes":                  utils.GetNodes,
+		"rebootNode":                utils.RebootNode,
+		"getStorageNodes":           utils.GetStorageNodes,
+		"getStorageLessNodes":       utils.GetStorageLessNodes,
+		"collectSupport":            utils.CollectSupport,
+		"scheduleApps":              utils.ScheduleAppsAndValidate,
+		"deployPxAgent":             utils.ExecuteHelmCmd,
+		"getClusterID":              utils.GetNamespaceID,
+		"getClusterNodeStatus":      utils.GetNodeStatus,
+		"runHelmCmd":                utils.ExecuteHelmCmd,
+		"getPxVersion":              utils.GetPxVersion,
+		"isPxInstalled":             utils.IsPxInstalled,
+		"getPxctlStatus":            utils.GetPxctlStatusOutput,
+		"getVMsByNamespaces":        utils.GetVMsInNamespaces,
+		"getVMsByNamespaceLabels":   utils.GetVMsWithNamespaceLabels,
+		"addNamespaceLabel":         utils.AddNSLabel,
+		"upgradeStork":              utils.UpgradeStork,
+		"deletePod":                 utils.DeletePod,
+		"getPxBackupNamespace":      utils.GetPxBackupNamespace,
+		"createVolumeSnapshotClass": utils.CreateVolumeSnapshotClass,
+	}
 	router := gin.Default()
-	router.DELETE("taas/deletens/:namespace", utils.DeleteNS)
-	router.POST("taas/createns", utils.CreateNS)
-	router.POST("taas/inittorpedo", utils.InitializeDrivers)
-	router.GET("taas/getnodes", utils.GetNodes)
-	router.POST("taas/rebootnode/:nodename", utils.RebootNode)
-	router.GET("taas/storagenodes", utils.GetStorageNodes)
-	router.GET("taas/storagelessnodes", utils.GetStorageLessNodes)
-	router.POST("taas/collectsupport", utils.CollectSupport)
-	router.POST("taas/scheduleapps", utils.ScheduleAppsAndValidate)
-	router.POST("taas/deploypxagent", utils.ExecuteHelmCmd)
-	router.GET("taas/getclusterid/:namespace", utils.GetNamespaceID)
-	router.GET("taas/getclusternodestatus", utils.GetNodeStatus)
-	router.POST("taas/runhelmcmd", utils.ExecuteHelmCmd)
-	router.GET("taas/pxversion", utils.GetPxVersion)
-	router.GET("taas/ispxinstalled", utils.IsPxInstalled)
-	router.GET("taas/getpxctloutput", utils.GetPxctlStatusOutput)
-	router.GET("taas/getkubevirtvmsbyns", utils.GetVMsInNamespaces)
-	router.GET("taas/getkubevirtvmsbynslabels", utils.GetVMsWithNamespaceLabels)
-	router.POST("taas/namespaces/addLabel", utils.AddNSLabel)
-	router.POST("taas/stork/upgrade", utils.UpgradeStork)
-	router.DELETE("taas/deletepod", utils.DeletePod)
-	router.GET("taas/getpxbackupnamespace", utils.GetPxBackupNamespace)
-	router.POST("taas/createvolumesnapshotclass", utils.CreateVolumeSnapshotClass)
+	for _, r := range models.Routes {
+		handler, ok := handlers[r.OperationID]
+		if !ok {
+			log.Fatalf("No handler for route [%s %s] with operation [%s]", r.Method, r.Path, r.OperationID)
+		}
+		router.Handle(r.Method, r.Path, authz.Handler(r), utils.Sessions.Handler(r), handler)
+	}
 	log.Fatal(router.Run(":8080"))
 }
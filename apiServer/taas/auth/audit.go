@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+// maxAuditBody is the size above which request bodies are truncated in the audit log
+const maxAuditBody = 64 << 10
+
+// AuditEvent is a line of the audit log
+type AuditEvent struct {
+	Time time.Time `json:"time"`
+	// Subject is the authenticated caller, empty if authentication failed
+	Subject     string `json:"subject,omitempty"`
+	RemoteAddr  string `json:"remoteAddr"`
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operationId"`
+	// Params are the path parameters, for e.g. the node to reboot
+	Params map[string]string `json:"params,omitempty"`
+	// Query are the query parameters
+	Query map[string][]string `json:"query,omitempty"`
+	// Body is the request body, a JSON string if the body is not JSON
+	Body json.RawMessage `json:"body,omitempty"`
+	// BodyTruncated is true if the body was larger than the logged part
+	BodyTruncated bool `json:"bodyTruncated,omitempty"`
+	// Status is the HTTP status of the response
+	Status int `json:"status"`
+	// Error is the reason the call was denied
+	Error string `json:"error,omitempty"`
+	// Location is the location of the submitted job, for async routes
+	Location string `json:"location,omitempty"`
+}
+
+// AuditLogger writes audit events as JSON lines
+type AuditLogger struct {
+	sync.Mutex
+	w io.Writer
+}
+
+// NewAuditLogger returns an audit logger writing to w
+func NewAuditLogger(w io.Writer) *AuditLogger {
+	return &AuditLogger{w: w}
+}
+
+// OpenAuditLog returns an audit logger appending to the file at path, or
+// writing to stdout if path is empty
+func OpenAuditLog(path string) (*AuditLogger, error) {
+	if path == "" {
+		return NewAuditLogger(os.Stdout), nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	return NewAuditLogger(f), nil
+}
+
+// Log writes an event. Failures are logged, as the call was already served.
+func (l *AuditLogger) Log(event *AuditEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Errorf("Failed to marshal audit event of [%s %s]: %v", event.Method, event.Path, err)
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+	if _, err := l.w.Write(append(data, '\n')); err != nil {
+		log.Errorf("Failed to write audit event of [%s %s]: %v", event.Method, event.Path, err)
+	}
+}
+
+// auditBody returns the body to log, as is if it is JSON and as a JSON string otherwise
+func auditBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return body
+	}
+	data, _ := json.Marshal(string(body))
+	return data
+}
@@ -0,0 +1,58 @@
+// Package auth authenticates the callers of the TaaS API server, authorizes
+// them by the role each route requires and writes the calls of the destructive
+// routes to an audit log.
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/portworx/torpedo/apiServer/taas/models"
+)
+
+var (
+	// ErrNoCredentials is returned for requests without a bearer token
+	ErrNoCredentials = errors.New("missing bearer token")
+	// ErrInvalidCredentials is returned for requests with an unknown or malformed bearer token
+	ErrInvalidCredentials = errors.New("invalid bearer token")
+)
+
+// Identity is an authenticated caller
+type Identity struct {
+	// Subject names the caller in the audit log
+	Subject string
+	// Roles are the roles granted to the caller
+	Roles []models.Role
+}
+
+// Allows returns true if one of the roles of the caller grants the routes requiring the given role
+func (i *Identity) Allows(required models.Role) bool {
+	for _, role := range i.Roles {
+		if role.Allows(required) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator authenticates the caller of a request. Static tokens are
+// supported for now, other schemes such as OIDC only need to implement it.
+type Authenticator interface {
+	// Authenticate returns the identity of the caller, ErrNoCredentials if the
+	// request has none and ErrInvalidCredentials if they are not valid
+	Authenticate(r *http.Request) (*Identity, error)
+}
+
+// BearerToken returns the token of the Authorization header of a request
+func BearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrNoCredentials
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", ErrInvalidCredentials
+	}
+	return strings.TrimSpace(token), nil
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/stretchr/testify/require"
+)
+
+const tokenFile = `
+tokens:
+- token: viewer-token
+  subject: dashboard
+  roles: [read-only]
+- token: ops-token
+  subject: ci-pipeline
+  roles: [app-ops]
+- token: admin-token
+  subject: oncall
+  roles: [destructive]
+`
+
+var testRoutes = []models.Route{
+	{Method: http.MethodGet, Path: "taas/openapi.json", OperationID: "getOpenAPISpec", Role: models.RolePublic},
+	{Method: http.MethodGet, Path: "taas/getnodes", OperationID: "getNodes", Role: models.RoleReadOnly},
+	{Method: http.MethodPost, Path: "taas/scheduleapps", OperationID: "scheduleApps", Role: models.RoleAppOps},
+	{Method: http.MethodPost, Path: "taas/rebootnode/:nodename", OperationID: "rebootNode", Role: models.RoleDestructive},
+}
+
+// newTestRouter serves the test routes, the handlers echoing the request body
+func newTestRouter(t *testing.T, authn Authenticator, audit io.Writer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authz := NewAuthorizer(authn, NewAuditLogger(audit))
+	router := gin.New()
+	for _, r := range testRoutes {
+		router.Handle(r.Method, r.Path, authz.Handler(r), func(c *gin.Context) {
+			body, err := io.ReadAll(c.Request.Body)
+			require.NoError(t, err)
+			subject := ""
+			if identity := IdentityFrom(c); identity != nil {
+				subject = identity.Subject
+			}
+			c.JSON(http.StatusOK, gin.H{"subject": subject, "body": string(body)})
+		})
+	}
+	return router
+}
+
+func serve(router *gin.Engine, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/"+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func loadTestTokens(t *testing.T) *TokenAuthenticator {
+	path := filepath.Join(t.TempDir(), "tokens.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(tokenFile), 0600))
+	authn, err := LoadTokenAuthenticator(path)
+	require.NoError(t, err)
+	return authn
+}
+
+func TestAuthorization(t *testing.T) {
+	router := newTestRouter(t, loadTestTokens(t), io.Discard)
+
+	for _, tc := range []struct {
+		name, method, path, token string
+		status                    int
+	}{
+		{"public route", http.MethodGet, "taas/openapi.json", "", http.StatusOK},
+		{"missing token", http.MethodGet, "taas/getnodes", "", http.StatusUnauthorized},
+		{"unknown token", http.MethodGet, "taas/getnodes", "guessed", http.StatusUnauthorized},
+		{"read-only route", http.MethodGet, "taas/getnodes", "viewer-token", http.StatusOK},
+		{"role too low", http.MethodPost, "taas/scheduleapps", "viewer-token", http.StatusForbidden},
+		{"app-ops route", http.MethodPost, "taas/scheduleapps", "ops-token", http.StatusOK},
+		{"higher role", http.MethodGet, "taas/getnodes", "admin-token", http.StatusOK},
+		{"destructive route", http.MethodPost, "taas/rebootnode/all", "ops-token", http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := serve(router, tc.method, tc.path, tc.token, "")
+			require.Equal(t, tc.status, w.Code, w.Body.String())
+			if tc.status == http.StatusUnauthorized {
+				require.Equal(t, `Bearer realm="taas"`, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/taas/getnodes", nil)
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), ErrInvalidCredentials.Error())
+}
+
+func TestAuditLog(t *testing.T) {
+	audit := &bytes.Buffer{}
+	router := newTestRouter(t, loadTestTokens(t), audit)
+
+	w := serve(router, http.MethodPost, "taas/rebootnode/node-1?force=true", "admin-token", `{"reason":"test"}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	// the handler still reads the whole body
+	require.JSONEq(t, `{"subject":"oncall","body":"{\"reason\":\"test\"}"}`, w.Body.String())
+	require.Equal(t, http.StatusForbidden, serve(router, http.MethodPost, "taas/rebootnode/node-2", "ops-token", "not json").Code)
+	// only the destructive routes are audited
+	require.Equal(t, http.StatusOK, serve(router, http.MethodGet, "taas/getnodes", "admin-token", "").Code)
+
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+	require.Len(t, lines, 2)
+	var allowed, denied AuditEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &allowed))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &denied))
+
+	require.Equal(t, "oncall", allowed.Subject)
+	require.Equal(t, "rebootNode", allowed.OperationID)
+	require.Equal(t, "/taas/rebootnode/node-1", allowed.Path)
+	require.Equal(t, map[string]string{"nodename": "node-1"}, allowed.Params)
+	require.Equal(t, map[string][]string{"force": {"true"}}, allowed.Query)
+	require.JSONEq(t, `{"reason":"test"}`, string(allowed.Body))
+	require.Equal(t, http.StatusOK, allowed.Status)
+	require.Empty(t, allowed.Error)
+
+	require.Equal(t, "ci-pipeline", denied.Subject)
+	require.Equal(t, `"not json"`, string(denied.Body))
+	require.Equal(t, http.StatusForbidden, denied.Status)
+	require.Equal(t, "role [destructive] required", denied.Error)
+}
+
+func TestAuthenticationDisabled(t *testing.T) {
+	audit := &bytes.Buffer{}
+	router := newTestRouter(t, nil, audit)
+	w := serve(router, http.MethodPost, "taas/rebootnode/all", "", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), Anonymous.Subject)
+	require.Contains(t, audit.String(), `"subject":"anonymous"`)
+}
+
+func TestInvalidTokens(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		tokens []Token
+		err    string
+	}{
+		{"no subject", []Token{{Token: "a", Roles: []models.Role{models.RoleReadOnly}}}, "must have a token and a subject"},
+		{"no roles", []Token{{Token: "a", Subject: "s"}}, "has no roles"},
+		{"unknown role", []Token{{Token: "a", Subject: "s", Roles: []models.Role{"admin"}}}, "unknown role [admin]"},
+		{"duplicate token", []Token{
+			{Token: "a", Subject: "s1", Roles: []models.Role{models.RoleReadOnly}},
+			{Token: "a", Subject: "s2", Roles: []models.Role{models.RoleReadOnly}},
+		}, "same token"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewTokenAuthenticator(tc.tokens)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+)
+
+// identityKey is the key of the identity of the caller in the gin context
+const identityKey = "taas.identity"
+
+// Anonymous is the identity of the callers when authentication is disabled
+var Anonymous = &Identity{Subject: "anonymous", Roles: []models.Role{models.RoleDestructive}}
+
+// Authorizer authenticates and authorizes the calls of the routes, and audits
+// the calls of the destructive routes
+type Authorizer struct {
+	authn Authenticator
+	audit *AuditLogger
+}
+
+// NewAuthorizer returns an authorizer. A nil authenticator disables
+// authentication, all callers being Anonymous. A nil audit logger disables the
+// audit log.
+func NewAuthorizer(authn Authenticator, audit *AuditLogger) *Authorizer {
+	return &Authorizer{authn: authn, audit: audit}
+}
+
+// IdentityFrom returns the identity of the caller, nil for public routes
+func IdentityFrom(c *gin.Context) *Identity {
+	if v, ok := c.Get(identityKey); ok {
+		return v.(*Identity)
+	}
+	return nil
+}
+
+// Handler returns the middleware of a route, to be registered before its handler
+func (a *Authorizer) Handler(route models.Route) gin.HandlerFunc {
+	audited := a.audit != nil && route.Role == models.RoleDestructive
+	return func(c *gin.Context) {
+		if route.Role == models.RolePublic {
+			c.Next()
+			return
+		}
+		var event *AuditEvent
+		if audited {
+			event = newAuditEvent(c, route)
+		}
+		identity, status, err := a.authorize(c.Request, route.Role)
+		if identity != nil && event != nil {
+			event.Subject = identity.Subject
+		}
+		if err != nil {
+			if status == http.StatusUnauthorized {
+				c.Header("WWW-Authenticate", `Bearer realm="taas"`)
+			}
+			c.AbortWithStatusJSON(status, models.ErrorResponse{Error: err.Error()})
+			if event != nil {
+				event.Status = status
+				event.Error = err.Error()
+				a.audit.Log(event)
+			}
+			return
+		}
+		c.Set(identityKey, identity)
+		c.Next()
+		if event != nil {
+			event.Status = c.Writer.Status()
+			event.Location = c.Writer.Header().Get("Location")
+			a.audit.Log(event)
+		}
+	}
+}
+
+// authorize returns the identity of the caller and, if the call is not
+// allowed, the status and the error to respond with
+func (a *Authorizer) authorize(r *http.Request, required models.Role) (*Identity, int, error) {
+	if a.authn == nil {
+		return Anonymous, 0, nil
+	}
+	identity, err := a.authn.Authenticate(r)
+	if err != nil {
+		if errors.Is(err, ErrNoCredentials) || errors.Is(err, ErrInvalidCredentials) {
+			return nil, http.StatusUnauthorized, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	if !identity.Allows(required) {
+		return identity, http.StatusForbidden, fmt.Errorf("role [%s] required", required)
+	}
+	return identity, 0, nil
+}
+
+// newAuditEvent returns the event of a call, reading the start of the request
+// body and leaving the body intact for the handler
+func newAuditEvent(c *gin.Context, route models.Route) *AuditEvent {
+	event := &AuditEvent{
+		Time:        time.Now().UTC(),
+		RemoteAddr:  c.ClientIP(),
+		Method:      c.Request.Method,
+		Path:        c.Request.URL.Path,
+		OperationID: route.OperationID,
+	}
+	if len(c.Params) > 0 {
+		event.Params = make(map[string]string, len(c.Params))
+		for _, p := range c.Params {
+			event.Params[p.Key] = p.Value
+		}
+	}
+	if query := c.Request.URL.Query(); len(query) > 0 {
+		event.Query = query
+	}
+	if c.Request.Body != nil {
+		head, _ := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBody+1))
+		c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(head), c.Request.Body), c.Request.Body}
+		if len(head) > maxAuditBody {
+			head = head[:maxAuditBody]
+			event.BodyTruncated = true
+		}
+		event.Body = auditBody(head)
+	}
+	return event
+}
+
+// readCloser reads from a reader and closes the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/ghodss/yaml"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+)
+
+// Token is a static bearer token and the identity it authenticates
+type Token struct {
+	// Token is the secret sent by the caller
+	Token string `json:"token"`
+	// Subject names the caller in the audit log
+	Subject string `json:"subject"`
+	// Roles are the roles granted to the caller
+	Roles []models.Role `json:"roles"`
+}
+
+// TokenFile is the file configuring the static tokens, for e.g.
+//
+//	tokens:
+//	- token: 8c1f...
+//	  subject: ci-pipeline
+//	  roles: [app-ops]
+type TokenFile struct {
+	Tokens []Token `json:"tokens"`
+}
+
+// TokenAuthenticator authenticates the callers by static bearer tokens
+type TokenAuthenticator struct {
+	// identities are indexed by the digest of their token, so a lookup does
+	// not leak the tokens through timing
+	identities map[[sha256.Size]byte]*Identity
+}
+
+// NewTokenAuthenticator returns an authenticator of the given tokens
+func NewTokenAuthenticator(tokens []Token) (*TokenAuthenticator, error) {
+	a := &TokenAuthenticator{identities: make(map[[sha256.Size]byte]*Identity)}
+	for i, t := range tokens {
+		if t.Token == "" || t.Subject == "" {
+			return nil, fmt.Errorf("token [%d] must have a token and a subject", i)
+		}
+		if len(t.Roles) == 0 {
+			return nil, fmt.Errorf("token of subject [%s] has no roles", t.Subject)
+		}
+		for _, role := range t.Roles {
+			if !role.Allows(models.RolePublic) {
+				return nil, fmt.Errorf("token of subject [%s] has unknown role [%s], expected one of %v", t.Subject, role, models.Roles)
+			}
+		}
+		digest := sha256.Sum256([]byte(t.Token))
+		if other, ok := a.identities[digest]; ok {
+			return nil, fmt.Errorf("subjects [%s] and [%s] have the same token", other.Subject, t.Subject)
+		}
+		a.identities[digest] = &Identity{Subject: t.Subject, Roles: t.Roles}
+	}
+	return a, nil
+}
+
+// LoadTokenAuthenticator returns an authenticator of the tokens of a YAML or JSON TokenFile
+func LoadTokenAuthenticator(path string) (*TokenAuthenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %v", err)
+	}
+	var file TokenFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse token file [%s]: %v", path, err)
+	}
+	return NewTokenAuthenticator(file.Tokens)
+}
+
+// Authenticate returns the identity of the bearer token of the request
+func (a *TokenAuthenticator) Authenticate(r *http.Request) (*Identity, error) {
+	token, err := BearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	identity, ok := a.identities[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+	return identity, nil
+}
@@ -0,0 +1,396 @@
+// Package client is a Go client of the TaaS API server. Long-running operations
+// are submitted as jobs, which the client can poll, cancel and wait for.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/portworx/torpedo/apiServer/taas/models"
+)
+
+const (
+	// DefaultPollInterval is the default interval at which WaitForJob polls the job
+	DefaultPollInterval = 10 * time.Second
+)
+
+// Client calls a TaaS API server
+type Client struct {
+	// BaseURL is the URL of the API server, for e.g. http://taas:8080
+	BaseURL string
+	// HTTPClient sends the requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+	// PollInterval is the interval at which WaitForJob polls the job, DefaultPollInterval if not set
+	PollInterval time.Duration
+	// Token is the bearer token authenticating the client, if the server requires one
+	Token string
+	// Session is the ID of the session the requests act on, the default session if empty
+	Session string
+}
+
+// New returns a client of the API server at baseURL
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Error is the error of a request the API server failed
+type Error struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	// Message is the error returned by the server
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("taas request failed with status [%d]: %s", e.StatusCode, e.Message)
+}
+
+// JobError is the error of a job which did not succeed
+type JobError struct {
+	Job *models.Job
+}
+
+func (e *JobError) Error() string {
+	return fmt.Sprintf("job [%s] of type [%s] %s: %s", e.Job.ID, e.Job.Type, e.Job.Status, e.Job.Error)
+}
+
+// ListJobs returns the jobs of a type and status, empty filters match all jobs
+func (c *Client) ListJobs(ctx context.Context, jobType string, status models.JobStatus) ([]models.Job, error) {
+	query := url.Values{}
+	if jobType != "" {
+		query.Set("type", jobType)
+	}
+	if status != "" {
+		query.Set("status", string(status))
+	}
+	list := &models.JobList{}
+	if err := c.do(ctx, http.MethodGet, "taas/jobs", query, nil, list); err != nil {
+		return nil, err
+	}
+	return list.Jobs, nil
+}
+
+// GetJob returns a job
+func (c *Client) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	job := &models.Job{}
+	if err := c.do(ctx, http.MethodGet, "taas/jobs/"+url.PathEscape(id), nil, nil, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetJobLogs returns the log lines of a job from an offset
+func (c *Client) GetJobLogs(ctx context.Context, id string, offset int) (*models.JobLogs, error) {
+	query := url.Values{"offset": []string{strconv.Itoa(offset)}}
+	logs := &models.JobLogs{}
+	if err := c.do(ctx, http.MethodGet, "taas/jobs/"+url.PathEscape(id)+"/logs", query, nil, logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// CancelJob cancels a job
+func (c *Client) CancelJob(ctx context.Context, id string) (*models.Job, error) {
+	job := &models.Job{}
+	if err := c.do(ctx, http.MethodPost, "taas/jobs/"+url.PathEscape(id)+"/cancel", nil, nil, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetJobEvents returns the events of a job from an offset
+func (c *Client) GetJobEvents(ctx context.Context, id string, offset int) (*models.JobEvents, error) {
+	query := url.Values{"offset": []string{strconv.Itoa(offset)}}
+	events := &models.JobEvents{}
+	if err := c.do(ctx, http.MethodGet, "taas/jobs/"+url.PathEscape(id)+"/events", query, nil, events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// FollowJobEvents streams the events of a job from an offset, calling handle
+// for each event, until the job completes. It returns the completed job, or
+// the error of handle which stops the stream.
+func (c *Client) FollowJobEvents(ctx context.Context, id string, offset int, handle func(event json.RawMessage) error) (*models.Job, error) {
+	query := url.Values{"offset": []string{strconv.Itoa(offset)}, "follow": []string{"true"}}
+	resp, err := c.send(ctx, http.MethodGet, "taas/jobs/"+url.PathEscape(id)+"/events", query, nil, "text/event-stream")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// events are separated by empty lines, their fields being "name:value" lines
+	var name string
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				name = value
+			case "data":
+				data = append(data, value)
+			}
+			continue
+		}
+		payload := []byte(strings.Join(data, "\n"))
+		switch name {
+		case "event":
+			if err := handle(payload); err != nil {
+				return nil, err
+			}
+		case "done":
+			job := &models.Job{}
+			if err := json.Unmarshal(payload, job); err != nil {
+				return nil, fmt.Errorf("failed to parse job [%s]: %v", id, err)
+			}
+			return job, nil
+		}
+		name, data = "", nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("events stream of job [%s] ended before the job completed", id)
+}
+
+// TriggerEvents decodes the events of a runtriggers job
+func TriggerEvents(events []json.RawMessage) ([]models.TriggerEvent, error) {
+	triggerEvents := make([]models.TriggerEvent, len(events))
+	for i, event := range events {
+		if err := json.Unmarshal(event, &triggerEvents[i]); err != nil {
+			return nil, err
+		}
+	}
+	return triggerEvents, nil
+}
+
+// ListTriggers returns the names of the test triggers which can be run
+func (c *Client) ListTriggers(ctx context.Context) ([]string, error) {
+	list := &models.TriggerList{}
+	if err := c.do(ctx, http.MethodGet, "taas/triggers", nil, nil, list); err != nil {
+		return nil, err
+	}
+	return list.Triggers, nil
+}
+
+// WaitForJob polls a job until it completes or ctx is done. A JobError is
+// returned, along with the job, if the job did not succeed.
+func (c *Client) WaitForJob(ctx context.Context, id string) (*models.Job, error) {
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		job, err := c.GetJob(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status.Done() {
+			if job.Status != models.JobSucceeded {
+				return job, &JobError{Job: job}
+			}
+			return job, nil
+		}
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// DecodeResult decodes the result of a succeeded job into out
+func DecodeResult(job *models.Job, out interface{}) error {
+	if len(job.Result) == 0 {
+		return fmt.Errorf("job [%s] has no result", job.ID)
+	}
+	return json.Unmarshal(job.Result, out)
+}
+
+// ListSessions returns the sessions
+func (c *Client) ListSessions(ctx context.Context) ([]models.Session, error) {
+	list := &models.SessionList{}
+	if err := c.do(ctx, http.MethodGet, "taas/sessions", nil, nil, list); err != nil {
+		return nil, err
+	}
+	return list.Sessions, nil
+}
+
+// CreateSession creates a session, whose ID can then be set as the Session of a client
+func (c *Client) CreateSession(ctx context.Context, req models.CreateSessionRequest) (*models.Session, error) {
+	session := &models.Session{}
+	if err := c.do(ctx, http.MethodPost, "taas/sessions", nil, req, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// DeleteSession deletes a session
+func (c *Client) DeleteSession(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "taas/sessions/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// SubmitInitTorpedo submits a job initializing the torpedo drivers
+func (c *Client) SubmitInitTorpedo(ctx context.Context) (*models.Job, error) {
+	return c.submit(ctx, "taas/jobs/inittorpedo", nil)
+}
+
+// SubmitRebootNode submits a job rebooting a node by name, or all nodes with
+// "all", or a random node with "random"
+func (c *Client) SubmitRebootNode(ctx context.Context, nodeName string) (*models.Job, error) {
+	return c.submit(ctx, "taas/jobs/rebootnode/"+url.PathEscape(nodeName), nil)
+}
+
+// SubmitCollectSupport submits a job collecting the support bundle
+func (c *Client) SubmitCollectSupport(ctx context.Context) (*models.Job, error) {
+	return c.submit(ctx, "taas/jobs/collectsupport", nil)
+}
+
+// SubmitScheduleApps submits a job scheduling and validating applications
+func (c *Client) SubmitScheduleApps(ctx context.Context, req models.ScheduleAppsRequest) (*models.Job, error) {
+	return c.submit(ctx, "taas/jobs/scheduleapps", req)
+}
+
+// SubmitUpgradeStork submits a job upgrading stork
+func (c *Client) SubmitUpgradeStork(ctx context.Context, req models.UpgradeStorkRequest) (*models.Job, error) {
+	return c.submit(ctx, "taas/jobs/stork/upgrade", req)
+}
+
+// SubmitRunHelmCmd submits a job running a helm command
+func (c *Client) SubmitRunHelmCmd(ctx context.Context, req models.HelmPayload) (*models.Job, error) {
+	return c.submit(ctx, "taas/jobs/runhelmcmd", req)
+}
+
+// SubmitRunTriggers submits a job running test triggers against the scheduled applications
+func (c *Client) SubmitRunTriggers(ctx context.Context, req models.RunTriggersRequest) (*models.Job, error) {
+	return c.submit(ctx, "taas/jobs/triggers", req)
+}
+
+// ScheduleApps schedules and validates applications and waits for the result
+func (c *Client) ScheduleApps(ctx context.Context, req models.ScheduleAppsRequest) (*models.ScheduleAppsResult, error) {
+	job, err := c.SubmitScheduleApps(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if job, err = c.WaitForJob(ctx, job.ID); err != nil {
+		return nil, err
+	}
+	result := &models.ScheduleAppsResult{}
+	if err = DecodeResult(job, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RebootNode reboots nodes and waits for the result
+func (c *Client) RebootNode(ctx context.Context, nodeName string) (*models.RebootNodeResult, error) {
+	job, err := c.SubmitRebootNode(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	if job, err = c.WaitForJob(ctx, job.ID); err != nil {
+		return nil, err
+	}
+	result := &models.RebootNodeResult{}
+	if err = DecodeResult(job, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) submit(ctx context.Context, path string, request interface{}) (*models.Job, error) {
+	job := &models.Job{}
+	if err := c.do(ctx, http.MethodPost, path, nil, request, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// do sends a request with a JSON body, if not nil, and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	resp, err := c.send(ctx, method, path, query, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response of [%s %s]: %v", method, path, err)
+	}
+	return nil
+}
+
+// send sends a request with a JSON body, if not nil, and returns the response
+// if it succeeded. The caller closes the response body.
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body interface{}, accept string) (*http.Response, error) {
+	u := strings.TrimSuffix(c.BaseURL, "/") + "/" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", accept)
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if c.Session != "" {
+		req.Header.Set(models.SessionHeader, c.Session)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		errResp := models.ErrorResponse{}
+		if json.Unmarshal(data, &errResp) != nil || errResp.Error == "" {
+			errResp.Error = strings.TrimSpace(string(data))
+		}
+		return nil, &Error{StatusCode: resp.StatusCode, Message: errResp.Error}
+	}
+	return resp, nil
+}
@@ -0,0 +1,243 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/auth"
+	"github.com/portworx/torpedo/apiServer/taas/jobs"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/portworx/torpedo/apiServer/taas/sessions"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testToken  = "test-token"
+	adminToken = "admin-token"
+)
+
+// noDrivers are the drivers of the test sessions
+type noDrivers struct{}
+
+func (noDrivers) Use() error { return nil }
+
+// newTestServer serves all routes, the job routes being backed by a real job
+// manager running run for the submitted jobs
+func newTestServer(t *testing.T, run jobs.RunFunc) *Client {
+	gin.SetMode(gin.TestMode)
+	m, err := jobs.NewManager(jobs.NewMemoryStore(), 1)
+	require.NoError(t, err)
+	t.Cleanup(m.Stop)
+
+	sm, err := sessions.NewManager(t.TempDir(), t.TempDir(), func(*sessions.Session, []string) sessions.Drivers { return noDrivers{} })
+	require.NoError(t, err)
+
+	handlers := map[string]gin.HandlerFunc{
+		"listSessions":  sm.ListSessions,
+		"createSession": sm.CreateSession,
+		"deleteSession": sm.DeleteSession,
+		"listJobs":      m.ListJobs,
+		"getJob":        m.GetJob,
+		"getJobLogs":    m.GetJobLogs,
+		"getJobEvents":  m.GetJobEvents,
+		"cancelJob":     m.CancelJob,
+		"submitScheduleApps": func(c *gin.Context) {
+			var req models.ScheduleAppsRequest
+			if err := c.BindJSON(&req); err != nil {
+				return
+			}
+			m.SubmitJob(c, models.JobScheduleApps, req, run)
+		},
+		"submitRunTriggers": func(c *gin.Context) {
+			var req models.RunTriggersRequest
+			if err := c.BindJSON(&req); err != nil {
+				return
+			}
+			m.SubmitJob(c, models.JobRunTriggers, req, run)
+		},
+	}
+	authn, err := auth.NewTokenAuthenticator([]auth.Token{
+		{Token: testToken, Subject: "client-test", Roles: []models.Role{models.RoleAppOps}},
+		{Token: adminToken, Subject: "client-test-admin", Roles: []models.Role{models.RoleDestructive}},
+	})
+	require.NoError(t, err)
+	authz := auth.NewAuthorizer(authn, nil)
+	router := gin.New()
+	for _, r := range models.Routes {
+		handler, ok := handlers[r.OperationID]
+		if !ok {
+			handler = func(c *gin.Context) {
+				c.JSON(http.StatusNotImplemented, models.ErrorResponse{Error: "not implemented"})
+			}
+		}
+		router.Handle(r.Method, r.Path, authz.Handler(r), sm.Handler(r), handler)
+	}
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	c := New(server.URL + "/")
+	c.PollInterval = 10 * time.Millisecond
+	c.Token = testToken
+	return c
+}
+
+func TestScheduleApps(t *testing.T) {
+	c := newTestServer(t, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		logger.Infof("scheduling apps")
+		return models.ScheduleAppsResult{Namespaces: []string{"mysql-taas"}}, nil
+	})
+	ctx := context.Background()
+
+	result, err := c.ScheduleApps(ctx, models.ScheduleAppsRequest{AppList: []string{"mysql"}, NamespaceSuffix: "taas"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"mysql-taas"}, result.Namespaces)
+
+	list, err := c.ListJobs(ctx, models.JobScheduleApps, models.JobSucceeded)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	logs, err := c.GetJobLogs(ctx, list[0].ID, 0)
+	require.NoError(t, err)
+	require.Contains(t, logs.Lines[1], "scheduling apps")
+
+	// cancelling a job is destructive
+	_, err = c.CancelJob(ctx, list[0].ID)
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	c.Token = adminToken
+	_, err = c.CancelJob(ctx, list[0].ID)
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusConflict, apiErr.StatusCode)
+	require.Equal(t, jobs.ErrDone.Error(), apiErr.Message)
+
+	// the request body is validated before the job is submitted
+	_, err = c.SubmitScheduleApps(ctx, models.ScheduleAppsRequest{})
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+}
+
+func TestWaitForFailedJob(t *testing.T) {
+	c := newTestServer(t, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		return nil, errors.New("app validation failed")
+	})
+	_, err := c.ScheduleApps(context.Background(), models.ScheduleAppsRequest{AppList: []string{"fio"}})
+	var jobErr *JobError
+	require.True(t, errors.As(err, &jobErr))
+	require.Equal(t, models.JobFailed, jobErr.Job.Status)
+	require.Equal(t, "app validation failed", jobErr.Job.Error)
+
+	_, err = c.GetJob(context.Background(), "unknown")
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	started := make(chan struct{})
+	c := newTestServer(t, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	c.Token = adminToken
+	ctx := context.Background()
+	job, err := c.SubmitScheduleApps(ctx, models.ScheduleAppsRequest{AppList: []string{"fio"}})
+	require.NoError(t, err)
+	<-started
+
+	job, err = c.CancelJob(ctx, job.ID)
+	require.NoError(t, err)
+	require.True(t, job.CancelRequested)
+	job, err = c.WaitForJob(ctx, job.ID)
+	require.Error(t, err)
+	require.Equal(t, models.JobCancelled, job.Status)
+}
+
+func TestAuthentication(t *testing.T) {
+	c := newTestServer(t, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		return nil, nil
+	})
+	ctx := context.Background()
+
+	_, err := c.SubmitRunHelmCmd(ctx, models.HelmPayload{Command: "helm list"})
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+	c.Token = ""
+	_, err = c.ListJobs(ctx, "", "")
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestFollowJobEvents(t *testing.T) {
+	jobs.FollowInterval = 10 * time.Millisecond
+	proceed := make(chan struct{})
+	c := newTestServer(t, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		logger.Event(models.TriggerEvent{ID: "1", Type: "rebootNode", Nodes: []string{"node-1"}})
+		<-proceed
+		logger.Event(models.TriggerEvent{ID: "2", Type: "crashVolDriver", Errors: []string{"px did not come up"}})
+		return models.RunTriggersResult{Events: 2, FailedEvents: 1}, nil
+	})
+	c.Token = adminToken
+	ctx := context.Background()
+
+	job, err := c.SubmitRunTriggers(ctx, models.RunTriggersRequest{Triggers: []string{"rebootNode", "crashVolDriver"}})
+	require.NoError(t, err)
+	var events []json.RawMessage
+	job, err = c.FollowJobEvents(ctx, job.ID, 0, func(event json.RawMessage) error {
+		events = append(events, event)
+		if len(events) == 1 {
+			close(proceed)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, models.JobSucceeded, job.Status)
+	triggerEvents, err := TriggerEvents(events)
+	require.NoError(t, err)
+	require.Equal(t, []models.TriggerEvent{
+		{ID: "1", Type: "rebootNode", Nodes: []string{"node-1"}},
+		{ID: "2", Type: "crashVolDriver", Errors: []string{"px did not come up"}},
+	}, triggerEvents)
+
+	page, err := c.GetJobEvents(ctx, job.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, page.Events, 1)
+	require.Equal(t, 2, page.Next)
+}
+
+func TestSessions(t *testing.T) {
+	c := newTestServer(t, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		return models.ScheduleAppsResult{}, nil
+	})
+	ctx := context.Background()
+
+	session, err := c.CreateSession(ctx, models.CreateSessionRequest{Name: "dr-pair", Kubeconfig: "source", DestinationKubeconfig: "destination"})
+	require.NoError(t, err)
+	require.True(t, session.HasDestination)
+	list, err := c.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	c.Session = session.ID
+	_, err = c.ScheduleApps(ctx, models.ScheduleAppsRequest{AppList: []string{"mysql"}})
+	require.NoError(t, err)
+
+	c.Session = ""
+	var apiErr *Error
+	require.True(t, errors.As(c.DeleteSession(ctx, session.ID), &apiErr))
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	c.Token = adminToken
+	require.NoError(t, c.DeleteSession(ctx, session.ID))
+	c.Session = session.ID
+	_, err = c.SubmitScheduleApps(ctx, models.ScheduleAppsRequest{AppList: []string{"mysql"}})
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+}
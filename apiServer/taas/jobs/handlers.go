@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+)
+
+// FollowInterval is the interval at which the followed events of a job are polled
+var FollowInterval = time.Second
+
+// ListJobs : Lists the jobs, filtered by the type and status query parameters
+func (m *Manager) ListJobs(c *gin.Context) {
+	jobs, err := m.List(c.Query("type"), models.JobStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.JobList{Jobs: jobs})
+}
+
+// GetJob : Returns the job with the id path parameter
+func (m *Manager) GetJob(c *gin.Context) {
+	job, err := m.Get(c.Param("id"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// GetJobLogs : Returns the log lines of a job from the offset query parameter
+func (m *Manager) GetJobLogs(c *gin.Context) {
+	offset, ok := queryOffset(c)
+	if !ok {
+		return
+	}
+	logs, err := m.Logs(c.Param("id"), offset)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, logs)
+}
+
+// GetJobEvents : Returns the events of a job from the offset query parameter.
+// With follow=true, the events are streamed as server-sent events until the
+// job completes, a final done event carrying the job.
+func (m *Manager) GetJobEvents(c *gin.Context) {
+	offset, ok := queryOffset(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	if c.Query("follow") != "true" {
+		events, err := m.Events(id, offset)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, events)
+		return
+	}
+	if _, err := m.Get(id); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	ticker := time.NewTicker(FollowInterval)
+	defer ticker.Stop()
+	for {
+		// load the job before its events, so no event is missed once it is done
+		job, err := m.Get(id)
+		if err != nil {
+			return
+		}
+		events, err := m.Events(id, offset)
+		if err != nil {
+			return
+		}
+		for i, event := range events.Events {
+			c.Render(-1, sse.Event{Id: strconv.Itoa(offset + i), Event: "event", Data: string(event)})
+		}
+		offset = events.Next
+		if job.Status.Done() {
+			c.Render(-1, sse.Event{Event: "done", Data: job})
+			return
+		}
+		c.Writer.Flush()
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// queryOffset returns the offset query parameter, responding with an error if it is invalid
+func queryOffset(c *gin.Context) (int, bool) {
+	o := c.Query("offset")
+	if o == "" {
+		return 0, true
+	}
+	offset, err := strconv.Atoi(o)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "offset must be a non negative integer"})
+		return 0, false
+	}
+	return offset, true
+}
+
+// CancelJob : Cancels the job with the id path parameter
+func (m *Manager) CancelJob(c *gin.Context) {
+	job, err := m.Cancel(c.Param("id"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// SubmitJob submits a job and responds with it
+func (m *Manager) SubmitJob(c *gin.Context, jobType string, request interface{}, run RunFunc) {
+	job, err := m.Submit(jobType, request, run)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Header("Location", "/taas/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+func abortWithError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrDone):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,345 @@
+// Package jobs runs the long-running operations of the TaaS API server as
+// asynchronous jobs. A job is persisted when submitted and each time its status
+// changes, so clients can poll its status, logs and result, and cancel it.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+var (
+	// ErrNotFound is returned for unknown job IDs
+	ErrNotFound = errors.New("job not found")
+	// ErrDone is returned when cancelling a job which already completed
+	ErrDone = errors.New("job already completed")
+)
+
+// errInterrupted is the error of the jobs which were running when the server stopped
+const errInterrupted = "interrupted by an API server restart"
+
+// RunFunc runs a job and returns its result. Torpedo calls don't take a context,
+// so a RunFunc should check ctx between steps to stop early when cancelled.
+type RunFunc func(ctx context.Context, logger *Logger) (interface{}, error)
+
+// Manager queues the submitted jobs and runs them on a fixed number of workers
+type Manager struct {
+	sync.Mutex
+	store   Store
+	queue   chan string
+	runs    map[string]RunFunc
+	cancels map[string]context.CancelFunc
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewManager returns a manager running the jobs on the given number of workers.
+// Jobs left pending or running by a previous server are marked failed, their
+// run functions being lost.
+func NewManager(store Store, workers int) (*Manager, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	m := &Manager{
+		store:   store,
+		queue:   make(chan string, 1024),
+		runs:    make(map[string]RunFunc),
+		cancels: make(map[string]context.CancelFunc),
+		stop:    make(chan struct{}),
+	}
+	jobs, err := store.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stored jobs: %v", err)
+	}
+	for _, job := range jobs {
+		if job.Status.Done() {
+			continue
+		}
+		m.finish(job, nil, errors.New(errInterrupted))
+	}
+	for i := 0; i < workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	return m, nil
+}
+
+// Stop stops the workers once their running jobs complete
+func (m *Manager) Stop() {
+	close(m.stop)
+	m.wg.Wait()
+}
+
+// Submit persists a pending job and queues it
+func (m *Manager) Submit(jobType string, request interface{}, run RunFunc) (*models.Job, error) {
+	job := &models.Job{
+		ID:        uuid.New(),
+		Type:      jobType,
+		Status:    models.JobPending,
+		CreatedAt: time.Now().UTC(),
+	}
+	if request != nil {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request of job [%s]: %v", jobType, err)
+		}
+		job.Request = data
+	}
+	if err := m.store.Save(job); err != nil {
+		return nil, err
+	}
+
+	m.Lock()
+	m.runs[job.ID] = run
+	m.Unlock()
+	select {
+	case m.queue <- job.ID:
+	default:
+		m.Lock()
+		delete(m.runs, job.ID)
+		m.Unlock()
+		m.finish(job, nil, errors.New("job queue is full"))
+		return nil, fmt.Errorf("job queue is full, [%d] jobs pending", cap(m.queue))
+	}
+	log.Infof("Submitted job [%s] of type [%s]", job.ID, jobType)
+	return job, nil
+}
+
+// Get returns a job
+func (m *Manager) Get(id string) (*models.Job, error) {
+	return m.store.Load(id)
+}
+
+// List returns the jobs of a type and status, most recent first. Empty filters match all jobs.
+func (m *Manager) List(jobType string, status models.JobStatus) ([]models.Job, error) {
+	all, err := m.store.List()
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]models.Job, 0, len(all))
+	for _, job := range all {
+		if (jobType == "" || job.Type == jobType) && (status == "" || job.Status == status) {
+			jobs = append(jobs, *job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+// Logs returns the log lines of a job from an offset
+func (m *Manager) Logs(id string, offset int) (*models.JobLogs, error) {
+	if _, err := m.store.Load(id); err != nil {
+		return nil, err
+	}
+	lines, err := m.store.Logs(id, offset)
+	if err != nil {
+		return nil, err
+	}
+	if lines == nil {
+		lines = []string{}
+	}
+	return &models.JobLogs{ID: id, Lines: lines, Next: offset + len(lines)}, nil
+}
+
+// Events returns the events of a job from an offset
+func (m *Manager) Events(id string, offset int) (*models.JobEvents, error) {
+	if _, err := m.store.Load(id); err != nil {
+		return nil, err
+	}
+	events, err := m.store.Events(id, offset)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []json.RawMessage{}
+	}
+	return &models.JobEvents{ID: id, Events: events, Next: offset + len(events)}, nil
+}
+
+// Cancel cancels a pending job right away. A running job is asked to stop and
+// is cancelled once its run function returns.
+func (m *Manager) Cancel(id string) (*models.Job, error) {
+	m.Lock()
+	defer m.Unlock()
+	job, err := m.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status.Done() {
+		return job, ErrDone
+	}
+	if _, pending := m.runs[id]; pending && job.Status == models.JobPending {
+		// the worker skips the job when it dequeues it
+		delete(m.runs, id)
+		now := time.Now().UTC()
+		job.Status = models.JobCancelled
+		job.Error = context.Canceled.Error()
+		job.FinishedAt = &now
+		return job, m.store.Save(job)
+	}
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+	}
+	job.CancelRequested = true
+	return job, m.store.Save(job)
+}
+
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case id := <-m.queue:
+			m.run(id)
+		}
+	}
+}
+
+func (m *Manager) run(id string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m.Lock()
+	run, ok := m.runs[id]
+	delete(m.runs, id)
+	if !ok {
+		m.Unlock()
+		return
+	}
+	job, err := m.store.Load(id)
+	if err != nil {
+		m.Unlock()
+		log.Errorf("Failed to load job [%s]: %v", id, err)
+		return
+	}
+	now := time.Now().UTC()
+	job.Status = models.JobRunning
+	job.StartedAt = &now
+	m.cancels[id] = cancel
+	err = m.store.Save(job)
+	m.Unlock()
+	if err != nil {
+		log.Errorf("Failed to save job [%s]: %v", id, err)
+	}
+
+	logger := &Logger{store: m.store, id: id}
+	logger.Infof("Running job [%s] of type [%s]", id, job.Type)
+	result, err := runSafely(ctx, logger, run)
+	if err != nil {
+		logger.Errorf("Job failed: %v", err)
+	} else {
+		logger.Infof("Job succeeded")
+	}
+
+	m.Lock()
+	delete(m.cancels, id)
+	// reload the job to keep the cancel request
+	if stored, loadErr := m.store.Load(id); loadErr == nil {
+		job = stored
+	}
+	// a job returning nothing once cancelled was interrupted rather than done
+	if ctx.Err() != nil && err == nil && result == nil {
+		err = ctx.Err()
+	}
+	m.finish(job, result, err)
+	m.Unlock()
+}
+
+// runSafely runs the job, turning a panic, for e.g. of a failed ginkgo assertion, into an error
+func runSafely(ctx context.Context, logger *Logger, run RunFunc) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+			logger.Errorf("%s", debug.Stack())
+		}
+	}()
+	return run(ctx, logger)
+}
+
+// finish saves the completed job with its result or error. A job which succeeded
+// before noticing its cancellation is kept as succeeded.
+func (m *Manager) finish(job *models.Job, result interface{}, err error) {
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	switch {
+	case err == nil:
+		job.Status = models.JobSucceeded
+		if result != nil {
+			data, marshalErr := json.Marshal(result)
+			if marshalErr != nil {
+				job.Status = models.JobFailed
+				job.Error = fmt.Sprintf("failed to marshal result: %v", marshalErr)
+			} else {
+				job.Result = data
+			}
+		}
+	case job.CancelRequested:
+		job.Status = models.JobCancelled
+		job.Error = context.Canceled.Error()
+	default:
+		job.Status = models.JobFailed
+		job.Error = err.Error()
+	}
+	if saveErr := m.store.Save(job); saveErr != nil {
+		log.Errorf("Failed to save job [%s]: %v", job.ID, saveErr)
+	}
+}
+
+// Logger appends log lines to the logs of a job, and to the server log, and
+// records the events of the job. A nil Logger only logs to the server log, so
+// code shared by synchronous handlers and jobs can log the same way.
+type Logger struct {
+	store Store
+	id    string
+}
+
+// Infof logs an informational line
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.append("INFO", format, args...)
+}
+
+// Errorf logs an error line
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.append("ERROR", format, args...)
+}
+
+// Event records an event of the job, for e.g. a trigger execution. A nil
+// Logger drops the event.
+func (l *Logger) Event(event interface{}) {
+	if l == nil {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err == nil {
+		err = l.store.AppendEvent(l.id, data)
+	}
+	if err != nil {
+		log.Errorf("Failed to append to events of job [%s]: %v", l.id, err)
+	}
+}
+
+func (l *Logger) append(level, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if l == nil {
+		log.Infof("%s", msg)
+		return
+	}
+	log.Infof("[job %s] %s", l.id, msg)
+	line := fmt.Sprintf("%s %s %s", time.Now().UTC().Format(time.RFC3339), level, msg)
+	if err := l.store.AppendLog(l.id, line); err != nil {
+		log.Errorf("Failed to append to logs of job [%s]: %v", l.id, err)
+	}
+}
@@ -0,0 +1,170 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/stretchr/testify/require"
+)
+
+func waitForStatus(t *testing.T, m *Manager, id string, status models.JobStatus) *models.Job {
+	var job *models.Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Get(id)
+		require.NoError(t, err)
+		return job.Status == status
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func TestJobLifecycle(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	m, err := NewManager(store, 1)
+	require.NoError(t, err)
+	defer m.Stop()
+
+	job, err := m.Submit(models.JobScheduleApps, models.ScheduleAppsRequest{AppList: []string{"fio"}},
+		func(ctx context.Context, logger *Logger) (interface{}, error) {
+			logger.Infof("scheduling [%d] apps", 1)
+			logger.Event(models.TriggerEvent{ID: "1", Type: "deployApps"})
+			logger.Event(models.TriggerEvent{ID: "2", Type: "rebootNode"})
+			return models.ScheduleAppsResult{Namespaces: []string{"fio-taas"}}, nil
+		})
+	require.NoError(t, err)
+	require.Equal(t, models.JobPending, job.Status)
+	require.JSONEq(t, `{"nsSuffix":"","appList":["fio"]}`, string(job.Request))
+
+	job = waitForStatus(t, m, job.ID, models.JobSucceeded)
+	require.JSONEq(t, `{"namespace":["fio-taas"]}`, string(job.Result))
+	require.NotNil(t, job.StartedAt)
+	require.NotNil(t, job.FinishedAt)
+
+	logs, err := m.Logs(job.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, logs.Lines, 2)
+	require.Contains(t, logs.Lines[0], "INFO scheduling [1] apps")
+	require.Equal(t, 3, logs.Next)
+
+	events, err := m.Events(job.ID, 1)
+	require.NoError(t, err)
+	require.Len(t, events.Events, 1)
+	require.JSONEq(t, `{"id":"2","type":"rebootNode","start":"","end":""}`, string(events.Events[0]))
+	require.Equal(t, 2, events.Next)
+
+	_, err = m.Cancel(job.ID)
+	require.ErrorIs(t, err, ErrDone)
+	_, err = m.Get("unknown")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestJobFailures(t *testing.T) {
+	m, err := NewManager(NewMemoryStore(), 2)
+	require.NoError(t, err)
+	defer m.Stop()
+
+	failed, err := m.Submit(models.JobRebootNode, nil, func(ctx context.Context, logger *Logger) (interface{}, error) {
+		return nil, errors.New("node not found")
+	})
+	require.NoError(t, err)
+	panicked, err := m.Submit(models.JobCollectSupport, nil, func(ctx context.Context, logger *Logger) (interface{}, error) {
+		panic("assertion failed")
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "node not found", waitForStatus(t, m, failed.ID, models.JobFailed).Error)
+	require.Equal(t, "job panicked: assertion failed", waitForStatus(t, m, panicked.ID, models.JobFailed).Error)
+
+	jobs, err := m.List(models.JobRebootNode, models.JobFailed)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, failed.ID, jobs[0].ID)
+}
+
+func TestCancelJob(t *testing.T) {
+	m, err := NewManager(NewMemoryStore(), 1)
+	require.NoError(t, err)
+	defer m.Stop()
+
+	started := make(chan struct{})
+	running, err := m.Submit(models.JobScheduleApps, nil, func(ctx context.Context, logger *Logger) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	require.NoError(t, err)
+	<-started
+
+	// the only worker is busy, so this job stays pending
+	ran := false
+	pending, err := m.Submit(models.JobScheduleApps, nil, func(ctx context.Context, logger *Logger) (interface{}, error) {
+		ran = true
+		return nil, nil
+	})
+	require.NoError(t, err)
+	job, err := m.Cancel(pending.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.JobCancelled, job.Status)
+
+	job, err = m.Cancel(running.ID)
+	require.NoError(t, err)
+	require.True(t, job.CancelRequested)
+	job = waitForStatus(t, m, running.ID, models.JobCancelled)
+	require.Equal(t, context.Canceled.Error(), job.Error)
+
+	// the next job runs once the cancelled job is skipped
+	next, err := m.Submit(models.JobScheduleApps, nil, func(ctx context.Context, logger *Logger) (interface{}, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+	waitForStatus(t, m, next.ID, models.JobSucceeded)
+
+	// a job completing before it notices the cancellation keeps its result
+	release := make(chan struct{})
+	done, err := m.Submit(models.JobScheduleApps, nil, func(ctx context.Context, logger *Logger) (interface{}, error) {
+		<-release
+		return "done", nil
+	})
+	require.NoError(t, err)
+	waitForStatus(t, m, done.ID, models.JobRunning)
+	_, err = m.Cancel(done.ID)
+	require.NoError(t, err)
+	close(release)
+	job = waitForStatus(t, m, done.ID, models.JobSucceeded)
+	require.Empty(t, job.Error)
+	require.False(t, ran)
+}
+
+func TestInterruptedJobs(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	for i, status := range []models.JobStatus{models.JobRunning, models.JobPending, models.JobSucceeded} {
+		require.NoError(t, store.Save(&models.Job{ID: fmt.Sprintf("job-%d", i), Status: status, CreatedAt: time.Now()}))
+	}
+
+	// a new server fails the jobs the previous one left unfinished
+	m, err := NewManager(store, 1)
+	require.NoError(t, err)
+	defer m.Stop()
+	jobs, err := m.List("", models.JobFailed)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	for _, job := range jobs {
+		require.Equal(t, errInterrupted, job.Error)
+	}
+	job, err := m.Get("job-2")
+	require.NoError(t, err)
+	require.Equal(t, models.JobSucceeded, job.Status)
+}
+
+func TestNilLogger(t *testing.T) {
+	var logger *Logger
+	logger.Infof("logged to the server log only")
+	logger.Errorf("logged to the server log only")
+}
@@ -0,0 +1,273 @@
+package jobs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/portworx/torpedo/apiServer/taas/models"
+)
+
+// Store persists the jobs, their logs and their events
+type Store interface {
+	// Save creates or updates a job
+	Save(job *models.Job) error
+	// Load returns a job, ErrNotFound if it does not exist
+	Load(id string) (*models.Job, error)
+	// List returns all jobs
+	List() ([]*models.Job, error)
+	// AppendLog appends a line to the logs of a job
+	AppendLog(id, line string) error
+	// Logs returns the log lines of a job from an offset
+	Logs(id string, offset int) ([]string, error)
+	// AppendEvent appends an event to the events of a job
+	AppendEvent(id string, event json.RawMessage) error
+	// Events returns the events of a job from an offset
+	Events(id string, offset int) ([]json.RawMessage, error)
+}
+
+// FileStore stores each job as a JSON file, along with a file of its logs and
+// a file of its events, one JSON event per line
+type FileStore struct {
+	sync.Mutex
+	dir string
+}
+
+// NewFileStore returns a store of the jobs in the directory, created if needed
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create jobs directory [%s]: %v", dir, err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) jobPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *FileStore) logPath(id string) string {
+	return filepath.Join(s.dir, id+".log")
+}
+
+func (s *FileStore) eventsPath(id string) string {
+	return filepath.Join(s.dir, id+".events")
+}
+
+// Save writes the job file, replacing it atomically
+func (s *FileStore) Save(job *models.Job) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	tmp := s.jobPath(job.ID) + ".tmp"
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to save job [%s]: %v", job.ID, err)
+	}
+	if err = os.Rename(tmp, s.jobPath(job.ID)); err != nil {
+		return fmt.Errorf("failed to save job [%s]: %v", job.ID, err)
+	}
+	return nil
+}
+
+// Load reads the job file
+func (s *FileStore) Load(id string) (*models.Job, error) {
+	if strings.ContainsAny(id, `/\`) {
+		return nil, ErrNotFound
+	}
+	s.Lock()
+	defer s.Unlock()
+	return s.load(s.jobPath(id))
+}
+
+func (s *FileStore) load(path string) (*models.Job, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	job := &models.Job{}
+	if err = json.Unmarshal(data, job); err != nil {
+		return nil, fmt.Errorf("failed to parse job file [%s]: %v", path, err)
+	}
+	return job, nil
+}
+
+// List reads all job files
+func (s *FileStore) List() ([]*models.Job, error) {
+	s.Lock()
+	defer s.Unlock()
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var jobs []*models.Job
+	for _, path := range paths {
+		job, err := s.load(path)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// AppendLog appends the line to the log file of the job
+func (s *FileStore) AppendLog(id, line string) error {
+	return s.appendLine(s.logPath(id), strings.ReplaceAll(line, "\n", " "))
+}
+
+// Logs reads the log file of the job
+func (s *FileStore) Logs(id string, offset int) ([]string, error) {
+	return s.readLines(s.logPath(id), offset)
+}
+
+// AppendEvent appends the compacted event to the events file of the job
+func (s *FileStore) AppendEvent(id string, event json.RawMessage) error {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, event); err != nil {
+		return fmt.Errorf("invalid event of job [%s]: %v", id, err)
+	}
+	return s.appendLine(s.eventsPath(id), buf.String())
+}
+
+// Events reads the events file of the job
+func (s *FileStore) Events(id string, offset int) ([]json.RawMessage, error) {
+	lines, err := s.readLines(s.eventsPath(id), offset)
+	if err != nil {
+		return nil, err
+	}
+	var events []json.RawMessage
+	for _, line := range lines {
+		events = append(events, json.RawMessage(line))
+	}
+	return events, nil
+}
+
+func (s *FileStore) appendLine(path, line string) error {
+	s.Lock()
+	defer s.Unlock()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(line + "\n")
+	return err
+}
+
+func (s *FileStore) readLines(path string, offset int) ([]string, error) {
+	s.Lock()
+	defer s.Unlock()
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for i := 0; scanner.Scan(); i++ {
+		if i >= offset {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// MemoryStore keeps the jobs in memory, they are lost when the server restarts
+type MemoryStore struct {
+	sync.Mutex
+	jobs   map[string]models.Job
+	logs   map[string][]string
+	events map[string][]json.RawMessage
+}
+
+// NewMemoryStore returns an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		jobs:   make(map[string]models.Job),
+		logs:   make(map[string][]string),
+		events: make(map[string][]json.RawMessage),
+	}
+}
+
+// Save stores a copy of the job
+func (s *MemoryStore) Save(job *models.Job) error {
+	s.Lock()
+	defer s.Unlock()
+	s.jobs[job.ID] = *job
+	return nil
+}
+
+// Load returns a copy of the job
+func (s *MemoryStore) Load(id string) (*models.Job, error) {
+	s.Lock()
+	defer s.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &job, nil
+}
+
+// List returns copies of all jobs
+func (s *MemoryStore) List() ([]*models.Job, error) {
+	s.Lock()
+	defer s.Unlock()
+	var jobs []*models.Job
+	for _, job := range s.jobs {
+		job := job
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// AppendLog appends the line to the logs of the job
+func (s *MemoryStore) AppendLog(id, line string) error {
+	s.Lock()
+	defer s.Unlock()
+	s.logs[id] = append(s.logs[id], line)
+	return nil
+}
+
+// Logs returns the log lines of the job from an offset
+func (s *MemoryStore) Logs(id string, offset int) ([]string, error) {
+	s.Lock()
+	defer s.Unlock()
+	logs := s.logs[id]
+	if offset >= len(logs) {
+		return nil, nil
+	}
+	return append([]string(nil), logs[offset:]...), nil
+}
+
+// AppendEvent appends a copy of the event to the events of the job
+func (s *MemoryStore) AppendEvent(id string, event json.RawMessage) error {
+	s.Lock()
+	defer s.Unlock()
+	s.events[id] = append(s.events[id], append(json.RawMessage(nil), event...))
+	return nil
+}
+
+// Events returns the events of the job from an offset
+func (s *MemoryStore) Events(id string, offset int) ([]json.RawMessage, error) {
+	s.Lock()
+	defer s.Unlock()
+	events := s.events[id]
+	if offset >= len(events) {
+		return nil, nil
+	}
+	return append([]json.RawMessage(nil), events[offset:]...), nil
+}
@@ -0,0 +1,245 @@
+// Package models holds the request and response types of the TaaS API server.
+// They are shared by the server handlers, the OpenAPI spec and the Go client.
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobStatus is the status of an asynchronous job
+type JobStatus string
+
+const (
+	// JobPending is the status of a job waiting for a worker
+	JobPending JobStatus = "pending"
+	// JobRunning is the status of a running job
+	JobRunning JobStatus = "running"
+	// JobSucceeded is the status of a job which completed without error
+	JobSucceeded JobStatus = "succeeded"
+	// JobFailed is the status of a job which completed with an error
+	JobFailed JobStatus = "failed"
+	// JobCancelled is the status of a job cancelled before it completed
+	JobCancelled JobStatus = "cancelled"
+)
+
+// Done returns true if the job will not change status anymore
+func (s JobStatus) Done() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCancelled
+}
+
+// Types of the asynchronous jobs
+const (
+	JobInitTorpedo    = "inittorpedo"
+	JobRebootNode     = "rebootnode"
+	JobCollectSupport = "collectsupport"
+	JobScheduleApps   = "scheduleapps"
+	JobUpgradeStork   = "upgradestork"
+	JobRunHelmCmd     = "runhelmcmd"
+	JobRunTriggers    = "runtriggers"
+)
+
+// Job is a long-running operation submitted to the API server
+type Job struct {
+	// ID identifies the job
+	ID string `json:"id" binding:"required"`
+	// Type is the operation run by the job, for e.g. scheduleapps
+	Type string `json:"type" binding:"required"`
+	// Status is the status of the job
+	Status JobStatus `json:"status" binding:"required"`
+	// Request is the request body the job was submitted with
+	Request json.RawMessage `json:"request,omitempty"`
+	// Result is the result of the job once succeeded, its type depends on the job type
+	Result json.RawMessage `json:"result,omitempty"`
+	// Error is the error of the job once failed or cancelled
+	Error string `json:"error,omitempty"`
+	// CancelRequested is true if the job was asked to stop while running
+	CancelRequested bool `json:"cancelRequested,omitempty"`
+	// CreatedAt is the time the job was submitted
+	CreatedAt time.Time `json:"createdAt" binding:"required"`
+	// StartedAt is the time the job started running
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	// FinishedAt is the time the job completed
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// JobList is a list of jobs, most recent first
+type JobList struct {
+	Jobs []Job `json:"jobs" binding:"required"`
+}
+
+// JobLogs are log lines of a job
+type JobLogs struct {
+	// ID is the ID of the job
+	ID string `json:"id" binding:"required"`
+	// Lines are the log lines from the requested offset
+	Lines []string `json:"lines" binding:"required"`
+	// Next is the offset to request the following lines from
+	Next int `json:"next" binding:"required"`
+}
+
+// JobEvents are events of a job, for e.g. the TriggerEvents of a runtriggers job
+type JobEvents struct {
+	// ID is the ID of the job
+	ID string `json:"id" binding:"required"`
+	// Events are the events from the requested offset
+	Events []json.RawMessage `json:"events" binding:"required"`
+	// Next is the offset to request the following events from
+	Next int `json:"next" binding:"required"`
+}
+
+// ErrorResponse is the body of the responses of failed requests
+type ErrorResponse struct {
+	Error string `json:"error" binding:"required"`
+}
+
+// MessageResponse is the body of the responses of requests without result
+type MessageResponse struct {
+	Message string `json:"message" binding:"required"`
+}
+
+// ScheduleAppsRequest is the request to schedule and validate applications
+type ScheduleAppsRequest struct {
+	// NamespaceSuffix is appended to the namespaces of the applications
+	NamespaceSuffix string `json:"nsSuffix"`
+	// AppList are the applications to schedule, for e.g. mysql or fio
+	AppList []string `json:"appList" binding:"required"`
+}
+
+// ScheduleAppsResult is the result of scheduling applications
+type ScheduleAppsResult struct {
+	// Namespaces are the namespaces of the scheduled applications
+	Namespaces []string `json:"namespace"`
+}
+
+// RebootNodeResult is the result of rebooting nodes
+type RebootNodeResult struct {
+	// Nodes are the names of the rebooted nodes
+	Nodes []string `json:"nodes"`
+}
+
+// CommandResult is the result of running a command
+type CommandResult struct {
+	// Output is the combined stdout and stderr of the command
+	Output string `json:"output"`
+}
+
+// HelmPayload is the request to run a helm command
+type HelmPayload struct {
+	Command string `json:"command" binding:"required"`
+}
+
+// NamespacesRequest selects namespaces by name
+type NamespacesRequest struct {
+	Namespaces []string `json:"namespaces" binding:"required"`
+}
+
+// NamespaceLabelsRequest selects namespaces by labels
+type NamespaceLabelsRequest struct {
+	NamespaceLabels map[string]string `json:"namespaceLabels" binding:"required"`
+}
+
+// NamespaceLabelRequest is the request to add a label to namespaces
+type NamespaceLabelRequest struct {
+	Namespaces []string          `json:"namespaces" binding:"required"`
+	Label      map[string]string `json:"ns_label" binding:"required"`
+}
+
+// UpgradeStorkRequest is the request to upgrade stork
+type UpgradeStorkRequest struct {
+	Version string `json:"version" binding:"required"`
+}
+
+// DeletePodRequest is the request to delete pods by label or by name
+type DeletePodRequest struct {
+	Namespace   string            `json:"namespace" binding:"required"`
+	Label       map[string]string `json:"label"`
+	PodList     []string          `json:"podList"`
+	IgnoreLabel bool              `json:"ignoreLabel"`
+}
+
+// CreateVolumeSnapshotClassRequest is the request to create a volume snapshot class
+type CreateVolumeSnapshotClassRequest struct {
+	VolumeSnapshotClassName      string `json:"volumeSnapshotClassName" binding:"required"`
+	Provisioner                  string `json:"provisioner" binding:"required"`
+	IsDefaultVolumeSnapshotClass bool   `json:"isDefaultVolumeSnapshotClass"`
+	DeletePolicy                 string `json:"deletePolicy"`
+}
+
+// VM is a KubeVirt virtual machine
+type VM struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Status    string `json:"status"`
+}
+
+// TriggerList lists the test triggers which can be run
+type TriggerList struct {
+	Triggers []string `json:"triggers" binding:"required"`
+}
+
+// RunTriggersRequest is the request to run test triggers against the scheduled applications
+type RunTriggersRequest struct {
+	// Triggers are run in order, for e.g. rebootNode or crashVolDriver
+	Triggers []string `json:"triggers" binding:"required"`
+	// ChaosLevel is the chaos level of the triggers, from 1 to 10, defaults to 5
+	ChaosLevel int `json:"chaosLevel"`
+	// Iterations is the number of times the triggers are run, defaults to 1
+	Iterations int `json:"iterations"`
+}
+
+// RunTriggersResult is the result of running test triggers
+type RunTriggersResult struct {
+	// Events is the number of events the triggers recorded
+	Events int `json:"events"`
+	// FailedEvents is the number of events with errors
+	FailedEvents int `json:"failedEvents"`
+}
+
+// TriggerEvent is an event recorded by a test trigger
+type TriggerEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type" binding:"required"`
+	// Start and End are the RFC1123 times of the event
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Errors are the errors the trigger hit
+	Errors  []string `json:"errors,omitempty"`
+	Nodes   []string `json:"nodes,omitempty"`
+	Volumes []string `json:"volumes,omitempty"`
+}
+
+// SessionHeader is the header selecting the session of a request, the
+// DefaultSession if not set
+const SessionHeader = "X-TaaS-Session"
+
+// DefaultSession is the ID of the session of the cluster the API server was started for
+const DefaultSession = "default"
+
+// Session targets a cluster, and optionally the destination cluster of DR
+// tests, and keeps the applications scheduled on them
+type Session struct {
+	// ID identifies the session in the SessionHeader of the requests
+	ID string `json:"id" binding:"required"`
+	// Name describes the session
+	Name string `json:"name" binding:"required"`
+	// HasDestination is true if the session has a destination cluster
+	HasDestination bool `json:"hasDestination"`
+	// CreatedAt is the time the session was created
+	CreatedAt time.Time `json:"createdAt" binding:"required"`
+}
+
+// SessionList is a list of sessions
+type SessionList struct {
+	Sessions []Session `json:"sessions" binding:"required"`
+}
+
+// CreateSessionRequest is the request to create a session
+type CreateSessionRequest struct {
+	// Name describes the session
+	Name string `json:"name" binding:"required"`
+	// Kubeconfig is the kubeconfig of the cluster of the session
+	Kubeconfig string `json:"kubeconfig" binding:"required"`
+	// DestinationKubeconfig is the kubeconfig of the destination cluster of DR tests
+	DestinationKubeconfig string `json:"destinationKubeconfig"`
+}
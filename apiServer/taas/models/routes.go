@@ -0,0 +1,153 @@
+package models
+
+import "net/http"
+
+// Route describes a route of the API server. The server registers its handlers
+// from Routes and the OpenAPI spec is generated from it, so they can't diverge.
+type Route struct {
+	// Method is the HTTP method of the route
+	Method string
+	// Path is the gin path of the route, for e.g. taas/rebootnode/:nodename
+	Path string
+	// OperationID identifies the route in the spec and maps it to its handler
+	OperationID string
+	// Summary describes the route
+	Summary string
+	// Request is a value of the type of the request body, nil if the route takes none
+	Request interface{}
+	// Response is a value of the type of the response body, nil if it is free-form
+	Response interface{}
+	// Query are the names of the query parameters of the route
+	Query []string
+	// Async routes respond with 202 and the submitted Job
+	Async bool
+	// Role is the role required to call the route
+	Role Role
+	// Session routes act on the cluster of the session selected by the SessionHeader
+	Session bool
+}
+
+// Role is a role of the callers of the API server. Roles are ordered, each
+// role granting the routes of the roles before it.
+type Role string
+
+const (
+	// RolePublic routes need no authentication
+	RolePublic Role = "public"
+	// RoleReadOnly routes read the state of the cluster
+	RoleReadOnly Role = "read-only"
+	// RoleAppOps routes deploy and manage applications
+	RoleAppOps Role = "app-ops"
+	// RoleDestructive routes disrupt the cluster, for e.g. by rebooting nodes or running commands
+	RoleDestructive Role = "destructive"
+)
+
+// Roles are the roles, in order
+var Roles = []Role{RolePublic, RoleReadOnly, RoleAppOps, RoleDestructive}
+
+// Allows returns true if the role grants the routes requiring the given role.
+// Unknown roles grant nothing.
+func (r Role) Allows(required Role) bool {
+	rank, requiredRank := roleRank(r), roleRank(required)
+	return rank >= 0 && requiredRank >= 0 && rank >= requiredRank
+}
+
+func roleRank(r Role) int {
+	for i, role := range Roles {
+		if role == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Routes are the routes of the API server
+var Routes = []Route{
+	// Jobs
+	{Method: http.MethodGet, Path: "taas/jobs", OperationID: "listJobs", Role: RoleReadOnly,
+		Summary: "Lists the jobs, most recent first", Response: JobList{}, Query: []string{"type", "status"}},
+	{Method: http.MethodGet, Path: "taas/jobs/:id", OperationID: "getJob", Role: RoleReadOnly,
+		Summary: "Returns a job", Response: Job{}},
+	{Method: http.MethodGet, Path: "taas/jobs/:id/logs", OperationID: "getJobLogs", Role: RoleReadOnly,
+		Summary: "Returns the log lines of a job from an offset", Response: JobLogs{}, Query: []string{"offset"}},
+	{Method: http.MethodGet, Path: "taas/jobs/:id/events", OperationID: "getJobEvents", Role: RoleReadOnly,
+		Summary: "Returns the events of a job from an offset, streamed as server-sent events with follow=true", Response: JobEvents{}, Query: []string{"offset", "follow"}},
+	{Method: http.MethodPost, Path: "taas/jobs/:id/cancel", OperationID: "cancelJob", Role: RoleDestructive,
+		Summary: "Cancels a pending or running job", Response: Job{}},
+	{Method: http.MethodPost, Path: "taas/jobs/inittorpedo", OperationID: "submitInitTorpedo", Role: RoleAppOps, Session: true,
+		Summary: "Submits a job initializing the torpedo drivers", Async: true},
+	{Method: http.MethodPost, Path: "taas/jobs/rebootnode/:nodename", OperationID: "submitRebootNode", Role: RoleDestructive, Session: true,
+		Summary: "Submits a job rebooting all nodes, a random node or the named node", Async: true},
+	{Method: http.MethodPost, Path: "taas/jobs/collectsupport", OperationID: "submitCollectSupport", Role: RoleAppOps, Session: true,
+		Summary: "Submits a job collecting the support bundle", Async: true},
+	{Method: http.MethodPost, Path: "taas/jobs/scheduleapps", OperationID: "submitScheduleApps", Role: RoleAppOps, Session: true,
+		Summary: "Submits a job scheduling and validating applications", Request: ScheduleAppsRequest{}, Async: true},
+	{Method: http.MethodPost, Path: "taas/jobs/stork/upgrade", OperationID: "submitUpgradeStork", Role: RoleDestructive, Session: true,
+		Summary: "Submits a job upgrading stork", Request: UpgradeStorkRequest{}, Async: true},
+	{Method: http.MethodPost, Path: "taas/jobs/runhelmcmd", OperationID: "submitRunHelmCmd", Role: RoleDestructive, Session: true,
+		Summary: "Submits a job running a helm command", Request: HelmPayload{}, Async: true},
+	{Method: http.MethodPost, Path: "taas/jobs/triggers", OperationID: "submitRunTriggers", Role: RoleDestructive, Session: true,
+		Summary: "Submits a job running test triggers against the scheduled applications", Request: RunTriggersRequest{}, Async: true},
+
+	// Sessions
+	{Method: http.MethodGet, Path: "taas/sessions", OperationID: "listSessions", Role: RoleReadOnly,
+		Summary: "Lists the sessions", Response: SessionList{}},
+	{Method: http.MethodPost, Path: "taas/sessions", OperationID: "createSession", Role: RoleAppOps,
+		Summary: "Creates a session from the kubeconfigs of its clusters", Request: CreateSessionRequest{}, Response: Session{}},
+	{Method: http.MethodGet, Path: "taas/sessions/:id", OperationID: "getSession", Role: RoleReadOnly,
+		Summary: "Returns a session", Response: Session{}},
+	{Method: http.MethodDelete, Path: "taas/sessions/:id", OperationID: "deleteSession", Role: RoleDestructive,
+		Summary: "Deletes a session, its applications are left running", Response: MessageResponse{}},
+
+	// Synchronous routes
+	{Method: http.MethodGet, Path: "taas/openapi.json", OperationID: "getOpenAPISpec", Role: RolePublic,
+		Summary: "Returns the OpenAPI spec of the API server"},
+	{Method: http.MethodGet, Path: "taas/triggers", OperationID: "listTriggers", Role: RoleReadOnly,
+		Summary: "Lists the test triggers which can be run", Response: TriggerList{}},
+	{Method: http.MethodDelete, Path: "taas/deletens/:namespace", OperationID: "deleteNamespace", Role: RoleDestructive, Session: true,
+		Summary: "Deletes a namespace", Response: MessageResponse{}},
+	{Method: http.MethodPost, Path: "taas/createns", OperationID: "createNamespace", Role: RoleAppOps, Session: true,
+		Summary: "Creates a namespace with a random name"},
+	{Method: http.MethodPost, Path: "taas/inittorpedo", OperationID: "initTorpedo", Role: RoleAppOps,
+		Summary: "Initializes the torpedo drivers"},
+	{Method: http.MethodGet, Path: "taas/getnodes", OperationID: "getNodes", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the worker nodes"},
+	{Method: http.MethodPost, Path: "taas/rebootnode/:nodename", OperationID: "rebootNode", Role: RoleDestructive, Session: true,
+		Summary: "Reboots all nodes, a random node or the named node"},
+	{Method: http.MethodGet, Path: "taas/storagenodes", OperationID: "getStorageNodes", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the storage nodes"},
+	{Method: http.MethodGet, Path: "taas/storagelessnodes", OperationID: "getStorageLessNodes", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the storageless nodes"},
+	{Method: http.MethodPost, Path: "taas/collectsupport", OperationID: "collectSupport", Role: RoleAppOps, Session: true,
+		Summary: "Collects the support bundle", Response: MessageResponse{}},
+	{Method: http.MethodPost, Path: "taas/scheduleapps", OperationID: "scheduleApps", Role: RoleAppOps, Session: true,
+		Summary: "Schedules and validates applications", Request: ScheduleAppsRequest{}},
+	{Method: http.MethodPost, Path: "taas/deploypxagent", OperationID: "deployPxAgent", Role: RoleDestructive, Session: true,
+		Summary: "Runs the helm command deploying the px agent", Request: HelmPayload{}, Response: MessageResponse{}},
+	{Method: http.MethodGet, Path: "taas/getclusterid/:namespace", OperationID: "getClusterID", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the UID of a namespace"},
+	{Method: http.MethodGet, Path: "taas/getclusternodestatus", OperationID: "getClusterNodeStatus", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the count of nodes by readiness"},
+	{Method: http.MethodPost, Path: "taas/runhelmcmd", OperationID: "runHelmCmd", Role: RoleDestructive, Session: true,
+		Summary: "Runs a helm command", Request: HelmPayload{}, Response: MessageResponse{}},
+	{Method: http.MethodGet, Path: "taas/pxversion", OperationID: "getPxVersion", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the version of Portworx"},
+	{Method: http.MethodGet, Path: "taas/ispxinstalled", OperationID: "isPxInstalled", Role: RoleReadOnly, Session: true,
+		Summary: "Checks that Portworx is installed on all nodes"},
+	{Method: http.MethodGet, Path: "taas/getpxctloutput", OperationID: "getPxctlStatus", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the pxctl status of a node"},
+	{Method: http.MethodGet, Path: "taas/getkubevirtvmsbyns", OperationID: "getVMsByNamespaces", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the virtual machines of namespaces", Request: NamespacesRequest{}, Response: []VM{}},
+	{Method: http.MethodGet, Path: "taas/getkubevirtvmsbynslabels", OperationID: "getVMsByNamespaceLabels", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the virtual machines of the namespaces with labels", Request: NamespaceLabelsRequest{}, Response: []VM{}},
+	{Method: http.MethodPost, Path: "taas/namespaces/addLabel", OperationID: "addNamespaceLabel", Role: RoleAppOps, Session: true,
+		Summary: "Adds a label to namespaces", Request: NamespaceLabelRequest{}},
+	{Method: http.MethodPost, Path: "taas/stork/upgrade", OperationID: "upgradeStork", Role: RoleDestructive, Session: true,
+		Summary: "Upgrades stork", Request: UpgradeStorkRequest{}},
+	{Method: http.MethodDelete, Path: "taas/deletepod", OperationID: "deletePod", Role: RoleDestructive, Session: true,
+		Summary: "Deletes pods by label or by name", Request: DeletePodRequest{}, Response: MessageResponse{}},
+	{Method: http.MethodGet, Path: "taas/getpxbackupnamespace", OperationID: "getPxBackupNamespace", Role: RoleReadOnly, Session: true,
+		Summary: "Returns the namespace of px-backup"},
+	{Method: http.MethodPost, Path: "taas/createvolumesnapshotclass", OperationID: "createVolumeSnapshotClass", Role: RoleAppOps, Session: true,
+		Summary: "Creates a volume snapshot class", Request: CreateVolumeSnapshotClassRequest{}, Response: MessageResponse{}},
+}
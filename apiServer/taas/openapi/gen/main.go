@@ -0,0 +1,23 @@
+// Command gen writes the OpenAPI spec of the TaaS API server to a file
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/portworx/torpedo/apiServer/taas/openapi"
+)
+
+func main() {
+	out := flag.String("o", "openapi.json", "path of the generated spec")
+	flag.Parse()
+	data, err := openapi.Generate(models.Routes).JSON()
+	if err != nil {
+		log.Fatalf("failed to generate spec: %v", err)
+	}
+	if err = os.WriteFile(*out, data, 0644); err != nil {
+		log.Fatalf("failed to write spec: %v", err)
+	}
+}
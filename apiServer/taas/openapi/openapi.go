@@ -0,0 +1,278 @@
+// Package openapi generates the OpenAPI 3 spec of the TaaS API server from its
+// route table. Schemas are derived from the request and response models: JSON
+// field names come from the json tags and required fields from the
+// binding:"required" tags.
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/portworx/torpedo/apiServer/taas/models"
+)
+
+//go:generate go run ./gen -o ../openapi.json
+
+const (
+	// Title is the title of the spec
+	Title = "Torpedo as a Service"
+	// Version is the version of the API
+	Version = "1.0.0"
+
+	schemaRefPrefix = "#/components/schemas/"
+	// bearerAuth is the name of the security scheme of the bearer tokens
+	bearerAuth = "bearerAuth"
+)
+
+// Schema is an OpenAPI schema object
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+}
+
+// Spec is an OpenAPI document
+type Spec struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]Operation `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+// Info is the info object of the spec
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Components holds the named schemas and the security schemes of the spec
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is a security scheme of the spec
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// Operation is an operation of a path
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	// Role is the role required to call the operation
+	Role models.Role `json:"x-taas-role"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is the body of a request
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a content type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// enums are the allowed values of the string types of the models
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(models.JobStatus("")): {
+		string(models.JobPending), string(models.JobRunning), string(models.JobSucceeded),
+		string(models.JobFailed), string(models.JobCancelled),
+	},
+}
+
+// Generate returns the spec of the routes
+func Generate(routes []models.Route) *Spec {
+	g := &generator{schemas: make(map[string]*Schema)}
+	spec := &Spec{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: Title, Version: Version},
+		Paths:   make(map[string]map[string]Operation),
+	}
+	errorResponse := Response{
+		Description: "error",
+		Content:     jsonContent(g.schema(reflect.TypeOf(models.ErrorResponse{}))),
+	}
+	for _, r := range routes {
+		path, params := convertPath(r.Path)
+		op := Operation{
+			OperationID: r.OperationID,
+			Summary:     r.Summary,
+			Parameters:  params,
+			Responses:   map[string]Response{"default": errorResponse},
+			Role:        r.Role,
+		}
+		if r.Role != models.RolePublic {
+			op.Security = []map[string][]string{{bearerAuth: []string{}}}
+		}
+		if r.Session {
+			op.Parameters = append(op.Parameters, Parameter{Name: models.SessionHeader, In: "header", Schema: &Schema{Type: "string"}})
+		}
+		for _, q := range r.Query {
+			op.Parameters = append(op.Parameters, Parameter{Name: q, In: "query", Schema: &Schema{Type: "string"}})
+		}
+		if r.Request != nil {
+			op.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(g.schema(reflect.TypeOf(r.Request))),
+			}
+		}
+		switch {
+		case r.Async:
+			op.Responses["202"] = Response{
+				Description: "job submitted",
+				Content:     jsonContent(g.schema(reflect.TypeOf(models.Job{}))),
+			}
+		case r.Response != nil:
+			op.Responses["200"] = Response{Description: "success", Content: jsonContent(g.schema(reflect.TypeOf(r.Response)))}
+		default:
+			op.Responses["200"] = Response{Description: "success", Content: jsonContent(&Schema{Type: "object"})}
+		}
+		if spec.Paths[path] == nil {
+			spec.Paths[path] = make(map[string]Operation)
+		}
+		spec.Paths[path][strings.ToLower(r.Method)] = op
+	}
+	spec.Components.Schemas = g.schemas
+	spec.Components.SecuritySchemes = map[string]SecurityScheme{bearerAuth: {Type: "http", Scheme: "bearer"}}
+	return spec
+}
+
+// JSON returns the indented JSON encoding of the spec. Maps are encoded with
+// sorted keys, so the output is stable.
+func (s *Spec) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// convertPath converts a gin path to an OpenAPI path and its path parameters
+func convertPath(ginPath string) (string, []Parameter) {
+	var params []Parameter
+	segments := strings.Split(strings.TrimPrefix(ginPath, "/"), "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			name := s[1:]
+			segments[i] = "{" + name + "}"
+			params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return "/" + strings.Join(segments, "/"), params
+}
+
+type generator struct {
+	schemas map[string]*Schema
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schema returns the schema of a type, a reference for named struct types
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		// any JSON value
+		return &Schema{}
+	}
+	if values, ok := enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		if _, ok := g.schemas[t.Name()]; !ok {
+			// reserve the name before recursing, for recursive types
+			g.schemas[t.Name()] = nil
+			g.schemas[t.Name()] = g.structSchema(t)
+		}
+		return &Schema{Ref: schemaRefPrefix + t.Name()}
+	}
+	// interface{} and other kinds accept any value
+	return &Schema{}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		s.Properties[name] = g.schema(f.Type)
+		for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+			if rule == "required" {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSpecUpToDate fails when the committed spec differs from the routes, run go generate to update it
+func TestSpecUpToDate(t *testing.T) {
+	generated, err := Generate(models.Routes).JSON()
+	require.NoError(t, err)
+	committed, err := os.ReadFile("../openapi.json")
+	require.NoError(t, err)
+	require.Equal(t, string(committed), string(generated), "openapi.json is out of date, run go generate ./apiServer/taas/openapi")
+}
+
+func TestGenerate(t *testing.T) {
+	spec := Generate(models.Routes)
+
+	ids := make(map[string]bool)
+	for _, r := range models.Routes {
+		require.False(t, ids[r.OperationID], "duplicate operation [%s]", r.OperationID)
+		ids[r.OperationID] = true
+	}
+
+	reboot := spec.Paths["/taas/jobs/rebootnode/{nodename}"]["post"]
+	require.Equal(t, "submitRebootNode", reboot.OperationID)
+	require.Equal(t, []Parameter{
+		{Name: "nodename", In: "path", Required: true, Schema: &Schema{Type: "string"}},
+		{Name: models.SessionHeader, In: "header", Schema: &Schema{Type: "string"}},
+	}, reboot.Parameters)
+	require.Equal(t, schemaRefPrefix+"Job", reboot.Responses["202"].Content["application/json"].Schema.Ref)
+	require.Equal(t, models.RoleDestructive, reboot.Role)
+	require.Equal(t, []map[string][]string{{bearerAuth: {}}}, reboot.Security)
+	require.Empty(t, spec.Paths["/taas/openapi.json"]["get"].Security)
+
+	logs := spec.Paths["/taas/jobs/{id}/logs"]["get"]
+	require.Len(t, logs.Parameters, 2)
+	require.Equal(t, "offset", logs.Parameters[1].Name)
+	require.Equal(t, "query", logs.Parameters[1].In)
+
+	job := spec.Components.Schemas["Job"]
+	require.Equal(t, []string{"createdAt", "id", "status", "type"}, job.Required)
+	require.Equal(t, &Schema{Type: "string", Format: "date-time"}, job.Properties["startedAt"])
+	require.Equal(t, []string{"pending", "running", "succeeded", "failed", "cancelled"}, job.Properties["status"].Enum)
+	require.Equal(t, &Schema{}, job.Properties["result"])
+
+	label := spec.Components.Schemas["NamespaceLabelRequest"]
+	require.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, label.Properties["ns_label"])
+
+	vms := spec.Paths["/taas/getkubevirtvmsbyns"]["get"].Responses["200"].Content["application/json"].Schema
+	require.Equal(t, "array", vms.Type)
+	require.Equal(t, schemaRefPrefix+"VM", vms.Items.Ref)
+}
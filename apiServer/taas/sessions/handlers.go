@@ -0,0 +1,113 @@
+package sessions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+)
+
+// sessionKey is the key of the session of the request in the gin context
+const sessionKey = "taas.session"
+
+// Handler returns the middleware of a route, to be registered before its
+// handler. It selects the session of the SessionHeader for the routes acting on
+// a cluster. Synchronous routes run with the drivers switched to the session,
+// and are rejected with a conflict while a job of another session runs.
+// Asynchronous routes only record it, their jobs switching to it when they run.
+func (m *Manager) Handler(route models.Route) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !route.Session {
+			c.Next()
+			return
+		}
+		id := c.GetHeader(models.SessionHeader)
+		if id == "" {
+			id = models.DefaultSession
+		}
+		s, err := m.Get(id)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.Set(sessionKey, s)
+		if route.Async {
+			c.Next()
+			return
+		}
+		release, err := m.Activate(s)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrBusy) {
+				status = http.StatusConflict
+			}
+			c.AbortWithStatusJSON(status, models.ErrorResponse{Error: err.Error()})
+			return
+		}
+		defer release()
+		c.Next()
+	}
+}
+
+// SessionFrom returns the session selected for the request, nil for the routes not acting on a cluster
+func SessionFrom(c *gin.Context) *Session {
+	if v, ok := c.Get(sessionKey); ok {
+		return v.(*Session)
+	}
+	return nil
+}
+
+// ListSessions : Lists the sessions
+func (m *Manager) ListSessions(c *gin.Context) {
+	c.JSON(http.StatusOK, models.SessionList{Sessions: m.List()})
+}
+
+// GetSession : Returns the session with the id path parameter
+func (m *Manager) GetSession(c *gin.Context) {
+	s, err := m.Get(c.Param("id"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, s.Session)
+}
+
+// CreateSession : Creates a session from the kubeconfigs of its clusters
+func (m *Manager) CreateSession(c *gin.Context) {
+	var req models.CreateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	s, err := m.Create(req)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.Header("Location", "/taas/sessions/"+s.ID)
+	c.JSON(http.StatusOK, s.Session)
+}
+
+// DeleteSession : Deletes the session with the id path parameter, its applications are left running
+func (m *Manager) DeleteSession(c *gin.Context) {
+	id := c.Param("id")
+	if err := m.Delete(id); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.MessageResponse{Message: "Session " + id + " deleted"})
+}
+
+func abortWithError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrDefault):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrInUse):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	}
+}
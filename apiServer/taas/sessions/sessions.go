@@ -0,0 +1,283 @@
+// Package sessions manages the sessions of the TaaS API server. A session
+// targets a cluster, and optionally the destination cluster of DR tests, and
+// keeps the applications scheduled on them.
+//
+// Each session has its own torpedo drivers, created for its clusters the first
+// time it is used. The drivers of all sessions share the kubernetes clients of
+// the process, which the manager points at the clusters of a session for the
+// time of a request or of a job. Requests and jobs of the session the clients
+// point at run together. A job of another session waits for them to end, while
+// a request of another session is rejected with ErrBusy rather than waiting
+// behind a running job.
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+var (
+	// ErrNotFound is returned for unknown session IDs
+	ErrNotFound = errors.New("session not found")
+	// ErrDefault is returned when deleting the default session
+	ErrDefault = errors.New("the default session can't be deleted")
+	// ErrBusy is returned when the clients are held by a job of another session
+	ErrBusy = errors.New("the clients are in use by a job of another session")
+	// ErrInUse is returned when deleting a session used by a request or a job
+	ErrInUse = errors.New("the session is in use")
+)
+
+// Session is a session and its state
+type Session struct {
+	models.Session
+	// Kubeconfigs are the file names of the kubeconfigs of the session in the
+	// kubeconfig directory, the source cluster first. They are empty for the
+	// default session, which uses the kubeconfig of the server.
+	Kubeconfigs []string `json:"kubeconfigs,omitempty"`
+	// Contexts are the applications scheduled in the session. They are lost
+	// when the server restarts.
+	Contexts []*scheduler.Context `json:"-"`
+
+	// drivers are created the first time the session is used
+	drivers Drivers
+}
+
+// IsDefault returns true for the default session
+func (s *Session) IsDefault() bool {
+	return s.ID == models.DefaultSession
+}
+
+// Drivers are the torpedo drivers of a session
+type Drivers interface {
+	// Use points the kubernetes clients shared by the drivers of all sessions
+	// at the clusters of the session
+	Use() error
+}
+
+// NewDriversFunc returns the drivers of a session, given the paths of its kubeconfigs
+type NewDriversFunc func(s *Session, kubeconfigPaths []string) Drivers
+
+// Manager creates, persists and activates the sessions
+type Manager struct {
+	sync.Mutex
+	dir           string
+	kubeconfigDir string
+	newDrivers    NewDriversFunc
+	sessions      map[string]*Session
+
+	// clientsLock guards the use of the shared clients, clientsFree being
+	// signaled when a user of the clients releases them
+	clientsLock sync.Mutex
+	clientsFree *sync.Cond
+	inUse       Drivers
+	// users are the requests and jobs using the clients, jobs the jobs among them
+	users, jobs int
+}
+
+// NewManager returns a manager persisting the sessions in dir and writing their
+// kubeconfigs to kubeconfigDir. Sessions of a previous server are reloaded
+// without their applications.
+func NewManager(dir, kubeconfigDir string, newDrivers NewDriversFunc) (*Manager, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create sessions directory [%s]: %v", dir, err)
+	}
+	m := &Manager{
+		dir:           dir,
+		kubeconfigDir: kubeconfigDir,
+		newDrivers:    newDrivers,
+		sessions:      make(map[string]*Session),
+	}
+	m.clientsFree = sync.NewCond(&m.clientsLock)
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		s := &Session{}
+		if err = json.Unmarshal(data, s); err != nil {
+			return nil, fmt.Errorf("failed to parse session file [%s]: %v", path, err)
+		}
+		m.sessions[s.ID] = s
+	}
+	m.sessions[models.DefaultSession] = &Session{
+		Session: models.Session{ID: models.DefaultSession, Name: "cluster of the API server", CreatedAt: time.Now().UTC()},
+	}
+	return m, nil
+}
+
+// Create creates a session, writing its kubeconfigs
+func (m *Manager) Create(req models.CreateSessionRequest) (*Session, error) {
+	s := &Session{
+		Session: models.Session{
+			ID:             uuid.New(),
+			Name:           req.Name,
+			HasDestination: req.DestinationKubeconfig != "",
+			CreatedAt:      time.Now().UTC(),
+		},
+	}
+	kubeconfigs := []string{req.Kubeconfig}
+	if s.HasDestination {
+		kubeconfigs = append(kubeconfigs, req.DestinationKubeconfig)
+	}
+	for i, kubeconfig := range kubeconfigs {
+		name := fmt.Sprintf("taas-%s-%d", s.ID, i)
+		if err := os.WriteFile(filepath.Join(m.kubeconfigDir, name), []byte(kubeconfig), 0600); err != nil {
+			m.removeKubeconfigs(s)
+			return nil, fmt.Errorf("failed to write kubeconfig of session [%s]: %v", s.Name, err)
+		}
+		s.Kubeconfigs = append(s.Kubeconfigs, name)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err == nil {
+		err = os.WriteFile(m.sessionPath(s.ID), data, 0600)
+	}
+	if err != nil {
+		m.removeKubeconfigs(s)
+		return nil, fmt.Errorf("failed to save session [%s]: %v", s.Name, err)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.sessions[s.ID] = s
+	log.Infof("Created session [%s] named [%s]", s.ID, s.Name)
+	return s, nil
+}
+
+// Get returns a session
+func (m *Manager) Get(id string) (*Session, error) {
+	m.Lock()
+	defer m.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s, nil
+}
+
+// List returns the sessions, the default session first and the others by creation time
+func (m *Manager) List() []models.Session {
+	m.Lock()
+	defer m.Unlock()
+	list := make([]models.Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		list = append(list, s.Session)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ID == models.DefaultSession || list[j].ID == models.DefaultSession {
+			return list[i].ID == models.DefaultSession
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Delete deletes a session, its drivers and its kubeconfigs. Its applications
+// are left running. It waits for the requests and jobs using the shared clients.
+func (m *Manager) Delete(id string) error {
+	if id == models.DefaultSession {
+		return ErrDefault
+	}
+	m.clientsLock.Lock()
+	defer m.clientsLock.Unlock()
+	m.Lock()
+	defer m.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if s.drivers != nil && m.inUse == s.drivers && m.users > 0 {
+		return fmt.Errorf("failed to delete session [%s]: %w", id, ErrInUse)
+	}
+	if err := os.Remove(m.sessionPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete session [%s]: %v", id, err)
+	}
+	m.removeKubeconfigs(s)
+	delete(m.sessions, id)
+	if s.drivers != nil && m.inUse == s.drivers {
+		m.inUse = nil
+	}
+	s.drivers = nil
+	log.Infof("Deleted session [%s] named [%s]", s.ID, s.Name)
+	return nil
+}
+
+// Activate creates the drivers of a session if not already, points the shared
+// clients at its clusters and holds them there until release is called. It is
+// called by requests: it returns ErrBusy instead of waiting when a job of
+// another session holds the clients, and only waits for requests of other
+// sessions.
+func (m *Manager) Activate(s *Session) (release func(), err error) {
+	return m.activate(s, false)
+}
+
+// ActivateJob is Activate for a job, waiting for the requests and the jobs of
+// other sessions to release the clients
+func (m *Manager) ActivateJob(s *Session) (release func(), err error) {
+	return m.activate(s, true)
+}
+
+func (m *Manager) activate(s *Session, job bool) (func(), error) {
+	m.clientsLock.Lock()
+	defer m.clientsLock.Unlock()
+	if s.drivers == nil {
+		var paths []string
+		for _, name := range s.Kubeconfigs {
+			paths = append(paths, filepath.Join(m.kubeconfigDir, name))
+		}
+		s.drivers = m.newDrivers(s, paths)
+	}
+	for m.users > 0 && m.inUse != s.drivers {
+		if !job && m.jobs > 0 {
+			return nil, fmt.Errorf("failed to switch to session [%s]: %w", s.ID, ErrBusy)
+		}
+		m.clientsFree.Wait()
+	}
+	if m.inUse != s.drivers {
+		if err := s.drivers.Use(); err != nil {
+			// the clients may be half switched, switch them again next time
+			m.inUse = nil
+			return nil, fmt.Errorf("failed to switch to session [%s]: %v", s.ID, err)
+		}
+		m.inUse = s.drivers
+	}
+	m.users++
+	if job {
+		m.jobs++
+	}
+	return func() {
+		m.clientsLock.Lock()
+		defer m.clientsLock.Unlock()
+		m.users--
+		if job {
+			m.jobs--
+		}
+		m.clientsFree.Broadcast()
+	}, nil
+}
+
+func (m *Manager) sessionPath(id string) string {
+	return filepath.Join(m.dir, id+".json")
+}
+
+func (m *Manager) removeKubeconfigs(s *Session) {
+	for _, name := range s.Kubeconfigs {
+		if err := os.Remove(filepath.Join(m.kubeconfigDir, name)); err != nil && !os.IsNotExist(err) {
+			log.Errorf("Failed to remove kubeconfig [%s] of session [%s]: %v", name, s.ID, err)
+		}
+	}
+}
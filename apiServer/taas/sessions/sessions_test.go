@@ -0,0 +1,188 @@
+package sessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder records the drivers created for the sessions and their uses
+type recorder struct {
+	sync.Mutex
+	created   []string
+	activated []string
+	paths     [][]string
+}
+
+// recordedDrivers are the drivers of a session recording their uses
+type recordedDrivers struct {
+	r  *recorder
+	id string
+}
+
+func (d *recordedDrivers) Use() error {
+	d.r.Lock()
+	defer d.r.Unlock()
+	d.r.activated = append(d.r.activated, d.id)
+	return nil
+}
+
+func (r *recorder) newDrivers(s *Session, kubeconfigPaths []string) Drivers {
+	r.Lock()
+	defer r.Unlock()
+	r.created = append(r.created, s.ID)
+	r.paths = append(r.paths, kubeconfigPaths)
+	return &recordedDrivers{r: r, id: s.ID}
+}
+
+func TestSessions(t *testing.T) {
+	dir, kubeconfigDir := t.TempDir(), t.TempDir()
+	r := &recorder{}
+	m, err := NewManager(dir, kubeconfigDir, r.newDrivers)
+	require.NoError(t, err)
+
+	dr, err := m.Create(models.CreateSessionRequest{Name: "dr", Kubeconfig: "source", DestinationKubeconfig: "destination"})
+	require.NoError(t, err)
+	require.True(t, dr.HasDestination)
+	require.Len(t, dr.Kubeconfigs, 2)
+	data, err := os.ReadFile(filepath.Join(kubeconfigDir, dr.Kubeconfigs[1]))
+	require.NoError(t, err)
+	require.Equal(t, "destination", string(data))
+
+	list := m.List()
+	require.Len(t, list, 2)
+	require.Equal(t, models.DefaultSession, list[0].ID)
+	require.Equal(t, dr.ID, list[1].ID)
+
+	// the drivers are created once per session and used only when the session changes
+	for _, s := range []*Session{dr, dr} {
+		release, err := m.Activate(s)
+		require.NoError(t, err)
+		release()
+	}
+	def, err := m.Get(models.DefaultSession)
+	require.NoError(t, err)
+	release, err := m.Activate(def)
+	require.NoError(t, err)
+	release()
+	release, err = m.Activate(dr)
+	require.NoError(t, err)
+	release()
+	require.Equal(t, []string{dr.ID, models.DefaultSession}, r.created)
+	require.Equal(t, []string{dr.ID, models.DefaultSession, dr.ID}, r.activated)
+	require.Equal(t, []string{filepath.Join(kubeconfigDir, dr.Kubeconfigs[0]), filepath.Join(kubeconfigDir, dr.Kubeconfigs[1])}, r.paths[0])
+	require.Empty(t, r.paths[1])
+
+	// sessions are reloaded by a new server
+	m, err = NewManager(dir, kubeconfigDir, r.newDrivers)
+	require.NoError(t, err)
+	reloaded, err := m.Get(dr.ID)
+	require.NoError(t, err)
+	require.Equal(t, dr.Kubeconfigs, reloaded.Kubeconfigs)
+
+	require.ErrorIs(t, m.Delete(models.DefaultSession), ErrDefault)
+	require.NoError(t, m.Delete(dr.ID))
+	require.ErrorIs(t, m.Delete(dr.ID), ErrNotFound)
+	_, err = os.Stat(filepath.Join(kubeconfigDir, dr.Kubeconfigs[0]))
+	require.True(t, os.IsNotExist(err))
+	require.Len(t, m.List(), 1)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := &recorder{}
+	m, err := NewManager(t.TempDir(), t.TempDir(), r.newDrivers)
+	require.NoError(t, err)
+	s, err := m.Create(models.CreateSessionRequest{Name: "test", Kubeconfig: "kubeconfig"})
+	require.NoError(t, err)
+
+	router := gin.New()
+	for _, route := range []models.Route{
+		{Method: http.MethodGet, Path: "taas/getnodes", Session: true},
+		{Method: http.MethodPost, Path: "taas/jobs/rebootnode/:nodename", Session: true, Async: true},
+		{Method: http.MethodGet, Path: "taas/jobs", Session: false},
+	} {
+		router.Handle(route.Method, route.Path, m.Handler(route), func(c *gin.Context) {
+			id := ""
+			if s := SessionFrom(c); s != nil {
+				id = s.ID
+			}
+			c.String(http.StatusOK, id)
+		})
+	}
+	serve := func(method, path, session string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if session != "" {
+			req.Header.Set(models.SessionHeader, session)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodGet, "/taas/getnodes", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, models.DefaultSession, w.Body.String())
+	w = serve(http.MethodGet, "/taas/getnodes", s.ID)
+	require.Equal(t, s.ID, w.Body.String())
+	require.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/taas/getnodes", "unknown").Code)
+
+	// jobs switch to the session when they run, not when submitted
+	w = serve(http.MethodPost, "/taas/jobs/rebootnode/all", s.ID)
+	require.Equal(t, s.ID, w.Body.String())
+	require.Empty(t, serve(http.MethodGet, "/taas/jobs", s.ID).Body.String())
+	require.Equal(t, []string{models.DefaultSession, s.ID}, r.activated)
+}
+
+func TestActivateWithJob(t *testing.T) {
+	r := &recorder{}
+	m, err := NewManager(t.TempDir(), t.TempDir(), r.newDrivers)
+	require.NoError(t, err)
+	s, err := m.Create(models.CreateSessionRequest{Name: "test", Kubeconfig: "kubeconfig"})
+	require.NoError(t, err)
+	def, err := m.Get(models.DefaultSession)
+	require.NoError(t, err)
+
+	releaseJob, err := m.ActivateJob(s)
+	require.NoError(t, err)
+	// requests of the session of the job run alongside it
+	release, err := m.Activate(s)
+	require.NoError(t, err)
+	release()
+	// requests of other sessions don't wait for the job
+	_, err = m.Activate(def)
+	require.ErrorIs(t, err, ErrBusy)
+	require.ErrorIs(t, m.Delete(s.ID), ErrInUse)
+
+	// jobs of other sessions wait for the job
+	switched := make(chan error, 1)
+	go func() {
+		release, err := m.ActivateJob(def)
+		if err == nil {
+			release()
+		}
+		switched <- err
+	}()
+	select {
+	case <-switched:
+		t.Fatal("job of the default session ran alongside the job of another session")
+	case <-time.After(100 * time.Millisecond):
+	}
+	releaseJob()
+	select {
+	case err = <-switched:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job of the default session did not run after the job of another session")
+	}
+	require.Equal(t, []string{s.ID, models.DefaultSession}, r.activated)
+	require.NoError(t, m.Delete(s.ID))
+}
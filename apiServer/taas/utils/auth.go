@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/portworx/torpedo/apiServer/taas/auth"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+// NewAuthorizer returns the authorizer of the API server routes. The
+// TAAS_AUTH_MODE environment variable selects the authentication:
+//   - token, the default: static bearer tokens listed in the file set by TAAS_AUTH_TOKENS_FILE
+//   - none: no authentication, every caller is allowed all routes
+//
+// Calls of the destructive routes are audited to the file set by
+// TAAS_AUDIT_LOG, or to stdout.
+func NewAuthorizer() (*auth.Authorizer, error) {
+	audit, err := auth.OpenAuditLog(os.Getenv(auditLogEnv))
+	if err != nil {
+		return nil, err
+	}
+	switch mode := os.Getenv(authModeEnv); mode {
+	case "", authModeToken:
+		path := os.Getenv(authTokensFileEnv)
+		if path == "" {
+			return nil, fmt.Errorf("%s must be set with auth mode [%s], or set %s=%s to disable authentication",
+				authTokensFileEnv, authModeToken, authModeEnv, authModeNone)
+		}
+		authn, err := auth.LoadTokenAuthenticator(path)
+		if err != nil {
+			return nil, err
+		}
+		return auth.NewAuthorizer(authn, audit), nil
+	case authModeNone:
+		log.Warnf("Authentication of the TaaS API server is disabled, anyone can call the destructive routes")
+		return auth.NewAuthorizer(nil, audit), nil
+	default:
+		return nil, fmt.Errorf("unknown auth mode [%s], expected [%s] or [%s]", mode, authModeToken, authModeNone)
+	}
+}
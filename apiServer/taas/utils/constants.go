@@ -6,10 +6,6 @@ import (
 	"github.com/portworx/sched-ops/k8s/core"
 )
 
-type HelmPayload struct {
-	Command string `json:"command"`
-}
-
 const (
 	mysql                        = "mysql"
 	cassandra                    = "cassandra"
@@ -22,6 +18,18 @@ const (
 	defaultCommandRetry          = 5 * time.Second
 	defaultCommandTimeout        = 1 * time.Minute
 	defaultTestConnectionTimeout = 15 * time.Minute
+	jobsDirEnv                   = "TAAS_JOBS_DIR"
+	defaultJobsDir               = "/var/lib/taas/jobs"
+	authModeEnv                  = "TAAS_AUTH_MODE"
+	authModeToken                = "token"
+	authModeNone                 = "none"
+	authTokensFileEnv            = "TAAS_AUTH_TOKENS_FILE"
+	auditLogEnv                  = "TAAS_AUDIT_LOG"
+	defaultChaosLevel            = 5
+	sessionsDirEnv               = "TAAS_SESSIONS_DIR"
+	defaultSessionsDir           = "/var/lib/taas/sessions"
+	kubeconfigsEnv               = "KUBECONFIGS"
+	kubeconfigEnv                = "KUBECONFIG"
 )
 
 var (
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/jobs"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/portworx/torpedo/apiServer/taas/openapi"
+	"github.com/portworx/torpedo/apiServer/taas/sessions"
+	"github.com/portworx/torpedo/tests"
+)
+
+var (
+	// Jobs runs the jobs submitted to the API server
+	Jobs *jobs.Manager
+
+	// initLock serializes the initialization of the torpedo drivers
+	initLock sync.Mutex
+	// parseFlagsOnce guards the parsing of the torpedo flags
+	parseFlagsOnce sync.Once
+	// defaultInstance is the torpedo instance of the default session
+	defaultInstance *tests.Torpedo
+	// initializedInstances are the torpedo instances whose drivers are initialized
+	initializedInstances = make(map[*tests.Torpedo]bool)
+)
+
+// InitJobs creates the job manager. Jobs are persisted in the directory set by
+// the TAAS_JOBS_DIR environment variable. They run one at a time as torpedo
+// keeps global state, the synchronous requests of their session running
+// alongside them.
+func InitJobs() error {
+	dir := os.Getenv(jobsDirEnv)
+	if dir == "" {
+		dir = defaultJobsDir
+	}
+	store, err := jobs.NewFileStore(dir)
+	if err != nil {
+		return err
+	}
+	Jobs, err = jobs.NewManager(store, 1)
+	return err
+}
+
+// initTorpedo initializes the torpedo drivers of the session in use if not done yet
+func initTorpedo(logger *jobs.Logger) error {
+	initLock.Lock()
+	defer initLock.Unlock()
+	parseFlags()
+	if initializedInstances[tests.Inst()] {
+		return nil
+	}
+	logger.Infof("Initializing torpedo drivers")
+	initializeDrivers()
+	if !initializedInstances[tests.Inst()] {
+		return fmt.Errorf("torpedo init failed")
+	}
+	return nil
+}
+
+// SubmitInitTorpedo : Submits a job initializing the torpedo drivers
+func SubmitInitTorpedo(c *gin.Context) {
+	Jobs.SubmitJob(c, models.JobInitTorpedo, nil, inSession(c, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		if err := initTorpedo(logger); err != nil {
+			return nil, err
+		}
+		return models.MessageResponse{Message: "Torpedo drivers initialized"}, nil
+	}))
+}
+
+// SubmitRebootNode : Submits a job rebooting all nodes, a random node or the node with the given name
+func SubmitRebootNode(c *gin.Context) {
+	nodename := c.Param("nodename")
+	Jobs.SubmitJob(c, models.JobRebootNode, nil, inSession(c, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		if err := initTorpedo(logger); err != nil {
+			return nil, err
+		}
+		return rebootNodes(ctx, logger, nodename)
+	}))
+}
+
+// SubmitCollectSupport : Submits a job collecting the support bundle
+func SubmitCollectSupport(c *gin.Context) {
+	Jobs.SubmitJob(c, models.JobCollectSupport, nil, inSession(c, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		if err := initTorpedo(logger); err != nil {
+			return nil, err
+		}
+		logger.Infof("Collecting support bundle")
+		tests.CollectSupport()
+		return models.MessageResponse{Message: "Collection of support bundle done from Torpedo End"}, nil
+	}))
+}
+
+// SubmitScheduleApps : Submits a job scheduling and validating applications
+func SubmitScheduleApps(c *gin.Context) {
+	var requestBody models.ScheduleAppsRequest
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	session := sessions.SessionFrom(c)
+	Jobs.SubmitJob(c, models.JobScheduleApps, requestBody, inSession(c, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		if err := initTorpedo(logger); err != nil {
+			return nil, err
+		}
+		return scheduleAppsAndValidate(ctx, logger, session, requestBody)
+	}))
+}
+
+// SubmitUpgradeStork : Submits a job upgrading stork to the given version
+func SubmitUpgradeStork(c *gin.Context) {
+	var requestBody models.UpgradeStorkRequest
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	Jobs.SubmitJob(c, models.JobUpgradeStork, requestBody, inSession(c, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		if err := initTorpedo(logger); err != nil {
+			return nil, err
+		}
+		logger.Infof("Upgrading stork to [%s]", requestBody.Version)
+		if err := tests.UpgradeStorkVersion(requestBody.Version); err != nil {
+			return nil, err
+		}
+		return models.MessageResponse{Message: "Stork upgraded successfully"}, nil
+	}))
+}
+
+// SubmitRunHelmCmd : Submits a job running a helm command, the job is killed when cancelled
+func SubmitRunHelmCmd(c *gin.Context) {
+	var payload models.HelmPayload
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	Jobs.SubmitJob(c, models.JobRunHelmCmd, payload, inSession(c, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		logger.Infof("Running command [%s]", payload.Command)
+		out, err := exec.CommandContext(ctx, "sh", "-c", payload.Command).CombinedOutput()
+		logger.Infof("Command output: %s", out)
+		if err != nil {
+			return nil, fmt.Errorf("command execution failed: %v", err)
+		}
+		return models.CommandResult{Output: string(out)}, nil
+	}))
+}
+
+// inSession wraps the run function of a job submitted by a request, switching
+// the drivers to the session of the request while the job runs
+func inSession(c *gin.Context, run jobs.RunFunc) jobs.RunFunc {
+	session := sessions.SessionFrom(c)
+	return func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		logger.Infof("Switching to session [%s]", session.ID)
+		release, err := Sessions.ActivateJob(session)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return run(ctx, logger)
+	}
+}
+
+// GetOpenAPISpec : Returns the OpenAPI spec of the API server
+func GetOpenAPISpec(c *gin.Context) {
+	data, err := openapi.Generate(models.Routes).JSON()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/json", data)
+}
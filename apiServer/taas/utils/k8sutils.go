@@ -3,6 +3,7 @@ package utils
 import (
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/portworx/torpedo/apiServer/taas/models"
 	"github.com/portworx/torpedo/drivers/pds/lib"
 	"github.com/portworx/torpedo/pkg/log"
 	"github.com/portworx/torpedo/tests"
@@ -40,7 +41,7 @@ func CreateNS(c *gin.Context) {
 
 // ExecuteHelmCmd : Execute the copied Helm Command
 func ExecuteHelmCmd(c *gin.Context) {
-	var payload HelmPayload
+	var payload models.HelmPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
 		c.JSON(400, gin.H{
 			"error": err.Error(),
@@ -123,12 +124,7 @@ func GetNodeStatus(c *gin.Context) {
 // CreateVolumeSnapshotClass creates volume snapshot class
 func CreateVolumeSnapshotClass(c *gin.Context) {
 	log.Infof("Creating volume snapshot class")
-	var createVolumeSnapshotClassRequest struct {
-		VolumeSnapshotClassName      string `json:"volumeSnapshotClassName"`
-		Provisioner                  string `json:"provisioner"`
-		IsDefaultVolumeSnapshotClass bool   `json:"isDefaultVolumeSnapshotClass"`
-		DeletePolicy                 string `json:"deletePolicy"`
-	}
+	var createVolumeSnapshotClassRequest models.CreateVolumeSnapshotClassRequest
 	if !checkTorpedoInit(c) {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error in init": fmt.Errorf("error in InitInstance()"),
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"os"
+	"strings"
+
+	"github.com/portworx/torpedo/apiServer/taas/sessions"
+	"github.com/portworx/torpedo/tests"
+)
+
+// Sessions are the sessions of the clusters the API server acts on
+var Sessions *sessions.Manager
+
+// InitSessions creates the session manager. Sessions are persisted in the
+// directory set by the TAAS_SESSIONS_DIR environment variable, their
+// kubeconfigs being written where torpedo looks for the KUBECONFIGS.
+func InitSessions() error {
+	dir := os.Getenv(sessionsDirEnv)
+	if dir == "" {
+		dir = defaultSessionsDir
+	}
+	defaultEnv := map[string]string{
+		kubeconfigsEnv: os.Getenv(kubeconfigsEnv),
+		kubeconfigEnv:  os.Getenv(kubeconfigEnv),
+	}
+	var err error
+	Sessions, err = sessions.NewManager(dir, tests.KubeconfigDirectory, func(s *sessions.Session, kubeconfigPaths []string) sessions.Drivers {
+		if s.IsDefault() {
+			return &sessionDrivers{env: defaultEnv}
+		}
+		return &sessionDrivers{
+			env: map[string]string{
+				kubeconfigsEnv: strings.Join(s.Kubeconfigs, ","),
+				kubeconfigEnv:  kubeconfigPaths[0],
+			},
+			kubeconfigPath: kubeconfigPaths[0],
+		}
+	})
+	return err
+}
+
+// sessionDrivers are the torpedo drivers of a session. The default session uses
+// the torpedo instance of the API server, the other sessions their own instance,
+// initialized by initTorpedo for the cluster of the session.
+type sessionDrivers struct {
+	instance *tests.Torpedo
+	// env are the environment variables of the session. The DR helpers of
+	// torpedo find the source and destination clusters in KUBECONFIGS, and the
+	// commands run by the API server, such as helm, use KUBECONFIG.
+	env map[string]string
+	// kubeconfigPath is the kubeconfig of the source cluster, empty for the
+	// default session
+	kubeconfigPath string
+}
+
+// Use sets the torpedo instance of the session and points the kubernetes
+// clients at its cluster
+func (d *sessionDrivers) Use() error {
+	for name, value := range d.env {
+		if err := os.Setenv(name, value); err != nil {
+			return err
+		}
+	}
+	initLock.Lock()
+	defer initLock.Unlock()
+	parseFlags()
+	if d.instance == nil {
+		if d.kubeconfigPath == "" {
+			d.instance = defaultInstance
+		} else {
+			d.instance = tests.NewInstance()
+		}
+	}
+	tests.SetInstance(d.instance)
+	if !initializedInstances[d.instance] {
+		// the clients are set for initTorpedo to initialize the drivers for the
+		// cluster of the session, the node registry being refreshed by the init
+		if err := d.instance.S.SetConfig(d.kubeconfigPath); err != nil {
+			return err
+		}
+		tests.CurrentClusterConfigPath = d.kubeconfigPath
+		return nil
+	}
+	return tests.SetClusterContext(d.kubeconfigPath)
+}
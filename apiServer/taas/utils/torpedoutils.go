@@ -1,55 +1,85 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/portworx/sched-ops/k8s/core"
+	"github.com/portworx/torpedo/apiServer/taas/jobs"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/portworx/torpedo/apiServer/taas/sessions"
 	"github.com/portworx/torpedo/drivers/backup"
 	"github.com/portworx/torpedo/drivers/node"
-	"github.com/portworx/torpedo/drivers/scheduler"
 	"github.com/portworx/torpedo/pkg/log"
 	"github.com/portworx/torpedo/tests"
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	"math/rand"
 	"net/http"
 	"regexp"
+	"strings"
 )
 
 var (
-	IsTorpedoInitDone bool                 // flag to check if Drivers init is done
-	context           []*scheduler.Context // Application context will be maintained globally for now
+	IsTorpedoInitDone bool // flag to check if Drivers init is done for the cluster of the API server
+
+	errNodeNotFound = errors.New("node not found")
 )
 
-// This method checks if test has done InitInstance once or not. If not, we will try to do it.
+// This method checks if test has done InitInstance once for the session in use or not. If not, we will try to do it.
 func checkTorpedoInit(c *gin.Context) bool {
-	if !IsTorpedoInitDone {
-		InitializeDrivers(c)
-		if !IsTorpedoInitDone {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "Torpedo Init failed",
-			})
-			return false
-		}
+	if err := initTorpedo(nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"message": "Torpedo Init failed",
+		})
+		return false
 	}
 	return true
 }
 
 // InitializeDrivers : This API Call will init all Torpedo Drivers. This needs to be run as ginkgo test
 // as multiple ginkgo and gomega dependencies are being called in InitInstance()
+// The drivers are initialized for the cluster of the API server, the default session.
 func InitializeDrivers(c *gin.Context) {
-	// TODO: Remove the Ginkgo dependency from functions outside the tests package.
-	// Redefining tests.Step to avoid Ginkgo's "spec structure" error with `go run`, ensuring compatibility.
-	tests.Step = func(text string, callback ...func()) {
-		log.Infof("Step: [%s]", text)
-		if len(callback) == 1 {
-			callback[0]()
-		} else if len(callback) > 1 {
-			panic(fmt.Sprintf("Step: [%s] has more than one callback", text))
+	defaultSession, err := Sessions.Get(models.DefaultSession)
+	if err == nil {
+		var release func()
+		if release, err = Sessions.Activate(defaultSession); err == nil {
+			defer release()
+			err = initTorpedo(nil)
 		}
 	}
-	tests.ParseFlags()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// parseFlags parses the torpedo flags once, creating the torpedo instance of
+// the cluster of the API server
+func parseFlags() {
+	parseFlagsOnce.Do(func() {
+		// TODO: Remove the Ginkgo dependency from functions outside the tests package.
+		// Redefining tests.Step to avoid Ginkgo's "spec structure" error with `go run`, ensuring compatibility.
+		tests.Step = func(text string, callback ...func()) {
+			log.Infof("Step: [%s]", text)
+			if len(callback) == 1 {
+				callback[0]()
+			} else if len(callback) > 1 {
+				panic(fmt.Sprintf("Step: [%s] has more than one callback", text))
+			}
+		}
+		tests.ParseFlags()
+		defaultInstance = tests.Inst()
+	})
+}
+
+// initializeDrivers inits the torpedo drivers of the instance in use
+func initializeDrivers() {
 	tests.InitInstance()
-	IsTorpedoInitDone = true
+	initializedInstances[tests.Inst()] = true
+	if tests.Inst() == defaultInstance {
+		IsTorpedoInitDone = true
+	}
 }
 
 // GetNodes : This API will return list of all worker nodes in the Cluster
@@ -78,27 +108,57 @@ func RebootNode(c *gin.Context) {
 		})
 		return
 	}
-	nodes := node.GetWorkerNodes()
 	nodename := c.Param("nodename")
-	if nodename == "all" {
+	result, err := rebootNodes(context.Background(), nil, nodename)
+	if err == errNodeNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Node with name %s not found", nodename)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	switch nodename {
+	case "all":
+		c.JSON(http.StatusOK, gin.H{"message": "All Nodes successfully rebooted"})
+	case "random":
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Randomly selected node %s successfully rebooted", result.Nodes[0])})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Node with name %s successfully rebooted", nodename)})
+	}
+}
+
+// rebootNodes reboots all nodes, a random node or the node with the given name.
+// It stops before the next node when ctx is cancelled.
+func rebootNodes(ctx context.Context, logger *jobs.Logger, nodename string) (*models.RebootNodeResult, error) {
+	nodes := node.GetWorkerNodes()
+	var selected []node.Node
+	switch nodename {
+	case "all":
+		selected = nodes
+	case "random":
+		if len(nodes) == 0 {
+			return nil, errNodeNotFound
+		}
+		selected = []node.Node{nodes[rand.Intn(len(nodes))]}
+	default:
 		for _, n := range nodes {
-			err := tests.Inst().N.RebootNode(n, node.RebootNodeOpts{
-				Force: true,
-				ConnectionOpts: node.ConnectionOpts{
-					Timeout:         defaultCommandTimeout,
-					TimeBeforeRetry: defaultCommandRetry,
-				},
-			})
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				return
+			if n.Name == nodename {
+				selected = []node.Node{n}
+				break
 			}
 		}
-		c.JSON(http.StatusOK, gin.H{"message": "All Nodes successfully rebooted"})
-		return
-	} else if nodename == "random" {
-		randomNode := nodes[rand.Intn(len(nodes))]
-		err := tests.Inst().N.RebootNode(randomNode, node.RebootNodeOpts{
+		if len(selected) == 0 {
+			return nil, errNodeNotFound
+		}
+	}
+	result := &models.RebootNodeResult{Nodes: make([]string, 0, len(selected))}
+	for _, n := range selected {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		logger.Infof("Rebooting node [%s]", n.Name)
+		err := tests.Inst().N.RebootNode(n, node.RebootNodeOpts{
 			Force: true,
 			ConnectionOpts: node.ConnectionOpts{
 				Timeout:         defaultCommandTimeout,
@@ -106,30 +166,11 @@ func RebootNode(c *gin.Context) {
 			},
 		})
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		} else {
-			c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Randomly selected node %s successfully rebooted", randomNode.Name)})
-		}
-	} else {
-		for _, n := range nodes {
-			if n.Name == nodename {
-				err := tests.Inst().N.RebootNode(n, node.RebootNodeOpts{
-					Force: true,
-					ConnectionOpts: node.ConnectionOpts{
-						Timeout:         defaultCommandTimeout,
-						TimeBeforeRetry: defaultCommandRetry,
-					},
-				})
-				if err != nil {
-					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-				} else {
-					c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Node with name %s successfully rebooted", n.Name)})
-				}
-				return
-			}
+			return nil, err
 		}
-		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Node with name %s not found", nodename)})
+		result.Nodes = append(result.Nodes, n.Name)
 	}
+	return result, nil
 }
 
 // GetStorageNodes : Returns all Storage Node objects in the cluster
@@ -177,16 +218,9 @@ func CollectSupport(c *gin.Context) {
 }
 
 // ScheduleAppsAndValidate : This API schedules multiple applications on the cluster and validates them
-// context is created as a global context to be accessed later in further tests
+// The contexts are kept in the session of the request to be accessed later in further tests
 func ScheduleAppsAndValidate(c *gin.Context) {
-
-	var requestBody struct {
-		NamespaceSuffix string   `json:"nsSuffix"`
-		AppList         []string `json:"appList"`
-	}
-	var errors []error
-	errStrings := make([]string, 0)
-	errChan := make(chan error, 100)
+	var requestBody models.ScheduleAppsRequest
 	if !checkTorpedoInit(c) {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Errorf("error during InitInstance"),
@@ -197,38 +231,53 @@ func ScheduleAppsAndValidate(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	tests.Inst().AppList = requestBody.AppList
-	options := tests.CreateScheduleOptions(requestBody.NamespaceSuffix)
-	context, err := tests.Inst().S.Schedule(requestBody.NamespaceSuffix, options)
+	result, err := scheduleAppsAndValidate(context.Background(), nil, sessions.SessionFrom(c), requestBody)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	for _, ctx := range context {
-		tests.ValidateContext(ctx, &errChan)
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "App is created and validated successfully",
+		"namespace": result.Namespaces,
+	})
+}
+
+// scheduleAppsAndValidate schedules the applications in the session and validates them.
+// It stops before validating the next application when ctx is cancelled.
+func scheduleAppsAndValidate(ctx context.Context, logger *jobs.Logger, session *sessions.Session,
+	requestBody models.ScheduleAppsRequest) (*models.ScheduleAppsResult, error) {
+	tests.Inst().AppList = requestBody.AppList
+	options := tests.CreateScheduleOptions(requestBody.NamespaceSuffix)
+	logger.Infof("Scheduling apps %v", requestBody.AppList)
+	scheduled, err := tests.Inst().S.Schedule(requestBody.NamespaceSuffix, options)
+	if err != nil {
+		return nil, err
 	}
-	for err = range errChan {
-		errors = append(errors, err)
+	session.Contexts = append(session.Contexts, scheduled...)
+
+	errChan := make(chan error, 100)
+	for _, appCtx := range scheduled {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		logger.Infof("Validating app [%s]", appCtx.App.Key)
+		tests.ValidateContext(appCtx, &errChan)
 	}
-	for _, err = range errors {
+	close(errChan)
+	errStrings := make([]string, 0)
+	for err := range errChan {
 		if err != nil {
 			errStrings = append(errStrings, err.Error())
 		}
 	}
 	if len(errStrings) > 0 {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": errStrings})
-		return
+		return nil, fmt.Errorf("app validation failed: %s", strings.Join(errStrings, "; "))
 	}
-	namespacesList := make([]string, 0)
-	for _, ctx := range context {
-		namespace := tests.GetAppNamespace(ctx, requestBody.NamespaceSuffix)
-		namespacesList = append(namespacesList, namespace)
+	result := &models.ScheduleAppsResult{Namespaces: make([]string, 0, len(scheduled))}
+	for _, appCtx := range scheduled {
+		result.Namespaces = append(result.Namespaces, tests.GetAppNamespace(appCtx, requestBody.NamespaceSuffix))
 	}
-	c.JSON(http.StatusOK, gin.H{
-		"message":   "App is created and validated successfully",
-		"namespace": namespacesList,
-	})
+	return result, nil
 }
 
 // GetPxVersion This function returns the current Px Version in the Target Cluster
@@ -349,16 +398,9 @@ func GetPxctlStatusOutput(c *gin.Context) {
 
 // GetVMsInNamespaces gets the list of Virtual Machines in the given namespaces
 func GetVMsInNamespaces(c *gin.Context) {
-	var requestBody struct {
-		Namespaces []string `json:"namespaces"`
-	}
+	var requestBody models.NamespacesRequest
 	var vms []kubevirtv1.VirtualMachine
-	type VM struct {
-		Name      string `json:"name"`
-		Namespace string `json:"namespace"`
-		Status    string `json:"status"`
-	}
-	var vmResponse []VM
+	var vmResponse []models.VM
 
 	if !checkTorpedoInit(c) {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -386,7 +428,7 @@ func GetVMsInNamespaces(c *gin.Context) {
 	}
 
 	for _, v := range vms {
-		vmResponse = append(vmResponse, VM{
+		vmResponse = append(vmResponse, models.VM{
 			Name:      v.Name,
 			Namespace: v.Namespace,
 			Status:    string(v.Status.PrintableStatus),
@@ -399,16 +441,9 @@ func GetVMsInNamespaces(c *gin.Context) {
 
 // GetVMsWithNamespaceLabels gets the list of Virtual Machines in the namespaces with the given labels
 func GetVMsWithNamespaceLabels(c *gin.Context) {
-	var requestBody struct {
-		NamespaceLabels map[string]string `json:"namespaceLabels"`
-	}
+	var requestBody models.NamespaceLabelsRequest
 	var vms []kubevirtv1.VirtualMachine
-	type VM struct {
-		Name      string `json:"name"`
-		Namespace string `json:"namespace"`
-		Status    string `json:"status"`
-	}
-	var vmResponse []VM
+	var vmResponse []models.VM
 	if !checkTorpedoInit(c) {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": fmt.Errorf("error in InitInstance()"),
@@ -429,7 +464,7 @@ func GetVMsWithNamespaceLabels(c *gin.Context) {
 		return
 	}
 	for _, v := range vms {
-		vmResponse = append(vmResponse, VM{
+		vmResponse = append(vmResponse, models.VM{
 			Name:      v.Name,
 			Namespace: v.Namespace,
 			Status:    string(v.Status.PrintableStatus),
@@ -449,10 +484,7 @@ func AddNSLabel(c *gin.Context) {
 		Failed  map[string]string `json:"failed"`
 	}
 
-	var NamespaceLabelRequest struct {
-		Namespaces []string          `json:"namespaces" binding:"required"`
-		Label      map[string]string `json:"ns_label" binding:"required"`
-	}
+	var NamespaceLabelRequest models.NamespaceLabelRequest
 	success := make(map[string]string)
 	failed := make(map[string]string)
 	if !checkTorpedoInit(c) {
@@ -491,9 +523,7 @@ func AddNSLabel(c *gin.Context) {
 
 // UpgradeStork upgrades the stork to given version
 func UpgradeStork(c *gin.Context) {
-	var requestBody struct {
-		Version string `json:"version"`
-	}
+	var requestBody models.UpgradeStorkRequest
 	if err := c.BindJSON(&requestBody); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -518,12 +548,7 @@ func UpgradeStork(c *gin.Context) {
 // DeletePod deletes the pods with given label
 func DeletePod(c *gin.Context) {
 	log.Infof("Deleting pods with given label")
-	var deletePodRequest struct {
-		Namespace   string            `json:"namespace"`
-		Label       map[string]string `json:"label"`
-		PodList     []string          `json:"podList"`
-		IgnoreLabel bool              `json:"ignoreLabel"`
-	}
+	var deletePodRequest models.DeletePodRequest
 	if !checkTorpedoInit(c) {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error in init": fmt.Errorf("error in InitInstance()"),
@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pborman/uuid"
+	"github.com/portworx/torpedo/apiServer/taas/jobs"
+	"github.com/portworx/torpedo/apiServer/taas/models"
+	"github.com/portworx/torpedo/apiServer/taas/sessions"
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/tests"
+)
+
+// ListTriggers : Returns the names of the test triggers which can be run
+func ListTriggers(c *gin.Context) {
+	c.JSON(http.StatusOK, models.TriggerList{Triggers: tests.LongevityTriggerNames()})
+}
+
+// SubmitRunTriggers : Submits a job running test triggers against the apps scheduled in the session of the request.
+// The events recorded by the triggers are returned by taas/jobs/:id/events.
+func SubmitRunTriggers(c *gin.Context) {
+	var requestBody models.RunTriggersRequest
+	if err := c.ShouldBindJSON(&requestBody); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	triggers := tests.LongevityTriggers()
+	for _, name := range requestBody.Triggers {
+		if _, ok := triggers[name]; !ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: fmt.Sprintf("unknown trigger [%s], see taas/triggers", name)})
+			return
+		}
+	}
+	if requestBody.ChaosLevel == 0 {
+		requestBody.ChaosLevel = defaultChaosLevel
+	}
+	if requestBody.ChaosLevel < 1 || requestBody.ChaosLevel > 10 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "chaosLevel must be between 1 and 10"})
+		return
+	}
+	if requestBody.Iterations == 0 {
+		requestBody.Iterations = 1
+	}
+	if requestBody.Iterations < 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "iterations must be positive"})
+		return
+	}
+	session := sessions.SessionFrom(c)
+	Jobs.SubmitJob(c, models.JobRunTriggers, requestBody, inSession(c, func(ctx context.Context, logger *jobs.Logger) (interface{}, error) {
+		if err := initTorpedo(logger); err != nil {
+			return nil, err
+		}
+		return runTriggers(ctx, logger, session, requestBody, triggers)
+	}))
+}
+
+// runTriggers runs the triggers in order for the requested iterations against the apps of
+// the session, recording their events as job events. It stops before the next trigger when
+// ctx is cancelled.
+func runTriggers(ctx context.Context, logger *jobs.Logger, session *sessions.Session, req models.RunTriggersRequest,
+	triggers map[string]func(*[]*scheduler.Context, *chan *tests.EventRecord)) (*models.RunTriggersResult, error) {
+	if len(session.Contexts) == 0 {
+		logger.Infof("No apps were scheduled in session [%s], triggers targeting apps will find none", session.ID)
+	}
+	for _, name := range req.Triggers {
+		if err := tests.ValidateTriggerSettings(name); err != nil {
+			return nil, err
+		}
+	}
+	if tests.ChaosMap == nil {
+		tests.ChaosMap = make(map[string]int)
+	}
+	// the server log and the dashboard are shared by all requests, so trigger executions
+	// must not redirect the log or open test cases
+	tests.TriggerTestCases = false
+
+	// triggers send their records from deferred functions, which may run after
+	// the trigger returns, so the channel is drained until stopped rather than closed.
+	// Each record goes through tests.RecordEvent, like in the longevity test, which ends
+	// the disruption window of the trigger and journals the event.
+	result := &models.RunTriggersResult{}
+	eventsChan := make(chan *tests.EventRecord, 100)
+	stop, done := make(chan struct{}), make(chan struct{})
+	record := func(r *tests.EventRecord) {
+		tests.RecordEvent(r)
+		event := triggerEvent(r)
+		result.Events++
+		if len(event.Errors) > 0 {
+			result.FailedEvents++
+		}
+		logger.Event(event)
+	}
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case r := <-eventsChan:
+				record(r)
+			case <-stop:
+				for {
+					select {
+					case r := <-eventsChan:
+						record(r)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	var err error
+loop:
+	for i := 1; i <= req.Iterations; i++ {
+		for _, name := range req.Triggers {
+			if err = ctx.Err(); err != nil {
+				break loop
+			}
+			tests.ChaosMap[name] = req.ChaosLevel
+			logger.Infof("Running trigger [%s] with chaos level [%d], iteration [%d/%d]", name, req.ChaosLevel, i, req.Iterations)
+			runTrigger(logger, session, name, triggers[name], &eventsChan)
+		}
+	}
+	close(stop)
+	<-done
+	if err != nil {
+		return nil, err
+	}
+	logger.Infof("Triggers recorded [%d] events, [%d] with errors", result.Events, result.FailedEvents)
+	return result, nil
+}
+
+// runTrigger runs a trigger, recording a failed event if it panics, for e.g. on a failed ginkgo assertion
+func runTrigger(logger *jobs.Logger, session *sessions.Session, name string, trigger func(*[]*scheduler.Context, *chan *tests.EventRecord),
+	eventsChan *chan *tests.EventRecord) {
+	start := time.Now().Format(time.RFC1123)
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Trigger [%s] panicked: %v", name, r)
+			*eventsChan <- &tests.EventRecord{
+				Event:   tests.Event{ID: uuid.New(), Type: name},
+				Start:   start,
+				End:     time.Now().Format(time.RFC1123),
+				Outcome: []error{fmt.Errorf("trigger panicked: %v", r)},
+			}
+		}
+	}()
+	trigger(&session.Contexts, eventsChan)
+}
+
+// triggerEvent converts an event record of a trigger
+func triggerEvent(r *tests.EventRecord) models.TriggerEvent {
+	event := models.TriggerEvent{
+		ID:      r.Event.ID,
+		Type:    r.Event.Type,
+		Start:   r.Start,
+		End:     r.End,
+		Nodes:   r.Nodes,
+		Volumes: r.Volumes,
+	}
+	for _, err := range r.Outcome {
+		if err != nil {
+			event.Errors = append(event.Errors, err.Error())
+		}
+	}
+	return event
+}
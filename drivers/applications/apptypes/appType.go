@@ -2,9 +2,13 @@ package applications
 
 // All appType enums
 const (
-	MySql    = "mysql"
-	Postgres = "postgres"
-	Kubevirt = "kubevirt"
+	MySql         = "mysql"
+	Postgres      = "postgres"
+	Kubevirt      = "kubevirt"
+	MongoDB       = "mongodb"
+	Cassandra     = "cassandra"
+	Kafka         = "kafka"
+	Elasticsearch = "elasticsearch"
 )
 
 // Commands to start, pause or stop data
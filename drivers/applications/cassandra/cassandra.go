@@ -0,0 +1,275 @@
+package applications
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
+	. "github.com/portworx/torpedo/drivers/utilities"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+type CassandraConfig struct {
+	Hostname     string
+	User         string
+	Password     string
+	Port         int
+	Keyspace     string
+	Namespace    string
+	DataCommands map[string]map[string][]string
+}
+
+// DefaultPort returns default port for cassandra
+func (app *CassandraConfig) DefaultPort() int { return 9042 }
+
+// DefaultKeyspace returns default keyspace name
+func (app *CassandraConfig) DefaultKeyspace() string { return "torpedo" }
+
+// ExecuteCommand executes CQL queries with cqlsh in a cassandra pod and returns their output
+func (app *CassandraConfig) ExecuteCommand(commands []string, ctx context.Context) ([]string, error) {
+
+	var outputs []string
+	if app.Port == 0 {
+		app.Port = app.DefaultPort()
+	}
+
+	pod, container, err := GetPodWithContainerPort(app.Namespace, app.Port)
+	if err != nil {
+		return outputs, err
+	}
+
+	for _, eachCommand := range commands {
+		cqlsh := []string{"cqlsh", pod.Status.PodIP, strconv.Itoa(app.Port), "--request-timeout=60"}
+		if app.User != "" {
+			cqlsh = append(cqlsh, "-u", app.User, "-p", app.Password)
+		}
+		output, err := RunCommandInContainer(pod, container, append(cqlsh, "-e", eachCommand), "")
+		if err != nil {
+			return outputs, err
+		}
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
+
+// InsertBackupData inserts the rows generated initially by utilities or rows passed
+func (app *CassandraConfig) InsertBackupData(ctx context.Context, identifier string, commands []string) error {
+
+	var err error
+	log.InfoD("Inserting data")
+	if len(commands) == 0 {
+		log.Infof("Inserting below data : %s", strings.Join(app.DataCommands[identifier]["insert"], "\n"))
+		_, err = app.ExecuteCommand(app.DataCommands[identifier]["insert"], ctx)
+	} else {
+		log.Infof("Inserting below data : %s", strings.Join(commands, "\n"))
+		_, err = app.ExecuteCommand(commands, ctx)
+	}
+
+	return err
+}
+
+// Return data inserted before backup
+func (app *CassandraConfig) GetBackupData(identifier string) []string {
+	if _, ok := app.DataCommands[identifier]; ok {
+		return app.DataCommands[identifier]["select"]
+	} else {
+		log.InfoD("%s not found in app cql command", identifier)
+		log.Infof("All current CQL commands - %+v", app.DataCommands)
+		return nil
+	}
+}
+
+// CheckDataPresent checks if the select queries return a row
+func (app *CassandraConfig) CheckDataPresent(selectQueries []string, ctx context.Context) error {
+
+	log.InfoD("Running Select Queries")
+
+	var queryNotFoundList []string
+	for _, eachQuery := range selectQueries {
+		outputs, err := app.ExecuteCommand([]string{eachQuery}, ctx)
+		if err != nil {
+			log.InfoD("Select query failed - [%s] Error - [%s]", eachQuery, err.Error())
+			queryNotFoundList = append(queryNotFoundList, eachQuery)
+			continue
+		}
+		if len(parseCQLRows(outputs[0])) == 0 {
+			log.InfoD("Select query returned no row - [%s]", eachQuery)
+			queryNotFoundList = append(queryNotFoundList, eachQuery)
+		}
+	}
+
+	if len(queryNotFoundList) != 0 {
+		errorMessage := strings.Join(queryNotFoundList, "\n")
+		return fmt.Errorf("Below results not found in the table:\n %s", errorMessage)
+	}
+	return nil
+}
+
+// StartData - Go routine to run parallal with app to keep injecting data every 2 seconds
+func (app *CassandraConfig) StartData(command <-chan string, ctx context.Context) error {
+	var status = DataStart
+	var allSelectCommands []string
+	var allErrors []string
+	var tableName = fmt.Sprintf("%s.table_%s", app.keyspace(), RandomString(4))
+
+	_, err := app.ExecuteCommand([]string{
+		CQLCreateKeyspaceCommand(app.keyspace()),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (key text PRIMARY KEY, value text)", tableName),
+	}, ctx)
+	if err != nil {
+		allErrors = append(allErrors, fmt.Sprintf("Continuity Pipeline Error - [%s] at [%s]", err.Error(), time.Now().Format("2006-01-02 15:04:05")))
+	}
+	for {
+		select {
+		case cmd := <-command:
+			switch cmd {
+			case DataStop:
+				if len(allErrors) != 0 {
+					return fmt.Errorf(strings.Join(allErrors, "\n"))
+				}
+				err := app.CheckDataPresent(allSelectCommands, ctx)
+				return err
+
+			case DataPause:
+				status = DataPause
+			default:
+				status = DataStart
+			}
+		default:
+			if status == DataStart {
+				commandPair := GenerateCQLCommandPair(tableName)
+				_, err := app.ExecuteCommand(commandPair["insert"], ctx)
+				if err != nil {
+					allErrors = append(allErrors, fmt.Sprintf("Continuity Pipeline Error - [%s] at [%s]", err.Error(), time.Now().Format("2006-01-02 15:04:05")))
+				}
+				allSelectCommands = append(allSelectCommands, commandPair["select"]...)
+				time.Sleep(2 * time.Second)
+			}
+		}
+	}
+}
+
+// Update the existing CQL commands
+func (app *CassandraConfig) UpdateDataCommands(count int, identifier string) {
+	app.DataCommands[identifier] = GenerateRandomCQLCommands(count, app.keyspace())
+	log.InfoD("CQL Commands updated")
+}
+
+// Add CQL commands to the existing ones
+func (app *CassandraConfig) AddDataCommands(identifier string, commands map[string][]string) {
+	app.DataCommands[identifier] = commands
+	log.InfoD("CQL commands added")
+}
+
+// Generate and return random CQL commands
+func (app *CassandraConfig) GetRandomDataCommands(count int) map[string][]string {
+	return GenerateRandomCQLCommands(count, app.keyspace())
+}
+
+// Get the application type
+func (app *CassandraConfig) GetApplicationType() string {
+	return Cassandra
+}
+
+// Get Namespace of the app
+func (app *CassandraConfig) GetNamespace() string {
+	return app.Namespace
+}
+
+// WaitForVMToBoot waits for VM to boot
+func (app *CassandraConfig) WaitForVMToBoot() error {
+	log.Warnf("Not implemented for Cassandra")
+	return nil
+}
+
+// WriteWorkload creates the table of the integrity workload in the keyspace and inserts its rows
+func (app *CassandraConfig) WriteWorkload(ctx context.Context, workload integrity.Workload) error {
+	log.InfoD("Writing integrity workload [%s] with %d rows to [%s]", workload.Name, workload.Count, app.Hostname)
+	return app.WriteRows(ctx, workload.ObjectName(), workload.Rows())
+}
+
+// Checksum returns the logical checksum of the integrity workload tables of the keyspace
+func (app *CassandraConfig) Checksum(ctx context.Context) (*integrity.Snapshot, error) {
+	outputs, err := app.ExecuteCommand([]string{
+		fmt.Sprintf("SELECT table_name FROM system_schema.tables WHERE keyspace_name = '%s'", app.keyspace()),
+	}, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := integrity.NewSnapshot(Cassandra, app.Namespace)
+	for _, tableRow := range parseCQLRows(outputs[0]) {
+		table := tableRow[0]
+		if !strings.HasPrefix(table, integrity.ObjectPrefix) {
+			continue
+		}
+		rows, err := app.ReadRows(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		digest := &integrity.Digest{}
+		for _, row := range rows {
+			digest.Add(row.Key, row.Value)
+		}
+		snapshot.Objects[table] = digest.Checksum()
+	}
+	return snapshot, nil
+}
+
+// WriteRows creates the keyspace and the table if they do not exist and inserts the rows
+func (app *CassandraConfig) WriteRows(ctx context.Context, object string, rows []integrity.Row) error {
+	statements := append([]string{CQLCreateKeyspaceCommand(app.keyspace())},
+		integrity.CQLInsertStatements(app.keyspace()+"."+object, rows)...)
+	_, err := app.ExecuteCommand(statements, ctx)
+	return err
+}
+
+// ReadRows returns the rows of the table of the keyspace
+func (app *CassandraConfig) ReadRows(ctx context.Context, object string) ([]integrity.Row, error) {
+	outputs, err := app.ExecuteCommand([]string{integrity.SQLSelectStatement(app.keyspace() + "." + object)}, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table [%s]: %v", object, err)
+	}
+	var rows []integrity.Row
+	for _, columns := range parseCQLRows(outputs[0]) {
+		if len(columns) == 2 {
+			rows = append(rows, integrity.Row{Key: columns[0], Value: columns[1]})
+		}
+	}
+	return rows, nil
+}
+
+// keyspace returns the keyspace the data is written to
+func (app *CassandraConfig) keyspace() string {
+	if app.Keyspace == "" {
+		app.Keyspace = app.DefaultKeyspace()
+	}
+	return app.Keyspace
+}
+
+// parseCQLRows returns the columns of the rows printed by cqlsh, which are the lines between
+// the header separator line and the first empty line
+func parseCQLRows(output string) [][]string {
+	var rows [][]string
+	inRows := false
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !inRows {
+			inRows = trimmed != "" && strings.Trim(trimmed, "-+") == ""
+			continue
+		}
+		if trimmed == "" {
+			break
+		}
+		var columns []string
+		for _, column := range strings.Split(line, "|") {
+			columns = append(columns, strings.TrimSpace(column))
+		}
+		rows = append(rows, columns)
+	}
+	return rows
+}
@@ -0,0 +1,36 @@
+package applications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCQLRows(t *testing.T) {
+	output := `
+ key        | value
+------------+------------------
+ key-abcdef | value-abcdefghij
+      key-2 |          value-2
+
+(2 rows)
+`
+	require.Equal(t, [][]string{{"key-abcdef", "value-abcdefghij"}, {"key-2", "value-2"}}, parseCQLRows(output))
+
+	output = `
+ table_name
+--------------------
+ integrity_before
+
+(1 rows)
+`
+	require.Equal(t, [][]string{{"integrity_before"}}, parseCQLRows(output))
+
+	output = `
+ key | value
+-----+-------
+
+(0 rows)
+`
+	require.Empty(t, parseCQLRows(output))
+}
@@ -0,0 +1,362 @@
+// Package continuity writes sequence numbered, timestamped records to an application while it is
+// migrated, failed over and failed back, and measures from the records present after the failover
+// how much data was lost (RPO) and how long the application could not be written to (RTO).
+package continuity
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+const (
+	// ObjectPrefix is the prefix of the tables and directories the records are written to
+	ObjectPrefix = "continuity_"
+	// DefaultInterval is the interval between two writes of a writer
+	DefaultInterval = 2 * time.Second
+
+	sequenceFormat = "%012d"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_]`)
+
+// Target is where the records are written to and read from, an ApplicationDriver is a Target
+type Target interface {
+	StartData(command <-chan string, ctx context.Context) error
+	WriteRows(ctx context.Context, object string, rows []integrity.Row) error
+	ReadRows(ctx context.Context, object string) ([]integrity.Row, error)
+}
+
+// Record is a write of a writer, its sequence number grows with each write attempt
+type Record struct {
+	Sequence  int64
+	WrittenAt time.Time
+}
+
+// Row returns the row the record is written as, the key is the zero padded sequence number
+// and the value the write time
+func (r Record) Row() integrity.Row {
+	return integrity.Row{
+		Key:   fmt.Sprintf(sequenceFormat, r.Sequence),
+		Value: r.WrittenAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// ParseRecord returns the record written as the row
+func ParseRecord(row integrity.Row) (Record, error) {
+	sequence, err := strconv.ParseInt(row.Key, 10, 64)
+	if err != nil {
+		return Record{}, fmt.Errorf("invalid record sequence [%s]: %v", row.Key, err)
+	}
+	writtenAt, err := time.Parse(time.RFC3339Nano, row.Value)
+	if err != nil {
+		return Record{}, fmt.Errorf("invalid record time [%s]: %v", row.Value, err)
+	}
+	return Record{Sequence: sequence, WrittenAt: writtenAt}, nil
+}
+
+// Writer writes a record to its target every interval and keeps the records the target acknowledged
+type Writer struct {
+	name     string
+	interval time.Duration
+
+	mu           sync.Mutex
+	target       Target
+	switchedAt   time.Time
+	sequence     int64
+	failed       int
+	acknowledged []Record
+}
+
+// NewWriter returns a writer of the records of name to the target, every interval or DefaultInterval if not set
+func NewWriter(name string, target Target, interval time.Duration) *Writer {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Writer{name: name, target: target, interval: interval}
+}
+
+// ObjectName returns the name of the table or directory the records are written to
+func (w *Writer) ObjectName() string {
+	return ObjectPrefix + invalidNameChars.ReplaceAllString(strings.ToLower(w.name), "_")
+}
+
+// Run starts the StartData pipeline of the target and writes the records alongside its data until DataStop
+// is received or the context is done. The commands are passed on to the pipeline, so the writes stop on
+// DataPause and resume on DataStart. When the writer is switched to another target, the pipeline of the
+// previous target is stopped and one is started on the new target. A failed record write is not an error,
+// it is the unavailability the writer measures, but on DataStop the error of the pipeline of the current
+// target is returned.
+func (w *Writer) Run(command <-chan string, ctx context.Context) error {
+	var status = DataStart
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	pipeline := startData(ctx, w.currentTarget(), status)
+	for {
+		select {
+		case cmd := <-command:
+			switch cmd {
+			case DataStop:
+				err := pipeline.stop()
+				log.InfoD("Continuity writer [%s] stopped, %d records acknowledged and %d writes failed", w.name, len(w.Acknowledged()), w.Failed())
+				return err
+			case DataPause:
+				status = DataPause
+			default:
+				status = DataStart
+			}
+			pipeline.send(status)
+		case <-ctx.Done():
+			go pipeline.stop()
+			return ctx.Err()
+		case <-ticker.C:
+			if target := w.currentTarget(); target != pipeline.target {
+				// The data of the previous target is measured from the records, its pipeline
+				// may fail on the unavailable target and must not delay the writes to the new one
+				go func(previous *dataPipeline) {
+					if err := previous.stop(); err != nil {
+						log.Infof("Continuity writer [%s] data pipeline of the previous target failed: %v", w.name, err)
+					}
+				}(pipeline)
+				pipeline = startData(ctx, target, status)
+			}
+			if status == DataStart {
+				w.write(ctx)
+			}
+		}
+	}
+}
+
+// Switch makes the writer write to the target from now on and returns the time of the switch
+func (w *Writer) Switch(target Target) time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.target = target
+	w.switchedAt = time.Now()
+	return w.switchedAt
+}
+
+// SwitchedAt returns the time of the last switch, the zero time if the writer was never switched
+func (w *Writer) SwitchedAt() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.switchedAt
+}
+
+// Acknowledged returns the records acknowledged by the targets, in write order
+func (w *Writer) Acknowledged() []Record {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]Record(nil), w.acknowledged...)
+}
+
+// Failed returns the number of writes that failed
+func (w *Writer) Failed() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.failed
+}
+
+// WaitForAcknowledgement waits until a record written after since is acknowledged and returns it
+func (w *Writer) WaitForAcknowledgement(ctx context.Context, since time.Time) (Record, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		if record, ok := firstAfter(w.Acknowledged(), since); ok {
+			return record, nil
+		}
+		select {
+		case <-ctx.Done():
+			return Record{}, fmt.Errorf("no record of [%s] was acknowledged after [%s]: %v", w.name, since.Format(time.RFC3339), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Measure reads the records present on the target and measures the RPO and RTO of the failover at failoverAt,
+// after which the writer was switched to the target
+func (w *Writer) Measure(ctx context.Context, target Target, failoverAt time.Time) (*Measurement, error) {
+	switchedAt := w.SwitchedAt()
+	if switchedAt.Before(failoverAt) {
+		return nil, fmt.Errorf("continuity writer [%s] was not switched after the failover at [%s]", w.name, failoverAt.Format(time.RFC3339))
+	}
+	rows, err := target.ReadRows(ctx, w.ObjectName())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the records of [%s]: %v", w.name, err)
+	}
+	var present []Record
+	for _, row := range rows {
+		record, err := ParseRecord(row)
+		if err != nil {
+			return nil, err
+		}
+		present = append(present, record)
+	}
+	return Compute(w.Acknowledged(), present, failoverAt, switchedAt)
+}
+
+func (w *Writer) currentTarget() Target {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.target
+}
+
+func (w *Writer) write(ctx context.Context) {
+	w.mu.Lock()
+	target := w.target
+	w.sequence++
+	record := Record{Sequence: w.sequence, WrittenAt: time.Now().UTC()}
+	w.mu.Unlock()
+
+	err := target.WriteRows(ctx, w.ObjectName(), []integrity.Row{record.Row()})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		w.failed++
+		log.Infof("Continuity writer [%s] failed to write record [%d]: %v", w.name, record.Sequence, err)
+		return
+	}
+	w.acknowledged = append(w.acknowledged, record)
+}
+
+// Measurement is the data loss and unavailability of an application across a failover
+type Measurement struct {
+	FailoverAt time.Time
+	// SwitchedAt is when the writer was switched to the failed over application
+	SwitchedAt time.Time
+	// LastAcknowledged is the last record acknowledged by the application before the switch
+	LastAcknowledged Record
+	// LastPresent is the last record written before the switch that is present after it
+	LastPresent Record
+	// LostRecords is the number of records acknowledged before the switch that are not present after it
+	LostRecords int
+	// RPO is the time between the last acknowledged and the last present record written before the switch
+	RPO time.Duration
+	// FirstAfter is the first record acknowledged by the failed over application
+	FirstAfter Record
+	// RTO is the time between the failover, or the last record acknowledged before the switch if the
+	// application was still written to after the failover, and the first record acknowledged after the switch
+	RTO time.Duration
+}
+
+// Compute measures the RPO and RTO of the failover at failoverAt from the records acknowledged by the
+// writer, which was switched to the failed over application at switchedAt, and the records present on it
+func Compute(acknowledged, present []Record, failoverAt, switchedAt time.Time) (*Measurement, error) {
+	m := &Measurement{FailoverAt: failoverAt, SwitchedAt: switchedAt}
+
+	isPresent := make(map[int64]bool)
+	found := false
+	for _, record := range present {
+		isPresent[record.Sequence] = true
+		if record.WrittenAt.After(switchedAt) {
+			continue
+		}
+		if !found || record.Sequence > m.LastPresent.Sequence {
+			m.LastPresent = record
+			found = true
+		}
+	}
+
+	var before []Record
+	for _, record := range acknowledged {
+		if !record.WrittenAt.After(switchedAt) {
+			before = append(before, record)
+		}
+	}
+	if len(before) == 0 {
+		return nil, fmt.Errorf("no record was acknowledged before the switch at [%s]", switchedAt.Format(time.RFC3339))
+	}
+	if !found {
+		return nil, fmt.Errorf("none of the %d records acknowledged before the switch at [%s] is present", len(before), switchedAt.Format(time.RFC3339))
+	}
+
+	m.LastAcknowledged = before[len(before)-1]
+	for _, record := range before {
+		if !isPresent[record.Sequence] {
+			m.LostRecords++
+		}
+	}
+	if m.LastAcknowledged.WrittenAt.After(m.LastPresent.WrittenAt) {
+		m.RPO = m.LastAcknowledged.WrittenAt.Sub(m.LastPresent.WrittenAt)
+	}
+
+	first, ok := firstAfter(acknowledged, switchedAt)
+	if !ok {
+		return nil, fmt.Errorf("no record was acknowledged after the switch at [%s]", switchedAt.Format(time.RFC3339))
+	}
+	m.FirstAfter = first
+	unavailableFrom := failoverAt
+	if m.LastAcknowledged.WrittenAt.After(unavailableFrom) {
+		unavailableFrom = m.LastAcknowledged.WrittenAt
+	}
+	m.RTO = first.WrittenAt.Sub(unavailableFrom)
+	return m, nil
+}
+
+// Stats returns the measurement as dashboard stats
+func (m *Measurement) Stats() map[string]string {
+	return map[string]string{
+		"FailoverTime":               m.FailoverAt.Format(time.RFC1123),
+		"SwitchTime":                 m.SwitchedAt.Format(time.RFC1123),
+		"RPOSeconds":                 strconv.FormatFloat(m.RPO.Seconds(), 'f', 3, 64),
+		"RTOSeconds":                 strconv.FormatFloat(m.RTO.Seconds(), 'f', 3, 64),
+		"LostRecords":                strconv.Itoa(m.LostRecords),
+		"LastAcknowledgedSequence":   strconv.FormatInt(m.LastAcknowledged.Sequence, 10),
+		"LastPresentSequence":        strconv.FormatInt(m.LastPresent.Sequence, 10),
+		"FirstSequenceAfterFailover": strconv.FormatInt(m.FirstAfter.Sequence, 10),
+	}
+}
+
+// firstAfter returns the first of the records, in write order, written after since
+func firstAfter(records []Record, since time.Time) (Record, bool) {
+	for _, record := range records {
+		if record.WrittenAt.After(since) {
+			return record, true
+		}
+	}
+	return Record{}, false
+}
+
+// dataPipeline is a StartData pipeline of a target
+type dataPipeline struct {
+	target  Target
+	command chan string
+	done    chan error
+}
+
+// startData starts the StartData pipeline of the target, paused unless status is DataStart
+func startData(ctx context.Context, target Target, status string) *dataPipeline {
+	p := &dataPipeline{target: target, command: make(chan string), done: make(chan error, 1)}
+	go func() {
+		p.done <- target.StartData(p.command, ctx)
+	}()
+	if status != DataStart {
+		p.send(status)
+	}
+	return p
+}
+
+// send passes the command on to the pipeline, unless the pipeline already returned
+func (p *dataPipeline) send(cmd string) {
+	select {
+	case p.command <- cmd:
+	case err := <-p.done:
+		p.done <- err
+	}
+}
+
+// stop stops the pipeline and returns its error
+func (p *dataPipeline) stop() error {
+	p.send(DataStop)
+	return <-p.done
+}
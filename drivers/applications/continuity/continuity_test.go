@@ -0,0 +1,167 @@
+package continuity
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTarget struct {
+	mu       sync.Mutex
+	down     bool
+	rows     map[string][]integrity.Row
+	commands []string
+}
+
+func (f *fakeTarget) StartData(command <-chan string, ctx context.Context) error {
+	for cmd := range command {
+		f.mu.Lock()
+		f.commands = append(f.commands, cmd)
+		down := f.down
+		f.mu.Unlock()
+		if cmd == DataStop {
+			if down {
+				return errors.New("connection refused")
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+func (f *fakeTarget) received() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.commands...)
+}
+
+func (f *fakeTarget) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *fakeTarget) WriteRows(ctx context.Context, object string, rows []integrity.Row) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return errors.New("connection refused")
+	}
+	if f.rows == nil {
+		f.rows = make(map[string][]integrity.Row)
+	}
+	f.rows[object] = append(f.rows[object], rows...)
+	return nil
+}
+
+func (f *fakeTarget) ReadRows(ctx context.Context, object string) ([]integrity.Row, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]integrity.Row(nil), f.rows[object]...), nil
+}
+
+func TestRecord(t *testing.T) {
+	record := Record{Sequence: 42, WrittenAt: time.Date(2024, 5, 1, 10, 0, 0, 123456789, time.UTC)}
+	row := record.Row()
+	require.Equal(t, integrity.Row{Key: "000000000042", Value: "2024-05-01T10:00:00.123456789Z"}, row)
+	parsed, err := ParseRecord(row)
+	require.NoError(t, err)
+	require.Equal(t, record, parsed)
+
+	_, err = ParseRecord(integrity.Row{Key: "abc", Value: row.Value})
+	require.Error(t, err)
+}
+
+func TestCompute(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+	failoverAt, switchedAt := at(10), at(20)
+	// 1 to 6 written to the source until it became unavailable after the failover, 7 failed,
+	// 8 and 9 written to the destination after the switch
+	acknowledged := []Record{{1, at(1)}, {2, at(3)}, {3, at(5)}, {4, at(7)}, {5, at(9)}, {6, at(11)}, {8, at(25)}, {9, at(27)}}
+	// the last migration before the failover copied 1 to 3
+	present := []Record{{3, at(5)}, {1, at(1)}, {2, at(3)}, {8, at(25)}, {9, at(27)}}
+
+	m, err := Compute(acknowledged, present, failoverAt, switchedAt)
+	require.NoError(t, err)
+	require.Equal(t, Record{6, at(11)}, m.LastAcknowledged)
+	require.Equal(t, Record{3, at(5)}, m.LastPresent)
+	require.Equal(t, 3, m.LostRecords)
+	require.Equal(t, 6*time.Second, m.RPO)
+	require.Equal(t, Record{8, at(25)}, m.FirstAfter)
+	require.Equal(t, 14*time.Second, m.RTO)
+	require.Equal(t, "6.000", m.Stats()["RPOSeconds"])
+	require.Equal(t, "14.000", m.Stats()["RTOSeconds"])
+
+	m, err = Compute(acknowledged, append(present, Record{4, at(7)}, Record{5, at(9)}, Record{6, at(11)}), failoverAt, switchedAt)
+	require.NoError(t, err)
+	require.Equal(t, 0, m.LostRecords)
+	require.Equal(t, time.Duration(0), m.RPO)
+
+	// the source was unavailable before the failover, so the application was unavailable from the failover
+	withoutLate := append(append([]Record(nil), acknowledged[:5]...), acknowledged[6:]...)
+	m, err = Compute(withoutLate, present, failoverAt, switchedAt)
+	require.NoError(t, err)
+	require.Equal(t, 15*time.Second, m.RTO)
+
+	_, err = Compute(acknowledged, present, at(0), at(0))
+	require.EqualError(t, err, "no record was acknowledged before the switch at [2024-05-01T10:00:00Z]")
+	_, err = Compute(acknowledged, present[3:], failoverAt, switchedAt)
+	require.EqualError(t, err, "none of the 6 records acknowledged before the switch at [2024-05-01T10:00:20Z] is present")
+	_, err = Compute(acknowledged[:6], present, failoverAt, switchedAt)
+	require.EqualError(t, err, "no record was acknowledged after the switch at [2024-05-01T10:00:20Z]")
+}
+
+func TestWriter(t *testing.T) {
+	source, destination := &fakeTarget{}, &fakeTarget{}
+	w := NewWriter("Postgres-1", source, 10*time.Millisecond)
+	require.Equal(t, "continuity_postgres_1", w.ObjectName())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	command := make(chan string)
+	done := make(chan error)
+	go func() { done <- w.Run(command, ctx) }()
+
+	// the source is written to after the failover until it becomes unavailable
+	failoverAt := time.Now()
+	_, err := w.WaitForAcknowledgement(ctx, failoverAt)
+	require.NoError(t, err)
+	_, err = w.Measure(ctx, destination, failoverAt)
+	require.EqualError(t, err, "continuity writer [Postgres-1] was not switched after the failover at ["+failoverAt.Format(time.RFC3339)+"]")
+
+	// the failover migrates what the source has, then the destination is unavailable for a while
+	source.setDown(true)
+	rows, err := source.ReadRows(ctx, w.ObjectName())
+	require.NoError(t, err)
+	require.NoError(t, destination.WriteRows(ctx, w.ObjectName(), rows))
+	time.Sleep(30 * time.Millisecond)
+	destination.setDown(true)
+	switchedAt := w.Switch(destination)
+	time.Sleep(50 * time.Millisecond)
+	destination.setDown(false)
+
+	first, err := w.WaitForAcknowledgement(ctx, switchedAt)
+	require.NoError(t, err)
+	m, err := w.Measure(ctx, destination, failoverAt)
+	require.NoError(t, err)
+	require.Equal(t, 0, m.LostRecords)
+	require.Equal(t, time.Duration(0), m.RPO)
+	require.Equal(t, first, m.FirstAfter)
+	require.GreaterOrEqual(t, m.RTO, 80*time.Millisecond)
+	require.Greater(t, w.Failed(), 0)
+
+	// the pipeline of the source fails on the unavailable source, only the one of the current target is returned
+	require.Eventually(t, func() bool { return len(source.received()) == 1 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{DataStop}, source.received())
+	command <- DataPause
+	command <- DataStop
+	require.NoError(t, <-done)
+	require.Equal(t, []string{DataPause, DataStop}, destination.received())
+}
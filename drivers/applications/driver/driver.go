@@ -2,10 +2,16 @@ package driver
 
 import (
 	"context"
+
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	"github.com/portworx/torpedo/drivers/node"
 
 	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	. "github.com/portworx/torpedo/drivers/applications/cassandra"
+	. "github.com/portworx/torpedo/drivers/applications/elasticsearch"
+	. "github.com/portworx/torpedo/drivers/applications/kafka"
 	. "github.com/portworx/torpedo/drivers/applications/kubevirt"
+	. "github.com/portworx/torpedo/drivers/applications/mongodb"
 	. "github.com/portworx/torpedo/drivers/applications/mysql"
 	. "github.com/portworx/torpedo/drivers/applications/postgres"
 	. "github.com/portworx/torpedo/drivers/utilities"
@@ -47,6 +53,18 @@ type ApplicationDriver interface {
 
 	// WaitForVMToBoot waits for kubevirt vm to boot
 	WaitForVMToBoot() error
+
+	// WriteWorkload writes the seeded rows or files of the integrity workload to the application
+	WriteWorkload(ctx context.Context, workload integrity.Workload) error
+
+	// Checksum returns the logical checksum of the integrity workloads written to the application
+	Checksum(ctx context.Context) (*integrity.Snapshot, error)
+
+	// WriteRows writes the rows to the table, or the equivalent object of the application, creating it if needed
+	WriteRows(ctx context.Context, object string, rows []integrity.Row) error
+
+	// ReadRows returns the rows of the table, or the equivalent object of the application
+	ReadRows(ctx context.Context, object string) ([]integrity.Row, error)
 }
 
 // GetApplicationDriver returns struct of appType provided as input
@@ -94,6 +112,55 @@ func GetApplicationDriver(appType string, hostname string, user string,
 			NodeDriver: nodeDriver,
 			Namespace:  namespace,
 		}, nil
+	case MongoDB:
+		return &MongoDBConfig{
+			Hostname: hostname,
+			User:     user,
+			Password: password,
+			Port:     port,
+			DBName:   dbname,
+			DataCommands: map[string]map[string][]string{
+				"default": GenerateRandomMongoCommands(20),
+			},
+			NodePort:  nodePort,
+			Namespace: namespace,
+		}, nil
+	case Cassandra:
+		app := &CassandraConfig{
+			Hostname:  hostname,
+			User:      user,
+			Password:  password,
+			Port:      port,
+			Keyspace:  dbname,
+			Namespace: namespace,
+		}
+		app.DataCommands = map[string]map[string][]string{
+			"default": app.GetRandomDataCommands(20),
+		}
+		return app, nil
+	case Kafka:
+		return &KafkaConfig{
+			Hostname: hostname,
+			User:     user,
+			Password: password,
+			Port:     port,
+			DataCommands: map[string]map[string][]string{
+				"default": GenerateRandomKafkaCommands(20),
+			},
+			Namespace: namespace,
+		}, nil
+	case Elasticsearch:
+		return &ElasticsearchConfig{
+			Hostname: hostname,
+			User:     user,
+			Password: password,
+			Port:     port,
+			DataCommands: map[string]map[string][]string{
+				"default": GenerateRandomElasticsearchCommands(20),
+			},
+			NodePort:  nodePort,
+			Namespace: namespace,
+		}, nil
 	default:
 		return &PostgresConfig{
 			Hostname: hostname,
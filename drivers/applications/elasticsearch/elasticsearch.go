@@ -0,0 +1,342 @@
+package applications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
+	. "github.com/portworx/torpedo/drivers/utilities"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+const (
+	requestTimeout  = 60 * time.Second
+	bulkBatchSize   = 1000
+	scrollPageSize  = 1000
+	scrollKeepAlive = "1m"
+)
+
+// ElasticsearchConfig writes and reads documents with the REST API of elasticsearch.
+// The data commands are requests in the form "<method> <path> [<body>]".
+type ElasticsearchConfig struct {
+	Hostname     string
+	User         string
+	Password     string
+	Port         int
+	NodePort     int
+	Namespace    string
+	DataCommands map[string]map[string][]string
+}
+
+// searchResponse is the part of a search or scroll response holding the documents
+type searchResponse struct {
+	ScrollID string `json:"_scroll_id"`
+	Hits     struct {
+		Hits []struct {
+			ID     string `json:"_id"`
+			Source struct {
+				Value string `json:"v"`
+			} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// DefaultPort returns default port for elasticsearch
+func (app *ElasticsearchConfig) DefaultPort() int { return 9200 }
+
+// ExecuteCommand sends the requests, each in the form "<method> <path> [<body>]", and returns their responses
+func (app *ElasticsearchConfig) ExecuteCommand(commands []string, ctx context.Context) ([]string, error) {
+
+	var responses []string
+	for _, eachCommand := range commands {
+		fields := strings.SplitN(strings.TrimSpace(eachCommand), " ", 3)
+		if len(fields) < 2 {
+			return responses, fmt.Errorf("invalid request [%s], expected <method> <path> [<body>]", eachCommand)
+		}
+		var body string
+		if len(fields) == 3 {
+			body = fields[2]
+		}
+		response, err := app.request(ctx, fields[0], fields[1], "application/json", body)
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, string(response))
+	}
+	return responses, nil
+}
+
+// InsertBackupData indexes the documents generated initially by utilities or documents passed
+func (app *ElasticsearchConfig) InsertBackupData(ctx context.Context, identifier string, commands []string) error {
+
+	var err error
+	log.InfoD("Inserting data")
+	if len(commands) == 0 {
+		log.Infof("Inserting below data : %s", strings.Join(app.DataCommands[identifier]["insert"], "\n"))
+		_, err = app.ExecuteCommand(app.DataCommands[identifier]["insert"], ctx)
+	} else {
+		log.Infof("Inserting below data : %s", strings.Join(commands, "\n"))
+		_, err = app.ExecuteCommand(commands, ctx)
+	}
+
+	return err
+}
+
+// Return data inserted before backup
+func (app *ElasticsearchConfig) GetBackupData(identifier string) []string {
+	if _, ok := app.DataCommands[identifier]; ok {
+		return app.DataCommands[identifier]["select"]
+	} else {
+		log.InfoD("%s not found in app data command", identifier)
+		log.Infof("All current data commands - %+v", app.DataCommands)
+		return nil
+	}
+}
+
+// CheckDataPresent checks if the get requests find their document
+func (app *ElasticsearchConfig) CheckDataPresent(selectQueries []string, ctx context.Context) error {
+
+	log.InfoD("Running Get Requests")
+
+	var queryNotFoundList []string
+	for _, eachQuery := range selectQueries {
+		_, err := app.ExecuteCommand([]string{eachQuery}, ctx)
+		if err != nil {
+			log.InfoD("Get request failed - [%s] Error - [%s]", eachQuery, err.Error())
+			queryNotFoundList = append(queryNotFoundList, eachQuery)
+		}
+	}
+
+	if len(queryNotFoundList) != 0 {
+		errorMessage := strings.Join(queryNotFoundList, "\n")
+		return fmt.Errorf("Below documents not found in the index:\n %s", errorMessage)
+	}
+	return nil
+}
+
+// StartData - Go routine to run parallal with app to keep injecting data every 2 seconds
+func (app *ElasticsearchConfig) StartData(command <-chan string, ctx context.Context) error {
+	var status = DataStart
+	var allSelectCommands []string
+	var allErrors []string
+	var indexName = "index_" + RandomString(4)
+
+	for {
+		select {
+		case cmd := <-command:
+			switch cmd {
+			case DataStop:
+				if len(allErrors) != 0 {
+					return fmt.Errorf(strings.Join(allErrors, "\n"))
+				}
+				err := app.CheckDataPresent(allSelectCommands, ctx)
+				return err
+
+			case DataPause:
+				status = DataPause
+			default:
+				status = DataStart
+			}
+		default:
+			if status == DataStart {
+				commandPair := GenerateElasticsearchCommandPair(indexName)
+				_, err := app.ExecuteCommand(commandPair["insert"], ctx)
+				if err != nil {
+					allErrors = append(allErrors, fmt.Sprintf("Continuity Pipeline Error - [%s] at [%s]", err.Error(), time.Now().Format("2006-01-02 15:04:05")))
+				}
+				allSelectCommands = append(allSelectCommands, commandPair["select"]...)
+				time.Sleep(2 * time.Second)
+			}
+		}
+	}
+}
+
+// Update the existing data commands
+func (app *ElasticsearchConfig) UpdateDataCommands(count int, identifier string) {
+	app.DataCommands[identifier] = GenerateRandomElasticsearchCommands(count)
+	log.InfoD("Data commands updated")
+}
+
+// Add data commands to the existing ones
+func (app *ElasticsearchConfig) AddDataCommands(identifier string, commands map[string][]string) {
+	app.DataCommands[identifier] = commands
+	log.InfoD("Data commands added")
+}
+
+// Generate and return random data commands
+func (app *ElasticsearchConfig) GetRandomDataCommands(count int) map[string][]string {
+	return GenerateRandomElasticsearchCommands(count)
+}
+
+// Get the application type
+func (app *ElasticsearchConfig) GetApplicationType() string {
+	return Elasticsearch
+}
+
+// Get Namespace of the app
+func (app *ElasticsearchConfig) GetNamespace() string {
+	return app.Namespace
+}
+
+// WaitForVMToBoot waits for VM to boot
+func (app *ElasticsearchConfig) WaitForVMToBoot() error {
+	log.Warnf("Not implemented for Elasticsearch")
+	return nil
+}
+
+// WriteWorkload indexes the rows of the integrity workload as documents of its index
+func (app *ElasticsearchConfig) WriteWorkload(ctx context.Context, workload integrity.Workload) error {
+	log.InfoD("Writing integrity workload [%s] with %d rows to [%s]", workload.Name, workload.Count, app.Hostname)
+	return app.WriteRows(ctx, workload.ObjectName(), workload.Rows())
+}
+
+// Checksum returns the logical checksum of the integrity workload indices
+func (app *ElasticsearchConfig) Checksum(ctx context.Context) (*integrity.Snapshot, error) {
+	response, err := app.request(ctx, http.MethodGet, fmt.Sprintf("/_cat/indices/%s*?h=index&format=json", integrity.ObjectPrefix), "", "")
+	if err != nil {
+		return nil, err
+	}
+	var indices []struct {
+		Index string `json:"index"`
+	}
+	if err = json.Unmarshal(response, &indices); err != nil {
+		return nil, err
+	}
+
+	snapshot := integrity.NewSnapshot(Elasticsearch, app.Namespace)
+	for _, index := range indices {
+		rows, err := app.ReadRows(ctx, index.Index)
+		if err != nil {
+			return nil, err
+		}
+		digest := &integrity.Digest{}
+		for _, row := range rows {
+			digest.Add(row.Key, row.Value)
+		}
+		snapshot.Objects[index.Index] = digest.Checksum()
+	}
+	return snapshot, nil
+}
+
+// WriteRows indexes the rows as documents of the index, with the row key as id, and refreshes the index
+func (app *ElasticsearchConfig) WriteRows(ctx context.Context, object string, rows []integrity.Row) error {
+	for start := 0; start < len(rows); start += bulkBatchSize {
+		end := start + bulkBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		var bulk strings.Builder
+		for _, row := range rows[start:end] {
+			action, _ := json.Marshal(map[string]map[string]string{"index": {"_index": object, "_id": row.Key}})
+			document, _ := json.Marshal(map[string]string{"v": row.Value})
+			bulk.Write(action)
+			bulk.WriteByte('\n')
+			bulk.Write(document)
+			bulk.WriteByte('\n')
+		}
+		response, err := app.request(ctx, http.MethodPost, "/_bulk?refresh=true", "application/x-ndjson", bulk.String())
+		if err != nil {
+			return err
+		}
+		var result struct {
+			Errors bool `json:"errors"`
+		}
+		if err = json.Unmarshal(response, &result); err != nil {
+			return err
+		}
+		if result.Errors {
+			return fmt.Errorf("failed to index the documents of [%s]: %s", object, string(response))
+		}
+	}
+	return nil
+}
+
+// ReadRows returns the documents of the index as rows
+func (app *ElasticsearchConfig) ReadRows(ctx context.Context, object string) ([]integrity.Row, error) {
+	var rows []integrity.Row
+	err := app.scroll(ctx, object, func(page *searchResponse) {
+		for _, hit := range page.Hits.Hits {
+			rows = append(rows, integrity.Row{Key: hit.ID, Value: hit.Source.Value})
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read index [%s]: %v", object, err)
+	}
+	return rows, nil
+}
+
+// scroll calls visit on each page of the documents of the index
+func (app *ElasticsearchConfig) scroll(ctx context.Context, index string, visit func(page *searchResponse)) error {
+	path := fmt.Sprintf("/%s/_search?scroll=%s", index, scrollKeepAlive)
+	body := fmt.Sprintf(`{"size": %d, "query": {"match_all": {}}}`, scrollPageSize)
+	for {
+		response, err := app.request(ctx, http.MethodPost, path, "application/json", body)
+		if err != nil {
+			return err
+		}
+		page := &searchResponse{}
+		if err = json.Unmarshal(response, page); err != nil {
+			return err
+		}
+		if len(page.Hits.Hits) == 0 {
+			_, err = app.request(ctx, http.MethodDelete, "/_search/scroll", "application/json", fmt.Sprintf(`{"scroll_id": "%s"}`, page.ScrollID))
+			if err != nil {
+				log.Warnf("Failed to clear scroll of index [%s]: %v", index, err)
+			}
+			return nil
+		}
+		visit(page)
+		path = "/_search/scroll"
+		body = fmt.Sprintf(`{"scroll": "%s", "scroll_id": "%s"}`, scrollKeepAlive, page.ScrollID)
+	}
+}
+
+// request sends a request to elasticsearch and returns the response body, a non 2xx status is an error
+func (app *ElasticsearchConfig) request(ctx context.Context, method, path, contentType, body string) ([]byte, error) {
+	if app.Port == 0 {
+		app.Port = app.DefaultPort()
+	}
+
+	var url string
+	if app.NodePort != 0 {
+		// Connect with NodePort Service
+		url = fmt.Sprintf("http://%s:%d%s", app.Hostname, app.NodePort, path)
+	} else {
+		// Connect with Cluster Service
+		url = fmt.Sprintf("http://%s:%d%s", app.Hostname, app.Port, path)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBufferString(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if app.User != "" {
+		req.SetBasicAuth(app.User, app.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to elasticsearch: %s, Url - [%s]", err, url)
+	}
+	defer resp.Body.Close()
+	response, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return response, fmt.Errorf("request [%s %s] failed with status [%d]: %s", method, path, resp.StatusCode, string(response))
+	}
+	return response, nil
+}
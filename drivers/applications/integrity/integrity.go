@@ -0,0 +1,347 @@
+// Package integrity writes deterministic workloads to applications and takes
+// logical checksums of them, so that the data of an application can be
+// compared across backup/restore, cloudsnap restore and DR failover.
+package integrity
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// ObjectPrefix is the prefix of the tables and directories the workloads are written to
+	ObjectPrefix = "integrity_"
+	// FileRoot is the directory under the user home the file workloads are written to
+	FileRoot = "integrity"
+
+	defaultRowSize  = 32
+	defaultFileSize = 1024
+	insertBatchSize = 100
+	shellReadMarker = "file:"
+	alphanumerics   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+var (
+	invalidNameChars = regexp.MustCompile(`[^a-z0-9_]`)
+	md5sumLine       = regexp.MustCompile(`^([0-9a-f]{32})\s+\*?\./([^/]+)/(.+)$`)
+)
+
+// Workload is a seeded set of rows or files, the same workload always generates the same data
+type Workload struct {
+	// Name of the workload, the table or directory it is written to is derived from it
+	Name string
+	// Seed of the generated data
+	Seed int64
+	// Count is the number of rows or files
+	Count int
+	// Size is the size in bytes of each row value or file, 32 for rows and 1024 for files if not set
+	Size int
+}
+
+// Row is a row of a table workload
+type Row struct {
+	Key   string
+	Value string
+}
+
+// File is a file of a file workload
+type File struct {
+	Name    string
+	Content string
+}
+
+// ObjectName returns the name of the table or directory the workload is written to
+func (w Workload) ObjectName() string {
+	return ObjectPrefix + invalidNameChars.ReplaceAllString(strings.ToLower(w.Name), "_")
+}
+
+// Rows returns the rows of the workload
+func (w Workload) Rows() []Row {
+	r := rand.New(rand.NewSource(w.Seed))
+	rows := make([]Row, w.Count)
+	for i := range rows {
+		rows[i] = Row{
+			Key:   fmt.Sprintf("%s-%06d", w.Name, i),
+			Value: randomString(r, w.size(defaultRowSize)),
+		}
+	}
+	return rows
+}
+
+// Files returns the files of the workload
+func (w Workload) Files() []File {
+	r := rand.New(rand.NewSource(w.Seed))
+	files := make([]File, w.Count)
+	for i := range files {
+		files[i] = File{
+			Name:    fmt.Sprintf("%06d.dat", i),
+			Content: randomString(r, w.size(defaultFileSize)),
+		}
+	}
+	return files
+}
+
+// RowsChecksum returns the checksum of the workload written as a table
+func (w Workload) RowsChecksum() Checksum {
+	d := &Digest{}
+	for _, row := range w.Rows() {
+		d.Add(row.Key, row.Value)
+	}
+	return d.Checksum()
+}
+
+// FilesChecksum returns the checksum of the workload written as files
+func (w Workload) FilesChecksum() Checksum {
+	d := &Digest{}
+	for _, file := range w.Files() {
+		sum := md5.Sum([]byte(file.Content))
+		d.Add(file.Name, hex.EncodeToString(sum[:]))
+	}
+	return d.Checksum()
+}
+
+// SQLStatements returns the statements that create the table of the workload and insert its rows
+func (w Workload) SQLStatements() []string {
+	return SQLInsertStatements(w.ObjectName(), w.Rows())
+}
+
+// SQLInsertStatements returns the statements that create the table if it does not exist and insert the rows
+func SQLInsertStatements(table string, rows []Row) []string {
+	statements := []string{fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (k VARCHAR(128) NOT NULL PRIMARY KEY, v TEXT NOT NULL)", table)}
+	for start := 0; start < len(rows); start += insertBatchSize {
+		end := start + insertBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		var values []string
+		for _, row := range rows[start:end] {
+			values = append(values, fmt.Sprintf("('%s', '%s')", row.Key, row.Value))
+		}
+		statements = append(statements, fmt.Sprintf("INSERT INTO %s (k, v) VALUES %s", table, strings.Join(values, ", ")))
+	}
+	return statements
+}
+
+// SQLSelectStatement returns the statement that reads the rows of a workload table
+func SQLSelectStatement(table string) string {
+	return fmt.Sprintf("SELECT k, v FROM %s", table)
+}
+
+// CQLStatements returns the statements that create the table of the workload in the keyspace and insert its rows
+func (w Workload) CQLStatements(keyspace string) []string {
+	return CQLInsertStatements(fmt.Sprintf("%s.%s", keyspace, w.ObjectName()), w.Rows())
+}
+
+// CQLInsertStatements returns the statements that create the table if it does not exist and insert the rows,
+// in unlogged batches as CQL has no multi row insert
+func CQLInsertStatements(table string, rows []Row) []string {
+	statements := []string{fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (k text PRIMARY KEY, v text)", table)}
+	for start := 0; start < len(rows); start += insertBatchSize {
+		end := start + insertBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		var inserts []string
+		for _, row := range rows[start:end] {
+			inserts = append(inserts, fmt.Sprintf("INSERT INTO %s (k, v) VALUES ('%s', '%s');", table, row.Key, row.Value))
+		}
+		statements = append(statements, fmt.Sprintf("BEGIN UNLOGGED BATCH %s APPLY BATCH", strings.Join(inserts, " ")))
+	}
+	return statements
+}
+
+// ShellCommands returns the shell commands that write the files of the workload under the user home.
+// The files are written in chunks of about maxLength bytes per command.
+func (w Workload) ShellCommands(maxLength int) []string {
+	return ShellWriteCommands(w.ObjectName(), w.Files(), maxLength)
+}
+
+// ShellWriteCommands returns the shell commands that write the files to the object directory under the
+// user home, in chunks of about maxLength bytes per command
+func ShellWriteCommands(object string, files []File, maxLength int) []string {
+	dir := ObjectDir(object)
+	var commands []string
+	current := []string{fmt.Sprintf("mkdir -p %s", dir)}
+	length := len(current[0])
+	for _, file := range files {
+		write := fmt.Sprintf("printf '%%s' '%s' > %s/%s", file.Content, dir, file.Name)
+		if length+len(write) > maxLength {
+			commands = append(commands, strings.Join(current, " && "))
+			current, length = nil, 0
+		}
+		current = append(current, write)
+		length += len(write)
+	}
+	current = append(current, "sync")
+	return append(commands, strings.Join(current, " && "))
+}
+
+// ShellReadCommand returns the shell command that prints the name and the content of each file of the
+// object directory, to be parsed by ParseShellRead
+func ShellReadCommand(object string) string {
+	return fmt.Sprintf(`cd %s 2>/dev/null && for f in *; do [ -f "$f" ] && printf '%s %%s %%s\n' "$f" "$(cat "$f")"; done || true`, ObjectDir(object), shellReadMarker)
+}
+
+// ParseShellRead returns the files printed by ShellReadCommand
+func ParseShellRead(output string) []File {
+	var files []File
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == shellReadMarker {
+			files = append(files, File{Name: fields[1], Content: fields[2]})
+		}
+	}
+	return files
+}
+
+// ObjectDir returns the directory under the user home the files of the object are written to
+func ObjectDir(object string) string {
+	return fmt.Sprintf("~/%s/%s", FileRoot, object)
+}
+
+// ManifestCommand is the shell command that lists the md5sum of the files of all the file workloads
+var ManifestCommand = fmt.Sprintf("cd ~/%s 2>/dev/null && find . -type f -exec md5sum {} + || true", FileRoot)
+
+// Checksum is the logical checksum of a table or directory
+type Checksum struct {
+	// Count is the number of rows or files, 0 if the checksum is computed by a
+	// workload which does not report it, as the PDS workloads
+	Count int64 `json:"count"`
+	// Hash is the hash of the rows or files, independent of their order
+	Hash string `json:"hash"`
+}
+
+// Digest computes the checksum of rows added in any order
+type Digest struct {
+	entries []string
+}
+
+// Add adds a row with the given fields
+func (d *Digest) Add(fields ...string) {
+	d.entries = append(d.entries, strings.Join(fields, "\x00"))
+}
+
+// Checksum returns the checksum of the added rows
+func (d *Digest) Checksum() Checksum {
+	sort.Strings(d.entries)
+	h := sha256.New()
+	for _, entry := range d.entries {
+		h.Write([]byte(entry))
+		h.Write([]byte{'\n'})
+	}
+	return Checksum{
+		Count: int64(len(d.entries)),
+		Hash:  hex.EncodeToString(h.Sum(nil)),
+	}
+}
+
+// Snapshot is the logical checksum of the workloads written to an application
+type Snapshot struct {
+	AppType   string `json:"appType"`
+	Namespace string `json:"namespace"`
+	// Objects are the checksums of the tables or directories, keyed by their name
+	Objects map[string]Checksum `json:"objects"`
+}
+
+// NewSnapshot returns an empty snapshot of the application
+func NewSnapshot(appType, namespace string) *Snapshot {
+	return &Snapshot{
+		AppType:   appType,
+		Namespace: namespace,
+		Objects:   make(map[string]Checksum),
+	}
+}
+
+// ParseManifest returns the snapshot of the file workloads from the output of ManifestCommand
+func ParseManifest(appType, namespace, output string) *Snapshot {
+	digests := make(map[string]*Digest)
+	for _, line := range strings.Split(output, "\n") {
+		match := md5sumLine.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil || !strings.HasPrefix(match[2], ObjectPrefix) {
+			continue
+		}
+		if _, ok := digests[match[2]]; !ok {
+			digests[match[2]] = &Digest{}
+		}
+		digests[match[2]].Add(match[3], match[1])
+	}
+	snapshot := NewSnapshot(appType, namespace)
+	for object, d := range digests {
+		snapshot.Objects[object] = d.Checksum()
+	}
+	return snapshot
+}
+
+// MismatchError is the difference between the expected and the actual snapshot of an application
+type MismatchError struct {
+	// Missing are the objects of the expected snapshot that are not in the actual one
+	Missing []string
+	// Unexpected are the objects of the actual snapshot that are not in the expected one
+	Unexpected []string
+	// Changed are the objects whose checksum differs, with their expected and actual counts
+	Changed []string
+}
+
+func (e *MismatchError) Error() string {
+	var diffs []string
+	if len(e.Missing) > 0 {
+		diffs = append(diffs, fmt.Sprintf("missing %v", e.Missing))
+	}
+	if len(e.Unexpected) > 0 {
+		diffs = append(diffs, fmt.Sprintf("unexpected %v", e.Unexpected))
+	}
+	if len(e.Changed) > 0 {
+		diffs = append(diffs, fmt.Sprintf("changed %v", e.Changed))
+	}
+	return fmt.Sprintf("data integrity mismatch: %s", strings.Join(diffs, ", "))
+}
+
+// Compare returns a MismatchError if the snapshot differs from the expected one
+func (s *Snapshot) Compare(expected *Snapshot) error {
+	e := &MismatchError{}
+	for object, want := range expected.Objects {
+		got, ok := s.Objects[object]
+		if !ok {
+			e.Missing = append(e.Missing, object)
+		} else if got != want {
+			if got.Count != want.Count {
+				e.Changed = append(e.Changed, fmt.Sprintf("%s (count %d -> %d)", object, want.Count, got.Count))
+			} else {
+				e.Changed = append(e.Changed, fmt.Sprintf("%s (hash %s -> %s)", object, want.Hash, got.Hash))
+			}
+		}
+	}
+	for object := range s.Objects {
+		if _, ok := expected.Objects[object]; !ok {
+			e.Unexpected = append(e.Unexpected, object)
+		}
+	}
+	if len(e.Missing)+len(e.Unexpected)+len(e.Changed) == 0 {
+		return nil
+	}
+	sort.Strings(e.Missing)
+	sort.Strings(e.Unexpected)
+	sort.Strings(e.Changed)
+	return e
+}
+
+func (w Workload) size(defaultSize int) int {
+	if w.Size > 0 {
+		return w.Size
+	}
+	return defaultSize
+}
+
+func randomString(r *rand.Rand, length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = alphanumerics[r.Intn(len(alphanumerics))]
+	}
+	return string(b)
+}
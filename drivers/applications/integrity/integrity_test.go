@@ -0,0 +1,91 @@
+package integrity
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkload(t *testing.T) {
+	w := Workload{Name: "Before-Backup", Seed: 42, Count: 250}
+	require.Equal(t, "integrity_before_backup", w.ObjectName())
+	require.Equal(t, w.Rows(), w.Rows())
+	require.NotEqual(t, w.Rows(), Workload{Name: "Before-Backup", Seed: 43, Count: 250}.Rows())
+	require.Len(t, w.Rows()[0].Value, 32)
+
+	statements := w.SQLStatements()
+	require.Len(t, statements, 4)
+	require.True(t, strings.HasPrefix(statements[0], "CREATE TABLE IF NOT EXISTS integrity_before_backup "))
+	require.Equal(t, 50, strings.Count(statements[3], "('Before-Backup-"))
+
+	statements = w.CQLStatements("torpedo")
+	require.Len(t, statements, 4)
+	require.Equal(t, "CREATE TABLE IF NOT EXISTS torpedo.integrity_before_backup (k text PRIMARY KEY, v text)", statements[0])
+	require.True(t, strings.HasPrefix(statements[1], "BEGIN UNLOGGED BATCH INSERT INTO torpedo.integrity_before_backup (k, v) VALUES ('Before-Backup-000000', "))
+	require.True(t, strings.HasSuffix(statements[3], "; APPLY BATCH"))
+	require.Equal(t, 50, strings.Count(statements[3], "INSERT INTO"))
+
+	files := Workload{Name: "vm", Seed: 1, Count: 10, Size: 100}
+	commands := files.ShellCommands(500)
+	require.Len(t, commands, 4)
+	require.True(t, strings.HasPrefix(commands[0], "mkdir -p ~/integrity/integrity_vm && printf '%s' '"))
+	require.True(t, strings.HasSuffix(commands[3], " && sync"))
+	require.Equal(t, 10, strings.Count(strings.Join(commands, "\n"), "printf"))
+
+	require.Equal(t, `cd ~/integrity/continuity_vm 2>/dev/null && for f in *; do [ -f "$f" ] && printf 'file: %s %s\n' "$f" "$(cat "$f")"; done || true`,
+		ShellReadCommand("continuity_vm"))
+	output := "Warning: Permanently added '10.0.0.1' (ECDSA) to the list of known hosts.\nfile: 000001 a\nfile: 000002 b\n"
+	require.Equal(t, []File{{Name: "000001", Content: "a"}, {Name: "000002", Content: "b"}}, ParseShellRead(output))
+}
+
+func TestDigestAndManifest(t *testing.T) {
+	w := Workload{Name: "vm", Seed: 7, Count: 3}
+	rows := &Digest{}
+	for i := len(w.Rows()) - 1; i >= 0; i-- {
+		rows.Add(w.Rows()[i].Key, w.Rows()[i].Value)
+	}
+	require.Equal(t, w.RowsChecksum(), rows.Checksum())
+	require.Equal(t, int64(3), rows.Checksum().Count)
+
+	var output []string
+	output = append(output, "Warning: Permanently added '10.0.0.1' (ECDSA) to the list of known hosts.")
+	for _, file := range w.Files() {
+		sum := md5.Sum([]byte(file.Content))
+		output = append(output, fmt.Sprintf("%s  ./%s/%s", hex.EncodeToString(sum[:]), w.ObjectName(), file.Name))
+	}
+	output = append(output, "d41d8cd98f00b204e9800998ecf8427e  ./other/file")
+	snapshot := ParseManifest("kubevirt", "ns", strings.Join(output, "\n"))
+	require.Equal(t, map[string]Checksum{w.ObjectName(): w.FilesChecksum()}, snapshot.Objects)
+}
+
+func TestSnapshotCompare(t *testing.T) {
+	before := Workload{Name: "before", Seed: 1, Count: 10}
+	after := Workload{Name: "after", Seed: 2, Count: 10}
+	expected := NewSnapshot("postgres", "ns")
+	expected.Objects[before.ObjectName()] = before.RowsChecksum()
+
+	actual := NewSnapshot("postgres", "ns-restored")
+	actual.Objects[before.ObjectName()] = before.RowsChecksum()
+	require.NoError(t, actual.Compare(expected))
+
+	actual.Objects[after.ObjectName()] = after.RowsChecksum()
+	err := actual.Compare(expected)
+	require.EqualError(t, err, "data integrity mismatch: unexpected [integrity_after]")
+
+	delete(actual.Objects, after.ObjectName())
+	actual.Objects[before.ObjectName()] = Workload{Name: "before", Seed: 1, Count: 9}.RowsChecksum()
+	err = actual.Compare(expected)
+	require.EqualError(t, err, "data integrity mismatch: changed [integrity_before (count 10 -> 9)]")
+
+	// checksums without count, as the ones of the PDS workloads, differ by their hash
+	expected.Objects[before.ObjectName()] = Checksum{Hash: "a"}
+	actual.Objects[before.ObjectName()] = Checksum{Hash: "b"}
+	err = actual.Compare(expected)
+	require.EqualError(t, err, "data integrity mismatch: changed [integrity_before (hash a -> b)]")
+
+	require.EqualError(t, NewSnapshot("postgres", "ns").Compare(expected), "data integrity mismatch: missing [integrity_before]")
+}
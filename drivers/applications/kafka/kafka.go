@@ -0,0 +1,303 @@
+package applications
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
+	. "github.com/portworx/torpedo/drivers/utilities"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+const (
+	// recordSeparator separates the key and the value of a record
+	recordSeparator = ":"
+	// consumeTimeout is the time the consumer waits for a new record before it stops reading a topic
+	consumeTimeout = 10 * time.Second
+)
+
+// KafkaConfig writes and reads records with the kafka console scripts of a broker pod.
+// The data commands are records in the form "<topic> <key>:<value>".
+type KafkaConfig struct {
+	Hostname     string
+	User         string
+	Password     string
+	Port         int
+	Namespace    string
+	DataCommands map[string]map[string][]string
+}
+
+// DefaultPort returns default port for kafka
+func (app *KafkaConfig) DefaultPort() int { return 9092 }
+
+// ExecuteCommand produces the records, each in the form "<topic> <key>:<value>", to their topics
+func (app *KafkaConfig) ExecuteCommand(commands []string, ctx context.Context) ([]string, error) {
+
+	var dummy []string
+	topics, records, err := groupRecordsByTopic(commands)
+	if err != nil {
+		return dummy, err
+	}
+
+	for _, topic := range topics {
+		if err = app.produce(topic, records[topic]); err != nil {
+			return dummy, err
+		}
+	}
+	return dummy, nil
+}
+
+// InsertBackupData produces the records generated initially by utilities or records passed
+func (app *KafkaConfig) InsertBackupData(ctx context.Context, identifier string, commands []string) error {
+
+	var err error
+	log.InfoD("Inserting data")
+	if len(commands) == 0 {
+		log.Infof("Inserting below data : %s", strings.Join(app.DataCommands[identifier]["insert"], "\n"))
+		_, err = app.ExecuteCommand(app.DataCommands[identifier]["insert"], ctx)
+	} else {
+		log.Infof("Inserting below data : %s", strings.Join(commands, "\n"))
+		_, err = app.ExecuteCommand(commands, ctx)
+	}
+
+	return err
+}
+
+// Return data inserted before backup
+func (app *KafkaConfig) GetBackupData(identifier string) []string {
+	if _, ok := app.DataCommands[identifier]; ok {
+		return app.DataCommands[identifier]["select"]
+	} else {
+		log.InfoD("%s not found in app data command", identifier)
+		log.Infof("All current data commands - %+v", app.DataCommands)
+		return nil
+	}
+}
+
+// CheckDataPresent checks if the records, each in the form "<topic> <key>:<value>", are in their topics
+func (app *KafkaConfig) CheckDataPresent(selectQueries []string, ctx context.Context) error {
+
+	log.InfoD("Consuming topics")
+
+	topics, records, err := groupRecordsByTopic(selectQueries)
+	if err != nil {
+		return err
+	}
+
+	var queryNotFoundList []string
+	for _, topic := range topics {
+		consumed, err := app.consume(topic)
+		if err != nil {
+			log.InfoD("Consuming topic failed - [%s] Error - [%s]", topic, err.Error())
+		}
+		present := make(map[string]bool)
+		for _, record := range consumed {
+			present[record] = true
+		}
+		for _, record := range records[topic] {
+			if !present[record] {
+				queryNotFoundList = append(queryNotFoundList, fmt.Sprintf("%s %s", topic, record))
+			}
+		}
+	}
+
+	if len(queryNotFoundList) != 0 {
+		errorMessage := strings.Join(queryNotFoundList, "\n")
+		return fmt.Errorf("Below records not found in the topics:\n %s", errorMessage)
+	}
+	return nil
+}
+
+// StartData - Go routine to run parallal with app to keep injecting data every 2 seconds
+func (app *KafkaConfig) StartData(command <-chan string, ctx context.Context) error {
+	var status = DataStart
+	var allSelectCommands []string
+	var allErrors []string
+	var topicName = "topic_" + RandomString(4)
+
+	for {
+		select {
+		case cmd := <-command:
+			switch cmd {
+			case DataStop:
+				if len(allErrors) != 0 {
+					return fmt.Errorf(strings.Join(allErrors, "\n"))
+				}
+				err := app.CheckDataPresent(allSelectCommands, ctx)
+				return err
+
+			case DataPause:
+				status = DataPause
+			default:
+				status = DataStart
+			}
+		default:
+			if status == DataStart {
+				commandPair := GenerateKafkaCommandPair(topicName)
+				_, err := app.ExecuteCommand(commandPair["insert"], ctx)
+				if err != nil {
+					allErrors = append(allErrors, fmt.Sprintf("Continuity Pipeline Error - [%s] at [%s]", err.Error(), time.Now().Format("2006-01-02 15:04:05")))
+				}
+				allSelectCommands = append(allSelectCommands, commandPair["select"]...)
+				time.Sleep(2 * time.Second)
+			}
+		}
+	}
+}
+
+// Update the existing data commands
+func (app *KafkaConfig) UpdateDataCommands(count int, identifier string) {
+	app.DataCommands[identifier] = GenerateRandomKafkaCommands(count)
+	log.InfoD("Data commands updated")
+}
+
+// Add data commands to the existing ones
+func (app *KafkaConfig) AddDataCommands(identifier string, commands map[string][]string) {
+	app.DataCommands[identifier] = commands
+	log.InfoD("Data commands added")
+}
+
+// Generate and return random data commands
+func (app *KafkaConfig) GetRandomDataCommands(count int) map[string][]string {
+	return GenerateRandomKafkaCommands(count)
+}
+
+// Get the application type
+func (app *KafkaConfig) GetApplicationType() string {
+	return Kafka
+}
+
+// Get Namespace of the app
+func (app *KafkaConfig) GetNamespace() string {
+	return app.Namespace
+}
+
+// WaitForVMToBoot waits for VM to boot
+func (app *KafkaConfig) WaitForVMToBoot() error {
+	log.Warnf("Not implemented for Kafka")
+	return nil
+}
+
+// WriteWorkload produces the rows of the integrity workload as records of its topic
+func (app *KafkaConfig) WriteWorkload(ctx context.Context, workload integrity.Workload) error {
+	log.InfoD("Writing integrity workload [%s] with %d rows to [%s]", workload.Name, workload.Count, app.Namespace)
+	return app.WriteRows(ctx, workload.ObjectName(), workload.Rows())
+}
+
+// Checksum returns the logical checksum of the integrity workload topics
+func (app *KafkaConfig) Checksum(ctx context.Context) (*integrity.Snapshot, error) {
+	output, err := app.runScript([]string{"kafka-topics.sh", "--list"}, "")
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := integrity.NewSnapshot(Kafka, app.Namespace)
+	for _, topic := range strings.Split(output, "\n") {
+		topic = strings.TrimSpace(topic)
+		if !strings.HasPrefix(topic, integrity.ObjectPrefix) {
+			continue
+		}
+		rows, err := app.ReadRows(ctx, topic)
+		if err != nil {
+			return nil, err
+		}
+		digest := &integrity.Digest{}
+		for _, row := range rows {
+			digest.Add(row.Key, row.Value)
+		}
+		snapshot.Objects[topic] = digest.Checksum()
+	}
+	return snapshot, nil
+}
+
+// WriteRows produces the rows as records of the topic, with the row key as record key
+func (app *KafkaConfig) WriteRows(ctx context.Context, object string, rows []integrity.Row) error {
+	var records []string
+	for _, row := range rows {
+		records = append(records, row.Key+recordSeparator+row.Value)
+	}
+	return app.produce(object, records)
+}
+
+// ReadRows returns the records of the topic from the beginning as rows
+func (app *KafkaConfig) ReadRows(ctx context.Context, object string) ([]integrity.Row, error) {
+	records, err := app.consume(object)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topic [%s]: %v", object, err)
+	}
+	var rows []integrity.Row
+	for _, record := range records {
+		fields := strings.SplitN(record, recordSeparator, 2)
+		rows = append(rows, integrity.Row{Key: fields[0], Value: fields[1]})
+	}
+	return rows, nil
+}
+
+// produce creates the topic if needed and produces the records, each in the form "<key>:<value>"
+func (app *KafkaConfig) produce(topic string, records []string) error {
+	_, err := app.runScript([]string{"kafka-topics.sh", "--create", "--if-not-exists", "--topic", topic}, "")
+	if err != nil {
+		return err
+	}
+	_, err = app.runScript([]string{
+		"kafka-console-producer.sh", "--topic", topic,
+		"--producer-property", "acks=all",
+		"--property", "parse.key=true",
+		"--property", "key.separator=" + recordSeparator,
+	}, strings.Join(records, "\n")+"\n")
+	return err
+}
+
+// consume returns the records of the topic from the beginning, each in the form "<key>:<value>"
+func (app *KafkaConfig) consume(topic string) ([]string, error) {
+	output, err := app.runScript([]string{
+		"kafka-console-consumer.sh", "--topic", topic, "--from-beginning",
+		"--timeout-ms", fmt.Sprintf("%d", consumeTimeout.Milliseconds()),
+		"--property", "print.key=true",
+		"--property", "key.separator=" + recordSeparator,
+	}, "")
+	if err != nil {
+		return nil, err
+	}
+	var records []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); strings.Contains(line, recordSeparator) {
+			records = append(records, line)
+		}
+	}
+	return records, nil
+}
+
+// runScript runs a kafka script against the broker of a kafka pod
+func (app *KafkaConfig) runScript(command []string, stdin string) (string, error) {
+	if app.Port == 0 {
+		app.Port = app.DefaultPort()
+	}
+	pod, container, err := GetPodWithContainerPort(app.Namespace, app.Port)
+	if err != nil {
+		return "", err
+	}
+	script := append([]string{command[0], "--bootstrap-server", fmt.Sprintf("localhost:%d", app.Port)}, command[1:]...)
+	return RunCommandInContainer(pod, container, script, stdin)
+}
+
+// groupRecordsByTopic splits the records, each in the form "<topic> <key>:<value>", by topic
+// and returns the topics in the order they first appear
+func groupRecordsByTopic(commands []string) ([]string, map[string][]string, error) {
+	var topics []string
+	records := make(map[string][]string)
+	for _, command := range commands {
+		fields := strings.SplitN(strings.TrimSpace(command), " ", 2)
+		if len(fields) != 2 || !strings.Contains(fields[1], recordSeparator) {
+			return nil, nil, fmt.Errorf("invalid record [%s], expected <topic> <key>%s<value>", command, recordSeparator)
+		}
+		if _, ok := records[fields[0]]; !ok {
+			topics = append(topics, fields[0])
+		}
+		records[fields[0]] = append(records[fields[0]], fields[1])
+	}
+	return topics, records, nil
+}
@@ -0,0 +1,19 @@
+package applications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupRecordsByTopic(t *testing.T) {
+	topics, records, err := groupRecordsByTopic([]string{"b k1:v1", "a k2:v2", "b k3:v3"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "a"}, topics)
+	require.Equal(t, map[string][]string{"a": {"k2:v2"}, "b": {"k1:v1", "k3:v3"}}, records)
+
+	_, _, err = groupRecordsByTopic([]string{"topic-only"})
+	require.Error(t, err)
+	_, _, err = groupRecordsByTopic([]string{"topic no-separator"})
+	require.Error(t, err)
+}
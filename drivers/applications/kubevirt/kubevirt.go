@@ -5,6 +5,7 @@ import (
 	"github.com/portworx/sched-ops/k8s/core"
 	"github.com/portworx/sched-ops/task"
 	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	"github.com/portworx/torpedo/drivers/node"
 	. "github.com/portworx/torpedo/drivers/utilities"
 	"github.com/portworx/torpedo/pkg/log"
@@ -20,6 +21,8 @@ const (
 	sshPodName      = "ssh-pod"
 	sshPodNamespace = "default"
 	sshContainer    = "ssh-container"
+	// maxWorkloadCommandLength is the length of the commands the workload files are written with
+	maxWorkloadCommandLength = 16 * 1024
 )
 
 type KubevirtConfig struct {
@@ -59,7 +62,7 @@ func (app *KubevirtConfig) ExecuteCommand(commands []string, ctx context.Context
 		workerNode := node.GetWorkerNodes()[0]
 		t := func() (interface{}, bool, error) {
 			for _, eachCommand := range commands {
-				cmd := strings.Join(getSSHCommandArgs(app.User, app.Password, app.IPAddress, eachCommand), " ")
+				cmd := getSSHCommand(app.User, app.Password, app.IPAddress, eachCommand)
 				log.Infof("Executing = [%s]", cmd)
 				output, err := RunCmdGetOutputOnNode(cmd, workerNode, app.NodeDriver)
 				if err != nil {
@@ -118,7 +121,7 @@ func (app *KubevirtConfig) WaitForVMToBoot() error {
 	} else {
 		workerNode := node.GetWorkerNodes()[0]
 		t := func() (interface{}, bool, error) {
-			cmd := strings.Join(getSSHCommandArgs(app.User, app.Password, app.IPAddress, "hostname"), " ")
+			cmd := getSSHCommand(app.User, app.Password, app.IPAddress, "hostname")
 			log.Infof("Executing = [%s]", cmd)
 			output, err := RunCmdGetOutputOnNode(cmd, workerNode, app.NodeDriver)
 			if err != nil {
@@ -210,6 +213,45 @@ func (app *KubevirtConfig) CheckDataPresent(lsCommands []string, ctx context.Con
 	return err
 }
 
+// WriteWorkload writes the files of the integrity workload in the VM
+func (app *KubevirtConfig) WriteWorkload(ctx context.Context, workload integrity.Workload) error {
+	log.InfoD("Writing integrity workload [%s] with %d files to VM [%s]", workload.Name, workload.Count, app.Hostname)
+	_, err := app.ExecuteCommand(workload.ShellCommands(maxWorkloadCommandLength), ctx)
+	return err
+}
+
+// Checksum returns the logical checksum of the integrity workload files in the VM
+func (app *KubevirtConfig) Checksum(ctx context.Context) (*integrity.Snapshot, error) {
+	output, err := app.ExecuteCommand([]string{integrity.ManifestCommand}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return integrity.ParseManifest(Kubevirt, app.Namespace, strings.Join(output, "\n")), nil
+}
+
+// WriteRows writes each row as a file named by its key, with its value as content, in the object directory of the VM
+func (app *KubevirtConfig) WriteRows(ctx context.Context, object string, rows []integrity.Row) error {
+	var files []integrity.File
+	for _, row := range rows {
+		files = append(files, integrity.File{Name: row.Key, Content: row.Value})
+	}
+	_, err := app.ExecuteCommand(integrity.ShellWriteCommands(object, files, maxWorkloadCommandLength), ctx)
+	return err
+}
+
+// ReadRows returns the files of the object directory of the VM as rows
+func (app *KubevirtConfig) ReadRows(ctx context.Context, object string) ([]integrity.Row, error) {
+	output, err := app.ExecuteCommand([]string{integrity.ShellReadCommand(object)}, ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []integrity.Row
+	for _, file := range integrity.ParseShellRead(strings.Join(output, "\n")) {
+		rows = append(rows, integrity.Row{Key: file.Name, Value: file.Content})
+	}
+	return rows, nil
+}
+
 // isConnectionError checks if the error message is a connection error
 func isConnectionError(errorMessage string) bool {
 	return strings.Contains(errorMessage, "Connection refused") || strings.Contains(errorMessage, "Host is unreachable") ||
@@ -221,6 +263,14 @@ func getSSHCommandArgs(username, password, ipAddress, cmd string) []string {
 	return []string{"sshpass", "-p", password, "ssh", "-o", "StrictHostKeyChecking=no", fmt.Sprintf("%s@%s", username, ipAddress), cmd}
 }
 
+// getSSHCommand returns the SSH command to run on a node, the command to run in the VM is quoted
+// so that redirections and command lists are evaluated in the VM rather than on the node
+func getSSHCommand(username, password, ipAddress, cmd string) string {
+	args := getSSHCommandArgs(username, password, ipAddress, cmd)
+	quoted := "'" + strings.ReplaceAll(cmd, "'", `'\''`) + "'"
+	return strings.Join(append(args[:len(args)-1], quoted), " ")
+}
+
 // initSSHPod creates a pod with ssh server installed and running along with sshpass utility
 func initSSHPod(namespace string) error {
 	var p *corev1.Pod
@@ -0,0 +1,349 @@
+package applications
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
+	. "github.com/portworx/torpedo/drivers/utilities"
+	"github.com/portworx/torpedo/pkg/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const mongoInsertBatchSize = 1000
+
+type MongoDBConfig struct {
+	Hostname     string
+	User         string
+	Password     string
+	Port         int
+	NodePort     int
+	DBName       string
+	Namespace    string
+	DataCommands map[string]map[string][]string
+}
+
+// mongoCommandResult is the part of a database command result checked for failures and found documents
+type mongoCommandResult struct {
+	WriteErrors []bson.M `bson:"writeErrors"`
+	Cursor      struct {
+		FirstBatch []bson.Raw `bson:"firstBatch"`
+	} `bson:"cursor"`
+}
+
+// GetConnection returns a connected client for the mongodb database
+func (app *MongoDBConfig) GetConnection(ctx context.Context) (*mongo.Client, error) {
+
+	if app.Port == 0 {
+		app.Port = app.DefaultPort()
+	}
+
+	if app.DBName == "" {
+		app.DBName = app.DefaultDBName()
+	}
+
+	port := app.Port
+	if app.NodePort != 0 {
+		// Connect with NodePort Service
+		port = app.NodePort
+	}
+
+	var credentials string
+	if app.User != "" {
+		credentials = fmt.Sprintf("%s:%s@", url.QueryEscape(app.User), url.QueryEscape(app.Password))
+	}
+	uri := fmt.Sprintf("mongodb://%s%s:%d/?authSource=admin", credentials, app.Hostname, port)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).SetConnectTimeout(30*time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to database: %s, Host - [%s:%d]", err, app.Hostname, port)
+	}
+	if err = client.Ping(ctx, nil); err != nil {
+		client.Disconnect(ctx)
+		return nil, fmt.Errorf("unable to ping database: %s, Host - [%s:%d]", err, app.Hostname, port)
+	}
+
+	return client, nil
+}
+
+// DefaultPort returns default port for mongodb
+func (app *MongoDBConfig) DefaultPort() int { return 27017 }
+
+// DefaultDBName returns default database name
+func (app *MongoDBConfig) DefaultDBName() string { return "torpedo" }
+
+// ExecuteCommand runs the database commands, given in extended JSON, and returns their results
+func (app *MongoDBConfig) ExecuteCommand(commands []string, ctx context.Context) ([]string, error) {
+
+	var results []string
+	client, err := app.GetConnection(ctx)
+	if err != nil {
+		return results, err
+	}
+
+	defer client.Disconnect(ctx)
+
+	for _, eachCommand := range commands {
+		raw, err := app.runCommand(ctx, client, eachCommand)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, raw.String())
+	}
+	return results, nil
+}
+
+// runCommand runs a database command given in extended JSON and fails on write errors
+func (app *MongoDBConfig) runCommand(ctx context.Context, client *mongo.Client, command string) (bson.Raw, error) {
+	var document bson.D
+	if err := bson.UnmarshalExtJSON([]byte(command), false, &document); err != nil {
+		return nil, fmt.Errorf("invalid command [%s]: %v", command, err)
+	}
+	raw, err := client.Database(app.DBName).RunCommand(ctx, document).Raw()
+	if err != nil {
+		return nil, err
+	}
+	var result mongoCommandResult
+	if err = bson.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	if len(result.WriteErrors) != 0 {
+		return nil, fmt.Errorf("command [%s] failed with write errors %v", command, result.WriteErrors)
+	}
+	return raw, nil
+}
+
+// InsertBackupData inserts the documents generated initially by utilities or documents passed
+func (app *MongoDBConfig) InsertBackupData(ctx context.Context, identifier string, commands []string) error {
+
+	var err error
+	log.InfoD("Inserting data")
+	if len(commands) == 0 {
+		log.Infof("Inserting below data : %s", strings.Join(app.DataCommands[identifier]["insert"], "\n"))
+		_, err = app.ExecuteCommand(app.DataCommands[identifier]["insert"], ctx)
+	} else {
+		log.Infof("Inserting below data : %s", strings.Join(commands, "\n"))
+		_, err = app.ExecuteCommand(commands, ctx)
+	}
+
+	return err
+}
+
+// Return data inserted before backup
+func (app *MongoDBConfig) GetBackupData(identifier string) []string {
+	if _, ok := app.DataCommands[identifier]; ok {
+		return app.DataCommands[identifier]["select"]
+	} else {
+		log.InfoD("%s not found in app data command", identifier)
+		log.Infof("All current data commands - %+v", app.DataCommands)
+		return nil
+	}
+}
+
+// CheckDataPresent checks if the find commands return a document
+func (app *MongoDBConfig) CheckDataPresent(selectQueries []string, ctx context.Context) error {
+
+	log.InfoD("Running Find Commands")
+
+	client, err := app.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer client.Disconnect(ctx)
+
+	var queryNotFoundList []string
+	for _, eachQuery := range selectQueries {
+		raw, err := app.runCommand(ctx, client, eachQuery)
+		if err != nil {
+			log.InfoD("Find command failed - [%s] Error - [%s]", eachQuery, err.Error())
+			queryNotFoundList = append(queryNotFoundList, eachQuery)
+			continue
+		}
+		var result mongoCommandResult
+		if err = bson.Unmarshal(raw, &result); err != nil || len(result.Cursor.FirstBatch) == 0 {
+			log.InfoD("Find command returned no document - [%s]", eachQuery)
+			queryNotFoundList = append(queryNotFoundList, eachQuery)
+		}
+	}
+
+	if len(queryNotFoundList) != 0 {
+		errorMessage := strings.Join(queryNotFoundList, "\n")
+		return fmt.Errorf("Below results not found in the collection:\n %s", errorMessage)
+	}
+	return nil
+}
+
+// StartData - Go routine to run parallal with app to keep injecting data every 2 seconds
+func (app *MongoDBConfig) StartData(command <-chan string, ctx context.Context) error {
+	var status = DataStart
+	var allSelectCommands []string
+	var allErrors []string
+	var collectionName = "collection_" + RandomString(4)
+
+	for {
+		select {
+		case cmd := <-command:
+			switch cmd {
+			case DataStop:
+				if len(allErrors) != 0 {
+					return fmt.Errorf(strings.Join(allErrors, "\n"))
+				}
+				err := app.CheckDataPresent(allSelectCommands, ctx)
+				return err
+
+			case DataPause:
+				status = DataPause
+			default:
+				status = DataStart
+			}
+		default:
+			if status == DataStart {
+				commandPair := GenerateMongoCommandPair(collectionName)
+				_, err := app.ExecuteCommand(commandPair["insert"], ctx)
+				if err != nil {
+					allErrors = append(allErrors, fmt.Sprintf("Continuity Pipeline Error - [%s] at [%s]", err.Error(), time.Now().Format("2006-01-02 15:04:05")))
+				}
+				allSelectCommands = append(allSelectCommands, commandPair["select"]...)
+				time.Sleep(2 * time.Second)
+			}
+		}
+	}
+}
+
+// Update the existing data commands
+func (app *MongoDBConfig) UpdateDataCommands(count int, identifier string) {
+	app.DataCommands[identifier] = GenerateRandomMongoCommands(count)
+	log.InfoD("Data commands updated")
+}
+
+// Add data commands to the existing ones
+func (app *MongoDBConfig) AddDataCommands(identifier string, commands map[string][]string) {
+	app.DataCommands[identifier] = commands
+	log.InfoD("Data commands added")
+}
+
+// Generate and return random data commands
+func (app *MongoDBConfig) GetRandomDataCommands(count int) map[string][]string {
+	return GenerateRandomMongoCommands(count)
+}
+
+// Get the application type
+func (app *MongoDBConfig) GetApplicationType() string {
+	return MongoDB
+}
+
+// Get Namespace of the app
+func (app *MongoDBConfig) GetNamespace() string {
+	return app.Namespace
+}
+
+// WaitForVMToBoot waits for VM to boot
+func (app *MongoDBConfig) WaitForVMToBoot() error {
+	log.Warnf("Not implemented for MongoDB")
+	return nil
+}
+
+// WriteWorkload inserts the rows of the integrity workload as documents of its collection
+func (app *MongoDBConfig) WriteWorkload(ctx context.Context, workload integrity.Workload) error {
+	log.InfoD("Writing integrity workload [%s] with %d rows to [%s]", workload.Name, workload.Count, app.Hostname)
+	return app.WriteRows(ctx, workload.ObjectName(), workload.Rows())
+}
+
+// Checksum returns the logical checksum of the integrity workload collections
+func (app *MongoDBConfig) Checksum(ctx context.Context) (*integrity.Snapshot, error) {
+	client, err := app.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Disconnect(ctx)
+
+	collections, err := client.Database(app.DBName).ListCollectionNames(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := integrity.NewSnapshot(MongoDB, app.Namespace)
+	for _, collection := range collections {
+		if !strings.HasPrefix(collection, integrity.ObjectPrefix) {
+			continue
+		}
+		rows, err := app.readRows(ctx, client, collection)
+		if err != nil {
+			return nil, err
+		}
+		digest := &integrity.Digest{}
+		for _, row := range rows {
+			digest.Add(row.Key, row.Value)
+		}
+		snapshot.Objects[collection] = digest.Checksum()
+	}
+	return snapshot, nil
+}
+
+// WriteRows inserts the rows as documents of the collection, with the key as id
+func (app *MongoDBConfig) WriteRows(ctx context.Context, object string, rows []integrity.Row) error {
+	client, err := app.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+
+	collection := client.Database(app.DBName).Collection(object)
+	for start := 0; start < len(rows); start += mongoInsertBatchSize {
+		end := start + mongoInsertBatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		var documents []interface{}
+		for _, row := range rows[start:end] {
+			documents = append(documents, bson.D{{Key: "_id", Value: row.Key}, {Key: "v", Value: row.Value}})
+		}
+		if _, err = collection.InsertMany(ctx, documents); err != nil {
+			return fmt.Errorf("failed to insert into collection [%s]: %v", object, err)
+		}
+	}
+	return nil
+}
+
+// ReadRows returns the documents of the collection as rows
+func (app *MongoDBConfig) ReadRows(ctx context.Context, object string) ([]integrity.Row, error) {
+	client, err := app.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Disconnect(ctx)
+
+	return app.readRows(ctx, client, object)
+}
+
+// readRows returns the id and value of the documents of the collection
+func (app *MongoDBConfig) readRows(ctx context.Context, client *mongo.Client, collection string) ([]integrity.Row, error) {
+	cursor, err := client.Database(app.DBName).Collection(collection).Find(ctx, bson.D{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []integrity.Row
+	for cursor.Next(ctx) {
+		var document struct {
+			Key   string `bson:"_id"`
+			Value string `bson:"v"`
+		}
+		if err = cursor.Decode(&document); err != nil {
+			return nil, err
+		}
+		rows = append(rows, integrity.Row{Key: document.Key, Value: document.Value})
+	}
+	if err = cursor.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read collection [%s]: %v", collection, err)
+	}
+	return rows, nil
+}
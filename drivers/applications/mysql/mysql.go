@@ -9,6 +9,7 @@ import (
 
 	_ "github.com/go-sql-driver/mysql"
 	. "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	. "github.com/portworx/torpedo/drivers/utilities"
 	"github.com/portworx/torpedo/pkg/log"
 )
@@ -248,3 +249,92 @@ func (app *MySqlConfig) WaitForVMToBoot() error {
 	log.Warnf("Not implemented for Mysql")
 	return nil
 }
+
+// WriteWorkload creates the table of the integrity workload and inserts its rows
+func (app *MySqlConfig) WriteWorkload(ctx context.Context, workload integrity.Workload) error {
+	log.InfoD("Writing integrity workload [%s] with %d rows to [%s]", workload.Name, workload.Count, app.Hostname)
+	_, err := app.ExecuteCommand(workload.SQLStatements(), ctx)
+	return err
+}
+
+// Checksum returns the logical checksum of the integrity workload tables
+func (app *MySqlConfig) Checksum(ctx context.Context) (*integrity.Snapshot, error) {
+	conn, err := app.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	tableRows, err := conn.QueryContext(ctx, "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE()")
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for tableRows.Next() {
+		var table string
+		if err = tableRows.Scan(&table); err != nil {
+			tableRows.Close()
+			return nil, err
+		}
+		if strings.HasPrefix(table, integrity.ObjectPrefix) {
+			tables = append(tables, table)
+		}
+	}
+	tableRows.Close()
+	if err = tableRows.Err(); err != nil {
+		return nil, err
+	}
+
+	snapshot := integrity.NewSnapshot(MySql, app.Namespace)
+	for _, table := range tables {
+		rows, err := app.readRows(ctx, conn, table)
+		if err != nil {
+			return nil, err
+		}
+		digest := &integrity.Digest{}
+		for _, row := range rows {
+			digest.Add(row.Key, row.Value)
+		}
+		snapshot.Objects[table] = digest.Checksum()
+	}
+	return snapshot, nil
+}
+
+// WriteRows creates the table if needed and inserts the rows
+func (app *MySqlConfig) WriteRows(ctx context.Context, object string, rows []integrity.Row) error {
+	_, err := app.ExecuteCommand(integrity.SQLInsertStatements(object, rows), ctx)
+	return err
+}
+
+// ReadRows returns the rows of the table
+func (app *MySqlConfig) ReadRows(ctx context.Context, object string) ([]integrity.Row, error) {
+	conn, err := app.GetConnection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return app.readRows(ctx, conn, object)
+}
+
+// readRows returns the key and value columns of the rows of the table
+func (app *MySqlConfig) readRows(ctx context.Context, conn *sql.DB, table string) ([]integrity.Row, error) {
+	rows, err := conn.QueryContext(ctx, integrity.SQLSelectStatement(table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []integrity.Row
+	for rows.Next() {
+		var row integrity.Row
+		if err = rows.Scan(&row.Key, &row.Value); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read table [%s]: %v", table, err)
+	}
+	return result, nil
+}
@@ -2,15 +2,33 @@ package monitor
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/portworx/torpedo/pkg/errors"
 	"github.com/portworx/torpedo/pkg/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// InitOptions initialization options
+type InitOptions struct {
+	// JobName is the name of the torpedo job, set as a label of all metrics
+	JobName string
+	// JobType is the type of the torpedo job, set as a label of all metrics
+	JobType string
+	// MetricsPort is the port of the scrape endpoint, the endpoint is not started if set to "0"
+	MetricsPort string
+	// PushGatewayURL is the URL of a Pushgateway the metrics are pushed to
+	PushGatewayURL string
+	// RemoteWriteURL is the URL of a Prometheus remote-write endpoint the metrics are pushed to
+	RemoteWriteURL string
+	// PushInterval is the interval at which metrics are pushed, when a push URL is set
+	PushInterval time.Duration
+}
+
 type Driver interface {
 
 	// Init initializes the monitor driver
-	Init(string, string) error
+	Init(InitOptions) error
 
 	// String returns the string name of this driver.
 	String() string
@@ -32,6 +50,15 @@ type Driver interface {
 
 	// SetGaugeMetricWithNonDefaultLabels sets gauge metrics with non default labels to the value provided
 	SetGaugeMetricWithNonDefaultLabels(metric *prometheus.GaugeVec, value float64, testName string, additionalLabels ...string)
+
+	// ObserveHistogramMetric adds an observation, for e.g. a duration in seconds, to histogram metrics
+	ObserveHistogramMetric(metric *prometheus.HistogramVec, value float64, testName string, additionalLabels ...string)
+
+	// ObserveSummaryMetric adds an observation, for e.g. a duration in seconds, to summary metrics
+	ObserveSummaryMetric(metric *prometheus.SummaryVec, value float64, testName string, additionalLabels ...string)
+
+	// Push pushes the current metrics to the configured Pushgateway and remote-write endpoints, if any
+	Push() error
 }
 
 var (
@@ -1,8 +1,11 @@
 package prometheus
 
 import (
-	"github.com/portworx/torpedo/pkg/log"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/portworx/torpedo/pkg/log"
 
 	"github.com/portworx/torpedo/drivers/monitor"
 	"github.com/prometheus/client_golang/prometheus"
@@ -16,6 +19,11 @@ type Prom struct {
 	vCenterName    string
 	vCenterCluster string
 	vCenterNetwork string
+	pushGatewayURL string
+	remoteWriteURL string
+	gatherer       prometheus.Gatherer
+	client         *http.Client
+	pushLock       sync.Mutex
 }
 
 const (
@@ -24,6 +32,12 @@ const (
 
 	// DefaultMetricsPort is the default port for exposing metrics
 	DefaultMetricsPort = "8181"
+
+	// DefaultPushInterval is the default interval at which metrics are pushed in push mode
+	DefaultPushInterval = 30 * time.Second
+
+	// disabledMetricsPort is the metrics port which disables the scrape endpoint
+	disabledMetricsPort = "0"
 )
 
 var (
@@ -36,6 +50,15 @@ var (
 		// Torpedo Job Type
 		"job_type",
 	}
+
+	// durationBuckets are the histogram buckets of durations in seconds, from 1s to about 4.5h
+	durationBuckets = prometheus.ExponentialBuckets(1, 2, 15)
+
+	// latencyBuckets are the histogram buckets of request latencies in seconds, from 5ms to about 40s
+	latencyBuckets = prometheus.ExponentialBuckets(0.005, 2, 14)
+
+	// defaultObjectives are the quantiles of summary metrics with their allowed error
+	defaultObjectives = map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001}
 )
 
 // Torpedo metrics
@@ -54,6 +77,27 @@ var (
 
 	// TorpedoTestFailCount is a counter metric for the total number of failed tests
 	TorpedoTestFailCount = AddCounterMetric("torpedo_test_fail_count", "Torpedo test fail count")
+
+	// TorpedoBackupDuration is a histogram metric of the time taken by backups to succeed
+	TorpedoBackupDuration = AddHistogramMetric("torpedo_backup_duration_seconds", "Torpedo backup duration in seconds", durationBuckets)
+
+	// TorpedoVolumeAttachTime is a histogram metric of the time taken by volumes to be attached after their creation
+	TorpedoVolumeAttachTime = AddHistogramMetric("torpedo_volume_attach_time_seconds", "Torpedo volume attach time in seconds", durationBuckets)
+
+	// TorpedoRebootToReadyTime is a histogram metric of the time taken by nodes to be ready after a reboot
+	TorpedoRebootToReadyTime = AddHistogramMetric("torpedo_node_reboot_to_ready_seconds", "Torpedo node reboot to ready time in seconds", durationBuckets, "node")
+
+	// TorpedoTriggerDuration is a summary metric of the time taken by longevity triggers
+	TorpedoTriggerDuration = AddSummaryMetric("torpedo_trigger_duration_seconds", "Torpedo trigger duration in seconds", defaultObjectives)
+
+	// TorpedoAPILoadRequests is a gauge metric of the number of requests sent by API load runs
+	TorpedoAPILoadRequests = AddGaugeMetric("torpedo_api_load_requests", "Torpedo API load requests sent", "collection", "request")
+
+	// TorpedoAPILoadFailures is a gauge metric of the number of requests of API load runs which failed, by cause
+	TorpedoAPILoadFailures = AddGaugeMetric("torpedo_api_load_failures", "Torpedo API load requests failed", "collection", "request", "cause")
+
+	// TorpedoAPILoadLatency is a histogram metric of the latency of the requests of API load runs
+	TorpedoAPILoadLatency = AddHistogramMetric("torpedo_api_load_latency_seconds", "Torpedo API load request latency in seconds", latencyBuckets, "collection", "request")
 )
 
 // AddGaugeMetric adds GaugeVec metrics
@@ -83,6 +127,34 @@ func AddCounterMetric(name string, helpMessage string, additionalLabels ...strin
 	return counterMetrics
 }
 
+// AddHistogramMetric adds HistogramVec metrics with the given buckets
+func AddHistogramMetric(name string, helpMessage string, buckets []float64, additionalLabels ...string) *prometheus.HistogramVec {
+	additionalLabels = append(metricsLabel, additionalLabels...)
+	histogramMetrics := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    name,
+			Help:    helpMessage,
+			Buckets: buckets,
+		}, additionalLabels,
+	)
+	prometheus.MustRegister(histogramMetrics)
+	return histogramMetrics
+}
+
+// AddSummaryMetric adds SummaryVec metrics with the given quantile objectives
+func AddSummaryMetric(name string, helpMessage string, objectives map[float64]float64, additionalLabels ...string) *prometheus.SummaryVec {
+	additionalLabels = append(metricsLabel, additionalLabels...)
+	summaryMetrics := prometheus.NewSummaryVec(
+		prometheus.SummaryOpts{
+			Name:       name,
+			Help:       helpMessage,
+			Objectives: objectives,
+		}, additionalLabels,
+	)
+	prometheus.MustRegister(summaryMetrics)
+	return summaryMetrics
+}
+
 // IncrementGaugeMetric increments gauge metrics
 func (p *Prom) IncrementGaugeMetric(metric *prometheus.GaugeVec, testName string) {
 	metric.WithLabelValues(testName, p.jobName, p.jobType).Inc()
@@ -117,25 +189,67 @@ func (p *Prom) SetGaugeMetricWithNonDefaultLabels(metric *prometheus.GaugeVec, v
 	metric.WithLabelValues(lvs...).Set(value)
 }
 
+// ObserveHistogramMetric adds an observation to histogram metrics
+func (p *Prom) ObserveHistogramMetric(metric *prometheus.HistogramVec, value float64, testName string, additionalLabels ...string) {
+	lvs := []string{testName, p.jobName, p.jobType}
+	lvs = append(lvs, additionalLabels...)
+	metric.WithLabelValues(lvs...).Observe(value)
+}
+
+// ObserveSummaryMetric adds an observation to summary metrics
+func (p *Prom) ObserveSummaryMetric(metric *prometheus.SummaryVec, value float64, testName string, additionalLabels ...string) {
+	lvs := []string{testName, p.jobName, p.jobType}
+	lvs = append(lvs, additionalLabels...)
+	metric.WithLabelValues(lvs...).Observe(value)
+}
+
 // String returns the name of the Prometheus monitor driver implementation
 func (p *Prom) String() string {
 	return DriverName
 }
 
-// Init initializes Prom object and starts metrics handler
-func (p *Prom) Init(jobName string, jobType string) error {
+// Init initializes Prom object, starts metrics handler and, in push mode, pushes metrics periodically
+func (p *Prom) Init(opts monitor.InitOptions) error {
+	p.port = opts.MetricsPort
 	if p.port == "" {
 		p.port = DefaultMetricsPort
 	}
-	p.jobName = jobName
-	p.jobType = jobType
-	http.Handle("/metrics", promhttp.Handler())
-	log.Infof("Starting http service for %s on port %s", DriverName, p.port)
-	go func() {
-		if err := http.ListenAndServe(":"+p.port, nil); err != nil {
-			log.Errorf("Failed to start http service for %s on port %s due to %v", DriverName, p.port, err)
+	p.jobName = opts.JobName
+	p.jobType = opts.JobType
+	p.pushGatewayURL = opts.PushGatewayURL
+	p.remoteWriteURL = opts.RemoteWriteURL
+	if p.gatherer == nil {
+		p.gatherer = prometheus.DefaultGatherer
+	}
+	if p.client == nil {
+		p.client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if p.port != disabledMetricsPort {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.HandlerFor(p.gatherer, promhttp.HandlerOpts{}))
+		log.Infof("Starting http service for %s on port %s", DriverName, p.port)
+		go func() {
+			if err := http.ListenAndServe(":"+p.port, mux); err != nil {
+				log.Errorf("Failed to start http service for %s on port %s due to %v", DriverName, p.port, err)
+			}
+		}()
+	}
+
+	if p.pushGatewayURL != "" || p.remoteWriteURL != "" {
+		interval := opts.PushInterval
+		if interval <= 0 {
+			interval = DefaultPushInterval
 		}
-	}()
+		log.Infof("Pushing %s metrics every %v to pushgateway [%s] and remote-write [%s]", DriverName, interval, p.pushGatewayURL, p.remoteWriteURL)
+		go func() {
+			for range time.Tick(interval) {
+				if err := p.Push(); err != nil {
+					log.Warnf("Failed to push %s metrics: %v", DriverName, err)
+				}
+			}
+		}()
+	}
 	return nil
 }
 
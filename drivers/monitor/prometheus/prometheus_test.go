@@ -0,0 +1,101 @@
+package prometheus
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/portworx/torpedo/drivers/monitor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProm(t *testing.T, opts monitor.InitOptions) (*Prom, *prometheus.HistogramVec, *prometheus.SummaryVec) {
+	registry := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "test_reboot_seconds",
+		Buckets: []float64{1, 10},
+	}, append(metricsLabel, "node"))
+	summary := prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       "test_trigger_seconds",
+		Objectives: map[float64]float64{0.5: 0.05},
+	}, metricsLabel)
+	registry.MustRegister(histogram, summary)
+
+	p := &Prom{gatherer: registry}
+	opts.MetricsPort = disabledMetricsPort
+	require.NoError(t, p.Init(opts))
+	return p, histogram, summary
+}
+
+func TestPushGateway(t *testing.T) {
+	var path, body string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		path = r.URL.EscapedPath()
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		body = string(b)
+	}))
+	defer gateway.Close()
+
+	p, histogram, _ := newTestProm(t, monitor.InitOptions{
+		JobName:        "longevity",
+		JobType:        "nightly/px",
+		PushGatewayURL: gateway.URL + "/",
+	})
+	p.ObserveHistogramMetric(histogram, 5, "RebootNode", "node-0")
+	require.NoError(t, p.Push())
+
+	require.Equal(t, "/metrics/job/longevity", path)
+	require.Contains(t, body, `test_reboot_seconds_bucket{job_name="longevity",job_type="nightly/px",node="node-0",test_name="RebootNode",le="10"} 1`)
+	require.Contains(t, body, `test_reboot_seconds_count{job_name="longevity",job_type="nightly/px",node="node-0",test_name="RebootNode"} 1`)
+}
+
+func TestPushRemoteWrite(t *testing.T) {
+	var series []map[string]string
+	var values []float64
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		b, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		var req prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(b, &req))
+		for _, ts := range req.Timeseries {
+			labels := make(map[string]string)
+			for _, l := range ts.Labels {
+				labels[l.Name] = l.Value
+			}
+			series = append(series, labels)
+			values = append(values, ts.Samples[0].Value)
+		}
+	}))
+	defer receiver.Close()
+
+	p, _, summary := newTestProm(t, monitor.InitOptions{RemoteWriteURL: receiver.URL})
+	p.ObserveSummaryMetric(summary, 3, "RebootNode")
+	p.ObserveSummaryMetric(summary, 3, "RebootNode")
+	require.NoError(t, p.Push())
+
+	require.Len(t, series, 3)
+	require.Equal(t, map[string]string{
+		"__name__":  "test_trigger_seconds",
+		"job_name":  "",
+		"job_type":  "",
+		"quantile":  "0.5",
+		"test_name": "RebootNode",
+	}, series[0])
+	require.Equal(t, []float64{3, 6, 2}, values)
+	require.Equal(t, "test_trigger_seconds_count", series[2]["__name__"])
+}
+
+func TestPushDisabled(t *testing.T) {
+	p, _, _ := newTestProm(t, monitor.InitOptions{})
+	require.NoError(t, p.Push())
+}
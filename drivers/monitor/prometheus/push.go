@@ -0,0 +1,135 @@
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/castai/promwrite"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/prometheus/common/expfmt"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// defaultPushJobName is the Pushgateway job of the metrics when the torpedo job name is not set
+const defaultPushJobName = "torpedo"
+
+// Push pushes the current metrics to the Pushgateway and remote-write endpoint, if set.
+// Pushes are serialized so that a periodic push never overtakes a later one.
+func (p *Prom) Push() error {
+	if p.pushGatewayURL == "" && p.remoteWriteURL == "" {
+		return nil
+	}
+	p.pushLock.Lock()
+	defer p.pushLock.Unlock()
+
+	var errs []string
+	if p.pushGatewayURL != "" {
+		if err := p.pushToGateway(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if p.remoteWriteURL != "" {
+		if err := p.pushRemoteWrite(time.Now()); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// pushToGateway replaces the metrics of the job group of the Pushgateway, so
+// that they outlive the torpedo process. The job type is a label of every metric,
+// so it can't also be a grouping label of the group.
+func (p *Prom) pushToGateway() error {
+	jobName := p.jobName
+	if jobName == "" {
+		jobName = defaultPushJobName
+	}
+	err := push.New(p.pushGatewayURL, jobName).
+		Gatherer(p.gatherer).
+		Client(p.client).
+		Format(expfmt.FmtText).
+		Push()
+	if err != nil {
+		return fmt.Errorf("failed to push metrics to pushgateway [%s]: %v", p.pushGatewayURL, err)
+	}
+	return nil
+}
+
+// pushRemoteWrite sends the metrics as samples at the given time to a remote-write endpoint
+func (p *Prom) pushRemoteWrite(now time.Time) error {
+	families, err := p.gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %v", err)
+	}
+	series := toTimeSeries(families, now)
+	if len(series) == 0 {
+		return nil
+	}
+	client := promwrite.NewClient(p.remoteWriteURL, promwrite.HttpClient(p.client))
+	if _, err = client.Write(context.Background(), &promwrite.WriteRequest{TimeSeries: series}); err != nil {
+		return fmt.Errorf("failed to push metrics to remote-write [%s]: %v", p.remoteWriteURL, err)
+	}
+	return nil
+}
+
+// toTimeSeries flattens metric families the way Prometheus stores them when
+// scraped: histograms and summaries become _bucket, _sum and _count series
+func toTimeSeries(families []*dto.MetricFamily, now time.Time) []promwrite.TimeSeries {
+	var series []promwrite.TimeSeries
+	for _, family := range families {
+		name := family.GetName()
+		for _, m := range family.GetMetric() {
+			add := func(suffix string, value float64, extra ...promwrite.Label) {
+				labels := []promwrite.Label{{Name: "__name__", Value: name + suffix}}
+				for _, lp := range m.GetLabel() {
+					labels = append(labels, promwrite.Label{Name: lp.GetName(), Value: lp.GetValue()})
+				}
+				labels = append(labels, extra...)
+				sort.Slice(labels, func(i, j int) bool {
+					return labels[i].Name < labels[j].Name
+				})
+				series = append(series, promwrite.TimeSeries{Labels: labels, Sample: promwrite.Sample{Time: now, Value: value}})
+			}
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add("", m.GetGauge().GetValue())
+			case dto.MetricType_UNTYPED:
+				add("", m.GetUntyped().GetValue())
+			case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+				h := m.GetHistogram()
+				for _, b := range h.GetBucket() {
+					add("_bucket", float64(b.GetCumulativeCount()), promwrite.Label{Name: "le", Value: formatFloat(b.GetUpperBound())})
+				}
+				add("_bucket", float64(h.GetSampleCount()), promwrite.Label{Name: "le", Value: "+Inf"})
+				add("_sum", h.GetSampleSum())
+				add("_count", float64(h.GetSampleCount()))
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				for _, q := range s.GetQuantile() {
+					add("", q.GetValue(), promwrite.Label{Name: "quantile", Value: formatFloat(q.GetQuantile())})
+				}
+				add("_sum", s.GetSampleSum())
+				add("_count", float64(s.GetSampleCount()))
+			}
+		}
+	}
+	return series
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
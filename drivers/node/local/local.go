@@ -0,0 +1,599 @@
+package local
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portworx/sched-ops/task"
+	"github.com/portworx/torpedo/drivers/node"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+const (
+	// DriverName is the name of the local node driver
+	DriverName = "local"
+	// TargetsEnv is the env var mapping nodes to targets, for e.g.
+	// "node-0=container:kind-worker,node-1=netns:ns1,node-2=host"
+	TargetsEnv = "TORPEDO_LOCAL_NODE_TARGETS"
+	// DefaultTargetTypeEnv is the env var with the target type of nodes not
+	// listed in TargetsEnv. The target name defaults to the node name.
+	DefaultTargetTypeEnv = "TORPEDO_LOCAL_DEFAULT_TARGET_TYPE"
+	// ContainerRuntimeEnv is the env var with the container CLI used for container targets
+	ContainerRuntimeEnv = "TORPEDO_LOCAL_CONTAINER_RUNTIME"
+	// RebootDurationEnv is the env var with how long an emulated reboot keeps the node down
+	RebootDurationEnv = "TORPEDO_LOCAL_REBOOT_DURATION"
+	// NetworkDeviceEnv is the env var with the network device used to inject network errors
+	NetworkDeviceEnv = "TORPEDO_LOCAL_NETWORK_DEVICE"
+
+	// DefaultContainerRuntime is the default container CLI
+	DefaultContainerRuntime = "docker"
+	// DefaultRebootDuration is the default duration of an emulated reboot
+	DefaultRebootDuration = 10 * time.Second
+	// DefaultNetworkDevice is the default network device used to inject network errors
+	DefaultNetworkDevice = "eth0"
+)
+
+// TargetType is the kind of local environment a node is mapped to
+type TargetType string
+
+const (
+	// TargetHost runs commands directly on the local host
+	TargetHost TargetType = "host"
+	// TargetContainer runs commands in a local container
+	TargetContainer TargetType = "container"
+	// TargetNetns runs commands in a local network namespace
+	TargetNetns TargetType = "netns"
+)
+
+// Target is the local environment a node is mapped to
+type Target struct {
+	Type TargetType
+	// Name is the name of the container or network namespace
+	Name string
+}
+
+// Local node driver running node operations through local exec against
+// containers, network namespaces or the local host. Reboots and crashes of
+// containers restart the container, while those of host and netns targets are
+// emulated: the node refuses commands for the reboot duration and reports the
+// emulated boot time afterwards.
+type Local struct {
+	node.Driver
+	sync.Mutex
+	targets           map[string]Target
+	defaultTargetType TargetType
+	containerRuntime  string
+	rebootDuration    time.Duration
+	networkDevice     string
+	// bootTimes records the boot time of nodes that went through an emulated reboot
+	bootTimes map[string]time.Time
+	// poweredOff tracks nodes shut down until they are powered on again
+	poweredOff map[string]bool
+}
+
+func (l *Local) String() string {
+	return DriverName
+}
+
+// Init reads the node to target mapping and settings from the environment
+func (l *Local) Init(nodeOpts node.InitOptions) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if targetType := os.Getenv(DefaultTargetTypeEnv); targetType != "" {
+		if err := validateTarget(Target{Type: TargetType(targetType)}); err != nil {
+			return err
+		}
+		l.defaultTargetType = TargetType(targetType)
+	}
+	if runtime := os.Getenv(ContainerRuntimeEnv); runtime != "" {
+		l.containerRuntime = runtime
+	}
+	if duration := os.Getenv(RebootDurationEnv); duration != "" {
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s [%s]. Err: %v", RebootDurationEnv, duration, err)
+		}
+		l.rebootDuration = d
+	}
+	if device := os.Getenv(NetworkDeviceEnv); device != "" {
+		l.networkDevice = device
+	}
+
+	targets, err := parseTargets(os.Getenv(TargetsEnv))
+	if err != nil {
+		return err
+	}
+	for name, target := range targets {
+		l.targets[name] = target
+	}
+	for _, n := range node.GetNodes() {
+		target := l.targetLocked(n)
+		log.Infof("Node [%s] is mapped to local %s target [%s]", n.Name, target.Type, target.Name)
+	}
+	return nil
+}
+
+// parseTargets parses a comma separated list of node=type[:name] entries
+func parseTargets(spec string) (map[string]Target, error) {
+	targets := make(map[string]Target)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, fmt.Errorf("invalid node target [%s] in %s, expected node=type[:name]", entry, TargetsEnv)
+		}
+		typeAndName := strings.SplitN(kv[1], ":", 2)
+		target := Target{Type: TargetType(typeAndName[0]), Name: kv[0]}
+		if len(typeAndName) == 2 {
+			target.Name = typeAndName[1]
+		}
+		if err := validateTarget(target); err != nil {
+			return nil, err
+		}
+		targets[kv[0]] = target
+	}
+	return targets, nil
+}
+
+func validateTarget(target Target) error {
+	switch target.Type {
+	case TargetHost, TargetContainer, TargetNetns:
+		return nil
+	default:
+		return fmt.Errorf("invalid local target type [%s], supported types: %s, %s, %s",
+			target.Type, TargetHost, TargetContainer, TargetNetns)
+	}
+}
+
+// SetTarget maps the node with the given name to the target
+func (l *Local) SetTarget(nodeName string, target Target) error {
+	if err := validateTarget(target); err != nil {
+		return err
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.targets[nodeName] = target
+	return nil
+}
+
+// GetTarget returns the target the node is mapped to
+func (l *Local) GetTarget(n node.Node) Target {
+	l.Lock()
+	defer l.Unlock()
+	return l.targetLocked(n)
+}
+
+func (l *Local) targetLocked(n node.Node) Target {
+	if target, ok := l.targets[n.Name]; ok {
+		return target
+	}
+	return Target{Type: l.defaultTargetType, Name: n.Name}
+}
+
+// IsUsingSSH returns false as commands are run through local exec
+func (l *Local) IsUsingSSH() bool {
+	return false
+}
+
+// TestConnection tests the given node accepts commands
+func (l *Local) TestConnection(n node.Node, options node.ConnectionOpts) error {
+	if _, err := l.RunCommand(n, "date", options); err != nil {
+		return &node.ErrFailedToTestConnection{
+			Node:  n,
+			Cause: err.Error(),
+		}
+	}
+	return nil
+}
+
+// RebootNode reboots the given node. Containers are restarted while other
+// targets go through an emulated reboot.
+func (l *Local) RebootNode(n node.Node, options node.RebootNodeOpts) error {
+	log.Infof("Rebooting node %s", n.Name)
+	if err := l.restart(n, options.Force); err != nil {
+		return &node.ErrFailedToRebootNode{
+			Node:  n,
+			Cause: err.Error(),
+		}
+	}
+	return nil
+}
+
+// RebootNodeAndWait reboots the given node and waits for it to accept commands again
+func (l *Local) RebootNodeAndWait(n node.Node) error {
+	if err := l.RebootNode(n, node.RebootNodeOpts{Force: true}); err != nil {
+		return err
+	}
+	return l.TestConnection(n, node.ConnectionOpts{
+		Timeout:         l.rebootDuration + time.Minute,
+		TimeBeforeRetry: time.Second,
+	})
+}
+
+// CrashNode crashes the given node. Containers are killed and started again
+// while other targets go through an emulated reboot.
+func (l *Local) CrashNode(n node.Node, options node.CrashNodeOpts) error {
+	log.Infof("Crashing node %s", n.Name)
+	if err := l.restart(n, true); err != nil {
+		return &node.ErrFailedToCrashNode{
+			Node:  n,
+			Cause: err.Error(),
+		}
+	}
+	return nil
+}
+
+func (l *Local) restart(n node.Node, force bool) error {
+	target := l.GetTarget(n)
+	if target.Type == TargetContainer {
+		if force {
+			if _, err := l.runtimeCmd("kill", target.Name); err != nil {
+				return err
+			}
+			_, err := l.runtimeCmd("start", target.Name)
+			return err
+		}
+		_, err := l.runtimeCmd("restart", target.Name)
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+	l.bootTimes[n.Name] = time.Now().Add(l.rebootDuration)
+	delete(l.poweredOff, n.Name)
+	return nil
+}
+
+// ShutdownNode shuts down the given node until it is powered on with PowerOnVM
+func (l *Local) ShutdownNode(n node.Node, options node.ShutdownNodeOpts) error {
+	if err := l.PowerOffVM(n); err != nil {
+		return &node.ErrFailedToShutdownNode{
+			Node:  n,
+			Cause: err.Error(),
+		}
+	}
+	return nil
+}
+
+// PowerOffVM stops the container or marks the node powered off
+func (l *Local) PowerOffVM(n node.Node) error {
+	target := l.GetTarget(n)
+	if target.Type == TargetContainer {
+		_, err := l.runtimeCmd("stop", target.Name)
+		return err
+	}
+	l.Lock()
+	defer l.Unlock()
+	l.poweredOff[n.Name] = true
+	return nil
+}
+
+// PowerOnVM starts the container or brings a powered off node back up
+func (l *Local) PowerOnVM(n node.Node) error {
+	target := l.GetTarget(n)
+	if target.Type == TargetContainer {
+		_, err := l.runtimeCmd("start", target.Name)
+		return err
+	}
+	l.Lock()
+	defer l.Unlock()
+	if l.poweredOff[n.Name] {
+		delete(l.poweredOff, n.Name)
+		l.bootTimes[n.Name] = time.Now()
+	}
+	return nil
+}
+
+// GetNodeState returns "Running" or "Stopped"
+func (l *Local) GetNodeState(n node.Node) (string, error) {
+	target := l.GetTarget(n)
+	if target.Type == TargetContainer {
+		out, err := l.runtimeCmd("inspect", "-f", "{{.State.Running}}", target.Name)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(out) == "true" {
+			return "Running", nil
+		}
+		return "Stopped", nil
+	}
+	if err := l.checkUp(n); err != nil {
+		return "Stopped", nil
+	}
+	return "Running", nil
+}
+
+// IsNodeRebootedInGivenTimeRange return true if node rebooted in given time range
+func (l *Local) IsNodeRebootedInGivenTimeRange(n node.Node, timerange time.Duration) (bool, error) {
+	bootTime, err := l.getBootTime(n)
+	if err != nil {
+		return false, &node.ErrFailedToRunCommand{
+			Node:  n,
+			Addr:  n.Name,
+			Cause: fmt.Sprintf("failed to get boot time of node %v: %v", n.Name, err),
+		}
+	}
+	return time.Since(bootTime) <= timerange, nil
+}
+
+func (l *Local) getBootTime(n node.Node) (time.Time, error) {
+	l.Lock()
+	bootTime, emulated := l.bootTimes[n.Name]
+	l.Unlock()
+	if emulated {
+		return bootTime, nil
+	}
+
+	target := l.GetTarget(n)
+	if target.Type == TargetContainer {
+		out, err := l.runtimeCmd("inspect", "-f", "{{.State.StartedAt}}", target.Name)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Parse(time.RFC3339Nano, strings.TrimSpace(out))
+	}
+	out, err := l.doCmd(n, "uptime -s", false)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.ParseInLocation("2006-01-02 15:04:05", strings.TrimSpace(out), time.Local)
+}
+
+// RunCommand runs given command on given node
+func (l *Local) RunCommand(n node.Node, command string, options node.ConnectionOpts) (string, error) {
+	t := func() (interface{}, bool, error) {
+		output, err := l.doCmd(n, command, options.IgnoreError)
+		if err != nil {
+			return "", true, err
+		}
+		return output, false, nil
+	}
+
+	output, err := task.DoRetryWithTimeout(t, options.Timeout, options.TimeBeforeRetry)
+	if err != nil {
+		return "", err
+	}
+	return output.(string), nil
+}
+
+// RunCommandWithNoRetry runs given command on given node but with no retries
+func (l *Local) RunCommandWithNoRetry(n node.Node, command string, options node.ConnectionOpts) (string, error) {
+	return l.doCmd(n, command, options.IgnoreError)
+}
+
+// FindFiles finds files from give path on given node
+func (l *Local) FindFiles(path string, n node.Node, options node.FindOpts) (string, error) {
+	findCmd := "find " + path
+	if options.Name != "" {
+		findCmd += " -name " + options.Name
+	}
+	if options.MinDepth > 0 {
+		findCmd += " -mindepth " + strconv.Itoa(options.MinDepth)
+	}
+	if options.MaxDepth > 0 {
+		findCmd += " -maxdepth " + strconv.Itoa(options.MaxDepth)
+	}
+	if options.Type != "" {
+		findCmd += " -type " + string(options.Type)
+	}
+	if options.Empty {
+		findCmd += " -empty"
+	}
+
+	t := func() (interface{}, bool, error) {
+		out, err := l.doCmd(n, findCmd, true)
+		return out, true, err
+	}
+
+	out, err := task.DoRetryWithTimeout(t,
+		options.ConnectionOpts.Timeout,
+		options.ConnectionOpts.TimeBeforeRetry)
+
+	if err != nil {
+		return "", &node.ErrFailedToFindFileOnNode{
+			Node:  n,
+			Cause: err.Error(),
+		}
+	}
+	return out.(string), nil
+}
+
+// Systemctl allows to run systemctl commands on a give node
+func (l *Local) Systemctl(n node.Node, service string, options node.SystemctlOpts) error {
+	systemctlCmd := fmt.Sprintf("systemctl %v %v", options.Action, service)
+	t := func() (interface{}, bool, error) {
+		out, err := l.doCmd(n, systemctlCmd, false)
+		if err != nil {
+			return out, true, err
+		}
+		return out, false, nil
+	}
+
+	if _, err := task.DoRetryWithTimeout(t,
+		options.ConnectionOpts.Timeout,
+		options.ConnectionOpts.TimeBeforeRetry); err != nil {
+		return &node.ErrFailedToRunSystemctlOnNode{
+			Node:  n,
+			Cause: err.Error(),
+		}
+	}
+	return nil
+}
+
+// SystemctlUnitExist checks if a given service exists on the node
+func (l *Local) SystemctlUnitExist(n node.Node, service string, options node.SystemctlOpts) (bool, error) {
+	systemctlCmd := fmt.Sprintf("systemctl list-units --full --all | grep \"%s.service\" || true", service)
+	out, err := l.RunCommand(n, systemctlCmd, options.ConnectionOpts)
+	if err != nil {
+		return false, &node.ErrFailedToRunSystemctlOnNode{
+			Node:  n,
+			Cause: err.Error(),
+		}
+	}
+	return len(out) > 0, nil
+}
+
+// InjectNetworkError by dropping packets or introdiucing delay in packet tramission
+// nodes=> list of nodes where network injection should be done.
+// errorInjectionType => pass "delay" or "drop"
+// operationType => add/change/delete
+// dropPercentage => intger value from 1 to 100
+// delayInMilliseconds => 1 to 1000
+func (l *Local) InjectNetworkError(nodes []node.Node, errorInjectionType string, operationType string, dropPercentage int, delayInMilliseconds int) error {
+	var netem string
+	switch errorInjectionType {
+	case "delay":
+		netem = "delay " + strconv.Itoa(delayInMilliseconds) + "ms"
+	case "drop":
+		netem = "loss " + strconv.Itoa(dropPercentage) + "%"
+	default:
+		return fmt.Errorf("invalid network error injection type %v", errorInjectionType)
+	}
+	cmd := fmt.Sprintf("tc qdisc %s dev %s root netem %s", operationType, l.networkDevice, netem)
+	if operationType == "del" || operationType == "delete" {
+		cmd = fmt.Sprintf("tc qdisc %s dev %s root netem", operationType, l.networkDevice)
+	}
+
+	for _, n := range nodes {
+		log.Infof("Error injection on Node name : %s injection cmd: %s ", n.Name, cmd)
+		if _, err := l.doCmd(n, cmd, false); err != nil {
+			return &node.ErrFailedToSetNetworkErrorOnNode{
+				Node:  n,
+				Cause: err.Error(),
+			}
+		}
+	}
+	return nil
+}
+
+// InjectNetworkErrorWithRebootFallback injects the network error and, if a node
+// stops accepting commands, reboots it and tries again
+func (l *Local) InjectNetworkErrorWithRebootFallback(nodes []node.Node, errorInjectionType string, operationType string, dropPercentage int, delayInMilliseconds int) error {
+	for _, n := range nodes {
+		err := l.InjectNetworkError([]node.Node{n}, errorInjectionType, operationType, dropPercentage, delayInMilliseconds)
+		if err == nil {
+			continue
+		}
+		log.Infof("Node %s is unreachable. Rebooting the node to recover", n.Name)
+		if rebootErr := l.RebootNodeAndWait(n); rebootErr != nil {
+			return fmt.Errorf("failed to reboot node %s: %v", n.Name, rebootErr)
+		}
+		if err = l.InjectNetworkError([]node.Node{n}, errorInjectionType, operationType, dropPercentage, delayInMilliseconds); err != nil {
+			return fmt.Errorf("failed to inject network error on node [%s] after reboot. Err: [%v]", n.Name, err)
+		}
+	}
+	return nil
+}
+
+// SystemCheck check if any cores are generated on given node
+func (l *Local) SystemCheck(n node.Node, options node.ConnectionOpts) (string, error) {
+	findOpts := node.FindOpts{
+		ConnectionOpts: options,
+		Name:           "core-px*",
+		Type:           node.File,
+	}
+	file, err := l.FindFiles("/var/cores/", n, findOpts)
+	if err != nil {
+		return "", &node.ErrFailedToSystemCheck{
+			Node:  n,
+			Cause: fmt.Sprintf("failed to check for core files due to: %v", err),
+		}
+	}
+	return file, nil
+}
+
+// checkUp returns an error if the node is powered off or in an emulated reboot
+func (l *Local) checkUp(n node.Node) error {
+	l.Lock()
+	defer l.Unlock()
+	if l.poweredOff[n.Name] {
+		return fmt.Errorf("node %s is powered off", n.Name)
+	}
+	if bootTime, ok := l.bootTimes[n.Name]; ok && time.Now().Before(bootTime) {
+		return fmt.Errorf("node %s is rebooting", n.Name)
+	}
+	return nil
+}
+
+// doCmd runs the command with bash on the target of the node
+func (l *Local) doCmd(n node.Node, cmd string, ignoreErr bool) (string, error) {
+	target := l.GetTarget(n)
+	if target.Type != TargetContainer {
+		if err := l.checkUp(n); err != nil {
+			return "", &node.ErrFailedToRunCommand{
+				Node:  n,
+				Addr:  n.Name,
+				Cause: err.Error(),
+			}
+		}
+	}
+
+	var args []string
+	switch target.Type {
+	case TargetContainer:
+		args = []string{l.containerRuntime, "exec", target.Name, "/bin/bash", "-c", cmd}
+	case TargetNetns:
+		args = []string{"ip", "netns", "exec", target.Name, "/bin/bash", "-c", cmd}
+	default:
+		args = []string{"/bin/bash", "-c", cmd}
+	}
+	log.Debugf("Running command on node %s [%s]", n.Name, strings.Join(args, " "))
+	out, err := run(args...)
+	if !ignoreErr && err != nil {
+		return out, &node.ErrFailedToRunCommand{
+			Node:  n,
+			Addr:  n.Name,
+			Cause: fmt.Sprintf("failed to run command [%s]. err: %v", cmd, err),
+		}
+	}
+	return out, nil
+}
+
+// runtimeCmd runs the container CLI with the given arguments
+func (l *Local) runtimeCmd(args ...string) (string, error) {
+	out, err := run(append([]string{l.containerRuntime}, args...)...)
+	if err != nil {
+		return out, fmt.Errorf("failed to run [%s %s]. err: %v", l.containerRuntime, strings.Join(args, " "), err)
+	}
+	return out, nil
+}
+
+// run runs the command and returns its stdout. Stderr is included in the error.
+func run(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command(args[0], args[1:]...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("%v, stderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// New returns a new local node driver
+func New() *Local {
+	return &Local{
+		Driver:            node.NotSupportedDriver,
+		targets:           make(map[string]Target),
+		defaultTargetType: TargetHost,
+		containerRuntime:  DefaultContainerRuntime,
+		rebootDuration:    DefaultRebootDuration,
+		networkDevice:     DefaultNetworkDevice,
+		bootTimes:         make(map[string]time.Time),
+		poweredOff:        make(map[string]bool),
+	}
+}
+
+func init() {
+	node.Register(DriverName, New())
+}
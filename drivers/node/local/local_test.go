@@ -0,0 +1,51 @@
+package local
+
+import (
+	"testing"
+	"time"
+
+	"github.com/portworx/torpedo/drivers/node"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTargets(t *testing.T) {
+	targets, err := parseTargets("node-0=container:kind-worker, node-1=netns,node-2=host")
+	require.NoError(t, err)
+	require.Equal(t, Target{Type: TargetContainer, Name: "kind-worker"}, targets["node-0"])
+	require.Equal(t, Target{Type: TargetNetns, Name: "node-1"}, targets["node-1"])
+	require.Equal(t, Target{Type: TargetHost, Name: "node-2"}, targets["node-2"])
+
+	_, err = parseTargets("node-0=vm:foo")
+	require.Error(t, err)
+	_, err = parseTargets("node-0")
+	require.Error(t, err)
+}
+
+func TestEmulatedReboot(t *testing.T) {
+	l := New()
+	l.rebootDuration = 200 * time.Millisecond
+	n := node.Node{Name: "node-0"}
+	opts := node.ConnectionOpts{Timeout: time.Second, TimeBeforeRetry: 50 * time.Millisecond}
+
+	out, err := l.RunCommand(n, "echo hello", opts)
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", out)
+
+	require.NoError(t, l.RebootNode(n, node.RebootNodeOpts{}))
+	_, err = l.RunCommandWithNoRetry(n, "echo hello", opts)
+	require.Error(t, err)
+	require.NoError(t, l.TestConnection(n, opts))
+
+	rebooted, err := l.IsNodeRebootedInGivenTimeRange(n, time.Minute)
+	require.NoError(t, err)
+	require.True(t, rebooted)
+
+	require.NoError(t, l.ShutdownNode(n, node.ShutdownNodeOpts{}))
+	state, err := l.GetNodeState(n)
+	require.NoError(t, err)
+	require.Equal(t, "Stopped", state)
+	require.NoError(t, l.PowerOnVM(n))
+	state, err = l.GetNodeState(n)
+	require.NoError(t, err)
+	require.Equal(t, "Running", state)
+}
@@ -23,6 +23,7 @@ import (
 	volumedriver "github.com/portworx/torpedo/drivers/volume"
 	"github.com/portworx/torpedo/drivers/volume/portworx/schedops"
 	"github.com/portworx/torpedo/pkg/log"
+	"github.com/portworx/torpedo/pkg/tracing"
 	ssh_pkg "golang.org/x/crypto/ssh"
 	appsv1_api "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
@@ -583,7 +584,9 @@ func (s *SSH) RecoverDrive(n node.Node, driveNameToRecover string, driveUUIDToRe
 }
 
 // RunCommand runs given command on given node
-func (s *SSH) RunCommand(n node.Node, command string, options node.ConnectionOpts) (string, error) {
+func (s *SSH) RunCommand(n node.Node, command string, options node.ConnectionOpts) (_ string, err error) {
+	span := tracing.Start("ssh.RunCommand", tracing.Driver(s.String()), tracing.Node(n.Name))
+	defer span.FinishWith(&err)
 	t := func() (interface{}, bool, error) {
 		output, err := s.doCmd(n, options, command, options.IgnoreError)
 		if err != nil {
@@ -595,7 +598,7 @@ func (s *SSH) RunCommand(n node.Node, command string, options node.ConnectionOpt
 		return output, false, nil
 	}
 
-	output, err := task.DoRetryWithTimeout(t, options.Timeout, options.TimeBeforeRetry)
+	output, err := task.DoRetryWithTimeout(span.Retries(t), options.Timeout, options.TimeBeforeRetry)
 	if err != nil {
 		return "", err
 	}
@@ -770,7 +773,9 @@ func (s *SSH) doCmdUsingPodWithoutRetry(n node.Node, cmd string) (string, error)
 	return k8sCore.RunCommandInPod(cmds, debugPod.Name, "", debugPod.Namespace)
 }
 
-func (s *SSH) doCmdUsingPod(n node.Node, options node.ConnectionOpts, cmd string, ignoreErr bool) (string, error) {
+func (s *SSH) doCmdUsingPod(n node.Node, options node.ConnectionOpts, cmd string, ignoreErr bool) (_ string, err error) {
+	span := tracing.Start("ssh.doCmdUsingPod", tracing.Node(n.Name))
+	defer span.FinishWith(&err)
 	var debugPod *v1.Pod
 	t := func() (interface{}, bool, error) {
 		if debugPod == nil {
@@ -806,14 +811,16 @@ func (s *SSH) doCmdUsingPod(n node.Node, options node.ConnectionOpts, cmd string
 		return output, false, nil
 	}
 
-	output, err := task.DoRetryWithTimeout(t, options.Timeout, options.TimeBeforeRetry)
+	output, err := task.DoRetryWithTimeout(span.Retries(t), options.Timeout, options.TimeBeforeRetry)
 	if err != nil {
 		return "", err
 	}
 	return output.(string), nil
 }
 
-func (s *SSH) doCmdSSH(n node.Node, options node.ConnectionOpts, cmd string, ignoreErr bool) (string, error) {
+func (s *SSH) doCmdSSH(n node.Node, options node.ConnectionOpts, cmd string, ignoreErr bool) (_ string, err error) {
+	span := tracing.Start("ssh.doCmdSSH", tracing.Node(n.Name))
+	defer span.FinishWith(&err)
 	var out string
 	var sterr string
 	connection, err := s.getConnection(n, options)
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	pdsdriver "github.com/portworx/torpedo/drivers/pds"
 	"github.com/portworx/torpedo/pkg/log"
 	v1 "k8s.io/api/apps/v1"
@@ -237,53 +238,65 @@ func createPolicies(namespace string) (*corev1.ServiceAccount, error) {
 	return serviceAccount, nil
 }
 
-// InsertDataAndReturnChecksum Inserts Data into the db and returns the checksum
-func (ds *DataserviceType) InsertDataAndReturnChecksum(pdsDeployment *pds.ModelsDeployment, wkloadGenParams pdsdriver.LoadGenParams) (string, *v1.Deployment, error) {
+// InsertDataAndReturnSnapshot Inserts Data into the db and returns the integrity snapshot of the data
+func (ds *DataserviceType) InsertDataAndReturnSnapshot(pdsDeployment *pds.ModelsDeployment, wkloadGenParams pdsdriver.LoadGenParams) (*integrity.Snapshot, *v1.Deployment, error) {
 	wkloadGenParams.Mode = "write"
 	_, dep, err := ds.GenerateWorkload(pdsDeployment, wkloadGenParams)
 	if err == nil {
 		err := k8sApps.DeleteDeployment(dep.Name, dep.Namespace)
 		if err != nil {
-			return "", nil, fmt.Errorf("error while deleting the workload deployment")
+			return nil, nil, fmt.Errorf("error while deleting the workload deployment")
 		}
 	}
-	ckSum, wlDep, err := ds.ReadDataAndReturnChecksum(pdsDeployment, wkloadGenParams)
-	return ckSum, wlDep, err
+	return ds.ReadDataAndReturnSnapshot(pdsDeployment, wkloadGenParams)
 }
 
-// ReadDataAndReturnChecksum Reads Data from the db and returns the checksum
-func (ds *DataserviceType) ReadDataAndReturnChecksum(pdsDeployment *pds.ModelsDeployment, wkloadGenParams pdsdriver.LoadGenParams) (string, *v1.Deployment, error) {
+// ReadDataAndReturnSnapshot Reads Data from the db and returns the integrity snapshot of the data, the table
+// checksum computed by the workload being recorded as the checksum of the table
+func (ds *DataserviceType) ReadDataAndReturnSnapshot(pdsDeployment *pds.ModelsDeployment, wkloadGenParams pdsdriver.LoadGenParams) (*integrity.Snapshot, *v1.Deployment, error) {
 	wkloadGenParams.Mode = "read"
 	ckSum, wlDep, err := ds.GenerateWorkload(pdsDeployment, wkloadGenParams)
-	return ckSum, wlDep, err
-}
-
-// ValidateDataMd5Hash validates the hash of the data service deployments
-func (ds *DataserviceType) ValidateDataMd5Hash(deploymentHash, restoredDepHash map[string]string) bool {
-	count := 0
-
-	//Debug block to print hash of the database table
-	for depName, hash := range deploymentHash {
-		log.Debugf("Dep name %s and hash %s", depName, hash)
-	}
-	for depName, hash := range restoredDepHash {
-		log.Debugf("Restored Dep name %s and hash %s", depName, hash)
+	if err != nil {
+		return nil, wlDep, err
 	}
+	// the workload only reports the checksum of the table, not its number of rows
+	snapshot := integrity.NewSnapshot(pdsDeployment.GetClusterResourceName(), wkloadGenParams.Namespace)
+	snapshot.Objects[integrity.ObjectPrefix+wkloadGenParams.TableName] = integrity.Checksum{Hash: ckSum}
+	return snapshot, wlDep, nil
+}
 
-	for key, depHash := range deploymentHash {
-		depName, _, _ := strings.Cut(key, "-")
-		for key1, resDepHash := range restoredDepHash {
-			resDepName, _, _ := strings.Cut(key1, "-")
-			if depName == resDepName && depHash == resDepHash {
-				log.InfoD("data is consistent for restored deployment %s", key1)
-				count += 1
+// ValidateDataIntegrity compares the snapshots of the restored data service deployments with the snapshots of the
+// deployments they were restored from, matched by the name of the deployment without its generated suffix
+func (ds *DataserviceType) ValidateDataIntegrity(deploymentSnapshots, restoredDepSnapshots map[string]*integrity.Snapshot) error {
+	var errs []string
+	for resKey, restored := range restoredDepSnapshots {
+		resDepName, _, _ := strings.Cut(resKey, "-")
+		var mismatches []string
+		matched := false
+		for key, snapshot := range deploymentSnapshots {
+			depName, _, _ := strings.Cut(key, "-")
+			if depName != resDepName {
+				continue
+			}
+			if err := restored.Compare(snapshot); err != nil {
+				mismatches = append(mismatches, fmt.Sprintf("%s: %v", key, err))
+				continue
+			}
+			log.InfoD("data is consistent for restored deployment %s", resKey)
+			matched = true
+			break
+		}
+		if !matched {
+			if len(mismatches) == 0 {
+				mismatches = append(mismatches, "no source deployment found")
 			}
+			errs = append(errs, fmt.Sprintf("restored deployment %s [%s]", resKey, strings.Join(mismatches, "; ")))
 		}
 	}
-	if count < len(restoredDepHash) {
-		return false
+	if len(errs) > 0 {
+		return fmt.Errorf("data integrity validation failed for %s", strings.Join(errs, ", "))
 	}
-	return true
+	return nil
 }
 
 // GenerateWorkload creates a deployment using the given params(perform read/write) and returns the checksum
@@ -3,6 +3,7 @@ package pds
 import (
 	"fmt"
 	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	pdsapi "github.com/portworx/torpedo/drivers/pds/api"
 	pdscontrolplane "github.com/portworx/torpedo/drivers/pds/controlplane"
 	"github.com/portworx/torpedo/drivers/scheduler"
@@ -42,11 +43,11 @@ type Driver interface {
 	//ValidateDataServiceDeployment Validate the PDS deployments
 	ValidateDataServiceDeployment(deployment *pds.ModelsDeployment, namespace string) error
 
-	//InsertDataAndReturnChecksum Inserts data and returns md5 hash for the data inserted
-	InsertDataAndReturnChecksum(pdsDeployment *pds.ModelsDeployment, wkloadGenParams LoadGenParams) (string, *v1.Deployment, error)
+	//InsertDataAndReturnSnapshot Inserts data and returns the integrity snapshot of the data inserted
+	InsertDataAndReturnSnapshot(pdsDeployment *pds.ModelsDeployment, wkloadGenParams LoadGenParams) (*integrity.Snapshot, *v1.Deployment, error)
 
-	//ReadDataAndReturnChecksum Reads data and returns md5 hash for the data
-	ReadDataAndReturnChecksum(pdsDeployment *pds.ModelsDeployment, wkloadGenParams LoadGenParams) (string, *v1.Deployment, error)
+	//ReadDataAndReturnSnapshot Reads data and returns the integrity snapshot of the data
+	ReadDataAndReturnSnapshot(pdsDeployment *pds.ModelsDeployment, wkloadGenParams LoadGenParams) (*integrity.Snapshot, *v1.Deployment, error)
 }
 
 var (
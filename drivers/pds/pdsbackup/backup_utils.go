@@ -319,7 +319,7 @@ func (backupClient *BackupClient) TriggerAndValidateAdhocBackup(deploymentID str
 	log.Infof("Created adhoc backup. Details: deployment- %v,backup type - %v, backup resource name: %v, backupObj id: %v", bkpObj.GetDeploymentName(),
 		bkpObj.GetBackupType(), bkpObj.GetClusterResourceName(), bkpObj.GetId())
 
-	waitErr := wait.Poll(bkpTimeInterval, bkpMaxtimeInterval, func() (bool, error) {
+	waitErr := wait.PollImmediate(bkpTimeInterval, bkpMaxtimeInterval, func() (bool, error) {
 		bkpJobs, err = backupClient.Components.BackupJob.ListBackupJobs(bkpObj.GetId())
 		if err != nil {
 			return false, err
@@ -0,0 +1,1373 @@
+package pdsmock
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+)
+
+const (
+	apiVersion = "pdsmock"
+
+	deploymentAvailable   = "Available"
+	deploymentUnavailable = "Unavailable"
+	backupJobRunning      = "Running"
+	backupJobSucceeded    = "Succeeded"
+	restoreRunning        = "Running"
+	restoreSuccessful     = "Successful"
+	backupTargetSynced    = "successful"
+	adhocBackup           = "adhoc"
+)
+
+// page is the body of the list requests
+type page struct {
+	Data interface{} `json:"data"`
+}
+
+// loginResponse is the body of the login request, as read by the api wrappers
+type loginResponse struct {
+	SUCCESS bool `json:"SUCCESS"`
+	DATA    struct {
+		Token string `json:"token"`
+	} `json:"DATA"`
+}
+
+func (s *Server) registerRoutes() {
+	s.handle(http.MethodPost, "/login", s.login)
+	s.handle(http.MethodGet, "/api/version", s.getAPIVersion)
+
+	s.handle(http.MethodGet, "/api/accounts", s.listAccounts)
+	s.handle(http.MethodGet, "/api/accounts/{id}", s.getAccount)
+	s.handle(http.MethodGet, "/api/accounts/{id}/users", s.listAccountUsers)
+	s.handle(http.MethodPut, "/api/accounts/{id}/eula", s.acceptEULA)
+	s.handle(http.MethodGet, "/api/accounts/{id}/tenants", s.listAccountTenants)
+
+	s.handle(http.MethodGet, "/api/tenants/{id}", s.getTenant)
+	s.handle(http.MethodGet, "/api/tenants/{id}/dns-details", s.getTenantDNSDetails)
+	s.handle(http.MethodGet, "/api/tenants/{id}/projects", s.listTenantProjects)
+	s.handle(http.MethodGet, "/api/projects/{id}", s.getProject)
+
+	s.handle(http.MethodGet, "/api/tenants/{id}/deployment-targets", s.listTenantDeploymentTargets)
+	s.handle(http.MethodGet, "/api/projects/{id}/deployment-targets", s.listProjectDeploymentTargets)
+	s.handle(http.MethodGet, "/api/deployment-targets/{id}", s.getDeploymentTarget)
+	s.handle(http.MethodPut, "/api/deployment-targets/{id}", s.updateDeploymentTarget)
+	s.handle(http.MethodPatch, "/api/deployment-targets/{id}", s.patchDeploymentTarget)
+	s.handle(http.MethodDelete, "/api/deployment-targets/{id}", s.deleteDeploymentTarget)
+	s.handle(http.MethodGet, "/api/deployment-targets/{id}/namespaces", s.listNamespaces)
+	s.handle(http.MethodPost, "/api/deployment-targets/{id}/namespaces", s.createNamespace)
+	s.handle(http.MethodGet, "/api/namespaces/{id}", s.getNamespace)
+	s.handle(http.MethodDelete, "/api/namespaces/{id}", s.deleteNamespace)
+
+	s.handle(http.MethodGet, "/api/data-services", s.listDataServices)
+	s.handle(http.MethodGet, "/api/data-services/{id}", s.getDataService)
+	s.handle(http.MethodGet, "/api/data-services/{id}/versions", s.listVersions)
+	s.handle(http.MethodGet, "/api/versions/{id}", s.getVersion)
+	s.handle(http.MethodGet, "/api/versions/{id}/images", s.listImages)
+	s.handle(http.MethodGet, "/api/images/{id}", s.getImage)
+
+	s.handle(http.MethodGet, "/api/tenants/{id}/resource-settings-templates", s.listResourceTemplates)
+	s.handle(http.MethodPost, "/api/tenants/{id}/resource-settings-templates", s.createResourceTemplate)
+	s.handle(http.MethodGet, "/api/resource-settings-templates/{id}", s.getResourceTemplate)
+	s.handle(http.MethodPut, "/api/resource-settings-templates/{id}", s.updateResourceTemplate)
+	s.handle(http.MethodDelete, "/api/resource-settings-templates/{id}", s.deleteResourceTemplate)
+	s.handle(http.MethodGet, "/api/tenants/{id}/storage-options-templates", s.listStorageTemplates)
+	s.handle(http.MethodPost, "/api/tenants/{id}/storage-options-templates", s.createStorageTemplate)
+	s.handle(http.MethodGet, "/api/storage-options-templates/{id}", s.getStorageTemplate)
+	s.handle(http.MethodPut, "/api/storage-options-templates/{id}", s.updateStorageTemplate)
+	s.handle(http.MethodDelete, "/api/storage-options-templates/{id}", s.deleteStorageTemplate)
+	s.handle(http.MethodGet, "/api/tenants/{id}/application-configuration-templates", s.listAppConfigTemplates)
+	s.handle(http.MethodPost, "/api/tenants/{id}/application-configuration-templates", s.createAppConfigTemplate)
+	s.handle(http.MethodGet, "/api/application-configuration-templates/{id}", s.getAppConfigTemplate)
+	s.handle(http.MethodPut, "/api/application-configuration-templates/{id}", s.updateAppConfigTemplate)
+	s.handle(http.MethodDelete, "/api/application-configuration-templates/{id}", s.deleteAppConfigTemplate)
+
+	s.handle(http.MethodGet, "/api/tenants/{id}/backup-credentials", s.listBackupCredentials)
+	s.handle(http.MethodPost, "/api/tenants/{id}/backup-credentials", s.createBackupCredentials)
+	s.handle(http.MethodGet, "/api/backup-credentials/{id}", s.getBackupCredentials)
+	s.handle(http.MethodPut, "/api/backup-credentials/{id}", s.updateBackupCredentials)
+	s.handle(http.MethodDelete, "/api/backup-credentials/{id}", s.deleteBackupCredentials)
+	s.handle(http.MethodGet, "/api/backup-credentials/{id}/credentials", s.getPartialCredentials)
+
+	s.handle(http.MethodGet, "/api/tenants/{id}/backup-targets", s.listTenantBackupTargets)
+	s.handle(http.MethodPost, "/api/tenants/{id}/backup-targets", s.createBackupTarget)
+	s.handle(http.MethodGet, "/api/projects/{id}/backup-targets", s.listProjectBackupTargets)
+	s.handle(http.MethodGet, "/api/backup-targets/{id}", s.getBackupTarget)
+	s.handle(http.MethodPut, "/api/backup-targets/{id}", s.updateBackupTarget)
+	s.handle(http.MethodDelete, "/api/backup-targets/{id}", s.deleteBackupTarget)
+	s.handle(http.MethodGet, "/api/backup-targets/{id}/states", s.listBackupTargetStates)
+	s.handle(http.MethodPost, "/api/backup-targets/{id}/retry", s.syncBackupTarget)
+	s.handle(http.MethodGet, "/api/backup-targets/{id}/backups", s.listBackupTargetBackups)
+
+	s.handle(http.MethodGet, "/api/projects/{id}/deployments", s.listDeployments)
+	s.handle(http.MethodPost, "/api/projects/{id}/deployments", s.createDeployment)
+	s.handle(http.MethodPost, "/api/deployments", s.createDeployment)
+	s.handle(http.MethodGet, "/api/deployments/{id}", s.getDeployment)
+	s.handle(http.MethodPut, "/api/deployments/{id}", s.updateDeployment)
+	s.handle(http.MethodDelete, "/api/deployments/{id}", s.deleteDeployment)
+	s.handle(http.MethodGet, "/api/deployments/{id}/status", s.getDeploymentStatus)
+	s.handle(http.MethodGet, "/api/deployments/{id}/connection-info", s.getConnectionInfo)
+	s.handle(http.MethodGet, "/api/deployments/{id}/credentials", s.getDeploymentCredentials)
+
+	s.handle(http.MethodGet, "/api/deployments/{id}/backups", s.listDeploymentBackups)
+	s.handle(http.MethodPost, "/api/deployments/{id}/backups", s.createBackup)
+	s.handle(http.MethodGet, "/api/backups/{id}", s.getBackup)
+	s.handle(http.MethodPut, "/api/backups/{id}", s.updateBackup)
+	s.handle(http.MethodDelete, "/api/backups/{id}", s.deleteBackup)
+	s.handle(http.MethodGet, "/api/backups/{id}/jobs", s.listBackupJobStatuses)
+	s.handle(http.MethodDelete, "/api/backups/{id}/jobs/{name}", s.deleteBackupJobByName)
+	s.handle(http.MethodGet, "/api/projects/{id}/backup-jobs", s.listBackupJobs)
+	s.handle(http.MethodGet, "/api/backup-jobs/{id}", s.getBackupJob)
+	s.handle(http.MethodDelete, "/api/backup-jobs/{id}", s.deleteBackupJob)
+
+	s.handle(http.MethodPost, "/api/backup-jobs/{id}/restore", s.createRestore)
+	s.handle(http.MethodGet, "/api/restores/restorability-matrix", s.getRestorabilityMatrix)
+	s.handle(http.MethodGet, "/api/restores/{id}", s.getRestore)
+	s.handle(http.MethodPost, "/api/restores/{id}/retry", s.retryRestore)
+}
+
+func (s *Server) login(r *http.Request, params map[string]string) (int, interface{}) {
+	response := loginResponse{SUCCESS: true}
+	response.DATA.Token = Token
+	return http.StatusOK, response
+}
+
+func (s *Server) getAPIVersion(r *http.Request, params map[string]string) (int, interface{}) {
+	return http.StatusOK, pds.ControllersAPIVersionResponse{
+		ApiVersion:       pds.PtrString(apiVersion),
+		HelmChartVersion: pds.PtrString(apiVersion),
+	}
+}
+
+// Accounts, tenants and projects
+
+func (s *Server) listAccounts(r *http.Request, params map[string]string) (int, interface{}) {
+	return http.StatusOK, page{Data: s.accounts.list(nil)}
+}
+
+func (s *Server) getAccount(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.accounts, "account", params["id"])
+}
+
+func (s *Server) listAccountUsers(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.accounts.get(params["id"]); !ok {
+		return notFound("account", params["id"])
+	}
+	return http.StatusOK, page{Data: s.users.list(nil)}
+}
+
+func (s *Server) acceptEULA(r *http.Request, params map[string]string) (int, interface{}) {
+	account, ok := s.accounts.get(params["id"])
+	if !ok {
+		return notFound("account", params["id"])
+	}
+	var request pds.ControllersAcceptEULARequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	account.Eula = &pds.ModelsEULADetails{Accepted: pds.PtrBool(true), AcceptedVersion: request.Version}
+	account.UpdatedAt = now()
+	return http.StatusOK, nil
+}
+
+func (s *Server) listAccountTenants(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.accounts.get(params["id"]); !ok {
+		return notFound("account", params["id"])
+	}
+	return http.StatusOK, page{Data: s.tenants.list(func(tenant *pds.ModelsTenant) bool {
+		return tenant.GetAccountId() == params["id"]
+	})}
+}
+
+func (s *Server) getTenant(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.tenants, "tenant", params["id"])
+}
+
+func (s *Server) getTenantDNSDetails(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.tenants.get(params["id"]); !ok {
+		return notFound("tenant", params["id"])
+	}
+	return http.StatusOK, pds.ModelsDNSDetails{DnsZone: pds.PtrString(DNSZone)}
+}
+
+func (s *Server) listTenantProjects(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.tenants.get(params["id"]); !ok {
+		return notFound("tenant", params["id"])
+	}
+	return http.StatusOK, page{Data: s.projects.list(func(project *pds.ModelsProject) bool {
+		return project.GetTenantId() == params["id"]
+	})}
+}
+
+func (s *Server) getProject(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.projects, "project", params["id"])
+}
+
+// Deployment targets and namespaces
+
+func (s *Server) listTenantDeploymentTargets(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.tenants.get(params["id"]); !ok {
+		return notFound("tenant", params["id"])
+	}
+	return http.StatusOK, page{Data: s.tenantDeploymentTargets(params["id"])}
+}
+
+func (s *Server) listProjectDeploymentTargets(r *http.Request, params map[string]string) (int, interface{}) {
+	project, ok := s.projects.get(params["id"])
+	if !ok {
+		return notFound("project", params["id"])
+	}
+	return http.StatusOK, page{Data: s.tenantDeploymentTargets(project.GetTenantId())}
+}
+
+func (s *Server) tenantDeploymentTargets(tenantID string) []pds.ModelsDeploymentTarget {
+	return s.deploymentTargets.list(func(target *pds.ModelsDeploymentTarget) bool {
+		return target.GetTenantId() == tenantID
+	})
+}
+
+func (s *Server) getDeploymentTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.deploymentTargets, "deployment target", params["id"])
+}
+
+func (s *Server) updateDeploymentTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	target, ok := s.deploymentTargets.get(params["id"])
+	if !ok {
+		return notFound("deployment target", params["id"])
+	}
+	var request pds.ControllersUpdateDeploymentTargetRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" {
+		return badRequest("name is required")
+	}
+	target.Name = request.Name
+	target.UpdatedAt = now()
+	return http.StatusOK, target
+}
+
+func (s *Server) patchDeploymentTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	target, ok := s.deploymentTargets.get(params["id"])
+	if !ok {
+		return notFound("deployment target", params["id"])
+	}
+	var request pds.RequestsPatchDeploymentTargetRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.Name != nil {
+		target.Name = request.Name
+	}
+	if request.TlsIssuer != nil {
+		target.TlsIssuer = request.TlsIssuer
+	}
+	if request.TlsRequired != nil {
+		target.TlsRequired = request.TlsRequired
+	}
+	target.UpdatedAt = now()
+	return http.StatusOK, target
+}
+
+func (s *Server) deleteDeploymentTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.deploymentTargets.get(params["id"]); !ok {
+		return notFound("deployment target", params["id"])
+	}
+	if deployments := s.deployments.list(func(deployment *pds.ModelsDeployment) bool {
+		return deployment.GetDeploymentTargetId() == params["id"]
+	}); len(deployments) != 0 {
+		return conflict("deployment target %s has %d deployments", params["id"], len(deployments))
+	}
+	for _, namespace := range s.namespaces.list(func(namespace *pds.ModelsNamespace) bool {
+		return namespace.GetDeploymentTargetId() == params["id"]
+	}) {
+		s.namespaces.delete(namespace.GetId())
+	}
+	s.deploymentTargets.delete(params["id"])
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) listNamespaces(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.deploymentTargets.get(params["id"]); !ok {
+		return notFound("deployment target", params["id"])
+	}
+	return http.StatusOK, page{Data: s.namespaces.list(func(namespace *pds.ModelsNamespace) bool {
+		return namespace.GetDeploymentTargetId() == params["id"]
+	})}
+}
+
+func (s *Server) createNamespace(r *http.Request, params map[string]string) (int, interface{}) {
+	target, ok := s.deploymentTargets.get(params["id"])
+	if !ok {
+		return notFound("deployment target", params["id"])
+	}
+	var request pds.ControllersCreateNamespace
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" {
+		return badRequest("name is required")
+	}
+	if existing := s.namespaces.list(func(namespace *pds.ModelsNamespace) bool {
+		return namespace.GetDeploymentTargetId() == params["id"] && namespace.GetName() == request.GetName()
+	}); len(existing) != 0 {
+		return conflict("namespace %s already exists on deployment target %s", request.GetName(), params["id"])
+	}
+	namespace := &pds.ModelsNamespace{
+		Id:                 pds.PtrString(newID()),
+		AccountId:          target.AccountId,
+		TenantId:           target.TenantId,
+		DeploymentTargetId: target.Id,
+		Name:               request.Name,
+		Status:             pds.PtrString(namespaceAvailable),
+		CreatedAt:          now(),
+	}
+	s.namespaces.put(namespace.GetId(), namespace)
+	return http.StatusOK, namespace
+}
+
+func (s *Server) getNamespace(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.namespaces, "namespace", params["id"])
+}
+
+func (s *Server) deleteNamespace(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.namespaces.get(params["id"]); !ok {
+		return notFound("namespace", params["id"])
+	}
+	if deployments := s.deployments.list(func(deployment *pds.ModelsDeployment) bool {
+		return deployment.GetNamespaceId() == params["id"]
+	}); len(deployments) != 0 {
+		return conflict("namespace %s has %d deployments", params["id"], len(deployments))
+	}
+	s.namespaces.delete(params["id"])
+	return http.StatusNoContent, nil
+}
+
+// Data services, versions and images
+
+func (s *Server) listDataServices(r *http.Request, params map[string]string) (int, interface{}) {
+	return http.StatusOK, page{Data: s.dataServices.list(nil)}
+}
+
+func (s *Server) getDataService(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.dataServices, "data service", params["id"])
+}
+
+func (s *Server) listVersions(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.dataServices.get(params["id"]); !ok {
+		return notFound("data service", params["id"])
+	}
+	return http.StatusOK, page{Data: s.versions.list(func(version *pds.ModelsVersion) bool {
+		return version.GetDataServiceId() == params["id"]
+	})}
+}
+
+func (s *Server) getVersion(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.versions, "version", params["id"])
+}
+
+func (s *Server) listImages(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.versions.get(params["id"]); !ok {
+		return notFound("version", params["id"])
+	}
+	return http.StatusOK, page{Data: s.images.list(func(image *pds.ModelsImage) bool {
+		return image.GetVersionId() == params["id"]
+	})}
+}
+
+func (s *Server) getImage(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.images, "image", params["id"])
+}
+
+// Templates
+
+func (s *Server) listResourceTemplates(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.tenants.get(params["id"]); !ok {
+		return notFound("tenant", params["id"])
+	}
+	return http.StatusOK, page{Data: s.resourceTemplates.list(func(template *pds.ModelsResourceSettingsTemplate) bool {
+		return template.GetTenantId() == params["id"]
+	})}
+}
+
+func (s *Server) createResourceTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	tenant, ok := s.tenants.get(params["id"])
+	if !ok {
+		return notFound("tenant", params["id"])
+	}
+	var request pds.ControllersCreateResourceSettingsTemplateRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" {
+		return badRequest("name is required")
+	}
+	if _, ok := s.dataServices.get(request.GetDataServiceId()); !ok {
+		return badRequest("data service %s not found", request.GetDataServiceId())
+	}
+	template := &pds.ModelsResourceSettingsTemplate{
+		Id:             pds.PtrString(newID()),
+		AccountId:      tenant.AccountId,
+		TenantId:       tenant.Id,
+		DataServiceId:  request.DataServiceId,
+		Name:           request.Name,
+		CpuLimit:       request.CpuLimit,
+		CpuRequest:     request.CpuRequest,
+		MemoryLimit:    request.MemoryLimit,
+		MemoryRequest:  request.MemoryRequest,
+		StorageRequest: request.StorageRequest,
+		CreatedAt:      now(),
+	}
+	s.resourceTemplates.put(template.GetId(), template)
+	return http.StatusOK, template
+}
+
+func (s *Server) getResourceTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.resourceTemplates, "resource settings template", params["id"])
+}
+
+func (s *Server) updateResourceTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	template, ok := s.resourceTemplates.get(params["id"])
+	if !ok {
+		return notFound("resource settings template", params["id"])
+	}
+	var request pds.ControllersUpdateResourceSettingsTemplateRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	setIfPresent(&template.Name, request.Name)
+	setIfPresent(&template.CpuLimit, request.CpuLimit)
+	setIfPresent(&template.CpuRequest, request.CpuRequest)
+	setIfPresent(&template.MemoryLimit, request.MemoryLimit)
+	setIfPresent(&template.MemoryRequest, request.MemoryRequest)
+	setIfPresent(&template.StorageRequest, request.StorageRequest)
+	template.UpdatedAt = now()
+	return http.StatusOK, template
+}
+
+func (s *Server) deleteResourceTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	return deleteItem(s.resourceTemplates, "resource settings template", params["id"])
+}
+
+func (s *Server) listStorageTemplates(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.tenants.get(params["id"]); !ok {
+		return notFound("tenant", params["id"])
+	}
+	return http.StatusOK, page{Data: s.storageTemplates.list(func(template *pds.ModelsStorageOptionsTemplate) bool {
+		return template.GetTenantId() == params["id"]
+	})}
+}
+
+func (s *Server) createStorageTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	tenant, ok := s.tenants.get(params["id"])
+	if !ok {
+		return notFound("tenant", params["id"])
+	}
+	var request pds.ControllersCreateStorageOptionsTemplateRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" {
+		return badRequest("name is required")
+	}
+	template := &pds.ModelsStorageOptionsTemplate{
+		Id:          pds.PtrString(newID()),
+		AccountId:   tenant.AccountId,
+		TenantId:    tenant.Id,
+		Name:        request.Name,
+		Fg:          request.Fg,
+		Fs:          request.Fs,
+		Provisioner: request.Provisioner,
+		Repl:        request.Repl,
+		Secure:      request.Secure,
+		CreatedAt:   now(),
+	}
+	s.storageTemplates.put(template.GetId(), template)
+	return http.StatusOK, template
+}
+
+func (s *Server) getStorageTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.storageTemplates, "storage options template", params["id"])
+}
+
+func (s *Server) updateStorageTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	template, ok := s.storageTemplates.get(params["id"])
+	if !ok {
+		return notFound("storage options template", params["id"])
+	}
+	var request pds.ControllersUpdateStorageOptionsTemplateRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	setIfPresent(&template.Name, request.Name)
+	setIfPresent(&template.Fg, request.Fg)
+	setIfPresent(&template.Fs, request.Fs)
+	setIfPresent(&template.Provisioner, request.Provisioner)
+	setIfPresent(&template.Repl, request.Repl)
+	setIfPresent(&template.Secure, request.Secure)
+	template.UpdatedAt = now()
+	return http.StatusOK, template
+}
+
+func (s *Server) deleteStorageTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	return deleteItem(s.storageTemplates, "storage options template", params["id"])
+}
+
+func (s *Server) listAppConfigTemplates(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.tenants.get(params["id"]); !ok {
+		return notFound("tenant", params["id"])
+	}
+	return http.StatusOK, page{Data: s.appConfigs.list(func(template *pds.ModelsApplicationConfigurationTemplate) bool {
+		return template.GetTenantId() == params["id"]
+	})}
+}
+
+func (s *Server) createAppConfigTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	tenant, ok := s.tenants.get(params["id"])
+	if !ok {
+		return notFound("tenant", params["id"])
+	}
+	var request pds.ControllersCreateApplicationConfigurationTemplateRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" {
+		return badRequest("name is required")
+	}
+	if _, ok := s.dataServices.get(request.GetDataServiceId()); !ok {
+		return badRequest("data service %s not found", request.GetDataServiceId())
+	}
+	template := &pds.ModelsApplicationConfigurationTemplate{
+		Id:            pds.PtrString(newID()),
+		AccountId:     tenant.AccountId,
+		TenantId:      tenant.Id,
+		DataServiceId: request.DataServiceId,
+		Name:          request.Name,
+		ConfigItems:   request.ConfigItems,
+		CreatedAt:     now(),
+	}
+	s.appConfigs.put(template.GetId(), template)
+	return http.StatusOK, template
+}
+
+func (s *Server) getAppConfigTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.appConfigs, "application configuration template", params["id"])
+}
+
+func (s *Server) updateAppConfigTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	template, ok := s.appConfigs.get(params["id"])
+	if !ok {
+		return notFound("application configuration template", params["id"])
+	}
+	var request pds.ControllersUpdateApplicationConfigurationTemplateRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	setIfPresent(&template.Name, request.Name)
+	if request.ConfigItems != nil {
+		template.ConfigItems = request.ConfigItems
+	}
+	template.UpdatedAt = now()
+	return http.StatusOK, template
+}
+
+func (s *Server) deleteAppConfigTemplate(r *http.Request, params map[string]string) (int, interface{}) {
+	return deleteItem(s.appConfigs, "application configuration template", params["id"])
+}
+
+// Backup credentials and targets
+
+func (s *Server) listBackupCredentials(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.tenants.get(params["id"]); !ok {
+		return notFound("tenant", params["id"])
+	}
+	return http.StatusOK, page{Data: s.backupCredentials.list(func(credentials *pds.ModelsBackupCredentials) bool {
+		return credentials.GetTenantId() == params["id"]
+	})}
+}
+
+func (s *Server) createBackupCredentials(r *http.Request, params map[string]string) (int, interface{}) {
+	tenant, ok := s.tenants.get(params["id"])
+	if !ok {
+		return notFound("tenant", params["id"])
+	}
+	var request pds.ControllersCreateBackupCredentialsRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" {
+		return badRequest("name is required")
+	}
+	credentialsType, err := credentialsType(request.Credentials)
+	if err != nil {
+		return badRequest(err.Error())
+	}
+	credentials := &pds.ModelsBackupCredentials{
+		Id:        pds.PtrString(newID()),
+		AccountId: tenant.AccountId,
+		TenantId:  tenant.Id,
+		Name:      request.Name,
+		Type:      pds.PtrString(credentialsType),
+		CreatedAt: now(),
+	}
+	s.backupCredentials.put(credentials.GetId(), credentials)
+	s.credentials[credentials.GetId()] = *request.Credentials
+	return http.StatusOK, credentials
+}
+
+func (s *Server) getBackupCredentials(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.backupCredentials, "backup credentials", params["id"])
+}
+
+func (s *Server) updateBackupCredentials(r *http.Request, params map[string]string) (int, interface{}) {
+	credentials, ok := s.backupCredentials.get(params["id"])
+	if !ok {
+		return notFound("backup credentials", params["id"])
+	}
+	var request pds.ControllersUpdateBackupCredentialsRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.Credentials != nil {
+		credentialsType, err := credentialsType(request.Credentials)
+		if err != nil {
+			return badRequest(err.Error())
+		}
+		if credentialsType != credentials.GetType() {
+			return badRequest("backup credentials %s are %s credentials, not %s", params["id"], credentials.GetType(), credentialsType)
+		}
+		s.credentials[params["id"]] = *request.Credentials
+	}
+	setIfPresent(&credentials.Name, request.Name)
+	credentials.UpdatedAt = now()
+	return http.StatusOK, credentials
+}
+
+func (s *Server) deleteBackupCredentials(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.backupCredentials.get(params["id"]); !ok {
+		return notFound("backup credentials", params["id"])
+	}
+	if targets := s.backupTargets.list(func(target *pds.ModelsBackupTarget) bool {
+		return target.GetBackupCredentialsId() == params["id"]
+	}); len(targets) != 0 {
+		return conflict("backup credentials %s are used by %d backup targets", params["id"], len(targets))
+	}
+	s.backupCredentials.delete(params["id"])
+	delete(s.credentials, params["id"])
+	return http.StatusNoContent, nil
+}
+
+// getPartialCredentials returns the credentials without their secrets
+func (s *Server) getPartialCredentials(r *http.Request, params map[string]string) (int, interface{}) {
+	credentials, ok := s.credentials[params["id"]]
+	if !ok {
+		return notFound("backup credentials", params["id"])
+	}
+	var partial pds.ControllersPartialCredentials
+	if credentials.S3 != nil {
+		partial.S3 = &pds.ControllersPartialS3Credentials{AccessKey: credentials.S3.AccessKey, Endpoint: credentials.S3.Endpoint}
+	}
+	if credentials.S3Compatible != nil {
+		partial.S3Compatible = &pds.ControllersPartialS3CompatibleCredentials{AccessKey: credentials.S3Compatible.AccessKey, Endpoint: credentials.S3Compatible.Endpoint}
+	}
+	if credentials.Azure != nil {
+		partial.Azure = &pds.ControllersPartialAzureCredentials{AccountName: credentials.Azure.AccountName}
+	}
+	if credentials.Google != nil {
+		partial.Google = &pds.ControllersPartialGoogleCredentials{ProjectId: credentials.Google.ProjectId}
+	}
+	return http.StatusOK, partial
+}
+
+func (s *Server) listTenantBackupTargets(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.tenants.get(params["id"]); !ok {
+		return notFound("tenant", params["id"])
+	}
+	return http.StatusOK, page{Data: s.tenantBackupTargets(params["id"])}
+}
+
+// listProjectBackupTargets returns the backup targets of the tenant of the project, the backup targets
+// are synced to all the deployment targets of the tenant
+func (s *Server) listProjectBackupTargets(r *http.Request, params map[string]string) (int, interface{}) {
+	project, ok := s.projects.get(params["id"])
+	if !ok {
+		return notFound("project", params["id"])
+	}
+	if targetID := r.URL.Query().Get("deployment_target_id"); targetID != "" {
+		if target, ok := s.deploymentTargets.get(targetID); !ok || target.GetTenantId() != project.GetTenantId() {
+			return http.StatusOK, page{Data: []pds.ModelsBackupTarget{}}
+		}
+	}
+	return http.StatusOK, page{Data: s.tenantBackupTargets(project.GetTenantId())}
+}
+
+func (s *Server) tenantBackupTargets(tenantID string) []pds.ModelsBackupTarget {
+	return s.backupTargets.list(func(target *pds.ModelsBackupTarget) bool {
+		return target.GetTenantId() == tenantID
+	})
+}
+
+func (s *Server) createBackupTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	tenant, ok := s.tenants.get(params["id"])
+	if !ok {
+		return notFound("tenant", params["id"])
+	}
+	var request pds.ControllersCreateTenantBackupTarget
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" || request.GetBucket() == "" {
+		return badRequest("name and bucket are required")
+	}
+	credentials, ok := s.backupCredentials.get(request.GetBackupCredentialsId())
+	if !ok || credentials.GetTenantId() != tenant.GetId() {
+		return badRequest("backup credentials %s not found", request.GetBackupCredentialsId())
+	}
+	target := &pds.ModelsBackupTarget{
+		Id:                  pds.PtrString(newID()),
+		AccountId:           tenant.AccountId,
+		TenantId:            tenant.Id,
+		BackupCredentialsId: request.BackupCredentialsId,
+		Bucket:              request.Bucket,
+		Name:                request.Name,
+		Region:              request.Region,
+		Type:                request.Type,
+		CreatedAt:           now(),
+	}
+	s.backupTargets.put(target.GetId(), target)
+	return http.StatusOK, target
+}
+
+func (s *Server) getBackupTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.backupTargets, "backup target", params["id"])
+}
+
+func (s *Server) updateBackupTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	target, ok := s.backupTargets.get(params["id"])
+	if !ok {
+		return notFound("backup target", params["id"])
+	}
+	var request pds.ControllersUpdateBackupTargetRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	setIfPresent(&target.Name, request.Name)
+	target.UpdatedAt = now()
+	return http.StatusOK, target
+}
+
+// deleteBackupTarget deletes the backup target, a backup target with backups is only deleted, with
+// its backups, when forced
+func (s *Server) deleteBackupTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.backupTargets.get(params["id"]); !ok {
+		return notFound("backup target", params["id"])
+	}
+	backups := s.backups.list(func(backup *pds.ModelsBackup) bool {
+		return backup.GetBackupTargetId() == params["id"]
+	})
+	if len(backups) != 0 {
+		if force, _ := strconv.ParseBool(r.URL.Query().Get("force")); !force {
+			return conflict("backup target %s has %d backups", params["id"], len(backups))
+		}
+		for _, backup := range backups {
+			s.removeBackup(backup.GetId())
+		}
+	}
+	s.backupTargets.delete(params["id"])
+	return http.StatusNoContent, nil
+}
+
+// listBackupTargetStates returns the state of the backup target on each deployment target of its tenant
+func (s *Server) listBackupTargetStates(r *http.Request, params map[string]string) (int, interface{}) {
+	target, ok := s.backupTargets.get(params["id"])
+	if !ok {
+		return notFound("backup target", params["id"])
+	}
+	states := []pds.ModelsBackupTargetState{}
+	for _, deploymentTarget := range s.tenantDeploymentTargets(target.GetTenantId()) {
+		states = append(states, pds.ModelsBackupTargetState{
+			BackupTargetId:     target.Id,
+			DeploymentTargetId: deploymentTarget.Id,
+			PxCredentialsId:    target.BackupCredentialsId,
+			PxCredentialsName:  pds.PtrString(fmt.Sprintf("px-%s", target.GetBackupCredentialsId())),
+			State:              pds.PtrString(backupTargetSynced),
+		})
+	}
+	return http.StatusOK, page{Data: states}
+}
+
+func (s *Server) syncBackupTarget(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.backupTargets.get(params["id"]); !ok {
+		return notFound("backup target", params["id"])
+	}
+	return http.StatusOK, nil
+}
+
+func (s *Server) listBackupTargetBackups(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.backupTargets.get(params["id"]); !ok {
+		return notFound("backup target", params["id"])
+	}
+	return http.StatusOK, page{Data: s.backups.list(func(backup *pds.ModelsBackup) bool {
+		return backup.GetBackupTargetId() == params["id"]
+	})}
+}
+
+// Deployments
+
+func (s *Server) listDeployments(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.projects.get(params["id"]); !ok {
+		return notFound("project", params["id"])
+	}
+	return http.StatusOK, page{Data: s.deployments.list(func(deployment *pds.ModelsDeployment) bool {
+		return deployment.GetProjectId() == params["id"]
+	})}
+}
+
+// createDeployment creates a deployment of the image in the namespace, a deployment created without
+// a project is created in the project of the seed
+func (s *Server) createDeployment(r *http.Request, params map[string]string) (int, interface{}) {
+	projectID, ok := params["id"]
+	if !ok {
+		projectID = s.Seed.ProjectID
+	}
+	project, ok := s.projects.get(projectID)
+	if !ok {
+		return notFound("project", projectID)
+	}
+	var request pds.RequestsCreateProjectDeploymentRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" || request.GetNodeCount() < 1 {
+		return badRequest("name and a node count of at least 1 are required")
+	}
+	target, ok := s.deploymentTargets.get(request.GetDeploymentTargetId())
+	if !ok || target.GetTenantId() != project.GetTenantId() {
+		return badRequest("deployment target %s not found", request.GetDeploymentTargetId())
+	}
+	namespace, ok := s.namespaces.get(request.GetNamespaceId())
+	if !ok || namespace.GetDeploymentTargetId() != target.GetId() {
+		return badRequest("namespace %s not found on deployment target %s", request.GetNamespaceId(), target.GetId())
+	}
+	image, ok := s.images.get(request.GetImageId())
+	if !ok {
+		return badRequest("image %s not found", request.GetImageId())
+	}
+	resourceTemplate, ok := s.resourceTemplates.get(request.GetResourceSettingsTemplateId())
+	if !ok || resourceTemplate.GetDataServiceId() != image.GetDataServiceId() {
+		return badRequest("resource settings template %s not found for data service %s", request.GetResourceSettingsTemplateId(), image.GetDataServiceId())
+	}
+	storageTemplate, ok := s.storageTemplates.get(request.GetStorageOptionsTemplateId())
+	if !ok {
+		return badRequest("storage options template %s not found", request.GetStorageOptionsTemplateId())
+	}
+	if request.ApplicationConfigurationTemplateId != nil && request.GetApplicationConfigurationTemplateId() != "" {
+		if template, ok := s.appConfigs.get(request.GetApplicationConfigurationTemplateId()); !ok || template.GetDataServiceId() != image.GetDataServiceId() {
+			return badRequest("application configuration template %s not found for data service %s", request.GetApplicationConfigurationTemplateId(), image.GetDataServiceId())
+		}
+	}
+	if s.deploymentExists(namespace.GetId(), request.GetName()) {
+		return conflict("deployment %s already exists in namespace %s", request.GetName(), namespace.GetName())
+	}
+
+	deployment := &pds.ModelsDeployment{
+		Id:                 pds.PtrString(newID()),
+		AccountId:          project.AccountId,
+		TenantId:           project.TenantId,
+		ProjectId:          project.Id,
+		DeploymentTargetId: target.Id,
+		NamespaceId:        namespace.Id,
+		Namespace:          namespace,
+		DataServiceId:      image.DataServiceId,
+		VersionId:          image.VersionId,
+		ImageId:            image.Id,
+		Name:               request.Name,
+		NodeCount:          request.NodeCount,
+		ServiceType:        request.ServiceType,
+		DnsZone:            request.DnsZone,
+		TlsEnabled:         request.TlsEnabled,
+		Resources:          deploymentResources(resourceTemplate),
+		StorageOptions:     deploymentStorageOptions(storageTemplate),
+		CreatedAt:          now(),
+	}
+	deployment.ClusterResourceName = pds.PtrString(clusterResourceName(deployment))
+	s.deployments.put(deployment.GetId(), deployment)
+	return http.StatusOK, deployment
+}
+
+// deploymentResources returns the resources of a deployment created with the template
+func deploymentResources(template *pds.ModelsResourceSettingsTemplate) *pds.ModelsDeploymentResources {
+	return &pds.ModelsDeploymentResources{
+		CpuLimit:       template.CpuLimit,
+		CpuRequest:     template.CpuRequest,
+		MemoryLimit:    template.MemoryLimit,
+		MemoryRequest:  template.MemoryRequest,
+		StorageRequest: template.StorageRequest,
+	}
+}
+
+// deploymentStorageOptions returns the storage options of a deployment created with the template
+func deploymentStorageOptions(template *pds.ModelsStorageOptionsTemplate) *pds.ModelsDeploymentStorageOptions {
+	return &pds.ModelsDeploymentStorageOptions{
+		Fg:          template.Fg,
+		Fs:          template.Fs,
+		Provisioner: template.Provisioner,
+		Repl:        template.Repl,
+		Secure:      template.Secure,
+	}
+}
+
+func (s *Server) deploymentExists(namespaceID, name string) bool {
+	return len(s.deployments.list(func(deployment *pds.ModelsDeployment) bool {
+		return deployment.GetNamespaceId() == namespaceID && deployment.GetName() == name
+	})) != 0
+}
+
+func (s *Server) getDeployment(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.deployments, "deployment", params["id"])
+}
+
+// updateDeployment updates the deployment, which is rolled out again before it is available
+func (s *Server) updateDeployment(r *http.Request, params map[string]string) (int, interface{}) {
+	deployment, ok := s.deployments.get(params["id"])
+	if !ok {
+		return notFound("deployment", params["id"])
+	}
+	var request pds.RequestsUpdateDeploymentRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.ImageId != nil && request.GetImageId() != "" {
+		image, ok := s.images.get(request.GetImageId())
+		if !ok || image.GetDataServiceId() != deployment.GetDataServiceId() {
+			return badRequest("image %s not found for data service %s", request.GetImageId(), deployment.GetDataServiceId())
+		}
+		deployment.ImageId = image.Id
+		deployment.VersionId = image.VersionId
+	}
+	if request.NodeCount != nil {
+		if request.GetNodeCount() < 1 {
+			return badRequest("a node count of at least 1 is required")
+		}
+		deployment.NodeCount = request.NodeCount
+	}
+	if request.ResourceSettingsTemplateId != nil && request.GetResourceSettingsTemplateId() != "" {
+		template, ok := s.resourceTemplates.get(request.GetResourceSettingsTemplateId())
+		if !ok || template.GetDataServiceId() != deployment.GetDataServiceId() {
+			return badRequest("resource settings template %s not found for data service %s", request.GetResourceSettingsTemplateId(), deployment.GetDataServiceId())
+		}
+		deployment.Resources = deploymentResources(template)
+	}
+	setIfPresent(&deployment.TlsEnabled, request.TlsEnabled)
+	deployment.UpdatedAt = now()
+	delete(s.reads, deployment.GetId())
+	return http.StatusOK, deployment
+}
+
+func (s *Server) deleteDeployment(r *http.Request, params map[string]string) (int, interface{}) {
+	delete(s.reads, params["id"])
+	return deleteItem(s.deployments, "deployment", params["id"])
+}
+
+// getDeploymentStatus reports the deployment unavailable until it is ready
+func (s *Server) getDeploymentStatus(r *http.Request, params map[string]string) (int, interface{}) {
+	deployment, ok := s.deployments.get(params["id"])
+	if !ok {
+		return notFound("deployment", params["id"])
+	}
+	status := pds.ServiceDeploymentStatus{
+		Health:        pds.PtrString(deploymentUnavailable),
+		Replicas:      deployment.NodeCount,
+		ReadyReplicas: pds.PtrInt32(0),
+		Initialized:   pds.PtrString("False"),
+		Restoring:     pds.PtrBool(deployment.RestoreId != nil),
+	}
+	if s.ready(deployment.GetId()) {
+		status.Health = pds.PtrString(deploymentAvailable)
+		status.ReadyReplicas = deployment.NodeCount
+		status.Initialized = pds.PtrString("True")
+		status.Restoring = pds.PtrBool(false)
+	}
+	return http.StatusOK, status
+}
+
+func (s *Server) getConnectionInfo(r *http.Request, params map[string]string) (int, interface{}) {
+	deployment, ok := s.deployments.get(params["id"])
+	if !ok {
+		return notFound("deployment", params["id"])
+	}
+	var nodes []string
+	for i := int32(0); i < deployment.GetNodeCount(); i++ {
+		nodes = append(nodes, fmt.Sprintf("%s-%d-%s.%s", deployment.GetClusterResourceName(), i, deployment.Namespace.GetName(), DNSZone))
+	}
+	ports := make(map[string]int32)
+	if dataService, ok := s.dataServices.get(deployment.GetDataServiceId()); ok {
+		if name, port := dataServicePort(dataService.GetName()); port != 0 {
+			ports[name] = port
+		}
+	}
+	return http.StatusOK, pds.DeploymentsConnectionInfo{
+		ConnectionDetails: &pds.DeploymentsConnectionDetails{Nodes: nodes, Ports: &ports},
+		ClusterDetails: map[string]interface{}{
+			"host": fmt.Sprintf("%s-%s.%s", deployment.GetClusterResourceName(), deployment.Namespace.GetName(), DNSZone),
+			"port": ports,
+		},
+	}
+}
+
+func (s *Server) getDeploymentCredentials(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.deployments.get(params["id"]); !ok {
+		return notFound("deployment", params["id"])
+	}
+	return http.StatusOK, pds.DeploymentsCredentials{Password: pds.PtrString(deploymentPassword(params["id"]))}
+}
+
+// Backups, backup jobs and restores
+
+func (s *Server) listDeploymentBackups(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.deployments.get(params["id"]); !ok {
+		return notFound("deployment", params["id"])
+	}
+	return http.StatusOK, page{Data: s.backups.list(func(backup *pds.ModelsBackup) bool {
+		return backup.GetDeploymentId() == params["id"]
+	})}
+}
+
+// createBackup creates a backup of the deployment to the backup target, an adhoc backup runs a backup
+// job right away
+func (s *Server) createBackup(r *http.Request, params map[string]string) (int, interface{}) {
+	deployment, ok := s.deployments.get(params["id"])
+	if !ok {
+		return notFound("deployment", params["id"])
+	}
+	var request pds.ControllersCreateDeploymentBackup
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	target, ok := s.backupTargets.get(request.GetBackupTargetId())
+	if !ok || target.GetTenantId() != deployment.GetTenantId() {
+		return badRequest("backup target %s not found", request.GetBackupTargetId())
+	}
+	backup := &pds.ModelsBackup{
+		Id:                  pds.PtrString(newID()),
+		AccountId:           deployment.AccountId,
+		TenantId:            deployment.TenantId,
+		ProjectId:           deployment.ProjectId,
+		DeploymentTargetId:  deployment.DeploymentTargetId,
+		NamespaceId:         deployment.NamespaceId,
+		DeploymentId:        deployment.Id,
+		DeploymentName:      deployment.Name,
+		DataServiceId:       deployment.DataServiceId,
+		BackupTargetId:      target.Id,
+		BackupLevel:         request.BackupLevel,
+		BackupType:          request.BackupType,
+		JobHistoryLimit:     request.JobHistoryLimit,
+		Schedule:            request.Schedule,
+		ReclaimPolicy:       pds.PtrString("Delete"),
+		Suspend:             pds.PtrBool(false),
+		ClusterResourceName: pds.PtrString(fmt.Sprintf("%s-%s", deployment.GetClusterResourceName(), shortID(newID()))),
+		CreatedAt:           now(),
+	}
+	s.backups.put(backup.GetId(), backup)
+	if backup.GetBackupType() == adhocBackup {
+		s.runBackupJob(backup, deployment)
+	}
+	return http.StatusOK, backup
+}
+
+// runBackupJob adds a job of the backup of the deployment, which is running until it is ready
+func (s *Server) runBackupJob(backup *pds.ModelsBackup, deployment *pds.ModelsDeployment) *pds.ModelsBackupJob {
+	startTime := time.Now().UTC()
+	job := &pds.ModelsBackupJob{
+		Id:                 pds.PtrString(newID()),
+		BackupId:           backup.Id,
+		Name:               pds.PtrString(fmt.Sprintf("%s-%d", backup.GetClusterResourceName(), startTime.Unix())),
+		ProjectId:          deployment.ProjectId,
+		DeploymentId:       deployment.Id,
+		DeploymentTargetId: deployment.DeploymentTargetId,
+		NamespaceId:        deployment.NamespaceId,
+		ImageId:            deployment.ImageId,
+		BackupCapability:   backup.BackupLevel,
+		CloudSnapId:        pds.PtrString(fmt.Sprintf("%s/%s", backup.GetBackupTargetId(), newID())),
+		StartTime:          pds.PtrString(startTime.Format(time.RFC3339)),
+		CreatedAt:          pds.PtrString(startTime.Format(time.RFC3339)),
+	}
+	s.backupJobs.put(job.GetId(), job)
+	s.backupSources[job.GetId()] = *deployment
+	return job
+}
+
+func (s *Server) getBackup(r *http.Request, params map[string]string) (int, interface{}) {
+	return getItem(s.backups, "backup", params["id"])
+}
+
+func (s *Server) updateBackup(r *http.Request, params map[string]string) (int, interface{}) {
+	backup, ok := s.backups.get(params["id"])
+	if !ok {
+		return notFound("backup", params["id"])
+	}
+	var request pds.ControllersUpdateBackupRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	setIfPresent(&backup.JobHistoryLimit, request.JobHistoryLimit)
+	backup.UpdatedAt = now()
+	return http.StatusOK, backup
+}
+
+func (s *Server) deleteBackup(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.backups.get(params["id"]); !ok {
+		return notFound("backup", params["id"])
+	}
+	s.removeBackup(params["id"])
+	return http.StatusNoContent, nil
+}
+
+// removeBackup deletes the backup and its jobs
+func (s *Server) removeBackup(backupID string) {
+	for _, job := range s.backupJobs.list(func(job *pds.ModelsBackupJob) bool {
+		return job.GetBackupId() == backupID
+	}) {
+		s.removeBackupJob(job.GetId())
+	}
+	s.backups.delete(backupID)
+}
+
+func (s *Server) removeBackupJob(jobID string) {
+	s.backupJobs.delete(jobID)
+	delete(s.backupSources, jobID)
+	delete(s.reads, jobID)
+}
+
+func (s *Server) listBackupJobStatuses(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.backups.get(params["id"]); !ok {
+		return notFound("backup", params["id"])
+	}
+	statuses := []pds.ModelsBackupJobStatusResponse{}
+	for _, id := range s.backupJobs.ids {
+		job := s.backupJobs.items[id]
+		if job.GetBackupId() != params["id"] {
+			continue
+		}
+		s.refreshBackupJob(job)
+		statuses = append(statuses, pds.ModelsBackupJobStatusResponse{
+			Name:           job.Name,
+			StartTime:      job.StartTime,
+			CompletionTime: job.CompletionTime,
+			Status:         job.CompletionStatus,
+		})
+	}
+	return http.StatusOK, pds.ControllersListBackupJobsStatusResponse{Data: statuses}
+}
+
+func (s *Server) deleteBackupJobByName(r *http.Request, params map[string]string) (int, interface{}) {
+	jobs := s.backupJobs.list(func(job *pds.ModelsBackupJob) bool {
+		return job.GetBackupId() == params["id"] && job.GetName() == params["name"]
+	})
+	if len(jobs) == 0 {
+		return notFound("backup job", params["name"])
+	}
+	s.removeBackupJob(jobs[0].GetId())
+	return http.StatusNoContent, nil
+}
+
+func (s *Server) listBackupJobs(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.projects.get(params["id"]); !ok {
+		return notFound("project", params["id"])
+	}
+	query := r.URL.Query()
+	jobs := []pds.ModelsBackupJob{}
+	for _, id := range s.backupJobs.ids {
+		job := s.backupJobs.items[id]
+		if job.GetProjectId() != params["id"] ||
+			(query.Get("deployment_id") != "" && job.GetDeploymentId() != query.Get("deployment_id")) ||
+			(query.Get("deployment_target_id") != "" && job.GetDeploymentTargetId() != query.Get("deployment_target_id")) {
+			continue
+		}
+		s.refreshBackupJob(job)
+		jobs = append(jobs, *job)
+	}
+	return http.StatusOK, page{Data: jobs}
+}
+
+func (s *Server) getBackupJob(r *http.Request, params map[string]string) (int, interface{}) {
+	job, ok := s.backupJobs.get(params["id"])
+	if !ok {
+		return notFound("backup job", params["id"])
+	}
+	s.refreshBackupJob(job)
+	return http.StatusOK, job
+}
+
+func (s *Server) deleteBackupJob(r *http.Request, params map[string]string) (int, interface{}) {
+	if _, ok := s.backupJobs.get(params["id"]); !ok {
+		return notFound("backup job", params["id"])
+	}
+	s.removeBackupJob(params["id"])
+	return http.StatusNoContent, nil
+}
+
+// refreshBackupJob counts a read of the job and completes it when it is ready
+func (s *Server) refreshBackupJob(job *pds.ModelsBackupJob) {
+	if job.GetCompletionStatus() == backupJobSucceeded {
+		return
+	}
+	job.CompletionStatus = pds.PtrString(backupJobRunning)
+	if s.ready(job.GetId()) {
+		job.CompletionStatus = pds.PtrString(backupJobSucceeded)
+		job.CompletionTime = now()
+		job.Timestamp = job.CompletionTime
+	}
+}
+
+// createRestore restores the backup job as a new deployment, the job has to be completed
+func (s *Server) createRestore(r *http.Request, params map[string]string) (int, interface{}) {
+	job, ok := s.backupJobs.get(params["id"])
+	if !ok {
+		return notFound("backup job", params["id"])
+	}
+	if job.GetCompletionStatus() != backupJobSucceeded && !s.completed(job.GetId()) {
+		return conflict("backup job %s is not completed", params["id"])
+	}
+	var request pds.RequestsCreateRestoreRequest
+	if err := decode(r, &request); err != nil {
+		return badRequest(err.Error())
+	}
+	if request.GetName() == "" {
+		return badRequest("name is required")
+	}
+	source := s.backupSources[job.GetId()]
+	target, ok := s.deploymentTargets.get(request.GetDeploymentTargetId())
+	if !ok || target.GetTenantId() != source.GetTenantId() {
+		return badRequest("deployment target %s not found", request.GetDeploymentTargetId())
+	}
+	namespace, ok := s.namespaces.get(request.GetNamespaceId())
+	if !ok || namespace.GetDeploymentTargetId() != target.GetId() {
+		return badRequest("namespace %s not found on deployment target %s", request.GetNamespaceId(), target.GetId())
+	}
+	if s.deploymentExists(namespace.GetId(), request.GetName()) {
+		return conflict("deployment %s already exists in namespace %s", request.GetName(), namespace.GetName())
+	}
+
+	restore := &pds.ModelsRestore{
+		Id:                 pds.PtrString(newID()),
+		Name:               request.Name,
+		BackupJobId:        job.Id,
+		CloudSnapId:        job.CloudSnapId,
+		DeploymentTargetId: target.Id,
+		NamespaceId:        namespace.Id,
+		StartTime:          now(),
+		Status:             pds.PtrString(restoreRunning),
+		CreatedAt:          now(),
+	}
+	deployment := source
+	deployment.Id = pds.PtrString(newID())
+	deployment.Name = request.Name
+	deployment.DeploymentTargetId = target.Id
+	deployment.NamespaceId = namespace.Id
+	deployment.Namespace = namespace
+	deployment.RestoreId = restore.Id
+	deployment.CreatedAt = now()
+	deployment.UpdatedAt = nil
+	deployment.ClusterResourceName = pds.PtrString(clusterResourceName(&deployment))
+	s.deployments.put(deployment.GetId(), &deployment)
+
+	restore.DeploymentId = deployment.Id
+	restore.ClusterResourceName = deployment.ClusterResourceName
+	s.restores.put(restore.GetId(), restore)
+	return http.StatusOK, restore
+}
+
+func (s *Server) getRestorabilityMatrix(r *http.Request, params map[string]string) (int, interface{}) {
+	return http.StatusOK, map[string][]pds.ServiceRestoreCompatibilityCondition{}
+}
+
+func (s *Server) getRestore(r *http.Request, params map[string]string) (int, interface{}) {
+	restore, ok := s.restores.get(params["id"])
+	if !ok {
+		return notFound("restore", params["id"])
+	}
+	if restore.GetStatus() != restoreSuccessful && s.ready(restore.GetId()) {
+		restore.Status = pds.PtrString(restoreSuccessful)
+		restore.CompletionTime = now()
+	}
+	return http.StatusOK, restore
+}
+
+// retryRestore restarts the restore
+func (s *Server) retryRestore(r *http.Request, params map[string]string) (int, interface{}) {
+	restore, ok := s.restores.get(params["id"])
+	if !ok {
+		return notFound("restore", params["id"])
+	}
+	if restore.GetStatus() == restoreSuccessful {
+		return conflict("restore %s is already successful", params["id"])
+	}
+	restore.StartTime = now()
+	restore.UpdatedAt = now()
+	delete(s.reads, restore.GetId())
+	return http.StatusOK, restore
+}
+
+// getItem returns the object with the id
+func getItem[T any](c *collection[T], kind, id string) (int, interface{}) {
+	item, ok := c.get(id)
+	if !ok {
+		return notFound(kind, id)
+	}
+	return http.StatusOK, item
+}
+
+// deleteItem deletes the object with the id
+func deleteItem[T any](c *collection[T], kind, id string) (int, interface{}) {
+	if !c.delete(id) {
+		return notFound(kind, id)
+	}
+	return http.StatusNoContent, nil
+}
+
+// setIfPresent sets the field to the value of an update request if it is set
+func setIfPresent[T any](field **T, value *T) {
+	if value != nil {
+		*field = value
+	}
+}
+
+// credentialsType returns the type of the backup credentials, one type of credentials has to be set
+func credentialsType(credentials *pds.ControllersCredentials) (string, error) {
+	if credentials == nil {
+		return "", fmt.Errorf("credentials are required")
+	}
+	var types []string
+	if credentials.S3 != nil {
+		types = append(types, "s3")
+	}
+	if credentials.S3Compatible != nil {
+		types = append(types, "s3-compatible")
+	}
+	if credentials.Azure != nil {
+		types = append(types, "azure")
+	}
+	if credentials.Google != nil {
+		types = append(types, "google")
+	}
+	if len(types) != 1 {
+		return "", fmt.Errorf("exactly one type of credentials is required, got [%s]", strings.Join(types, ","))
+	}
+	return types[0], nil
+}
+
+func clusterResourceName(deployment *pds.ModelsDeployment) string {
+	return fmt.Sprintf("%s-%s", deployment.GetName(), shortID(deployment.GetId()))
+}
+
+func deploymentPassword(deploymentID string) string {
+	return fmt.Sprintf("pdsmock-%s", shortID(deploymentID))
+}
+
+func shortID(id string) string {
+	return strings.ReplaceAll(id, "-", "")[:8]
+}
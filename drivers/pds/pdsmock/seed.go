@@ -0,0 +1,186 @@
+package pdsmock
+
+import (
+	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+)
+
+const (
+	// AccountName is the name of the seeded account, like the AccountName of the default pds parameters
+	AccountName = "Portworx"
+	// TenantName is the name of the seeded tenant
+	TenantName = "Default"
+	// ProjectName is the name of the seeded project
+	ProjectName = "Default"
+	// DeploymentTargetName is the name of the seeded deployment target
+	DeploymentTargetName = "pdsmock-target"
+	// DNSZone is the dns zone of the tenants
+	DNSZone = "pdsmock.portworx.local"
+	// UserEmail is the email of the user of the seeded account
+	UserEmail = "pdsmock@portworx.com"
+
+	// StorageTemplateName is the name of the seeded storage options template
+	StorageTemplateName = "QaDefault"
+	// ResourceTemplateName is the name of the seeded resource settings templates
+	ResourceTemplateName = "Small"
+	// AppConfigTemplateName is the name of the seeded application configuration templates
+	AppConfigTemplateName = "QaDefault"
+
+	deploymentTargetHealthy = "healthy"
+	namespaceAvailable      = "available"
+)
+
+// seedDataService is a data service the mock serves
+type seedDataService struct {
+	name      string
+	shortName string
+	version   string
+	build     string
+	port      int32
+}
+
+var seedDataServices = []seedDataService{
+	{name: "PostgreSQL", shortName: "pg", version: "14.6", build: "bc6b2ac", port: 5432},
+	{name: "Cassandra", shortName: "cas", version: "4.1.2", build: "1b9e5b4", port: 9042},
+	{name: "Elasticsearch", shortName: "es", version: "8.8.0", build: "6e2d7c1", port: 9200},
+	{name: "Couchbase", shortName: "cb", version: "7.1.1", build: "a4c1f0e", port: 8091},
+	{name: "MongoDB Enterprise", shortName: "mdb", version: "6.0.3", build: "3f5a9d2", port: 27017},
+	{name: "RabbitMQ", shortName: "rmq", version: "3.11.7", build: "e7d0b6a", port: 5672},
+	{name: "MySQL", shortName: "my", version: "8.0.31", build: "9c2e4f7", port: 3306},
+	{name: "MS SQL Server", shortName: "sql", version: "2019-CU20", build: "5d8a3b1", port: 1433},
+	{name: "Kafka", shortName: "kf", version: "3.4.1", build: "0a6f2c9", port: 9092},
+	{name: "Consul", shortName: "con", version: "1.14.0", build: "7b3c1e8", port: 8500},
+	{name: "ZooKeeper", shortName: "zk", version: "3.8.1", build: "c2e9a7d", port: 2181},
+	{name: "Redis", shortName: "rd", version: "7.0.9", build: "4e1b8f3", port: 6379},
+}
+
+// seed adds the account, tenant, project, deployment target, data services and templates the mock starts with
+func (s *Server) seed() {
+	s.Seed = Seed{
+		AccountID:          newID(),
+		TenantID:           newID(),
+		ProjectID:          newID(),
+		DeploymentTargetID: newID(),
+		DataServices:       make(map[string]string),
+		Images:             make(map[string]string),
+		StorageTemplateID:  newID(),
+		ResourceTemplates:  make(map[string]string),
+		AppConfigTemplates: make(map[string]string),
+	}
+
+	s.accounts.put(s.Seed.AccountID, &pds.ModelsAccount{
+		Id:        pds.PtrString(s.Seed.AccountID),
+		Name:      pds.PtrString(AccountName),
+		Eula:      &pds.ModelsEULADetails{Accepted: pds.PtrBool(false)},
+		CreatedAt: now(),
+	})
+	userID := newID()
+	s.users.put(userID, &pds.ModelsUser{
+		Id:        pds.PtrString(userID),
+		Email:     pds.PtrString(UserEmail),
+		CreatedAt: now(),
+	})
+	s.tenants.put(s.Seed.TenantID, &pds.ModelsTenant{
+		Id:        pds.PtrString(s.Seed.TenantID),
+		AccountId: pds.PtrString(s.Seed.AccountID),
+		Name:      pds.PtrString(TenantName),
+		CreatedAt: now(),
+	})
+	s.projects.put(s.Seed.ProjectID, &pds.ModelsProject{
+		Id:        pds.PtrString(s.Seed.ProjectID),
+		AccountId: pds.PtrString(s.Seed.AccountID),
+		TenantId:  pds.PtrString(s.Seed.TenantID),
+		Name:      pds.PtrString(ProjectName),
+		CreatedAt: now(),
+	})
+	s.deploymentTargets.put(s.Seed.DeploymentTargetID, &pds.ModelsDeploymentTarget{
+		Id:          pds.PtrString(s.Seed.DeploymentTargetID),
+		AccountId:   pds.PtrString(s.Seed.AccountID),
+		TenantId:    pds.PtrString(s.Seed.TenantID),
+		ClusterId:   pds.PtrString(newID()),
+		Name:        pds.PtrString(DeploymentTargetName),
+		Status:      pds.PtrString(deploymentTargetHealthy),
+		TlsRequired: pds.PtrBool(false),
+		CreatedAt:   now(),
+	})
+	s.storageTemplates.put(s.Seed.StorageTemplateID, &pds.ModelsStorageOptionsTemplate{
+		Id:          pds.PtrString(s.Seed.StorageTemplateID),
+		AccountId:   pds.PtrString(s.Seed.AccountID),
+		TenantId:    pds.PtrString(s.Seed.TenantID),
+		Name:        pds.PtrString(StorageTemplateName),
+		Fs:          pds.PtrString("xfs"),
+		Fg:          pds.PtrBool(false),
+		Provisioner: pds.PtrString("pxd.portworx.com"),
+		Repl:        pds.PtrInt32(2),
+		Secure:      pds.PtrBool(false),
+		CreatedAt:   now(),
+	})
+
+	for _, ds := range seedDataServices {
+		dataServiceID, versionID, imageID := newID(), newID(), newID()
+		s.dataServices.put(dataServiceID, &pds.ModelsDataService{
+			Id:                   pds.PtrString(dataServiceID),
+			Name:                 pds.PtrString(ds.name),
+			ShortName:            pds.PtrString(ds.shortName),
+			HasFullBackup:        pds.PtrBool(true),
+			HasIncrementalBackup: pds.PtrBool(false),
+			ComingSoon:           pds.PtrBool(false),
+			CreatedAt:            now(),
+		})
+		s.versions.put(versionID, &pds.ModelsVersion{
+			Id:            pds.PtrString(versionID),
+			DataServiceId: pds.PtrString(dataServiceID),
+			Name:          pds.PtrString(ds.version),
+			Enabled:       pds.PtrBool(true),
+			CreatedAt:     now(),
+		})
+		s.images.put(imageID, &pds.ModelsImage{
+			Id:            pds.PtrString(imageID),
+			DataServiceId: pds.PtrString(dataServiceID),
+			VersionId:     pds.PtrString(versionID),
+			Name:          pds.PtrString(ds.shortName),
+			Build:         pds.PtrString(ds.build),
+			Tag:           pds.PtrString(ds.version),
+			Registry:      pds.PtrString("docker.io"),
+			Namespace:     pds.PtrString("portworx"),
+			TlsAvailable:  pds.PtrBool(true),
+			CreatedAt:     now(),
+		})
+		s.Seed.DataServices[ds.name] = dataServiceID
+		s.Seed.Images[ds.name] = imageID
+
+		resourceTemplateID, appConfigTemplateID := newID(), newID()
+		s.resourceTemplates.put(resourceTemplateID, &pds.ModelsResourceSettingsTemplate{
+			Id:             pds.PtrString(resourceTemplateID),
+			AccountId:      pds.PtrString(s.Seed.AccountID),
+			TenantId:       pds.PtrString(s.Seed.TenantID),
+			DataServiceId:  pds.PtrString(dataServiceID),
+			Name:           pds.PtrString(ResourceTemplateName),
+			CpuLimit:       pds.PtrString("1"),
+			CpuRequest:     pds.PtrString("500m"),
+			MemoryLimit:    pds.PtrString("2G"),
+			MemoryRequest:  pds.PtrString("1G"),
+			StorageRequest: pds.PtrString("10G"),
+			CreatedAt:      now(),
+		})
+		s.appConfigs.put(appConfigTemplateID, &pds.ModelsApplicationConfigurationTemplate{
+			Id:            pds.PtrString(appConfigTemplateID),
+			AccountId:     pds.PtrString(s.Seed.AccountID),
+			TenantId:      pds.PtrString(s.Seed.TenantID),
+			DataServiceId: pds.PtrString(dataServiceID),
+			Name:          pds.PtrString(AppConfigTemplateName),
+			CreatedAt:     now(),
+		})
+		s.Seed.ResourceTemplates[ds.name] = resourceTemplateID
+		s.Seed.AppConfigTemplates[ds.name] = appConfigTemplateID
+	}
+}
+
+// dataServicePort returns the port the deployments of the data service are connected to
+func dataServicePort(name string) (string, int32) {
+	for _, ds := range seedDataServices {
+		if ds.name == name {
+			return ds.shortName, ds.port
+		}
+	}
+	return "", 0
+}
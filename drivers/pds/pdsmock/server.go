@@ -0,0 +1,379 @@
+// Package pdsmock is an in-process mock of the PDS control plane REST API. It keeps the accounts,
+// tenants, projects, deployment targets, data services, templates, deployments, backups and restores
+// it serves in memory, moves deployments, backup jobs and restores through their states like the
+// control plane does, and can inject faults, so that the drivers/pds api wrappers and the logic built
+// on them can be tested without a PDS control plane.
+package pdsmock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+)
+
+const (
+	// Token is the bearer token returned by the login endpoint of the mock
+	Token = "pdsmock-token"
+
+	envControlPlaneURL = "CONTROL_PLANE_URL"
+	envPDSISSUERURL    = "PDS_ISSUER_URL"
+)
+
+// Fault makes the requests matching a route fail or slow down
+type Fault struct {
+	// Method of the requests, all methods if empty
+	Method string
+	// Path is the route pattern of the requests, like "/api/deployments/{id}"
+	Path string
+	// Status is the status the requests fail with, the requests are served if 0
+	Status int
+	// Delay is the time the requests are delayed by before they fail or are served
+	Delay time.Duration
+	// Times is the number of requests the fault applies to, all of them if 0
+	Times int
+}
+
+// Seed is the hierarchy the mock starts with
+type Seed struct {
+	AccountID          string
+	TenantID           string
+	ProjectID          string
+	DeploymentTargetID string
+	// DataServices are the ids of the data services, keyed by name
+	DataServices map[string]string
+	// Images are the ids of the images of the data services, keyed by data service name
+	Images map[string]string
+	// StorageTemplateID is the id of the storage options template
+	StorageTemplateID string
+	// ResourceTemplates are the ids of the resource settings templates, keyed by data service name
+	ResourceTemplates map[string]string
+	// AppConfigTemplates are the ids of the application configuration templates, keyed by data service name
+	AppConfigTemplates map[string]string
+}
+
+// Server is the PDS control plane mock
+type Server struct {
+	*httptest.Server
+	Seed Seed
+
+	// ReadyAfter is the number of reads of a deployment status, a backup job or a restore that report
+	// it in progress before it is reported ready, 0 reports them ready on the first read. It is set
+	// before the mock is used.
+	ReadyAfter int
+
+	mu       sync.Mutex
+	routes   []route
+	faults   []*Fault
+	requests map[string]int
+
+	accounts          *collection[pds.ModelsAccount]
+	users             *collection[pds.ModelsUser]
+	tenants           *collection[pds.ModelsTenant]
+	projects          *collection[pds.ModelsProject]
+	deploymentTargets *collection[pds.ModelsDeploymentTarget]
+	namespaces        *collection[pds.ModelsNamespace]
+	dataServices      *collection[pds.ModelsDataService]
+	versions          *collection[pds.ModelsVersion]
+	images            *collection[pds.ModelsImage]
+	resourceTemplates *collection[pds.ModelsResourceSettingsTemplate]
+	storageTemplates  *collection[pds.ModelsStorageOptionsTemplate]
+	appConfigs        *collection[pds.ModelsApplicationConfigurationTemplate]
+	backupCredentials *collection[pds.ModelsBackupCredentials]
+	credentials       map[string]pds.ControllersCredentials
+	backupTargets     *collection[pds.ModelsBackupTarget]
+	deployments       *collection[pds.ModelsDeployment]
+	backups           *collection[pds.ModelsBackup]
+	backupJobs        *collection[pds.ModelsBackupJob]
+	restores          *collection[pds.ModelsRestore]
+	// backupSources are the deployments as they were backed up, by backup job id
+	backupSources map[string]pds.ModelsDeployment
+	// reads counts the reads of the deployments, backup jobs and restores in progress, by id
+	reads map[string]int
+}
+
+// route is a handler of the requests matching a method and a path pattern
+type route struct {
+	method   string
+	pattern  string
+	segments []string
+	handler  handlerFunc
+}
+
+// handlerFunc serves a request with the path parameters of its route and returns the status and the
+// body of the response, it is called with the lock of the server held
+type handlerFunc func(r *http.Request, params map[string]string) (int, interface{})
+
+// errorResponse is the body of a failed request
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// NewServer starts a mock seeded with the account, tenant and project of the default pds parameters,
+// a healthy deployment target, the data services with one version and image each and the templates
+// the pds tests look up by name
+func NewServer() *Server {
+	s := &Server{
+		requests:          make(map[string]int),
+		accounts:          newCollection[pds.ModelsAccount](),
+		users:             newCollection[pds.ModelsUser](),
+		tenants:           newCollection[pds.ModelsTenant](),
+		projects:          newCollection[pds.ModelsProject](),
+		deploymentTargets: newCollection[pds.ModelsDeploymentTarget](),
+		namespaces:        newCollection[pds.ModelsNamespace](),
+		dataServices:      newCollection[pds.ModelsDataService](),
+		versions:          newCollection[pds.ModelsVersion](),
+		images:            newCollection[pds.ModelsImage](),
+		resourceTemplates: newCollection[pds.ModelsResourceSettingsTemplate](),
+		storageTemplates:  newCollection[pds.ModelsStorageOptionsTemplate](),
+		appConfigs:        newCollection[pds.ModelsApplicationConfigurationTemplate](),
+		backupCredentials: newCollection[pds.ModelsBackupCredentials](),
+		credentials:       make(map[string]pds.ControllersCredentials),
+		backupTargets:     newCollection[pds.ModelsBackupTarget](),
+		deployments:       newCollection[pds.ModelsDeployment](),
+		backups:           newCollection[pds.ModelsBackup](),
+		backupJobs:        newCollection[pds.ModelsBackupJob](),
+		restores:          newCollection[pds.ModelsRestore](),
+		backupSources:     make(map[string]pds.ModelsDeployment),
+		reads:             make(map[string]int),
+	}
+	s.registerRoutes()
+	s.seed()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Configuration returns the configuration of a pds api client of the mock
+func (s *Server) Configuration() *pds.Configuration {
+	endpointURL, _ := url.Parse(s.URL)
+	apiConf := pds.NewConfiguration()
+	apiConf.Host = endpointURL.Host
+	apiConf.Scheme = endpointURL.Scheme
+	return apiConf
+}
+
+// Env returns the environment variables that make the api wrappers use the mock as control plane and token issuer
+func (s *Server) Env() map[string]string {
+	return map[string]string{
+		envControlPlaneURL: s.URL,
+		envPDSISSUERURL:    s.URL,
+	}
+}
+
+// InjectFault adds a fault to the requests matching its route
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all the faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the number of requests served or failed for the method and route pattern
+func (s *Server) Requests(method, pattern string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[method+" "+pattern]
+}
+
+func (s *Server) handle(method, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		pattern:  pattern,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	route, params, ok := s.match(r.Method, r.URL.Path)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Message: fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path)})
+		return
+	}
+
+	s.mu.Lock()
+	s.requests[r.Method+" "+route.pattern]++
+	fault := s.fault(r.Method, route.pattern)
+	s.mu.Unlock()
+
+	if fault != nil {
+		time.Sleep(fault.Delay)
+		if fault.Status != 0 {
+			writeJSON(w, fault.Status, errorResponse{Message: fmt.Sprintf("injected fault for %s %s", r.Method, route.pattern)})
+			return
+		}
+	}
+
+	if strings.HasPrefix(route.pattern, "/api/") && r.Header.Get("Authorization") != "Bearer "+Token {
+		writeJSON(w, http.StatusUnauthorized, errorResponse{Message: "invalid bearer token"})
+		return
+	}
+
+	// the response is encoded with the lock held, the handlers return the stored objects
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, body := route.handler(r, params)
+	writeJSON(w, status, body)
+}
+
+// match returns the route of the request and its path parameters
+func (s *Server) match(method, path string) (route, map[string]string, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range s.routes {
+		if route.method != method || len(route.segments) != len(segments) {
+			continue
+		}
+		params := make(map[string]string)
+		matched := true
+		for i, segment := range route.segments {
+			if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+				params[strings.Trim(segment, "{}")] = segments[i]
+			} else if segment != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return route, params, true
+		}
+	}
+	return route{}, nil, false
+}
+
+// fault returns the first fault matching the request and consumes one of its times
+func (s *Server) fault(method, pattern string) *Fault {
+	for i, fault := range s.faults {
+		if fault.Path != pattern || (fault.Method != "" && fault.Method != method) {
+			continue
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+// SetDeploymentTargetStatus sets the status of the deployment target, like "healthy" or "unhealthy"
+func (s *Server) SetDeploymentTargetStatus(id, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, ok := s.deploymentTargets.get(id)
+	if !ok {
+		return fmt.Errorf("deployment target [%s] not found", id)
+	}
+	target.Status = pds.PtrString(status)
+	return nil
+}
+
+// ready counts a read of the object in progress and returns whether it is ready
+func (s *Server) ready(id string) bool {
+	s.reads[id]++
+	return s.reads[id] > s.ReadyAfter
+}
+
+// completed returns whether the object in progress is reported ready on its next read
+func (s *Server) completed(id string) bool {
+	return s.reads[id] >= s.ReadyAfter
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func notFound(kind, id string) (int, interface{}) {
+	return http.StatusNotFound, errorResponse{Message: fmt.Sprintf("%s %s not found", kind, id)}
+}
+
+func badRequest(format string, args ...interface{}) (int, interface{}) {
+	return http.StatusBadRequest, errorResponse{Message: fmt.Sprintf(format, args...)}
+}
+
+func conflict(format string, args ...interface{}) (int, interface{}) {
+	return http.StatusConflict, errorResponse{Message: fmt.Sprintf(format, args...)}
+}
+
+// decode decodes the body of the request into v
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %v", err)
+	}
+	return nil
+}
+
+func newID() string {
+	return uuid.New().String()
+}
+
+func now() *string {
+	return pds.PtrString(time.Now().UTC().Format(time.RFC3339))
+}
+
+// collection is a set of objects keyed by id, listed in the order they were created
+type collection[T any] struct {
+	ids   []string
+	items map[string]*T
+}
+
+func newCollection[T any]() *collection[T] {
+	return &collection[T]{items: make(map[string]*T)}
+}
+
+func (c *collection[T]) put(id string, item *T) {
+	if _, ok := c.items[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.items[id] = item
+}
+
+func (c *collection[T]) get(id string) (*T, bool) {
+	item, ok := c.items[id]
+	return item, ok
+}
+
+func (c *collection[T]) delete(id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+	delete(c.items, id)
+	for i, existing := range c.ids {
+		if existing == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// list returns the objects matching the filter, all of them if the filter is nil
+func (c *collection[T]) list(filter func(*T) bool) []T {
+	items := []T{}
+	for _, id := range c.ids {
+		if item := c.items[id]; filter == nil || filter(item) {
+			items = append(items, *item)
+		}
+	}
+	return items
+}
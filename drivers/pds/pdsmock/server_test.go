@@ -0,0 +1,291 @@
+package pdsmock
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+	"github.com/portworx/torpedo/drivers/pds/api"
+	"github.com/portworx/torpedo/drivers/pds/dataservice"
+	"github.com/portworx/torpedo/drivers/pds/pdsbackup"
+	"github.com/portworx/torpedo/drivers/pds/pdsrestore"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func newTestServer(t *testing.T) (*Server, *api.Components) {
+	s := NewServer()
+	t.Cleanup(s.Close)
+	for key, value := range s.Env() {
+		t.Setenv(key, value)
+	}
+	return s, api.NewComponents(pds.NewAPIClient(s.Configuration()))
+}
+
+func TestSeed(t *testing.T) {
+	s, components := newTestServer(t)
+
+	accounts, err := components.Account.GetAccountsList()
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	require.Equal(t, AccountName, accounts[0].GetName())
+	require.NoError(t, components.Account.AcceptEULA(s.Seed.AccountID, "1.0"))
+	account, err := components.Account.GetAccount(s.Seed.AccountID)
+	require.NoError(t, err)
+	require.True(t, account.Eula.GetAccepted())
+
+	tenants, err := components.Tenant.GetTenantsList(s.Seed.AccountID)
+	require.NoError(t, err)
+	require.Equal(t, s.Seed.TenantID, tenants[0].GetId())
+	dns, err := components.Tenant.GetDNS(s.Seed.TenantID)
+	require.NoError(t, err)
+	require.Equal(t, DNSZone, dns.GetDnsZone())
+	projects, err := components.Project.GetprojectsList(s.Seed.TenantID)
+	require.NoError(t, err)
+	require.Equal(t, ProjectName, projects[0].GetName())
+
+	targets, err := components.DeploymentTarget.ListDeploymentTargetsBelongsToProject(s.Seed.ProjectID)
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	require.Equal(t, "healthy", targets[0].GetStatus())
+	require.NoError(t, s.SetDeploymentTargetStatus(s.Seed.DeploymentTargetID, "unhealthy"))
+	target, err := components.DeploymentTarget.GetTarget(s.Seed.DeploymentTargetID)
+	require.NoError(t, err)
+	require.Equal(t, "unhealthy", target.GetStatus())
+
+	dataServices, err := components.DataService.ListDataServices()
+	require.NoError(t, err)
+	require.Len(t, dataServices, len(seedDataServices))
+	versions, err := components.Version.ListDataServiceVersions(s.Seed.DataServices["PostgreSQL"])
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	images, err := components.Image.ListImages(versions[0].GetId())
+	require.NoError(t, err)
+	require.Equal(t, s.Seed.Images["PostgreSQL"], images[0].GetId())
+}
+
+func TestTemplates(t *testing.T) {
+	s, components := newTestServer(t)
+	dataServiceID := s.Seed.DataServices["MySQL"]
+
+	resourceTemplate, err := components.ResourceSettingsTemplate.CreateTemplate(s.Seed.TenantID, "2", "1", dataServiceID, "4G", "2G", "custom", "20G")
+	require.NoError(t, err)
+	require.Equal(t, "4G", resourceTemplate.GetMemoryLimit())
+	resourceTemplate, err = components.ResourceSettingsTemplate.UpdateTemplate(resourceTemplate.GetId(), "4", "2", "8G", "4G", "custom", "40G")
+	require.NoError(t, err)
+	require.Equal(t, "40G", resourceTemplate.GetStorageRequest())
+	resourceTemplates, err := components.ResourceSettingsTemplate.ListTemplates(s.Seed.TenantID)
+	require.NoError(t, err)
+	require.Len(t, resourceTemplates, len(seedDataServices)+1)
+
+	storageTemplate, err := components.StorageSettingsTemplate.CreateTemplate(s.Seed.TenantID, false, "ext4", "custom", "pxd.portworx.com", 3, true)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), storageTemplate.GetRepl())
+
+	appConfigTemplate, err := components.AppConfigTemplate.CreateTemplate(s.Seed.TenantID, dataServiceID, "custom", []pds.ModelsConfigItem{{Key: pds.PtrString("MAX_CONNECTIONS"), Value: pds.PtrString("100")}})
+	require.NoError(t, err)
+	require.Len(t, appConfigTemplate.ConfigItems, 1)
+	_, err = components.AppConfigTemplate.CreateTemplate(s.Seed.TenantID, "missing", "custom", nil)
+	require.Error(t, err)
+
+	_, err = components.ResourceSettingsTemplate.DeleteTemplate(resourceTemplate.GetId())
+	require.NoError(t, err)
+	_, err = components.StorageSettingsTemplate.DeleteTemplate(storageTemplate.GetId())
+	require.NoError(t, err)
+	_, err = components.AppConfigTemplate.DeleteTemplate(appConfigTemplate.GetId())
+	require.NoError(t, err)
+	_, err = components.ResourceSettingsTemplate.GetTemplate(resourceTemplate.GetId())
+	require.Error(t, err)
+}
+
+func TestDeployment(t *testing.T) {
+	s, components := newTestServer(t)
+	s.ReadyAfter = 2
+
+	namespace, err := components.Namespace.CreateNamespace(s.Seed.DeploymentTargetID, "pds-namespace")
+	require.NoError(t, err)
+	require.Equal(t, "available", namespace.GetStatus())
+	_, err = components.Namespace.CreateNamespace(s.Seed.DeploymentTargetID, "pds-namespace")
+	require.Error(t, err)
+
+	deployment, err := components.DataServiceDeployment.CreateDeployment(s.Seed.ProjectID, s.Seed.DeploymentTargetID, DNSZone, "pg", namespace.GetId(),
+		s.Seed.AppConfigTemplates["PostgreSQL"], s.Seed.Images["PostgreSQL"], 3, "ClusterIP", s.Seed.ResourceTemplates["PostgreSQL"], s.Seed.StorageTemplateID, false)
+	require.NoError(t, err)
+	require.Equal(t, s.Seed.DataServices["PostgreSQL"], deployment.GetDataServiceId())
+	_, err = components.DataServiceDeployment.CreateDeployment(s.Seed.ProjectID, s.Seed.DeploymentTargetID, DNSZone, "pg", namespace.GetId(),
+		s.Seed.AppConfigTemplates["PostgreSQL"], s.Seed.Images["PostgreSQL"], 3, "ClusterIP", s.Seed.ResourceTemplates["MySQL"], s.Seed.StorageTemplateID, false)
+	require.Error(t, err)
+
+	for i := 0; i < s.ReadyAfter; i++ {
+		status, _, err := components.DataServiceDeployment.GetDeploymentStatus(deployment.GetId())
+		require.NoError(t, err)
+		require.Equal(t, "Unavailable", status.GetHealth())
+	}
+	status, _, err := components.DataServiceDeployment.GetDeploymentStatus(deployment.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "Available", status.GetHealth())
+	require.Equal(t, int32(3), status.GetReadyReplicas())
+
+	deployment, err = components.DataServiceDeployment.UpdateDeployment(deployment.GetId(), s.Seed.AppConfigTemplates["PostgreSQL"], s.Seed.Images["PostgreSQL"], 5, s.Seed.ResourceTemplates["PostgreSQL"], nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(5), deployment.GetNodeCount())
+	status, _, err = components.DataServiceDeployment.GetDeploymentStatus(deployment.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "Unavailable", status.GetHealth())
+
+	connectionDetails, _, err := components.DataServiceDeployment.GetConnectionDetails(deployment.GetId())
+	require.NoError(t, err)
+	require.Len(t, connectionDetails.GetNodes(), 5)
+	require.Equal(t, map[string]int32{"pg": 5432}, connectionDetails.GetPorts())
+	credentials, err := components.DataServiceDeployment.GetDeploymentCredentials(deployment.GetId())
+	require.NoError(t, err)
+	require.NotEmpty(t, credentials.GetPassword())
+
+	_, err = components.Namespace.DeleteNamespace(namespace.GetId())
+	require.Error(t, err)
+	_, err = components.DataServiceDeployment.DeleteDeployment(deployment.GetId())
+	require.NoError(t, err)
+	deployments, err := components.DataServiceDeployment.ListDeployments(s.Seed.ProjectID)
+	require.NoError(t, err)
+	require.Empty(t, deployments)
+	_, err = components.Namespace.DeleteNamespace(namespace.GetId())
+	require.NoError(t, err)
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	s, components := newTestServer(t)
+	s.ReadyAfter = 1
+
+	namespace, err := components.Namespace.CreateNamespace(s.Seed.DeploymentTargetID, "pds-namespace")
+	require.NoError(t, err)
+	deployment, err := components.DataServiceDeployment.CreateDeployment(s.Seed.ProjectID, s.Seed.DeploymentTargetID, DNSZone, "mysql", namespace.GetId(),
+		s.Seed.AppConfigTemplates["MySQL"], s.Seed.Images["MySQL"], 1, "ClusterIP", s.Seed.ResourceTemplates["MySQL"], s.Seed.StorageTemplateID, false)
+	require.NoError(t, err)
+
+	backupCredentials, err := components.BackupCredential.CreateS3BackupCredential(s.Seed.TenantID, "s3", "access", "http://minio:9000", "secret")
+	require.NoError(t, err)
+	require.Equal(t, "s3", backupCredentials.GetType())
+	partial, err := components.BackupCredential.GetCloudCredentials(backupCredentials.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "access", partial.S3.GetAccessKey())
+
+	backupTarget, err := components.BackupTarget.CreateBackupTarget(s.Seed.TenantID, "target", backupCredentials.GetId(), "bucket", "us-east-1", "s3")
+	require.NoError(t, err)
+	_, err = components.BackupTarget.SyncToBackupLocation(backupTarget.GetId())
+	require.NoError(t, err)
+	states, err := components.BackupTarget.LisBackupsStateBelongToBackupTarget(backupTarget.GetId())
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.Equal(t, s.Seed.DeploymentTargetID, states[0].GetDeploymentTargetId())
+	require.Equal(t, "successful", states[0].GetState())
+
+	backup, err := components.Backup.CreateBackup(deployment.GetId(), backupTarget.GetId(), true)
+	require.NoError(t, err)
+	jobs, err := components.BackupJob.ListBackupJobsBelongToDeployment(s.Seed.ProjectID, deployment.GetId())
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "Running", jobs[0].GetCompletionStatus())
+	jobStatuses, err := components.BackupJob.ListBackupJobs(backup.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "Succeeded", jobStatuses[0].GetStatus())
+
+	_, err = components.Restore.RestoreToNewDeployment(jobs[0].GetId(), "mysql", s.Seed.DeploymentTargetID, namespace.GetId())
+	require.Error(t, err)
+	restore, err := components.Restore.RestoreToNewDeployment(jobs[0].GetId(), "mysql-restored", s.Seed.DeploymentTargetID, namespace.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "Running", restore.GetStatus())
+	restore, err = components.Restore.GetRestore(restore.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "Running", restore.GetStatus())
+	restore, err = components.Restore.GetRestore(restore.GetId())
+	require.NoError(t, err)
+	require.Equal(t, "Successful", restore.GetStatus())
+	restored, err := components.DataServiceDeployment.GetDeployment(restore.GetDeploymentId())
+	require.NoError(t, err)
+	require.Equal(t, deployment.GetImageId(), restored.GetImageId())
+	require.Equal(t, restore.GetId(), restored.GetRestoreId())
+
+	_, err = components.BackupCredential.DeleteBackupCredential(backupCredentials.GetId())
+	require.Error(t, err)
+	_, err = components.BackupTarget.DeleteBackupTarget(backupTarget.GetId())
+	require.NoError(t, err)
+	_, err = components.Backup.GetBackup(backup.GetId())
+	require.Error(t, err)
+	_, err = components.BackupCredential.DeleteBackupCredential(backupCredentials.GetId())
+	require.NoError(t, err)
+}
+
+func TestDeployBackupAndRestoreFlow(t *testing.T) {
+	s, components := newTestServer(t)
+	// DeployDS reads the default parameters relative to the tests directory the suites run from
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir("../../../tests"))
+	t.Cleanup(func() { os.Chdir(wd) })
+	t.Setenv("PDS_PARAM_CM", "")
+
+	d, err := dataservice.DataserviceInit(s.URL)
+	require.NoError(t, err)
+	namespace, err := components.Namespace.CreateNamespace(s.Seed.DeploymentTargetID, "pds-namespace")
+	require.NoError(t, err)
+	deployment, _, _, err := d.DeployDS(dataservice.Mysql, s.Seed.ProjectID, s.Seed.DeploymentTargetID, DNSZone, "mysql", namespace.GetId(),
+		s.Seed.AppConfigTemplates["MySQL"], 1, "ClusterIP", s.Seed.ResourceTemplates["MySQL"], s.Seed.StorageTemplateID, "8.0.31", "", namespace.GetName(), false)
+	require.NoError(t, err)
+	require.Equal(t, s.Seed.Images["MySQL"], deployment.GetImageId())
+	require.NotEmpty(t, deployment.Resources.GetStorageRequest())
+
+	backupCredentials, err := components.BackupCredential.CreateS3BackupCredential(s.Seed.TenantID, "s3", "access", "http://minio:9000", "secret")
+	require.NoError(t, err)
+	backupTarget, err := components.BackupTarget.CreateBackupTarget(s.Seed.TenantID, "target", backupCredentials.GetId(), "bucket", "us-east-1", "s3")
+	require.NoError(t, err)
+	backupClient := &pdsbackup.BackupClient{Components: components}
+	require.NoError(t, backupClient.TriggerAndValidateAdhocBackup(deployment.GetId(), backupTarget.GetId(), "s3"))
+	jobs, err := components.BackupJob.ListBackupJobsBelongToDeployment(s.Seed.ProjectID, deployment.GetId())
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	restoreClient := &pdsrestore.RestoreClient{TenantId: s.Seed.TenantID, ProjectId: s.Seed.ProjectID, Components: components}
+	nsName, namespaceID, err := restoreClient.GetNameSpaceNameToRestore(jobs[0].GetId(), s.Seed.DeploymentTargetID, namespace.GetName(), true)
+	require.NoError(t, err)
+	require.Equal(t, namespace.GetId(), namespaceID)
+	backedUp := pdsrestore.DSEntity{Deployment: deployment}
+	restore, err := restoreClient.RestoreDataServiceWithRbac(s.Seed.DeploymentTargetID, jobs[0].GetId(), nsName, backedUp, namespaceID, false)
+	require.NoError(t, err)
+	require.NoError(t, wait.PollImmediate(time.Millisecond, time.Second, func() (bool, error) {
+		restore, err = components.Restore.GetRestore(restore.GetId())
+		return restore.GetStatus() == "Successful", err
+	}))
+	restored, err := components.DataServiceDeployment.GetDeployment(restore.GetDeploymentId())
+	require.NoError(t, err)
+	require.NoError(t, restoreClient.ValidateRestore(backedUp, pdsrestore.DSEntity{Deployment: restored}))
+}
+
+func TestFaults(t *testing.T) {
+	s, components := newTestServer(t)
+	namespace, err := components.Namespace.CreateNamespace(s.Seed.DeploymentTargetID, "pds-namespace")
+	require.NoError(t, err)
+
+	s.InjectFault(Fault{Method: http.MethodGet, Path: "/api/namespaces/{id}", Status: http.StatusInternalServerError, Times: 1})
+	_, err = components.Namespace.GetNamespace(namespace.GetId())
+	require.Error(t, err)
+	_, err = components.Namespace.GetNamespace(namespace.GetId())
+	require.NoError(t, err)
+	require.Equal(t, 2, s.Requests(http.MethodGet, "/api/namespaces/{id}"))
+
+	s.InjectFault(Fault{Path: "/api/deployment-targets/{id}/namespaces", Status: http.StatusServiceUnavailable})
+	_, err = components.Namespace.ListNamespaces(s.Seed.DeploymentTargetID)
+	require.Error(t, err)
+	_, err = components.Namespace.CreateNamespace(s.Seed.DeploymentTargetID, "other")
+	require.Error(t, err)
+	s.ClearFaults()
+	namespaces, err := components.Namespace.ListNamespaces(s.Seed.DeploymentTargetID)
+	require.NoError(t, err)
+	require.Len(t, namespaces, 1)
+
+	res, err := http.Get(s.URL + "/api/accounts")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
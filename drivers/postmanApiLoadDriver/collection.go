@@ -0,0 +1,404 @@
+package postmanApiLoadDriver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pborman/uuid"
+)
+
+// Kinds of the assertions parsed from the test scripts
+const (
+	AssertStatus       = "status"
+	AssertOK           = "ok"
+	AssertHeader       = "header"
+	AssertBodyContains = "body-contains"
+	AssertResponseTime = "response-time"
+)
+
+// Collection is a Postman v2.1 collection
+type Collection struct {
+	Info     CollectionInfo `json:"info"`
+	Item     []*Item        `json:"item"`
+	Variable []*KeyValue    `json:"variable"`
+	Auth     *Auth          `json:"auth"`
+}
+
+// CollectionInfo describes a collection
+type CollectionInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// Item is a request of a collection, or a folder of items
+type Item struct {
+	Name    string   `json:"name"`
+	Item    []*Item  `json:"item"`
+	Request *Request `json:"request"`
+	Event   []*Event `json:"event"`
+	Auth    *Auth    `json:"auth"`
+}
+
+// Event is a script run before or after a request
+type Event struct {
+	Listen string `json:"listen"`
+	Script Script `json:"script"`
+}
+
+// Script of an event, its lines are exported either as a string or as a list
+type Script struct {
+	Exec []string `json:"-"`
+	Type string   `json:"type"`
+}
+
+// UnmarshalJSON accepts exec as a string or as a list of lines
+func (s *Script) UnmarshalJSON(data []byte) error {
+	var script struct {
+		Exec json.RawMessage `json:"exec"`
+		Type string          `json:"type"`
+	}
+	if err := json.Unmarshal(data, &script); err != nil {
+		return err
+	}
+	s.Type = script.Type
+	if len(script.Exec) == 0 {
+		return nil
+	}
+	var line string
+	if err := json.Unmarshal(script.Exec, &line); err == nil {
+		s.Exec = strings.Split(line, "\n")
+		return nil
+	}
+	return json.Unmarshal(script.Exec, &s.Exec)
+}
+
+// Request of an item
+type Request struct {
+	Method string      `json:"method"`
+	Header []*KeyValue `json:"header"`
+	Body   *Body       `json:"body"`
+	URL    URL         `json:"url"`
+	Auth   *Auth       `json:"auth"`
+}
+
+// UnmarshalJSON accepts a request exported as its URL only
+func (r *Request) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*r = Request{Method: "GET", URL: URL{Raw: raw}}
+		return nil
+	}
+	type request Request
+	return json.Unmarshal(data, (*request)(r))
+}
+
+// URL of a request, only its raw form is used as the others are derived from it
+type URL struct {
+	Raw string `json:"raw"`
+}
+
+// UnmarshalJSON accepts a URL exported as a string
+func (u *URL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = URL{Raw: raw}
+		return nil
+	}
+	type postmanURL URL
+	return json.Unmarshal(data, (*postmanURL)(u))
+}
+
+// Body of a request
+type Body struct {
+	Mode       string      `json:"mode"`
+	Raw        string      `json:"raw"`
+	URLEncoded []*KeyValue `json:"urlencoded"`
+	Options    struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+// Auth of a request, inherited from its folders and collection if not set
+type Auth struct {
+	Type   string      `json:"type"`
+	Bearer []*KeyValue `json:"bearer"`
+	Basic  []*KeyValue `json:"basic"`
+	APIKey []*KeyValue `json:"apikey"`
+}
+
+// KeyValue is a header, a query parameter, a variable or an auth parameter
+type KeyValue struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// Assertion is a check of a response parsed from a test script
+type Assertion struct {
+	// Test is the name of the pm.test the assertion is in
+	Test string
+	// Kind of the assertion
+	Kind string
+	// Value expected, the status code, header name, body substring or maximum response time in ms
+	Value string
+}
+
+// Extraction sets a variable from the JSON response, as pm.globals.set does in a test script
+type Extraction struct {
+	// Variable set
+	Variable string
+	// Path of the value in the JSON response, like data[0].id
+	Path string
+	// Value set if Path is empty
+	Value string
+}
+
+// CollectionRequest is a request of a collection with its inherited auth and the checks of its test scripts
+type CollectionRequest struct {
+	// Name of the request, prefixed by the names of its folders
+	Name        string
+	Request     *Request
+	Auth        *Auth
+	Assertions  []*Assertion
+	Extractions []*Extraction
+	// Unparsed are the pm.test and pm.expect statements of the test scripts
+	// which are not supported, their checks not being run
+	Unparsed []string
+}
+
+var (
+	testNameRegex      = regexp.MustCompile(`pm\.test\(\s*["'](.+?)["']`)
+	checkRegex         = regexp.MustCompile(`pm\.(?:expect\(|response\.to\.)`)
+	statusRegex        = regexp.MustCompile(`pm\.response\.to\.have\.status\(\s*(\d+)\s*\)`)
+	expectStatusRegex  = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.code\s*\)\.to\.(?:eql|equal|eq|be\.equal)\(\s*(\d+)\s*\)`)
+	okRegex            = regexp.MustCompile(`pm\.response\.to\.be\.(?:ok|success)\b`)
+	headerRegex        = regexp.MustCompile(`pm\.response\.to\.have\.header\(\s*["']([^"']+)["']`)
+	bodyContainsRegex  = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.text\(\)\s*\)\.to\.include\(\s*["']([^"']*)["']\s*\)`)
+	responseTimeRegex  = regexp.MustCompile(`pm\.expect\(\s*pm\.response\.responseTime\s*\)\.to\.be\.(?:below|lessThan)\(\s*(\d+)\s*\)`)
+	jsonBodyRegex      = regexp.MustCompile(`(?:const|let|var)\s+(\w+)\s*=\s*(?:JSON\.parse\(\s*responseBody\s*\)|pm\.response\.json\(\))`)
+	jsonAliasRegex     = regexp.MustCompile(`(?:const|let|var)\s+(\w+)\s*=\s*(\w+)((?:\.\w+|\[\d+\])+)\s*;?`)
+	setVariableRegex   = regexp.MustCompile(`pm\.(?:globals|environment|collectionVariables|variables)\.set\(\s*["']([^"']+)["']\s*,\s*(.+?)\s*\)\s*;?\s*$`)
+	stringLiteralRegex = regexp.MustCompile(`^["'](.*)["']$`)
+	jsonPathRegex      = regexp.MustCompile(`^((?:\.\w+|\[\d+\])+)$`)
+	jsonPathTokenRegex = regexp.MustCompile(`\.(\w+)|\[(\d+)\]`)
+	variableRegex      = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+)
+
+// ParseCollection reads a Postman v2.1 collection
+func ParseCollection(path string) (*Collection, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	collection := &Collection{}
+	if err = json.Unmarshal(data, collection); err != nil {
+		return nil, fmt.Errorf("failed to parse postman collection [%s]: %v", path, err)
+	}
+	return collection, nil
+}
+
+// Requests returns the requests of the collection in order, folders are flattened
+func (c *Collection) Requests() []*CollectionRequest {
+	var requests []*CollectionRequest
+	var walk func(items []*Item, prefix string, auth *Auth)
+	walk = func(items []*Item, prefix string, auth *Auth) {
+		for _, item := range items {
+			itemAuth := auth
+			if item.Auth != nil {
+				itemAuth = item.Auth
+			}
+			if item.Request == nil {
+				walk(item.Item, prefix+item.Name+"/", itemAuth)
+				continue
+			}
+			if item.Request.Auth != nil {
+				itemAuth = item.Request.Auth
+			}
+			request := &CollectionRequest{
+				Name:    prefix + item.Name,
+				Request: item.Request,
+				Auth:    itemAuth,
+			}
+			for _, event := range item.Event {
+				if event.Listen == "test" {
+					request.parseTestScript(event.Script.Exec)
+				}
+			}
+			requests = append(requests, request)
+		}
+	}
+	walk(c.Item, "", c.Auth)
+	return requests
+}
+
+// Variables returns the enabled variables of the collection
+func (c *Collection) Variables() map[string]string {
+	variables := make(map[string]string)
+	for _, variable := range c.Variable {
+		if !variable.Disabled {
+			variables[variable.Key] = variable.Value
+		}
+	}
+	return variables
+}
+
+// parseTestScript parses the assertions and variables set by a test script, other statements are ignored.
+// The checks which are not supported, and the pm.test with none supported, are added to Unparsed.
+func (r *CollectionRequest) parseTestScript(lines []string) {
+	test, testLine := "", ""
+	testAssertions, testUnparsed := 0, 0
+	endTest := func() {
+		if testLine != "" && len(r.Assertions) == testAssertions && len(r.Unparsed) == testUnparsed {
+			r.Unparsed = append(r.Unparsed, testLine)
+		}
+	}
+	jsonVars := make(map[string]bool)
+	aliases := make(map[string]string)
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if m := testNameRegex.FindStringSubmatch(line); m != nil {
+			endTest()
+			test, testLine = m[1], line
+			testAssertions, testUnparsed = len(r.Assertions), len(r.Unparsed)
+		}
+		assertions := len(r.Assertions)
+		for _, assertion := range []struct {
+			kind  string
+			regex *regexp.Regexp
+		}{
+			{AssertStatus, statusRegex},
+			{AssertStatus, expectStatusRegex},
+			{AssertHeader, headerRegex},
+			{AssertBodyContains, bodyContainsRegex},
+			{AssertResponseTime, responseTimeRegex},
+		} {
+			if m := assertion.regex.FindStringSubmatch(line); m != nil {
+				r.Assertions = append(r.Assertions, &Assertion{Test: test, Kind: assertion.kind, Value: m[1]})
+			}
+		}
+		if okRegex.MatchString(line) {
+			r.Assertions = append(r.Assertions, &Assertion{Test: test, Kind: AssertOK})
+		}
+		if len(r.Assertions) == assertions && checkRegex.MatchString(line) {
+			r.Unparsed = append(r.Unparsed, line)
+		}
+
+		if m := jsonBodyRegex.FindStringSubmatch(line); m != nil {
+			jsonVars[m[1]] = true
+			continue
+		}
+		if m := jsonAliasRegex.FindStringSubmatch(line); m != nil && jsonVars[m[2]] {
+			aliases[m[1]] = m[3]
+			continue
+		}
+		if m := setVariableRegex.FindStringSubmatch(line); m != nil {
+			extraction := &Extraction{Variable: m[1]}
+			expr := m[2]
+			if path, ok := aliases[expr]; ok {
+				extraction.Path = strings.TrimPrefix(path, ".")
+			} else if lit := stringLiteralRegex.FindStringSubmatch(expr); lit != nil {
+				extraction.Value = lit[1]
+			} else if name, path := splitJSONExpr(expr); jsonVars[name] {
+				extraction.Path = strings.TrimPrefix(path, ".")
+			} else {
+				continue
+			}
+			r.Extractions = append(r.Extractions, extraction)
+		}
+	}
+	endTest()
+}
+
+// splitJSONExpr splits an expression like responseJson.data[0].id into its variable and path
+func splitJSONExpr(expr string) (string, string) {
+	i := strings.IndexAny(expr, ".[")
+	if i <= 0 || !jsonPathRegex.MatchString(expr[i:]) {
+		return "", ""
+	}
+	return expr[:i], expr[i:]
+}
+
+// jsonPathValue returns the value at the path, like data[0].id, of a decoded JSON document
+func jsonPathValue(doc interface{}, path string) (string, error) {
+	value := doc
+	for _, token := range jsonPathTokenRegex.FindAllStringSubmatch("."+path, -1) {
+		if token[1] != "" {
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return "", fmt.Errorf("[%s] is not an object in [%s]", token[1], path)
+			}
+			if value, ok = obj[token[1]]; !ok {
+				return "", fmt.Errorf("[%s] not found in [%s]", token[1], path)
+			}
+			continue
+		}
+		index, _ := strconv.Atoi(token[2])
+		list, ok := value.([]interface{})
+		if !ok || index >= len(list) {
+			return "", fmt.Errorf("index [%d] out of range in [%s]", index, path)
+		}
+		value = list[index]
+	}
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	case nil:
+		return "", fmt.Errorf("[%s] is null", path)
+	default:
+		data, err := json.Marshal(value)
+		return string(data), err
+	}
+}
+
+// substitute replaces the {{variables}} of s, unknown variables are kept
+func substitute(s string, variables map[string]string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return variableRegex.ReplaceAllStringFunc(s, func(match string) string {
+		name := variableRegex.FindStringSubmatch(match)[1]
+		switch name {
+		case "$guid", "$randomUUID":
+			return uuid.New()
+		case "$timestamp":
+			return strconv.FormatInt(time.Now().Unix(), 10)
+		case "$randomInt":
+			return strconv.Itoa(rand.Intn(1000))
+		}
+		if value, ok := variables[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// authParam returns the value of an auth parameter
+func authParam(params []*KeyValue, key string) string {
+	for _, param := range params {
+		if param.Key == key {
+			return param.Value
+		}
+	}
+	return ""
+}
+
+// encodeForm encodes urlencoded body parameters
+func encodeForm(params []*KeyValue, variables map[string]string) string {
+	form := url.Values{}
+	for _, param := range params {
+		if !param.Disabled {
+			form.Add(substitute(param.Key, variables), substitute(param.Value, variables))
+		}
+	}
+	return form.Encode()
+}
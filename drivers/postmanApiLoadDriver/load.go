@@ -0,0 +1,472 @@
+package postmanApiLoadDriver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portworx/torpedo/pkg/log"
+	"golang.org/x/time/rate"
+)
+
+// DefaultRequestTimeout is the timeout of each request of a load run if not set
+const DefaultRequestTimeout = 30 * time.Second
+
+// LoadOptions of a load run. The run stops when every virtual user has run the collection Iterations times
+// or after Duration, whichever comes first.
+type LoadOptions struct {
+	// Concurrency is the number of virtual users running the collection in parallel, 1 if not set
+	Concurrency int
+	// Rate is the maximum number of requests per second of all virtual users, unlimited if not set
+	Rate float64
+	// Duration of the run, unlimited if not set
+	Duration time.Duration
+	// Iterations of the collection by each virtual user, unlimited if Duration is set, 1 otherwise
+	Iterations int
+	// Variables override the collection variables, e.g. the URL of the control plane or credentials
+	Variables map[string]string
+	// Timeout of each request, DefaultRequestTimeout if not set
+	Timeout time.Duration
+	// Client sends the requests, a client with Timeout is used if nil
+	Client *http.Client
+	// Strict fails the run if test scripts have pm.test or pm.expect statements which are not supported,
+	// else they are logged and their checks skipped
+	Strict bool
+}
+
+// LatencyStats are the latency percentiles of requests
+type LatencyStats struct {
+	Min  time.Duration `json:"min"`
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P90  time.Duration `json:"p90"`
+	P95  time.Duration `json:"p95"`
+	P99  time.Duration `json:"p99"`
+	Max  time.Duration `json:"max"`
+}
+
+// RequestResult are the results of a request of the collection
+type RequestResult struct {
+	// Name of the request
+	Name string `json:"name"`
+	// Method of the request
+	Method string `json:"method"`
+	// Requests is the number of requests sent
+	Requests int `json:"requests"`
+	// Failures is the number of requests which failed
+	Failures int `json:"failures"`
+	// Latency of the requests which got a response
+	Latency LatencyStats `json:"latency"`
+	// StatusCodes is the number of responses by status code
+	StatusCodes map[int]int `json:"statusCodes"`
+	// Errors is the number of failures by cause
+	Errors map[string]int `json:"errors,omitempty"`
+
+	latencies []time.Duration
+}
+
+// Result of a load run
+type Result struct {
+	// Collection is the name of the collection
+	Collection string `json:"collection"`
+	// Start of the run
+	Start time.Time `json:"start"`
+	// Duration of the run
+	Duration time.Duration `json:"duration"`
+	// Iterations of the collection completed by all virtual users
+	Iterations int `json:"iterations"`
+	// Requests is the number of requests sent
+	Requests int `json:"requests"`
+	// Failures is the number of requests which failed
+	Failures int `json:"failures"`
+	// Throughput in requests per second
+	Throughput float64 `json:"throughput"`
+	// Latency of all requests which got a response
+	Latency LatencyStats `json:"latency"`
+	// Errors is the number of failures by cause, prefixed by the request name
+	Errors map[string]int `json:"errors,omitempty"`
+	// RequestResults are the results of each request, in the order of the collection
+	RequestResults []*RequestResult `json:"requestResults"`
+}
+
+// String summarizes the result
+func (r *Result) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d iterations, %d requests, %d failures in %v (%.1f req/s), latency p50 %v p95 %v p99 %v max %v",
+		r.Collection, r.Iterations, r.Requests, r.Failures, r.Duration.Round(time.Millisecond), r.Throughput,
+		r.Latency.P50, r.Latency.P95, r.Latency.P99, r.Latency.Max)
+	for _, cause := range sortedCauses(r.Errors) {
+		fmt.Fprintf(&b, "\n  %d x %s", r.Errors[cause], cause)
+	}
+	return b.String()
+}
+
+// unparsedStatements logs the test statements of the requests which are not supported and returns their number
+func unparsedStatements(requests []*CollectionRequest) int {
+	unparsed := 0
+	for _, request := range requests {
+		for _, statement := range request.Unparsed {
+			log.Warnf("Test statement of request [%s] is not supported: %s", request.Name, statement)
+		}
+		unparsed += len(request.Unparsed)
+	}
+	return unparsed
+}
+
+// virtualUser runs the requests of the collection in order, with its own variables so that the variables set
+// by a request are used by the next ones
+type virtualUser struct {
+	requests  []*CollectionRequest
+	variables map[string]string
+	client    *http.Client
+	limiter   *rate.Limiter
+	results   []*RequestResult
+
+	iterations int
+}
+
+// RunLoad runs the collection with the load options and returns the results
+func RunLoad(collection *Collection, opts LoadOptions) (*Result, error) {
+	requests := collection.Requests()
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("postman collection [%s] has no request", collection.Info.Name)
+	}
+	if unparsed := unparsedStatements(requests); unparsed > 0 {
+		if opts.Strict {
+			return nil, fmt.Errorf("postman collection [%s] has %d test statements which are not supported", collection.Info.Name, unparsed)
+		}
+		log.Warnf("Skipping %d test statements of postman collection [%s] which are not supported", unparsed, collection.Info.Name)
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Iterations <= 0 && opts.Duration <= 0 {
+		opts.Iterations = 1
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultRequestTimeout
+	}
+	client := opts.Client
+	if client == nil {
+		client = &http.Client{Timeout: opts.Timeout}
+	}
+	var limiter *rate.Limiter
+	if opts.Rate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(opts.Rate), 1)
+	}
+
+	ctx := context.Background()
+	if opts.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Duration)
+		defer cancel()
+	}
+
+	users := make([]*virtualUser, opts.Concurrency)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range users {
+		users[i] = &virtualUser{
+			requests:  requests,
+			variables: collection.Variables(),
+			client:    client,
+			limiter:   limiter,
+			results:   newRequestResults(requests),
+		}
+		for key, value := range opts.Variables {
+			users[i].variables[key] = value
+		}
+		wg.Add(1)
+		go func(user *virtualUser) {
+			defer wg.Done()
+			user.run(ctx, opts.Iterations)
+		}(users[i])
+	}
+	wg.Wait()
+
+	result := &Result{
+		Collection:     collection.Info.Name,
+		Start:          start,
+		Duration:       time.Since(start),
+		Errors:         make(map[string]int),
+		RequestResults: newRequestResults(requests),
+	}
+	var latencies []time.Duration
+	for _, user := range users {
+		result.Iterations += user.iterations
+		for i, userResult := range user.results {
+			result.RequestResults[i].merge(userResult)
+		}
+	}
+	for _, requestResult := range result.RequestResults {
+		requestResult.Latency = latencyStats(requestResult.latencies)
+		result.Requests += requestResult.Requests
+		result.Failures += requestResult.Failures
+		for cause, count := range requestResult.Errors {
+			result.Errors[requestResult.Name+": "+cause] += count
+		}
+		latencies = append(latencies, requestResult.latencies...)
+	}
+	result.Latency = latencyStats(latencies)
+	if seconds := result.Duration.Seconds(); seconds > 0 {
+		result.Throughput = float64(result.Requests) / seconds
+	}
+	return result, nil
+}
+
+func newRequestResults(requests []*CollectionRequest) []*RequestResult {
+	results := make([]*RequestResult, len(requests))
+	for i, request := range requests {
+		results[i] = &RequestResult{
+			Name:        request.Name,
+			Method:      request.Request.Method,
+			StatusCodes: make(map[int]int),
+			Errors:      make(map[string]int),
+		}
+	}
+	return results
+}
+
+func (r *RequestResult) merge(other *RequestResult) {
+	r.Requests += other.Requests
+	r.Failures += other.Failures
+	r.latencies = append(r.latencies, other.latencies...)
+	for code, count := range other.StatusCodes {
+		r.StatusCodes[code] += count
+	}
+	for cause, count := range other.Errors {
+		r.Errors[cause] += count
+	}
+}
+
+func (u *virtualUser) run(ctx context.Context, iterations int) {
+	for iterations <= 0 || u.iterations < iterations {
+		for i, request := range u.requests {
+			if u.limiter != nil {
+				if err := u.limiter.Wait(ctx); err != nil {
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			latency, code, cause := u.send(ctx, request)
+			if ctx.Err() != nil {
+				// the run ended while the request was in flight
+				return
+			}
+			result := u.results[i]
+			result.Requests++
+			if code != 0 {
+				result.StatusCodes[code]++
+				result.latencies = append(result.latencies, latency)
+			}
+			if cause != "" {
+				result.Failures++
+				result.Errors[cause]++
+			}
+		}
+		u.iterations++
+	}
+}
+
+// send sends the request and checks its response, it returns the latency, the status code or 0 if no response
+// was received, and the cause of the failure if it failed
+func (u *virtualUser) send(ctx context.Context, request *CollectionRequest) (time.Duration, int, string) {
+	req, err := u.newRequest(ctx, request)
+	if err != nil {
+		return 0, 0, fmt.Sprintf("invalid request: %v", err)
+	}
+	start := time.Now()
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return 0, 0, transportError(err)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	latency := time.Since(start)
+	if err != nil {
+		return latency, resp.StatusCode, transportError(err)
+	}
+	return latency, resp.StatusCode, u.check(request, resp, body, latency)
+}
+
+func (u *virtualUser) newRequest(ctx context.Context, request *CollectionRequest) (*http.Request, error) {
+	var body io.Reader
+	contentType := ""
+	if b := request.Request.Body; b != nil {
+		switch b.Mode {
+		case "raw":
+			body = strings.NewReader(substitute(b.Raw, u.variables))
+			if b.Options.Raw.Language == "json" {
+				contentType = "application/json"
+			}
+		case "urlencoded":
+			body = strings.NewReader(encodeForm(b.URLEncoded, u.variables))
+			contentType = "application/x-www-form-urlencoded"
+		}
+	}
+	method := request.Request.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, substitute(request.Request.URL.Raw, u.variables), body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for _, header := range request.Request.Header {
+		if !header.Disabled {
+			req.Header.Set(substitute(header.Key, u.variables), substitute(header.Value, u.variables))
+		}
+	}
+	if auth := request.Auth; auth != nil {
+		switch auth.Type {
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+substitute(authParam(auth.Bearer, "token"), u.variables))
+		case "basic":
+			req.SetBasicAuth(substitute(authParam(auth.Basic, "username"), u.variables), substitute(authParam(auth.Basic, "password"), u.variables))
+		case "apikey":
+			key := substitute(authParam(auth.APIKey, "key"), u.variables)
+			value := substitute(authParam(auth.APIKey, "value"), u.variables)
+			if authParam(auth.APIKey, "in") == "query" {
+				query := req.URL.Query()
+				query.Set(key, value)
+				req.URL.RawQuery = query.Encode()
+			} else {
+				req.Header.Set(key, value)
+			}
+		}
+	}
+	return req, nil
+}
+
+// check runs the assertions of the request on the response and sets the variables extracted from it.
+// Without status assertion, error statuses fail the request.
+func (u *virtualUser) check(request *CollectionRequest, resp *http.Response, body []byte, latency time.Duration) string {
+	hasStatusAssertion := false
+	for _, assertion := range request.Assertions {
+		failed := ""
+		switch assertion.Kind {
+		case AssertStatus:
+			hasStatusAssertion = true
+			if strconv.Itoa(resp.StatusCode) != assertion.Value {
+				failed = fmt.Sprintf("expected status %s, got %d", assertion.Value, resp.StatusCode)
+			}
+		case AssertOK:
+			hasStatusAssertion = true
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				failed = fmt.Sprintf("expected success status, got %d", resp.StatusCode)
+			}
+		case AssertHeader:
+			if resp.Header.Get(assertion.Value) == "" {
+				failed = fmt.Sprintf("header [%s] not found", assertion.Value)
+			}
+		case AssertBodyContains:
+			if !strings.Contains(string(body), assertion.Value) {
+				failed = fmt.Sprintf("body does not include [%s]", assertion.Value)
+			}
+		case AssertResponseTime:
+			if max, _ := strconv.Atoi(assertion.Value); latency > time.Duration(max)*time.Millisecond {
+				failed = fmt.Sprintf("response time above %sms", assertion.Value)
+			}
+		}
+		if failed != "" {
+			return fmt.Sprintf("assertion [%s] failed: %s", assertion.Test, failed)
+		}
+	}
+	if !hasStatusAssertion && resp.StatusCode >= 400 {
+		return fmt.Sprintf("status %d", resp.StatusCode)
+	}
+
+	if len(request.Extractions) == 0 {
+		return ""
+	}
+	var doc interface{}
+	jsonErr := json.Unmarshal(body, &doc)
+	for _, extraction := range request.Extractions {
+		if extraction.Path == "" {
+			u.variables[extraction.Variable] = extraction.Value
+			continue
+		}
+		if jsonErr != nil {
+			return fmt.Sprintf("cannot set [%s]: invalid JSON response", extraction.Variable)
+		}
+		value, err := jsonPathValue(doc, extraction.Path)
+		if err != nil {
+			return fmt.Sprintf("cannot set [%s]: %v", extraction.Variable, err)
+		}
+		u.variables[extraction.Variable] = value
+	}
+	return ""
+}
+
+// transportError returns the cause of a failure to get a response, without the URL so that failures
+// of the same request are counted together
+func transportError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	return err.Error()
+}
+
+// latencyStats returns the percentiles of the latencies, nearest rank
+func latencyStats(latencies []time.Duration) LatencyStats {
+	if len(latencies) == 0 {
+		return LatencyStats{}
+	}
+	sorted := append([]time.Duration{}, latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	percentile := func(p int) time.Duration {
+		rank := (p*len(sorted) + 99) / 100
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1]
+	}
+	return LatencyStats{
+		Min:  sorted[0],
+		Mean: total / time.Duration(len(sorted)),
+		P50:  percentile(50),
+		P90:  percentile(90),
+		P95:  percentile(95),
+		P99:  percentile(99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+func sortedCauses(counts map[string]int) []string {
+	causes := make([]string, 0, len(counts))
+	for cause := range counts {
+		causes = append(causes, cause)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		if counts[causes[i]] != counts[causes[j]] {
+			return counts[causes[i]] > counts[causes[j]]
+		}
+		return causes[i] < causes[j]
+	})
+	return causes
+}
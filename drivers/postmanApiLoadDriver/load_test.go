@@ -0,0 +1,175 @@
+package postmanApiLoadDriver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testCollection = `{
+	"info": {"name": "test", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+	"variable": [{"key": "baseUrl", "value": "http://localhost"}],
+	"auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{auth_token}}", "type": "string"}]},
+	"item": [
+		{
+			"name": "Login",
+			"event": [{"listen": "test", "script": {"type": "text/javascript", "exec": [
+				"pm.test(\"response is ok\",  ()=>{",
+				"   if( pm.response.to.have.status(200)){",
+				"const responseJson = JSON.parse(responseBody);",
+				"const token = responseJson.data[0].jwt_token;",
+				"pm.globals.set(\"auth_token\", token);",
+				"}",
+				"})"
+			]}}],
+			"request": {"auth": {"type": "noauth"}, "method": "POST", "url": {"raw": "{{baseUrl}}/login"},
+				"body": {"mode": "raw", "raw": "{\"user\": \"{{user}}\"}", "options": {"raw": {"language": "json"}}}}
+		},
+		{
+			"name": "Accounts",
+			"item": [
+				{
+					"name": "List",
+					"event": [{"listen": "test", "script": {"type": "text/javascript", "exec": "pm.test(\"accounts\", function () {\n  pm.response.to.have.status(200);\n  pm.expect(pm.response.text()).to.include(\"automation\");\n});"}}],
+					"request": {"method": "GET", "url": "{{baseUrl}}/accounts"}
+				},
+				{
+					"name": "Broken",
+					"request": {"method": "GET", "url": "{{baseUrl}}/broken"}
+				}
+			]
+		}
+	]
+}`
+
+func TestParseCollection(t *testing.T) {
+	collection, err := ParseCollection("collections/collection.json")
+	require.NoError(t, err)
+	requests := collection.Requests()
+	require.NotEmpty(t, requests)
+	require.Equal(t, "Get API KEY", requests[0].Name)
+	require.Equal(t, []*Assertion{{Test: "response is ok", Kind: AssertStatus, Value: "200"}}, requests[0].Assertions)
+	require.Equal(t, []*Extraction{{Variable: "auth_token", Path: "data[0].jwt_token"}}, requests[0].Extractions)
+	require.Equal(t, "bearer", requests[1].Auth.Type)
+	for _, request := range requests {
+		require.Empty(t, request.Unparsed)
+	}
+}
+
+func TestParseTestScriptUnparsed(t *testing.T) {
+	request := &CollectionRequest{Name: "List"}
+	request.parseTestScript([]string{
+		`pm.test("status", function () {`,
+		`  pm.response.to.have.status(200);`,
+		`  pm.expect(pm.response.json().items).to.have.lengthOf(2);`,
+		`});`,
+		`pm.test("schema", function () {`,
+		`  const schema = {type: "object"};`,
+		`  tv4.validate(pm.response.json(), schema);`,
+		`});`,
+	})
+	require.Equal(t, []*Assertion{{Test: "status", Kind: AssertStatus, Value: "200"}}, request.Assertions)
+	require.Equal(t, []string{
+		`pm.expect(pm.response.json().items).to.have.lengthOf(2);`,
+		`pm.test("schema", function () {`,
+	}, request.Unparsed)
+
+	collection := &Collection{Item: []*Item{{
+		Name:    "List",
+		Event:   []*Event{{Listen: "test", Script: Script{Exec: []string{`pm.expect(pm.response.code).to.be.oneOf([200, 201]);`}}}},
+		Request: &Request{Method: http.MethodGet, URL: URL{Raw: "http://localhost/list"}},
+	}}}
+	_, err := RunLoad(collection, LoadOptions{Strict: true})
+	require.ErrorContains(t, err, "1 test statements which are not supported")
+}
+
+func TestRunLoad(t *testing.T) {
+	var loginCount, brokenCount int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["user"] != "admin" || r.Header.Get("Authorization") != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			atomic.AddInt32(&loginCount, 1)
+			w.Write([]byte(`{"data": [{"jwt_token": "secret"}]}`))
+		case "/accounts":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`[{"name": "automation"}]`))
+		default:
+			atomic.AddInt32(&brokenCount, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	collection := &Collection{}
+	require.NoError(t, json.Unmarshal([]byte(testCollection), collection))
+	result, err := RunLoad(collection, LoadOptions{
+		Concurrency: 3,
+		Iterations:  4,
+		Variables:   map[string]string{"baseUrl": server.URL, "user": "admin"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 12, result.Iterations)
+	require.Equal(t, 36, result.Requests)
+	require.Equal(t, 12, result.Failures)
+	require.Equal(t, int32(12), loginCount)
+	require.Equal(t, int32(12), brokenCount)
+	require.Equal(t, map[string]int{"Accounts/Broken: status 500": 12}, result.Errors)
+	require.Len(t, result.RequestResults, 3)
+	require.Equal(t, "Accounts/List", result.RequestResults[1].Name)
+	require.Equal(t, map[int]int{200: 12}, result.RequestResults[1].StatusCodes)
+	require.Zero(t, result.RequestResults[1].Failures)
+	require.True(t, result.Latency.P50 <= result.Latency.P99 && result.Latency.P99 <= result.Latency.Max)
+}
+
+func TestRunLoadAssertionsAndRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	collection := &Collection{}
+	require.NoError(t, json.Unmarshal([]byte(testCollection), collection))
+	start := time.Now()
+	result, err := RunLoad(collection, LoadOptions{
+		Concurrency: 2,
+		Rate:        50,
+		Duration:    200 * time.Millisecond,
+		Variables:   map[string]string{"baseUrl": server.URL},
+	})
+	require.NoError(t, err)
+	require.True(t, time.Since(start) < time.Second)
+	require.True(t, result.Requests > 0 && result.Requests <= 12, "%d requests", result.Requests)
+	require.Equal(t, result.RequestResults[0].Requests, result.RequestResults[0].Failures)
+	require.Contains(t, result.RequestResults[0].Errors, "cannot set [auth_token]: index [0] out of range in [data[0].jwt_token]")
+}
+
+func TestLatencyStats(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	require.Equal(t, LatencyStats{
+		Min:  time.Millisecond,
+		Mean: 50500 * time.Microsecond,
+		P50:  50 * time.Millisecond,
+		P90:  90 * time.Millisecond,
+		P95:  95 * time.Millisecond,
+		P99:  99 * time.Millisecond,
+		Max:  100 * time.Millisecond,
+	}, latencyStats(latencies))
+	require.Equal(t, LatencyStats{}, latencyStats(nil))
+}
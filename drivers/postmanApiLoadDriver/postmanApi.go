@@ -1,80 +1,163 @@
 package postmanApiLoadDriver
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/portworx/torpedo/pkg/log"
-	"github.com/portworx/torpedo/pkg/osutils"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/portworx/torpedo/drivers/monitor"
+	"github.com/portworx/torpedo/drivers/monitor/prometheus"
+	"github.com/portworx/torpedo/pkg/log"
 )
 
 const (
-	defaultCollectionPath = "../drivers/postmanApiLoadDriver/collections/collection.json"
-	defaultResultsPath    = "../drivers/postmanApiLoadDriver/newmanResults/"
-	PxDataServices        = "pds"
+	defaultCollectionsPath = "../drivers/postmanApiLoadDriver/collections/"
+	defaultCollectionPath  = defaultCollectionsPath + "collection.json"
+	defaultResultsPath     = "../drivers/postmanApiLoadDriver/newmanResults/"
+	PxDataServices         = "pds"
 )
 
-// PostmanDriver Struct to define variables to pass to the newman run command
+// PostmanDriver Struct to define the load run of a project
 type PostmanDriver struct {
+	// ResultsFileName is the name of the JSON results file in the results folder
 	ResultsFileName string
-	ResultType      string
-	Namespace       string
-	Iteration       string
-	Kubeconfig      string
+	// ResultType is the comma-separated list of reporters, cli logs the results and json exports them to ResultsFileName
+	ResultType string
+	Namespace  string
+	// Iteration is the number of iterations of the collection by each virtual user
+	Iteration  string
+	Kubeconfig string
+	// CollectionPath is the collection of the project, collections/<project>.json if not set
+	CollectionPath string
+	// Concurrency is the number of virtual users
+	Concurrency int
+	// Rate is the maximum number of requests per second
+	Rate float64
+	// Duration of the run, the iterations are not limited if set
+	Duration time.Duration
+	// Variables override the collection variables, e.g. the URL of the project control plane
+	Variables map[string]string
+	// Monitor the results are pushed to, if set
+	Monitor monitor.Driver
+	// TestName is the test name label of the metrics pushed to Monitor
+	TestName string
+	// Strict fails the run if the test scripts of the collection have statements which are not supported
+	Strict bool
 }
 
-// GetProjectNameToExecutePostman MAIN driver function which will decide which project to run
+// GetProjectNameToExecutePostman MAIN driver function which runs the load of the project's collection
 func GetProjectNameToExecutePostman(projectName string, driver *PostmanDriver) error {
-	if projectName == PxDataServices {
-		_, err := ExecutePostmanCommandInTorpedoForPDS(driver)
-		if err != nil {
-			return fmt.Errorf("postman execution failed.. [%v] Please check the logs manually", err)
-		}
+	result, err := ExecutePostmanLoad(projectName, driver)
+	if err != nil {
+		return fmt.Errorf("postman execution failed.. [%v] Please check the logs manually", err)
+	}
+	if result.Failures > 0 {
+		return fmt.Errorf("postman execution of [%s] had %d failed requests out of %d", projectName, result.Failures, result.Requests)
 	}
 	return nil
-	//ToDo: Add cases for other PX Projects
 }
 
-// GetPostmanCollectionPath to check if collection is present in the folder
-func GetPostmanCollectionPath() (string, error) {
-	postmanCollectionFile, err := filepath.Abs(defaultCollectionPath)
+// GetPostmanCollectionPath to check if the collection of the project is present in the folder
+func GetPostmanCollectionPath(projectName string) (string, error) {
+	collectionPath := defaultCollectionsPath + projectName + ".json"
+	if projectName == PxDataServices {
+		collectionPath = defaultCollectionPath
+	}
+	postmanCollectionFile, err := filepath.Abs(collectionPath)
+	if err == nil {
+		_, err = os.Stat(postmanCollectionFile)
+	}
 	if err != nil {
-		return "", fmt.Errorf("postman Collection Json not found, Please create a Collection json manually and export to {%v} folder", defaultCollectionPath)
+		return "", fmt.Errorf("postman Collection Json not found, Please create a Collection json manually and export to {%v} folder: %v", collectionPath, err)
 	}
 	log.InfoD("PostmanCollectionFile found is- [%v]", postmanCollectionFile)
 	return postmanCollectionFile, nil
 }
 
-// ExecuteCommandInShell to execute commands in shell
-func ExecuteCommandInShell(command string) (string, string, error) {
-	out, res, err := osutils.ExecShell(command)
+// ExecutePostmanLoad runs the collection of the project with the load parameters and reports the results
+func ExecutePostmanLoad(projectName string, postmanParams *PostmanDriver) (*Result, error) {
+	collectionPath := postmanParams.CollectionPath
+	if collectionPath == "" {
+		var err error
+		if collectionPath, err = GetPostmanCollectionPath(projectName); err != nil {
+			return nil, err
+		}
+	}
+	collection, err := ParseCollection(collectionPath)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	return out, res, nil
-}
 
-// ExecutePostmanCommandInTorpedoForPDS execute PDS specific collections
-func ExecutePostmanCommandInTorpedoForPDS(postmanParams *PostmanDriver) (bool, error) {
-	collectionPath, err := GetPostmanCollectionPath()
+	opts := LoadOptions{
+		Concurrency: postmanParams.Concurrency,
+		Rate:        postmanParams.Rate,
+		Duration:    postmanParams.Duration,
+		Variables:   postmanParams.Variables,
+		Strict:      postmanParams.Strict,
+	}
+	if postmanParams.Iteration != "" {
+		if opts.Iterations, err = strconv.Atoi(postmanParams.Iteration); err != nil {
+			return nil, fmt.Errorf("invalid iteration [%s]: %v", postmanParams.Iteration, err)
+		}
+	}
+	log.InfoD("Running postman collection [%s] of [%s] with %d virtual users, %d iterations, rate %v/s, duration %v",
+		collection.Info.Name, projectName, opts.Concurrency, opts.Iterations, opts.Rate, opts.Duration)
+	result, err := RunLoad(collection, opts)
 	if err != nil {
-		return false, fmt.Errorf("postman Collection Json not found [%v], Please create a Collection json manually and export to [%v] folder", err, defaultCollectionPath)
+		return nil, err
+	}
+
+	for _, reporter := range strings.Split(postmanParams.ResultType, ",") {
+		switch strings.TrimSpace(reporter) {
+		case "cli":
+			log.InfoD("Postman execution results: %v", result)
+		case "json":
+			if err = exportResults(result, postmanParams.ResultsFileName); err != nil {
+				return nil, err
+			}
+		}
 	}
-	log.InfoD("Postman Collection found is- %v", collectionPath)
+	if postmanParams.Monitor != nil {
+		if err = PushResults(postmanParams.Monitor, postmanParams.TestName, result); err != nil {
+			log.Warnf("Failed to push the postman execution results to [%s]: %v", postmanParams.Monitor, err)
+		}
+	}
+	return result, nil
+}
 
-	iterations := postmanParams.Iteration
+func exportResults(result *Result, resultsFileName string) error {
 	resultsPath, err := filepath.Abs(defaultResultsPath)
-	resultsFilePath := resultsPath + "/" + postmanParams.ResultsFileName
-	newmanCmd := "newman run " + collectionPath + " -n " + iterations + " --verbose" + " -r " + postmanParams.ResultType + " --reporter-json-export " + resultsFilePath
-	log.InfoD("Newman command formed is- [%v]", newmanCmd)
-	output, res, err := ExecuteCommandInShell(newmanCmd)
 	if err != nil {
-		return false, fmt.Errorf("there was some problem in executing Postman Newman container due to- [%v]", err)
+		return err
 	}
-	log.InfoD("output from the newman execution is- %v", res)
-	if strings.Contains(output, "failure") {
-		return false, fmt.Errorf("newman exited with a failure..[%v] Please check logs for more details", err)
+	resultsFilePath := filepath.Join(resultsPath, resultsFileName)
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(resultsFilePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to export the postman execution results: %v", err)
 	}
 	log.InfoD("Postman execution is completed and the results are exported to filepath - [%v]", resultsFilePath)
-	return true, nil
+	return nil
+}
+
+// PushResults sets the API load metrics of the monitor driver to the results of each request, observes the
+// latency of each request which got a response and pushes them
+func PushResults(m monitor.Driver, testName string, result *Result) error {
+	for _, request := range result.RequestResults {
+		m.SetGaugeMetricWithNonDefaultLabels(prometheus.TorpedoAPILoadRequests, float64(request.Requests), testName, result.Collection, request.Name)
+		for cause, count := range request.Errors {
+			m.SetGaugeMetricWithNonDefaultLabels(prometheus.TorpedoAPILoadFailures, float64(count), testName, result.Collection, request.Name, cause)
+		}
+		for _, latency := range request.latencies {
+			m.ObserveHistogramMetric(prometheus.TorpedoAPILoadLatency, latency.Seconds(), testName, result.Collection, request.Name)
+		}
+	}
+	return m.Push()
 }
@@ -1198,6 +1198,30 @@ func (d *dcos) GetPXCloudDriveConfigMap(cluster *v1.StorageCluster) (map[string]
 	}
 }
 
+// UpgradeChart is not supported
+func (d *dcos) UpgradeChart(repoInfo *scheduler.HelmRepo) (string, error) {
+	return "", &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "UpgradeChart()",
+	}
+}
+
+// RollbackChart is not supported
+func (d *dcos) RollbackChart(repoInfo *scheduler.HelmRepo, revision int) (string, error) {
+	return "", &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "RollbackChart()",
+	}
+}
+
+// HelmHistory is not supported
+func (d *dcos) HelmHistory(repoInfo *scheduler.HelmRepo) ([]scheduler.HelmRevision, error) {
+	return nil, &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "HelmHistory()",
+	}
+}
+
 func init() {
 	d := &dcos{}
 	scheduler.Register(SchedName, d)
@@ -0,0 +1,1913 @@
+package fake
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	volsnapv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
+	snapv1 "github.com/kubernetes-incubator/external-storage/snapshot/pkg/apis/crd/v1"
+	apapi "github.com/libopenstorage/autopilot-api/pkg/apis/autopilot/v1alpha1"
+	operatorcorev1 "github.com/libopenstorage/operator/pkg/apis/core/v1"
+	"github.com/portworx/sched-ops/task"
+	"github.com/portworx/torpedo/drivers/node"
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/drivers/scheduler/k8s"
+	"github.com/portworx/torpedo/drivers/scheduler/spec"
+	"github.com/portworx/torpedo/drivers/volume"
+	"github.com/portworx/torpedo/pkg/errors"
+	"github.com/portworx/torpedo/pkg/fault"
+	"github.com/portworx/torpedo/pkg/log"
+	appsapi "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storageapi "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// SchedName is the name of the fake scheduler driver implementation
+	SchedName = "fake"
+	// DeploymentSuffix is the suffix for deployment names stored in the scale factor map
+	DeploymentSuffix = "-dep"
+	// StatefulSetSuffix is the suffix for statefulset names stored in the scale factor map
+	StatefulSetSuffix = "-ss"
+	// PortworxNamespace is the namespace reported for portworx and autopilot
+	PortworxNamespace = "kube-system"
+	// replParameter is the storage class parameter holding the replication factor of a volume
+	replParameter = "repl"
+)
+
+// Driver is an in-memory scheduler driver. It keeps every scheduled object in an
+// ObjectStore instead of a kubernetes cluster, so tests can exercise trigger
+// logic in plain `go test`. Outcomes of the main operations can be scripted with
+// Script and Fail.
+type Driver struct {
+	sync.Mutex
+	*fault.Faults[Op]
+	store          *ObjectStore
+	specFactory    *spec.Factory
+	specParser     *k8s.K8s
+	apps           map[string]*spec.AppSpec
+	volDriverName  string
+	nodeDriverName string
+	customConfig   map[string]scheduler.AppConfig
+	// uidCounter generates deterministic object UIDs and volume names
+	uidCounter int64
+	// appNodes tracks the nodes where the pods of each scheduled app landed, keyed by app namespace
+	appNodes map[string][]string
+	// disabledNodes tracks nodes which are cordoned
+	disabledNodes map[string]bool
+	// nodeLabels tracks labels added on nodes
+	nodeLabels map[string]map[string]string
+	// owners tracks the namespace of the app which created each cluster scoped object, keyed by kind and name
+	owners map[string]string
+	events map[string][]scheduler.Event
+}
+
+// New returns a new fake scheduler driver with an empty object store
+func New() *Driver {
+	return &Driver{
+		store:         NewObjectStore(),
+		Faults:        fault.NewFaults[Op](),
+		apps:          make(map[string]*spec.AppSpec),
+		appNodes:      make(map[string][]string),
+		disabledNodes: make(map[string]bool),
+		nodeLabels:    make(map[string]map[string]string),
+		owners:        make(map[string]string),
+		events:        make(map[string][]scheduler.Event),
+		specParser:    k8s.NewSpecParser(nil, nil),
+	}
+}
+
+// Store returns the in-memory object store backing the driver
+func (d *Driver) Store() *ObjectStore {
+	return d.store
+}
+
+// AddApp registers an application which can then be scheduled by its key. It
+// allows tests to build specs in code instead of parsing a spec directory.
+func (d *Driver) AddApp(app *spec.AppSpec) {
+	d.Lock()
+	defer d.Unlock()
+	d.apps[app.Key] = app.DeepCopy()
+	d.apps[app.Key].Enabled = true
+}
+
+// Reset drops all scheduled objects, registered apps and scripted outcomes
+func (d *Driver) Reset() {
+	d.Lock()
+	d.apps = make(map[string]*spec.AppSpec)
+	d.appNodes = make(map[string][]string)
+	d.disabledNodes = make(map[string]bool)
+	d.nodeLabels = make(map[string]map[string]string)
+	d.owners = make(map[string]string)
+	d.events = make(map[string][]scheduler.Event)
+	d.uidCounter = 0
+	d.Unlock()
+	d.store.Reset()
+	d.ResetFaults()
+}
+
+// SetReadyReplicas overrides the number of ready replicas of a deployment or
+// statefulset, for e.g. to simulate pods stuck in CrashLoopBackOff
+func (d *Driver) SetReadyReplicas(namespace, name string, ready int32) error {
+	if obj, err := d.store.Get(&appsapi.Deployment{}, namespace, name); err == nil {
+		dep := obj.(*appsapi.Deployment)
+		dep.Status.ReadyReplicas = ready
+		_, err = d.store.Update(dep)
+		return err
+	}
+	obj, err := d.store.Get(&appsapi.StatefulSet{}, namespace, name)
+	if err != nil {
+		return err
+	}
+	ss := obj.(*appsapi.StatefulSet)
+	ss.Status.ReadyReplicas = ready
+	_, err = d.store.Update(ss)
+	return err
+}
+
+// RecordEvent adds an event which will be returned by GetEvents
+func (d *Driver) RecordEvent(kind string, event scheduler.Event) {
+	d.Lock()
+	defer d.Unlock()
+	d.events[kind] = append(d.events[kind], event)
+}
+
+func (d *Driver) String() string {
+	return SchedName
+}
+
+// Init initializes the fake scheduler. Specs are only parsed when a spec directory is given.
+func (d *Driver) Init(schedOpts scheduler.InitOptions) error {
+	d.volDriverName = schedOpts.VolDriverName
+	d.nodeDriverName = schedOpts.NodeDriverName
+	d.customConfig = schedOpts.CustomAppConfig
+	d.specParser = k8s.NewSpecParser(schedOpts.CustomAppConfig, schedOpts.SpecProfiles)
+
+	if schedOpts.SpecDir == "" {
+		return nil
+	}
+	var err error
+	d.specFactory, err = spec.NewFactory(schedOpts.SpecDir, schedOpts.VolDriverName, d)
+	return err
+}
+
+// ParseSpecs parses the specs in the given directory like the k8s scheduler does, rendering
+// templates with the custom app config and skipping the specs of other storage provisioners
+func (d *Driver) ParseSpecs(specDir, storageProvisioner string) ([]interface{}, error) {
+	return d.specParser.ParseSpecs(specDir, storageProvisioner)
+}
+
+// RescanSpecs re-parses the specs in the given directory
+func (d *Driver) RescanSpecs(specDir, storageDriver string) error {
+	var err error
+	d.specFactory, err = spec.NewFactory(specDir, storageDriver, d)
+	return err
+}
+
+func (d *Driver) getApp(key string) (*spec.AppSpec, error) {
+	d.Lock()
+	app, ok := d.apps[key]
+	d.Unlock()
+	if ok {
+		return app.DeepCopy(), nil
+	}
+	if d.specFactory != nil {
+		return d.specFactory.Get(key)
+	}
+	return nil, &errors.ErrNotFound{
+		ID:   key,
+		Type: "AppSpec",
+	}
+}
+
+func (d *Driver) getAllApps() []*spec.AppSpec {
+	d.Lock()
+	var apps []*spec.AppSpec
+	for _, app := range d.apps {
+		apps = append(apps, app.DeepCopy())
+	}
+	d.Unlock()
+	if d.specFactory != nil {
+		apps = append(apps, d.specFactory.GetAll()...)
+	}
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Key < apps[j].Key
+	})
+	return apps
+}
+
+func (d *Driver) nextUID() string {
+	d.Lock()
+	defer d.Unlock()
+	d.uidCounter++
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", d.uidCounter)
+}
+
+// IsNodeReady returns nil unless scheduling is disabled on the node
+func (d *Driver) IsNodeReady(n node.Node) error {
+	d.Lock()
+	defer d.Unlock()
+	if d.disabledNodes[n.Name] {
+		return &scheduler.ErrNodeNotReady{
+			Node:  n,
+			Cause: "node is cordoned",
+		}
+	}
+	return nil
+}
+
+// GetNodesForApp returns the nodes on which the pods of the given app were placed
+func (d *Driver) GetNodesForApp(ctx *scheduler.Context) ([]node.Node, error) {
+	d.Lock()
+	names := d.appNodes[ctx.App.NameSpace]
+	d.Unlock()
+
+	nodesByName := node.GetNodesByName()
+	var result []node.Node
+	for _, name := range names {
+		if n, ok := nodesByName[name]; ok && !node.Contains(result, n) {
+			result = append(result, n)
+		}
+	}
+	if len(result) == 0 {
+		return nil, &scheduler.ErrFailedToGetNodesForApp{
+			App:   ctx.App,
+			Cause: "no pods found for app",
+		}
+	}
+	return result, nil
+}
+
+// Schedule creates the objects of the requested apps in the object store
+func (d *Driver) Schedule(instanceID string, options scheduler.ScheduleOptions) ([]*scheduler.Context, error) {
+	var apps []*spec.AppSpec
+	if len(options.AppKeys) > 0 {
+		for _, key := range options.AppKeys {
+			app, err := d.getApp(key)
+			if err != nil {
+				return nil, err
+			}
+			apps = append(apps, app)
+		}
+	} else {
+		apps = d.getAllApps()
+	}
+	return d.ScheduleWithCustomAppSpecs(apps, instanceID, options)
+}
+
+// ScheduleWithCustomAppSpecs creates the objects of the given apps in the object store
+func (d *Driver) ScheduleWithCustomAppSpecs(apps []*spec.AppSpec, instanceID string, options scheduler.ScheduleOptions) ([]*scheduler.Context, error) {
+	var contexts []*scheduler.Context
+	for _, app := range apps {
+		if err := d.Inject(OpSchedule); err != nil {
+			return nil, err
+		}
+
+		appNamespace := app.GetID(instanceID)
+		if options.Namespace != "" {
+			appNamespace = options.Namespace
+		}
+		specObjects, err := d.createSpecObjects(app, appNamespace, options)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := options
+		opts.Namespace = appNamespace
+		contexts = append(contexts, &scheduler.Context{
+			UID: instanceID,
+			App: &spec.AppSpec{
+				Key:       app.Key,
+				SpecList:  specObjects,
+				Enabled:   app.Enabled,
+				NameSpace: appNamespace,
+			},
+			ScheduleOptions: opts,
+		})
+	}
+	return contexts, nil
+}
+
+// AddTasks creates the objects of the apps in options.AppKeys in the namespace of an existing context
+func (d *Driver) AddTasks(ctx *scheduler.Context, options scheduler.ScheduleOptions) error {
+	if ctx == nil {
+		return fmt.Errorf("context to add tasks to cannot be nil")
+	}
+	if len(options.AppKeys) == 0 {
+		return fmt.Errorf("need to specify list of applications to add to context")
+	}
+	for _, key := range options.AppKeys {
+		app, err := d.getApp(key)
+		if err != nil {
+			return err
+		}
+		specObjects, err := d.createSpecObjects(app, ctx.App.NameSpace, options)
+		if err != nil {
+			return err
+		}
+		ctx.App.SpecList = append(ctx.App.SpecList, specObjects...)
+	}
+	return nil
+}
+
+// ScheduleUninstall removes the objects of the apps in options.AppKeys from an existing context
+func (d *Driver) ScheduleUninstall(ctx *scheduler.Context, options scheduler.ScheduleOptions) error {
+	for _, key := range options.AppKeys {
+		app, err := d.getApp(key)
+		if err != nil {
+			return err
+		}
+		var remove []interface{}
+		for _, appSpec := range app.SpecList {
+			obj, ok := appSpec.(runtime.Object)
+			if !ok {
+				continue
+			}
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			remove = append(remove, appSpec)
+			namespace := ctx.App.NameSpace
+			if !isNamespaced(obj) {
+				// cluster scoped objects like storage classes can be shared with other apps
+				if !d.releaseOwnership(obj, ctx.App.NameSpace) {
+					continue
+				}
+				namespace = ""
+			}
+			if err := d.store.Delete(obj, namespace, accessor.GetName()); err != nil {
+				return err
+			}
+		}
+		if err := d.RemoveAppSpecsByName(ctx, remove); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveAppSpecsByName removes the specs with the same kind and name from the context
+func (d *Driver) RemoveAppSpecsByName(ctx *scheduler.Context, removeSpecs []interface{}) error {
+	var kept []interface{}
+	for _, appSpec := range ctx.App.SpecList {
+		remove := false
+		for _, r := range removeSpecs {
+			if kindOf(appSpec) == kindOf(r) && nameOf(appSpec) == nameOf(r) {
+				remove = true
+				break
+			}
+		}
+		if !remove {
+			kept = append(kept, appSpec)
+		}
+	}
+	ctx.App.SpecList = kept
+	return nil
+}
+
+// UpdateTasksID updates the instance ID of the context
+func (d *Driver) UpdateTasksID(ctx *scheduler.Context, id string) error {
+	ctx.UID = id
+	return nil
+}
+
+func nameOf(obj interface{}) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetName()
+}
+
+func ownerKey(obj interface{}) string {
+	return kindOf(obj) + "/" + nameOf(obj)
+}
+
+// releaseOwnership returns true and forgets the owner when the cluster scoped object was created by
+// the app in the given namespace
+func (d *Driver) releaseOwnership(obj interface{}, namespace string) bool {
+	d.Lock()
+	defer d.Unlock()
+	key := ownerKey(obj)
+	if owner, ok := d.owners[key]; !ok || owner != namespace {
+		return false
+	}
+	delete(d.owners, key)
+	return true
+}
+
+// isNamespaced returns false for the cluster scoped kinds torpedo specs contain
+func isNamespaced(obj interface{}) bool {
+	switch obj.(type) {
+	case *storageapi.StorageClass, *volsnapv1.VolumeSnapshotClass, *corev1.Namespace, *corev1.PersistentVolume:
+		return false
+	}
+	return true
+}
+
+func (d *Driver) createSpecObjects(app *spec.AppSpec, namespace string, options scheduler.ScheduleOptions) ([]interface{}, error) {
+	if _, err := d.store.Get(&corev1.Namespace{}, "", namespace); err != nil {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace,
+				Labels: options.Labels,
+			},
+		}
+		if _, err := d.store.Create(ns); err != nil {
+			return nil, err
+		}
+	}
+
+	var specObjects []interface{}
+	// Storage classes are created first so PVCs can read their parameters
+	specList := append([]interface{}{}, app.SpecList...)
+	sort.SliceStable(specList, func(i, j int) bool {
+		_, iSC := specList[i].(*storageapi.StorageClass)
+		_, jSC := specList[j].(*storageapi.StorageClass)
+		return iSC && !jSC
+	})
+	for _, appSpec := range specList {
+		obj, ok := appSpec.(runtime.Object)
+		if !ok {
+			// Objects the fake does not know about (for e.g. helm repos) are passed through
+			specObjects = append(specObjects, appSpec)
+			continue
+		}
+		created, err := d.createObject(app, obj.DeepCopyObject(), namespace, options)
+		if err != nil {
+			return nil, &scheduler.ErrFailedToScheduleApp{
+				App:   app,
+				Cause: err.Error(),
+			}
+		}
+		if created != nil {
+			specObjects = append(specObjects, created)
+		}
+	}
+	return specObjects, nil
+}
+
+func (d *Driver) createObject(app *spec.AppSpec, obj runtime.Object, namespace string, options scheduler.ScheduleOptions) (runtime.Object, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	if isNamespaced(obj) {
+		accessor.SetNamespace(namespace)
+	}
+	accessor.SetUID(types.UID(d.nextUID()))
+	if len(options.Labels) > 0 {
+		labels := accessor.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		for k, v := range options.Labels {
+			labels[k] = v
+		}
+		accessor.SetLabels(labels)
+	}
+
+	switch o := obj.(type) {
+	case *storageapi.StorageClass:
+		// storage classes are shared between apps, reuse an existing one
+		if existing, err := d.store.Get(o, "", o.Name); err == nil {
+			return existing, nil
+		}
+	case *corev1.PersistentVolumeClaim:
+		if err := d.bindPVC(o, options); err != nil {
+			return nil, err
+		}
+	case *appsapi.Deployment:
+		replicas := replicasOrDefault(o.Spec.Replicas)
+		o.Spec.Replicas = &replicas
+		o.Status.Replicas = replicas
+		o.Status.ReadyReplicas = replicas
+		o.Status.AvailableReplicas = replicas
+		d.placePods(namespace, int(replicas), options.Nodes)
+	case *appsapi.StatefulSet:
+		replicas := replicasOrDefault(o.Spec.Replicas)
+		o.Spec.Replicas = &replicas
+		o.Status.Replicas = replicas
+		o.Status.ReadyReplicas = replicas
+		if err := d.createStatefulSetPVCs(o, options); err != nil {
+			return nil, err
+		}
+		d.placePods(namespace, int(replicas), options.Nodes)
+	}
+
+	log.Debugf("[%v] Creating %s %s/%s", app.Key, kindOf(obj), accessor.GetNamespace(), accessor.GetName())
+	created, err := d.store.Create(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !isNamespaced(obj) {
+		d.Lock()
+		d.owners[ownerKey(obj)] = namespace
+		d.Unlock()
+	}
+	return created, nil
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// placePods deterministically spreads the given number of pods across the
+// nodes in round-robin order of their names
+func (d *Driver) placePods(namespace string, count int, restrictTo []node.Node) {
+	candidates := restrictTo
+	if len(candidates) == 0 {
+		candidates = node.GetWorkerNodes()
+	}
+	var names []string
+	for _, n := range candidates {
+		if !d.isNodeDisabled(n.Name) {
+			names = append(names, n.Name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	sort.Strings(names)
+
+	d.Lock()
+	defer d.Unlock()
+	offset := len(d.appNodes[namespace])
+	for i := 0; i < count; i++ {
+		d.appNodes[namespace] = append(d.appNodes[namespace], names[(offset+i)%len(names)])
+	}
+}
+
+func (d *Driver) isNodeDisabled(name string) bool {
+	d.Lock()
+	defer d.Unlock()
+	return d.disabledNodes[name]
+}
+
+// createStatefulSetPVCs creates the PVCs of the statefulset volume claim templates
+func (d *Driver) createStatefulSetPVCs(ss *appsapi.StatefulSet, options scheduler.ScheduleOptions) error {
+	for _, tmpl := range ss.Spec.VolumeClaimTemplates {
+		for i := 0; i < int(replicasOrDefault(ss.Spec.Replicas)); i++ {
+			pvc := tmpl.DeepCopy()
+			pvc.Name = fmt.Sprintf("%s-%s-%d", tmpl.Name, ss.Name, i)
+			pvc.Namespace = ss.Namespace
+			if _, err := d.store.Get(pvc, pvc.Namespace, pvc.Name); err == nil {
+				continue
+			}
+			pvc.UID = types.UID(d.nextUID())
+			if err := d.bindPVC(pvc, options); err != nil {
+				return err
+			}
+			if _, err := d.store.Create(pvc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindPVC provisions a volume for the PVC through the volume driver (when it
+// supports it) and marks the PVC as bound
+func (d *Driver) bindPVC(pvc *corev1.PersistentVolumeClaim, options scheduler.ScheduleOptions) error {
+	if options.PvcSize > 0 {
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = *resource.NewQuantity(options.PvcSize, resource.BinarySI)
+	}
+	size := pvcSize(pvc)
+
+	haLevel := int64(1)
+	if pvc.Spec.StorageClassName != nil {
+		if obj, err := d.store.Get(&storageapi.StorageClass{}, "", *pvc.Spec.StorageClassName); err == nil {
+			if repl, err := strconv.ParseInt(obj.(*storageapi.StorageClass).Parameters[replParameter], 10, 64); err == nil {
+				haLevel = repl
+			}
+		}
+	}
+
+	volName := fmt.Sprintf("pvc-%s", d.nextUID())
+	if volDriver, err := volume.Get(d.volDriverName); err == nil {
+		if _, err := volDriver.CreateVolume(volName, size, haLevel); err != nil {
+			if _, ok := err.(*errors.ErrNotSupported); !ok {
+				return fmt.Errorf("failed to provision volume for PVC %s/%s: %v", pvc.Namespace, pvc.Name, err)
+			}
+		}
+	}
+
+	pvc.Spec.VolumeName = volName
+	pvc.Status.Phase = corev1.ClaimBound
+	pvc.Status.Capacity = corev1.ResourceList{
+		corev1.ResourceStorage: *resource.NewQuantity(int64(size), resource.BinarySI),
+	}
+	return nil
+}
+
+func pvcSize(pvc *corev1.PersistentVolumeClaim) uint64 {
+	quantity := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	size, _ := quantity.AsInt64()
+	return uint64(size)
+}
+
+// storedObjects returns the current state of the runtime objects of the context
+func (d *Driver) storedObjects(ctx *scheduler.Context) ([]runtime.Object, error) {
+	var objs []runtime.Object
+	for _, appSpec := range ctx.App.SpecList {
+		obj, ok := appSpec.(runtime.Object)
+		if !ok {
+			continue
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+		stored, err := d.store.Get(obj, accessor.GetNamespace(), accessor.GetName())
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, stored)
+	}
+	return objs, nil
+}
+
+// WaitForRunning waits until all deployments and statefulsets of the context have all replicas ready
+func (d *Driver) WaitForRunning(ctx *scheduler.Context, timeout, retryInterval time.Duration) error {
+	if err := d.Inject(OpWaitForRunning); err != nil {
+		return err
+	}
+
+	t := func() (interface{}, bool, error) {
+		objs, err := d.storedObjects(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, obj := range objs {
+			switch o := obj.(type) {
+			case *appsapi.Deployment:
+				if o.Status.ReadyReplicas < replicasOrDefault(o.Spec.Replicas) {
+					return nil, true, fmt.Errorf("deployment %s/%s has %d/%d ready replicas",
+						o.Namespace, o.Name, o.Status.ReadyReplicas, replicasOrDefault(o.Spec.Replicas))
+				}
+			case *appsapi.StatefulSet:
+				if o.Status.ReadyReplicas < replicasOrDefault(o.Spec.Replicas) {
+					return nil, true, fmt.Errorf("statefulset %s/%s has %d/%d ready replicas",
+						o.Namespace, o.Name, o.Status.ReadyReplicas, replicasOrDefault(o.Spec.Replicas))
+				}
+			}
+		}
+		return nil, false, nil
+	}
+	if _, err := task.DoRetryWithTimeout(t, timeout, retryInterval); err != nil {
+		return &scheduler.ErrFailedToValidateApp{
+			App:   ctx.App,
+			Cause: err.Error(),
+		}
+	}
+	return nil
+}
+
+// Destroy removes all objects of the context except its volumes
+func (d *Driver) Destroy(ctx *scheduler.Context, opts map[string]bool) error {
+	if err := d.Inject(OpDestroy); err != nil {
+		return err
+	}
+	for _, appSpec := range ctx.App.SpecList {
+		obj, ok := appSpec.(runtime.Object)
+		if !ok {
+			continue
+		}
+		switch obj.(type) {
+		case *corev1.PersistentVolumeClaim, *storageapi.StorageClass:
+			continue
+		}
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		if err := d.store.Delete(obj, accessor.GetNamespace(), accessor.GetName()); err != nil {
+			if _, ok := err.(*ErrObjectNotFound); !ok {
+				return &scheduler.ErrFailedToDestroyApp{
+					App:   ctx.App,
+					Cause: err.Error(),
+				}
+			}
+		}
+	}
+	d.Lock()
+	delete(d.appNodes, ctx.App.NameSpace)
+	d.Unlock()
+	return nil
+}
+
+// WaitForDestroy verifies that no deployment or statefulset of the context is left
+func (d *Driver) WaitForDestroy(ctx *scheduler.Context, timeout time.Duration) error {
+	if err := d.Inject(OpWaitForDestroy); err != nil {
+		return err
+	}
+	for _, appSpec := range ctx.App.SpecList {
+		switch obj := appSpec.(type) {
+		case *appsapi.Deployment, *appsapi.StatefulSet:
+			runtimeObj := obj.(runtime.Object)
+			accessor, err := meta.Accessor(runtimeObj)
+			if err != nil {
+				return err
+			}
+			if _, err := d.store.Get(runtimeObj, accessor.GetNamespace(), accessor.GetName()); err == nil {
+				return &scheduler.ErrFailedToValidateAppDestroy{
+					App:   ctx.App,
+					Cause: fmt.Sprintf("%s %s/%s still exists", kindOf(obj), accessor.GetNamespace(), accessor.GetName()),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SelectiveWaitForTermination behaves like WaitForDestroy as pods are not modelled individually
+func (d *Driver) SelectiveWaitForTermination(ctx *scheduler.Context, timeout time.Duration, excludeList []node.Node) error {
+	return d.WaitForDestroy(ctx, timeout)
+}
+
+// DeleteTasks simulates deletion of the app pods. Pods are recreated immediately
+// on the same nodes so the app stays ready.
+func (d *Driver) DeleteTasks(ctx *scheduler.Context, opts *scheduler.DeleteTasksOptions) error {
+	if _, err := d.storedObjects(ctx); err != nil {
+		return &scheduler.ErrFailedToDeleteTasks{
+			App:   ctx.App,
+			Cause: err.Error(),
+		}
+	}
+	return nil
+}
+
+// GetVolumeDriverVolumeName returns the volume name bound to the given PVC
+func (d *Driver) GetVolumeDriverVolumeName(name string, namespace string) (string, error) {
+	obj, err := d.store.Get(&corev1.PersistentVolumeClaim{}, namespace, name)
+	if err != nil {
+		return "", err
+	}
+	return obj.(*corev1.PersistentVolumeClaim).Spec.VolumeName, nil
+}
+
+// GetVolumeParameters returns the storage class parameters of every volume of the context
+func (d *Driver) GetVolumeParameters(ctx *scheduler.Context) (map[string]map[string]string, error) {
+	pvcs, err := d.contextPVCs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]map[string]string)
+	for _, pvc := range pvcs {
+		params := make(map[string]string)
+		if pvc.Spec.StorageClassName != nil {
+			obj, err := d.store.Get(&storageapi.StorageClass{}, "", *pvc.Spec.StorageClassName)
+			if err != nil {
+				return nil, &scheduler.ErrFailedToGetVolumeParameters{
+					App:   ctx.App,
+					Cause: err.Error(),
+				}
+			}
+			for k, v := range obj.(*storageapi.StorageClass).Parameters {
+				params[k] = v
+			}
+		}
+		params["pvc_name"] = pvc.Name
+		params["pvc_namespace"] = pvc.Namespace
+		result[pvc.Spec.VolumeName] = params
+	}
+	return result, nil
+}
+
+// ValidateVolumes validates that the storage classes of the context exist and all its PVCs are bound
+func (d *Driver) ValidateVolumes(ctx *scheduler.Context, timeout, retryInterval time.Duration, options *scheduler.VolumeOptions) error {
+	if err := d.Inject(OpValidateVolumes); err != nil {
+		if options != nil && options.ExpectError {
+			return nil
+		}
+		return err
+	}
+	for _, appSpec := range ctx.App.SpecList {
+		switch obj := appSpec.(type) {
+		case *storageapi.StorageClass:
+			if ctx.SkipClusterScopedObject || (options != nil && options.SkipClusterScopedObjects) {
+				continue
+			}
+			if _, err := d.store.Get(obj, "", obj.Name); err != nil {
+				return &scheduler.ErrFailedToValidateStorage{
+					App:   ctx.App,
+					Cause: fmt.Sprintf("Failed to validate StorageClass: %v. Err: %v", obj.Name, err),
+				}
+			}
+		}
+	}
+	pvcs, err := d.contextPVCs(ctx)
+	if err != nil {
+		return &scheduler.ErrFailedToValidateStorage{
+			App:   ctx.App,
+			Cause: err.Error(),
+		}
+	}
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase != corev1.ClaimBound && !(options != nil && options.ExpectError) {
+			return &scheduler.ErrFailedToValidateStorage{
+				App:   ctx.App,
+				Cause: fmt.Sprintf("PVC %s/%s is in phase %s", pvc.Namespace, pvc.Name, pvc.Status.Phase),
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTopologyLabel is a no-op for the fake scheduler
+func (d *Driver) ValidateTopologyLabel(ctx *scheduler.Context) error {
+	return nil
+}
+
+// contextPVCs returns the stored PVCs of the context, including the ones created
+// from statefulset volume claim templates
+func (d *Driver) contextPVCs(ctx *scheduler.Context) ([]*corev1.PersistentVolumeClaim, error) {
+	var pvcs []*corev1.PersistentVolumeClaim
+	for _, appSpec := range ctx.App.SpecList {
+		switch obj := appSpec.(type) {
+		case *corev1.PersistentVolumeClaim:
+			stored, err := d.store.Get(obj, obj.Namespace, obj.Name)
+			if err != nil {
+				return nil, err
+			}
+			pvcs = append(pvcs, stored.(*corev1.PersistentVolumeClaim))
+		case *appsapi.StatefulSet:
+			for _, tmpl := range obj.Spec.VolumeClaimTemplates {
+				for i := 0; i < int(replicasOrDefault(obj.Spec.Replicas)); i++ {
+					name := fmt.Sprintf("%s-%s-%d", tmpl.Name, obj.Name, i)
+					stored, err := d.store.Get(&corev1.PersistentVolumeClaim{}, obj.Namespace, name)
+					if err != nil {
+						return nil, err
+					}
+					pvcs = append(pvcs, stored.(*corev1.PersistentVolumeClaim))
+				}
+			}
+		}
+	}
+	return pvcs, nil
+}
+
+func volumeForPVC(pvc *corev1.PersistentVolumeClaim) *volume.Volume {
+	shared := false
+	for _, mode := range pvc.Spec.AccessModes {
+		if mode == corev1.ReadWriteMany {
+			shared = true
+		}
+	}
+	return &volume.Volume{
+		ID:            pvc.Spec.VolumeName,
+		Name:          pvc.Name,
+		Namespace:     pvc.Namespace,
+		Annotations:   pvc.Annotations,
+		Labels:        pvc.Labels,
+		Size:          pvcSize(pvc),
+		RequestedSize: pvcSize(pvc),
+		Shared:        shared,
+		Raw:           pvc.Spec.VolumeMode != nil && *pvc.Spec.VolumeMode == corev1.PersistentVolumeBlock,
+	}
+}
+
+// GetVolumes returns the volumes bound to the PVCs of the context
+func (d *Driver) GetVolumes(ctx *scheduler.Context) ([]*volume.Volume, error) {
+	if err := d.Inject(OpGetVolumes); err != nil {
+		return nil, err
+	}
+	pvcs, err := d.contextPVCs(ctx)
+	if err != nil {
+		return nil, &scheduler.ErrFailedToGetStorage{
+			App:   ctx.App,
+			Cause: err.Error(),
+		}
+	}
+	var vols []*volume.Volume
+	for _, pvc := range pvcs {
+		vols = append(vols, volumeForPVC(pvc))
+	}
+	return vols, nil
+}
+
+// GetPureVolumes returns no volumes as the fake scheduler has no pure backend
+func (d *Driver) GetPureVolumes(ctx *scheduler.Context, pureVolType string) ([]*volume.Volume, error) {
+	return nil, nil
+}
+
+// DeleteVolumes deletes the PVCs of the context and their backing volumes
+func (d *Driver) DeleteVolumes(ctx *scheduler.Context, options *scheduler.VolumeOptions) ([]*volume.Volume, error) {
+	if err := d.Inject(OpDeleteVolumes); err != nil {
+		return nil, err
+	}
+	pvcs, err := d.contextPVCs(ctx)
+	if err != nil {
+		return nil, &scheduler.ErrFailedToDestroyStorage{
+			App:   ctx.App,
+			Cause: err.Error(),
+		}
+	}
+	volDriver, volErr := volume.Get(d.volDriverName)
+	var vols []*volume.Volume
+	for _, pvc := range pvcs {
+		if err := d.store.Delete(pvc, pvc.Namespace, pvc.Name); err != nil {
+			return nil, err
+		}
+		if volErr == nil {
+			if err := volDriver.DeleteVolume(pvc.Spec.VolumeName); err != nil {
+				if _, ok := err.(*errors.ErrNotSupported); !ok {
+					return nil, &scheduler.ErrFailedToDestroyStorage{
+						App:   ctx.App,
+						Cause: err.Error(),
+					}
+				}
+			}
+		}
+		vols = append(vols, volumeForPVC(pvc))
+	}
+	if options == nil || !options.SkipClusterScopedObjects {
+		for _, appSpec := range ctx.App.SpecList {
+			if sc, ok := appSpec.(*storageapi.StorageClass); ok {
+				if err := d.store.Delete(sc, "", sc.Name); err != nil {
+					if _, ok := err.(*ErrObjectNotFound); !ok {
+						return nil, err
+					}
+				}
+			}
+		}
+	}
+	return vols, nil
+}
+
+// GetPodsForPVC returns no pods as pods are not modelled individually
+func (d *Driver) GetPodsForPVC(pvcname, namespace string) ([]corev1.Pod, error) {
+	if _, err := d.store.Get(&corev1.PersistentVolumeClaim{}, namespace, pvcname); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// GetPodLog returns no logs
+func (d *Driver) GetPodLog(ctx *scheduler.Context, sinceSeconds int64, containerName string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+// ResizeVolume increases the size of all PVCs of the context by 1 GiB
+func (d *Driver) ResizeVolume(ctx *scheduler.Context, configMapName string) ([]*volume.Volume, error) {
+	pvcs, err := d.contextPVCs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var vols []*volume.Volume
+	for _, pvc := range pvcs {
+		vol, err := d.ResizePVC(ctx, pvc, 1)
+		if err != nil {
+			return nil, err
+		}
+		vols = append(vols, vol)
+	}
+	return vols, nil
+}
+
+// ResizePVC increases the size of the given PVC by the given number of GiB
+func (d *Driver) ResizePVC(ctx *scheduler.Context, pvc *corev1.PersistentVolumeClaim, sizeInGb uint64) (*volume.Volume, error) {
+	if err := d.Inject(OpResizeVolume); err != nil {
+		return nil, err
+	}
+	obj, err := d.store.Get(pvc, pvc.Namespace, pvc.Name)
+	if err != nil {
+		return nil, err
+	}
+	stored := obj.(*corev1.PersistentVolumeClaim)
+	vol := volumeForPVC(stored)
+	newSize := pvcSize(stored) + sizeInGb*uint64(1024*1024*1024)
+
+	if volDriver, err := volume.Get(d.volDriverName); err == nil {
+		if err := volDriver.ResizeVolume(stored.Spec.VolumeName, newSize); err != nil {
+			if _, ok := err.(*errors.ErrNotSupported); !ok {
+				return nil, &scheduler.ErrFailedToResizeStorage{
+					App:   ctx.App,
+					Cause: err.Error(),
+				}
+			}
+		}
+	}
+
+	quantity := *resource.NewQuantity(int64(newSize), resource.BinarySI)
+	stored.Spec.Resources.Requests[corev1.ResourceStorage] = quantity
+	stored.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: quantity}
+	if _, err := d.store.Update(stored); err != nil {
+		return nil, err
+	}
+	vol.RequestedSize = newSize
+	return vol, nil
+}
+
+// GetSnapshots returns the CSI snapshots taken of the context volumes
+func (d *Driver) GetSnapshots(ctx *scheduler.Context) ([]*volume.Snapshot, error) {
+	var snaps []*volume.Snapshot
+	for _, obj := range d.store.List(&volsnapv1.VolumeSnapshot{}, ctx.App.NameSpace) {
+		snap := obj.(*volsnapv1.VolumeSnapshot)
+		snaps = append(snaps, &volume.Snapshot{
+			ID:        string(snap.UID),
+			Name:      snap.Name,
+			Namespace: snap.Namespace,
+		})
+	}
+	return snaps, nil
+}
+
+// Describe returns a textual dump of the stored objects of the context
+func (d *Driver) Describe(ctx *scheduler.Context) (string, error) {
+	objs, err := d.storedObjects(ctx)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	for _, obj := range objs {
+		fmt.Fprintf(&buf, "%s %s\n", kindOf(obj), nameOf(obj))
+	}
+	return buf.String(), nil
+}
+
+// ScaleApplication sets the replicas of the deployments and statefulsets of the context
+func (d *Driver) ScaleApplication(ctx *scheduler.Context, scaleFactorMap map[string]int32) error {
+	if err := d.Inject(OpScaleApplication); err != nil {
+		return err
+	}
+	objs, err := d.storedObjects(ctx)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *appsapi.Deployment:
+			replicas, ok := scaleFactorMap[o.Name+DeploymentSuffix]
+			if !ok {
+				continue
+			}
+			o.Spec.Replicas = &replicas
+			o.Status.Replicas = replicas
+			o.Status.ReadyReplicas = replicas
+			o.Status.AvailableReplicas = replicas
+			if _, err := d.store.Update(o); err != nil {
+				return &scheduler.ErrFailedToUpdateApp{
+					App:   ctx.App,
+					Cause: fmt.Sprintf("Failed to update Deployment: %v. Err: %v", o.Name, err),
+				}
+			}
+			d.rescalePods(ctx, int(replicas))
+		case *appsapi.StatefulSet:
+			replicas, ok := scaleFactorMap[o.Name+StatefulSetSuffix]
+			if !ok {
+				continue
+			}
+			o.Spec.Replicas = &replicas
+			o.Status.Replicas = replicas
+			o.Status.ReadyReplicas = replicas
+			if err := d.createStatefulSetPVCs(o, ctx.ScheduleOptions); err != nil {
+				return err
+			}
+			if _, err := d.store.Update(o); err != nil {
+				return &scheduler.ErrFailedToUpdateApp{
+					App:   ctx.App,
+					Cause: fmt.Sprintf("Failed to update StatefulSet: %v. Err: %v", o.Name, err),
+				}
+			}
+			d.rescalePods(ctx, int(replicas))
+		}
+	}
+	return nil
+}
+
+func (d *Driver) rescalePods(ctx *scheduler.Context, replicas int) {
+	d.Lock()
+	current := len(d.appNodes[ctx.App.NameSpace])
+	if replicas < current {
+		d.appNodes[ctx.App.NameSpace] = d.appNodes[ctx.App.NameSpace][:replicas]
+	}
+	d.Unlock()
+	if replicas > current {
+		d.placePods(ctx.App.NameSpace, replicas-current, ctx.ScheduleOptions.Nodes)
+	}
+}
+
+// GetScaleFactorMap returns the current replicas of the deployments and statefulsets of the context
+func (d *Driver) GetScaleFactorMap(ctx *scheduler.Context) (map[string]int32, error) {
+	objs, err := d.storedObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scaleFactorMap := make(map[string]int32)
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *appsapi.Deployment:
+			scaleFactorMap[o.Name+DeploymentSuffix] = replicasOrDefault(o.Spec.Replicas)
+		case *appsapi.StatefulSet:
+			scaleFactorMap[o.Name+StatefulSetSuffix] = replicasOrDefault(o.Spec.Replicas)
+		}
+	}
+	return scaleFactorMap, nil
+}
+
+// IsScalable returns true for deployments and statefulsets
+func (d *Driver) IsScalable(spec interface{}) bool {
+	switch spec.(type) {
+	case *appsapi.Deployment, *appsapi.StatefulSet:
+		return true
+	}
+	return false
+}
+
+// StopSchedOnNode marks the node as not ready
+func (d *Driver) StopSchedOnNode(n node.Node) error {
+	return d.DisableSchedulingOnNode(n)
+}
+
+// StartSchedOnNode marks the node as ready
+func (d *Driver) StartSchedOnNode(n node.Node) error {
+	return d.EnableSchedulingOnNode(n)
+}
+
+// StopKubelet marks the node as not ready
+func (d *Driver) StopKubelet(n node.Node, opts node.SystemctlOpts) error {
+	return d.DisableSchedulingOnNode(n)
+}
+
+// StartKubelet marks the node as ready
+func (d *Driver) StartKubelet(n node.Node, opts node.SystemctlOpts) error {
+	return d.EnableSchedulingOnNode(n)
+}
+
+// RefreshNodeRegistry is a no-op as nodes are managed by the caller
+func (d *Driver) RefreshNodeRegistry() error {
+	return nil
+}
+
+// EnableSchedulingOnNode uncordons the node
+func (d *Driver) EnableSchedulingOnNode(n node.Node) error {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.disabledNodes, n.Name)
+	return nil
+}
+
+// DisableSchedulingOnNode cordons the node
+func (d *Driver) DisableSchedulingOnNode(n node.Node) error {
+	d.Lock()
+	defer d.Unlock()
+	d.disabledNodes[n.Name] = true
+	return nil
+}
+
+// PrepareNodeToDecommission cordons the node
+func (d *Driver) PrepareNodeToDecommission(n node.Node, provisioner string) error {
+	return d.DisableSchedulingOnNode(n)
+}
+
+// DeleteNode removes the node from the node registry
+func (d *Driver) DeleteNode(n node.Node) error {
+	if err := node.DeleteNode(n); err != nil {
+		return &scheduler.ErrFailedToDeleteNode{
+			Node:  n,
+			Cause: err.Error(),
+		}
+	}
+	return nil
+}
+
+// ValidateVolumeSnapshotRestore is not supported
+func (d *Driver) ValidateVolumeSnapshotRestore(ctx *scheduler.Context, timeStart time.Time) error {
+	return &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "ValidateVolumeSnapshotRestore()",
+	}
+}
+
+// GetTokenFromConfigMap is not supported
+func (d *Driver) GetTokenFromConfigMap(string) (string, error) {
+	return "", &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "GetTokenFromConfigMap()",
+	}
+}
+
+// AddLabelOnNode adds a label on the node
+func (d *Driver) AddLabelOnNode(n node.Node, key string, value string) error {
+	d.Lock()
+	defer d.Unlock()
+	if d.nodeLabels[n.Name] == nil {
+		d.nodeLabels[n.Name] = make(map[string]string)
+	}
+	d.nodeLabels[n.Name][key] = value
+	return nil
+}
+
+// RemoveLabelOnNode removes a label from the node
+func (d *Driver) RemoveLabelOnNode(n node.Node, key string) error {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.nodeLabels[n.Name], key)
+	return nil
+}
+
+// GetNodeLabels returns the labels added on the node
+func (d *Driver) GetNodeLabels(n node.Node) map[string]string {
+	d.Lock()
+	defer d.Unlock()
+	labels := make(map[string]string)
+	for k, v := range d.nodeLabels[n.Name] {
+		labels[k] = v
+	}
+	return labels
+}
+
+// IsAutopilotEnabledForVolume returns false
+func (d *Driver) IsAutopilotEnabledForVolume(*volume.Volume) bool {
+	return false
+}
+
+// SaveSchedulerLogsToFile is a no-op for the fake scheduler
+func (d *Driver) SaveSchedulerLogsToFile(n node.Node, location string) error {
+	return nil
+}
+
+// GetAutopilotNamespace returns the namespace autopilot is deemed to run in
+func (d *Driver) GetAutopilotNamespace() (string, error) {
+	return PortworxNamespace, nil
+}
+
+// IsAutopilotEnabled returns false
+func (d *Driver) IsAutopilotEnabled() (bool, error) {
+	return false, nil
+}
+
+// GetPortworxNamespace returns the namespace portworx is deemed to run in
+func (d *Driver) GetPortworxNamespace() (string, error) {
+	return PortworxNamespace, nil
+}
+
+// GetIOBandwidth is not supported
+func (d *Driver) GetIOBandwidth(string, string) (int, error) {
+	return 0, &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "GetIOBandwidth()",
+	}
+}
+
+// CreateAutopilotRule stores the given autopilot rule
+func (d *Driver) CreateAutopilotRule(apRule apapi.AutopilotRule) (*apapi.AutopilotRule, error) {
+	obj, err := d.store.Create(&apRule)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*apapi.AutopilotRule), nil
+}
+
+// GetAutopilotRule returns the stored autopilot rule with the given name
+func (d *Driver) GetAutopilotRule(name string) (*apapi.AutopilotRule, error) {
+	obj, err := d.store.Get(&apapi.AutopilotRule{}, "", name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*apapi.AutopilotRule), nil
+}
+
+// UpdateAutopilotRule updates the stored autopilot rule
+func (d *Driver) UpdateAutopilotRule(apRule *apapi.AutopilotRule) (*apapi.AutopilotRule, error) {
+	obj, err := d.store.Update(apRule)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*apapi.AutopilotRule), nil
+}
+
+// ListAutopilotRules lists the stored autopilot rules
+func (d *Driver) ListAutopilotRules() (*apapi.AutopilotRuleList, error) {
+	list := &apapi.AutopilotRuleList{}
+	for _, obj := range d.store.List(&apapi.AutopilotRule{}, "") {
+		list.Items = append(list.Items, *obj.(*apapi.AutopilotRule))
+	}
+	return list, nil
+}
+
+// DeleteAutopilotRule deletes the stored autopilot rule
+func (d *Driver) DeleteAutopilotRule(name string) error {
+	return d.store.Delete(&apapi.AutopilotRule{}, "", name)
+}
+
+// GetActionApproval returns the stored action approval
+func (d *Driver) GetActionApproval(namespace, name string) (*apapi.ActionApproval, error) {
+	obj, err := d.store.Get(&apapi.ActionApproval{}, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*apapi.ActionApproval), nil
+}
+
+// UpdateActionApproval updates the stored action approval
+func (d *Driver) UpdateActionApproval(namespace string, actionApproval *apapi.ActionApproval) (*apapi.ActionApproval, error) {
+	obj, err := d.store.Update(actionApproval)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*apapi.ActionApproval), nil
+}
+
+// DeleteActionApproval deletes the stored action approval
+func (d *Driver) DeleteActionApproval(namespace, name string) error {
+	return d.store.Delete(&apapi.ActionApproval{}, namespace, name)
+}
+
+// ListActionApprovals lists the stored action approvals in the namespace
+func (d *Driver) ListActionApprovals(namespace string) (*apapi.ActionApprovalList, error) {
+	list := &apapi.ActionApprovalList{}
+	for _, obj := range d.store.List(&apapi.ActionApproval{}, namespace) {
+		list.Items = append(list.Items, *obj.(*apapi.ActionApproval))
+	}
+	return list, nil
+}
+
+// GetEvents returns the events recorded with RecordEvent
+func (d *Driver) GetEvents() map[string][]scheduler.Event {
+	d.Lock()
+	defer d.Unlock()
+	events := make(map[string][]scheduler.Event, len(d.events))
+	for kind, list := range d.events {
+		events[kind] = append([]scheduler.Event(nil), list...)
+	}
+	return events
+}
+
+// ValidateAutopilotEvents is not supported
+func (d *Driver) ValidateAutopilotEvents(ctx *scheduler.Context) error {
+	return &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "ValidateAutopilotEvents()",
+	}
+}
+
+// ValidateAutopilotRuleObjects is not supported
+func (d *Driver) ValidateAutopilotRuleObjects() error {
+	return &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "ValidateAutopilotRuleObjects()",
+	}
+}
+
+// WaitForRebalanceAROToComplete is not supported
+func (d *Driver) WaitForRebalanceAROToComplete() error {
+	return &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "WaitForRebalanceAROToComplete()",
+	}
+}
+
+// VerifyPoolResizeARO is not supported
+func (d *Driver) VerifyPoolResizeARO(apRule apapi.AutopilotRule) (bool, error) {
+	return false, &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "VerifyPoolResizeARO()",
+	}
+}
+
+// GetWorkloadSizeFromAppSpec returns 0 as the fake scheduler runs no workload
+func (d *Driver) GetWorkloadSizeFromAppSpec(ctx *scheduler.Context) (uint64, error) {
+	return 0, nil
+}
+
+// SetConfig is a no-op for the fake scheduler
+func (d *Driver) SetConfig(configPath string) error {
+	return nil
+}
+
+// SetGkeConfig is a no-op for the fake scheduler
+func (d *Driver) SetGkeConfig(configPath string, jsonKey string) error {
+	return nil
+}
+
+// UpgradeScheduler is not supported
+func (d *Driver) UpgradeScheduler(version string) error {
+	return &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "UpgradeScheduler()",
+	}
+}
+
+// CreateSecret stores a secret with the given data field
+func (d *Driver) CreateSecret(namespace, name, dataField, secretDataString string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: map[string][]byte{
+			dataField: []byte(secretDataString),
+		},
+	}
+	_, err := d.store.Create(secret)
+	return err
+}
+
+// GetSecretData returns the given data field of a stored secret
+func (d *Driver) GetSecretData(namespace, name, dataField string) (string, error) {
+	obj, err := d.store.Get(&corev1.Secret{}, namespace, name)
+	if err != nil {
+		return "", &scheduler.ErrFailedToGetSecret{
+			App:   &spec.AppSpec{Key: name},
+			Cause: err.Error(),
+		}
+	}
+	return string(obj.(*corev1.Secret).Data[dataField]), nil
+}
+
+// DeleteSecret deletes a stored secret
+func (d *Driver) DeleteSecret(namespace, name string) error {
+	return d.store.Delete(&corev1.Secret{}, namespace, name)
+}
+
+// GetSnapShotData is not supported as legacy snapshots are not modelled
+func (d *Driver) GetSnapShotData(ctx *scheduler.Context, snapshotName, snapshotNameSpace string) (*snapv1.VolumeSnapshotData, error) {
+	return nil, &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "GetSnapShotData()",
+	}
+}
+
+// DeleteSnapShot deletes a CSI snapshot
+func (d *Driver) DeleteSnapShot(ctx *scheduler.Context, snapshotName, snapshotNameSpace string) error {
+	return d.DeleteCsiSnapshot(ctx, snapshotName, snapshotNameSpace)
+}
+
+// GetSnapshotsInNameSpace lists the CSI snapshots in the namespace
+func (d *Driver) GetSnapshotsInNameSpace(ctx *scheduler.Context, snapshotNameSpace string) (*volsnapv1.VolumeSnapshotList, error) {
+	list := &volsnapv1.VolumeSnapshotList{}
+	for _, obj := range d.store.List(&volsnapv1.VolumeSnapshot{}, snapshotNameSpace) {
+		list.Items = append(list.Items, *obj.(*volsnapv1.VolumeSnapshot))
+	}
+	return list, nil
+}
+
+// DeleteCsiSnapshotsFromNamespace deletes all CSI snapshots in the namespace
+func (d *Driver) DeleteCsiSnapshotsFromNamespace(ctx *scheduler.Context, namespace string) error {
+	for _, obj := range d.store.List(&volsnapv1.VolumeSnapshot{}, namespace) {
+		if err := d.DeleteCsiSnapshot(ctx, nameOf(obj), namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsCsiSnapshotExists checks if the CSI snapshot exists in the namespace
+func (d *Driver) IsCsiSnapshotExists(ctx *scheduler.Context, snapshotName string, namespace string) (bool, error) {
+	if _, err := d.store.Get(&volsnapv1.VolumeSnapshot{}, namespace, snapshotName); err != nil {
+		if _, ok := err.(*ErrObjectNotFound); ok {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateCsiSnapshotClass creates a CSI snapshot class for the portworx CSI driver
+func (d *Driver) CreateCsiSnapshotClass(snapClassName string, deletionPolicy string) (*volsnapv1.VolumeSnapshotClass, error) {
+	return d.CreateVolumeSnapshotClassesWithParameters(snapClassName, "pxd.portworx.com", false, deletionPolicy, nil)
+}
+
+// CreateVolumeSnapshotClasses creates a CSI snapshot class
+func (d *Driver) CreateVolumeSnapshotClasses(snapClassName string, provisioner string, isDefault bool, deletePolicy string) (*volsnapv1.VolumeSnapshotClass, error) {
+	return d.CreateVolumeSnapshotClassesWithParameters(snapClassName, provisioner, isDefault, deletePolicy, nil)
+}
+
+// CreateVolumeSnapshotClassesWithParameters creates a CSI snapshot class with the given parameters
+func (d *Driver) CreateVolumeSnapshotClassesWithParameters(snapClassName string, provisioner string, isDefault bool, deletePolicy string, parameters map[string]string) (*volsnapv1.VolumeSnapshotClass, error) {
+	snapClass := &volsnapv1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: snapClassName,
+			Annotations: map[string]string{
+				"snapshot.storage.kubernetes.io/is-default-class": strconv.FormatBool(isDefault),
+			},
+		},
+		Driver:         provisioner,
+		DeletionPolicy: volsnapv1.DeletionPolicy(deletePolicy),
+		Parameters:     parameters,
+	}
+	obj, err := d.store.Create(snapClass)
+	if err != nil {
+		return nil, &scheduler.ErrFailedToCreateSnapshotClass{
+			Name:  snapClassName,
+			Cause: err,
+		}
+	}
+	return obj.(*volsnapv1.VolumeSnapshotClass), nil
+}
+
+// DeleteCsiSnapshotClass deletes a CSI snapshot class
+func (d *Driver) DeleteCsiSnapshotClass(snapClassName string) error {
+	return d.store.Delete(&volsnapv1.VolumeSnapshotClass{}, "", snapClassName)
+}
+
+// GetAllSnapshotClasses lists all CSI snapshot classes
+func (d *Driver) GetAllSnapshotClasses() (*volsnapv1.VolumeSnapshotClassList, error) {
+	list := &volsnapv1.VolumeSnapshotClassList{}
+	for _, obj := range d.store.List(&volsnapv1.VolumeSnapshotClass{}, "") {
+		list.Items = append(list.Items, *obj.(*volsnapv1.VolumeSnapshotClass))
+	}
+	return list, nil
+}
+
+// CreateCsiSnapshot creates a ready-to-use CSI snapshot of the given PVC
+func (d *Driver) CreateCsiSnapshot(name string, namespace string, class string, pvc string) (*volsnapv1.VolumeSnapshot, error) {
+	if err := d.Inject(OpCreateCsiSnapshot); err != nil {
+		return nil, &scheduler.ErrFailedToCreateSnapshot{
+			PvcName: pvc,
+			Cause:   err,
+		}
+	}
+	obj, err := d.store.Get(&corev1.PersistentVolumeClaim{}, namespace, pvc)
+	if err != nil {
+		return nil, &scheduler.ErrFailedToCreateSnapshot{
+			PvcName: pvc,
+			Cause:   err,
+		}
+	}
+	source := obj.(*corev1.PersistentVolumeClaim)
+	if _, err := d.store.Get(&volsnapv1.VolumeSnapshotClass{}, "", class); err != nil {
+		return nil, &scheduler.ErrFailedToCreateSnapshot{
+			PvcName: pvc,
+			Cause:   err,
+		}
+	}
+
+	readyToUse := true
+	restoreSize := resource.NewQuantity(int64(pvcSize(source)), resource.BinarySI)
+	now := metav1.Now()
+	snap := &volsnapv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID(d.nextUID()),
+		},
+		Spec: volsnapv1.VolumeSnapshotSpec{
+			Source: volsnapv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &pvc,
+			},
+			VolumeSnapshotClassName: &class,
+		},
+		Status: &volsnapv1.VolumeSnapshotStatus{
+			ReadyToUse:   &readyToUse,
+			RestoreSize:  restoreSize,
+			CreationTime: &now,
+		},
+	}
+	created, err := d.store.Create(snap)
+	if err != nil {
+		return nil, &scheduler.ErrFailedToCreateSnapshot{
+			PvcName: pvc,
+			Cause:   err,
+		}
+	}
+	return created.(*volsnapv1.VolumeSnapshot), nil
+}
+
+// CreateCsiSnapsForVolumes creates a CSI snapshot of every PVC of the context
+func (d *Driver) CreateCsiSnapsForVolumes(ctx *scheduler.Context, snapClass string) (map[string]*volsnapv1.VolumeSnapshot, error) {
+	pvcs, err := d.contextPVCs(ctx)
+	if err != nil {
+		return nil, &scheduler.ErrFailedToCreateCsiSnapshots{
+			App:   ctx.App,
+			Cause: err.Error(),
+		}
+	}
+	snaps := make(map[string]*volsnapv1.VolumeSnapshot)
+	for _, pvc := range pvcs {
+		name := fmt.Sprintf("%s-snap-%s", pvc.Name, d.nextUID()[24:])
+		snap, err := d.CreateCsiSnapshot(name, pvc.Namespace, snapClass, pvc.Name)
+		if err != nil {
+			return nil, &scheduler.ErrFailedToCreateCsiSnapshots{
+				App:   ctx.App,
+				Cause: err.Error(),
+			}
+		}
+		snaps[pvc.Name] = snap
+	}
+	return snaps, nil
+}
+
+// GetCsiSnapshots returns the CSI snapshots of the given PVC
+func (d *Driver) GetCsiSnapshots(namespace string, pvcName string) ([]*volsnapv1.VolumeSnapshot, error) {
+	var snaps []*volsnapv1.VolumeSnapshot
+	for _, obj := range d.store.List(&volsnapv1.VolumeSnapshot{}, namespace) {
+		snap := obj.(*volsnapv1.VolumeSnapshot)
+		if snap.Spec.Source.PersistentVolumeClaimName != nil && *snap.Spec.Source.PersistentVolumeClaimName == pvcName {
+			snaps = append(snaps, snap)
+		}
+	}
+	return snaps, nil
+}
+
+// ValidateCsiSnapshots validates that the given CSI snapshots exist and are ready to use
+func (d *Driver) ValidateCsiSnapshots(ctx *scheduler.Context, volSnapMap map[string]*volsnapv1.VolumeSnapshot) error {
+	for pvc, snap := range volSnapMap {
+		obj, err := d.store.Get(snap, snap.Namespace, snap.Name)
+		if err != nil {
+			return &scheduler.ErrFailedToValidateCsiSnapshots{
+				App:   ctx.App,
+				Cause: fmt.Sprintf("snapshot %s of PVC %s: %v", snap.Name, pvc, err),
+			}
+		}
+		stored := obj.(*volsnapv1.VolumeSnapshot)
+		if stored.Status == nil || stored.Status.ReadyToUse == nil || !*stored.Status.ReadyToUse {
+			return &scheduler.ErrFailedToValidateCsiSnapshots{
+				App:   ctx.App,
+				Cause: fmt.Sprintf("snapshot %s of PVC %s is not ready to use", snap.Name, pvc),
+			}
+		}
+	}
+	return nil
+}
+
+// RestoreCsiSnapAndValidate restores the latest CSI snapshot of every PVC of the
+// context into a new PVC using the storage class of the given map
+func (d *Driver) RestoreCsiSnapAndValidate(ctx *scheduler.Context, scMap map[string]*storageapi.StorageClass) (map[string]corev1.PersistentVolumeClaim, error) {
+	if err := d.Inject(OpRestoreCsiSnapshot); err != nil {
+		return nil, &scheduler.ErrFailedToRestore{
+			App:   ctx.App,
+			Cause: err.Error(),
+		}
+	}
+	pvcs, err := d.contextPVCs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	restored := make(map[string]corev1.PersistentVolumeClaim)
+	for _, pvc := range pvcs {
+		snaps, err := d.GetCsiSnapshots(pvc.Namespace, pvc.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(snaps) == 0 {
+			return nil, &scheduler.ErrFailedToRestore{
+				App:   ctx.App,
+				Cause: fmt.Sprintf("no snapshot found for PVC %s/%s", pvc.Namespace, pvc.Name),
+			}
+		}
+		snap := snaps[len(snaps)-1]
+		restoredPVC := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-restore", pvc.Name),
+				Namespace: pvc.Namespace,
+				UID:       types.UID(d.nextUID()),
+			},
+			Spec: *pvc.Spec.DeepCopy(),
+		}
+		apiGroup := "snapshot.storage.k8s.io"
+		restoredPVC.Spec.DataSource = &corev1.TypedLocalObjectReference{
+			APIGroup: &apiGroup,
+			Kind:     "VolumeSnapshot",
+			Name:     snap.Name,
+		}
+		for _, sc := range scMap {
+			scName := sc.Name
+			restoredPVC.Spec.StorageClassName = &scName
+			break
+		}
+		if err := d.bindPVC(restoredPVC, ctx.ScheduleOptions); err != nil {
+			return nil, &scheduler.ErrFailedToRestore{
+				App:   ctx.App,
+				Cause: err.Error(),
+			}
+		}
+		if _, err := d.store.Create(restoredPVC); err != nil {
+			return nil, &scheduler.ErrFailedToRestore{
+				App:   ctx.App,
+				Cause: err.Error(),
+			}
+		}
+		restored[pvc.Name] = *restoredPVC
+	}
+	return restored, nil
+}
+
+// DeleteCsiSnapsForVolumes deletes all but the latest retainCount CSI snapshots of every PVC of the context
+func (d *Driver) DeleteCsiSnapsForVolumes(ctx *scheduler.Context, retainCount int) error {
+	pvcs, err := d.contextPVCs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, pvc := range pvcs {
+		snaps, err := d.GetCsiSnapshots(pvc.Namespace, pvc.Name)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < len(snaps)-retainCount; i++ {
+			if err := d.DeleteCsiSnapshot(ctx, snaps[i].Name, snaps[i].Namespace); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteCsiSnapshot deletes a CSI snapshot
+func (d *Driver) DeleteCsiSnapshot(ctx *scheduler.Context, snapshotName string, snapshotNameSpace string) error {
+	if err := d.Inject(OpDeleteCsiSnapshot); err != nil {
+		return &scheduler.ErrFailedToDeleteSnapshot{
+			Name:  snapshotNameSpace,
+			Cause: err,
+		}
+	}
+	if err := d.store.Delete(&volsnapv1.VolumeSnapshot{}, snapshotNameSpace, snapshotName); err != nil {
+		return &scheduler.ErrFailedToDeleteSnapshot{
+			Name:  snapshotNameSpace,
+			Cause: err,
+		}
+	}
+	return nil
+}
+
+// CSISnapshotTest snapshots the given PVC and restores it into a new PVC
+func (d *Driver) CSISnapshotTest(ctx *scheduler.Context, request scheduler.CSISnapshotRequest) error {
+	if _, err := d.CreateCsiSnapshot(request.SnapName, request.Namespace, request.SnapshotclassName, request.OriginalPVCName); err != nil {
+		return err
+	}
+	return d.restorePVC(request.Namespace, request.OriginalPVCName, request.RestoredPVCName, "VolumeSnapshot", request.SnapName)
+}
+
+// CSISnapshotAndRestoreMany snapshots the given PVC and restores it into a new PVC
+func (d *Driver) CSISnapshotAndRestoreMany(ctx *scheduler.Context, request scheduler.CSISnapshotRequest) error {
+	return d.CSISnapshotTest(ctx, request)
+}
+
+// CSICloneTest clones the given PVC into a new PVC
+func (d *Driver) CSICloneTest(ctx *scheduler.Context, request scheduler.CSICloneRequest) error {
+	return d.restorePVC(request.Namespace, request.OriginalPVCName, request.RestoredPVCName, "PersistentVolumeClaim", request.OriginalPVCName)
+}
+
+func (d *Driver) restorePVC(namespace, sourcePVC, newPVC, dataSourceKind, dataSourceName string) error {
+	obj, err := d.store.Get(&corev1.PersistentVolumeClaim{}, namespace, sourcePVC)
+	if err != nil {
+		return err
+	}
+	source := obj.(*corev1.PersistentVolumeClaim)
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      newPVC,
+			Namespace: namespace,
+			UID:       types.UID(d.nextUID()),
+		},
+		Spec: *source.Spec.DeepCopy(),
+	}
+	pvc.Spec.DataSource = &corev1.TypedLocalObjectReference{
+		Kind: dataSourceKind,
+		Name: dataSourceName,
+	}
+	if err := d.bindPVC(pvc, scheduler.ScheduleOptions{}); err != nil {
+		return err
+	}
+	_, err = d.store.Create(pvc)
+	return err
+}
+
+// WaitForSinglePVCToBound checks that the PVC is bound
+func (d *Driver) WaitForSinglePVCToBound(pvcName, namespace string, timeout int) error {
+	obj, err := d.store.Get(&corev1.PersistentVolumeClaim{}, namespace, pvcName)
+	if err != nil {
+		return err
+	}
+	if phase := obj.(*corev1.PersistentVolumeClaim).Status.Phase; phase != corev1.ClaimBound {
+		return &scheduler.ErrFailedToValidatePvc{
+			Name:  pvcName,
+			Cause: fmt.Errorf("PVC is in phase %s", phase),
+		}
+	}
+	return nil
+}
+
+// GetPodsRestartCount returns no pods as pods are not modelled individually
+func (d *Driver) GetPodsRestartCount(namespace string, label map[string]string) (map[*corev1.Pod]int32, error) {
+	return map[*corev1.Pod]int32{}, nil
+}
+
+func (d *Driver) getNamespace(namespace string) (*corev1.Namespace, error) {
+	obj, err := d.store.Get(&corev1.Namespace{}, "", namespace)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*corev1.Namespace), nil
+}
+
+// AddNamespaceLabel adds labels on the namespace
+func (d *Driver) AddNamespaceLabel(namespace string, labelMap map[string]string) error {
+	ns, err := d.getNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	if ns.Labels == nil {
+		ns.Labels = make(map[string]string)
+	}
+	for k, v := range labelMap {
+		ns.Labels[k] = v
+	}
+	_, err = d.store.Update(ns)
+	return err
+}
+
+// RemoveNamespaceLabel removes labels from the namespace
+func (d *Driver) RemoveNamespaceLabel(namespace string, labelMap map[string]string) error {
+	ns, err := d.getNamespace(namespace)
+	if err != nil {
+		return err
+	}
+	for k := range labelMap {
+		delete(ns.Labels, k)
+	}
+	_, err = d.store.Update(ns)
+	return err
+}
+
+// GetNamespaceLabel returns the labels of the namespace
+func (d *Driver) GetNamespaceLabel(namespace string) (map[string]string, error) {
+	ns, err := d.getNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return ns.Labels, nil
+}
+
+// GetZones returns the distinct zones of the registered nodes
+func (d *Driver) GetZones() ([]string, error) {
+	zones := make(map[string]bool)
+	for _, n := range node.GetNodes() {
+		if n.Zone != "" {
+			zones[n.Zone] = true
+		}
+	}
+	var result []string
+	for zone := range zones {
+		result = append(result, zone)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// GetASGClusterSize returns the number of registered worker nodes
+func (d *Driver) GetASGClusterSize() (int64, error) {
+	return int64(len(node.GetWorkerNodes())), nil
+}
+
+// SetASGClusterSize is not supported
+func (d *Driver) SetASGClusterSize(perZoneCount int64, timeout time.Duration) error {
+	return &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "SetASGClusterSize()",
+	}
+}
+
+// GetPXCloudDriveConfigMap is not supported
+func (d *Driver) GetPXCloudDriveConfigMap(cluster *operatorcorev1.StorageCluster) (map[string]node.DriveSet, error) {
+	return nil, &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "GetPXCloudDriveConfigMap()",
+	}
+}
+
+// UpgradeChart is not supported
+func (d *Driver) UpgradeChart(repoInfo *scheduler.HelmRepo) (string, error) {
+	return "", &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "UpgradeChart()",
+	}
+}
+
+// RollbackChart is not supported
+func (d *Driver) RollbackChart(repoInfo *scheduler.HelmRepo, revision int) (string, error) {
+	return "", &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "RollbackChart()",
+	}
+}
+
+// HelmHistory is not supported
+func (d *Driver) HelmHistory(repoInfo *scheduler.HelmRepo) ([]scheduler.HelmRevision, error) {
+	return nil, &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "HelmHistory()",
+	}
+}
+
+func init() {
+	scheduler.Register(SchedName, New())
+}
@@ -0,0 +1,209 @@
+package fake
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apapi "github.com/libopenstorage/autopilot-api/pkg/apis/autopilot/v1alpha1"
+	"github.com/portworx/torpedo/drivers/node"
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/drivers/scheduler/spec"
+	"github.com/stretchr/testify/require"
+	appsapi "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storageapi "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getTestApp(key string, replicas int32) *spec.AppSpec {
+	scName := key + "-sc"
+	return &spec.AppSpec{
+		Key: key,
+		SpecList: []interface{}{
+			&storageapi.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: scName},
+				Provisioner: "pxd.portworx.com",
+				Parameters:  map[string]string{"repl": "2"},
+			},
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: key + "-pvc"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &scName,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("10Gi"),
+						},
+					},
+				},
+			},
+			&appsapi.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: key},
+				Spec:       appsapi.DeploymentSpec{Replicas: &replicas},
+			},
+		},
+	}
+}
+
+func setupNodes(t *testing.T, count int) {
+	node.CleanupRegistry()
+	for i := 0; i < count; i++ {
+		require.NoError(t, node.AddNode(node.Node{
+			Name: fmt.Sprintf("node-%d", i),
+			Type: node.TypeWorker,
+		}))
+	}
+	t.Cleanup(node.CleanupRegistry)
+}
+
+func TestScheduleAndValidate(t *testing.T) {
+	setupNodes(t, 3)
+	d := New()
+	require.NoError(t, d.Init(scheduler.InitOptions{}))
+	d.AddApp(getTestApp("nginx", 2))
+
+	contexts, err := d.Schedule("t1", scheduler.ScheduleOptions{AppKeys: []string{"nginx"}})
+	require.NoError(t, err)
+	require.Len(t, contexts, 1)
+	ctx := contexts[0]
+	require.Equal(t, "nginx-t1", ctx.App.NameSpace)
+
+	require.NoError(t, d.WaitForRunning(ctx, time.Second, 10*time.Millisecond))
+	require.NoError(t, d.ValidateVolumes(ctx, time.Second, 10*time.Millisecond, nil))
+
+	vols, err := d.GetVolumes(ctx)
+	require.NoError(t, err)
+	require.Len(t, vols, 1)
+	require.Equal(t, uint64(10*1024*1024*1024), vols[0].Size)
+
+	nodes, err := d.GetNodesForApp(ctx)
+	require.NoError(t, err)
+	require.Len(t, nodes, 2)
+
+	params, err := d.GetVolumeParameters(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "2", params[vols[0].ID]["repl"])
+
+	require.NoError(t, d.Destroy(ctx, nil))
+	require.NoError(t, d.WaitForDestroy(ctx, time.Second))
+	deleted, err := d.DeleteVolumes(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+}
+
+func TestScriptedOutcomes(t *testing.T) {
+	setupNodes(t, 1)
+	d := New()
+	d.AddApp(getTestApp("fio", 1))
+	contexts, err := d.Schedule("t2", scheduler.ScheduleOptions{AppKeys: []string{"fio"}})
+	require.NoError(t, err)
+	ctx := contexts[0]
+
+	scripted := fmt.Errorf("scripted failure")
+	d.Script(OpWaitForRunning, nil, scripted)
+	require.NoError(t, d.WaitForRunning(ctx, time.Second, 10*time.Millisecond))
+	require.Equal(t, scripted, d.WaitForRunning(ctx, time.Second, 10*time.Millisecond))
+	require.NoError(t, d.WaitForRunning(ctx, time.Second, 10*time.Millisecond))
+	require.Equal(t, 3, d.Calls(OpWaitForRunning))
+
+	d.Fail(OpGetVolumes, scripted)
+	_, err = d.GetVolumes(ctx)
+	require.Equal(t, scripted, err)
+	d.Fail(OpGetVolumes, nil)
+	_, err = d.GetVolumes(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, d.SetReadyReplicas(ctx.App.NameSpace, "fio", 0))
+	err = d.WaitForRunning(ctx, 50*time.Millisecond, 10*time.Millisecond)
+	require.Error(t, err)
+	require.IsType(t, &scheduler.ErrFailedToValidateApp{}, err)
+}
+
+func TestScaleAndSnapshot(t *testing.T) {
+	setupNodes(t, 2)
+	d := New()
+	d.AddApp(getTestApp("mysql", 1))
+	contexts, err := d.Schedule("t3", scheduler.ScheduleOptions{AppKeys: []string{"mysql"}})
+	require.NoError(t, err)
+	ctx := contexts[0]
+
+	scaleFactor, err := d.GetScaleFactorMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), scaleFactor["mysql"+DeploymentSuffix])
+	scaleFactor["mysql"+DeploymentSuffix] = 3
+	require.NoError(t, d.ScaleApplication(ctx, scaleFactor))
+	scaleFactor, err = d.GetScaleFactorMap(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), scaleFactor["mysql"+DeploymentSuffix])
+
+	_, err = d.CreateCsiSnapshotClass("px-csi-snapclass", "Delete")
+	require.NoError(t, err)
+	snaps, err := d.CreateCsiSnapsForVolumes(ctx, "px-csi-snapclass")
+	require.NoError(t, err)
+	require.Len(t, snaps, 1)
+	require.NoError(t, d.ValidateCsiSnapshots(ctx, snaps))
+
+	restored, err := d.RestoreCsiSnapAndValidate(ctx, map[string]*storageapi.StorageClass{})
+	require.NoError(t, err)
+	require.Contains(t, restored, "mysql-pvc")
+
+	require.NoError(t, d.DeleteCsiSnapsForVolumes(ctx, 0))
+	exists, err := d.IsCsiSnapshotExists(ctx, snaps["mysql-pvc"].Name, ctx.App.NameSpace)
+	require.NoError(t, err)
+	require.False(t, exists)
+}
+
+func TestParseSpecs(t *testing.T) {
+	specDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "storage.yaml"), []byte(`kind: StorageClass
+apiVersion: storage.k8s.io/v1
+metadata:
+  name: fio-sc
+provisioner: pxd.portworx.com
+parameters:
+  repl: "{{ if .Repl }}{{ .Repl }}{{ else }}2{{ end }}"
+---
+apiVersion: autopilot.libopenstorage.org/v1alpha1
+kind: AutopilotRule
+metadata:
+  name: fio-resize
+spec:
+  actions:
+  - name: openstorage.io.action.volume/resize
+`), 0644))
+
+	d := New()
+	require.NoError(t, d.Init(scheduler.InitOptions{
+		CustomAppConfig: map[string]scheduler.AppConfig{filepath.Base(specDir): {Repl: "3"}},
+	}))
+	specs, err := d.ParseSpecs(specDir, "pxd")
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	require.Equal(t, "3", specs[0].(*storageapi.StorageClass).Parameters["repl"])
+	require.IsType(t, &apapi.AutopilotRule{}, specs[1])
+}
+
+func TestScheduleUninstall(t *testing.T) {
+	setupNodes(t, 1)
+	d := New()
+	app := getTestApp("redis", 1)
+	d.AddApp(app)
+
+	first, err := d.Schedule("t4", scheduler.ScheduleOptions{AppKeys: []string{"redis"}})
+	require.NoError(t, err)
+	second, err := d.Schedule("t5", scheduler.ScheduleOptions{AppKeys: []string{"redis"}})
+	require.NoError(t, err)
+	require.IsType(t, &storageapi.StorageClass{}, app.SpecList[0], "spec list of the app must not be reordered")
+
+	// the storage class created by the first app is left alone when the second one is uninstalled
+	require.NoError(t, d.ScheduleUninstall(second[0], scheduler.ScheduleOptions{AppKeys: []string{"redis"}}))
+	_, err = d.Store().Get(&storageapi.StorageClass{}, "", "redis-sc")
+	require.NoError(t, err)
+	require.NoError(t, d.ScheduleUninstall(first[0], scheduler.ScheduleOptions{AppKeys: []string{"redis"}}))
+	_, err = d.Store().Get(&storageapi.StorageClass{}, "", "redis-sc")
+	require.Error(t, err)
+	require.Empty(t, first[0].App.SpecList)
+}
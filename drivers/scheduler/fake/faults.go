@@ -0,0 +1,31 @@
+package fake
+
+// Op identifies a driver operation whose outcome can be scripted
+type Op string
+
+const (
+	// OpSchedule identifies Schedule and ScheduleWithCustomAppSpecs
+	OpSchedule Op = "Schedule"
+	// OpWaitForRunning identifies WaitForRunning
+	OpWaitForRunning Op = "WaitForRunning"
+	// OpValidateVolumes identifies ValidateVolumes
+	OpValidateVolumes Op = "ValidateVolumes"
+	// OpDestroy identifies Destroy
+	OpDestroy Op = "Destroy"
+	// OpWaitForDestroy identifies WaitForDestroy
+	OpWaitForDestroy Op = "WaitForDestroy"
+	// OpScaleApplication identifies ScaleApplication
+	OpScaleApplication Op = "ScaleApplication"
+	// OpGetVolumes identifies GetVolumes
+	OpGetVolumes Op = "GetVolumes"
+	// OpDeleteVolumes identifies DeleteVolumes
+	OpDeleteVolumes Op = "DeleteVolumes"
+	// OpResizeVolume identifies ResizeVolume and ResizePVC
+	OpResizeVolume Op = "ResizeVolume"
+	// OpCreateCsiSnapshot identifies CreateCsiSnapshot and CreateCsiSnapsForVolumes
+	OpCreateCsiSnapshot Op = "CreateCsiSnapshot"
+	// OpRestoreCsiSnapshot identifies RestoreCsiSnapAndValidate
+	OpRestoreCsiSnapshot Op = "RestoreCsiSnapshot"
+	// OpDeleteCsiSnapshot identifies DeleteCsiSnapshot and DeleteCsiSnapsForVolumes
+	OpDeleteCsiSnapshot Op = "DeleteCsiSnapshot"
+)
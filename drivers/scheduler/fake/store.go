@@ -0,0 +1,188 @@
+package fake
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// objectKey uniquely identifies an object in the in-memory store
+type objectKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (k objectKey) String() string {
+	if k.namespace == "" {
+		return fmt.Sprintf("%s/%s", k.kind, k.name)
+	}
+	return fmt.Sprintf("%s/%s/%s", k.kind, k.namespace, k.name)
+}
+
+// ObjectStore is a minimal in-memory replacement of the kube-apiserver. It only
+// keeps typed objects keyed by their kind, namespace and name. All objects are
+// deep copied on the way in and out so callers can never mutate stored state.
+type ObjectStore struct {
+	sync.RWMutex
+	objects map[objectKey]runtime.Object
+	// resourceVersion is bumped on every write to keep object versions deterministic
+	resourceVersion int64
+}
+
+// NewObjectStore returns an empty object store
+func NewObjectStore() *ObjectStore {
+	return &ObjectStore{
+		objects: make(map[objectKey]runtime.Object),
+	}
+}
+
+func keyFor(obj runtime.Object) (objectKey, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return objectKey{}, err
+	}
+	return objectKey{
+		kind:      kindOf(obj),
+		namespace: accessor.GetNamespace(),
+		name:      accessor.GetName(),
+	}, nil
+}
+
+// kindOf returns the go type name of the object. TypeMeta is frequently empty for
+// objects built in code, so it cannot be relied upon.
+func kindOf(obj interface{}) string {
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// Create adds the given object to the store. It fails if the object already exists.
+func (s *ObjectStore) Create(obj runtime.Object) (runtime.Object, error) {
+	key, err := keyFor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.objects[key]; ok {
+		return nil, fmt.Errorf("object %s already exists", key)
+	}
+	return s.write(key, obj)
+}
+
+// Update replaces an existing object in the store
+func (s *ObjectStore) Update(obj runtime.Object) (runtime.Object, error) {
+	key, err := keyFor(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.objects[key]; !ok {
+		return nil, &ErrObjectNotFound{Key: key.String()}
+	}
+	return s.write(key, obj)
+}
+
+func (s *ObjectStore) write(key objectKey, obj runtime.Object) (runtime.Object, error) {
+	s.resourceVersion++
+	stored := obj.DeepCopyObject()
+	accessor, err := meta.Accessor(stored)
+	if err != nil {
+		return nil, err
+	}
+	accessor.SetResourceVersion(fmt.Sprintf("%d", s.resourceVersion))
+	s.objects[key] = stored
+	return stored.DeepCopyObject(), nil
+}
+
+// Get returns a copy of the object of the same kind as the given prototype
+func (s *ObjectStore) Get(prototype runtime.Object, namespace, name string) (runtime.Object, error) {
+	key := objectKey{kind: kindOf(prototype), namespace: namespace, name: name}
+
+	s.RLock()
+	defer s.RUnlock()
+	obj, ok := s.objects[key]
+	if !ok {
+		return nil, &ErrObjectNotFound{Key: key.String()}
+	}
+	return obj.DeepCopyObject(), nil
+}
+
+// Delete removes the object of the same kind as the given prototype
+func (s *ObjectStore) Delete(prototype runtime.Object, namespace, name string) error {
+	key := objectKey{kind: kindOf(prototype), namespace: namespace, name: name}
+
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.objects[key]; !ok {
+		return &ErrObjectNotFound{Key: key.String()}
+	}
+	delete(s.objects, key)
+	return nil
+}
+
+// List returns copies of all objects of the same kind as the given prototype in
+// the given namespace. An empty namespace lists across all namespaces. Objects
+// are sorted by namespace and name so the output is deterministic.
+func (s *ObjectStore) List(prototype runtime.Object, namespace string) []runtime.Object {
+	kind := kindOf(prototype)
+
+	s.RLock()
+	var keys []objectKey
+	for key := range s.objects {
+		if key.kind == kind && (namespace == "" || key.namespace == namespace) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].name < keys[j].name
+	})
+	objs := make([]runtime.Object, 0, len(keys))
+	for _, key := range keys {
+		objs = append(objs, s.objects[key].DeepCopyObject())
+	}
+	s.RUnlock()
+
+	return objs
+}
+
+// DeleteNamespace removes all namespaced objects in the given namespace
+func (s *ObjectStore) DeleteNamespace(namespace string) {
+	s.Lock()
+	defer s.Unlock()
+	for key := range s.objects {
+		if key.namespace == namespace {
+			delete(s.objects, key)
+		}
+	}
+}
+
+// Reset removes all objects from the store
+func (s *ObjectStore) Reset() {
+	s.Lock()
+	defer s.Unlock()
+	s.objects = make(map[objectKey]runtime.Object)
+	s.resourceVersion = 0
+}
+
+// ErrObjectNotFound is returned when an object is not present in the store
+type ErrObjectNotFound struct {
+	Key string
+}
+
+func (e *ErrObjectNotFound) Error() string {
+	return fmt.Sprintf("object %s not found", e.Key)
+}
@@ -0,0 +1,375 @@
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/portworx/torpedo/pkg/log"
+	"github.com/portworx/torpedo/pkg/osutils"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// parseCustomResources decodes the custom resources of a rendered cr- spec
+func parseCustomResources(content []byte) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	specReader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+	for {
+		specContents, err := specReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(specContents)) == 0 {
+			continue
+		}
+		data, err := yaml.ToJSON(specContents)
+		if err != nil {
+			return nil, err
+		}
+		if string(data) == "null" {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err = obj.UnmarshalJSON(data); err != nil {
+			return nil, err
+		}
+		if obj.GetKind() == "" || obj.GetAPIVersion() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("custom resource must have an apiVersion, a kind and a name: %v", obj.Object)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
+
+// validateCustomResources validates the custom resources of the specs against the schemas of the
+// CRDs defined in the specs, returning an error for every invalid custom resource. The custom resources
+// of CRDs defined elsewhere are validated when created.
+func validateCustomResources(specs []interface{}) []error {
+	var crds []interface{}
+	for _, spec := range specs {
+		switch spec.(type) {
+		case *apiextensionsv1.CustomResourceDefinition, *apiextensionsv1beta1.CustomResourceDefinition:
+			crds = append(crds, spec)
+		}
+	}
+	var errs []error
+	for _, spec := range specs {
+		if obj, ok := spec.(*CustomResourceObjectYAML); ok {
+			errs = append(errs, obj.validate(crds)...)
+		}
+	}
+	return errs
+}
+
+// validate validates the custom resources against the schemas of the CRDs which define them,
+// custom resources without a CRD are left to be validated later
+func (obj *CustomResourceObjectYAML) validate(crds []interface{}) []error {
+	validated := true
+	var errs []error
+	for _, cr := range obj.Objects {
+		crdSchema, found, err := findCRDSchema(crds, cr.GroupVersionKind())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !found {
+			validated = false
+			continue
+		}
+		if crdSchema == nil {
+			// the CRD has no schema, anything goes
+			continue
+		}
+		if err = validateCustomResource(cr, crdSchema); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s [%s] in [%s]: %v", cr.GetKind(), cr.GetName(), obj.Path, err))
+			continue
+		}
+		log.Debugf("Validated %s [%s] in [%s]", cr.GetKind(), cr.GetName(), obj.Path)
+	}
+	obj.validated = validated && len(errs) == 0
+	return errs
+}
+
+// findCRDSchema returns the schema of the version of the CRD defining the kind, if the CRD is found
+func findCRDSchema(crds []interface{}, gvk schema.GroupVersionKind) (*apiextensionsv1.JSONSchemaProps, bool, error) {
+	for _, crd := range crds {
+		switch crd := crd.(type) {
+		case *apiextensionsv1.CustomResourceDefinition:
+			if crd.Spec.Group != gvk.Group || crd.Spec.Names.Kind != gvk.Kind {
+				continue
+			}
+			for _, version := range crd.Spec.Versions {
+				if version.Name == gvk.Version {
+					if version.Schema == nil {
+						return nil, true, nil
+					}
+					return version.Schema.OpenAPIV3Schema, true, nil
+				}
+			}
+			return nil, false, fmt.Errorf("version [%s] of %s is not served by CRD [%s]", gvk.Version, gvk.Kind, crd.Name)
+		case *apiextensionsv1beta1.CustomResourceDefinition:
+			if crd.Spec.Group != gvk.Group || crd.Spec.Names.Kind != gvk.Kind {
+				continue
+			}
+			validation := crd.Spec.Validation
+			for _, version := range crd.Spec.Versions {
+				if version.Name == gvk.Version && version.Schema != nil {
+					validation = version.Schema
+				}
+			}
+			if validation == nil || validation.OpenAPIV3Schema == nil {
+				return nil, true, nil
+			}
+			// both versions of the schema have the same JSON representation
+			data, err := json.Marshal(validation.OpenAPIV3Schema)
+			if err != nil {
+				return nil, true, err
+			}
+			crdSchema := &apiextensionsv1.JSONSchemaProps{}
+			if err = json.Unmarshal(data, crdSchema); err != nil {
+				return nil, true, err
+			}
+			return crdSchema, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// validateCustomResource validates a custom resource against the OpenAPI schema of its CRD.
+// Unlike the API server, fields unknown to the schema are reported instead of being pruned,
+// so that typos in the specs are caught.
+func validateCustomResource(obj *unstructured.Unstructured, crdSchema *apiextensionsv1.JSONSchemaProps) error {
+	return validateSchema(nil, obj.Object, crdSchema, true).ToAggregate()
+}
+
+// validateSchema validates a value against a schema, the metadata of resources is validated by the API server
+func validateSchema(fldPath *field.Path, value interface{}, s *apiextensionsv1.JSONSchemaProps, isResource bool) field.ErrorList {
+	var errs field.ErrorList
+	if value == nil {
+		if !s.Nullable && s.Type != "" {
+			errs = append(errs, field.Invalid(fldPath, value, "must not be null"))
+		}
+		return errs
+	}
+	if s.XIntOrString {
+		switch value.(type) {
+		case int64, string:
+		default:
+			return append(errs, field.Invalid(fldPath, value, "must be an integer or a string"))
+		}
+	} else if s.Type != "" {
+		if err := validateType(fldPath, value, s.Type); err != nil {
+			return append(errs, err)
+		}
+	}
+
+	errs = append(errs, validateEnum(fldPath, value, s)...)
+	switch value := value.(type) {
+	case map[string]interface{}:
+		errs = append(errs, validateObject(fldPath, value, s, isResource || s.XEmbeddedResource)...)
+	case []interface{}:
+		if s.MinItems != nil && int64(len(value)) < *s.MinItems {
+			errs = append(errs, field.Invalid(fldPath, len(value), fmt.Sprintf("must have at least %d items", *s.MinItems)))
+		}
+		if s.MaxItems != nil && int64(len(value)) > *s.MaxItems {
+			errs = append(errs, field.TooMany(fldPath, len(value), int(*s.MaxItems)))
+		}
+		if s.Items != nil && s.Items.Schema != nil {
+			for i, item := range value {
+				errs = append(errs, validateSchema(fldPath.Index(i), item, s.Items.Schema, false)...)
+			}
+		}
+	case string:
+		if s.MinLength != nil && int64(len(value)) < *s.MinLength {
+			errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("must be at least %d characters long", *s.MinLength)))
+		}
+		if s.MaxLength != nil && int64(len(value)) > *s.MaxLength {
+			errs = append(errs, field.TooLong(fldPath, value, int(*s.MaxLength)))
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(value) {
+				errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("must match the pattern %s", s.Pattern)))
+			}
+		}
+	case int64:
+		errs = append(errs, validateNumber(fldPath, float64(value), s)...)
+	case float64:
+		errs = append(errs, validateNumber(fldPath, value, s)...)
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, validateSchema(fldPath, value, &sub, isResource)...)
+	}
+	if len(s.AnyOf) > 0 && matchingSchemas(fldPath, value, s.AnyOf) == 0 {
+		errs = append(errs, field.Invalid(fldPath, value, "must match at least one of the schemas in anyOf"))
+	}
+	if len(s.OneOf) > 0 && matchingSchemas(fldPath, value, s.OneOf) != 1 {
+		errs = append(errs, field.Invalid(fldPath, value, "must match exactly one of the schemas in oneOf"))
+	}
+	return errs
+}
+
+func validateType(fldPath *field.Path, value interface{}, schemaType string) *field.Error {
+	valid := false
+	switch schemaType {
+	case "object":
+		_, valid = value.(map[string]interface{})
+	case "array":
+		_, valid = value.([]interface{})
+	case "string":
+		_, valid = value.(string)
+	case "boolean":
+		_, valid = value.(bool)
+	case "integer":
+		_, valid = value.(int64)
+	case "number":
+		switch value.(type) {
+		case int64, float64:
+			valid = true
+		}
+	default:
+		valid = true
+	}
+	if !valid {
+		return field.Invalid(fldPath, value, fmt.Sprintf("must be of type %s", schemaType))
+	}
+	return nil
+}
+
+func validateObject(fldPath *field.Path, value map[string]interface{}, s *apiextensionsv1.JSONSchemaProps, isResource bool) field.ErrorList {
+	var errs field.ErrorList
+	for _, required := range s.Required {
+		if _, ok := value[required]; !ok {
+			errs = append(errs, field.Required(fldPath.Child(required), ""))
+		}
+	}
+	preserveUnknown := s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields
+	for key, child := range value {
+		if isResource && (key == "apiVersion" || key == "kind" || key == "metadata") {
+			continue
+		}
+		if prop, ok := s.Properties[key]; ok {
+			errs = append(errs, validateSchema(fldPath.Child(key), child, &prop, false)...)
+		} else if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			errs = append(errs, validateSchema(fldPath.Key(key), child, s.AdditionalProperties.Schema, false)...)
+		} else if s.AdditionalProperties != nil && s.AdditionalProperties.Allows {
+			continue
+		} else if !preserveUnknown && (len(s.Properties) > 0 || s.AdditionalProperties != nil) {
+			errs = append(errs, field.NotSupported(fldPath.Child(key), child, sortedKeys(s.Properties)))
+		}
+	}
+	return errs
+}
+
+func validateNumber(fldPath *field.Path, value float64, s *apiextensionsv1.JSONSchemaProps) field.ErrorList {
+	var errs field.ErrorList
+	if s.Minimum != nil && (value < *s.Minimum || s.ExclusiveMinimum && value == *s.Minimum) {
+		errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("must be greater than or equal to %v", *s.Minimum)))
+	}
+	if s.Maximum != nil && (value > *s.Maximum || s.ExclusiveMaximum && value == *s.Maximum) {
+		errs = append(errs, field.Invalid(fldPath, value, fmt.Sprintf("must be less than or equal to %v", *s.Maximum)))
+	}
+	return errs
+}
+
+func validateEnum(fldPath *field.Path, value interface{}, s *apiextensionsv1.JSONSchemaProps) field.ErrorList {
+	if len(s.Enum) == 0 {
+		return nil
+	}
+	normalized, err := normalizeJSON(value)
+	if err != nil {
+		return field.ErrorList{field.InternalError(fldPath, err)}
+	}
+	var supported []string
+	for _, enum := range s.Enum {
+		var allowed interface{}
+		if err = json.Unmarshal(enum.Raw, &allowed); err != nil {
+			return field.ErrorList{field.InternalError(fldPath, err)}
+		}
+		if reflect.DeepEqual(normalized, allowed) {
+			return nil
+		}
+		supported = append(supported, string(enum.Raw))
+	}
+	return field.ErrorList{field.NotSupported(fldPath, value, supported)}
+}
+
+// matchingSchemas returns the number of schemas the value is valid against
+func matchingSchemas(fldPath *field.Path, value interface{}, schemas []apiextensionsv1.JSONSchemaProps) int {
+	matching := 0
+	for _, sub := range schemas {
+		// the branches of anyOf and oneOf only validate values, the fields are known to the parent schema
+		sub.XPreserveUnknownFields = &[]bool{true}[0]
+		if len(validateSchema(fldPath, value, &sub, false)) == 0 {
+			matching++
+		}
+	}
+	return matching
+}
+
+// normalizeJSON converts the value to the types JSON values are decoded to
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func sortedKeys(properties map[string]apiextensionsv1.JSONSchemaProps) []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// kubectlCustomResources runs kubectl on the rendered custom resources of the spec
+func kubectlCustomResources(verb string, obj *CustomResourceObjectYAML, namespace string) error {
+	cryaml := obj.Path
+	if obj.Content != nil {
+		f, err := os.CreateTemp("", "cr-*.yaml")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(f.Name())
+		if _, err = f.Write(obj.Content); err != nil {
+			f.Close()
+			return err
+		}
+		if err = f.Close(); err != nil {
+			return err
+		}
+		cryaml = f.Name()
+	} else if _, err := os.Stat(cryaml); err != nil {
+		return fmt.Errorf("Cannot find yaml in path %s", cryaml)
+	}
+	return osutils.Kubectl([]string{verb, "-f", cryaml, "-n", namespace})
+}
+
+// customResourceNames returns the names of the custom resources of the spec
+func (obj *CustomResourceObjectYAML) customResourceNames() string {
+	var names []string
+	for _, cr := range obj.Objects {
+		names = append(names, cr.GetName())
+	}
+	return strings.Join(names, ",")
+}
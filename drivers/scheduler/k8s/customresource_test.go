@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func TestParseCustomResourceSpecs(t *testing.T) {
+	k := &K8s{
+		customConfig: map[string]scheduler.AppConfig{
+			"elasticsearch-crd-webhook": {Replicas: 5, VolumeSize: "10Gi"},
+		},
+	}
+
+	specs, err := k.ParseSpecs("specs/elasticsearch-crd-webhook", "pxd")
+	require.NoError(t, err)
+	var crs []*CustomResourceObjectYAML
+	for _, spec := range specs {
+		if obj, ok := spec.(*CustomResourceObjectYAML); ok {
+			crs = append(crs, obj)
+		}
+	}
+	require.Len(t, crs, 1)
+	require.Equal(t, "px-es", crs[0].Name)
+	require.True(t, crs[0].validated)
+	require.Contains(t, string(crs[0].Content), "count: 5")
+
+	nodeSets, _, err := unstructured.NestedSlice(crs[0].Objects[0].Object, "spec", "nodeSets")
+	require.NoError(t, err)
+	require.Equal(t, int64(5), nodeSets[0].(map[string]interface{})["count"])
+}
+
+const testCRDSchema = `
+type: object
+properties:
+  spec:
+    type: object
+    required: [size]
+    properties:
+      size:
+        type: integer
+        minimum: 1
+      mode:
+        type: string
+        enum: [fast, safe]
+      port:
+        x-kubernetes-int-or-string: true
+      labels:
+        type: object
+        additionalProperties:
+          type: string
+      config:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+`
+
+func TestValidateCustomResource(t *testing.T) {
+	crdSchema := &apiextensionsv1.JSONSchemaProps{}
+	require.NoError(t, yaml.Unmarshal([]byte(testCRDSchema), crdSchema))
+
+	validate := func(spec string) error {
+		objects, err := parseCustomResources([]byte("apiVersion: test.io/v1\nkind: Test\nmetadata:\n  name: test\nspec:\n" + spec))
+		require.NoError(t, err)
+		require.Len(t, objects, 1)
+		return validateCustomResource(objects[0], crdSchema)
+	}
+
+	require.NoError(t, validate("  size: 3\n  mode: safe\n  port: http\n  labels: {a: b}\n  config: {any: [1, 2]}\n"))
+	require.NoError(t, validate("  size: 3\n  port: 8080\n"))
+	require.EqualError(t, validate("  mode: safe\n"), "spec.size: Required value")
+	require.EqualError(t, validate("  size: three\n"), `spec.size: Invalid value: "three": must be of type integer`)
+	require.EqualError(t, validate("  size: 0\n"), "spec.size: Invalid value: 0: must be greater than or equal to 1")
+	require.EqualError(t, validate("  size: 1\n  mode: slow\n"), `spec.mode: Unsupported value: "slow": supported values: "\"fast\"", "\"safe\""`)
+	require.EqualError(t, validate("  size: 1\n  port: true\n"), "spec.port: Invalid value: true: must be an integer or a string")
+	require.EqualError(t, validate("  size: 1\n  labels: {a: 1}\n"), "spec.labels[a]: Invalid value: 1: must be of type string")
+	require.Error(t, validate("  size: 1\n  sise: 2\n"))
+}
@@ -8,25 +8,31 @@ import (
 	"gopkg.in/yaml.v2"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/portworx/torpedo/drivers/scheduler"
 	"github.com/portworx/torpedo/drivers/scheduler/spec"
 
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
 	"helm.sh/helm/v3/pkg/cli"
-	"helm.sh/helm/v3/pkg/cli/values"
 	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/releaseutil"
 	"helm.sh/helm/v3/pkg/repo"
 	"helm.sh/helm/v3/pkg/strvals"
+	sigsyaml "sigs.k8s.io/yaml"
 )
 
 var settings *cli.EnvSettings
@@ -44,6 +50,13 @@ const (
 	HelmRepoName  = "repo-name"
 )
 
+const (
+	// helmValuesDir is the directory of the values files in the app spec dir
+	helmValuesDir = "values"
+	// maxHelmHistory is the maximum number of revisions returned by HelmHistory, as with helm history
+	maxHelmHistory = 256
+)
+
 // HelmSchedule will install the application with helm
 func (k *K8s) HelmSchedule(app *spec.AppSpec, appNamespace string, options scheduler.ScheduleOptions) ([]interface{}, error) {
 	var specObjects []interface{}
@@ -86,6 +99,11 @@ func (k *K8s) HelmSchedule(app *spec.AppSpec, appNamespace string, options sched
 					return nil, err
 				}
 			} else {
+				if diff, err := k.DiffChart(repoInfo); err != nil {
+					log.Warnf("Failed to diff the upgrade of release [%s]: %v", repoInfo.ReleaseName, err)
+				} else {
+					log.Infof("Upgrading release [%s] in [%s]:\n%s", repoInfo.ReleaseName, appNamespace, diff)
+				}
 				manifest, err = k.UpgradeChart(repoInfo)
 				if err != nil {
 					return nil, err
@@ -137,13 +155,18 @@ func (k *K8s) SetHelmParams(appKey string, helmRepo *scheduler.HelmRepo, options
 	if values, ok := configMap.Data[HelmValues]; ok {
 		helmRepo.Values = values
 		log.Debugf("helm values set: %s", helmRepo.Values)
-	} else {
+	} else if len(helmRepo.ValuesFiles) == 0 && helmRepo.Overrides == "" {
+		// apps with values files or overrides in their spec may not need any other value
 		return fmt.Errorf("helm install custom values not provided in the configmap %s", appKey)
 	}
 
 	// some values are generated during the test, e.g. UI endpoint and OIDC secret
 	if extraValues, ok := configMap.Data[HelmExtraValues]; ok && extraValues != "" {
-		helmRepo.Values = fmt.Sprintf("%s,%s", helmRepo.Values, extraValues)
+		if helmRepo.Values != "" {
+			helmRepo.Values = fmt.Sprintf("%s,%s", helmRepo.Values, extraValues)
+		} else {
+			helmRepo.Values = extraValues
+		}
 		log.Debugf("helm extra values added: %s", helmRepo.Values)
 	}
 
@@ -166,6 +189,86 @@ func (k *K8s) ParseCharts(fileName string) (interface{}, error) {
 	return &repoInfo, nil
 }
 
+// renderHelmValues renders the values files and overrides of the chart with the app config and
+// merges them in order into the rendered values
+func renderHelmValues(repoInfo *scheduler.HelmRepo, specDir string, customConfig scheduler.AppConfig) error {
+	vals := map[string]interface{}{}
+	for _, valuesFile := range repoInfo.ValuesFiles {
+		path := filepath.Join(specDir, valuesFile)
+		file, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fileVals, err := renderValues(file, customConfig)
+		if err != nil {
+			return fmt.Errorf("values file [%s]: %v", path, err)
+		}
+		vals = mergeHelmValues(vals, fileVals)
+	}
+	if repoInfo.Overrides != "" {
+		overrides, err := renderValues([]byte(repoInfo.Overrides), customConfig)
+		if err != nil {
+			return fmt.Errorf("overrides: %v", err)
+		}
+		vals = mergeHelmValues(vals, overrides)
+	}
+	repoInfo.RenderedValues = vals
+	return nil
+}
+
+func renderValues(file []byte, customConfig scheduler.AppConfig) (map[string]interface{}, error) {
+	processedFile, err := renderSpecTemplate(file, customConfig)
+	if err != nil {
+		return nil, err
+	}
+	return chartutil.ReadValues(processedFile)
+}
+
+// mergeHelmValues returns the values of dst overwritten by the ones of src, maps are merged recursively
+// and copied so that dst and src are not modified when the result is
+func mergeHelmValues(dst, src map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(dst)+len(src))
+	for key, val := range dst {
+		if valMap, ok := val.(map[string]interface{}); ok {
+			val = mergeHelmValues(nil, valMap)
+		}
+		merged[key] = val
+	}
+	for key, val := range src {
+		if valMap, ok := val.(map[string]interface{}); ok {
+			dstMap, _ := merged[key].(map[string]interface{})
+			val = mergeHelmValues(dstMap, valMap)
+		}
+		merged[key] = val
+	}
+	return merged
+}
+
+// chartValues returns the values to install or upgrade the chart with, the rendered values of the app spec
+// overwritten by the ones set in the helm ConfigMap
+func chartValues(repoInfo *scheduler.HelmRepo) (map[string]interface{}, error) {
+	vals := mergeHelmValues(nil, repoInfo.RenderedValues)
+
+	// Get values from ConfigMap if exist
+	if repoInfo.Values != "" {
+		if err := strvals.ParseInto(repoInfo.Values, vals); err != nil {
+			return nil, errors.Wrap(err, "failed parsing --set data")
+		}
+	}
+	return vals, nil
+}
+
+func newHelmActionConfig(namespace string) (*action.Configuration, error) {
+	if settings == nil {
+		settings = cli.New()
+	}
+	actionConfig := new(action.Configuration)
+	if err := actionConfig.Init(settings.RESTClientGetter(), namespace, os.Getenv("HELM_DRIVER"), debug); err != nil {
+		return nil, err
+	}
+	return actionConfig, nil
+}
+
 // RepoAdd adds repo with given name and url
 func (k *K8s) RepoAdd(repoInfo *scheduler.HelmRepo) error {
 	name := repoInfo.RepoName
@@ -266,8 +369,8 @@ func (k *K8s) RepoUpdate() error {
 
 // InstallChart will install the helm chart
 func (k *K8s) InstallChart(repoInfo *scheduler.HelmRepo) (string, error) {
-	actionConfig := new(action.Configuration)
-	if err := actionConfig.Init(settings.RESTClientGetter(), repoInfo.Namespace, os.Getenv("HELM_DRIVER"), debug); err != nil {
+	actionConfig, err := newHelmActionConfig(repoInfo.Namespace)
+	if err != nil {
 		return "", err
 	}
 	client := action.NewInstall(actionConfig)
@@ -284,17 +387,11 @@ func (k *K8s) InstallChart(repoInfo *scheduler.HelmRepo) (string, error) {
 	log.Debugf("chart install path: %s", cp)
 
 	p := getter.All(settings)
-	valueOpts := &values.Options{}
-	vals, err := valueOpts.MergeValues(p)
+	vals, err := chartValues(repoInfo)
 	if err != nil {
 		return "", err
 	}
 
-	// Add args
-	if err := strvals.ParseInto(repoInfo.Values, vals); err != nil {
-		return "", errors.Wrap(err, "failed parsing --set data")
-	}
-
 	// Check chart dependencies to make sure all are present in /charts
 	chartRequested, err := loader.Load(cp)
 	if err != nil {
@@ -348,10 +445,19 @@ func isChartInstallable(ch *chart.Chart) (bool, error) {
 
 // UpgradeChart will upgrade the release
 func (k *K8s) UpgradeChart(repoInfo *scheduler.HelmRepo) (string, error) {
-	actionConfig := new(action.Configuration)
-	if err := actionConfig.Init(settings.RESTClientGetter(), repoInfo.Namespace, os.Getenv("HELM_DRIVER"), debug); err != nil {
+	upgraded, err := k.upgradeChart(repoInfo, false)
+	if err != nil {
 		return "", err
 	}
+	return upgraded.Manifest, nil
+}
+
+// upgradeChart upgrades the release, or only renders the upgrade if dryRun is set
+func (k *K8s) upgradeChart(repoInfo *scheduler.HelmRepo, dryRun bool) (*release.Release, error) {
+	actionConfig, err := newHelmActionConfig(repoInfo.Namespace)
+	if err != nil {
+		return nil, err
+	}
 
 	client := action.NewUpgrade(actionConfig)
 	if client.Version == "" && client.Devel {
@@ -359,55 +465,186 @@ func (k *K8s) UpgradeChart(repoInfo *scheduler.HelmRepo) (string, error) {
 	}
 	cp, err := client.ChartPathOptions.LocateChart(fmt.Sprintf("%s/%s", repoInfo.RepoName, repoInfo.ChartName), settings)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	log.Debugf("chart upgrade path: %s", cp)
 
-	p := getter.All(settings)
-	valueOpts := &values.Options{}
-	vals, err := valueOpts.MergeValues(p)
+	vals, err := chartValues(repoInfo)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// Get values from ConfigMap if exist
-	if err := strvals.ParseInto(repoInfo.Values, vals); err != nil {
-		return "", errors.Wrap(err, "failed parsing --set data")
+	// Check chart dependencies to make sure all are present in /charts
+	chartRequested, err := loader.Load(cp)
+	if err != nil {
+		return nil, err
 	}
 
-	// Overwrite using existing values
-	existVals, err := k.GetChartValues(repoInfo)
+	validInstallableChart, err := isChartInstallable(chartRequested)
+	if !validInstallableChart {
+		return nil, err
+	}
+
+	client.Namespace = repoInfo.Namespace
+	client.DryRun = dryRun
+	// as helm upgrade --reuse-values, the values of the release are kept under
+	// the ones of the spec only if asked, else the chart defaults are used
+	client.ReuseValues = repoInfo.ReuseValues
+
+	return client.Run(repoInfo.ReleaseName, chartRequested, vals)
+}
+
+// DiffChart renders the upgrade of the release without applying it and returns the diff of its manifest
+// with the deployed one, empty if the upgrade changes no object
+func (k *K8s) DiffChart(repoInfo *scheduler.HelmRepo) (string, error) {
+	actionConfig, err := newHelmActionConfig(repoInfo.Namespace)
+	if err != nil {
+		return "", err
+	}
+	deployed, err := action.NewGet(actionConfig).Run(repoInfo.ReleaseName)
 	if err != nil {
-		return "", errors.Wrap(err, "failed getting existing values --set data")
+		return "", err
 	}
-	for k, v := range existVals {
-		vals[k] = v
+	upgraded, err := k.upgradeChart(repoInfo, true)
+	if err != nil {
+		return "", err
 	}
+	return diffManifests(deployed.Manifest, upgraded.Manifest)
+}
 
-	// Check chart dependencies to make sure all are present in /charts
-	chartRequested, err := loader.Load(cp)
+// RollbackChart rolls the release back to the revision, or to the previous one if 0, and returns its manifest
+func (k *K8s) RollbackChart(repoInfo *scheduler.HelmRepo, revision int) (string, error) {
+	actionConfig, err := newHelmActionConfig(repoInfo.Namespace)
 	if err != nil {
 		return "", err
 	}
 
-	validInstallableChart, err := isChartInstallable(chartRequested)
-	if !validInstallableChart {
+	if revision == 0 {
+		// resolve the previous revision, as helm does, to log it
+		current, err := action.NewGet(actionConfig).Run(repoInfo.ReleaseName)
+		if err != nil {
+			return "", err
+		}
+		revision = current.Version - 1
+	}
+
+	client := action.NewRollback(actionConfig)
+	client.Version = revision
+	client.Wait = true
+	client.Timeout = DefaultTimeout
+	if err = client.Run(repoInfo.ReleaseName); err != nil {
 		return "", err
 	}
+	log.Infof("Rolled back release [%s] in [%s] to revision [%d]", repoInfo.ReleaseName, repoInfo.Namespace, revision)
 
-	client.Namespace = repoInfo.Namespace
+	rolledBack, err := action.NewGet(actionConfig).Run(repoInfo.ReleaseName)
+	if err != nil {
+		return "", err
+	}
+	return rolledBack.Manifest, nil
+}
+
+// HelmHistory returns the revisions of the release, oldest first
+func (k *K8s) HelmHistory(repoInfo *scheduler.HelmRepo) ([]scheduler.HelmRevision, error) {
+	actionConfig, err := newHelmActionConfig(repoInfo.Namespace)
+	if err != nil {
+		return nil, err
+	}
 
-	release, err := client.Run(repoInfo.ReleaseName, chartRequested, vals)
+	client := action.NewHistory(actionConfig)
+	client.Max = maxHelmHistory
+	history, err := client.Run(repoInfo.ReleaseName)
+	if err != nil {
+		return nil, err
+	}
+	releaseutil.SortByRevision(history)
+	revisions := make([]scheduler.HelmRevision, 0, len(history))
+	for _, rel := range history {
+		revision := scheduler.HelmRevision{Revision: rel.Version}
+		if rel.Info != nil {
+			revision.Status = rel.Info.Status.String()
+			revision.Updated = rel.Info.LastDeployed.Time
+			revision.Description = rel.Info.Description
+		}
+		if rel.Chart != nil && rel.Chart.Metadata != nil {
+			revision.ChartVersion = rel.Chart.Metadata.Version
+			revision.AppVersion = rel.Chart.Metadata.AppVersion
+		}
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// diffManifests returns the unified diff of the objects of two release manifests, objects are matched by
+// kind, namespace and name
+func diffManifests(from, to string) (string, error) {
+	fromObjects, err := manifestObjects(from)
 	if err != nil {
 		return "", err
 	}
-	return release.Manifest, nil
+	toObjects, err := manifestObjects(to)
+	if err != nil {
+		return "", err
+	}
+
+	var keys []string
+	for key := range fromObjects {
+		keys = append(keys, key)
+	}
+	for key := range toObjects {
+		if _, ok := fromObjects[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var diff strings.Builder
+	for _, key := range keys {
+		if fromObjects[key] == toObjects[key] {
+			continue
+		}
+		objectDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(fromObjects[key]),
+			B:        difflib.SplitLines(toObjects[key]),
+			FromFile: "deployed " + key,
+			ToFile:   "upgraded " + key,
+			Context:  3,
+		})
+		if err != nil {
+			return "", err
+		}
+		diff.WriteString(objectDiff)
+	}
+	return diff.String(), nil
+}
+
+// manifestObjects splits a release manifest into its objects, indexed by kind, namespace and name
+func manifestObjects(manifest string) (map[string]string, error) {
+	objects := make(map[string]string)
+	for _, object := range releaseutil.SplitManifests(manifest) {
+		var head struct {
+			Kind     string `json:"kind"`
+			Metadata struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}
+		if err := sigsyaml.Unmarshal([]byte(object), &head); err != nil {
+			return nil, err
+		}
+		if head.Kind == "" {
+			continue
+		}
+		key := path.Join(head.Kind, head.Metadata.Namespace, head.Metadata.Name)
+		objects[key] = strings.TrimSpace(object) + "\n"
+	}
+	return objects, nil
 }
 
 // GetChartValues gets existing helm values for current installed release
 func (k *K8s) GetChartValues(repoInfo *scheduler.HelmRepo) (map[string]interface{}, error) {
-	actionConfig := new(action.Configuration)
-	if err := actionConfig.Init(settings.RESTClientGetter(), repoInfo.Namespace, os.Getenv("HELM_DRIVER"), debug); err != nil {
+	actionConfig, err := newHelmActionConfig(repoInfo.Namespace)
+	if err != nil {
 		return nil, err
 	}
 
@@ -422,9 +659,8 @@ func (k *K8s) GetChartValues(repoInfo *scheduler.HelmRepo) (map[string]interface
 
 // UnInstallHelmChart will uninstall the release
 func (k *K8s) UnInstallHelmChart(repoInfo *scheduler.HelmRepo) ([]interface{}, error) {
-	var err error
-	actionConfig := new(action.Configuration)
-	if err = actionConfig.Init(settings.RESTClientGetter(), repoInfo.Namespace, os.Getenv("HELM_DRIVER"), debug); err != nil {
+	actionConfig, err := newHelmActionConfig(repoInfo.Namespace)
+	if err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHelmSpecsWithValues(t *testing.T) {
+	specDir := filepath.Join(t.TempDir(), "helm-app")
+	files := map[string]string{
+		"repo.yaml": `reponame: test
+chartname: app
+releasename: app
+reusevalues: true
+valuesfiles: [values/base.yaml, values/ha.yaml]
+overrides: |
+  persistence:
+    size: {{ if .VolumeSize }}{{ .VolumeSize }}{{ else }}1Gi{{ end }}
+`,
+		"values/base.yaml": `replicas: 1
+image:
+  repository: nginx
+  tag: "1.23"
+persistence:
+  enabled: true
+`,
+		"values/ha.yaml": `replicas: {{ if .Replicas }}{{ .Replicas }}{{ else }}3{{ end }}
+image:
+  tag: "1.25"
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(specDir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(specDir, name), []byte(content), 0644))
+	}
+
+	k := &K8s{customConfig: map[string]scheduler.AppConfig{"helm-app": {VolumeSize: "10Gi"}}}
+	specs, err := k.ParseSpecs(specDir, "pxd")
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	repoInfo, ok := specs[0].(*scheduler.HelmRepo)
+	require.True(t, ok)
+	require.True(t, repoInfo.ReuseValues)
+	require.Equal(t, map[string]interface{}{
+		"replicas": float64(3),
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.25",
+		},
+		"persistence": map[string]interface{}{
+			"enabled": true,
+			"size":    "10Gi",
+		},
+	}, repoInfo.RenderedValues)
+
+	repoInfo.Values = "image.tag=1.27"
+	vals, err := chartValues(repoInfo)
+	require.NoError(t, err)
+	require.Equal(t, "1.27", vals["image"].(map[string]interface{})["tag"])
+	require.Equal(t, "1.25", repoInfo.RenderedValues["image"].(map[string]interface{})["tag"])
+}
+
+func TestDiffManifests(t *testing.T) {
+	deployed := `---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+`
+	upgraded := `---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 3
+---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+`
+
+	diff, err := diffManifests(deployed, deployed)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	diff, err = diffManifests(deployed, upgraded)
+	require.NoError(t, err)
+	require.Contains(t, diff, "+++ upgraded ConfigMap/app\n")
+	require.Contains(t, diff, "+++ upgraded Deployment/app\n")
+	require.Contains(t, diff, "-  replicas: 1\n+  replicas: 3\n")
+	require.NotContains(t, diff, "Service/app")
+}
@@ -20,7 +20,6 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"text/template"
 	"time"
 
 	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
@@ -67,6 +66,7 @@ import (
 	"github.com/portworx/torpedo/pkg/aututils"
 	"github.com/portworx/torpedo/pkg/errors"
 	"github.com/portworx/torpedo/pkg/pureutils"
+	"github.com/portworx/torpedo/pkg/tracing"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	tektoncdv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
@@ -87,6 +87,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
@@ -258,6 +259,42 @@ type CustomResourceObjectYAML struct {
 	// Namespace will only be assigned DURING creation
 	Namespace string
 	Name      string
+	// Content is the spec rendered with the app config, applied instead of the file at Path
+	Content []byte
+	// Objects are the custom resources of the spec
+	Objects []*unstructured.Unstructured
+	// validated is true once all custom resources are validated against the schemas of their CRDs
+	validated bool
+}
+
+// Stages of the parsing of a spec file
+const (
+	// SpecStageTemplate is the rendering of the spec through the app config template
+	SpecStageTemplate = "template"
+	// SpecStageKustomize is the build of the kustomization of the app
+	SpecStageKustomize = "kustomize"
+	// SpecStageDecode is the decoding of the objects of the spec
+	SpecStageDecode = "decode"
+	// SpecStageSchema is the validation of the custom resources against the schemas of their CRDs
+	SpecStageSchema = "schema"
+)
+
+// ErrInvalidSpec is returned when a spec file of an app cannot be parsed
+type ErrInvalidSpec struct {
+	// Path of the spec file, or of the app spec directory
+	Path string
+	// Stage at which the parsing failed
+	Stage string
+	// Cause is the underlying error
+	Cause error
+}
+
+func (e *ErrInvalidSpec) Error() string {
+	return fmt.Sprintf("invalid spec [%s], %s failed: %v", e.Path, e.Stage, e.Cause)
+}
+
+func (e *ErrInvalidSpec) Unwrap() error {
+	return e.Cause
 }
 
 // K8s  The kubernetes structure
@@ -277,6 +314,7 @@ type K8s struct {
 	RunCSISnapshotAndRestoreManyTest bool
 	helmValuesConfigMapName          string
 	secureApps                       []string
+	specProfiles                     []string
 }
 
 // IsNodeReady  Check whether the cluster node is ready
@@ -303,6 +341,15 @@ func (k *K8s) String() string {
 	return SchedName
 }
 
+// NewSpecParser returns a driver which only parses the app specs, with the custom app config and the
+// spec profiles, without a cluster. Init must be called to use its other operations.
+func NewSpecParser(customConfig map[string]scheduler.AppConfig, specProfiles []string) *K8s {
+	return &K8s{
+		customConfig: customConfig,
+		specProfiles: specProfiles,
+	}
+}
+
 // Init Initialize the driver
 func (k *K8s) Init(schedOpts scheduler.InitOptions) error {
 	k.NodeDriverName = schedOpts.NodeDriverName
@@ -318,6 +365,7 @@ func (k *K8s) Init(schedOpts scheduler.InitOptions) error {
 	k.PureFADAPod = schedOpts.PureFADAPod
 	k.RunCSISnapshotAndRestoreManyTest = schedOpts.RunCSISnapshotAndRestoreManyTest
 	k.secureApps = schedOpts.SecureApps
+	k.specProfiles = schedOpts.SpecProfiles
 
 	nodes, err := k8sCore.GetNodes()
 	if err != nil {
@@ -330,6 +378,10 @@ func (k *K8s) Init(schedOpts scheduler.InitOptions) error {
 		}
 	}
 
+	if err = validateSpecProfiles(schedOpts.SpecDir, k.specProfiles); err != nil {
+		return err
+	}
+
 	k.SpecFactory, err = spec.NewFactory(schedOpts.SpecDir, schedOpts.VolDriverName, k)
 	if err != nil {
 		return err
@@ -461,11 +513,47 @@ func (k *K8s) RefreshNodeRegistry() error {
 	return nil
 }
 
-// ParseSpecs parses the application spec file
+// ParseSpecs parses the application spec file. When spec files are invalid, it goes on with the
+// other files and returns the specs which could be parsed along with an error joining an
+// ErrInvalidSpec for every problem found.
 func (k *K8s) ParseSpecs(specDir, storageProvisioner string) ([]interface{}, error) {
 	log.Debugf("ParseSpecs k.CustomConfig = %v", k.customConfig)
+	splitPath := strings.Split(specDir, "/")
+	appName := splitPath[len(splitPath)-1]
+	if appName == ProfilesDir {
+		// The shared profiles are applied to the other apps
+		return nil, nil
+	}
+
+	var customConfig scheduler.AppConfig
+	var ok bool
+
+	if customConfig, ok = k.customConfig[appName]; !ok {
+		customConfig = scheduler.AppConfig{}
+	} else {
+		log.Infof("customConfig[%v] = %v", appName, customConfig)
+	}
+
+	if isKustomizeApp(specDir) {
+		profiles := k.appProfiles(customConfig)
+		log.Debugf("Building specs in [%s] for [%s] with profiles %v", specDir, storageProvisioner, profiles)
+		rendered, err := renderKustomization(specDir, storageProvisioner, customConfig, profiles)
+		if err != nil {
+			return nil, err
+		}
+		specs, err := k.ParseSpecsFromYamlBuf(bytes.NewBuffer(rendered))
+		if err != nil {
+			return nil, &ErrInvalidSpec{Path: specDir, Stage: SpecStageDecode, Cause: err}
+		}
+		return specs, nil
+	}
+
 	fileList := make([]string, 0)
 	if err := filepath.Walk(specDir, func(path string, f os.FileInfo, err error) error {
+		if f != nil && f.IsDir() && path == filepath.Join(specDir, helmValuesDir) {
+			// values files of helm charts are not specs
+			return filepath.SkipDir
+		}
 		if f != nil && !f.IsDir() {
 			if isValidProvider(path, storageProvisioner) {
 				log.Debugf("	add filepath: %s", path)
@@ -480,9 +568,7 @@ func (k *K8s) ParseSpecs(specDir, storageProvisioner string) ([]interface{}, err
 
 	log.Debugf("fileList: %v", fileList)
 	var specs []interface{}
-
-	splitPath := strings.Split(specDir, "/")
-	appName := splitPath[len(splitPath)-1]
+	var invalid []error
 
 	for _, fileName := range fileList {
 		isHelmChart, err := k.IsAppHelmChartType(fileName)
@@ -492,58 +578,43 @@ func (k *K8s) ParseSpecs(specDir, storageProvisioner string) ([]interface{}, err
 
 		splitPath := strings.Split(fileName, "/")
 		if strings.HasPrefix(splitPath[len(splitPath)-1], "cr-") {
-			// TODO: process with templates
-			specObj := &CustomResourceObjectYAML{
-				Path: fileName,
-			}
-			specs = append(specs, specObj)
-			log.Warnf("custom res: %v", specObj) //TODO: remove
-		} else if !isHelmChart {
 			file, err := ioutil.ReadFile(fileName)
 			if err != nil {
 				return nil, err
 			}
 
-			var customConfig scheduler.AppConfig
-			var ok bool
-
-			if customConfig, ok = k.customConfig[appName]; !ok {
-				customConfig = scheduler.AppConfig{}
-			} else {
-				log.Infof("customConfig[%v] = %v", appName, customConfig)
-			}
-			var funcs = template.FuncMap{
-				"Iterate": func(count int) []int {
-					var i int
-					var Items []int
-					for i = 1; i <= (count); i++ {
-						Items = append(Items, i)
-					}
-					return Items
-				},
-				"array": func(arr []string) string {
-					string := "[\""
-					for i, val := range arr {
-						if i != 0 {
-							string += "\", \""
-						}
-						string += val
-					}
-					return string + "\"]"
-				},
+			processedFile, err := renderSpecTemplate(file, customConfig)
+			if err != nil {
+				invalid = append(invalid, &ErrInvalidSpec{Path: fileName, Stage: SpecStageTemplate, Cause: err})
+				continue
 			}
 
-			tmpl, err := template.New("customConfig").Funcs(funcs).Parse(string(file))
+			objects, err := parseCustomResources(processedFile)
 			if err != nil {
-				return nil, err
+				log.Warnf("Error decoding custom resources from %v: %v", fileName, err)
+				invalid = append(invalid, &ErrInvalidSpec{Path: fileName, Stage: SpecStageDecode, Cause: err})
+				continue
+			}
+			specObj := &CustomResourceObjectYAML{
+				Path:    fileName,
+				Content: processedFile,
+				Objects: objects,
 			}
-			var processedFile bytes.Buffer
-			err = tmpl.Execute(&processedFile, customConfig)
+			specObj.Name = specObj.customResourceNames()
+			specs = append(specs, specObj)
+		} else if !isHelmChart {
+			file, err := ioutil.ReadFile(fileName)
 			if err != nil {
 				return nil, err
 			}
 
-			reader := bufio.NewReader(&processedFile)
+			processedFile, err := renderSpecTemplate(file, customConfig)
+			if err != nil {
+				invalid = append(invalid, &ErrInvalidSpec{Path: fileName, Stage: SpecStageTemplate, Cause: err})
+				continue
+			}
+
+			reader := bufio.NewReader(bytes.NewReader(processedFile))
 			specReader := yaml.NewYAMLReader(reader)
 
 			for {
@@ -555,13 +626,15 @@ func (k *K8s) ParseSpecs(specDir, storageProvisioner string) ([]interface{}, err
 					obj, err := decodeSpec(specContents)
 					if err != nil {
 						log.Warnf("Error decoding spec from %v: %v", fileName, err)
-						return nil, err
+						invalid = append(invalid, &ErrInvalidSpec{Path: fileName, Stage: SpecStageDecode, Cause: err})
+						continue
 					}
 
 					specObj, err := validateSpec(obj)
 					if err != nil {
 						log.Warnf("Error parsing spec from %v: %v", fileName, err)
-						return nil, err
+						invalid = append(invalid, &ErrInvalidSpec{Path: fileName, Stage: SpecStageDecode, Cause: err})
+						continue
 					}
 					substituteImageWithInternalRegistry(specObj)
 					specs = append(specs, specObj)
@@ -572,9 +645,19 @@ func (k *K8s) ParseSpecs(specDir, storageProvisioner string) ([]interface{}, err
 			if err != nil {
 				return nil, err
 			}
+			if err = renderHelmValues(repoInfo.(*scheduler.HelmRepo), specDir, customConfig); err != nil {
+				invalid = append(invalid, &ErrInvalidSpec{Path: fileName, Stage: SpecStageTemplate, Cause: err})
+				continue
+			}
 			specs = append(specs, repoInfo)
 		}
 	}
+	for _, err := range validateCustomResources(specs) {
+		invalid = append(invalid, &ErrInvalidSpec{Path: specDir, Stage: SpecStageSchema, Cause: err})
+	}
+	if len(invalid) > 0 {
+		return specs, baseErrors.Join(invalid...)
+	}
 	return specs, nil
 }
 
@@ -901,7 +984,9 @@ func isCsiApp(options scheduler.ScheduleOptions, appName string) bool {
 }
 
 // Schedule Schedules the application
-func (k *K8s) Schedule(instanceID string, options scheduler.ScheduleOptions) ([]*scheduler.Context, error) {
+func (k *K8s) Schedule(instanceID string, options scheduler.ScheduleOptions) (_ []*scheduler.Context, err error) {
+	span := tracing.Start("k8s.Schedule", tracing.Driver(k.String()), tracing.Apps(options.AppKeys))
+	defer span.FinishWith(&err)
 	var apps []*spec.AppSpec
 	if len(options.AppKeys) > 0 {
 		for _, key := range options.AppKeys {
@@ -3033,7 +3118,9 @@ func (k *K8s) validatePodsTopology(podList *v1.PodList, labelValue string) error
 }
 
 // WaitForRunning   wait for running
-func (k *K8s) WaitForRunning(ctx *scheduler.Context, timeout, retryInterval time.Duration) error {
+func (k *K8s) WaitForRunning(ctx *scheduler.Context, timeout, retryInterval time.Duration) (err error) {
+	span := tracing.Start("k8s.WaitForRunning", tracing.Driver(k.String()), tracing.App(ctx.App.Key))
+	defer span.FinishWith(&err)
 	for _, specObj := range ctx.App.SpecList {
 		if obj, ok := specObj.(*appsapi.Deployment); ok {
 			if err := k8sApps.ValidateDeployment(obj, timeout, retryInterval); err != nil {
@@ -3264,7 +3351,7 @@ func (k *K8s) WaitForRunning(ctx *scheduler.Context, timeout, retryInterval time
 		return nil, false, nil
 	}
 
-	_, err := task.DoRetryWithTimeout(isPodTerminating, k8sDestroyTimeout, DefaultRetryInterval)
+	_, err = task.DoRetryWithTimeout(isPodTerminating, k8sDestroyTimeout, DefaultRetryInterval)
 	if err != nil {
 		log.Warnf("Timed out waiting for app %v's pods to terminate: %v", ctx.App.Key, err)
 		return err
@@ -3273,7 +3360,9 @@ func (k *K8s) WaitForRunning(ctx *scheduler.Context, timeout, retryInterval time
 }
 
 // Destroy destroy
-func (k *K8s) Destroy(ctx *scheduler.Context, opts map[string]bool) error {
+func (k *K8s) Destroy(ctx *scheduler.Context, opts map[string]bool) (err error) {
+	span := tracing.Start("k8s.Destroy", tracing.Driver(k.String()), tracing.App(ctx.App.Key))
+	defer span.FinishWith(&err)
 	var podList []corev1.Pod
 
 	// destruction of CustomResourceObjects must most likely be done *first*,
@@ -3303,7 +3392,7 @@ func (k *K8s) Destroy(ctx *scheduler.Context, opts map[string]bool) error {
 		}
 	}
 	// helm uninstall would delete objects automatically so skip destroy for those
-	err := k.RemoveAppSpecsByName(ctx, removeSpecs)
+	err = k.RemoveAppSpecsByName(ctx, removeSpecs)
 	if err != nil {
 		return err
 	}
@@ -3529,7 +3618,9 @@ func (k *K8s) getVolumeDirPath(podUID types.UID) string {
 }
 
 // WaitForDestroy wait for schedule context destroy
-func (k *K8s) WaitForDestroy(ctx *scheduler.Context, timeout time.Duration) error {
+func (k *K8s) WaitForDestroy(ctx *scheduler.Context, timeout time.Duration) (err error) {
+	span := tracing.Start("k8s.WaitForDestroy", tracing.Driver(k.String()), tracing.App(ctx.App.Key))
+	defer span.FinishWith(&err)
 	for _, specObj := range ctx.App.SpecList {
 		if obj, ok := specObj.(*appsapi.Deployment); ok {
 			if err := k8sApps.ValidateTerminatedDeployment(obj, timeout, DefaultRetryInterval); err != nil {
@@ -3893,7 +3984,9 @@ func (k *K8s) GetVolumeParameters(ctx *scheduler.Context) (map[string]map[string
 
 // ValidateVolumes Validates the volumes
 func (k *K8s) ValidateVolumes(ctx *scheduler.Context, timeout, retryInterval time.Duration,
-	options *scheduler.VolumeOptions) error {
+	options *scheduler.VolumeOptions) (err error) {
+	span := tracing.Start("k8s.ValidateVolumes", tracing.Driver(k.String()), tracing.App(ctx.App.Key))
+	defer span.FinishWith(&err)
 	for _, specObj := range ctx.App.SpecList {
 		if obj, ok := specObj.(*storageapi.StorageClass); ok {
 			if ctx.SkipClusterScopedObject {
@@ -4117,7 +4210,9 @@ func (k *K8s) isPVCShared(pvc *corev1.PersistentVolumeClaim) bool {
 }
 
 // DeleteVolumes  delete the volumes
-func (k *K8s) DeleteVolumes(ctx *scheduler.Context, options *scheduler.VolumeOptions) ([]*volume.Volume, error) {
+func (k *K8s) DeleteVolumes(ctx *scheduler.Context, options *scheduler.VolumeOptions) (_ []*volume.Volume, err error) {
+	span := tracing.Start("k8s.DeleteVolumes", tracing.Driver(k.String()), tracing.App(ctx.App.Key))
+	defer span.FinishWith(&err)
 	var vols []*volume.Volume
 
 	for _, specObj := range ctx.App.SpecList {
@@ -5746,18 +5841,28 @@ func (k *K8s) createCustomResourceObjects(
 ) (interface{}, error) {
 
 	if obj, ok := spec.(*CustomResourceObjectYAML); ok {
-		log.Warn("applying custom resources")
-		cryaml := obj.Path
-		if _, err := os.Stat(cryaml); baseErrors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("Cannot find yaml in path %s", cryaml)
+		if !obj.validated {
+			// the CRDs of the custom resources are not part of the app, validate against the ones of the cluster
+			if crds, err := k8sApiExtensions.ListCRDs(); err != nil {
+				log.Warnf("[%v] Skipping validation of custom resources [%s], failed to list CRDs: %v", app.Key, obj.Name, err)
+			} else {
+				var crdSpecs []interface{}
+				for i := range crds.Items {
+					crdSpecs = append(crdSpecs, &crds.Items[i])
+				}
+				if err = baseErrors.Join(obj.validate(crdSpecs)...); err != nil {
+					return nil, &scheduler.ErrFailedToScheduleApp{
+						App:   app,
+						Cause: err.Error(),
+					}
+				}
+			}
 		}
-		cmdArgs := []string{"apply", "-f", cryaml, "-n", ns.Name}
-		err := osutils.Kubectl(cmdArgs)
-		if err != nil {
-			return nil, fmt.Errorf("Error applying spec [%s], Error: %s", cryaml, err)
+		log.Infof("[%v] Applying custom resources [%s] from [%s]", app.Key, obj.Name, obj.Path)
+		if err := kubectlCustomResources("apply", obj, ns.Name); err != nil {
+			return nil, fmt.Errorf("Error applying spec [%s], Error: %s", obj.Path, err)
 		}
 		obj.Namespace = ns.Name
-		obj.Name = "placeholder" //TODO1
 		return obj, nil
 	}
 
@@ -5768,16 +5873,7 @@ func (k *K8s) createCustomResourceObjects(
 func (k *K8s) destroyCustomResourceObjects(spec interface{}, app *spec.AppSpec) error {
 
 	if obj, ok := spec.(*CustomResourceObjectYAML); ok {
-		cryaml := obj.Path
-		if _, err := os.Stat(cryaml); baseErrors.Is(err, os.ErrNotExist) {
-			return &scheduler.ErrFailedToDestroyApp{
-				App:   app,
-				Cause: fmt.Sprintf("Failed to destroy Custom Resource Object: %v. Err: Cannot find yaml in path: %v", obj.Name, cryaml),
-			}
-		}
-
-		cmdArgs := []string{"delete", "-f", cryaml, "-n", obj.Namespace}
-		err := osutils.Kubectl(cmdArgs)
+		err := kubectlCustomResources("delete", obj, obj.Namespace)
 		if err != nil {
 			return &scheduler.ErrFailedToDestroyApp{
 				App:   app,
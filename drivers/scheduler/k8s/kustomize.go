@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"text/template"
+
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/pkg/log"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+)
+
+// An app whose spec directory has a base/kustomization.yaml file is laid out for kustomize:
+//
+//	<app>/base/                            the base, common to all environments
+//	<app>/overlays/<provisioner>/          the overlay of a storage provisioner, used instead of the base if present
+//	<app>/profiles/<profile>/              the kustomize components of the profiles specific to the app
+//	profiles/<profile>/                    the kustomize components of the profiles shared by all apps
+//
+// All files are rendered through the app config template first, then the base or the overlay is built
+// by kustomize with the components of the selected profiles applied on top, in order. Strategic merge
+// and JSON 6902 patches are written in the overlays and the components as usual.
+const (
+	// kustomizationFile is the file of a kustomization or a component
+	kustomizationFile = "kustomization.yaml"
+	// baseDir is the directory of the kustomize base of an app
+	baseDir = "base"
+	// overlaysDir is the directory of the storage provisioner overlays of an app
+	overlaysDir = "overlays"
+	// ProfilesDir is the directory of the spec profiles, at the root of the spec directory or of an app
+	ProfilesDir = "profiles"
+)
+
+// specTemplateFuncs are the functions available to the spec templates
+var specTemplateFuncs = template.FuncMap{
+	"Iterate": func(count int) []int {
+		var i int
+		var Items []int
+		for i = 1; i <= (count); i++ {
+			Items = append(Items, i)
+		}
+		return Items
+	},
+	"array": func(arr []string) string {
+		string := "[\""
+		for i, val := range arr {
+			if i != 0 {
+				string += "\", \""
+			}
+			string += val
+		}
+		return string + "\"]"
+	},
+}
+
+// renderSpecTemplate executes the spec template with the app config
+func renderSpecTemplate(spec []byte, customConfig scheduler.AppConfig) ([]byte, error) {
+	tmpl, err := template.New("customConfig").Funcs(specTemplateFuncs).Parse(string(spec))
+	if err != nil {
+		return nil, err
+	}
+	var processedFile bytes.Buffer
+	if err = tmpl.Execute(&processedFile, customConfig); err != nil {
+		return nil, err
+	}
+	return processedFile.Bytes(), nil
+}
+
+// isKustomizeApp returns true if the spec directory of the app is laid out for kustomize
+func isKustomizeApp(specDir string) bool {
+	_, err := os.Stat(filepath.Join(specDir, baseDir, kustomizationFile))
+	return err == nil
+}
+
+func isDir(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// appProfiles returns the profiles an app is rendered with, the profiles of all apps followed by
+// the ones set in its custom config
+func (k *K8s) appProfiles(customConfig scheduler.AppConfig) []string {
+	var profiles []string
+	seen := make(map[string]bool)
+	for _, profile := range append(append([]string{}, k.specProfiles...), customConfig.Profiles...) {
+		if !seen[profile] {
+			seen[profile] = true
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// validateSpecProfiles checks that each profile is defined for all apps or for at least one app
+func validateSpecProfiles(specDir string, profiles []string) error {
+	for _, profile := range profiles {
+		if isDir(filepath.Join(specDir, ProfilesDir, profile)) {
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(specDir, "*", ProfilesDir, profile))
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("spec profile [%s] not found in [%s]", profile, specDir)
+		}
+	}
+	return nil
+}
+
+// renderKustomization renders the specs of an app laid out for kustomize for the storage
+// provisioner and the profiles. Profiles which are neither defined for the app nor for all apps
+// do not apply to the app and are skipped.
+func renderKustomization(specDir, storageProvisioner string, customConfig scheduler.AppConfig, profiles []string) ([]byte, error) {
+	fSys := filesys.MakeFsInMemory()
+	if err := copySpecTemplates(fSys, specDir, "/app", customConfig); err != nil {
+		return nil, err
+	}
+
+	root := types.Kustomization{
+		TypeMeta: types.TypeMeta{
+			APIVersion: types.KustomizationVersion,
+			Kind:       types.KustomizationKind,
+		},
+		Resources: []string{path.Join("../app", baseDir)},
+	}
+	if storageProvisioner != "" && isDir(filepath.Join(specDir, overlaysDir, storageProvisioner)) {
+		root.Resources = []string{path.Join("../app", overlaysDir, storageProvisioner)}
+	}
+	for _, profile := range profiles {
+		if isDir(filepath.Join(specDir, ProfilesDir, profile)) {
+			root.Components = append(root.Components, path.Join("../app", ProfilesDir, profile))
+			continue
+		}
+		sharedDir := filepath.Join(filepath.Dir(specDir), ProfilesDir, profile)
+		if !isDir(sharedDir) {
+			log.Debugf("Profile [%s] does not apply to the specs in [%s]", profile, specDir)
+			continue
+		}
+		if err := copySpecTemplates(fSys, sharedDir, path.Join("/", ProfilesDir, profile), customConfig); err != nil {
+			return nil, err
+		}
+		root.Components = append(root.Components, path.Join("..", ProfilesDir, profile))
+	}
+
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	if err = fSys.WriteFile(path.Join("/render", kustomizationFile), data); err != nil {
+		return nil, err
+	}
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, "/render")
+	if err != nil {
+		return nil, &ErrInvalidSpec{Path: specDir, Stage: SpecStageKustomize, Cause: fmt.Errorf("profiles %v: %v", profiles, err)}
+	}
+	return resMap.AsYaml()
+}
+
+// copySpecTemplates renders all files of a directory through the app config template into the
+// in-memory file system
+func copySpecTemplates(fSys filesys.FileSystem, srcDir, dstDir string, customConfig scheduler.AppConfig) error {
+	return filepath.Walk(srcDir, func(srcPath string, f os.FileInfo, err error) error {
+		if err != nil || f.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, srcPath)
+		if err != nil {
+			return err
+		}
+		spec, err := os.ReadFile(srcPath)
+		if err != nil {
+			return err
+		}
+		rendered, err := renderSpecTemplate(spec, customConfig)
+		if err != nil {
+			return &ErrInvalidSpec{Path: srcPath, Stage: SpecStageTemplate, Cause: err}
+		}
+		dstPath := path.Join(dstDir, filepath.ToSlash(rel))
+		if err = fSys.MkdirAll(path.Dir(dstPath)); err != nil {
+			return err
+		}
+		return fSys.WriteFile(dstPath, rendered)
+	})
+}
@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/stretchr/testify/require"
+	appsapi "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storageapi "k8s.io/api/storage/v1"
+)
+
+// specsByKind indexes the parsed specs by kind and name
+func specsByKind(specs []interface{}) map[string]interface{} {
+	index := make(map[string]interface{})
+	for _, spec := range specs {
+		switch obj := spec.(type) {
+		case *storageapi.StorageClass:
+			index["StorageClass/"+obj.Name] = obj
+		case *appsapi.Deployment:
+			index["Deployment/"+obj.Name] = obj
+		case *corev1.PersistentVolumeClaim:
+			index["PersistentVolumeClaim/"+obj.Name] = obj
+		case *corev1.ServiceAccount:
+			index["ServiceAccount/"+obj.Name] = obj
+		case *rbacv1.RoleBinding:
+			index["RoleBinding/"+obj.Name] = obj
+		default:
+			index["other"] = obj
+		}
+	}
+	return index
+}
+
+func TestParseKustomizeSpecs(t *testing.T) {
+	k := &K8s{}
+
+	pxd, err := k.ParseSpecs("specs/mysql", "pxd")
+	require.NoError(t, err)
+	index := specsByKind(pxd)
+	require.Len(t, pxd, 12)
+	require.Equal(t, "kubernetes.io/portworx-volume", index["StorageClass/mysql-sc"].(*storageapi.StorageClass).Provisioner)
+	require.Contains(t, index, "PersistentVolumeClaim/mysql-snap-clone")
+
+	aws, err := k.ParseSpecs("specs/mysql", "aws")
+	require.NoError(t, err)
+	index = specsByKind(aws)
+	require.Len(t, aws, 10)
+	require.Equal(t, "kubernetes.io/aws-ebs", index["StorageClass/mysql-sc"].(*storageapi.StorageClass).Provisioner)
+	require.NotContains(t, index, "PersistentVolumeClaim/mysql-snap-clone")
+
+	// without an overlay, only the base is rendered
+	base, err := k.ParseSpecs("specs/mysql", "gce")
+	require.NoError(t, err)
+	require.Len(t, base, 7)
+
+	shared, err := k.ParseSpecs("specs/profiles", "pxd")
+	require.NoError(t, err)
+	require.Empty(t, shared)
+}
+
+func TestParseKustomizeSpecsWithProfiles(t *testing.T) {
+	k := &K8s{
+		specProfiles: []string{"restricted-psa", "ocp", "unknown"},
+		customConfig: map[string]scheduler.AppConfig{
+			"mysql": {Profiles: []string{"pure-fada"}, PureFaPodName: "fada-pod"},
+		},
+	}
+
+	specs, err := k.ParseSpecs("specs/mysql", "pxd")
+	require.NoError(t, err)
+	index := specsByKind(specs)
+	require.Len(t, specs, 14)
+
+	sc := index["StorageClass/mysql-sc"].(*storageapi.StorageClass)
+	require.Equal(t, "pxd.portworx.com", sc.Provisioner)
+	require.Equal(t, "pure_block", sc.Parameters["backend"])
+	require.Equal(t, "fada-pod", sc.Parameters["pure_fa_pod_name"])
+
+	mysql := index["Deployment/mysql"].(*appsapi.Deployment)
+	require.Equal(t, "mysql", mysql.Spec.Template.Spec.ServiceAccountName)
+	require.True(t, *mysql.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
+	require.False(t, *mysql.Spec.Template.Spec.Containers[0].SecurityContext.AllowPrivilegeEscalation)
+	require.Equal(t, "mysql", index["RoleBinding/mysql-anyuid"].(*rbacv1.RoleBinding).Subjects[0].Name)
+
+	slap := index["Deployment/mysqlslap"].(*appsapi.Deployment)
+	require.Empty(t, slap.Spec.Template.Spec.ServiceAccountName)
+	require.True(t, *slap.Spec.Template.Spec.SecurityContext.RunAsNonRoot)
+
+	require.NoError(t, validateSpecProfiles("specs", []string{"restricted-psa", "pure-fada"}))
+	require.EqualError(t, validateSpecProfiles("specs", []string{"unknown"}), "spec profile [unknown] not found in [specs]")
+}
@@ -0,0 +1,159 @@
+// Command lint lints the app spec library offline, for every storage provisioner and profile.
+// It exits with status 1 if issues are found.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/drivers/scheduler/k8s/speclint"
+	"github.com/portworx/torpedo/drivers/volume"
+	torpedolog "github.com/portworx/torpedo/pkg/log"
+	yaml "gopkg.in/yaml.v2"
+
+	// import the volume drivers so that the specs of other provisioners are skipped as when running tests
+	_ "github.com/portworx/torpedo/drivers/volume/aws"
+	_ "github.com/portworx/torpedo/drivers/volume/azure"
+	_ "github.com/portworx/torpedo/drivers/volume/fakepx"
+	_ "github.com/portworx/torpedo/drivers/volume/gce"
+	_ "github.com/portworx/torpedo/drivers/volume/generic_csi"
+	_ "github.com/portworx/torpedo/drivers/volume/ibm"
+	_ "github.com/portworx/torpedo/drivers/volume/ocp"
+	_ "github.com/portworx/torpedo/drivers/volume/portworx"
+	_ "github.com/portworx/torpedo/drivers/volume/pso"
+)
+
+var (
+	// defaultStorageClasses are created by stork, the storage drivers, the cloud providers or the tests,
+	// apps may use them without defining them
+	defaultStorageClasses = []string{
+		"stork-snapshot-sc",
+		"px-csi-db",
+		"portworx-proxy-volume-volume",
+		"ocs-storagecluster-ceph-rbd",
+		"ocs-storagecluster-cephfs",
+		"managed-csi",
+		"standard-rwo",
+		"ibmc-vpc-block-10iops-tier",
+	}
+	// defaultStorageProvisioners are the provisioners of the storage classes the tests run with
+	defaultStorageProvisioners = []string{
+		"kubernetes.io/portworx-volume",
+		"pxd.portworx.com",
+		"pure-csi",
+		"stork-snapshot",
+		"kubernetes.io/aws-ebs",
+		"ebs.csi.aws.com",
+		"kubernetes.io/azure-disk",
+		"kubernetes.io/azure-file",
+		"disk.csi.azure.com",
+		"file.csi.azure.com",
+		"kubernetes.io/gce-pd",
+		"pd.csi.storage.gke.io",
+		"vpc.block.csi.ibm.io",
+		"openshift-storage.rbd.csi.ceph.com",
+		"openshift-storage.cephfs.csi.ceph.com",
+		"openshift-storage.ceph.rook.io/bucket",
+	}
+)
+
+func main() {
+	specDir := flag.String("spec-dir", "drivers/scheduler/k8s/specs", "root of the app spec library")
+	provisioners := flag.String("provisioners", "", "comma-separated list of storage provisioners to load the apps for, all volume drivers if not set")
+	specProfiles := flag.String("spec-profiles", "", "comma-separated list of spec profiles, the apps are loaded with each of them in addition to none")
+	customConfigs := flag.String("custom-configs", "", "comma-separated list of custom app config files, the apps are loaded with each of them in addition to none")
+	storageClasses := flag.String("storage-classes", "", "comma-separated list of storage classes PVCs may reference in addition to the well-known ones")
+	storageProvisioners := flag.String("storage-provisioners", strings.Join(defaultStorageProvisioners, ","), "comma-separated list of known provisioners of storage classes, empty to skip the check")
+	checkImages := flag.Bool("check-images", false, "check that the images are available in their registry with docker manifest inspect")
+	jsonOutput := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	// the spec parser logs every file it loads, keep stdout for the report
+	torpedolog.GetLogInstance().Out = os.Stderr
+	torpedolog.SetLoglevel("warn")
+
+	opts := speclint.Options{
+		SpecDir:             *specDir,
+		Provisioners:        splitList(*provisioners),
+		StorageClasses:      append(defaultStorageClasses, splitList(*storageClasses)...),
+		StorageProvisioners: splitList(*storageProvisioners),
+		Profiles:            []speclint.Profile{speclint.DefaultProfile},
+	}
+	if len(opts.Provisioners) == 0 {
+		opts.Provisioners = volume.GetVolumeDrivers()
+		sort.Strings(opts.Provisioners)
+	}
+	for _, specProfile := range splitList(*specProfiles) {
+		opts.Profiles = append(opts.Profiles, speclint.Profile{Name: specProfile, SpecProfiles: []string{specProfile}})
+	}
+	for _, path := range splitList(*customConfigs) {
+		customAppConfig, err := readCustomConfig(path)
+		if err != nil {
+			log.Fatalf("Failed to read custom app config: %v", err)
+		}
+		opts.Profiles = append(opts.Profiles, speclint.Profile{Name: filepath.Base(path), CustomAppConfig: customAppConfig})
+	}
+	if *checkImages {
+		opts.CheckImage = inspectImage
+	}
+
+	report, err := speclint.Lint(opts)
+	if err != nil {
+		log.Fatalf("Failed to lint specs in [%s]: %v", *specDir, err)
+	}
+	if *jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to marshal report: %v", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, issue := range report.Issues {
+			fmt.Println(issue)
+		}
+		fmt.Printf("%d apps linted for %d provisioners and %d profiles, %d issues found\n",
+			report.Apps, len(opts.Provisioners), len(opts.Profiles), len(report.Issues))
+	}
+	if len(report.Issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readCustomConfig reads a custom app config file, as passed to the tests with --custom-config
+func readCustomConfig(path string) (map[string]scheduler.AppConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	customAppConfig := make(map[string]scheduler.AppConfig)
+	if err = yaml.Unmarshal(data, &customAppConfig); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal [%s]: %v", path, err)
+	}
+	return customAppConfig, nil
+}
+
+func inspectImage(image string) error {
+	out, err := exec.Command("docker", "manifest", "inspect", image).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+// Package speclint lints the app spec library offline. Every app is loaded through the spec factory,
+// the same way the scheduler loads it, for every storage provisioner and profile, and its objects
+// are checked for problems which would otherwise only show when the app is scheduled.
+package speclint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/drivers/scheduler/k8s"
+	"github.com/portworx/torpedo/drivers/scheduler/spec"
+	appsapi "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	storageapi "k8s.io/api/storage/v1"
+)
+
+// Checks reported by the linter
+const (
+	// CheckTemplate reports specs which fail to render through the app config template
+	CheckTemplate = "template"
+	// CheckKustomize reports apps whose kustomization fails to build
+	CheckKustomize = "kustomize"
+	// CheckDecode reports objects which cannot be decoded or are not supported by the scheduler
+	CheckDecode = "decode"
+	// CheckSchema reports custom resources which do not match the schema of their CRD
+	CheckSchema = "schema"
+	// CheckParse reports other failures to load an app
+	CheckParse = "parse"
+	// CheckStorageClass reports PVCs referencing a storage class which is neither in the app nor known
+	CheckStorageClass = "storage-class"
+	// CheckProvisioner reports storage classes whose provisioner is not known
+	CheckProvisioner = "provisioner"
+	// CheckImage reports containers without an image or whose image is not available
+	CheckImage = "image"
+)
+
+// storageClassAnnotation is the legacy annotation setting the storage class of a PVC
+const storageClassAnnotation = "volume.beta.kubernetes.io/storage-class"
+
+// Profile is a combination of spec profiles and custom app config the apps are loaded with
+type Profile struct {
+	// Name of the profile in the report
+	Name string
+	// SpecProfiles are the environment profiles the specs are rendered with
+	SpecProfiles []string
+	// CustomAppConfig is the custom config of the apps
+	CustomAppConfig map[string]scheduler.AppConfig
+}
+
+// DefaultProfile loads the apps without spec profile nor custom config
+var DefaultProfile = Profile{Name: "default"}
+
+// Options of a lint run
+type Options struct {
+	// SpecDir is the root of the app spec library
+	SpecDir string
+	// Provisioners are the storage provisioners the apps are loaded for
+	Provisioners []string
+	// Profiles the apps are loaded with, DefaultProfile if empty
+	Profiles []Profile
+	// StorageClasses are the storage classes PVCs may reference without the app defining them,
+	// like the ones created by stork or by the storage driver
+	StorageClasses []string
+	// StorageProvisioners are the known provisioners of storage classes, provisioners are not checked if empty
+	StorageProvisioners []string
+	// CheckImage checks that an image is available, images are only checked to be set if nil
+	CheckImage func(image string) error
+}
+
+// Issue is a problem found in an app
+type Issue struct {
+	// App is the name of the app
+	App string `json:"app"`
+	// Check which found the issue
+	Check string `json:"check"`
+	// Object is the kind and name of the object with the issue, or the path of the spec
+	Object string `json:"object,omitempty"`
+	// Message describes the issue
+	Message string `json:"message"`
+	// Variants are the provisioner/profile combinations in which the issue was found
+	Variants []string `json:"variants"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s %v", i.App, i.Check, i.Object, i.Message, i.Variants)
+}
+
+// Report of a lint run
+type Report struct {
+	// Apps is the number of apps linted
+	Apps int `json:"apps"`
+	// Issues found, sorted by app, check and object
+	Issues []*Issue `json:"issues"`
+}
+
+// recordingParser parses the specs of each app and records the result, so that the factory loads all apps
+// even when some of them fail. The specs which could be parsed are recorded along with the errors.
+type recordingParser struct {
+	parser  spec.Parser
+	specs   map[string][]interface{}
+	errors  map[string]error
+	visited []string
+}
+
+func (p *recordingParser) ParseSpecs(specDir, storageProvisioner string) ([]interface{}, error) {
+	app := filepath.Base(specDir)
+	p.visited = append(p.visited, app)
+	specs, err := p.parser.ParseSpecs(specDir, storageProvisioner)
+	p.specs[app] = specs
+	if err != nil {
+		p.errors[app] = err
+		return nil, nil
+	}
+	return specs, nil
+}
+
+// Lint loads all apps of the spec library for every provisioner and profile and checks them
+func Lint(opts Options) (*Report, error) {
+	profiles := opts.Profiles
+	if len(profiles) == 0 {
+		profiles = []Profile{DefaultProfile}
+	}
+	if len(opts.Provisioners) == 0 {
+		return nil, fmt.Errorf("no storage provisioner to load the apps for")
+	}
+
+	l := &linter{
+		opts:   opts,
+		issues: make(map[string]*Issue),
+		images: make(map[string]error),
+		apps:   make(map[string]bool),
+	}
+	for _, provisioner := range opts.Provisioners {
+		for _, profile := range profiles {
+			if err := l.lintVariant(provisioner, profile); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	report := &Report{Apps: len(l.apps)}
+	for _, issue := range l.issues {
+		report.Issues = append(report.Issues, issue)
+	}
+	sort.Slice(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.App != b.App {
+			return a.App < b.App
+		}
+		if a.Check != b.Check {
+			return a.Check < b.Check
+		}
+		if a.Object != b.Object {
+			return a.Object < b.Object
+		}
+		return a.Message < b.Message
+	})
+	return report, nil
+}
+
+type linter struct {
+	opts    Options
+	issues  map[string]*Issue
+	images  map[string]error
+	apps    map[string]bool
+	variant string
+}
+
+func (l *linter) lintVariant(provisioner string, profile Profile) error {
+	l.variant = provisioner + "/" + profile.Name
+	parser := &recordingParser{
+		parser: k8s.NewSpecParser(profile.CustomAppConfig, profile.SpecProfiles),
+		specs:  make(map[string][]interface{}),
+		errors: make(map[string]error),
+	}
+	if _, err := spec.NewFactory(l.opts.SpecDir, provisioner, parser); err != nil && len(parser.errors) == 0 {
+		return err
+	}
+
+	for _, app := range parser.visited {
+		if err, ok := parser.errors[app]; ok {
+			l.apps[app] = true
+			l.reportParseError(app, err)
+		}
+		if specs := parser.specs[app]; len(specs) > 0 {
+			l.apps[app] = true
+			l.lintApp(app, specs, l.targets(app, provisioner))
+		}
+	}
+	return nil
+}
+
+// targets returns if the app targets the provisioner, that is it has specs for it or no provisioner specific
+// specs at all. Apps are loaded for any provisioner, but the specs of the others may lack their storage classes.
+func (l *linter) targets(app, provisioner string) bool {
+	hasProvisionerSpecs := false
+	for _, p := range l.opts.Provisioners {
+		// kustomize apps have their provisioner specific specs in overlays
+		for _, dir := range []string{filepath.Join(l.opts.SpecDir, app, p), filepath.Join(l.opts.SpecDir, app, "overlays", p)} {
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				if p == provisioner {
+					return true
+				}
+				hasProvisionerSpecs = true
+			}
+		}
+	}
+	return !hasProvisionerSpecs
+}
+
+func (l *linter) report(app, check, object, message string) {
+	key := strings.Join([]string{app, check, object, message}, "\x00")
+	issue, ok := l.issues[key]
+	if !ok {
+		issue = &Issue{App: app, Check: check, Object: object, Message: message}
+		l.issues[key] = issue
+	}
+	issue.Variants = append(issue.Variants, l.variant)
+}
+
+// reportParseError reports every problem of the error, the parser joining an error per invalid spec
+func (l *linter) reportParseError(app string, err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			l.reportParseError(app, err)
+		}
+		return
+	}
+	var invalidSpec *k8s.ErrInvalidSpec
+	if !errors.As(err, &invalidSpec) {
+		l.report(app, CheckParse, "", err.Error())
+		return
+	}
+	check := CheckParse
+	switch invalidSpec.Stage {
+	case k8s.SpecStageTemplate:
+		check = CheckTemplate
+	case k8s.SpecStageKustomize:
+		check = CheckKustomize
+	case k8s.SpecStageDecode:
+		check = CheckDecode
+	case k8s.SpecStageSchema:
+		check = CheckSchema
+	}
+	object := invalidSpec.Path
+	if rel, err := filepath.Rel(l.opts.SpecDir, invalidSpec.Path); err == nil {
+		object = rel
+	}
+	l.report(app, check, object, invalidSpec.Cause.Error())
+}
+
+// lintApp checks the objects of the app, storage class references are only checked if the app targets the provisioner
+func (l *linter) lintApp(app string, specs []interface{}, checkStorageClasses bool) {
+	storageClasses := make(map[string]bool)
+	for _, name := range l.opts.StorageClasses {
+		storageClasses[name] = true
+	}
+	for _, obj := range specs {
+		if sc, ok := obj.(*storageapi.StorageClass); ok {
+			storageClasses[sc.Name] = true
+			l.lintProvisioner(app, sc)
+		}
+	}
+
+	for _, obj := range specs {
+		switch obj := obj.(type) {
+		case *corev1.PersistentVolumeClaim:
+			if !checkStorageClasses {
+				break
+			}
+			l.lintClaim(app, "PersistentVolumeClaim/"+obj.Name, obj, storageClasses)
+		case *appsapi.StatefulSet:
+			if !checkStorageClasses {
+				break
+			}
+			for i := range obj.Spec.VolumeClaimTemplates {
+				l.lintClaim(app, "StatefulSet/"+obj.Name, &obj.Spec.VolumeClaimTemplates[i], storageClasses)
+			}
+		}
+		if kind, name, podSpec := podSpecOf(obj); podSpec != nil {
+			l.lintImages(app, kind+"/"+name, podSpec)
+		}
+	}
+}
+
+func (l *linter) lintClaim(app, object string, pvc *corev1.PersistentVolumeClaim, storageClasses map[string]bool) {
+	name := pvc.Annotations[storageClassAnnotation]
+	if pvc.Spec.StorageClassName != nil {
+		name = *pvc.Spec.StorageClassName
+	}
+	if name != "" && !storageClasses[name] {
+		l.report(app, CheckStorageClass, object, fmt.Sprintf("claim [%s] references storage class [%s] which is not defined", pvc.Name, name))
+	}
+}
+
+func (l *linter) lintProvisioner(app string, sc *storageapi.StorageClass) {
+	if len(l.opts.StorageProvisioners) == 0 {
+		return
+	}
+	for _, provisioner := range l.opts.StorageProvisioners {
+		if sc.Provisioner == provisioner {
+			return
+		}
+	}
+	l.report(app, CheckProvisioner, "StorageClass/"+sc.Name, fmt.Sprintf("provisioner [%s] is not known", sc.Provisioner))
+}
+
+func (l *linter) lintImages(app, object string, podSpec *corev1.PodSpec) {
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, container := range containers {
+		if strings.TrimSpace(container.Image) == "" {
+			l.report(app, CheckImage, object, fmt.Sprintf("container [%s] has no image", container.Name))
+			continue
+		}
+		if l.opts.CheckImage == nil {
+			continue
+		}
+		err, checked := l.images[container.Image]
+		if !checked {
+			err = l.opts.CheckImage(container.Image)
+			l.images[container.Image] = err
+		}
+		if err != nil {
+			l.report(app, CheckImage, object, fmt.Sprintf("image [%s] of container [%s] is not available: %v", container.Image, container.Name, err))
+		}
+	}
+}
+
+// podSpecOf returns the pod spec of the objects running pods
+func podSpecOf(obj interface{}) (string, string, *corev1.PodSpec) {
+	switch obj := obj.(type) {
+	case *corev1.Pod:
+		return "Pod", obj.Name, &obj.Spec
+	case *appsapi.Deployment:
+		return "Deployment", obj.Name, &obj.Spec.Template.Spec
+	case *appsapi.StatefulSet:
+		return "StatefulSet", obj.Name, &obj.Spec.Template.Spec
+	case *appsapi.DaemonSet:
+		return "DaemonSet", obj.Name, &obj.Spec.Template.Spec
+	case *appsapi.ReplicaSet:
+		return "ReplicaSet", obj.Name, &obj.Spec.Template.Spec
+	case *batchv1.Job:
+		return "Job", obj.Name, &obj.Spec.Template.Spec
+	case *batchv1.CronJob:
+		return "CronJob", obj.Name, &obj.Spec.JobTemplate.Spec.Template.Spec
+	case *batchv1beta1.CronJob:
+		return "CronJob", obj.Name, &obj.Spec.JobTemplate.Spec.Template.Spec
+	}
+	return "", "", nil
+}
@@ -0,0 +1,128 @@
+package speclint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	// register the volume drivers, the specs of the other provisioners are skipped
+	_ "github.com/portworx/torpedo/drivers/volume/aws"
+	_ "github.com/portworx/torpedo/drivers/volume/gce"
+)
+
+const (
+	testStorageClass = `apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: test-sc
+provisioner: pxd.portworx.com
+`
+	testClaim = `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: test-pvc
+spec:
+  storageClassName: %s
+  accessModes: [ReadWriteOnce]
+  resources:
+    requests:
+      storage: 1Gi
+`
+	testDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test
+spec:
+  selector:
+    matchLabels:
+      app: test
+  template:
+    metadata:
+      labels:
+        app: test
+    spec:
+      containers:
+      - name: test
+        image: %s
+`
+)
+
+func writeSpec(t *testing.T, dir, app, file, content string, args ...interface{}) {
+	if len(args) > 0 {
+		content = fmt.Sprintf(content, args...)
+	}
+	path := filepath.Join(dir, app, file)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "good", "storage.yaml", testStorageClass+"---\n"+testClaim, "test-sc")
+	writeSpec(t, dir, "good", "app.yaml", testDeployment, "nginx:latest")
+	writeSpec(t, dir, "bad-template", "app.yaml", "{{ if .Replicas }}")
+	writeSpec(t, dir, "dangling", "storage.yaml", testClaim, "missing-sc")
+	writeSpec(t, dir, "dangling", "app.yaml", testDeployment, "nginx:latest")
+	writeSpec(t, dir, "no-image", "app.yaml", testDeployment, `""`)
+	// all problems of an app are reported, the specs which could be parsed being checked too
+	writeSpec(t, dir, "many-problems", "a.yaml", "{{ if .Replicas }}")
+	writeSpec(t, dir, "many-problems", "b.yaml", "{{ end }}")
+	writeSpec(t, dir, "many-problems", "app.yaml", testDeployment, `""`)
+	writeSpec(t, dir, "unavailable-image", "app.yaml", testDeployment, "nginx:missing")
+
+	report, err := Lint(Options{
+		SpecDir:      dir,
+		Provisioners: []string{"aws"},
+		Profiles:     []Profile{DefaultProfile, {Name: "replicas"}},
+		CheckImage: func(image string) error {
+			if image == "nginx:missing" {
+				return errors.New("manifest unknown")
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 6, report.Apps)
+
+	var checks []string
+	for _, issue := range report.Issues {
+		checks = append(checks, issue.App+"/"+issue.Check+"/"+issue.Object)
+		require.Equal(t, []string{"aws/default", "aws/replicas"}, issue.Variants)
+	}
+	require.Equal(t, []string{
+		"bad-template/template/bad-template/app.yaml",
+		"dangling/storage-class/PersistentVolumeClaim/test-pvc",
+		"many-problems/image/Deployment/test",
+		"many-problems/template/many-problems/a.yaml",
+		"many-problems/template/many-problems/b.yaml",
+		"no-image/image/Deployment/test",
+		"unavailable-image/image/Deployment/test",
+	}, checks)
+}
+
+func TestLintProvisioners(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "app", "storage.yaml", testClaim, "test-sc")
+	writeSpec(t, dir, "app", "aws/sc.yaml", `apiVersion: storage.k8s.io/v1
+kind: StorageClass
+metadata:
+  name: test-sc
+provisioner: example.com/unknown
+`)
+
+	// the app does not target gce, its claim is not checked there
+	report, err := Lint(Options{
+		SpecDir:             dir,
+		Provisioners:        []string{"aws", "gce"},
+		StorageProvisioners: []string{"ebs.csi.aws.com"},
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	require.Equal(t, CheckProvisioner, report.Issues[0].Check)
+	require.Equal(t, "StorageClass/test-sc", report.Issues[0].Object)
+	require.Equal(t, []string{"aws/default"}, report.Issues[0].Variants)
+}
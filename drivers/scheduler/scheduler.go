@@ -89,6 +89,7 @@ type AppConfig struct {
 	PureFaPodName               string   `yaml:"pure_fa_pod_name"`
 	FsType                      string   `yaml:"csi.storage.k8s.io/fstype"`
 	CreateOptions               string   `yaml:"createoptions"`
+	Profiles                    []string `yaml:"profiles"`
 }
 
 // InitOptions initialization options
@@ -132,6 +133,8 @@ type InitOptions struct {
 	AnthosInstancePath string
 	// UpgradeHops needed for a scheduler like Anthos to decide whether to upgrade admin cluster
 	UpgradeHops string
+	// SpecProfiles environment profiles the app specs are rendered with, in order
+	SpecProfiles []string
 }
 
 // ScheduleOptions are options that callers to pass to influence the apps that get schduled
@@ -478,6 +481,16 @@ type Driver interface {
 
 	// GetPXCloudDriveConfigMap gets the PX-Cloud drive config map
 	GetPXCloudDriveConfigMap(cluster *operatorcorev1.StorageCluster) (map[string]node.DriveSet, error)
+
+	// UpgradeChart upgrades the helm release of the chart and returns its manifest
+	UpgradeChart(repoInfo *HelmRepo) (string, error)
+
+	// RollbackChart rolls the helm release of the chart back to the revision, or to the previous one if 0,
+	// and returns its manifest
+	RollbackChart(repoInfo *HelmRepo, revision int) (string, error)
+
+	// HelmHistory returns the revisions of the helm release of the chart, oldest first
+	HelmHistory(repoInfo *HelmRepo) ([]HelmRevision, error)
 }
 
 var (
@@ -521,6 +534,25 @@ type HelmRepo struct {
 	Namespace   string
 	Version     string
 	Values      string
+	// ValuesFiles are layered in order on top of the chart values, their paths are relative to the app spec dir
+	ValuesFiles []string `yaml:"valuesfiles"`
+	// Overrides are values layered on top of the values files
+	Overrides string `yaml:"overrides"`
+	// RenderedValues are the values files and overrides rendered with the app config when the spec is parsed
+	RenderedValues map[string]interface{} `yaml:"-"`
+	// ReuseValues layers the values of the spec on the values of the deployed
+	// release on upgrade, as helm upgrade --reuse-values
+	ReuseValues bool `yaml:"reusevalues"`
+}
+
+// HelmRevision is a revision of a helm release
+type HelmRevision struct {
+	Revision     int
+	Status       string
+	ChartVersion string
+	AppVersion   string
+	Updated      time.Time
+	Description  string
 }
 
 // Register registers the given scheduler driver
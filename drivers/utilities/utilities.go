@@ -1,6 +1,7 @@
 package utilities
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/portworx/sched-ops/k8s/kubevirt"
@@ -9,6 +10,7 @@ import (
 	kubevirtv1 "kubevirt.io/api/core/v1"
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/portworx/sched-ops/k8s/core"
@@ -41,6 +43,7 @@ const (
 	defaultCmdTimeout               = 20 * time.Second
 	defaultCmdRetryInterval         = 5 * time.Second
 	defaultKubeconfigMapForKubevirt = "kubevirt-creds"
+	defaultCQLReplicationFactor     = 3
 )
 
 // RandomString generates a random lowercase string of length characters.
@@ -194,6 +197,11 @@ func ExtractConnectionInfo(ctx *scheduler.Context, context context.Context) (App
 		}
 		if obj, ok := specObj.(*corev1.Service); ok {
 			appInfo.Namespace = obj.Namespace
+			// Only the annotated service is used to connect, apps like kafka-stack have more than one service
+			if svcAnnotationValue, ok := obj.Annotations[svcAnnotationKey]; !ok || svcAnnotationValue != "true" {
+				continue
+			}
+			appInfo.StartDataSupport = true
 			// TODO: This needs to be fetched from spec once CloneAppContextAndTransformWithMappings is fixed
 			svc, err := core.Instance().GetService(obj.Name, obj.Namespace)
 			if err != nil {
@@ -206,15 +214,6 @@ func ExtractConnectionInfo(ctx *scheduler.Context, context context.Context) (App
 			}
 			appInfo.Hostname = hostname
 			appInfo.NodePort = int(nodePort)
-			if svcAnnotationValue, ok := obj.Annotations[svcAnnotationKey]; ok {
-				appInfo.StartDataSupport = svcAnnotationValue == "true"
-				if !appInfo.StartDataSupport {
-					continue
-				}
-			} else {
-				appInfo.StartDataSupport = false
-				continue
-			}
 			if userAnnotationValue, ok := obj.Annotations[userAnnotationKey]; ok {
 				appInfo.User = userAnnotationValue
 			} else {
@@ -307,3 +306,177 @@ func syncData(namespace string) {
 	}
 
 }
+
+// GenerateRandomMongoCommands generates pairs of insert, update, find and delete commands for a mongodb collection.
+// The commands are database commands in extended JSON.
+func GenerateRandomMongoCommands(count int) map[string][]string {
+	var randomMongoCommands = make(map[string][]string)
+	var collectionName = "mongo_validation_" + RandomString(5)
+
+	for counter := 0; counter < count; counter++ {
+		currentCounter := strconv.Itoa(counter)
+		randomValue := "Value-" + RandomString(10)
+		updatedRandomValue := "Value-Updated-" + RandomString(10)
+		randomMongoCommands["insert"] = append(randomMongoCommands["insert"], fmt.Sprintf(`{"insert": "%s", "documents": [{"_id": "%s", "value": "%s"}]}`, collectionName, currentCounter, randomValue))
+		randomMongoCommands["select"] = append(randomMongoCommands["select"], fmt.Sprintf(`{"find": "%s", "filter": {"_id": "%s"}, "limit": 1}`, collectionName, currentCounter))
+		randomMongoCommands["update"] = append(randomMongoCommands["update"], fmt.Sprintf(`{"update": "%s", "updates": [{"q": {"_id": "%s"}, "u": {"$set": {"value": "%s"}}}]}`, collectionName, currentCounter, updatedRandomValue))
+		randomMongoCommands["delete"] = append(randomMongoCommands["delete"], fmt.Sprintf(`{"delete": "%s", "deletes": [{"q": {"_id": "%s"}, "limit": 1}]}`, collectionName, currentCounter))
+	}
+
+	return randomMongoCommands
+}
+
+// GenerateMongoCommandPair generates pairs of insert and find commands for a mongodb collection
+func GenerateMongoCommandPair(collectionName string) map[string][]string {
+	var mongoCommandMap = make(map[string][]string)
+	randomKey := "key-" + RandomString(10)
+	randomValue := "value-" + RandomString(10)
+
+	mongoCommandMap["insert"] = append(mongoCommandMap["insert"], fmt.Sprintf(`{"insert": "%s", "documents": [{"_id": "%s", "value": "%s"}]}`, collectionName, randomKey, randomValue))
+	mongoCommandMap["select"] = append(mongoCommandMap["select"], fmt.Sprintf(`{"find": "%s", "filter": {"_id": "%s"}, "limit": 1}`, collectionName, randomKey))
+
+	return mongoCommandMap
+}
+
+// GenerateRandomCQLCommands generates pairs of INSERT, UPDATE, SELECT and DELETE queries for a cassandra keyspace
+func GenerateRandomCQLCommands(count int, keyspace string) map[string][]string {
+	var randomCQLCommands = make(map[string][]string)
+	var tableName = fmt.Sprintf("%s.cassandra_validation_%s", keyspace, RandomString(5))
+
+	randomCQLCommands["insert"] = []string{
+		CQLCreateKeyspaceCommand(keyspace),
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (key text PRIMARY KEY, value text)", tableName),
+	}
+	for counter := 0; counter < count; counter++ {
+		currentCounter := strconv.Itoa(counter)
+		randomValue := "Value-" + RandomString(10)
+		updatedRandomValue := "Value-Updated-" + RandomString(10)
+		randomCQLCommands["insert"] = append(randomCQLCommands["insert"], fmt.Sprintf("INSERT INTO %s (key, value) VALUES ('%s', '%s')", tableName, currentCounter, randomValue))
+		randomCQLCommands["select"] = append(randomCQLCommands["select"], fmt.Sprintf("SELECT key, value FROM %s WHERE key='%s'", tableName, currentCounter))
+		randomCQLCommands["update"] = append(randomCQLCommands["update"], fmt.Sprintf("UPDATE %s SET value='%s' WHERE key='%s'", tableName, updatedRandomValue, currentCounter))
+		randomCQLCommands["delete"] = append(randomCQLCommands["delete"], fmt.Sprintf("DELETE FROM %s WHERE key='%s'", tableName, currentCounter))
+	}
+
+	return randomCQLCommands
+}
+
+// GenerateCQLCommandPair generates pairs of INSERT and SELECT queries for a cassandra table
+func GenerateCQLCommandPair(tableName string) map[string][]string {
+	var cqlCommandMap = make(map[string][]string)
+	randomKey := "key-" + RandomString(10)
+	randomValue := "value-" + RandomString(10)
+
+	cqlCommandMap["insert"] = append(cqlCommandMap["insert"], fmt.Sprintf("INSERT INTO %s (key, value) VALUES ('%s', '%s')", tableName, randomKey, randomValue))
+	cqlCommandMap["select"] = append(cqlCommandMap["select"], fmt.Sprintf("SELECT key, value FROM %s WHERE key='%s'", tableName, randomKey))
+
+	return cqlCommandMap
+}
+
+// CQLCreateKeyspaceCommand returns the query creating the keyspace if it does not exist
+func CQLCreateKeyspaceCommand(keyspace string) string {
+	return fmt.Sprintf("CREATE KEYSPACE IF NOT EXISTS %s WITH replication = {'class': 'SimpleStrategy', 'replication_factor': %d}", keyspace, defaultCQLReplicationFactor)
+}
+
+// GenerateRandomKafkaCommands generates pairs of produced and expected records for a kafka topic.
+// Each command is a record in the form "<topic> <key>:<value>". Records can't be deleted from a
+// topic, so there are no delete commands and the update commands produce a new value for each key.
+func GenerateRandomKafkaCommands(count int) map[string][]string {
+	var randomKafkaCommands = make(map[string][]string)
+	var topicName = "kafka_validation_" + RandomString(5)
+
+	for counter := 0; counter < count; counter++ {
+		currentCounter := strconv.Itoa(counter)
+		record := fmt.Sprintf("%s %s:Value-%s", topicName, currentCounter, RandomString(10))
+		randomKafkaCommands["insert"] = append(randomKafkaCommands["insert"], record)
+		randomKafkaCommands["select"] = append(randomKafkaCommands["select"], record)
+		randomKafkaCommands["update"] = append(randomKafkaCommands["update"], fmt.Sprintf("%s %s:Value-Updated-%s", topicName, currentCounter, RandomString(10)))
+	}
+
+	return randomKafkaCommands
+}
+
+// GenerateKafkaCommandPair generates pairs of produced and expected records for a kafka topic
+func GenerateKafkaCommandPair(topicName string) map[string][]string {
+	var kafkaCommandMap = make(map[string][]string)
+	record := fmt.Sprintf("%s key-%s:value-%s", topicName, RandomString(10), RandomString(10))
+
+	kafkaCommandMap["insert"] = append(kafkaCommandMap["insert"], record)
+	kafkaCommandMap["select"] = append(kafkaCommandMap["select"], record)
+
+	return kafkaCommandMap
+}
+
+// GenerateRandomElasticsearchCommands generates pairs of index, update, get and delete requests for an elasticsearch index.
+// Each command is a request in the form "<method> <path> [<body>]".
+func GenerateRandomElasticsearchCommands(count int) map[string][]string {
+	var randomRequests = make(map[string][]string)
+	var indexName = "es_validation_" + RandomString(5)
+
+	for counter := 0; counter < count; counter++ {
+		currentCounter := strconv.Itoa(counter)
+		randomValue := "Value-" + RandomString(10)
+		updatedRandomValue := "Value-Updated-" + RandomString(10)
+		randomRequests["insert"] = append(randomRequests["insert"], fmt.Sprintf(`PUT /%s/_doc/%s?refresh=true {"value": "%s"}`, indexName, currentCounter, randomValue))
+		randomRequests["select"] = append(randomRequests["select"], fmt.Sprintf("GET /%s/_doc/%s", indexName, currentCounter))
+		randomRequests["update"] = append(randomRequests["update"], fmt.Sprintf(`POST /%s/_update/%s?refresh=true {"doc": {"value": "%s"}}`, indexName, currentCounter, updatedRandomValue))
+		randomRequests["delete"] = append(randomRequests["delete"], fmt.Sprintf("DELETE /%s/_doc/%s?refresh=true", indexName, currentCounter))
+	}
+
+	return randomRequests
+}
+
+// GenerateElasticsearchCommandPair generates pairs of index and get requests for an elasticsearch index
+func GenerateElasticsearchCommandPair(indexName string) map[string][]string {
+	var requestMap = make(map[string][]string)
+	randomKey := "key-" + RandomString(10)
+	randomValue := "value-" + RandomString(10)
+
+	requestMap["insert"] = append(requestMap["insert"], fmt.Sprintf(`PUT /%s/_doc/%s?refresh=true {"value": "%s"}`, indexName, randomKey, randomValue))
+	requestMap["select"] = append(requestMap["select"], fmt.Sprintf("GET /%s/_doc/%s", indexName, randomKey))
+
+	return requestMap
+}
+
+// GetPodWithContainerPort returns a running pod of the namespace and the name of its container exposing the port
+func GetPodWithContainerPort(namespace string, port int) (*corev1.Pod, string, error) {
+	pods, err := core.Instance().GetPods(namespace, make(map[string]string))
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, containerPort := range container.Ports {
+				if int(containerPort.ContainerPort) == port {
+					return pod, container.Name, nil
+				}
+			}
+		}
+	}
+	return nil, "", fmt.Errorf("no running pod exposing port [%d] found in namespace [%s]", port, namespace)
+}
+
+// RunCommandInContainer runs the command in the container of the pod, feeding it the stdin if any, and returns its stdout
+func RunCommandInContainer(pod *corev1.Pod, container string, command []string, stdin string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	request := &core.RunCommandInPodExRequest{
+		Command:       command,
+		PODName:       pod.Name,
+		ContainerName: container,
+		Namespace:     pod.Namespace,
+		Stdout:        &stdout,
+		Stderr:        &stderr,
+	}
+	if stdin != "" {
+		request.Stdin = strings.NewReader(stdin)
+	}
+	err := core.Instance().RunCommandInPodEx(request)
+	if err != nil {
+		return stdout.String(), fmt.Errorf("failed to run [%s] in pod [%s/%s]: %v, stderr - [%s]",
+			strings.Join(command, " "), pod.Namespace, pod.Name, err, stderr.String())
+	}
+	return stdout.String(), nil
+}
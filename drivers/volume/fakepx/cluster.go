@@ -0,0 +1,743 @@
+package fakepx
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/libopenstorage/openstorage/api"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// PoolStatusOnline is the status of a healthy pool
+	PoolStatusOnline = "Online"
+	// PoolStatusOffline is the status of a pool whose drives are unavailable
+	PoolStatusOffline = "Offline"
+	// PoolStatusMaintenance is the status of a pool in maintenance
+	PoolStatusMaintenance = "In Maintenance"
+
+	// DefaultPoolSize is the size of the pools of nodes added during Init
+	DefaultPoolSize = 100 * gib
+	// DefaultPoolsPerNode is the number of pools of nodes added during Init
+	DefaultPoolsPerNode = 2
+	// maxKvdbMembers is the number of nodes acting as internal kvdb members
+	maxKvdbMembers = 3
+
+	gib = uint64(1024 * 1024 * 1024)
+)
+
+// Cluster is the in-memory model of a Portworx cluster: storage nodes with their
+// pools, volumes with their replica sets and snapshots. All methods are safe
+// for concurrent use and return copies of the stored objects.
+type Cluster struct {
+	sync.Mutex
+	nodes     map[string]*api.StorageNode
+	nodeOrder []string
+	volumes   map[string]*api.Volume
+	// poolStatus is keyed by pool UUID
+	poolStatus map[string]string
+	// driverDown tracks nodes where the driver is stopped
+	driverDown map[string]bool
+	// resizeFailAt is the percentage of progress at which the next resize of a pool fails
+	resizeFailAt map[string]int
+	volCounter   int
+}
+
+// NewCluster returns an empty cluster
+func NewCluster() *Cluster {
+	return &Cluster{
+		nodes:        make(map[string]*api.StorageNode),
+		volumes:      make(map[string]*api.Volume),
+		poolStatus:   make(map[string]string),
+		driverDown:   make(map[string]bool),
+		resizeFailAt: make(map[string]int),
+	}
+}
+
+// AddNode adds a storage node with one pool per given pool size (in bytes). A
+// node without pools is storageless.
+func (c *Cluster) AddNode(schedulerNodeName string, poolSizes ...uint64) *api.StorageNode {
+	c.Lock()
+	defer c.Unlock()
+
+	index := len(c.nodeOrder)
+	n := &api.StorageNode{
+		Id:                fmt.Sprintf("fakepx-node-%d", index),
+		Hostname:          schedulerNodeName,
+		SchedulerNodeName: schedulerNodeName,
+		MgmtIp:            fmt.Sprintf("10.0.0.%d", index+1),
+		DataIp:            fmt.Sprintf("10.0.0.%d", index+1),
+		Status:            api.Status_STATUS_OK,
+		NodeLabels:        map[string]string{},
+	}
+	for i, size := range poolSizes {
+		pool := &api.StoragePool{
+			ID:        int32(i),
+			Uuid:      fmt.Sprintf("fakepx-pool-%d-%d", index, i),
+			TotalSize: size,
+			Medium:    api.StorageMedium_STORAGE_MEDIUM_SSD,
+			Labels:    map[string]string{},
+		}
+		n.Pools = append(n.Pools, pool)
+		c.poolStatus[pool.Uuid] = PoolStatusOnline
+	}
+	c.nodes[n.Id] = n
+	c.nodeOrder = append(c.nodeOrder, n.Id)
+	return proto.Clone(n).(*api.StorageNode)
+}
+
+// nodeByName returns the storage node with the given ID or scheduler node name
+func (c *Cluster) nodeByName(idOrName string) (*api.StorageNode, error) {
+	if n, ok := c.nodes[idOrName]; ok {
+		return n, nil
+	}
+	for _, id := range c.nodeOrder {
+		if n := c.nodes[id]; n.SchedulerNodeName == idOrName {
+			return n, nil
+		}
+	}
+	return nil, &ErrNodeNotFound{ID: idOrName}
+}
+
+func (c *Cluster) poolByUUID(uuid string) (*api.StorageNode, *api.StoragePool, error) {
+	for _, id := range c.nodeOrder {
+		n := c.nodes[id]
+		for _, pool := range n.Pools {
+			if pool.Uuid == uuid {
+				return n, pool, nil
+			}
+		}
+	}
+	return nil, nil, &ErrPoolNotFound{UUID: uuid}
+}
+
+func (c *Cluster) volumeByName(idOrName string) (*api.Volume, error) {
+	if v, ok := c.volumes[idOrName]; ok {
+		return v, nil
+	}
+	for _, v := range c.volumes {
+		if v.Locator.GetName() == idOrName {
+			return v, nil
+		}
+	}
+	return nil, &ErrVolumeNotFound{ID: idOrName}
+}
+
+// isNodeUsable returns true if volumes can be placed on and served by the node
+func (c *Cluster) isNodeUsable(n *api.StorageNode) bool {
+	return !c.driverDown[n.Id] && n.Status == api.Status_STATUS_OK
+}
+
+// Node returns a copy of the storage node with the given ID or scheduler node name
+func (c *Cluster) Node(idOrName string) (*api.StorageNode, error) {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Clone(n).(*api.StorageNode), nil
+}
+
+// Nodes returns copies of all storage nodes in the order they were added
+func (c *Cluster) Nodes() []*api.StorageNode {
+	c.Lock()
+	defer c.Unlock()
+	var nodes []*api.StorageNode
+	for _, id := range c.nodeOrder {
+		nodes = append(nodes, proto.Clone(c.nodes[id]).(*api.StorageNode))
+	}
+	return nodes
+}
+
+// Volume returns a copy of the volume with the given ID or name with its status
+// computed from the state of the replica nodes
+func (c *Cluster) Volume(idOrName string) (*api.Volume, error) {
+	c.Lock()
+	defer c.Unlock()
+	v, err := c.volumeByName(idOrName)
+	if err != nil {
+		return nil, err
+	}
+	return c.withStatus(v), nil
+}
+
+// Volumes returns copies of all volumes sorted by ID
+func (c *Cluster) Volumes() []*api.Volume {
+	c.Lock()
+	defer c.Unlock()
+	var ids []string
+	for id := range c.volumes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var vols []*api.Volume
+	for _, id := range ids {
+		vols = append(vols, c.withStatus(c.volumes[id]))
+	}
+	return vols
+}
+
+func (c *Cluster) withStatus(v *api.Volume) *api.Volume {
+	out := proto.Clone(v).(*api.Volume)
+	up := 0
+	total := 0
+	for _, rs := range v.ReplicaSets {
+		for _, nodeID := range rs.Nodes {
+			total++
+			if n, ok := c.nodes[nodeID]; ok && !c.driverDown[nodeID] && n.Status != api.Status_STATUS_OFFLINE {
+				up++
+			}
+		}
+	}
+	switch {
+	case up == total:
+		out.Status = api.VolumeStatus_VOLUME_STATUS_UP
+	case up == 0:
+		out.Status = api.VolumeStatus_VOLUME_STATUS_DOWN
+	default:
+		out.Status = api.VolumeStatus_VOLUME_STATUS_DEGRADED
+	}
+	return out
+}
+
+// placeReplicas picks count pools, each on a distinct usable node not in
+// exclude, preferring the pools with the most free space. Ties are broken by
+// node order so the placement is deterministic.
+func (c *Cluster) placeReplicas(count int, size uint64, exclude []string) ([]string, []string, error) {
+	type candidate struct {
+		nodeID string
+		pool   *api.StoragePool
+		order  int
+	}
+	excluded := make(map[string]bool)
+	for _, id := range exclude {
+		excluded[id] = true
+	}
+
+	var candidates []candidate
+	for i, id := range c.nodeOrder {
+		n := c.nodes[id]
+		if excluded[id] || !c.isNodeUsable(n) {
+			continue
+		}
+		var best *api.StoragePool
+		for _, pool := range n.Pools {
+			if c.poolStatus[pool.Uuid] != PoolStatusOnline || pool.TotalSize-pool.Used < size {
+				continue
+			}
+			if best == nil || pool.TotalSize-pool.Used > best.TotalSize-best.Used {
+				best = pool
+			}
+		}
+		if best != nil {
+			candidates = append(candidates, candidate{nodeID: id, pool: best, order: i})
+		}
+	}
+	if len(candidates) < count {
+		return nil, nil, fmt.Errorf("could not find enough nodes to place %d replicas of %d bytes, only %d eligible", count, size, len(candidates))
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		fi := candidates[i].pool.TotalSize - candidates[i].pool.Used
+		fj := candidates[j].pool.TotalSize - candidates[j].pool.Used
+		if fi != fj {
+			return fi > fj
+		}
+		return candidates[i].order < candidates[j].order
+	})
+
+	var nodes, pools []string
+	for _, cand := range candidates[:count] {
+		nodes = append(nodes, cand.nodeID)
+		pools = append(pools, cand.pool.Uuid)
+	}
+	return nodes, pools, nil
+}
+
+// CreateVolume creates a volume and places its replicas
+func (c *Cluster) CreateVolume(name string, size uint64, haLevel int64, parentID string) (*api.Volume, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	if _, err := c.volumeByName(name); err == nil {
+		return nil, fmt.Errorf("volume with name %s already exists", name)
+	}
+	if haLevel < minReplicationFactor || haLevel > maxReplicationFactor {
+		return nil, fmt.Errorf("invalid replication factor %d", haLevel)
+	}
+	nodes, pools, err := c.placeReplicas(int(haLevel), size, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.volCounter++
+	v := &api.Volume{
+		Id: fmt.Sprintf("%d", 100000000000+c.volCounter),
+		Locator: &api.VolumeLocator{
+			Name: name,
+		},
+		Ctime: timestamppb.Now(),
+		Spec: &api.VolumeSpec{
+			Size:             size,
+			HaLevel:          haLevel,
+			Format:           api.FSType_FS_TYPE_EXT4,
+			AggregationLevel: 1,
+		},
+		State: api.VolumeState_VOLUME_STATE_DETACHED,
+		ReplicaSets: []*api.ReplicaSet{
+			{Nodes: nodes, PoolUuids: pools},
+		},
+	}
+	if parentID != "" {
+		v.Source = &api.Source{Parent: parentID}
+	}
+	c.chargePools(pools, int64(size))
+	c.volumes[v.Id] = v
+	return proto.Clone(v).(*api.Volume), nil
+}
+
+// chargePools adds delta bytes to the used size of the given pools
+func (c *Cluster) chargePools(poolUUIDs []string, delta int64) {
+	for _, uuid := range poolUUIDs {
+		if _, pool, err := c.poolByUUID(uuid); err == nil {
+			pool.Used = uint64(int64(pool.Used) + delta)
+		}
+	}
+}
+
+// DeleteVolume deletes the volume and releases its pool space
+func (c *Cluster) DeleteVolume(idOrName string) error {
+	c.Lock()
+	defer c.Unlock()
+	v, err := c.volumeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	for _, rs := range v.ReplicaSets {
+		c.chargePools(rs.PoolUuids, -int64(v.Spec.Size))
+	}
+	delete(c.volumes, v.Id)
+	return nil
+}
+
+// ResizeVolume grows the volume to the given size in bytes
+func (c *Cluster) ResizeVolume(idOrName string, size uint64) error {
+	c.Lock()
+	defer c.Unlock()
+	v, err := c.volumeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	if size <= v.Spec.Size {
+		return fmt.Errorf("new size %d of volume %s must be greater than current size %d", size, v.Id, v.Spec.Size)
+	}
+	delta := size - v.Spec.Size
+	for _, rs := range v.ReplicaSets {
+		for _, uuid := range rs.PoolUuids {
+			_, pool, err := c.poolByUUID(uuid)
+			if err != nil {
+				return err
+			}
+			if pool.TotalSize-pool.Used < delta {
+				return fmt.Errorf("pool %s does not have %d bytes free to resize volume %s", uuid, delta, v.Id)
+			}
+		}
+	}
+	for _, rs := range v.ReplicaSets {
+		c.chargePools(rs.PoolUuids, int64(delta))
+	}
+	v.Spec.Size = size
+	return nil
+}
+
+// SetReplicationFactor grows or shrinks the replica set of the volume. When
+// growing, replicas are placed on the given nodes (or pools) if any, otherwise
+// automatically. When shrinking, the replicas on the given nodes are removed,
+// otherwise the most recently added ones.
+func (c *Cluster) SetReplicationFactor(idOrName string, rf int64, nodeIDs []string, poolUUIDs []string) error {
+	c.Lock()
+	defer c.Unlock()
+	v, err := c.volumeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	if rf < minReplicationFactor || rf > maxReplicationFactor {
+		return fmt.Errorf("invalid replication factor %d", rf)
+	}
+	rs := v.ReplicaSets[0]
+	current := int64(len(rs.Nodes))
+	switch {
+	case rf > current:
+		if rf-current != 1 {
+			return fmt.Errorf("replication factor of volume %s can only be increased by 1 at a time", v.Id)
+		}
+		var addNodes, addPools []string
+		if len(poolUUIDs) > 0 {
+			n, _, err := c.poolByUUID(poolUUIDs[0])
+			if err != nil {
+				return err
+			}
+			addNodes, addPools = []string{n.Id}, poolUUIDs[:1]
+		} else if len(nodeIDs) > 0 {
+			n, err := c.nodeByName(nodeIDs[0])
+			if err != nil {
+				return err
+			}
+			_, pools, err := c.placeReplicas(1, v.Spec.Size, c.otherNodes(n.Id))
+			if err != nil {
+				return err
+			}
+			addNodes, addPools = []string{n.Id}, pools
+		} else {
+			addNodes, addPools, err = c.placeReplicas(1, v.Spec.Size, rs.Nodes)
+			if err != nil {
+				return err
+			}
+		}
+		for _, existing := range rs.Nodes {
+			if existing == addNodes[0] {
+				return fmt.Errorf("volume %s already has a replica on node %s", v.Id, existing)
+			}
+		}
+		rs.Nodes = append(rs.Nodes, addNodes...)
+		rs.PoolUuids = append(rs.PoolUuids, addPools...)
+		c.chargePools(addPools, int64(v.Spec.Size))
+	case rf < current:
+		if current-rf != 1 {
+			return fmt.Errorf("replication factor of volume %s can only be decreased by 1 at a time", v.Id)
+		}
+		remove := len(rs.Nodes) - 1
+		if len(nodeIDs) > 0 {
+			n, err := c.nodeByName(nodeIDs[0])
+			if err != nil {
+				return err
+			}
+			remove = -1
+			for i, id := range rs.Nodes {
+				if id == n.Id {
+					remove = i
+				}
+			}
+			if remove < 0 {
+				return fmt.Errorf("volume %s has no replica on node %s", v.Id, n.Id)
+			}
+		}
+		c.chargePools(rs.PoolUuids[remove:remove+1], -int64(v.Spec.Size))
+		rs.Nodes = append(rs.Nodes[:remove], rs.Nodes[remove+1:]...)
+		rs.PoolUuids = append(rs.PoolUuids[:remove], rs.PoolUuids[remove+1:]...)
+	}
+	v.Spec.HaLevel = rf
+	return nil
+}
+
+// otherNodes returns the IDs of all nodes except the given one
+func (c *Cluster) otherNodes(nodeID string) []string {
+	var others []string
+	for _, id := range c.nodeOrder {
+		if id != nodeID {
+			others = append(others, id)
+		}
+	}
+	return others
+}
+
+// AttachVolume attaches the volume on the given node
+func (c *Cluster) AttachVolume(idOrName, nodeID string) (*api.Volume, error) {
+	c.Lock()
+	defer c.Unlock()
+	v, err := c.volumeByName(idOrName)
+	if err != nil {
+		return nil, err
+	}
+	n, err := c.nodeByName(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if !c.isNodeUsable(n) {
+		return nil, fmt.Errorf("cannot attach volume %s on node %s in status %s", v.Id, n.Id, n.Status)
+	}
+	v.AttachedOn = n.Id
+	v.State = api.VolumeState_VOLUME_STATE_ATTACHED
+	v.AttachedState = api.AttachState_ATTACH_STATE_INTERNAL
+	v.DevicePath = fmt.Sprintf("/dev/pxd/pxd%s", v.Id)
+	return proto.Clone(v).(*api.Volume), nil
+}
+
+// DetachVolume detaches the volume
+func (c *Cluster) DetachVolume(idOrName string) error {
+	c.Lock()
+	defer c.Unlock()
+	v, err := c.volumeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	v.AttachedOn = ""
+	v.State = api.VolumeState_VOLUME_STATE_DETACHED
+	v.DevicePath = ""
+	return nil
+}
+
+// SetNodeStatus sets the status of the storage node
+func (c *Cluster) SetNodeStatus(idOrName string, status api.Status) error {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	n.Status = status
+	return nil
+}
+
+// SetDriverDown marks the driver as stopped or started on the node
+func (c *Cluster) SetDriverDown(idOrName string, down bool) error {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	if down {
+		c.driverDown[n.Id] = true
+		return nil
+	}
+	delete(c.driverDown, n.Id)
+	return nil
+}
+
+// IsDriverDown returns true if the driver is stopped on the node
+func (c *Cluster) IsDriverDown(idOrName string) (bool, error) {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return false, err
+	}
+	return c.driverDown[n.Id], nil
+}
+
+// SetPoolStatus sets the status of a pool, for e.g. PoolStatusOffline to
+// simulate a pool whose drives went away
+func (c *Cluster) SetPoolStatus(poolUUID, status string) error {
+	c.Lock()
+	defer c.Unlock()
+	if _, _, err := c.poolByUUID(poolUUID); err != nil {
+		return err
+	}
+	c.poolStatus[poolUUID] = status
+	return nil
+}
+
+// PoolStatus returns the status of the pool
+func (c *Cluster) PoolStatus(poolUUID string) (string, error) {
+	c.Lock()
+	defer c.Unlock()
+	if _, _, err := c.poolByUUID(poolUUID); err != nil {
+		return "", err
+	}
+	return c.poolStatus[poolUUID], nil
+}
+
+// setNodePoolsStatus sets the status of all pools of the node
+func (c *Cluster) setNodePoolsStatus(n *api.StorageNode, status string) {
+	for _, pool := range n.Pools {
+		c.poolStatus[pool.Uuid] = status
+	}
+}
+
+// FailPoolResizeAt makes the next resize of the pool fail once it has progressed
+// by the given percentage of the requested expansion
+func (c *Cluster) FailPoolResizeAt(poolUUID string, percent int) error {
+	c.Lock()
+	defer c.Unlock()
+	if _, _, err := c.poolByUUID(poolUUID); err != nil {
+		return err
+	}
+	c.resizeFailAt[poolUUID] = percent
+	return nil
+}
+
+// ExpandPool grows the pool to the given size in GiB
+func (c *Cluster) ExpandPool(poolUUID string, operation api.SdkStoragePool_ResizeOperationType, sizeInGiB uint64) error {
+	c.Lock()
+	defer c.Unlock()
+	n, pool, err := c.poolByUUID(poolUUID)
+	if err != nil {
+		return err
+	}
+	if c.driverDown[n.Id] || n.Status == api.Status_STATUS_OFFLINE {
+		return fmt.Errorf("cannot expand pool %s, node %s is offline", poolUUID, n.Id)
+	}
+	if status := c.poolStatus[poolUUID]; status == PoolStatusOffline {
+		return fmt.Errorf("cannot expand pool %s in status %s", poolUUID, status)
+	}
+	target := sizeInGiB * gib
+	if target <= pool.TotalSize {
+		return fmt.Errorf("requested size %d GiB of pool %s is not greater than current size %d GiB", sizeInGiB, poolUUID, pool.TotalSize/gib)
+	}
+
+	params := map[string]string{
+		"operation": operation.String(),
+		"size":      fmt.Sprintf("%d", sizeInGiB),
+	}
+	if percent, ok := c.resizeFailAt[poolUUID]; ok {
+		delete(c.resizeFailAt, poolUUID)
+		pool.TotalSize += (target - pool.TotalSize) * uint64(percent) / 100
+		pool.LastOperation = &api.StoragePoolOperation{
+			Type:   api.SdkStoragePool_OPERATION_RESIZE,
+			Msg:    fmt.Sprintf("resize of pool failed at %d%%", percent),
+			Params: params,
+			Status: api.SdkStoragePool_OPERATION_FAILED,
+		}
+		return fmt.Errorf("failed to expand pool %s: %s", poolUUID, pool.LastOperation.Msg)
+	}
+	pool.TotalSize = target
+	pool.LastOperation = &api.StoragePoolOperation{
+		Type:   api.SdkStoragePool_OPERATION_RESIZE,
+		Msg:    "Storage pool resize completed",
+		Params: params,
+		Status: api.SdkStoragePool_OPERATION_SUCCESSFUL,
+	}
+	return nil
+}
+
+// EnterMaintenance puts the node in maintenance. Volumes stay available through
+// their other replicas.
+func (c *Cluster) EnterMaintenance(idOrName string) error {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	if c.driverDown[n.Id] {
+		return fmt.Errorf("cannot put node %s in maintenance, driver is down", n.Id)
+	}
+	if n.Status == api.Status_STATUS_MAINTENANCE {
+		return fmt.Errorf("node %s is already in maintenance", n.Id)
+	}
+	n.Status = api.Status_STATUS_MAINTENANCE
+	return nil
+}
+
+// ExitMaintenance brings the node out of maintenance
+func (c *Cluster) ExitMaintenance(idOrName string) error {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	if n.Status != api.Status_STATUS_MAINTENANCE {
+		return fmt.Errorf("node %s is not in maintenance, status: %s", n.Id, n.Status)
+	}
+	n.Status = api.Status_STATUS_OK
+	return nil
+}
+
+// EnterPoolMaintenance puts all pools of the node in maintenance
+func (c *Cluster) EnterPoolMaintenance(idOrName string) error {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	if c.driverDown[n.Id] {
+		return fmt.Errorf("cannot put pools of node %s in maintenance, driver is down", n.Id)
+	}
+	n.Status = api.Status_STATUS_POOLMAINTENANCE
+	c.setNodePoolsStatus(n, PoolStatusMaintenance)
+	return nil
+}
+
+// ExitPoolMaintenance brings all pools of the node out of maintenance
+func (c *Cluster) ExitPoolMaintenance(idOrName string) error {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	if n.Status != api.Status_STATUS_POOLMAINTENANCE {
+		return fmt.Errorf("pools of node %s are not in maintenance, status: %s", n.Id, n.Status)
+	}
+	n.Status = api.Status_STATUS_OK
+	c.setNodePoolsStatus(n, PoolStatusOnline)
+	return nil
+}
+
+// DecommissionNode removes the node from the cluster. It fails if any volume
+// still has a replica on the node.
+func (c *Cluster) DecommissionNode(idOrName string) error {
+	c.Lock()
+	defer c.Unlock()
+	n, err := c.nodeByName(idOrName)
+	if err != nil {
+		return err
+	}
+	for _, v := range c.volumes {
+		for _, rs := range v.ReplicaSets {
+			for _, id := range rs.Nodes {
+				if id == n.Id {
+					return fmt.Errorf("cannot decommission node %s, volume %s has a replica on it", n.Id, v.Id)
+				}
+			}
+		}
+	}
+	for _, pool := range n.Pools {
+		delete(c.poolStatus, pool.Uuid)
+	}
+	delete(c.nodes, n.Id)
+	delete(c.driverDown, n.Id)
+	for i, id := range c.nodeOrder {
+		if id == n.Id {
+			c.nodeOrder = append(c.nodeOrder[:i], c.nodeOrder[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// KvdbMembers returns the IDs of the nodes acting as internal kvdb members:
+// the first storage nodes in the order they were added
+func (c *Cluster) KvdbMembers() []string {
+	c.Lock()
+	defer c.Unlock()
+	var members []string
+	for _, id := range c.nodeOrder {
+		if len(c.nodes[id].Pools) > 0 && len(members) < maxKvdbMembers {
+			members = append(members, id)
+		}
+	}
+	return members
+}
+
+// ErrNodeNotFound is returned when a storage node is not part of the cluster
+type ErrNodeNotFound struct {
+	ID string
+}
+
+func (e *ErrNodeNotFound) Error() string {
+	return fmt.Sprintf("storage node %s not found", e.ID)
+}
+
+// ErrPoolNotFound is returned when a storage pool is not part of the cluster
+type ErrPoolNotFound struct {
+	UUID string
+}
+
+func (e *ErrPoolNotFound) Error() string {
+	return fmt.Sprintf("storage pool %s not found", e.UUID)
+}
+
+// ErrVolumeNotFound is returned when a volume is not part of the cluster
+type ErrVolumeNotFound struct {
+	ID string
+}
+
+func (e *ErrVolumeNotFound) Error() string {
+	return fmt.Sprintf("volume %s not found", e.ID)
+}
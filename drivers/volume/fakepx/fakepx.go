@@ -0,0 +1,667 @@
+package fakepx
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/portworx/sched-ops/task"
+	driver_api "github.com/portworx/torpedo/drivers/api"
+	"github.com/portworx/torpedo/drivers/node"
+	torpedovolume "github.com/portworx/torpedo/drivers/volume"
+	"github.com/portworx/torpedo/pkg/errors"
+	"github.com/portworx/torpedo/pkg/fault"
+	"github.com/portworx/torpedo/pkg/log"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// DriverName is the name of the fake portworx driver implementation
+	DriverName = "fakepx"
+	// FakePxStorage is the provisioner of the fake portworx driver
+	FakePxStorage torpedovolume.StorageProvisionerType = "fakepx"
+	// DriverVersion is the version reported by the fake portworx driver
+	DriverVersion = "3.1.0.0-fakepx"
+
+	minReplicationFactor = 1
+	maxReplicationFactor = 3
+	replParameter        = "repl"
+	driverPollInterval   = 100 * time.Millisecond
+)
+
+// Provisioners types of supported provisioners
+var provisioners = map[torpedovolume.StorageProvisionerType]torpedovolume.StorageProvisionerType{
+	FakePxStorage: "pxd.portworx.com",
+}
+
+// Driver is an in-memory volume driver that simulates a Portworx cluster with
+// storage pools, replica sets and node status, for unit testing tests and
+// triggers without a real cluster. Outcomes of its operations can be scripted
+// with Script and Fail, and cluster-level faults injected through Cluster.
+type Driver struct {
+	torpedovolume.DefaultDriver
+	*fault.Faults[Op]
+	cluster *Cluster
+}
+
+// New returns a fake portworx driver with an empty cluster
+func New() *Driver {
+	return &Driver{
+		Faults:  fault.NewFaults[Op](),
+		cluster: NewCluster(),
+	}
+}
+
+// Cluster returns the in-memory cluster backing the driver
+func (d *Driver) Cluster() *Cluster {
+	return d.cluster
+}
+
+func (d *Driver) String() string {
+	return DriverName
+}
+
+// Init adds a storage node with DefaultPoolsPerNode pools to the cluster for
+// every worker node of the node registry and updates the registry with the
+// resulting storage node info
+func (d *Driver) Init(sched, nodeDriver, token, storageProvisioner, csiGenericDriverConfigMap string) error {
+	log.Infof("Using the fake portworx volume driver with provisioner %s under scheduler: %v", storageProvisioner, sched)
+	torpedovolume.StorageDriver = DriverName
+	torpedovolume.StorageProvisioner = provisioners[FakePxStorage]
+
+	for _, n := range node.GetWorkerNodes() {
+		if _, err := d.cluster.Node(n.Name); err == nil {
+			continue
+		}
+		var poolSizes []uint64
+		for i := 0; i < DefaultPoolsPerNode; i++ {
+			poolSizes = append(poolSizes, DefaultPoolSize)
+		}
+		d.cluster.AddNode(n.Name, poolSizes...)
+	}
+	return d.RefreshDriverEndpoints()
+}
+
+// RefreshDriverEndpoints updates the node registry with the storage node info of the cluster
+func (d *Driver) RefreshDriverEndpoints() error {
+	members := make(map[string]bool)
+	for _, id := range d.cluster.KvdbMembers() {
+		members[id] = true
+	}
+	for _, n := range node.GetWorkerNodes() {
+		sn, err := d.cluster.Node(n.Name)
+		if err != nil {
+			continue
+		}
+		n.StorageNode = sn
+		n.VolDriverNodeID = sn.Id
+		n.IsStorageDriverInstalled = true
+		n.IsMetadataNode = members[sn.Id]
+		n.StoragePools = nil
+		for _, pool := range sn.Pools {
+			n.StoragePools = append(n.StoragePools, node.StoragePool{
+				StoragePool:       pool,
+				StoragePoolAtInit: pool,
+			})
+		}
+		if err := node.UpdateNode(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListAllVolumes returns the IDs of all volumes
+func (d *Driver) ListAllVolumes() ([]string, error) {
+	var ids []string
+	for _, v := range d.cluster.Volumes() {
+		ids = append(ids, v.Id)
+	}
+	return ids, nil
+}
+
+// CreateVolume creates a volume and returns its ID
+func (d *Driver) CreateVolume(volName string, size uint64, haLevel int64) (string, error) {
+	if err := d.Inject(OpCreateVolume); err != nil {
+		return "", err
+	}
+	v, err := d.cluster.CreateVolume(volName, size, haLevel, "")
+	if err != nil {
+		return "", err
+	}
+	return v.Id, nil
+}
+
+// ResizeVolume resizes the volume to the given size in bytes
+func (d *Driver) ResizeVolume(volName string, size uint64) error {
+	if err := d.Inject(OpResizeVolume); err != nil {
+		return err
+	}
+	return d.cluster.ResizeVolume(volName, size)
+}
+
+// CloneVolume clones the volume and returns the ID of the clone
+func (d *Driver) CloneVolume(volumeID string) (string, error) {
+	v, err := d.cluster.Volume(volumeID)
+	if err != nil {
+		return "", err
+	}
+	clone, err := d.cluster.CreateVolume(v.Locator.Name+"-clone", v.Spec.Size, v.Spec.HaLevel, v.Id)
+	if err != nil {
+		return "", err
+	}
+	return clone.Id, nil
+}
+
+// AttachVolume attaches the volume on the first node of its replica set and
+// returns the device path
+func (d *Driver) AttachVolume(volumeID string) (string, error) {
+	v, err := d.cluster.Volume(volumeID)
+	if err != nil {
+		return "", err
+	}
+	v, err = d.cluster.AttachVolume(volumeID, v.ReplicaSets[0].Nodes[0])
+	if err != nil {
+		return "", err
+	}
+	return v.DevicePath, nil
+}
+
+// DetachVolume detaches the volume
+func (d *Driver) DetachVolume(volumeID string) error {
+	return d.cluster.DetachVolume(volumeID)
+}
+
+// DeleteVolume deletes the volume
+func (d *Driver) DeleteVolume(volumeID string) error {
+	if err := d.Inject(OpDeleteVolume); err != nil {
+		return err
+	}
+	return d.cluster.DeleteVolume(volumeID)
+}
+
+// InspectVolume returns the volume with the given ID or name
+func (d *Driver) InspectVolume(name string) (*api.Volume, error) {
+	if err := d.Inject(OpInspectVolume); err != nil {
+		return nil, err
+	}
+	return d.cluster.Volume(name)
+}
+
+// CreateSnapshot creates a snapshot of the volume
+func (d *Driver) CreateSnapshot(volumeID string, snapName string) (*api.SdkVolumeSnapshotCreateResponse, error) {
+	if err := d.Inject(OpCreateSnapshot); err != nil {
+		return nil, err
+	}
+	v, err := d.cluster.Volume(volumeID)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := d.cluster.CreateVolume(snapName, v.Spec.Size, v.Spec.HaLevel, v.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &api.SdkVolumeSnapshotCreateResponse{SnapshotId: snap.Id}, nil
+}
+
+// ValidateCreateVolume checks the volume exists and its replication factor
+// matches the one requested in params
+func (d *Driver) ValidateCreateVolume(name string, params map[string]string) error {
+	v, err := d.cluster.Volume(name)
+	if err != nil {
+		return err
+	}
+	if repl, ok := params[replParameter]; ok && repl != fmt.Sprintf("%d", v.Spec.HaLevel) {
+		return fmt.Errorf("volume %s has replication factor %d, expected %s", v.Id, v.Spec.HaLevel, repl)
+	}
+	return nil
+}
+
+// ValidateUpdateVolume checks the volume exists
+func (d *Driver) ValidateUpdateVolume(vol *torpedovolume.Volume, params map[string]string) error {
+	_, err := d.cluster.Volume(vol.ID)
+	return err
+}
+
+// ValidateDeleteVolume checks the volume no longer exists
+func (d *Driver) ValidateDeleteVolume(vol *torpedovolume.Volume) error {
+	if _, err := d.cluster.Volume(vol.ID); err == nil {
+		return fmt.Errorf("volume %s still exists", vol.ID)
+	}
+	return nil
+}
+
+// ValidateVolumeCleanup is a no-op as the fake driver leaves nothing behind
+func (d *Driver) ValidateVolumeCleanup() error {
+	return nil
+}
+
+// ValidateVolumeSetup checks the volume exists and all of its replicas are up
+func (d *Driver) ValidateVolumeSetup(vol *torpedovolume.Volume) error {
+	v, err := d.cluster.Volume(vol.ID)
+	if err != nil {
+		return err
+	}
+	if v.Status != api.VolumeStatus_VOLUME_STATUS_UP {
+		return fmt.Errorf("volume %s is in status %s", v.Id, v.Status)
+	}
+	return nil
+}
+
+// StopDriver stops the driver on the given nodes
+func (d *Driver) StopDriver(nodes []node.Node, force bool, triggerOpts *driver_api.TriggerOptions) error {
+	stopFn := func() error {
+		if err := d.Inject(OpStopDriver); err != nil {
+			return err
+		}
+		for _, n := range nodes {
+			if err := d.cluster.SetDriverDown(n.Name, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return driver_api.PerformTask(stopFn, triggerOpts)
+}
+
+// StartDriver starts the driver on the given node
+func (d *Driver) StartDriver(n node.Node) error {
+	if err := d.Inject(OpStartDriver); err != nil {
+		return err
+	}
+	return d.cluster.SetDriverDown(n.Name, false)
+}
+
+// RestartDriver restarts the driver on the given node
+func (d *Driver) RestartDriver(n node.Node, triggerOpts *driver_api.TriggerOptions) error {
+	restartFn := func() error {
+		if err := d.Inject(OpRestartDriver); err != nil {
+			return err
+		}
+		return d.cluster.SetDriverDown(n.Name, false)
+	}
+	return driver_api.PerformTask(restartFn, triggerOpts)
+}
+
+// WaitDriverUpOnNode waits for the driver to be started on the node
+func (d *Driver) WaitDriverUpOnNode(n node.Node, timeout time.Duration) error {
+	return d.waitDriverState(n, false, timeout)
+}
+
+// WaitDriverDownOnNode waits for the driver to be stopped on the node
+func (d *Driver) WaitDriverDownOnNode(n node.Node) error {
+	return d.waitDriverState(n, true, time.Minute)
+}
+
+func (d *Driver) waitDriverState(n node.Node, down bool, timeout time.Duration) error {
+	t := func() (interface{}, bool, error) {
+		isDown, err := d.cluster.IsDriverDown(n.Name)
+		if err != nil {
+			return nil, false, err
+		}
+		if isDown != down {
+			return nil, true, fmt.Errorf("driver on node %s is not %s yet", n.Name, map[bool]string{true: "down", false: "up"}[down])
+		}
+		return nil, false, nil
+	}
+	_, err := task.DoRetryWithTimeout(t, timeout, driverPollInterval)
+	return err
+}
+
+// IsPxReadyOnNode returns true if the driver is up and the node is healthy
+func (d *Driver) IsPxReadyOnNode(n node.Node) bool {
+	isDown, err := d.cluster.IsDriverDown(n.Name)
+	if err != nil || isDown {
+		return false
+	}
+	sn, err := d.cluster.Node(n.Name)
+	return err == nil && sn.Status == api.Status_STATUS_OK
+}
+
+// IsDriverInstalled returns true if the node is part of the cluster
+func (d *Driver) IsDriverInstalled(n node.Node) (bool, error) {
+	_, err := d.cluster.Node(n.Name)
+	return err == nil, nil
+}
+
+// GetNodeStatus returns the status of the node. A node where the driver is
+// stopped is reported offline.
+func (d *Driver) GetNodeStatus(n node.Node) (*api.Status, error) {
+	sn, err := d.cluster.Node(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	status := sn.Status
+	if isDown, _ := d.cluster.IsDriverDown(n.Name); isDown {
+		status = api.Status_STATUS_OFFLINE
+	}
+	return &status, nil
+}
+
+// GetDriverNode returns the storage node of the given node
+func (d *Driver) GetDriverNode(n *node.Node, nodeOpts ...api.OpenStorageNodeClient) (*api.StorageNode, error) {
+	return d.cluster.Node(n.Name)
+}
+
+// GetDriverNodes returns all storage nodes
+func (d *Driver) GetDriverNodes() ([]*api.StorageNode, error) {
+	return d.cluster.Nodes(), nil
+}
+
+// GetStoragelessNodes returns the nodes without storage pools
+func (d *Driver) GetStoragelessNodes() ([]*api.StorageNode, error) {
+	var storageless []*api.StorageNode
+	for _, sn := range d.cluster.Nodes() {
+		if len(sn.Pools) == 0 {
+			storageless = append(storageless, sn)
+		}
+	}
+	return storageless, nil
+}
+
+// GetDriverVersion returns DriverVersion
+func (d *Driver) GetDriverVersion() (string, error) {
+	return DriverVersion, nil
+}
+
+// GetDriverVersionOnNode returns DriverVersion
+func (d *Driver) GetDriverVersionOnNode(n node.Node) (string, error) {
+	return DriverVersion, nil
+}
+
+// GetNodeForVolume returns the node the volume is attached on, or the node of
+// its first replica if it is detached
+func (d *Driver) GetNodeForVolume(vol *torpedovolume.Volume, timeout time.Duration, retryInterval time.Duration) (*node.Node, error) {
+	v, err := d.cluster.Volume(vol.ID)
+	if err != nil {
+		return nil, err
+	}
+	nodeID := v.AttachedOn
+	if nodeID == "" {
+		nodeID = v.ReplicaSets[0].Nodes[0]
+	}
+	sn, err := d.cluster.Node(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	n, err := node.GetNodeByName(sn.SchedulerNodeName)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// GetReplicationFactor returns the replication factor of the volume
+func (d *Driver) GetReplicationFactor(vol *torpedovolume.Volume) (int64, error) {
+	v, err := d.cluster.Volume(vol.ID)
+	if err != nil {
+		return 0, err
+	}
+	return v.Spec.HaLevel, nil
+}
+
+// SetReplicationFactor changes the replication factor of the volume by one
+func (d *Driver) SetReplicationFactor(vol *torpedovolume.Volume, rf int64, nodesToBeUpdated []string, poolsToBeUpdated []string, waitForUpdateToFinish bool, opts ...torpedovolume.Options) error {
+	if err := d.Inject(OpSetReplicationFactor); err != nil {
+		return err
+	}
+	return d.cluster.SetReplicationFactor(vol.ID, rf, nodesToBeUpdated, poolsToBeUpdated)
+}
+
+// WaitForReplicationToComplete checks the volume has replFactor replicas.
+// Replication completes instantly in the fake driver.
+func (d *Driver) WaitForReplicationToComplete(vol *torpedovolume.Volume, replFactor int64, replicationUpdateTimeout time.Duration) error {
+	v, err := d.cluster.Volume(vol.ID)
+	if err != nil {
+		return err
+	}
+	if got := int64(len(v.ReplicaSets[0].Nodes)); got != replFactor {
+		return fmt.Errorf("volume %s has %d replicas, expected %d", v.Id, got, replFactor)
+	}
+	return nil
+}
+
+// GetMaxReplicationFactor returns the maximum replication factor
+func (d *Driver) GetMaxReplicationFactor() int64 {
+	return maxReplicationFactor
+}
+
+// GetMinReplicationFactor returns the minimum replication factor
+func (d *Driver) GetMinReplicationFactor() int64 {
+	return minReplicationFactor
+}
+
+// GetAggregationLevel returns the aggregation level of the volume
+func (d *Driver) GetAggregationLevel(vol *torpedovolume.Volume) (int64, error) {
+	v, err := d.cluster.Volume(vol.ID)
+	if err != nil {
+		return 0, err
+	}
+	return int64(v.Spec.AggregationLevel), nil
+}
+
+// GetReplicaSets returns the replica sets of the volume
+func (d *Driver) GetReplicaSets(vol *torpedovolume.Volume) ([]*api.ReplicaSet, error) {
+	v, err := d.cluster.Volume(vol.ID)
+	if err != nil {
+		return nil, err
+	}
+	return v.ReplicaSets, nil
+}
+
+// EnterMaintenance puts the node in maintenance
+func (d *Driver) EnterMaintenance(n node.Node) error {
+	if err := d.Inject(OpEnterMaintenance); err != nil {
+		return err
+	}
+	return d.cluster.EnterMaintenance(n.Name)
+}
+
+// ExitMaintenance brings the node out of maintenance
+func (d *Driver) ExitMaintenance(n node.Node) error {
+	if err := d.Inject(OpExitMaintenance); err != nil {
+		return err
+	}
+	return d.cluster.ExitMaintenance(n.Name)
+}
+
+// IsNodeInMaintenance returns true if the node is in maintenance
+func (d *Driver) IsNodeInMaintenance(n node.Node) (bool, error) {
+	sn, err := d.cluster.Node(n.Name)
+	if err != nil {
+		return false, err
+	}
+	return sn.Status == api.Status_STATUS_MAINTENANCE, nil
+}
+
+// IsNodeOutOfMaintenance returns true if the node is not in maintenance
+func (d *Driver) IsNodeOutOfMaintenance(n node.Node) (bool, error) {
+	inMaintenance, err := d.IsNodeInMaintenance(n)
+	return !inMaintenance, err
+}
+
+// EnterPoolMaintenance puts all pools of the node in maintenance
+func (d *Driver) EnterPoolMaintenance(n node.Node) error {
+	if err := d.Inject(OpEnterPoolMaintenance); err != nil {
+		return err
+	}
+	return d.cluster.EnterPoolMaintenance(n.Name)
+}
+
+// ExitPoolMaintenance brings all pools of the node out of maintenance
+func (d *Driver) ExitPoolMaintenance(n node.Node) error {
+	if err := d.Inject(OpExitPoolMaintenance); err != nil {
+		return err
+	}
+	return d.cluster.ExitPoolMaintenance(n.Name)
+}
+
+// GetNodePoolsStatus returns the status of the pools of the node keyed by pool UUID
+func (d *Driver) GetNodePoolsStatus(n node.Node) (map[string]string, error) {
+	sn, err := d.cluster.Node(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	poolsStatus := make(map[string]string)
+	for _, pool := range sn.Pools {
+		status, err := d.cluster.PoolStatus(pool.Uuid)
+		if err != nil {
+			return nil, err
+		}
+		poolsStatus[pool.Uuid] = status
+	}
+	return poolsStatus, nil
+}
+
+// GetNodePools returns the IDs of the pools of the node keyed by pool UUID
+func (d *Driver) GetNodePools(n node.Node) (map[string]string, error) {
+	sn, err := d.cluster.Node(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	pools := make(map[string]string)
+	for _, pool := range sn.Pools {
+		pools[pool.Uuid] = fmt.Sprintf("%d", pool.ID)
+	}
+	return pools, nil
+}
+
+// ListStoragePools returns the pools matching the label selector keyed by pool UUID
+func (d *Driver) ListStoragePools(labelSelector metav1.LabelSelector) (map[string]*api.StoragePool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return nil, err
+	}
+	pools := make(map[string]*api.StoragePool)
+	for _, sn := range d.cluster.Nodes() {
+		for _, pool := range sn.Pools {
+			if selector.Matches(labels.Set(pool.Labels)) {
+				pools[pool.Uuid] = pool
+			}
+		}
+	}
+	return pools, nil
+}
+
+// ValidateStoragePools checks all pools are online
+func (d *Driver) ValidateStoragePools() error {
+	for _, sn := range d.cluster.Nodes() {
+		for _, pool := range sn.Pools {
+			status, err := d.cluster.PoolStatus(pool.Uuid)
+			if err != nil {
+				return err
+			}
+			if status != PoolStatusOnline {
+				return fmt.Errorf("pool %s on node %s is in status %s", pool.Uuid, sn.Id, status)
+			}
+		}
+	}
+	return nil
+}
+
+// IsStorageExpansionEnabled returns true as pools of the fake driver can always be expanded
+func (d *Driver) IsStorageExpansionEnabled() (bool, error) {
+	return true, nil
+}
+
+// ExpandPool expands the pool to the given size in GiB
+func (d *Driver) ExpandPool(poolUID string, operation api.SdkStoragePool_ResizeOperationType, size uint64, skipWaitForCleanVolumes bool) error {
+	if err := d.Inject(OpExpandPool); err != nil {
+		return err
+	}
+	return d.cluster.ExpandPool(poolUID, operation, size)
+}
+
+// DecommissionNode removes the node from the cluster
+func (d *Driver) DecommissionNode(n *node.Node) error {
+	if err := d.Inject(OpDecommissionNode); err != nil {
+		return err
+	}
+	if err := d.cluster.DecommissionNode(n.Name); err != nil {
+		return err
+	}
+	n.StorageNode = nil
+	n.VolDriverNodeID = ""
+	n.IsStorageDriverInstalled = false
+	n.IsMetadataNode = false
+	n.StoragePools = nil
+	return node.UpdateNode(*n)
+}
+
+// GetKvdbMembers returns the internal kvdb members keyed by node ID
+func (d *Driver) GetKvdbMembers(n node.Node) (map[string]*torpedovolume.MetadataNode, error) {
+	kvdbMembers := make(map[string]*torpedovolume.MetadataNode)
+	for i, id := range d.cluster.KvdbMembers() {
+		sn, err := d.cluster.Node(id)
+		if err != nil {
+			return nil, err
+		}
+		isDown, _ := d.cluster.IsDriverDown(id)
+		kvdbMembers[id] = &torpedovolume.MetadataNode{
+			PeerUrls:   []string{fmt.Sprintf("http://%s:9018", sn.MgmtIp)},
+			ClientUrls: []string{fmt.Sprintf("http://%s:9019", sn.MgmtIp)},
+			Leader:     i == 0,
+			IsHealthy:  !isDown && sn.Status != api.Status_STATUS_OFFLINE,
+			ID:         id,
+			Name:       sn.SchedulerNodeName,
+		}
+	}
+	return kvdbMembers, nil
+}
+
+// DeleteSnapshotsForVolumes deletes the snapshots of the given volumes
+func (d *Driver) DeleteSnapshotsForVolumes(volumeNames []string, clusterProviderCredential string) error {
+	parents := make(map[string]bool)
+	for _, name := range volumeNames {
+		v, err := d.cluster.Volume(name)
+		if err != nil {
+			return err
+		}
+		parents[v.Id] = true
+	}
+	for _, v := range d.cluster.Volumes() {
+		if v.Source != nil && parents[v.Source.Parent] {
+			if err := d.cluster.DeleteVolume(v.Id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetProxySpecForAVolume returns nil as the fake driver has no proxy volumes
+func (d *Driver) GetProxySpecForAVolume(volume *torpedovolume.Volume) (*api.ProxySpec, error) {
+	return nil, nil
+}
+
+// InspectCurrentCluster returns the fake cluster
+func (d *Driver) InspectCurrentCluster() (*api.SdkClusterInspectCurrentResponse, error) {
+	return &api.SdkClusterInspectCurrentResponse{
+		Cluster: &api.StorageCluster{
+			Id:     DriverName,
+			Name:   DriverName,
+			Status: api.Status_STATUS_OK,
+		},
+	}, nil
+}
+
+// UpdateFBDANFSEndpoint is not supported by the fake driver
+func (d *Driver) UpdateFBDANFSEndpoint(volumeName string, newEndpoint string) error {
+	return &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "UpdateFBDANFSEndpoint()",
+	}
+}
+
+// ValidatePureFBDAMountSource is not supported by the fake driver
+func (d *Driver) ValidatePureFBDAMountSource(nodes []node.Node, vols []*torpedovolume.Volume, expectedIP string) error {
+	return &errors.ErrNotSupported{
+		Type:      "Function",
+		Operation: "ValidatePureFBDAMountSource()",
+	}
+}
+
+func init() {
+	torpedovolume.Register(DriverName, provisioners, New())
+}
@@ -0,0 +1,109 @@
+package fakepx
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/libopenstorage/openstorage/api"
+	"github.com/portworx/torpedo/drivers/node"
+	torpedovolume "github.com/portworx/torpedo/drivers/volume"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDriver(t *testing.T, nodeCount int) *Driver {
+	node.CleanupRegistry()
+	for i := 0; i < nodeCount; i++ {
+		require.NoError(t, node.AddNode(node.Node{
+			Name: fmt.Sprintf("node-%d", i),
+			Type: node.TypeWorker,
+		}))
+	}
+	t.Cleanup(node.CleanupRegistry)
+
+	d := New()
+	require.NoError(t, d.Init("fake", "fake", "", string(FakePxStorage), ""))
+	return d
+}
+
+func TestInitAndReplicas(t *testing.T) {
+	d := setupDriver(t, 3)
+
+	n, err := node.GetNodeByName("node-0")
+	require.NoError(t, err)
+	require.True(t, n.IsStorageDriverInstalled)
+	require.True(t, n.IsMetadataNode)
+	require.Len(t, n.StoragePools, DefaultPoolsPerNode)
+
+	id, err := d.CreateVolume("vol1", 10*gib, 2)
+	require.NoError(t, err)
+	vol := &torpedovolume.Volume{ID: id}
+	require.NoError(t, d.ValidateCreateVolume("vol1", map[string]string{replParameter: "2"}))
+
+	rs, err := d.GetReplicaSets(vol)
+	require.NoError(t, err)
+	require.Len(t, rs[0].Nodes, 2)
+
+	require.NoError(t, d.SetReplicationFactor(vol, 3, nil, nil, true))
+	require.NoError(t, d.WaitForReplicationToComplete(vol, 3, time.Second))
+	require.Error(t, d.SetReplicationFactor(vol, 1, nil, nil, true))
+	require.NoError(t, d.SetReplicationFactor(vol, 2, []string{"node-0"}, nil, true))
+	rs, err = d.GetReplicaSets(vol)
+	require.NoError(t, err)
+	require.NotContains(t, rs[0].Nodes, n.VolDriverNodeID)
+
+	require.NoError(t, d.StopDriver([]node.Node{n}, false, nil))
+	status, err := d.GetNodeStatus(n)
+	require.NoError(t, err)
+	require.Equal(t, api.Status_STATUS_OFFLINE, *status)
+	require.False(t, d.IsPxReadyOnNode(n))
+	require.NoError(t, d.StartDriver(n))
+	require.NoError(t, d.WaitDriverUpOnNode(n, time.Second))
+
+	require.NoError(t, d.DeleteVolume(id))
+	require.NoError(t, d.ValidateDeleteVolume(vol))
+}
+
+func TestMaintenanceAndPoolFaults(t *testing.T) {
+	d := setupDriver(t, 2)
+	n, err := node.GetNodeByName("node-1")
+	require.NoError(t, err)
+
+	require.NoError(t, d.EnterMaintenance(n))
+	inMaintenance, err := d.IsNodeInMaintenance(n)
+	require.NoError(t, err)
+	require.True(t, inMaintenance)
+	require.NoError(t, d.ExitMaintenance(n))
+
+	require.NoError(t, d.EnterPoolMaintenance(n))
+	poolsStatus, err := d.GetNodePoolsStatus(n)
+	require.NoError(t, err)
+	for _, status := range poolsStatus {
+		require.Equal(t, PoolStatusMaintenance, status)
+	}
+	require.NoError(t, d.ExitPoolMaintenance(n))
+
+	poolUUID := n.StoragePools[0].Uuid
+	require.NoError(t, d.SetPoolOffline(poolUUID, true))
+	require.Error(t, d.ValidateStoragePools())
+	require.Error(t, d.ExpandPool(poolUUID, api.SdkStoragePool_RESIZE_TYPE_AUTO, 200, true))
+	require.NoError(t, d.SetPoolOffline(poolUUID, false))
+
+	require.NoError(t, d.FailPoolResizeAt(poolUUID, 50))
+	require.Error(t, d.ExpandPool(poolUUID, api.SdkStoragePool_RESIZE_TYPE_ADD_DISK, 200, true))
+	sn, err := d.GetDriverNode(&n)
+	require.NoError(t, err)
+	require.Equal(t, 150*gib, sn.Pools[0].TotalSize)
+	require.Equal(t, api.SdkStoragePool_OPERATION_FAILED, sn.Pools[0].LastOperation.Status)
+
+	require.NoError(t, d.ExpandPool(poolUUID, api.SdkStoragePool_RESIZE_TYPE_ADD_DISK, 200, true))
+	sn, err = d.GetDriverNode(&n)
+	require.NoError(t, err)
+	require.Equal(t, 200*gib, sn.Pools[0].TotalSize)
+	require.Equal(t, api.SdkStoragePool_OPERATION_SUCCESSFUL, sn.Pools[0].LastOperation.Status)
+
+	scripted := fmt.Errorf("scripted failure")
+	d.Script(OpEnterMaintenance, scripted)
+	require.Equal(t, scripted, d.EnterMaintenance(n))
+	require.Equal(t, 2, d.Calls(OpEnterMaintenance))
+}
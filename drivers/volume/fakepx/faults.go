@@ -0,0 +1,53 @@
+package fakepx
+
+// Op identifies a driver operation whose outcome can be scripted
+type Op string
+
+const (
+	// OpCreateVolume identifies CreateVolume
+	OpCreateVolume Op = "CreateVolume"
+	// OpInspectVolume identifies InspectVolume
+	OpInspectVolume Op = "InspectVolume"
+	// OpDeleteVolume identifies DeleteVolume
+	OpDeleteVolume Op = "DeleteVolume"
+	// OpResizeVolume identifies ResizeVolume
+	OpResizeVolume Op = "ResizeVolume"
+	// OpCreateSnapshot identifies CreateSnapshot
+	OpCreateSnapshot Op = "CreateSnapshot"
+	// OpSetReplicationFactor identifies SetReplicationFactor
+	OpSetReplicationFactor Op = "SetReplicationFactor"
+	// OpStopDriver identifies StopDriver
+	OpStopDriver Op = "StopDriver"
+	// OpStartDriver identifies StartDriver
+	OpStartDriver Op = "StartDriver"
+	// OpRestartDriver identifies RestartDriver
+	OpRestartDriver Op = "RestartDriver"
+	// OpEnterMaintenance identifies EnterMaintenance
+	OpEnterMaintenance Op = "EnterMaintenance"
+	// OpExitMaintenance identifies ExitMaintenance
+	OpExitMaintenance Op = "ExitMaintenance"
+	// OpEnterPoolMaintenance identifies EnterPoolMaintenance
+	OpEnterPoolMaintenance Op = "EnterPoolMaintenance"
+	// OpExitPoolMaintenance identifies ExitPoolMaintenance
+	OpExitPoolMaintenance Op = "ExitPoolMaintenance"
+	// OpExpandPool identifies ExpandPool
+	OpExpandPool Op = "ExpandPool"
+	// OpDecommissionNode identifies DecommissionNode
+	OpDecommissionNode Op = "DecommissionNode"
+)
+
+// SetPoolOffline marks the pool offline, or back online, as if its drives went away
+func (d *Driver) SetPoolOffline(poolUUID string, offline bool) error {
+	status := PoolStatusOnline
+	if offline {
+		status = PoolStatusOffline
+	}
+	return d.cluster.SetPoolStatus(poolUUID, status)
+}
+
+// FailPoolResizeAt makes the next ExpandPool of the pool fail once the pool has
+// grown by the given percentage of the requested expansion, leaving the pool
+// partially expanded with a failed last operation
+func (d *Driver) FailPoolResizeAt(poolUUID string, percent int) error {
+	return d.cluster.FailPoolResizeAt(poolUUID, percent)
+}
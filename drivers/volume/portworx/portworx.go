@@ -51,6 +51,7 @@ import (
 	"github.com/portworx/torpedo/pkg/s3utils"
 	"github.com/portworx/torpedo/pkg/units"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -3447,7 +3448,7 @@ func (d *portworx) testAndSetEndpointUsingNodeIP(ip string) error {
 
 func (d *portworx) testAndSetEndpoint(endpoint string, sdkport, apiport int32) error {
 	pxEndpoint := net.JoinHostPort(endpoint, strconv.Itoa(int(sdkport)))
-	conn, err := grpc.Dial(pxEndpoint, grpc.WithInsecure())
+	conn, err := grpc.Dial(pxEndpoint, grpc.WithInsecure(), grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 	if err != nil {
 		return err
 	}
@@ -4385,7 +4386,7 @@ func (d *portworx) getClusterPairManagerByAddress(addr, token string) (api.OpenS
 		return nil, err
 	}
 	pxEndpoint := net.JoinHostPort(addr, strconv.Itoa(int(pxPort)))
-	conn, err := grpc.Dial(pxEndpoint, grpc.WithInsecure())
+	conn, err := grpc.Dial(pxEndpoint, grpc.WithInsecure(), grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 	if err != nil {
 		return nil, err
 	}
@@ -4416,7 +4417,7 @@ func (d *portworx) getNodeManagerByAddress(addr string) (api.OpenStorageNodeClie
 		return nil, err
 	}
 	pxEndpoint := net.JoinHostPort(addr, strconv.Itoa(int(pxPort)))
-	conn, err := grpc.Dial(pxEndpoint, grpc.WithInsecure())
+	conn, err := grpc.Dial(pxEndpoint, grpc.WithInsecure(), grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 	if err != nil {
 		return nil, err
 	}
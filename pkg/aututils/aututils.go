@@ -82,260 +82,84 @@ var (
 
 // PoolRuleByTotalSize returns an autopilot pool expand rule that uses total pool size
 func PoolRuleByTotalSize(total, scalePercentage uint64, expandType string, labelSelector map[string]string) apapi.AutopilotRule {
-	return apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pool-%s-total-%d", expandType, total),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Selector: apapi.RuleObjectSelector{
-				LabelSelector: meta_v1.LabelSelector{
-					MatchLabels: labelSelector,
-				},
-			},
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						Key:      PxPoolTotalCapacityMetric,
-						Operator: apapi.LabelSelectorOpLt,
-						Values:   []string{fmt.Sprintf("%d", total)},
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: StorageSpecAction,
-					Params: map[string]string{
-						RuleActionsScalePercentage: fmt.Sprintf("%d", scalePercentage),
-						RuleScaleType:              expandType,
-					},
-				},
-			},
-		},
-	}
+	return NewRuleBuilder(fmt.Sprintf("pool-%s-total-%d", expandType, total)).
+		Selector(labelSelector).
+		When(PxPoolTotalCapacityMetric, apapi.LabelSelectorOpLt, fmt.Sprintf("%d", total)).
+		ExpandPoolByPercentage(scalePercentage, expandType).
+		Build()
 }
 
 // PoolRuleFixedScaleSizeByTotalSize returns an autopilot pool expand rule that
 // uses total pool size and fixed scale size action
 func PoolRuleFixedScaleSizeByTotalSize(total uint64, scaleSize, expandType string, labelSelector map[string]string) apapi.AutopilotRule {
-	return apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pool-%s-fixedsize-%s-total-%d", expandType, strings.ToLower(scaleSize), total),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Selector: apapi.RuleObjectSelector{
-				LabelSelector: meta_v1.LabelSelector{
-					MatchLabels: labelSelector,
-				},
-			},
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						Key:      PxPoolTotalCapacityMetric,
-						Operator: apapi.LabelSelectorOpLt,
-						Values:   []string{fmt.Sprintf("%d", total)},
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: StorageSpecAction,
-					Params: map[string]string{
-						RuleActionsScaleSize: scaleSize,
-						RuleScaleType:        expandType,
-					},
-				},
-			},
-		},
-	}
+	return NewRuleBuilder(fmt.Sprintf("pool-%s-fixedsize-%s-total-%d", expandType, strings.ToLower(scaleSize), total)).
+		Selector(labelSelector).
+		When(PxPoolTotalCapacityMetric, apapi.LabelSelectorOpLt, fmt.Sprintf("%d", total)).
+		ExpandPoolBySize(scaleSize, expandType).
+		Build()
 }
 
 // PoolRuleByAvailableCapacity returns an autopilot pool expand rule that uses usage of pool size
 func PoolRuleByAvailableCapacity(usage, scalePercentage uint64, expandType string) apapi.AutopilotRule {
-	return apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pool-%s-available-%d", expandType, usage),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						Key:      PxPoolAvailableCapacityMetric,
-						Operator: apapi.LabelSelectorOpLt,
-						Values:   []string{fmt.Sprintf("%d", usage)},
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: StorageSpecAction,
-					Params: map[string]string{
-						RuleActionsScalePercentage: fmt.Sprintf("%d", scalePercentage),
-						RuleScaleType:              expandType,
-					},
-				},
-			},
-		},
-	}
+	return NewRuleBuilder(fmt.Sprintf("pool-%s-available-%d", expandType, usage)).
+		When(PxPoolAvailableCapacityMetric, apapi.LabelSelectorOpLt, fmt.Sprintf("%d", usage)).
+		ExpandPoolByPercentage(scalePercentage, expandType).
+		Build()
 }
 
 // PoolRuleFixedScaleSizeByAvailableCapacity returns an autopilot pool expand rule that
 // uses usage of pool size and fixed scale size action
 func PoolRuleFixedScaleSizeByAvailableCapacity(usage int, scaleSize, expandType string) apapi.AutopilotRule {
-	return apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pool-%s-fixedsize-%s-available-%d", expandType, strings.ToLower(scaleSize), usage),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						Key:      PxPoolAvailableCapacityMetric,
-						Operator: apapi.LabelSelectorOpLt,
-						Values:   []string{fmt.Sprintf("%d", usage)},
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: StorageSpecAction,
-					Params: map[string]string{
-						RuleActionsScaleSize: scaleSize,
-						RuleScaleType:        expandType,
-					},
-				},
-			},
-		},
-	}
+	return NewRuleBuilder(fmt.Sprintf("pool-%s-fixedsize-%s-available-%d", expandType, strings.ToLower(scaleSize), usage)).
+		When(PxPoolAvailableCapacityMetric, apapi.LabelSelectorOpLt, fmt.Sprintf("%d", usage)).
+		ExpandPoolBySize(scaleSize, expandType).
+		Build()
 }
 
 // PoolRuleRebalanceByProvisionedMean returns an autopilot pool rebalance rule that
 // uses provision deviation percentage alias key
 func PoolRuleRebalanceByProvisionedMean(values []string, approvalRequired bool) apapi.AutopilotRule {
-	apRuleObject := apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pvc-rebalance-provisioned-mean-%s", strings.Join(values, "-")),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						KeyAlias: RulePoolProvDeviationPercKeyAlias,
-						Operator: apapi.LabelSelectorOpNotInRange,
-						Values:   values,
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: RebalanceSpecAction,
-				},
-			},
-		},
-	}
-	if approvalRequired {
-		apRuleObject.Spec.Enforcement = apapi.ApprovalRequired
-	}
-	return apRuleObject
+	return NewRuleBuilder(fmt.Sprintf("pvc-rebalance-provisioned-mean-%s", strings.Join(values, "-"))).
+		WhenAlias(RulePoolProvDeviationPercKeyAlias, apapi.LabelSelectorOpNotInRange, values...).
+		Rebalance().
+		RequireApproval(approvalRequired).
+		Build()
 }
 
 // PoolRuleRebalanceByUsageMean returns an autopilot pool rebalance rule that
 // uses usage deviation percentage alias key
 func PoolRuleRebalanceByUsageMean(values []string, approvalRequired bool) apapi.AutopilotRule {
-	apRuleObject := apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pvc-rebalance-usage-mean-%s", strings.Join(values, "-")),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						KeyAlias: RulePoolUsageDeviationPercKeyAlias,
-						Operator: apapi.LabelSelectorOpNotInRange,
-						Values:   values,
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: RebalanceSpecAction,
-				},
-			},
-		},
-	}
-	if approvalRequired {
-		apRuleObject.Spec.Enforcement = apapi.ApprovalRequired
-	}
-	return apRuleObject
+	return NewRuleBuilder(fmt.Sprintf("pvc-rebalance-usage-mean-%s", strings.Join(values, "-"))).
+		WhenAlias(RulePoolUsageDeviationPercKeyAlias, apapi.LabelSelectorOpNotInRange, values...).
+		Rebalance().
+		RequireApproval(approvalRequired).
+		Build()
 }
 
 // PVCRuleByTotalSize resizes volume by its total size
 func PVCRuleByTotalSize(capacity int, scalePercentage int, maxSize string) apapi.AutopilotRule {
-	apRuleObject := apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pvc-total-%d-scale-%d", capacity, scalePercentage),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						Key:      PxVolumeTotalCapacityMetric,
-						Operator: apapi.LabelSelectorOpLt,
-						Values:   []string{fmt.Sprintf("%d", capacity)},
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: VolumeSpecAction,
-					Params: map[string]string{
-						RuleActionsScalePercentage: fmt.Sprintf("%d", scalePercentage),
-					},
-				},
-			},
-		},
-	}
+	name := fmt.Sprintf("pvc-total-%d-scale-%d", capacity, scalePercentage)
 	if maxSize != "" {
-		apRuleObject.Name = fmt.Sprintf("%s-maxsize-%s", apRuleObject.Name, strings.ToLower(maxSize))
-		for _, action := range apRuleObject.Spec.Actions {
-			action.Params[RuleMaxSize] = maxSize
-		}
+		name = fmt.Sprintf("%s-maxsize-%s", name, strings.ToLower(maxSize))
 	}
-	return apRuleObject
+	return NewRuleBuilder(name).
+		When(PxVolumeTotalCapacityMetric, apapi.LabelSelectorOpLt, fmt.Sprintf("%d", capacity)).
+		ResizeVolumeByPercentage(uint64(scalePercentage)).
+		MaxSize(maxSize).
+		Build()
 }
 
 // PVCRuleByUsageCapacity returns an autopilot pvc expand rule that uses usage of pvc size
 func PVCRuleByUsageCapacity(usagePercentage int, scalePercentage int, maxSize string) apapi.AutopilotRule {
-	apRuleObject := apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pvc-usage-%d-scale-%d", usagePercentage, scalePercentage),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						Key:      PxVolumeUsagePercentMetric,
-						Operator: apapi.LabelSelectorOpGt,
-						Values:   []string{fmt.Sprintf("%d", usagePercentage)},
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: VolumeSpecAction,
-					Params: map[string]string{
-						RuleActionsScalePercentage: fmt.Sprintf("%d", scalePercentage),
-					},
-				},
-			},
-		},
-	}
+	name := fmt.Sprintf("pvc-usage-%d-scale-%d", usagePercentage, scalePercentage)
 	if maxSize != "" {
-		apRuleObject.Name = fmt.Sprintf("%s-maxsize-%s", apRuleObject.Name, strings.ToLower(maxSize))
-		for _, action := range apRuleObject.Spec.Actions {
-			action.Params[RuleMaxSize] = maxSize
-		}
+		name = fmt.Sprintf("%s-maxsize-%s", name, strings.ToLower(maxSize))
 	}
-	return apRuleObject
+	return NewRuleBuilder(name).
+		When(PxVolumeUsagePercentMetric, apapi.LabelSelectorOpGt, fmt.Sprintf("%d", usagePercentage)).
+		ResizeVolumeByPercentage(uint64(scalePercentage)).
+		MaxSize(maxSize).
+		Build()
 }
 
 // WaitForAutopilotEvent waits for event which contains a reason and messages for given autopilot rule
@@ -418,34 +242,10 @@ func WaitForActionApprovalsObjects(namespace, name string) error {
 // PoolRuleRebalanceAbsolute returns an autopilot pool rebalance rule that
 // uses provisioned and used stats deviation percentage alias key
 func PoolRuleRebalanceAbsolute(provisionedValLimit, usedValLimit int, approvalRequired bool) apapi.AutopilotRule {
-	apRuleObject := apapi.AutopilotRule{
-		ObjectMeta: meta_v1.ObjectMeta{
-			Name: fmt.Sprintf("pool-rebalance-absolute-prov-%d-used-%d", provisionedValLimit, usedValLimit),
-		},
-		Spec: apapi.AutopilotRuleSpec{
-			Conditions: apapi.RuleConditions{
-				Expressions: []*apapi.LabelSelectorRequirement{
-					{
-						Key:      PXPoolProvisionedSpaceMetric,
-						Operator: apapi.LabelSelectorOpGt,
-						Values:   []string{fmt.Sprintf("%d", provisionedValLimit)},
-					},
-					{
-						Key:      PXPoolUsedSpaceMetric,
-						Operator: apapi.LabelSelectorOpGt,
-						Values:   []string{fmt.Sprintf("%d", usedValLimit)},
-					},
-				},
-			},
-			Actions: []*apapi.RuleAction{
-				{
-					Name: RebalanceSpecAction,
-				},
-			},
-		},
-	}
-	if approvalRequired {
-		apRuleObject.Spec.Enforcement = apapi.ApprovalRequired
-	}
-	return apRuleObject
+	return NewRuleBuilder(fmt.Sprintf("pool-rebalance-absolute-prov-%d-used-%d", provisionedValLimit, usedValLimit)).
+		When(PXPoolProvisionedSpaceMetric, apapi.LabelSelectorOpGt, fmt.Sprintf("%d", provisionedValLimit)).
+		When(PXPoolUsedSpaceMetric, apapi.LabelSelectorOpGt, fmt.Sprintf("%d", usedValLimit)).
+		Rebalance().
+		RequireApproval(approvalRequired).
+		Build()
 }
@@ -0,0 +1,158 @@
+package aututils
+
+import (
+	"testing"
+	"time"
+
+	apapi "github.com/libopenstorage/autopilot-api/pkg/apis/autopilot/v1alpha1"
+	"github.com/portworx/torpedo/pkg/units"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleBuilder(t *testing.T) {
+	rule := PVCRuleByUsageCapacity(50, 100, "20Gi")
+	require.Equal(t, "pvc-usage-50-scale-100-maxsize-20gi", rule.Name)
+	require.Equal(t, []*apapi.LabelSelectorRequirement{
+		{Key: PxVolumeUsagePercentMetric, Operator: apapi.LabelSelectorOpGt, Values: []string{"50"}},
+	}, rule.Spec.Conditions.Expressions)
+	require.Equal(t, []*apapi.RuleAction{
+		{Name: VolumeSpecAction, Params: map[string]string{RuleActionsScalePercentage: "100", RuleMaxSize: "20Gi"}},
+	}, rule.Spec.Actions)
+
+	rule = NewRuleBuilder("rebalance").
+		Selector(map[string]string{"node-type": "storage"}).
+		NamespaceSelector(map[string]string{"type": "db"}).
+		WhenAlias(RulePoolProvDeviationPercKeyAlias, apapi.LabelSelectorOpNotInRange, "-20", "20").
+		When(PXPoolUsedSpaceMetric, apapi.LabelSelectorOpGtEq, "70").
+		RequiredMatches(1).
+		For(2 * time.Minute).
+		PollInterval(10 * time.Second).
+		CoolDown(5 * time.Minute).
+		Weight(10).
+		Rebalance().
+		MaxSize("1Ti").
+		RequireApproval(true).
+		Build()
+	require.Equal(t, map[string]string{"node-type": "storage"}, rule.Spec.Selector.MatchLabels)
+	require.Equal(t, map[string]string{"type": "db"}, rule.Spec.NamespaceSelector.MatchLabels)
+	require.Len(t, rule.Spec.Conditions.Expressions, 2)
+	require.Equal(t, uint64(1), rule.Spec.Conditions.RequiredMatches)
+	require.Equal(t, int64(120), rule.Spec.Conditions.For)
+	require.Equal(t, int64(10), rule.Spec.PollInterval)
+	require.Equal(t, int64(300), rule.Spec.ActionsCoolDownPeriod)
+	require.Equal(t, int64(10), rule.Spec.Weight)
+	require.Equal(t, apapi.ApprovalRequired, rule.Spec.Enforcement)
+	require.Equal(t, []*apapi.RuleAction{{Name: RebalanceSpecAction}}, rule.Spec.Actions)
+}
+
+func TestSimulateVolumeRule(t *testing.T) {
+	simulation, err := SimulateVolumeRule(PVCRuleByUsageCapacity(50, 50, ""), VolumeState{Size: 10 * units.GB, UsedSize: 8 * units.GB})
+	require.NoError(t, err)
+	require.Equal(t, []SimulatedAction{
+		{Name: VolumeSpecAction, SizeBefore: 10 * units.GB, SizeAfter: 15 * units.GB},
+		{Name: VolumeSpecAction, SizeBefore: 15 * units.GB, SizeAfter: 22500 * units.MB},
+	}, simulation.Actions)
+	require.Equal(t, uint64(22500*units.MB), simulation.FinalSize)
+	require.False(t, simulation.MaxSizeReached)
+
+	simulation, err = SimulateVolumeRule(PVCRuleByUsageCapacity(50, 50, "20G"), VolumeState{Size: 10 * units.GB, UsedSize: 15 * units.GB})
+	require.NoError(t, err)
+	require.Len(t, simulation.Actions, 2)
+	require.Equal(t, uint64(20*units.GB), simulation.FinalSize)
+	require.True(t, simulation.MaxSizeReached)
+
+	simulation, err = SimulateVolumeRule(PVCRuleByTotalSize(20, 100, ""), VolumeState{Size: 30 * units.GB})
+	require.NoError(t, err)
+	require.Empty(t, simulation.Actions)
+	require.Equal(t, uint64(30*units.GB), simulation.FinalSize)
+
+	rule := NewRuleBuilder("pvc-for").
+		When(PxVolumeUsagePercentMetric, apapi.LabelSelectorOpGt, "50").
+		ResizeVolumeByPercentage(100).
+		For(time.Minute).
+		CoolDown(5 * time.Minute).
+		RequireApproval(true).
+		Build()
+	simulation, err = SimulateVolumeRule(rule, VolumeState{Size: 10 * units.GB, UsedSize: 16 * units.GB})
+	require.NoError(t, err)
+	require.Len(t, simulation.Actions, 2)
+	require.True(t, simulation.Actions[0].ApprovalRequired)
+	require.Equal(t, 12*time.Minute, simulation.MinDuration)
+
+	_, err = SimulateVolumeRule(PVCRuleByUsageCapacity(50, 0, ""), VolumeState{Size: 10 * units.GB, UsedSize: 8 * units.GB})
+	require.Error(t, err)
+	_, err = SimulateVolumeRule(PoolRuleByAvailableCapacity(50, 50, RuleScaleTypeAddDisk), VolumeState{Size: 10 * units.GB})
+	require.Error(t, err)
+}
+
+func TestSimulatePoolRule(t *testing.T) {
+	pool := PoolState{TotalSize: 64 * units.GiB, UsedSize: 40 * units.GiB, Disks: 2}
+	simulation, err := SimulatePoolRule(PoolRuleByAvailableCapacity(50, 50, RuleScaleTypeAddDisk), pool)
+	require.NoError(t, err)
+	require.Equal(t, []SimulatedAction{
+		{Name: StorageSpecAction, SizeBefore: 64 * units.GiB, SizeAfter: 96 * units.GiB, DisksAdded: 1},
+	}, simulation.Actions)
+	require.Equal(t, 3, simulation.FinalDisks)
+
+	simulation, err = SimulatePoolRule(PoolRuleFixedScaleSizeByAvailableCapacity(50, "16Gi", RuleScaleTypeResizeDisk), pool)
+	require.NoError(t, err)
+	require.Len(t, simulation.Actions, 1)
+	require.Equal(t, uint64(80*units.GiB), simulation.FinalSize)
+	require.Equal(t, 2, simulation.FinalDisks)
+
+	simulation, err = SimulatePoolRule(PoolRuleByTotalSize(200, 50, RuleScaleTypeResizeDisk, nil), pool)
+	require.NoError(t, err)
+	require.Len(t, simulation.Actions, 3)
+	require.Equal(t, uint64(216*units.GiB), simulation.FinalSize)
+
+	rule := NewRuleBuilder("pool-max").
+		When(PxPoolTotalCapacityMetric, apapi.LabelSelectorOpLt, "200").
+		ExpandPoolByPercentage(50, RuleScaleTypeResizeDisk).
+		MaxSize("100Gi").
+		Build()
+	simulation, err = SimulatePoolRule(rule, pool)
+	require.NoError(t, err)
+	require.Len(t, simulation.Actions, 2)
+	require.Equal(t, uint64(100*units.GiB), simulation.FinalSize)
+	require.True(t, simulation.MaxSizeReached)
+
+	pool.ProvDeviationPerc = 35
+	simulation, err = SimulatePoolRule(PoolRuleRebalanceByProvisionedMean([]string{"-20", "20"}, true), pool)
+	require.NoError(t, err)
+	require.Equal(t, []SimulatedAction{
+		{Name: RebalanceSpecAction, SizeBefore: 64 * units.GiB, SizeAfter: 64 * units.GiB, ApprovalRequired: true},
+	}, simulation.Actions)
+
+	pool.ProvisionedSize = 96 * units.GiB
+	simulation, err = SimulatePoolRule(PoolRuleRebalanceAbsolute(120, 70, false), pool)
+	require.NoError(t, err)
+	require.Empty(t, simulation.Actions)
+}
+
+func TestConditionMatches(t *testing.T) {
+	for _, tc := range []struct {
+		operator apapi.LabelSelectorOperator
+		values   []string
+		value    float64
+		match    bool
+	}{
+		{apapi.LabelSelectorOpLt, []string{"50"}, 49, true},
+		{apapi.LabelSelectorOpLt, []string{"50"}, 50, false},
+		{apapi.LabelSelectorOpLtEq, []string{"50"}, 50, true},
+		{apapi.LabelSelectorOpGt, []string{"50"}, 50, false},
+		{apapi.LabelSelectorOpGtEq, []string{"50"}, 50, true},
+		{apapi.LabelSelectorOpIn, []string{"10", "20"}, 20, true},
+		{apapi.LabelSelectorOpNotIn, []string{"10", "20"}, 20, false},
+		{apapi.LabelSelectorOpInRange, []string{"-20", "20"}, -20, true},
+		{apapi.LabelSelectorOpNotInRange, []string{"-20", "20"}, 21, true},
+		{apapi.LabelSelectorOpExists, nil, 0, true},
+		{apapi.LabelSelectorOpDoesNotExist, nil, 0, false},
+	} {
+		match, err := conditionMatches(tc.value, &apapi.LabelSelectorRequirement{Operator: tc.operator, Values: tc.values})
+		require.NoError(t, err)
+		require.Equal(t, tc.match, match, "%v %s %v", tc.value, tc.operator, tc.values)
+	}
+
+	_, err := conditionMatches(0, &apapi.LabelSelectorRequirement{Operator: apapi.LabelSelectorOpInRange, Values: []string{"1"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package aututils
+
+import (
+	"fmt"
+	"time"
+
+	apapi "github.com/libopenstorage/autopilot-api/pkg/apis/autopilot/v1alpha1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RuleBuilder composes an autopilot rule from its selectors, conditions, actions and enforcement
+//
+//	rule := aututils.NewRuleBuilder("pool-expand").
+//		When(aututils.PxPoolAvailableCapacityMetric, apapi.LabelSelectorOpLt, "50").
+//		ExpandPoolByPercentage(50, aututils.RuleScaleTypeAddDisk).
+//		MaxSize("400Gi").
+//		RequireApproval(true).
+//		Build()
+type RuleBuilder struct {
+	rule    apapi.AutopilotRule
+	maxSize string
+}
+
+// NewRuleBuilder returns a builder of an autopilot rule with the given name
+func NewRuleBuilder(name string) *RuleBuilder {
+	return &RuleBuilder{
+		rule: apapi.AutopilotRule{
+			ObjectMeta: meta_v1.ObjectMeta{
+				Name: name,
+			},
+		},
+	}
+}
+
+// Selector selects the objects of the rule by their labels
+func (b *RuleBuilder) Selector(labels map[string]string) *RuleBuilder {
+	b.rule.Spec.Selector.MatchLabels = labels
+	return b
+}
+
+// NamespaceSelector selects the namespaces of the rule objects by their labels
+func (b *RuleBuilder) NamespaceSelector(labels map[string]string) *RuleBuilder {
+	b.rule.Spec.NamespaceSelector.MatchLabels = labels
+	return b
+}
+
+// When adds a condition on the given metric, e.g. PxPoolAvailableCapacityMetric
+func (b *RuleBuilder) When(key string, operator apapi.LabelSelectorOperator, values ...string) *RuleBuilder {
+	b.rule.Spec.Conditions.Expressions = append(b.rule.Spec.Conditions.Expressions, &apapi.LabelSelectorRequirement{
+		Key:      key,
+		Operator: operator,
+		Values:   values,
+	})
+	return b
+}
+
+// WhenAlias adds a condition on the given key alias, e.g. RulePoolProvDeviationPercKeyAlias
+func (b *RuleBuilder) WhenAlias(keyAlias string, operator apapi.LabelSelectorOperator, values ...string) *RuleBuilder {
+	b.rule.Spec.Conditions.Expressions = append(b.rule.Spec.Conditions.Expressions, &apapi.LabelSelectorRequirement{
+		KeyAlias: keyAlias,
+		Operator: operator,
+		Values:   values,
+	})
+	return b
+}
+
+// For sets the duration the conditions must hold before the rule is triggered
+func (b *RuleBuilder) For(duration time.Duration) *RuleBuilder {
+	b.rule.Spec.Conditions.For = int64(duration.Seconds())
+	return b
+}
+
+// RequiredMatches sets the number of conditions that must match to trigger the rule, all of them if not set
+func (b *RuleBuilder) RequiredMatches(matches uint64) *RuleBuilder {
+	b.rule.Spec.Conditions.RequiredMatches = matches
+	return b
+}
+
+// PollInterval sets the interval the conditions are queried at
+func (b *RuleBuilder) PollInterval(interval time.Duration) *RuleBuilder {
+	b.rule.Spec.PollInterval = int64(interval.Seconds())
+	return b
+}
+
+// CoolDown sets the period autopilot does not re-trigger the actions for once they have been executed
+func (b *RuleBuilder) CoolDown(period time.Duration) *RuleBuilder {
+	b.rule.Spec.ActionsCoolDownPeriod = int64(period.Seconds())
+	return b
+}
+
+// Weight sets the weight that breaks the tie with conflicting rules
+func (b *RuleBuilder) Weight(weight int64) *RuleBuilder {
+	b.rule.Spec.Weight = weight
+	return b
+}
+
+// RequireApproval makes the actions of the rule wait for an action approval
+func (b *RuleBuilder) RequireApproval(approvalRequired bool) *RuleBuilder {
+	if approvalRequired {
+		b.rule.Spec.Enforcement = apapi.ApprovalRequired
+	} else {
+		b.rule.Spec.Enforcement = ""
+	}
+	return b
+}
+
+// ExpandPoolByPercentage adds a pool expand action that scales the pool by a percentage of its size
+func (b *RuleBuilder) ExpandPoolByPercentage(scalePercentage uint64, scaleType string) *RuleBuilder {
+	return b.Action(StorageSpecAction, map[string]string{
+		RuleActionsScalePercentage: fmt.Sprintf("%d", scalePercentage),
+		RuleScaleType:              scaleType,
+	})
+}
+
+// ExpandPoolBySize adds a pool expand action that scales the pool by a fixed size, e.g. 32Gi
+func (b *RuleBuilder) ExpandPoolBySize(scaleSize, scaleType string) *RuleBuilder {
+	return b.Action(StorageSpecAction, map[string]string{
+		RuleActionsScaleSize: scaleSize,
+		RuleScaleType:        scaleType,
+	})
+}
+
+// ResizeVolumeByPercentage adds a volume resize action that scales the volume by a percentage of its size
+func (b *RuleBuilder) ResizeVolumeByPercentage(scalePercentage uint64) *RuleBuilder {
+	return b.Action(VolumeSpecAction, map[string]string{
+		RuleActionsScalePercentage: fmt.Sprintf("%d", scalePercentage),
+	})
+}
+
+// Rebalance adds a pool rebalance action
+func (b *RuleBuilder) Rebalance() *RuleBuilder {
+	return b.Action(RebalanceSpecAction, nil)
+}
+
+// Action adds an action with the given name and params
+func (b *RuleBuilder) Action(name string, params map[string]string) *RuleBuilder {
+	b.rule.Spec.Actions = append(b.rule.Spec.Actions, &apapi.RuleAction{
+		Name:   name,
+		Params: params,
+	})
+	return b
+}
+
+// MaxSize sets the size the expand and resize actions of the rule do not scale beyond, e.g. 100Gi
+func (b *RuleBuilder) MaxSize(maxSize string) *RuleBuilder {
+	b.maxSize = maxSize
+	return b
+}
+
+// Build returns the autopilot rule
+func (b *RuleBuilder) Build() apapi.AutopilotRule {
+	if b.maxSize != "" {
+		for _, action := range b.rule.Spec.Actions {
+			if action.Name != StorageSpecAction && action.Name != VolumeSpecAction {
+				continue
+			}
+			if action.Params == nil {
+				action.Params = make(map[string]string)
+			}
+			action.Params[RuleMaxSize] = b.maxSize
+		}
+	}
+	return b.rule
+}
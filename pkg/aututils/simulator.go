@@ -0,0 +1,395 @@
+package aututils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	apapi "github.com/libopenstorage/autopilot-api/pkg/apis/autopilot/v1alpha1"
+	tp_errors "github.com/portworx/torpedo/pkg/errors"
+	"github.com/portworx/torpedo/pkg/units"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// maxSimulatedActions is the number of triggers after which a rule that keeps triggering is considered unbounded
+const maxSimulatedActions = 100
+
+// PoolState is the state of a storage pool a rule is simulated on
+type PoolState struct {
+	// TotalSize is the size of the pool in bytes
+	TotalSize uint64
+	// UsedSize is the used space of the pool in bytes, including the space PX reserves on the pool
+	UsedSize uint64
+	// ProvisionedSize is the space provisioned to the volumes of the pool in bytes
+	ProvisionedSize uint64
+	// Disks is the number of disks of the pool, all of the same size
+	Disks int
+	// ProvDeviationPerc is the deviation of the provisioned space of the pool from the cluster mean
+	ProvDeviationPerc float64
+	// UsageDeviationPerc is the deviation of the used space of the pool from the cluster mean
+	UsageDeviationPerc float64
+}
+
+// VolumeState is the state of a volume a rule is simulated on
+type VolumeState struct {
+	// Size is the size of the volume in bytes
+	Size uint64
+	// UsedSize is the used space of the volume in bytes
+	UsedSize uint64
+}
+
+// SimulatedAction is an action autopilot is expected to take
+type SimulatedAction struct {
+	// Name is the name of the rule action, e.g. StorageSpecAction
+	Name string
+	// SizeBefore and SizeAfter are the sizes of the object before and after the action
+	SizeBefore uint64
+	SizeAfter  uint64
+	// DisksAdded is the number of disks added by an add-disk pool expand
+	DisksAdded int
+	// ApprovalRequired is set if the action waits for an action approval
+	ApprovalRequired bool
+}
+
+// Simulation is the expected outcome of a rule
+type Simulation struct {
+	// Actions are the actions autopilot is expected to take, in order
+	Actions []SimulatedAction
+	// FinalSize is the size of the object once the conditions of the rule no longer match
+	FinalSize uint64
+	// FinalDisks is the number of disks of a pool once the conditions of the rule no longer match
+	FinalDisks int
+	// MaxSizeReached is set if the actions stopped at the max size of the rule while the conditions still match
+	MaxSizeReached bool
+	// MinDuration is the least time the actions take, from the condition duration and cooldown period of the rule
+	MinDuration time.Duration
+}
+
+// simulatedObject is a pool or a volume a rule is simulated on
+type simulatedObject interface {
+	// metric returns the value of the metric of the condition
+	metric(expression *apapi.LabelSelectorRequirement) (float64, error)
+	// size returns the current size of the object
+	size() uint64
+	// apply applies the action to the object, it returns true once the object reached the max size of the action
+	apply(action *apapi.RuleAction, step *SimulatedAction) (bool, error)
+}
+
+// SimulatePoolRule predicts the actions the rule takes on the pool and the final pool size.
+// A rebalance is simulated as a single action since its outcome depends on the other pools of the cluster.
+func SimulatePoolRule(apRule apapi.AutopilotRule, pool PoolState) (*Simulation, error) {
+	if pool.TotalSize == 0 {
+		return nil, fmt.Errorf("pool of rule [%s] has no size", apRule.Name)
+	}
+	p := &poolObject{pool}
+	simulation, err := simulate(apRule, p)
+	if err != nil {
+		return nil, err
+	}
+	simulation.FinalDisks = p.Disks
+	return simulation, nil
+}
+
+// SimulateVolumeRule predicts the actions the rule takes on the volume and the final volume size
+func SimulateVolumeRule(apRule apapi.AutopilotRule, volume VolumeState) (*Simulation, error) {
+	if volume.Size == 0 {
+		return nil, fmt.Errorf("volume of rule [%s] has no size", apRule.Name)
+	}
+	return simulate(apRule, &volumeObject{volume})
+}
+
+func simulate(apRule apapi.AutopilotRule, object simulatedObject) (*Simulation, error) {
+	if len(apRule.Spec.Actions) == 0 {
+		return nil, fmt.Errorf("rule [%s] has no actions", apRule.Name)
+	}
+	simulation := &Simulation{}
+	triggerDuration := time.Duration(apRule.Spec.Conditions.For+apRule.Spec.ActionsCoolDownPeriod) * time.Second
+	var maxSizeReached, rebalanced bool
+	for triggers := 0; ; triggers++ {
+		triggered, err := conditionsMatch(apRule.Spec.Conditions, object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate conditions of rule [%s]: %v", apRule.Name, err)
+		}
+		if !triggered || rebalanced {
+			break
+		}
+		if maxSizeReached {
+			simulation.MaxSizeReached = true
+			break
+		}
+		if triggers >= maxSimulatedActions {
+			return nil, fmt.Errorf("rule [%s] still triggers after %d actions", apRule.Name, triggers)
+		}
+
+		actions := len(simulation.Actions)
+		for _, action := range apRule.Spec.Actions {
+			step := SimulatedAction{
+				Name:             action.Name,
+				SizeBefore:       object.size(),
+				ApprovalRequired: apRule.Spec.Enforcement == apapi.ApprovalRequired,
+			}
+			atMaxSize, err := object.apply(action, &step)
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply action [%s] of rule [%s]: %v", action.Name, apRule.Name, err)
+			}
+			step.SizeAfter = object.size()
+			if step.SizeAfter != step.SizeBefore || action.Name == RebalanceSpecAction {
+				simulation.Actions = append(simulation.Actions, step)
+			}
+			maxSizeReached = maxSizeReached || atMaxSize
+			rebalanced = rebalanced || action.Name == RebalanceSpecAction
+		}
+		if len(simulation.Actions) > actions {
+			simulation.MinDuration += triggerDuration
+		}
+	}
+	simulation.FinalSize = object.size()
+	return simulation, nil
+}
+
+// conditionsMatch returns true if the number of matching expressions reaches the required matches of the conditions
+func conditionsMatch(conditions apapi.RuleConditions, object simulatedObject) (bool, error) {
+	if len(conditions.Expressions) == 0 {
+		return false, fmt.Errorf("no condition expressions")
+	}
+	var matches uint64
+	for _, expression := range conditions.Expressions {
+		value, err := object.metric(expression)
+		if err != nil {
+			return false, err
+		}
+		match, err := conditionMatches(value, expression)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			matches++
+		}
+	}
+	requiredMatches := conditions.RequiredMatches
+	if requiredMatches == 0 {
+		requiredMatches = uint64(len(conditions.Expressions))
+	}
+	return matches >= requiredMatches, nil
+}
+
+// conditionMatches returns true if the metric value satisfies the operator of the expression
+func conditionMatches(value float64, expression *apapi.LabelSelectorRequirement) (bool, error) {
+	var values []float64
+	for _, v := range expression.Values {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid value [%s] of condition [%s]: %v", v, expression.Operator, err)
+		}
+		values = append(values, f)
+	}
+	requireValues := func(n int) error {
+		if len(values) < n {
+			return fmt.Errorf("condition [%s] requires %d values, got %d", expression.Operator, n, len(values))
+		}
+		return nil
+	}
+
+	switch expression.Operator {
+	case apapi.LabelSelectorOpExists:
+		return true, nil
+	case apapi.LabelSelectorOpDoesNotExist:
+		return false, nil
+	case apapi.LabelSelectorOpIn, apapi.LabelSelectorOpNotIn:
+		if err := requireValues(1); err != nil {
+			return false, err
+		}
+		in := false
+		for _, v := range values {
+			in = in || v == value
+		}
+		return in == (expression.Operator == apapi.LabelSelectorOpIn), nil
+	case apapi.LabelSelectorOpGt, apapi.LabelSelectorOpGtEq, apapi.LabelSelectorOpLt, apapi.LabelSelectorOpLtEq:
+		if err := requireValues(1); err != nil {
+			return false, err
+		}
+		switch expression.Operator {
+		case apapi.LabelSelectorOpGt:
+			return value > values[0], nil
+		case apapi.LabelSelectorOpGtEq:
+			return value >= values[0], nil
+		case apapi.LabelSelectorOpLt:
+			return value < values[0], nil
+		default:
+			return value <= values[0], nil
+		}
+	case apapi.LabelSelectorOpInRange, apapi.LabelSelectorOpNotInRange:
+		if err := requireValues(2); err != nil {
+			return false, err
+		}
+		inRange := value >= values[0] && value <= values[1]
+		return inRange == (expression.Operator == apapi.LabelSelectorOpInRange), nil
+	default:
+		return false, &tp_errors.ErrNotSupported{
+			Type:      string(expression.Operator),
+			Operation: "Condition Expression Operator",
+		}
+	}
+}
+
+type poolObject struct {
+	PoolState
+}
+
+func (p *poolObject) metric(expression *apapi.LabelSelectorRequirement) (float64, error) {
+	total := float64(p.TotalSize)
+	switch {
+	case expression.Key == PxPoolAvailableCapacityMetric:
+		return (total - float64(p.UsedSize)) * 100 / total, nil
+	case expression.Key == PxPoolTotalCapacityMetric:
+		return total / units.GiB, nil
+	case expression.Key == PXPoolProvisionedSpaceMetric:
+		return float64(p.ProvisionedSize) * 100 / total, nil
+	case expression.Key == PXPoolUsedSpaceMetric:
+		return float64(p.UsedSize) * 100 / total, nil
+	case expression.Key == "" && expression.KeyAlias == RulePoolProvDeviationPercKeyAlias:
+		return p.ProvDeviationPerc, nil
+	case expression.Key == "" && expression.KeyAlias == RulePoolUsageDeviationPercKeyAlias:
+		return p.UsageDeviationPerc, nil
+	}
+	return 0, &tp_errors.ErrNotSupported{
+		Type:      expression.Key + expression.KeyAlias,
+		Operation: "Pool Condition Expression Key",
+	}
+}
+
+func (p *poolObject) size() uint64 {
+	return p.TotalSize
+}
+
+func (p *poolObject) apply(action *apapi.RuleAction, step *SimulatedAction) (bool, error) {
+	switch action.Name {
+	case RebalanceSpecAction:
+		return false, nil
+	case StorageSpecAction:
+	default:
+		return false, &tp_errors.ErrNotSupported{
+			Type:      action.Name,
+			Operation: "Pool action",
+		}
+	}
+
+	maxSize, err := ruleMaxSize(action.Params)
+	if err != nil {
+		return false, err
+	}
+	if maxSize != 0 && p.TotalSize >= maxSize {
+		return true, nil
+	}
+	scaleSize, err := ruleScaleSize(action.Params, p.TotalSize)
+	if err != nil {
+		return false, err
+	}
+
+	switch scaleType := action.Params[RuleScaleType]; scaleType {
+	case RuleScaleTypeAddDisk:
+		if p.Disks == 0 {
+			return false, fmt.Errorf("pool has no disks to add disks of the same size")
+		}
+		diskSize := p.TotalSize / uint64(p.Disks)
+		disks := uint64(math.Ceil(float64(scaleSize) / float64(diskSize)))
+		p.TotalSize += disks * diskSize
+		p.Disks += int(disks)
+		step.DisksAdded = int(disks)
+	case RuleScaleTypeResizeDisk:
+		p.TotalSize += scaleSize
+		if maxSize != 0 && p.TotalSize > maxSize {
+			p.TotalSize = maxSize
+		}
+	default:
+		return false, &tp_errors.ErrNotSupported{
+			Type:      scaleType,
+			Operation: "Pool expand scale type",
+		}
+	}
+	return maxSize != 0 && p.TotalSize >= maxSize, nil
+}
+
+type volumeObject struct {
+	VolumeState
+}
+
+func (v *volumeObject) metric(expression *apapi.LabelSelectorRequirement) (float64, error) {
+	switch expression.Key {
+	case PxVolumeUsagePercentMetric:
+		return float64(v.UsedSize) * 100 / float64(v.Size), nil
+	case PxVolumeTotalCapacityMetric:
+		return float64(v.Size) / units.GB, nil
+	}
+	return 0, &tp_errors.ErrNotSupported{
+		Type:      expression.Key + expression.KeyAlias,
+		Operation: "Volume Condition Expression Key",
+	}
+}
+
+func (v *volumeObject) size() uint64 {
+	return v.Size
+}
+
+func (v *volumeObject) apply(action *apapi.RuleAction, step *SimulatedAction) (bool, error) {
+	if action.Name != VolumeSpecAction {
+		return false, &tp_errors.ErrNotSupported{
+			Type:      action.Name,
+			Operation: "Volume action",
+		}
+	}
+	maxSize, err := ruleMaxSize(action.Params)
+	if err != nil {
+		return false, err
+	}
+	if maxSize != 0 && v.Size >= maxSize {
+		return true, nil
+	}
+	scaleSize, err := ruleScaleSize(action.Params, v.Size)
+	if err != nil {
+		return false, err
+	}
+	v.Size += scaleSize
+	if maxSize != 0 && v.Size >= maxSize {
+		v.Size = maxSize
+		return true, nil
+	}
+	return false, nil
+}
+
+// ruleScaleSize returns the size the action scales an object of the given size by
+func ruleScaleSize(params map[string]string, size uint64) (uint64, error) {
+	if scalePercentage, ok := params[RuleActionsScalePercentage]; ok {
+		percentage, err := strconv.ParseUint(scalePercentage, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s [%s]: %v", RuleActionsScalePercentage, scalePercentage, err)
+		}
+		return size * percentage / 100, nil
+	}
+	if scaleSize, ok := params[RuleActionsScaleSize]; ok {
+		return parseSize(RuleActionsScaleSize, scaleSize)
+	}
+	return 0, fmt.Errorf("action has neither %s nor %s", RuleActionsScalePercentage, RuleActionsScaleSize)
+}
+
+// ruleMaxSize returns the max size of the action, 0 if not set
+func ruleMaxSize(params map[string]string) (uint64, error) {
+	maxSize, ok := params[RuleMaxSize]
+	if !ok {
+		return 0, nil
+	}
+	return parseSize(RuleMaxSize, maxSize)
+}
+
+// parseSize parses a size in bytes or a quantity, e.g. 10Gi
+func parseSize(param, value string) (uint64, error) {
+	if size, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return size, nil
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s [%s]: %v", param, value, err)
+	}
+	return uint64(quantity.Value()), nil
+}
@@ -0,0 +1,297 @@
+// Package blastradius keeps concurrent disruptions of a cluster within a budget,
+// for e.g. at most one kvdb member or less than a quorum of storage nodes down
+// at once. Triggers claim what they disrupt before running and release it after.
+// A claim which does not fit is rejected, the manager does not queue claims: the
+// caller decides whether to skip the trigger or to retry later.
+package blastradius
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Kind is a kind of cluster resource a trigger can disrupt
+type Kind string
+
+const (
+	// Nodes are any nodes of the cluster
+	Nodes Kind = "nodes"
+	// StorageNodes are nodes running the storage driver
+	StorageNodes Kind = "storageNodes"
+	// KvdbMembers are the members of the internal kvdb
+	KvdbMembers Kind = "kvdbMembers"
+	// Pools are storage pools
+	Pools Kind = "pools"
+	// Volumes are storage volumes
+	Volumes Kind = "volumes"
+)
+
+// Kinds are the kinds of resources a budget can limit
+var Kinds = []Kind{Nodes, StorageNodes, KvdbMembers, Pools, Volumes}
+
+// Claim is what a trigger disrupts while running
+type Claim struct {
+	Trigger string
+	// Units are the number of resources disrupted by kind, for e.g. 2 storage
+	// nodes, when the trigger picks which ones while running
+	Units map[Kind]int
+	// Resources are the named resources disrupted by kind, when known upfront.
+	// Each counts as a unit on top of Units.
+	Resources map[Kind][]string
+	// Locks are mutual exclusion locks. Claims sharing a lock never overlap.
+	Locks []string
+	// Exclusive claims never overlap with any other claim
+	Exclusive bool
+}
+
+func (c Claim) units(kind Kind) int {
+	return c.Units[kind] + len(c.Resources[kind])
+}
+
+// IsEmpty returns true if the claim disrupts nothing and takes no lock
+func (c Claim) IsEmpty() bool {
+	for kind := range c.Units {
+		if c.units(kind) > 0 {
+			return false
+		}
+	}
+	for kind := range c.Resources {
+		if c.units(kind) > 0 {
+			return false
+		}
+	}
+	return len(c.Locks) == 0 && !c.Exclusive
+}
+
+// Lease is an acquired claim, to be released once the trigger is done
+type Lease struct {
+	id    int
+	Claim Claim
+}
+
+// BudgetExceededError is returned when a claim does not fit the budget
+type BudgetExceededError struct {
+	Trigger string
+	Reason  string
+	// Permanent is true if the claim does not fit even with nothing disrupted
+	Permanent bool
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("trigger [%s] exceeds the blast radius budget: %s", e.Trigger, e.Reason)
+}
+
+// LimitFunc returns the maximum number of resources of a kind disrupted at once.
+// It is evaluated on each claim so that it can follow the size of the cluster.
+type LimitFunc func() int
+
+// Fixed returns a constant limit
+func Fixed(n int) LimitFunc {
+	return func() int {
+		return n
+	}
+}
+
+// QuorumSafe returns a limit keeping a majority of the total up: floor((N-1)/2)
+func QuorumSafe(total func() int) LimitFunc {
+	return func() int {
+		n := total()
+		if n < 1 {
+			return 0
+		}
+		return (n - 1) / 2
+	}
+}
+
+// Manager grants claims within the limits
+type Manager struct {
+	sync.Mutex
+	limits map[Kind]LimitFunc
+	leases map[int]*Lease
+	nextID int
+}
+
+// NewManager returns a manager without limits
+func NewManager() *Manager {
+	return &Manager{
+		limits: make(map[Kind]LimitFunc),
+		leases: make(map[int]*Lease),
+	}
+}
+
+// SetLimit sets the limit of a kind of resources
+func (m *Manager) SetLimit(kind Kind, limit LimitFunc) {
+	m.Lock()
+	defer m.Unlock()
+	m.limits[kind] = limit
+}
+
+// TryAcquire grants the claim if it fits the budget now
+func (m *Manager) TryAcquire(c Claim) (*Lease, error) {
+	m.Lock()
+	defer m.Unlock()
+	if err := m.fits(c); err != nil {
+		return nil, err
+	}
+	m.nextID++
+	l := &Lease{id: m.nextID, Claim: c}
+	m.leases[l.id] = l
+	return l, nil
+}
+
+// Release returns the resources of the lease to the budget
+func (m *Manager) Release(l *Lease) {
+	if l == nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.leases[l.id]; !ok {
+		return
+	}
+	delete(m.leases, l.id)
+}
+
+// Bind records the named resources disrupted under the lease, once the trigger
+// picked them. Each name takes the place of a unit of its kind claimed without a
+// name, if any is left, so later claims conflict with the named resources. The
+// lease is left unchanged and an error returned if the resources bound do not fit
+// the budget along with the other leases, for e.g. a node already disrupted by
+// another trigger or more nodes than the trigger claimed.
+func (m *Manager) Bind(l *Lease, kind Kind, names ...string) error {
+	if l == nil {
+		return nil
+	}
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.leases[l.id]; !ok {
+		return nil
+	}
+	bound := copyClaim(l.Claim)
+	for _, name := range names {
+		held := false
+		for _, n := range bound.Resources[kind] {
+			held = held || n == name
+		}
+		if held {
+			continue
+		}
+		bound.Resources[kind] = append(bound.Resources[kind], name)
+		if bound.Units[kind] > 0 {
+			bound.Units[kind]--
+		}
+	}
+
+	delete(m.leases, l.id)
+	err := m.fits(bound)
+	m.leases[l.id] = l
+	if err != nil {
+		return err
+	}
+	l.Claim = bound
+	return nil
+}
+
+// InUse returns the number of resources disrupted by kind
+func (m *Manager) InUse() map[Kind]int {
+	m.Lock()
+	defer m.Unlock()
+	inUse := make(map[Kind]int)
+	for _, l := range m.leases {
+		for kind := range l.Claim.Units {
+			inUse[kind] += l.Claim.Units[kind]
+		}
+		for kind, names := range l.Claim.Resources {
+			inUse[kind] += len(names)
+		}
+	}
+	return inUse
+}
+
+// fits returns an error if the claim does not fit along with the current leases
+func (m *Manager) fits(c Claim) error {
+	exceeded := func(permanent bool, format string, args ...interface{}) error {
+		return &BudgetExceededError{Trigger: c.Trigger, Reason: fmt.Sprintf(format, args...), Permanent: permanent}
+	}
+
+	for _, kind := range sortedKinds(m.limits) {
+		limit := m.limits[kind]()
+		if c.units(kind) > limit {
+			return exceeded(true, "claims [%d] %s, limit is [%d]", c.units(kind), kind, limit)
+		}
+	}
+
+	inUse := make(map[Kind]int)
+	for _, l := range m.sortedLeases() {
+		if l.Claim.Exclusive || c.Exclusive {
+			return exceeded(false, "trigger [%s] is running and one of them is exclusive", l.Claim.Trigger)
+		}
+		for _, lock := range c.Locks {
+			for _, held := range l.Claim.Locks {
+				if lock == held {
+					return exceeded(false, "lock [%s] is held by trigger [%s]", lock, l.Claim.Trigger)
+				}
+			}
+		}
+		for kind, names := range c.Resources {
+			for _, name := range names {
+				for _, held := range l.Claim.Resources[kind] {
+					if name == held {
+						return exceeded(false, "%s [%s] is disrupted by trigger [%s]", kind, name, l.Claim.Trigger)
+					}
+				}
+			}
+		}
+		for kind := range m.limits {
+			inUse[kind] += l.Claim.units(kind)
+		}
+	}
+
+	var over []string
+	for _, kind := range sortedKinds(m.limits) {
+		if limit := m.limits[kind](); inUse[kind]+c.units(kind) > limit {
+			over = append(over, fmt.Sprintf("%s [%d+%d > %d]", kind, inUse[kind], c.units(kind), limit))
+		}
+	}
+	if len(over) > 0 {
+		return exceeded(false, "too many disrupted %s", strings.Join(over, ", "))
+	}
+	return nil
+}
+
+func copyClaim(c Claim) Claim {
+	copied := c
+	copied.Units = make(map[Kind]int)
+	for kind, n := range c.Units {
+		copied.Units[kind] = n
+	}
+	copied.Resources = make(map[Kind][]string)
+	for kind, names := range c.Resources {
+		copied.Resources[kind] = append([]string(nil), names...)
+	}
+	return copied
+}
+
+func (m *Manager) sortedLeases() []*Lease {
+	var leases []*Lease
+	for _, l := range m.leases {
+		leases = append(leases, l)
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].id < leases[j].id
+	})
+	return leases
+}
+
+func sortedKinds(limits map[Kind]LimitFunc) []Kind {
+	var kinds []Kind
+	for kind := range limits {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+	return kinds
+}
@@ -0,0 +1,115 @@
+package blastradius
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget(t *testing.T) {
+	storageNodes := 5
+	m := NewManager()
+	m.SetLimit(StorageNodes, QuorumSafe(func() int { return storageNodes }))
+	m.SetLimit(KvdbMembers, Fixed(1))
+
+	reboot, err := m.TryAcquire(Claim{Trigger: "rebootNode", Units: map[Kind]int{StorageNodes: 1}})
+	require.NoError(t, err)
+	kvdb, err := m.TryAcquire(Claim{Trigger: "kvdbFailover", Units: map[Kind]int{StorageNodes: 1, KvdbMembers: 1}})
+	require.NoError(t, err)
+	require.Equal(t, map[Kind]int{StorageNodes: 2, KvdbMembers: 1}, m.InUse())
+
+	// floor((5-1)/2) = 2 storage nodes are already down
+	_, err = m.TryAcquire(Claim{Trigger: "crashNode", Units: map[Kind]int{StorageNodes: 1}})
+	require.Error(t, err)
+	require.False(t, err.(*BudgetExceededError).Permanent)
+
+	m.Release(kvdb)
+	m.Release(kvdb)
+	_, err = m.TryAcquire(Claim{Trigger: "crashNode", Units: map[Kind]int{StorageNodes: 1}})
+	require.NoError(t, err)
+
+	// more than the limit can never fit
+	_, err = m.TryAcquire(Claim{Trigger: "rebootManyNodes", Units: map[Kind]int{StorageNodes: 3}})
+	require.Error(t, err)
+	require.True(t, err.(*BudgetExceededError).Permanent)
+
+	m.Release(reboot)
+	storageNodes = 3
+	_, err = m.TryAcquire(Claim{Trigger: "nodeDecommission", Units: map[Kind]int{StorageNodes: 1}})
+	require.Error(t, err, "limit follows the number of storage nodes")
+}
+
+func TestLocksAndResources(t *testing.T) {
+	m := NewManager()
+	pool, err := m.TryAcquire(Claim{Trigger: "poolDelete", Resources: map[Kind][]string{Pools: {"pool-0"}}, Locks: []string{"upgrade"}})
+	require.NoError(t, err)
+
+	_, err = m.TryAcquire(Claim{Trigger: "poolExpand", Resources: map[Kind][]string{Pools: {"pool-0"}}})
+	require.Error(t, err)
+	_, err = m.TryAcquire(Claim{Trigger: "upgradeVolumeDriver", Locks: []string{"upgrade"}})
+	require.Error(t, err)
+	_, err = m.TryAcquire(Claim{Trigger: "powerOffAllVMs", Exclusive: true})
+	require.Error(t, err)
+
+	other, err := m.TryAcquire(Claim{Trigger: "poolExpand", Resources: map[Kind][]string{Pools: {"pool-1"}}})
+	require.NoError(t, err)
+	m.Release(pool)
+	m.Release(other)
+	_, err = m.TryAcquire(Claim{Trigger: "powerOffAllVMs", Exclusive: true})
+	require.NoError(t, err)
+}
+
+func TestBind(t *testing.T) {
+	m := NewManager()
+	m.SetLimit(StorageNodes, Fixed(2))
+	reboot, err := m.TryAcquire(Claim{Trigger: "rebootNode", Units: map[Kind]int{StorageNodes: 1}})
+	require.NoError(t, err)
+	require.NoError(t, m.Bind(reboot, StorageNodes, "node-0"))
+	require.NoError(t, m.Bind(reboot, StorageNodes, "node-0"))
+	require.Equal(t, map[Kind]int{StorageNodes: 1}, m.InUse())
+
+	// the node picked by the reboot can not be disrupted by another trigger
+	_, err = m.TryAcquire(Claim{Trigger: "crashNode", Resources: map[Kind][]string{StorageNodes: {"node-0"}}})
+	require.Error(t, err)
+	crash, err := m.TryAcquire(Claim{Trigger: "crashNode", Units: map[Kind]int{StorageNodes: 1}})
+	require.NoError(t, err)
+	require.Error(t, m.Bind(crash, StorageNodes, "node-0"), "node is bound to the reboot")
+	require.NoError(t, m.Bind(crash, StorageNodes, "node-1"))
+
+	// binding more than claimed still counts, and is rejected over the limit
+	require.NoError(t, m.Bind(crash, Volumes, "vol-0"))
+	require.Equal(t, map[Kind]int{StorageNodes: 2, Volumes: 1}, m.InUse())
+	require.Error(t, m.Bind(crash, StorageNodes, "node-2"))
+	require.Equal(t, []string{"node-1"}, crash.Claim.Resources[StorageNodes], "rejected bind leaves the lease unchanged")
+	require.Equal(t, map[Kind]int{StorageNodes: 2, Volumes: 1}, m.InUse())
+
+	m.Release(crash)
+	require.NoError(t, m.Bind(crash, Volumes, "vol-1"))
+	require.Equal(t, map[Kind]int{StorageNodes: 1}, m.InUse())
+}
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig([]byte("limits:\n  storageNodes: quorum\n  pools: \"2\"\n"))
+	require.NoError(t, err)
+
+	m, err := NewManagerFromConfig(c, map[Kind]func() int{StorageNodes: func() int { return 7 }})
+	require.NoError(t, err)
+	_, err = m.TryAcquire(Claim{Trigger: "rebootManyNodes", Units: map[Kind]int{StorageNodes: 3, Pools: 2}})
+	require.NoError(t, err)
+
+	_, err = NewManagerFromConfig(c, nil)
+	require.Error(t, err)
+
+	c, err = ParseConfig([]byte(""))
+	require.NoError(t, err)
+	require.Equal(t, DefaultConfig(), c)
+
+	_, err = ParseConfig([]byte("mode: queue"))
+	require.Error(t, err, "triggers are not queued")
+	_, err = ParseConfig([]byte("limits:\n  kvdbMembers: one"))
+	require.Error(t, err)
+	_, err = ParseConfig([]byte("maxNodes: 1"))
+	require.Error(t, err)
+	_, err = ParseConfig([]byte("limits:\n  storagenodes: 1"))
+	require.Error(t, err, "limit kinds are case sensitive")
+}
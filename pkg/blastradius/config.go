@@ -0,0 +1,97 @@
+package blastradius
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// QuorumLimit is the limit keeping a majority of the resources up
+const QuorumLimit = "quorum"
+
+// Config is the budget configuration, for e.g.
+//
+//	limits:
+//	  storageNodes: quorum
+//	  kvdbMembers: 1
+type Config struct {
+	Limits map[Kind]string `yaml:"limits"`
+}
+
+// DefaultConfig keeps a quorum of storage nodes up and disrupts a single kvdb
+// member at a time
+func DefaultConfig() Config {
+	return Config{
+		Limits: map[Kind]string{
+			StorageNodes: QuorumLimit,
+			KvdbMembers:  "1",
+		},
+	}
+}
+
+// ParseConfig parses a YAML budget configuration. Unset fields take their
+// default value.
+func ParseConfig(data []byte) (Config, error) {
+	var c Config
+	if err := yaml.UnmarshalStrict(data, &c); err != nil {
+		return c, fmt.Errorf("failed to parse blast radius config: %v", err)
+	}
+	if c.Limits == nil {
+		c.Limits = DefaultConfig().Limits
+	}
+	for kind, limit := range c.Limits {
+		if !knownKind(kind) {
+			return c, fmt.Errorf("unknown blast radius limit kind [%s], expected one of %v", kind, Kinds)
+		}
+		if _, err := strconv.Atoi(limit); err != nil && limit != QuorumLimit {
+			return c, fmt.Errorf("invalid blast radius limit [%s] of [%s], expected a number or [%s]", limit, kind, QuorumLimit)
+		}
+	}
+	return c, nil
+}
+
+func knownKind(kind Kind) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// NewManagerFromConfig returns a manager with the limits of the config. totals
+// returns the number of resources of each kind, for quorum limits.
+func NewManagerFromConfig(c Config, totals map[Kind]func() int) (*Manager, error) {
+	m := NewManager()
+	if err := m.Configure(c, totals); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Configure replaces the limits of the manager by the ones of the config.
+// Current leases are kept.
+func (m *Manager) Configure(c Config, totals map[Kind]func() int) error {
+	limits := make(map[Kind]LimitFunc)
+	for kind, limit := range c.Limits {
+		if limit == QuorumLimit {
+			total, ok := totals[kind]
+			if !ok {
+				return fmt.Errorf("[%s] limit is not supported for [%s]", QuorumLimit, kind)
+			}
+			limits[kind] = QuorumSafe(total)
+			continue
+		}
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return fmt.Errorf("invalid blast radius limit [%s] of [%s]: %v", limit, kind, err)
+		}
+		limits[kind] = Fixed(n)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+	m.limits = limits
+	return nil
+}
@@ -0,0 +1,207 @@
+package chaosplan
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testPlan = `
+version: v1
+name: test
+maxDisruptive: 1
+stop:
+  maxFailures: 3
+phases:
+  - name: setup
+    groups:
+      - name: deploy
+        steps:
+          - trigger: deployApps
+  - name: chaos
+    repeat: 2
+    groups:
+      - name: disrupt
+        steps:
+          - trigger: rebootNode
+            after: [deployApps]
+            postconditions: [appsHealthy]
+          - trigger: crashNode
+            after: [deployApps]
+          - id: haIncreaseAfterReboot
+            trigger: haIncrease
+            after: [rebootNode]
+            repeat: 2
+            interval: 10ms
+`
+
+type recorder struct {
+	sync.Mutex
+	calls         []string
+	running       int
+	maxConcurrent int
+}
+
+func (r *recorder) trigger(name string, err error, disruptive bool) TriggerFunc {
+	return func() error {
+		r.Lock()
+		r.calls = append(r.calls, name)
+		if disruptive {
+			r.running++
+			if r.running > r.maxConcurrent {
+				r.maxConcurrent = r.running
+			}
+		}
+		r.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		r.Lock()
+		if disruptive {
+			r.running--
+		}
+		r.Unlock()
+		return err
+	}
+}
+
+func TestParseAndValidate(t *testing.T) {
+	p, err := Parse([]byte(testPlan))
+	require.NoError(t, err)
+	require.Equal(t, 1, *p.MaxDisruptive)
+	require.Equal(t, 2, p.Phases[1].Repeat)
+	require.Equal(t, "rebootNode", p.Phases[1].Groups[0].Steps[0].ID)
+	require.Equal(t, 10*time.Millisecond, p.Phases[1].Groups[0].Steps[2].Interval.Duration)
+	require.NoError(t, Validate(p, nil, nil))
+
+	_, err = Parse([]byte("version: v1\nunknownField: true\n"))
+	require.Error(t, err)
+
+	bad, err := Parse([]byte(`
+version: v2
+phases:
+  - name: p
+    groups:
+      - name: g
+        steps:
+          - trigger: a
+            after: [b]
+          - trigger: b
+            after: [a, c]
+          - trigger: a
+`))
+	require.NoError(t, err)
+	err = Validate(bad, map[string]bool{"a": true}, nil)
+	require.Error(t, err)
+	problems := err.(*ValidationError).Problems
+	require.Contains(t, problems, "unsupported version [v2], expected [v1]")
+	require.Contains(t, problems, "step [b]: unknown trigger [b]")
+	require.Contains(t, problems, "step ID [a] is used more than once, set a unique id")
+	require.Contains(t, problems, "step [b] depends on unknown step [c]")
+	require.Contains(t, problems, "dependency cycle: a -> b -> a")
+}
+
+func TestRun(t *testing.T) {
+	p, err := Parse([]byte(testPlan))
+	require.NoError(t, err)
+
+	rec := &recorder{}
+	disruptive := map[string]bool{"rebootNode": true, "crashNode": true}
+	e := NewExecutor(map[string]TriggerFunc{
+		"deployApps": rec.trigger("deployApps", nil, false),
+		"rebootNode": rec.trigger("rebootNode", nil, true),
+		"crashNode":  rec.trigger("crashNode", fmt.Errorf("crash failed"), true),
+		"haIncrease": rec.trigger("haIncrease", nil, false),
+	}, map[string]ConditionFunc{
+		"appsHealthy": func() error { return nil },
+	}, func(trigger string) bool { return disruptive[trigger] })
+
+	report, err := e.Run(p)
+	require.NoError(t, err)
+	require.Equal(t, 1, rec.maxConcurrent)
+	require.Equal(t, 2, report.Failures)
+	require.Empty(t, report.StopReason)
+	require.Equal(t, StepSucceeded, report.Steps["deployApps"].Status)
+	require.Equal(t, StepSucceeded, report.Steps["rebootNode"].Status)
+	require.Equal(t, 2, report.Steps["rebootNode"].Runs)
+	require.Equal(t, StepFailed, report.Steps["crashNode"].Status)
+	require.Equal(t, 4, report.Steps["haIncreaseAfterReboot"].Runs)
+
+	// a failing postcondition fails rebootNode and skips the step depending on it
+	e.conditions["appsHealthy"] = func() error { return fmt.Errorf("apps down") }
+	p.Stop.OnFailure = true
+	report, err = e.Run(p)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.StopReason)
+	require.Equal(t, StepSkipped, report.Steps["haIncreaseAfterReboot"].Status)
+	require.Zero(t, report.Steps["haIncreaseAfterReboot"].Runs)
+
+	_, err = e.Run(&Plan{Version: VersionV1})
+	require.Error(t, err)
+}
+
+func TestStopWhileWaitingForDisruptiveSlot(t *testing.T) {
+	p, err := Parse([]byte(`
+version: v1
+stop:
+  onFailure: true
+phases:
+  - name: p
+    groups:
+      - name: g
+        steps:
+          - id: first
+            trigger: crashNode
+            preconditions: [checked]
+          - id: second
+            trigger: crashNode
+            preconditions: [checked]
+`))
+	require.NoError(t, err)
+
+	rec := &recorder{}
+	checks := 0
+	e := NewExecutor(map[string]TriggerFunc{
+		"crashNode": rec.trigger("crashNode", fmt.Errorf("crash failed"), true),
+	}, map[string]ConditionFunc{
+		"checked": func() error {
+			rec.Lock()
+			defer rec.Unlock()
+			checks++
+			return nil
+		},
+	}, func(string) bool { return true })
+
+	// whichever step gets the slot first fails and stops the plan, the other
+	// neither checks its preconditions nor runs
+	report, err := e.Run(p)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.StopReason)
+	require.Equal(t, []string{"crashNode"}, rec.calls)
+	require.Equal(t, 1, checks)
+	require.Equal(t, 1, report.Failures)
+}
+
+func TestValidateSequential(t *testing.T) {
+	p, err := Parse([]byte(testPlan))
+	require.NoError(t, err)
+	err = ValidateSequential(p)
+	require.EqualError(t, err, "invalid chaos plan: phase [chaos] group [disrupt]: steps [rebootNode] and [crashNode] run in parallel, "+
+		"triggers run one at a time so one must be after the other")
+
+	p.Phases[1].Groups[0].Steps[1].After = []string{"haIncreaseAfterReboot"}
+	require.NoError(t, ValidateSequential(p))
+
+	maxDisruptive := 2
+	p.MaxDisruptive = &maxDisruptive
+	e := NewExecutor(map[string]TriggerFunc{
+		"deployApps": func() error { return nil },
+		"rebootNode": func() error { return nil },
+		"crashNode":  func() error { return nil },
+		"haIncrease": func() error { return nil },
+	}, map[string]ConditionFunc{"appsHealthy": func() error { return nil }}, nil)
+	require.NoError(t, e.Validate(p))
+	e.SetSequential()
+	require.EqualError(t, e.Validate(p), "invalid chaos plan: maxDisruptive [2] is not supported, triggers run one at a time")
+}
@@ -0,0 +1,387 @@
+package chaosplan
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+// TriggerFunc runs a trigger once and returns an error if the run failed
+type TriggerFunc func() error
+
+// ConditionFunc checks a condition and returns an error if it does not hold
+type ConditionFunc func() error
+
+// StepStatus is the outcome of a step
+type StepStatus string
+
+const (
+	// StepSucceeded means all runs of the step succeeded
+	StepSucceeded StepStatus = "Succeeded"
+	// StepFailed means at least one run of the step failed
+	StepFailed StepStatus = "Failed"
+	// StepSkipped means the step did not run, because a step it depends on did
+	// not succeed or because the plan was stopped
+	StepSkipped StepStatus = "Skipped"
+)
+
+// StepResult is the outcome of a step
+type StepResult struct {
+	ID      string
+	Trigger string
+	Status  StepStatus
+	// Runs is the number of times the trigger ran
+	Runs   int
+	Errors []error
+	Start  time.Time
+	End    time.Time
+}
+
+// Report is the outcome of a plan
+type Report struct {
+	Plan  string
+	Start time.Time
+	End   time.Time
+	// Steps holds the results keyed by step ID. For repeated phases, it holds
+	// the result of the last repetition along with the runs of all of them.
+	Steps map[string]*StepResult
+	// Failures is the number of failed trigger runs
+	Failures int
+	// StopReason is set when the plan stopped before all of its phases were done
+	StopReason string
+}
+
+// Failed returns true if any trigger run failed
+func (r *Report) Failed() bool {
+	return r.Failures > 0
+}
+
+// errStopped is returned by runOnce when the plan stopped before the trigger ran
+var errStopped = errors.New("chaos plan stopped")
+
+// Executor runs chaos plans by calling registered triggers and conditions
+type Executor struct {
+	triggers     map[string]TriggerFunc
+	conditions   map[string]ConditionFunc
+	isDisruptive func(trigger string) bool
+	stopChan     <-chan struct{}
+	sequential   bool
+}
+
+// NewExecutor returns an executor for the given triggers and conditions.
+// isDisruptive tells whether a trigger counts against the max number of
+// disruptive triggers of a plan, unless the step overrides it.
+func NewExecutor(triggers map[string]TriggerFunc, conditions map[string]ConditionFunc, isDisruptive func(trigger string) bool) *Executor {
+	if conditions == nil {
+		conditions = map[string]ConditionFunc{}
+	}
+	if isDisruptive == nil {
+		isDisruptive = func(string) bool { return false }
+	}
+	return &Executor{
+		triggers:     triggers,
+		conditions:   conditions,
+		isDisruptive: isDisruptive,
+	}
+}
+
+// SetStopChan makes the executor stop the plan when the given channel is closed
+func (e *Executor) SetStopChan(stopChan <-chan struct{}) {
+	e.stopChan = stopChan
+}
+
+// SetSequential tells the executor its trigger functions cannot run at the same
+// time. Plans running more than one disruptive trigger at a time or steps of a
+// group in parallel are then rejected, as they would not do what they declare.
+func (e *Executor) SetSequential() {
+	e.sequential = true
+}
+
+// Validate validates the plan against the triggers and conditions of the executor
+func (e *Executor) Validate(p *Plan) error {
+	triggers := make(map[string]bool)
+	for name := range e.triggers {
+		triggers[name] = true
+	}
+	conditions := make(map[string]bool)
+	for name := range e.conditions {
+		conditions[name] = true
+	}
+	if err := Validate(p, triggers, conditions); err != nil {
+		return err
+	}
+	if e.sequential {
+		return ValidateSequential(p)
+	}
+	return nil
+}
+
+// run holds the state of a plan being executed
+type run struct {
+	*Executor
+	plan       *Plan
+	report     *Report
+	disruptive chan struct{}
+	deadline   time.Time
+	halt       chan struct{}
+	sync.Mutex
+}
+
+// Run validates and executes the plan. It returns an error only if the plan is
+// invalid; the outcome of the triggers is in the report.
+func (e *Executor) Run(p *Plan) (*Report, error) {
+	if err := e.Validate(p); err != nil {
+		return nil, err
+	}
+
+	r := &run{
+		Executor: e,
+		plan:     p,
+		report: &Report{
+			Plan:  p.Name,
+			Start: time.Now(),
+			Steps: make(map[string]*StepResult),
+		},
+		halt: make(chan struct{}),
+	}
+	if *p.MaxDisruptive > 0 {
+		r.disruptive = make(chan struct{}, *p.MaxDisruptive)
+	}
+	if p.Stop.MaxDuration.Duration > 0 {
+		r.deadline = r.report.Start.Add(p.Stop.MaxDuration.Duration)
+	}
+
+	log.Infof("Running chaos plan [%s]", p.Name)
+	for _, phase := range p.Phases {
+		for rep := 0; rep < phase.Repeat && !r.stopped(); rep++ {
+			log.Infof("Chaos plan [%s]: running phase [%s] (%d/%d)", p.Name, phase.Name, rep+1, phase.Repeat)
+			for _, group := range phase.Groups {
+				if r.stopped() {
+					break
+				}
+				r.runGroup(group)
+			}
+		}
+	}
+	r.report.End = time.Now()
+	log.Infof("Chaos plan [%s] done with %d failed trigger runs. Stop reason: [%s]",
+		p.Name, r.report.Failures, r.report.StopReason)
+	return r.report, nil
+}
+
+func (r *run) runGroup(group Group) {
+	done := make(map[string]chan struct{})
+	for _, step := range group.Steps {
+		done[step.ID] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for _, step := range group.Steps {
+		wg.Add(1)
+		go func(step Step) {
+			defer wg.Done()
+			defer close(done[step.ID])
+			for _, dep := range step.After {
+				if ch, ok := done[dep]; ok {
+					<-ch
+				}
+			}
+			r.runStep(step)
+		}(step)
+	}
+	wg.Wait()
+}
+
+func (r *run) runStep(step Step) {
+	result := r.startStep(step)
+	defer func() {
+		r.Lock()
+		result.End = time.Now()
+		r.Unlock()
+	}()
+
+	for _, dep := range step.After {
+		if status := r.stepStatus(dep); status != StepSucceeded {
+			log.Infof("Skipping step [%s] as step [%s] it depends on is [%s]", step.ID, dep, status)
+			return
+		}
+	}
+
+	for i := 0; i < step.Repeat; i++ {
+		if i > 0 && !r.wait(step.Interval.Duration) {
+			return
+		}
+		if r.stopped() {
+			return
+		}
+		err := r.runOnce(step)
+		if err == errStopped {
+			return
+		}
+		if err != nil {
+			log.Errorf("Step [%s] run %d/%d failed: %v", step.ID, i+1, step.Repeat, err)
+			r.recordFailure(result, err)
+			if !step.ContinueOnFailure {
+				return
+			}
+			continue
+		}
+		r.Lock()
+		result.Runs++
+		if result.Status == StepSkipped {
+			result.Status = StepSucceeded
+		}
+		r.Unlock()
+	}
+}
+
+// startStep resets the status of the step for a new execution, keeping the
+// runs and errors of previous repetitions of the phase
+func (r *run) startStep(step Step) *StepResult {
+	r.Lock()
+	defer r.Unlock()
+	result, ok := r.report.Steps[step.ID]
+	if !ok {
+		result = &StepResult{ID: step.ID, Trigger: step.Trigger}
+		r.report.Steps[step.ID] = result
+	}
+	result.Status = StepSkipped
+	result.Start = time.Now()
+	return result
+}
+
+func (r *run) stepStatus(id string) StepStatus {
+	r.Lock()
+	defer r.Unlock()
+	if result, ok := r.report.Steps[id]; ok {
+		return result.Status
+	}
+	return StepSkipped
+}
+
+// runOnce acquires a disruptive slot if needed, checks the preconditions, runs
+// the trigger and checks the postconditions. It returns errStopped if the plan
+// stopped while waiting for the slot.
+func (r *run) runOnce(step Step) error {
+	disruptive := r.isDisruptive(step.Trigger)
+	if step.Disruptive != nil {
+		disruptive = *step.Disruptive
+	}
+	if disruptive && r.disruptive != nil {
+		if !r.acquireDisruptive() {
+			return errStopped
+		}
+		defer func() { <-r.disruptive }()
+	}
+
+	for _, name := range step.Preconditions {
+		if err := r.conditions[name](); err != nil {
+			return fmt.Errorf("precondition [%s] failed: %v", name, err)
+		}
+	}
+
+	log.Infof("Running trigger [%s] for step [%s]", step.Trigger, step.ID)
+	if err := r.triggers[step.Trigger](); err != nil {
+		return fmt.Errorf("trigger [%s] failed: %v", step.Trigger, err)
+	}
+
+	for _, name := range step.Postconditions {
+		if err := r.conditions[name](); err != nil {
+			return fmt.Errorf("postcondition [%s] failed: %v", name, err)
+		}
+	}
+	return nil
+}
+
+func (r *run) recordFailure(result *StepResult, err error) {
+	r.Lock()
+	defer r.Unlock()
+	result.Runs++
+	result.Status = StepFailed
+	result.Errors = append(result.Errors, err)
+	r.report.Failures++
+
+	switch {
+	case r.plan.Stop.OnFailure:
+		r.stopLocked(fmt.Sprintf("step [%s] failed", result.ID))
+	case r.plan.Stop.MaxFailures > 0 && r.report.Failures >= r.plan.Stop.MaxFailures:
+		r.stopLocked(fmt.Sprintf("reached %d failed trigger runs", r.report.Failures))
+	}
+}
+
+func (r *run) stopLocked(reason string) {
+	if r.report.StopReason != "" {
+		return
+	}
+	log.Warnf("Stopping chaos plan [%s]: %s", r.plan.Name, reason)
+	r.report.StopReason = reason
+	close(r.halt)
+}
+
+// stopped returns true if a stop condition was hit
+func (r *run) stopped() bool {
+	r.Lock()
+	defer r.Unlock()
+	if r.report.StopReason != "" {
+		return true
+	}
+	if !r.deadline.IsZero() && time.Now().After(r.deadline) {
+		r.stopLocked(fmt.Sprintf("reached max duration of %v", r.plan.Stop.MaxDuration.Duration))
+		return true
+	}
+	if r.stopChan != nil {
+		select {
+		case <-r.stopChan:
+			r.stopLocked("received stop signal")
+			return true
+		default:
+		}
+	}
+	return false
+}
+
+// acquireDisruptive waits for a disruptive slot and returns false, without
+// holding the slot, if the plan was stopped in the meantime
+func (r *run) acquireDisruptive() bool {
+	select {
+	case r.disruptive <- struct{}{}:
+	case <-r.halt:
+		return false
+	case <-r.stopChan:
+		r.stopped()
+		return false
+	case <-r.deadlineChan():
+		r.stopped()
+		return false
+	}
+	if r.stopped() {
+		<-r.disruptive
+		return false
+	}
+	return true
+}
+
+// deadlineChan returns a channel receiving at the max duration of the plan, nil if it has none
+func (r *run) deadlineChan() <-chan time.Time {
+	if r.deadline.IsZero() {
+		return nil
+	}
+	return time.After(time.Until(r.deadline))
+}
+
+// wait sleeps for d and returns false if the plan was stopped in the meantime
+func (r *run) wait(d time.Duration) bool {
+	deadline := r.deadlineChan()
+	select {
+	case <-time.After(d):
+		return !r.stopped()
+	case <-r.halt:
+	case <-r.stopChan:
+	case <-deadline:
+	}
+	r.stopped()
+	return false
+}
@@ -0,0 +1,151 @@
+package chaosplan
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// VersionV1 is the current version of the chaos plan format
+	VersionV1 = "v1"
+	// DefaultMaxDisruptive is the max number of disruptive triggers running at
+	// the same time when a plan does not set it
+	DefaultMaxDisruptive = 1
+)
+
+// Plan is a declarative description of the triggers of a longevity run.
+// Phases run one after the other. Within a phase, groups run one after the
+// other and the steps of a group run in parallel. Executors whose trigger
+// functions cannot run at the same time only accept plans whose groups are
+// ordered by the dependencies of their steps, see ValidateSequential.
+type Plan struct {
+	// Version of the plan format, must be VersionV1
+	Version string `yaml:"version"`
+	// Name of the plan
+	Name string `yaml:"name"`
+	// MaxDisruptive is the max number of disruptive triggers running at the
+	// same time across the whole plan. Defaults to DefaultMaxDisruptive.
+	MaxDisruptive *int `yaml:"maxDisruptive,omitempty"`
+	// Stop holds the conditions ending the plan early
+	Stop StopConditions `yaml:"stop,omitempty"`
+	// Phases of the plan
+	Phases []Phase `yaml:"phases"`
+}
+
+// StopConditions end the plan before all of its phases are done
+type StopConditions struct {
+	// MaxDuration stops the plan once it has been running for this long
+	MaxDuration Duration `yaml:"maxDuration,omitempty"`
+	// MaxFailures stops the plan once this many trigger runs have failed
+	MaxFailures int `yaml:"maxFailures,omitempty"`
+	// OnFailure stops the plan on the first failed trigger run
+	OnFailure bool `yaml:"onFailure,omitempty"`
+}
+
+// Phase is a named sequence of groups
+type Phase struct {
+	Name string `yaml:"name"`
+	// Repeat runs the phase this many times. Defaults to 1.
+	Repeat int     `yaml:"repeat,omitempty"`
+	Groups []Group `yaml:"groups"`
+}
+
+// Group is a set of steps running in parallel
+type Group struct {
+	Name  string `yaml:"name"`
+	Steps []Step `yaml:"steps"`
+}
+
+// Step runs a trigger one or more times
+type Step struct {
+	// ID identifies the step in dependencies. Defaults to the trigger name.
+	ID string `yaml:"id,omitempty"`
+	// Trigger is the name of the trigger to run
+	Trigger string `yaml:"trigger"`
+	// Repeat runs the trigger this many times. Defaults to 1.
+	Repeat int `yaml:"repeat,omitempty"`
+	// Interval is the wait between two runs of the trigger
+	Interval Duration `yaml:"interval,omitempty"`
+	// Disruptive overrides whether the trigger counts against MaxDisruptive
+	Disruptive *bool `yaml:"disruptive,omitempty"`
+	// After lists the IDs of steps which must have succeeded for this step to
+	// run. Steps can only depend on steps of the same or of earlier groups.
+	After []string `yaml:"after,omitempty"`
+	// Preconditions are checks which must pass before each run of the trigger
+	Preconditions []string `yaml:"preconditions,omitempty"`
+	// Postconditions are checks which must pass after each run of the trigger
+	Postconditions []string `yaml:"postconditions,omitempty"`
+	// ContinueOnFailure keeps repeating the trigger after a failed run
+	ContinueOnFailure bool `yaml:"continueOnFailure,omitempty"`
+}
+
+// Duration is a time.Duration read from strings like "10m" or "1h30m"
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalYAML parses the duration from its string form
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration [%s]: %v", s, err)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// MarshalYAML writes the duration in its string form
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+// Parse parses a plan, rejecting unknown fields, and fills in its defaults
+func Parse(data []byte) (*Plan, error) {
+	plan := &Plan{}
+	if err := yaml.UnmarshalStrict(data, plan); err != nil {
+		return nil, fmt.Errorf("failed to parse chaos plan: %v", err)
+	}
+	plan.setDefaults()
+	return plan, nil
+}
+
+// ParseFile reads and parses the plan at the given path
+func ParseFile(path string) (*Plan, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chaos plan [%s]: %v", path, err)
+	}
+	return Parse(data)
+}
+
+func (p *Plan) setDefaults() {
+	if p.MaxDisruptive == nil {
+		maxDisruptive := DefaultMaxDisruptive
+		p.MaxDisruptive = &maxDisruptive
+	}
+	for i := range p.Phases {
+		phase := &p.Phases[i]
+		if phase.Repeat == 0 {
+			phase.Repeat = 1
+		}
+		for j := range phase.Groups {
+			group := &phase.Groups[j]
+			for k := range group.Steps {
+				step := &group.Steps[k]
+				if step.ID == "" {
+					step.ID = step.Trigger
+				}
+				if step.Repeat == 0 {
+					step.Repeat = 1
+				}
+			}
+		}
+	}
+}
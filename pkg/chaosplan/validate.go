@@ -0,0 +1,214 @@
+package chaosplan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError lists all the problems found in a plan
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid chaos plan: %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks the structure of the plan. When triggers or conditions are
+// not nil, it also checks every trigger and condition referenced by the plan is
+// part of them.
+func Validate(p *Plan, triggers map[string]bool, conditions map[string]bool) error {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if p.Version != VersionV1 {
+		addProblem("unsupported version [%s], expected [%s]", p.Version, VersionV1)
+	}
+	if p.MaxDisruptive != nil && *p.MaxDisruptive < 0 {
+		addProblem("maxDisruptive must not be negative")
+	}
+	if p.Stop.MaxDuration.Duration < 0 {
+		addProblem("stop.maxDuration must not be negative")
+	}
+	if p.Stop.MaxFailures < 0 {
+		addProblem("stop.maxFailures must not be negative")
+	}
+	if len(p.Phases) == 0 {
+		addProblem("plan has no phases")
+	}
+
+	// groupOf maps step IDs to the index of their group in plan order so that
+	// dependencies can be checked to only point backwards
+	groupOf := make(map[string]int)
+	groupIndex := 0
+	for _, phase := range p.Phases {
+		if phase.Repeat < 0 {
+			addProblem("phase [%s]: repeat must not be negative", phase.Name)
+		}
+		if len(phase.Groups) == 0 {
+			addProblem("phase [%s] has no groups", phase.Name)
+		}
+		for _, group := range phase.Groups {
+			if len(group.Steps) == 0 {
+				addProblem("phase [%s] group [%s] has no steps", phase.Name, group.Name)
+			}
+			for _, step := range group.Steps {
+				if step.Trigger == "" {
+					addProblem("phase [%s] group [%s]: step [%s] has no trigger", phase.Name, group.Name, step.ID)
+				} else if triggers != nil && !triggers[step.Trigger] {
+					addProblem("step [%s]: unknown trigger [%s]", step.ID, step.Trigger)
+				}
+				if _, ok := groupOf[step.ID]; ok {
+					addProblem("step ID [%s] is used more than once, set a unique id", step.ID)
+				}
+				groupOf[step.ID] = groupIndex
+				if step.Repeat < 0 {
+					addProblem("step [%s]: repeat must not be negative", step.ID)
+				}
+				if step.Interval.Duration < 0 {
+					addProblem("step [%s]: interval must not be negative", step.ID)
+				}
+				for _, condition := range append(append([]string{}, step.Preconditions...), step.Postconditions...) {
+					if conditions != nil && !conditions[condition] {
+						addProblem("step [%s]: unknown condition [%s]", step.ID, condition)
+					}
+				}
+			}
+			groupIndex++
+		}
+	}
+
+	for _, phase := range p.Phases {
+		for _, group := range phase.Groups {
+			for _, step := range group.Steps {
+				for _, dep := range step.After {
+					depGroup, ok := groupOf[dep]
+					switch {
+					case !ok:
+						addProblem("step [%s] depends on unknown step [%s]", step.ID, dep)
+					case depGroup > groupOf[step.ID]:
+						addProblem("step [%s] depends on step [%s] of a later group", step.ID, dep)
+					}
+				}
+			}
+		}
+	}
+	if cycle := findCycle(p); cycle != nil {
+		addProblem("dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// ValidateSequential checks the plan can be run by trigger functions which
+// cannot run at the same time: it must not allow more than one disruptive
+// trigger at a time, and the steps of each group must be ordered by their
+// dependencies so that none of them is declared to run in parallel.
+func ValidateSequential(p *Plan) error {
+	var problems []string
+	if p.MaxDisruptive != nil && *p.MaxDisruptive > 1 {
+		problems = append(problems, fmt.Sprintf("maxDisruptive [%d] is not supported, triggers run one at a time", *p.MaxDisruptive))
+	}
+	for _, phase := range p.Phases {
+		for _, group := range phase.Groups {
+			if a, b, ok := parallelSteps(group); ok {
+				problems = append(problems, fmt.Sprintf("phase [%s] group [%s]: steps [%s] and [%s] run in parallel, "+
+					"triggers run one at a time so one must be after the other", phase.Name, group.Name, a, b))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// parallelSteps returns two steps of the group which do not depend on each other, directly or not
+func parallelSteps(group Group) (string, string, bool) {
+	deps := make(map[string][]string)
+	for _, step := range group.Steps {
+		deps[step.ID] = step.After
+	}
+	var dependsOn func(id, dep string, seen map[string]bool) bool
+	dependsOn = func(id, dep string, seen map[string]bool) bool {
+		if seen[id] {
+			return false
+		}
+		seen[id] = true
+		for _, d := range deps[id] {
+			if d == dep || dependsOn(d, dep, seen) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, a := range group.Steps {
+		for _, b := range group.Steps[i+1:] {
+			if !dependsOn(a.ID, b.ID, map[string]bool{}) && !dependsOn(b.ID, a.ID, map[string]bool{}) {
+				return a.ID, b.ID, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// findCycle returns the step IDs of a dependency cycle, if any
+func findCycle(p *Plan) []string {
+	deps := make(map[string][]string)
+	var order []string
+	for _, phase := range p.Phases {
+		for _, group := range phase.Groups {
+			for _, step := range group.Steps {
+				if _, ok := deps[step.ID]; !ok {
+					order = append(order, step.ID)
+				}
+				deps[step.ID] = append(deps[step.ID], step.After...)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		switch state[id] {
+		case visiting:
+			for i, p := range path {
+				if p == id {
+					return append(append([]string{}, path[i:]...), id)
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, dep := range deps[id] {
+			if _, ok := deps[dep]; !ok {
+				continue
+			}
+			if cycle := visit(dep); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+	for _, id := range order {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
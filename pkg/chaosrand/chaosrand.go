@@ -0,0 +1,172 @@
+// Package chaosrand provides seeded random streams for the decisions taken by
+// longevity triggers, so that a run can be reproduced from its seed and the
+// draws recorded for each trigger execution.
+package chaosrand
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+)
+
+// Draw is a single random decision: a value in [0, N)
+type Draw struct {
+	N     int `json:"n"`
+	Value int `json:"v"`
+}
+
+// StreamKey returns the key of the given execution of a named stream
+func StreamKey(name string, execution int) string {
+	return fmt.Sprintf("%s#%d", name, execution)
+}
+
+// Source hands out a random stream per execution of a trigger. The stream of
+// an execution is derived from the seed, the trigger name and the execution
+// number only, so it does not depend on how triggers interleave.
+type Source struct {
+	sync.Mutex
+	seed       int64
+	executions map[string]int
+	current    map[string]*Stream
+	// recorded holds the draws to replay, keyed by StreamKey
+	recorded map[string][]Draw
+}
+
+// NewSource returns a source seeded with the given seed
+func NewSource(seed int64) *Source {
+	return &Source{
+		seed:       seed,
+		executions: make(map[string]int),
+		current:    make(map[string]*Stream),
+	}
+}
+
+// NewReplaySource returns a source replaying the recorded draws, keyed by
+// StreamKey. Streams without recorded draws behave as with NewSource.
+func NewReplaySource(seed int64, recorded map[string][]Draw) *Source {
+	s := NewSource(seed)
+	s.recorded = recorded
+	return s
+}
+
+// Seed returns the seed of the source
+func (s *Source) Seed() int64 {
+	return s.seed
+}
+
+// Executions returns the number of executions of the named stream started so far
+func (s *Source) Executions(name string) int {
+	s.Lock()
+	defer s.Unlock()
+	return s.executions[name]
+}
+
+// Begin starts the next execution of the named stream and returns it
+func (s *Source) Begin(name string) *Stream {
+	s.Lock()
+	defer s.Unlock()
+	execution := s.executions[name]
+	s.executions[name] = execution + 1
+
+	key := StreamKey(name, execution)
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s", s.seed, key)
+	st := &Stream{
+		name:      name,
+		execution: execution,
+		rng:       rand.New(rand.NewSource(int64(h.Sum64()))),
+		replay:    s.recorded[key],
+	}
+	s.current[name] = st
+	return st
+}
+
+// Stream returns the current execution of the named stream, starting one if needed
+func (s *Source) Stream(name string) *Stream {
+	s.Lock()
+	st, ok := s.current[name]
+	s.Unlock()
+	if ok {
+		return st
+	}
+	return s.Begin(name)
+}
+
+// Stream is the random stream of a single trigger execution. It is safe for
+// concurrent use, but draws taken concurrently are only reproducible if they
+// do not depend on each other.
+type Stream struct {
+	sync.Mutex
+	name      string
+	execution int
+	rng       *rand.Rand
+	draws     []Draw
+	replay    []Draw
+	diverged  bool
+}
+
+// Name returns the name of the stream
+func (st *Stream) Name() string {
+	return st.name
+}
+
+// Execution returns the execution number of the stream, starting at 0
+func (st *Stream) Execution() int {
+	return st.execution
+}
+
+// Intn returns a random value in [0, n). It panics if n <= 0, like rand.Intn.
+func (st *Stream) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	st.Lock()
+	defer st.Unlock()
+
+	value := st.rng.Intn(n)
+	if i := len(st.draws); !st.diverged && i < len(st.replay) {
+		// the recorded draw is only usable if it was taken among as many choices
+		if st.replay[i].N == n {
+			value = st.replay[i].Value
+		} else {
+			st.diverged = true
+		}
+	}
+	st.draws = append(st.draws, Draw{N: n, Value: value})
+	return value
+}
+
+// Shuffle pseudo-randomizes the order of n elements, like rand.Shuffle
+func (st *Stream) Shuffle(n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, st.Intn(i+1))
+	}
+}
+
+// Perm returns a random permutation of [0, n)
+func (st *Stream) Perm(n int) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	st.Shuffle(n, func(i, j int) {
+		perm[i], perm[j] = perm[j], perm[i]
+	})
+	return perm
+}
+
+// Draws returns a copy of the draws taken so far
+func (st *Stream) Draws() []Draw {
+	st.Lock()
+	defer st.Unlock()
+	return append([]Draw(nil), st.draws...)
+}
+
+// Diverged returns true if a replayed stream had to stop using the recorded
+// draws because the number of choices changed, for e.g. a node went missing
+func (st *Stream) Diverged() bool {
+	st.Lock()
+	defer st.Unlock()
+	return st.diverged
+}
@@ -0,0 +1,58 @@
+package chaosrand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func draw(s *Source, name string, n int) []int {
+	st := s.Begin(name)
+	var values []int
+	for i := 0; i < n; i++ {
+		values = append(values, st.Intn(100))
+	}
+	return values
+}
+
+func TestStreamsAreIndependentOfInterleaving(t *testing.T) {
+	a := NewSource(42)
+	rebootFirst := draw(a, "rebootNode", 5)
+	crashFirst := draw(a, "crashNode", 5)
+
+	b := NewSource(42)
+	crashSecond := draw(b, "crashNode", 5)
+	rebootSecond := draw(b, "rebootNode", 5)
+
+	require.Equal(t, rebootFirst, rebootSecond)
+	require.Equal(t, crashFirst, crashSecond)
+	require.NotEqual(t, draw(a, "rebootNode", 5), rebootFirst, "next execution should draw differently")
+	require.NotEqual(t, draw(NewSource(43), "rebootNode", 5), rebootFirst)
+}
+
+func TestReplay(t *testing.T) {
+	recorded := map[string][]Draw{
+		StreamKey("rebootNode", 1): {{N: 3, Value: 2}, {N: 10, Value: 7}},
+	}
+	s := NewReplaySource(1, recorded)
+
+	s.Begin("rebootNode")
+	st := s.Begin("rebootNode")
+	require.Equal(t, 1, st.Execution())
+	require.Equal(t, 2, s.Executions("rebootNode"))
+	require.Equal(t, 2, st.Intn(3))
+	require.Equal(t, 7, st.Intn(10))
+	require.False(t, st.Diverged())
+	require.Equal(t, recorded[StreamKey("rebootNode", 1)], st.Draws())
+
+	st = NewReplaySource(1, map[string][]Draw{StreamKey("crashNode", 0): {{N: 3, Value: 2}}}).Stream("crashNode")
+	v := st.Intn(2)
+	require.True(t, v < 2)
+	require.True(t, st.Diverged())
+}
+
+func TestPerm(t *testing.T) {
+	perm := NewSource(7).Begin("poolExpand").Perm(10)
+	require.ElementsMatch(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, perm)
+	require.Equal(t, perm, NewSource(7).Begin("poolExpand").Perm(10))
+}
@@ -0,0 +1,113 @@
+package eventjournal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/portworx/torpedo/pkg/chaosrand"
+	"github.com/stretchr/testify/require"
+)
+
+type errRebootFailed struct{}
+
+func (e *errRebootFailed) Error() string {
+	return "reboot failed"
+}
+
+func TestWriteReadQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	w, err := Open(path)
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{RunID: "run1", EventID: "1", Trigger: "rebootNode", Start: start, End: start.Add(time.Minute),
+			Nodes: []string{"node-0"}, Cluster: ClusterSnapshot{VolumeDriverVersion: "3.0.0"}},
+		{RunID: "run1", EventID: "2", Trigger: "upgradeVolumeDriver", Start: start.Add(2 * time.Minute), End: start.Add(10 * time.Minute),
+			Cluster: ClusterSnapshot{VolumeDriverVersion: "3.0.0"}},
+		{RunID: "run1", EventID: "3", Trigger: "rebootNode", Start: start.Add(20 * time.Minute), End: start.Add(23 * time.Minute),
+			Nodes: []string{"node-1"}, Cluster: ClusterSnapshot{VolumeDriverVersion: "3.1.0"},
+			Outcomes: []Outcome{NewOutcome(&errRebootFailed{})}},
+		{RunID: "run2", EventID: "4", Trigger: "rebootNode", Start: start.Add(time.Hour), End: start.Add(time.Hour + time.Minute),
+			Outcomes: []Outcome{NewOutcome(fmt.Errorf("timed out<br>"))}},
+	}
+	for _, e := range entries {
+		require.NoError(t, w.Write(e))
+	}
+	require.NoError(t, w.Close())
+
+	// a truncated last line is ignored
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"runId":"run2","eve`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	read, err := Read(path)
+	require.NoError(t, err)
+	require.Len(t, read, 4)
+	require.Equal(t, StatusPassed, read[0].Status)
+	require.Equal(t, time.Minute, read[0].Duration)
+	require.Equal(t, StatusFailed, read[2].Status)
+	require.Equal(t, "*eventjournal.errRebootFailed", read[2].Outcomes[0].Type)
+	require.Equal(t, "timed out", read[3].Outcomes[0].Message)
+
+	failedAfterUpgrade := Filter(read, Query{Status: StatusFailed, AfterTrigger: "upgradeVolumeDriver"})
+	require.Len(t, failedAfterUpgrade, 1)
+	require.Equal(t, "3", failedAfterUpgrade[0].EventID)
+
+	require.Len(t, Filter(read, Query{Node: "node-0"}), 1)
+	require.Len(t, Filter(read, Query{VolumeDriverVersion: "3.1"}), 1)
+	require.Len(t, Filter(read, Query{RunIDs: []string{"run2"}, Triggers: []string{"rebootNode"}}), 1)
+
+	stats := Aggregate(Filter(read, Query{Triggers: []string{"rebootNode"}}), ByTrigger)["rebootNode"]
+	require.Equal(t, 3, stats.Total)
+	require.Equal(t, 2, stats.Failed)
+	require.Equal(t, 3*time.Minute, stats.MaxDuration)
+	require.Equal(t, 1, stats.OutcomeTypes["*eventjournal.errRebootFailed"])
+	require.InDelta(t, 2.0/3, stats.FailureRate(), 0.001)
+}
+
+func TestReadMalformedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("not json\n{\"runId\":\"run1\"}\n"), 0644))
+	_, err := Read(path)
+	require.Error(t, err)
+}
+
+func TestReplaySource(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{RunID: "run1", Trigger: "rebootNode", Start: start},
+		{RunID: "run2", Trigger: "rebootNode", Start: start.Add(time.Hour), Seed: 5, Execution: 0,
+			Draws: []chaosrand.Draw{{N: 4, Value: 3}}},
+		{RunID: "run2", Trigger: "rebootNode", Start: start.Add(2 * time.Hour), Seed: 5, Execution: 1,
+			Draws: []chaosrand.Draw{{N: 4, Value: 1}}},
+	}
+	runID := LastRunID(entries)
+	require.Equal(t, "run2", runID)
+
+	source := ReplaySource(Filter(entries, Query{RunIDs: []string{runID}}))
+	require.Equal(t, int64(5), source.Seed())
+	require.Equal(t, 3, source.Begin("rebootNode").Intn(4))
+	require.Equal(t, 1, source.Begin("rebootNode").Intn(4))
+}
+
+func TestWriteKeepsNanoseconds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	w, err := Open(path)
+	require.NoError(t, err)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 123456789, time.UTC)
+	require.NoError(t, w.Write(Entry{RunID: "run1", EventID: "1", Trigger: "rebootNode", Start: start, End: start.Add(250 * time.Millisecond)}))
+	require.NoError(t, w.Close())
+
+	read, err := Read(path)
+	require.NoError(t, err)
+	require.Len(t, read, 1)
+	require.True(t, start.Equal(read[0].Start))
+	require.Equal(t, 250*time.Millisecond, read[0].Duration)
+}
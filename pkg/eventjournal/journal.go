@@ -0,0 +1,162 @@
+package eventjournal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portworx/torpedo/pkg/chaosrand"
+)
+
+const (
+	// StatusPassed is the status of a trigger execution without errors
+	StatusPassed = "Passed"
+	// StatusFailed is the status of a trigger execution with errors
+	StatusFailed = "Failed"
+)
+
+// ClusterSnapshot captures the versions of the cluster at the time of a trigger execution
+type ClusterSnapshot struct {
+	VolumeDriver        string `json:"volumeDriver,omitempty"`
+	VolumeDriverVersion string `json:"volumeDriverVersion,omitempty"`
+	Scheduler           string `json:"scheduler,omitempty"`
+	SchedulerVersion    string `json:"schedulerVersion,omitempty"`
+	NodeCount           int    `json:"nodeCount,omitempty"`
+}
+
+// Outcome is an error of a trigger execution along with its Go type, for e.g.
+// "*node.ErrFailedToRebootNode", so failures can be grouped by kind
+type Outcome struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// NewOutcome returns the outcome of the given error
+func NewOutcome(err error) Outcome {
+	return Outcome{
+		Type:    fmt.Sprintf("%T", err),
+		Message: strings.TrimSuffix(err.Error(), "<br>"),
+	}
+}
+
+// Entry is the journal record of a trigger execution. Start and End are encoded in
+// RFC3339 with nanoseconds, so the durations of sub-second executions are kept.
+type Entry struct {
+	RunID    string          `json:"runId"`
+	EventID  string          `json:"eventId"`
+	Trigger  string          `json:"trigger"`
+	Start    time.Time       `json:"start"`
+	End      time.Time       `json:"end"`
+	Duration time.Duration   `json:"durationNs"`
+	Status   string          `json:"status"`
+	Nodes    []string        `json:"nodes,omitempty"`
+	Volumes  []string        `json:"volumes,omitempty"`
+	Cluster  ClusterSnapshot `json:"cluster"`
+	Outcomes []Outcome       `json:"outcomes,omitempty"`
+	// Seed is the random seed of the run, Execution the number of previous
+	// executions of the trigger in the run and Draws the random decisions
+	// taken by this execution. Together they allow replaying the run.
+	Seed      int64            `json:"seed,omitempty"`
+	Execution int              `json:"execution"`
+	Draws     []chaosrand.Draw `json:"draws,omitempty"`
+}
+
+// Writer appends entries to a JSON-lines journal file. It is safe for concurrent use.
+type Writer struct {
+	sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// Open opens the journal at the given path for appending, creating it if needed
+func Open(path string) (*Writer, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event journal [%s]: %v", path, err)
+	}
+	return &Writer{
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+// Write appends the entry to the journal. Status and Duration are derived from
+// the entry when not set.
+func (w *Writer) Write(entry Entry) error {
+	if entry.Status == "" {
+		entry.Status = StatusPassed
+		if len(entry.Outcomes) > 0 {
+			entry.Status = StatusFailed
+		}
+	}
+	if entry.Duration == 0 && !entry.Start.IsZero() && !entry.End.IsZero() {
+		entry.Duration = entry.End.Sub(entry.Start)
+	}
+
+	w.Lock()
+	defer w.Unlock()
+	if err := w.enc.Encode(entry); err != nil {
+		return fmt.Errorf("failed to write event [%s] to journal: %v", entry.EventID, err)
+	}
+	return w.file.Sync()
+}
+
+// Close closes the journal file
+func (w *Writer) Close() error {
+	w.Lock()
+	defer w.Unlock()
+	return w.file.Close()
+}
+
+// Read reads the entries of the given journal files in order. A truncated last
+// line, as left by a run killed while writing, is skipped.
+func Read(paths ...string) ([]Entry, error) {
+	var entries []Entry
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event journal [%s]: %v", path, err)
+		}
+		fileEntries, err := decode(file, path)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+func decode(r io.Reader, path string) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	var pendingErr error
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		// a malformed line is only an error if more entries follow it
+		if pendingErr != nil {
+			return nil, pendingErr
+		}
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			pendingErr = fmt.Errorf("failed to parse line %d of event journal [%s]: %v", lineNum, path, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read event journal [%s]: %v", path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,193 @@
+package eventjournal
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/portworx/torpedo/pkg/chaosrand"
+)
+
+// Query selects journal entries. Empty fields match everything.
+type Query struct {
+	RunIDs   []string
+	Triggers []string
+	Status   string
+	Since    time.Time
+	Until    time.Time
+	// Node and Volume match entries targeting the given node or volume
+	Node   string
+	Volume string
+	// VolumeDriverVersion matches entries with a volume driver version starting with it
+	VolumeDriverVersion string
+	// OutcomeType matches entries with at least one outcome of the given type
+	OutcomeType string
+	// AfterTrigger matches entries starting after the first successful execution
+	// of the given trigger in the same run, for e.g. to find what failed after
+	// an upgrade
+	AfterTrigger string
+}
+
+// Filter returns the entries matching the query, sorted by start time
+func Filter(entries []Entry, q Query) []Entry {
+	afterStart := make(map[string]time.Time)
+	if q.AfterTrigger != "" {
+		for _, e := range entries {
+			if e.Trigger != q.AfterTrigger || e.Status != StatusPassed {
+				continue
+			}
+			if start, ok := afterStart[e.RunID]; !ok || e.Start.Before(start) {
+				afterStart[e.RunID] = e.Start
+			}
+		}
+	}
+
+	var matched []Entry
+	for _, e := range entries {
+		if len(q.RunIDs) > 0 && !contains(q.RunIDs, e.RunID) {
+			continue
+		}
+		if len(q.Triggers) > 0 && !contains(q.Triggers, e.Trigger) {
+			continue
+		}
+		if q.Status != "" && e.Status != q.Status {
+			continue
+		}
+		if !q.Since.IsZero() && e.Start.Before(q.Since) {
+			continue
+		}
+		if !q.Until.IsZero() && e.Start.After(q.Until) {
+			continue
+		}
+		if q.Node != "" && !contains(e.Nodes, q.Node) {
+			continue
+		}
+		if q.Volume != "" && !contains(e.Volumes, q.Volume) {
+			continue
+		}
+		if q.VolumeDriverVersion != "" && !strings.HasPrefix(e.Cluster.VolumeDriverVersion, q.VolumeDriverVersion) {
+			continue
+		}
+		if q.OutcomeType != "" && !hasOutcomeType(e, q.OutcomeType) {
+			continue
+		}
+		if q.AfterTrigger != "" {
+			start, ok := afterStart[e.RunID]
+			if !ok || !e.Start.After(start) {
+				continue
+			}
+		}
+		matched = append(matched, e)
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Start.Before(matched[j].Start)
+	})
+	return matched
+}
+
+// Stats aggregates the executions of a group of entries
+type Stats struct {
+	Total         int
+	Failed        int
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+	// OutcomeTypes counts the outcomes by type
+	OutcomeTypes map[string]int
+}
+
+// FailureRate returns the ratio of failed executions
+func (s *Stats) FailureRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(s.Total)
+}
+
+// AverageDuration returns the average duration of the executions
+func (s *Stats) AverageDuration() time.Duration {
+	if s.Total == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Total)
+}
+
+// Aggregate groups the entries by the given key and computes stats for each group
+func Aggregate(entries []Entry, key func(Entry) string) map[string]*Stats {
+	stats := make(map[string]*Stats)
+	for _, e := range entries {
+		k := key(e)
+		s, ok := stats[k]
+		if !ok {
+			s = &Stats{OutcomeTypes: make(map[string]int)}
+			stats[k] = s
+		}
+		s.Total++
+		if e.Status == StatusFailed {
+			s.Failed++
+		}
+		s.TotalDuration += e.Duration
+		if e.Duration > s.MaxDuration {
+			s.MaxDuration = e.Duration
+		}
+		for _, o := range e.Outcomes {
+			s.OutcomeTypes[o.Type]++
+		}
+	}
+	return stats
+}
+
+// ByTrigger is an Aggregate key grouping entries by trigger
+func ByTrigger(e Entry) string {
+	return e.Trigger
+}
+
+// ByRun is an Aggregate key grouping entries by run
+func ByRun(e Entry) string {
+	return e.RunID
+}
+
+// ByVolumeDriverVersion is an Aggregate key grouping entries by volume driver version
+func ByVolumeDriverVersion(e Entry) string {
+	return e.Cluster.VolumeDriverVersion
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOutcomeType(e Entry, outcomeType string) bool {
+	for _, o := range e.Outcomes {
+		if o.Type == outcomeType {
+			return true
+		}
+	}
+	return false
+}
+
+// LastRunID returns the run of the latest entry
+func LastRunID(entries []Entry) string {
+	var last Entry
+	for _, e := range entries {
+		if last.RunID == "" || e.Start.After(last.Start) {
+			last = e
+		}
+	}
+	return last.RunID
+}
+
+// ReplaySource returns a random source replaying the draws recorded by the entries
+// of a single run
+func ReplaySource(entries []Entry) *chaosrand.Source {
+	var seed int64
+	recorded := make(map[string][]chaosrand.Draw)
+	for _, e := range entries {
+		seed = e.Seed
+		recorded[chaosrand.StreamKey(e.Trigger, e.Execution)] = e.Draws
+	}
+	return chaosrand.NewReplaySource(seed, recorded)
+}
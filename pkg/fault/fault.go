@@ -0,0 +1,113 @@
+package fault
+
+import (
+	"sync"
+)
+
+// Script keeps the scripted outcomes of the operations of a fake driver.
+// Outcomes queued with Queue are consumed one per call in FIFO order. Once the
+// queue of an operation is drained, the sticky error set with Fail (if any) is
+// returned.
+type Script struct {
+	sync.Mutex
+	queued map[string][]error
+	sticky map[string]error
+	calls  map[string]int
+}
+
+// NewScript returns an empty script where every operation succeeds
+func NewScript() *Script {
+	return &Script{
+		queued: make(map[string][]error),
+		sticky: make(map[string]error),
+		calls:  make(map[string]int),
+	}
+}
+
+// Next records a call to op and returns its scripted outcome
+func (s *Script) Next(op string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.calls[op]++
+	if q := s.queued[op]; len(q) > 0 {
+		s.queued[op] = q[1:]
+		return q[0]
+	}
+	return s.sticky[op]
+}
+
+// Queue queues outcomes for the next calls of the given operation. A nil entry
+// lets the corresponding call go through normally.
+func (s *Script) Queue(op string, outcomes ...error) {
+	s.Lock()
+	defer s.Unlock()
+	s.queued[op] = append(s.queued[op], outcomes...)
+}
+
+// Fail makes every call of the given operation fail with err once its queued
+// outcomes are exhausted. Passing a nil err clears the failure.
+func (s *Script) Fail(op string, err error) {
+	s.Lock()
+	defer s.Unlock()
+	if err == nil {
+		delete(s.sticky, op)
+		return
+	}
+	s.sticky[op] = err
+}
+
+// Calls returns how many times the given operation has been invoked
+func (s *Script) Calls(op string) int {
+	s.Lock()
+	defer s.Unlock()
+	return s.calls[op]
+}
+
+// Reset clears all scripted outcomes and call counters
+func (s *Script) Reset() {
+	s.Lock()
+	defer s.Unlock()
+	s.queued = make(map[string][]error)
+	s.sticky = make(map[string]error)
+	s.calls = make(map[string]int)
+}
+
+// Faults gives a fake driver the Script, Fail, Calls and ResetFaults methods for
+// its own operation type. Drivers embed it and call Inject at the start of each
+// scriptable operation.
+type Faults[Op ~string] struct {
+	script *Script
+}
+
+// NewFaults returns faults where every operation succeeds
+func NewFaults[Op ~string]() *Faults[Op] {
+	return &Faults[Op]{script: NewScript()}
+}
+
+// Inject records a call to op and returns its scripted outcome
+func (f *Faults[Op]) Inject(op Op) error {
+	return f.script.Next(string(op))
+}
+
+// Script queues outcomes for the next calls of the given operation. A nil entry
+// lets the corresponding call go through normally.
+func (f *Faults[Op]) Script(op Op, outcomes ...error) {
+	f.script.Queue(string(op), outcomes...)
+}
+
+// Fail makes every call of the given operation fail with err once its queued
+// outcomes are exhausted. Passing a nil err clears the failure.
+func (f *Faults[Op]) Fail(op Op, err error) {
+	f.script.Fail(string(op), err)
+}
+
+// Calls returns how many times the given operation has been invoked
+func (f *Faults[Op]) Calls(op Op) int {
+	return f.script.Calls(string(op))
+}
+
+// ResetFaults clears all scripted outcomes and call counters
+func (f *Faults[Op]) ResetFaults() {
+	f.script.Reset()
+}
@@ -0,0 +1,105 @@
+package fault
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testOp string
+
+const (
+	opCreate testOp = "create"
+	opDelete testOp = "delete"
+)
+
+var (
+	errFirst  = errors.New("first")
+	errSecond = errors.New("second")
+	errSticky = errors.New("sticky")
+)
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		name   string
+		script func(f *Faults[testOp])
+		calls  []testOp
+		want   []error
+		counts map[testOp]int
+	}{
+		{
+			name:   "no script",
+			calls:  []testOp{opCreate, opCreate},
+			want:   []error{nil, nil},
+			counts: map[testOp]int{opCreate: 2, opDelete: 0},
+		},
+		{
+			name: "queued outcomes in order then success",
+			script: func(f *Faults[testOp]) {
+				f.Script(opCreate, errFirst, nil, errSecond)
+			},
+			calls:  []testOp{opCreate, opCreate, opCreate, opCreate},
+			want:   []error{errFirst, nil, errSecond, nil},
+			counts: map[testOp]int{opCreate: 4},
+		},
+		{
+			name: "queued outcomes before the sticky failure",
+			script: func(f *Faults[testOp]) {
+				f.Fail(opCreate, errSticky)
+				f.Script(opCreate, errFirst)
+			},
+			calls:  []testOp{opCreate, opCreate, opCreate},
+			want:   []error{errFirst, errSticky, errSticky},
+			counts: map[testOp]int{opCreate: 3},
+		},
+		{
+			name: "operations are scripted separately",
+			script: func(f *Faults[testOp]) {
+				f.Script(opCreate, errFirst)
+				f.Fail(opDelete, errSticky)
+			},
+			calls:  []testOp{opDelete, opCreate, opDelete, opCreate},
+			want:   []error{errSticky, errFirst, errSticky, nil},
+			counts: map[testOp]int{opCreate: 2, opDelete: 2},
+		},
+		{
+			name: "nil failure clears the sticky failure",
+			script: func(f *Faults[testOp]) {
+				f.Fail(opCreate, errSticky)
+				f.Fail(opCreate, nil)
+			},
+			calls:  []testOp{opCreate},
+			want:   []error{nil},
+			counts: map[testOp]int{opCreate: 1},
+		},
+		{
+			name: "reset clears the script and the counts",
+			script: func(f *Faults[testOp]) {
+				f.Script(opCreate, errFirst)
+				f.Fail(opDelete, errSticky)
+				_ = f.Inject(opDelete)
+				f.ResetFaults()
+			},
+			calls:  []testOp{opCreate, opDelete},
+			want:   []error{nil, nil},
+			counts: map[testOp]int{opCreate: 1, opDelete: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFaults[testOp]()
+			if tt.script != nil {
+				tt.script(f)
+			}
+			var got []error
+			for _, op := range tt.calls {
+				got = append(got, f.Inject(op))
+			}
+			require.Equal(t, tt.want, got)
+			for op, count := range tt.counts {
+				require.Equal(t, count, f.Calls(op), "calls of %s", op)
+			}
+		})
+	}
+}
@@ -0,0 +1,308 @@
+// Package invariant runs checkers continuously, concurrently with longevity
+// triggers, and attributes the violations they find to the disruption windows
+// that were active when the violations appeared.
+package invariant
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+const (
+	// DefaultInterval is the interval of checkers registered without one
+	DefaultInterval = time.Minute
+	// DefaultAttributionSlack is how long after its end a disruption window is
+	// still blamed for new violations, as effects of a disruption can lag
+	DefaultAttributionSlack = 5 * time.Minute
+)
+
+// Window is the period during which a trigger disrupts the cluster
+type Window struct {
+	Trigger string
+	// Nodes are the nodes under intentional disruption
+	Nodes []string
+	Start time.Time
+	// End is zero while the window is active
+	End time.Time
+}
+
+// HasNode returns true if the node is under disruption in the window
+func (w Window) HasNode(nodeName string) bool {
+	for _, n := range w.Nodes {
+		if n == nodeName {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckFunc checks an invariant. active lists the disruption windows active at
+// the time of the check, so that checkers can exclude what is intentionally
+// disrupted, for e.g. a node being rebooted.
+type CheckFunc func(active []Window) error
+
+// Checker is an invariant checked on its own cadence
+type Checker struct {
+	Name string
+	// Interval is the time between two checks
+	Interval time.Duration
+	// Tolerance is how long the invariant can be broken before it's a violation,
+	// for e.g. a volume can be below its HA level while it resyncs
+	Tolerance time.Duration
+	Check     CheckFunc
+}
+
+// Violation is a period during which an invariant was broken
+type Violation struct {
+	Checker string
+	// Err is the latest error of the checker
+	Err       error
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Resolved is zero while the invariant is still broken
+	Resolved time.Time
+	// Windows are the disruption windows active, or recently ended, when the
+	// invariant broke
+	Windows []Window
+}
+
+func (v Violation) String() string {
+	var triggers []string
+	for _, w := range v.Windows {
+		triggers = append(triggers, w.Trigger)
+	}
+	return fmt.Sprintf("invariant [%s] broken since [%s] during disruptions %v: %v",
+		v.Checker, v.FirstSeen.Format(time.RFC1123), triggers, v.Err)
+}
+
+// Monitor runs registered checkers and keeps track of disruption windows
+type Monitor struct {
+	sync.Mutex
+	// AttributionSlack is how long after its end a window is still attributed violations
+	AttributionSlack time.Duration
+
+	checkers   map[string]Checker
+	active     map[string]*Window
+	ended      []Window
+	open       map[string]*Violation
+	violations []*Violation
+	stop       chan struct{}
+	wg         sync.WaitGroup
+	now        func() time.Time
+}
+
+// NewMonitor returns a monitor without checkers
+func NewMonitor() *Monitor {
+	return &Monitor{
+		AttributionSlack: DefaultAttributionSlack,
+		checkers:         make(map[string]Checker),
+		active:           make(map[string]*Window),
+		open:             make(map[string]*Violation),
+		now:              time.Now,
+	}
+}
+
+// Register adds a checker. It must be called before Start.
+func (m *Monitor) Register(c Checker) error {
+	if c.Name == "" || c.Check == nil {
+		return fmt.Errorf("invariant checker needs a name and a check function")
+	}
+	if c.Interval <= 0 {
+		c.Interval = DefaultInterval
+	}
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.checkers[c.Name]; ok {
+		return fmt.Errorf("invariant checker [%s] is already registered", c.Name)
+	}
+	m.checkers[c.Name] = c
+	return nil
+}
+
+// BeginWindow opens the disruption window of a trigger. An already active
+// window of the trigger is ended first.
+func (m *Monitor) BeginWindow(trigger string) {
+	m.Lock()
+	defer m.Unlock()
+	m.endWindow(trigger)
+	m.active[trigger] = &Window{Trigger: trigger, Start: m.now()}
+}
+
+// AddWindowNodes adds nodes under disruption to the active window of the trigger
+func (m *Monitor) AddWindowNodes(trigger string, nodes ...string) {
+	m.Lock()
+	defer m.Unlock()
+	w, ok := m.active[trigger]
+	if !ok {
+		return
+	}
+	for _, n := range nodes {
+		if !w.HasNode(n) {
+			w.Nodes = append(w.Nodes, n)
+		}
+	}
+}
+
+// EndWindow closes the disruption window of a trigger, if active
+func (m *Monitor) EndWindow(trigger string) {
+	m.Lock()
+	defer m.Unlock()
+	m.endWindow(trigger)
+}
+
+func (m *Monitor) endWindow(trigger string) {
+	w, ok := m.active[trigger]
+	if !ok {
+		return
+	}
+	delete(m.active, trigger)
+	w.End = m.now()
+	m.ended = append(m.pruneEnded(w.End), *w)
+}
+
+// pruneEnded drops the ended windows that can no longer be attributed violations at or after t
+func (m *Monitor) pruneEnded(t time.Time) []Window {
+	var ended []Window
+	for _, w := range m.ended {
+		if !w.End.Before(t.Add(-m.AttributionSlack)) {
+			ended = append(ended, w)
+		}
+	}
+	return ended
+}
+
+// ActiveWindows returns the disruption windows active now
+func (m *Monitor) ActiveWindows() []Window {
+	m.Lock()
+	defer m.Unlock()
+	return m.activeWindows()
+}
+
+func (m *Monitor) activeWindows() []Window {
+	var windows []Window
+	for _, w := range m.active {
+		windows = append(windows, copyWindow(*w))
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Start.Before(windows[j].Start)
+	})
+	return windows
+}
+
+// attributedWindows returns the windows active at t or ended within the attribution slack before it
+func (m *Monitor) attributedWindows(t time.Time) []Window {
+	windows := m.activeWindows()
+	for _, w := range m.ended {
+		if !w.End.Before(t.Add(-m.AttributionSlack)) && !w.Start.After(t) {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// Start runs every checker in its own goroutine until Stop is called
+func (m *Monitor) Start() {
+	m.Lock()
+	defer m.Unlock()
+	if m.stop != nil {
+		return
+	}
+	m.stop = make(chan struct{})
+	for _, c := range m.checkers {
+		m.wg.Add(1)
+		go m.run(c, m.stop)
+	}
+}
+
+// Stop stops the checkers and waits for running checks to finish
+func (m *Monitor) Stop() {
+	m.Lock()
+	stop := m.stop
+	m.stop = nil
+	m.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	m.wg.Wait()
+}
+
+func (m *Monitor) run(c Checker, stop chan struct{}) {
+	defer m.wg.Done()
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.check(c)
+		}
+	}
+}
+
+// check runs the checker once and updates its violation
+func (m *Monitor) check(c Checker) {
+	started := m.now()
+	err := c.Check(m.ActiveWindows())
+
+	m.Lock()
+	defer m.Unlock()
+	v, broken := m.open[c.Name]
+	if err == nil {
+		if broken {
+			delete(m.open, c.Name)
+			if v.Resolved.IsZero() && m.isReported(v) {
+				v.Resolved = started
+				log.Infof("Invariant [%s] holds again after [%v]", c.Name, v.Resolved.Sub(v.FirstSeen))
+			}
+		}
+		return
+	}
+
+	if !broken {
+		v = &Violation{
+			Checker:   c.Name,
+			FirstSeen: started,
+			Windows:   m.attributedWindows(started),
+		}
+		m.open[c.Name] = v
+	}
+	v.Err = err
+	v.LastSeen = started
+	if !m.isReported(v) && v.LastSeen.Sub(v.FirstSeen) >= c.Tolerance {
+		m.violations = append(m.violations, v)
+		log.Errorf("Violation: %v", v)
+	}
+}
+
+func (m *Monitor) isReported(v *Violation) bool {
+	for _, reported := range m.violations {
+		if reported == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Violations returns the violations found so far
+func (m *Monitor) Violations() []Violation {
+	m.Lock()
+	defer m.Unlock()
+	var violations []Violation
+	for _, v := range m.violations {
+		violation := *v
+		violation.Windows = append([]Window(nil), v.Windows...)
+		violations = append(violations, violation)
+	}
+	return violations
+}
+
+func copyWindow(w Window) Window {
+	w.Nodes = append([]string(nil), w.Nodes...)
+	return w
+}
@@ -0,0 +1,127 @@
+package invariant
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func TestViolationAttribution(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m := NewMonitor()
+	m.now = clock.now
+
+	offline := map[string]bool{}
+	nodesOnline := Checker{
+		Name:      "pxNodesOnline",
+		Tolerance: 2 * time.Minute,
+		Check: func(active []Window) error {
+			for n := range offline {
+				disrupted := false
+				for _, w := range active {
+					disrupted = disrupted || w.HasNode(n)
+				}
+				if !disrupted {
+					return fmt.Errorf("node [%s] is offline", n)
+				}
+			}
+			return nil
+		},
+	}
+	require.NoError(t, m.Register(nodesOnline))
+	require.Error(t, m.Register(nodesOnline))
+
+	// a node offline under intentional disruption is not a violation
+	m.BeginWindow("rebootNode")
+	m.AddWindowNodes("rebootNode", "node-0")
+	offline["node-0"] = true
+	m.check(nodesOnline)
+	require.Empty(t, m.Violations())
+
+	// the node stays offline after the window, only reported once the tolerance passes
+	clock.advance(time.Minute)
+	m.EndWindow("rebootNode")
+	m.BeginWindow("volumeResize")
+	m.check(nodesOnline)
+	clock.advance(time.Minute)
+	m.check(nodesOnline)
+	require.Empty(t, m.Violations())
+	clock.advance(time.Minute)
+	m.check(nodesOnline)
+
+	violations := m.Violations()
+	require.Len(t, violations, 1)
+	require.Equal(t, "pxNodesOnline", violations[0].Checker)
+	require.Equal(t, clock.t.Add(-2*time.Minute), violations[0].FirstSeen)
+	require.Len(t, violations[0].Windows, 2)
+	require.Equal(t, "volumeResize", violations[0].Windows[0].Trigger)
+	require.Equal(t, "rebootNode", violations[0].Windows[1].Trigger)
+	require.True(t, violations[0].Resolved.IsZero())
+
+	delete(offline, "node-0")
+	clock.advance(time.Minute)
+	m.check(nodesOnline)
+	require.Equal(t, clock.t, m.Violations()[0].Resolved)
+}
+
+func TestTransientFailureBelowTolerance(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	m := NewMonitor()
+	m.now = clock.now
+
+	failing := true
+	c := Checker{Name: "volumeHALevel", Tolerance: 5 * time.Minute, Check: func([]Window) error {
+		if failing {
+			return fmt.Errorf("volume below HA level")
+		}
+		return nil
+	}}
+	require.NoError(t, m.Register(c))
+	m.check(c)
+	clock.advance(time.Minute)
+	failing = false
+	m.check(c)
+	require.Empty(t, m.Violations())
+}
+
+func TestEndedWindowsPruned(t *testing.T) {
+	clock := &fakeClock{t: time.Now()}
+	m := NewMonitor()
+	m.now = clock.now
+
+	m.BeginWindow("rebootNode")
+	m.EndWindow("rebootNode")
+	clock.advance(m.AttributionSlack + time.Minute)
+	m.BeginWindow("crashVolDriver")
+	m.EndWindow("crashVolDriver")
+	require.Len(t, m.ended, 1)
+	require.Equal(t, "crashVolDriver", m.ended[0].Trigger)
+}
+
+func TestStartStop(t *testing.T) {
+	m := NewMonitor()
+	var checks int32
+	require.NoError(t, m.Register(Checker{Name: "kvdbQuorum", Interval: time.Millisecond, Check: func([]Window) error {
+		atomic.AddInt32(&checks, 1)
+		return nil
+	}}))
+	m.Start()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&checks) > 2 }, time.Second, time.Millisecond)
+	m.Stop()
+	m.Stop()
+}
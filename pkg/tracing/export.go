@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// otlpTracesPath is the path of the OTLP/HTTP traces endpoint of a collector
+const otlpTracesPath = "/v1/traces"
+
+// newHTTPExporter returns an exporter to the OTLP/HTTP collector at the given
+// endpoint, for e.g. http://otel-collector:4318
+func newHTTPExporter(endpoint string, headers map[string]string) (sdktrace.SpanExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid trace endpoint [%s], expected for e.g. http://otel-collector:4318", endpoint)
+	}
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if path := strings.TrimSuffix(u.Path, "/"); path != "" {
+		if !strings.HasSuffix(path, otlpTracesPath) {
+			path += otlpTracesPath
+		}
+		opts = append(opts, otlptracehttp.WithURLPath(path))
+	}
+	if len(headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(headers))
+	}
+	return otlptracehttp.New(context.Background(), opts...)
+}
+
+// fileExporter appends spans to a file, one JSON encoded span per line
+type fileExporter struct {
+	*stdouttrace.Exporter
+	file *os.File
+}
+
+// newFileExporter returns an exporter appending to the file at the given path
+func newFileExporter(path string) (sdktrace.SpanExporter, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file [%s]: %v", path, err)
+	}
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &fileExporter{Exporter: exporter, file: file}, nil
+}
+
+// Shutdown stops the exporter and closes the file
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	err := e.Exporter.Shutdown(ctx)
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+// provider wraps the SDK tracer provider so that spans started without a parent
+// in their context are parented to the active span, see StartActive.
+type provider struct {
+	embedded.TracerProvider
+	sdk    *sdktrace.TracerProvider
+	active *activeSpans
+}
+
+func newProvider(active *activeSpans, opts ...sdktrace.TracerProviderOption) *provider {
+	opts = append(opts, sdktrace.WithSpanProcessor(active))
+	return &provider{
+		sdk:    sdktrace.NewTracerProvider(opts...),
+		active: active,
+	}
+}
+
+// Tracer returns a tracer of the provider
+func (p *provider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &tracer{Tracer: p.sdk.Tracer(name, opts...), active: p.active}
+}
+
+type tracer struct {
+	trace.Tracer
+	active *activeSpans
+}
+
+// Start starts a span, child of the span of ctx or else of the active span
+func (t *tracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	if !cfg.NewRoot() && !trace.SpanContextFromContext(ctx).IsValid() {
+		if parent := t.active.parent(); parent.IsValid() {
+			ctx = trace.ContextWithSpanContext(ctx, parent)
+		}
+	}
+	return t.Tracer.Start(ctx, name, opts...)
+}
+
+// activeSpans is the stack of the active spans. It is a span processor so that
+// active spans leave the stack when they end.
+type activeSpans struct {
+	sync.Mutex
+	spans []trace.SpanContext
+}
+
+func (a *activeSpans) push(s trace.SpanContext) {
+	a.Lock()
+	defer a.Unlock()
+	a.spans = append(a.spans, s)
+}
+
+// parent returns the most recently started active span
+func (a *activeSpans) parent() trace.SpanContext {
+	a.Lock()
+	defer a.Unlock()
+	if len(a.spans) == 0 {
+		return trace.SpanContext{}
+	}
+	return a.spans[len(a.spans)-1]
+}
+
+func (a *activeSpans) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (a *activeSpans) OnEnd(s sdktrace.ReadOnlySpan) {
+	a.Lock()
+	defer a.Unlock()
+	for i := len(a.spans) - 1; i >= 0; i-- {
+		if a.spans[i].Equal(s.SpanContext()) {
+			a.spans = append(a.spans[:i], a.spans[i+1:]...)
+			return
+		}
+	}
+}
+
+func (a *activeSpans) Shutdown(context.Context) error {
+	return nil
+}
+
+func (a *activeSpans) ForceFlush(context.Context) error {
+	return nil
+}
@@ -0,0 +1,254 @@
+// Package tracing provides optional OpenTelemetry tracing of tests, triggers and
+// driver calls. Spans are exported with OTLP/HTTP to a collector, or as JSON to a
+// local file for offline analysis. When tracing is not initialized, spans are no-ops.
+//
+// Driver interfaces do not take a context, so spans started without a parent
+// are parented to the most recently started active span (see StartActive), for
+// e.g. driver calls made while a trigger runs are children of the trigger span.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	// ScopeName is the instrumentation scope of the spans started by this package
+	ScopeName = "github.com/portworx/torpedo"
+	// DefaultServiceName is the service name of the exported spans
+	DefaultServiceName = "torpedo"
+)
+
+// Attribute keys of the spans
+const (
+	RunKey     = attribute.Key("torpedo.run")
+	TestKey    = attribute.Key("torpedo.test")
+	TriggerKey = attribute.Key("torpedo.trigger")
+	DriverKey  = attribute.Key("torpedo.driver")
+	NodeKey    = attribute.Key("torpedo.node")
+	VolumeKey  = attribute.Key("torpedo.volume")
+	AppKey     = attribute.Key("torpedo.app")
+	AttemptKey = attribute.Key("torpedo.attempt")
+)
+
+// Config configures the span exporters. Tracing is disabled when neither an
+// endpoint nor a file is set.
+type Config struct {
+	// Endpoint is the OTLP/HTTP collector endpoint, for e.g. http://otel-collector:4318
+	Endpoint string
+	// Headers are sent along with the spans to the collector, for e.g. for auth
+	Headers map[string]string
+	// File is the path of a file the spans are appended to
+	File string
+	// ServiceName is the service.name of the spans, DefaultServiceName if not set
+	ServiceName string
+	// Resource are additional attributes of the exported resource, for e.g. the run ID
+	Resource []attribute.KeyValue
+}
+
+var (
+	lock       sync.Mutex
+	current    *provider
+	tracerImpl trace.Tracer = noop.NewTracerProvider().Tracer(ScopeName)
+)
+
+// Init starts exporting spans. It also sets the global OpenTelemetry tracer
+// provider so that instrumented libraries, for e.g. gRPC clients, are traced.
+func Init(cfg Config) error {
+	if cfg.Endpoint == "" && cfg.File == "" {
+		return nil
+	}
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+	attrs := append([]attribute.KeyValue{semconv.ServiceName(serviceName)}, cfg.Resource...)
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(resource.NewSchemaless(attrs...))}
+
+	if cfg.Endpoint != "" {
+		exporter, err := newHTTPExporter(cfg.Endpoint, cfg.Headers)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	if cfg.File != "" {
+		exporter, err := newFileExporter(cfg.File)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	start(opts...)
+	return nil
+}
+
+func start(opts ...sdktrace.TracerProviderOption) {
+	p := newProvider(&activeSpans{}, opts...)
+	lock.Lock()
+	defer lock.Unlock()
+	current = p
+	tracerImpl = p.Tracer(ScopeName)
+	otel.SetTracerProvider(p)
+}
+
+// Enabled returns true if spans are exported
+func Enabled() bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return current != nil
+}
+
+// Shutdown exports the remaining spans and stops tracing
+func Shutdown() error {
+	lock.Lock()
+	p := current
+	current = nil
+	tracerImpl = noop.NewTracerProvider().Tracer(ScopeName)
+	lock.Unlock()
+	if p == nil {
+		return nil
+	}
+	return p.sdk.Shutdown(context.Background())
+}
+
+// Flush exports the ended spans without waiting for the next flush interval
+func Flush() error {
+	lock.Lock()
+	p := current
+	lock.Unlock()
+	if p == nil {
+		return nil
+	}
+	return p.sdk.ForceFlush(context.Background())
+}
+
+// Span is a span started by this package
+type Span struct {
+	trace.Span
+	ctx context.Context
+}
+
+// Start starts a span, child of the active span if any
+func Start(name string, attrs ...attribute.KeyValue) *Span {
+	return StartWithContext(context.Background(), name, attrs...)
+}
+
+// StartWithContext starts a span, child of the span of ctx or else of the active span
+func StartWithContext(ctx context.Context, name string, attrs ...attribute.KeyValue) *Span {
+	lock.Lock()
+	t := tracerImpl
+	lock.Unlock()
+	ctx, s := t.Start(ctx, name, trace.WithAttributes(attrs...))
+	return &Span{Span: s, ctx: ctx}
+}
+
+// StartActive starts a span which is the parent of the spans started without
+// one, until it ends. Tests and triggers use it so that driver calls made while
+// they run are their children.
+func StartActive(name string, attrs ...attribute.KeyValue) *Span {
+	lock.Lock()
+	p := current
+	lock.Unlock()
+	s := Start(name, attrs...)
+	if p != nil && s.IsRecording() {
+		p.active.push(s.SpanContext())
+	}
+	return s
+}
+
+// Context returns a context holding the span, to pass to calls taking one
+func (s *Span) Context() context.Context {
+	return s.ctx
+}
+
+// Child starts a child span
+func (s *Span) Child(name string, attrs ...attribute.KeyValue) *Span {
+	return StartWithContext(s.ctx, name, attrs...)
+}
+
+// Fail records the error and sets the span status to error, without ending it
+func (s *Span) Fail(err error) {
+	if err != nil {
+		s.RecordError(err)
+		s.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// Finish ends the span, recording the error if not nil
+func (s *Span) Finish(err error) {
+	s.Fail(err)
+	s.End()
+}
+
+// FinishWith ends the span recording the error pointed to, for use in a defer
+// with a named error result: defer span.FinishWith(&err)
+func (s *Span) FinishWith(err *error) {
+	s.Finish(*err)
+}
+
+// Retries wraps a sched-ops task so that each attempt is a child span of s
+func (s *Span) Retries(t func() (interface{}, bool, error)) func() (interface{}, bool, error) {
+	attempt := 0
+	return func() (interface{}, bool, error) {
+		attempt++
+		child := s.Child(fmt.Sprintf("attempt %d", attempt), AttemptKey.Int(attempt))
+		out, retry, err := t()
+		if retry {
+			child.SetAttributes(attribute.Bool("torpedo.retry", true))
+		}
+		child.Finish(err)
+		return out, retry, err
+	}
+}
+
+// Run returns the attribute of a torpedo run ID
+func Run(id string) attribute.KeyValue {
+	return RunKey.String(id)
+}
+
+// Test returns the attribute of a test name
+func Test(name string) attribute.KeyValue {
+	return TestKey.String(name)
+}
+
+// Trigger returns the attribute of a trigger type
+func Trigger(triggerType string) attribute.KeyValue {
+	return TriggerKey.String(strings.Split(triggerType, "<br>")[0])
+}
+
+// Driver returns the attribute of a driver name
+func Driver(name string) attribute.KeyValue {
+	return DriverKey.String(name)
+}
+
+// Node returns the attribute of a node name
+func Node(name string) attribute.KeyValue {
+	return NodeKey.String(name)
+}
+
+// Volume returns the attribute of a volume ID or name
+func Volume(volume string) attribute.KeyValue {
+	return VolumeKey.String(volume)
+}
+
+// App returns the attribute of an app key
+func App(key string) attribute.KeyValue {
+	return AppKey.String(key)
+}
+
+// Apps returns the attribute of several app keys
+func Apps(keys []string) attribute.KeyValue {
+	return AppKey.StringSlice(keys)
+}
@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/portworx/sched-ops/task"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func spansByName(exporter *tracetest.InMemoryExporter) map[string]tracetest.SpanStub {
+	spans := make(map[string]tracetest.SpanStub)
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+	return spans
+}
+
+func TestSpanHierarchy(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	start(sdktrace.WithSyncer(exporter))
+	require.True(t, Enabled())
+
+	test := StartActive("test", Test("RebootNode"))
+	trigger := StartActive("trigger", Trigger("rebootNode<br>node-0"))
+	call := Start("ssh.RunCommand", Node("node-0"))
+	attempts := 0
+	_, err := task.DoRetryWithTimeout(call.Retries(func() (interface{}, bool, error) {
+		attempts++
+		if attempts < 2 {
+			return nil, true, fmt.Errorf("connection refused")
+		}
+		return "ok", false, nil
+	}), time.Second, time.Millisecond)
+	require.NoError(t, err)
+	call.Finish(nil)
+	trigger.Finish(fmt.Errorf("node not up"))
+
+	// once the trigger ended, spans without parent go back to the test
+	_, grpcSpan := otel.Tracer("grpc").Start(test.Context(), "SDK call")
+	grpcSpan.End()
+	after := Start("after")
+	after.End()
+	test.End()
+
+	spans := spansByName(exporter)
+	require.Len(t, spans, 7)
+	require.False(t, spans["test"].Parent.IsValid())
+	require.Equal(t, spans["test"].SpanContext.SpanID(), spans["trigger"].Parent.SpanID())
+	require.Equal(t, spans["trigger"].SpanContext.SpanID(), spans["ssh.RunCommand"].Parent.SpanID())
+	require.Equal(t, spans["ssh.RunCommand"].SpanContext.SpanID(), spans["attempt 1"].Parent.SpanID())
+	require.Equal(t, spans["ssh.RunCommand"].SpanContext.SpanID(), spans["attempt 2"].Parent.SpanID())
+	require.Equal(t, spans["test"].SpanContext.SpanID(), spans["SDK call"].Parent.SpanID())
+	require.Equal(t, spans["test"].SpanContext.SpanID(), spans["after"].Parent.SpanID())
+	for _, s := range spans {
+		require.Equal(t, spans["test"].SpanContext.TraceID(), s.SpanContext.TraceID())
+	}
+	require.Equal(t, codes.Error, spans["trigger"].Status.Code)
+	require.Equal(t, "node not up", spans["trigger"].Status.Description)
+	require.Equal(t, "exception", spans["attempt 1"].Events[0].Name)
+	require.Equal(t, TriggerKey.String("rebootNode"), spans["trigger"].Attributes[0])
+
+	// spans are no-ops once tracing is shut down
+	require.NoError(t, Shutdown())
+	require.False(t, Enabled())
+	noop := StartActive("noop")
+	noop.Finish(fmt.Errorf("ignored"))
+	require.Empty(t, exporter.GetSpans())
+}
+
+func TestExporters(t *testing.T) {
+	var received []*coltracepb.ExportTraceServiceRequest
+	var lock sync.Mutex
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/traces", r.URL.Path)
+		require.Equal(t, "secret", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := &coltracepb.ExportTraceServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, req))
+		lock.Lock()
+		received = append(received, req)
+		lock.Unlock()
+	}))
+	defer collector.Close()
+
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	require.NoError(t, Init(Config{
+		Endpoint: collector.URL,
+		Headers:  map[string]string{"Authorization": "secret"},
+		File:     path,
+	}))
+	s := Start("ValidateVolumes", App("mysql"), Volume("pvc-1"), AttemptKey.Int(3))
+	s.Finish(fmt.Errorf("volume not attached"))
+	require.NoError(t, Shutdown())
+
+	lock.Lock()
+	require.Len(t, received, 1)
+	resourceSpans := received[0].ResourceSpans[0]
+	lock.Unlock()
+	require.Equal(t, "service.name", resourceSpans.Resource.Attributes[0].Key)
+	require.Equal(t, DefaultServiceName, resourceSpans.Resource.Attributes[0].Value.GetStringValue())
+	span := resourceSpans.ScopeSpans[0].Spans[0]
+	require.Equal(t, "ValidateVolumes", span.Name)
+	require.Empty(t, span.ParentSpanId)
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, span.Status.Code)
+	require.Equal(t, int64(3), span.Attributes[2].Value.GetIntValue())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	require.True(t, scanner.Scan())
+	var stub struct {
+		Name   string
+		Status struct {
+			Description string
+		}
+	}
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &stub))
+	require.False(t, scanner.Scan())
+	require.Equal(t, "ValidateVolumes", stub.Name)
+	require.Equal(t, "volume not attached", stub.Status.Description)
+}
@@ -0,0 +1,196 @@
+// Package workerpool runs a task over a collection of inputs with bounded
+// parallelism, cancellation, per-input timeouts and per-input results, so that
+// the failure of a single input is reported rather than lost.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrorMode defines what the pool does once a task fails
+type ErrorMode int
+
+const (
+	// CollectAll runs the task on all inputs and returns the errors of all the failed ones
+	CollectAll ErrorMode = iota
+	// FailFast cancels the remaining tasks on the first failure and returns its error
+	FailFast
+)
+
+// Task is the function run on each input
+type Task[I, O any] func(ctx context.Context, input I) (O, error)
+
+// Options of a pool run
+type Options struct {
+	// Concurrency is the maximum number of tasks running at once, all of the inputs if not set
+	Concurrency int
+	// Timeout of the task on each input, not limited if not set. A task that does not honour
+	// its context keeps running in the background but its input is reported as timed out.
+	Timeout time.Duration
+	// ErrorMode is either CollectAll or FailFast
+	ErrorMode ErrorMode
+	// Progress is called after each input with the number of completed and total inputs
+	Progress func(completed, total int)
+}
+
+// Result of the task on an input
+type Result[I, O any] struct {
+	// Index of the input
+	Index  int
+	Input  I
+	Output O
+	// Err is the error of the task, or the context error if the task was cancelled or timed out
+	Err      error
+	Duration time.Duration
+}
+
+// Entry is a key and value of a map, to run a task over the entries of a map
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Entries returns the entries of the map in no particular order
+func Entries[K comparable, V any](m map[K]V) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, Entry[K, V]{Key: k, Value: v})
+	}
+	return entries
+}
+
+// ForEach runs the task on each input and returns the error of the failed inputs
+func ForEach[I any](ctx context.Context, inputs []I, task func(ctx context.Context, input I) error, opts Options) error {
+	_, err := Run(ctx, inputs, func(ctx context.Context, input I) (struct{}, error) {
+		return struct{}{}, task(ctx, input)
+	}, opts)
+	return err
+}
+
+// Run runs the task on each input and returns the result of each input in the order of the inputs.
+// The error is the first failure in FailFast mode and all the failures in CollectAll mode.
+func Run[I, O any](ctx context.Context, inputs []I, task Task[I, O], opts Options) ([]Result[I, O], error) {
+	results := make([]Result[I, O], len(inputs))
+	for i, input := range inputs {
+		results[i] = Result[I, O]{Index: i, Input: input}
+	}
+	if len(inputs) == 0 {
+		return results, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	concurrency := opts.Concurrency
+	if concurrency <= 0 || concurrency > len(inputs) {
+		concurrency = len(inputs)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+		firstErr  error
+		indexes   = make(chan int)
+	)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := &results[i]
+				start := time.Now()
+				result.Output, result.Err = runTask(ctx, task, result.Input, opts.Timeout)
+				result.Duration = time.Since(start)
+
+				mu.Lock()
+				if result.Err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("task on input [%d] failed: %w", i, result.Err)
+					if opts.ErrorMode == FailFast {
+						cancel()
+					}
+				}
+				completed++
+				if opts.Progress != nil {
+					opts.Progress(completed, len(inputs))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	started := 0
+feed:
+	for ; started < len(inputs); started++ {
+		select {
+		case indexes <- started:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	for i := started; i < len(inputs); i++ {
+		results[i].Err = ctx.Err()
+	}
+
+	if opts.ErrorMode == FailFast && firstErr != nil {
+		return results, firstErr
+	}
+	return results, Errors(results)
+}
+
+// Errors returns the errors of the failed inputs joined, nil if none failed
+func Errors[I, O any](results []Result[I, O]) error {
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("task on input [%d] failed: %w", result.Index, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runTask runs the task on the input within the timeout, a panic of the task is returned as an error
+func runTask[I, O any](ctx context.Context, task Task[I, O], input I, timeout time.Duration) (O, error) {
+	var output O
+	if err := ctx.Err(); err != nil {
+		return output, err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		output O
+		err    error
+	}
+	outcomes := make(chan outcome, 1)
+	go func() {
+		var o outcome
+		defer func() {
+			if r := recover(); r != nil {
+				o.err = fmt.Errorf("task panicked: %v", r)
+			}
+			outcomes <- o
+		}()
+		o.output, o.err = task(ctx, input)
+	}()
+
+	select {
+	case o := <-outcomes:
+		return o.output, o.err
+	case <-ctx.Done():
+		select {
+		case o := <-outcomes:
+			return o.output, o.err
+		default:
+			return output, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	var running, maxRunning int32
+	var progress []int
+	results, err := Run(context.Background(), []int{1, 2, 3, 4, 5, 6}, func(ctx context.Context, input int) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if input%3 == 0 {
+			return "", fmt.Errorf("input %d", input)
+		}
+		return fmt.Sprintf("output %d", input), nil
+	}, Options{
+		Concurrency: 2,
+		Progress: func(completed, total int) {
+			require.Equal(t, 6, total)
+			progress = append(progress, completed)
+		},
+	})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "task on input [2] failed: input 3")
+	require.Contains(t, err.Error(), "task on input [5] failed: input 6")
+	require.Equal(t, int32(2), maxRunning)
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6}, progress)
+	require.Len(t, results, 6)
+	for i, result := range results {
+		require.Equal(t, i, result.Index)
+		require.Equal(t, i+1, result.Input)
+		if result.Input%3 == 0 {
+			require.Error(t, result.Err)
+		} else {
+			require.NoError(t, result.Err)
+			require.Equal(t, fmt.Sprintf("output %d", i+1), result.Output)
+		}
+	}
+}
+
+func TestRunFailFast(t *testing.T) {
+	errFailed := errors.New("failed")
+	var ran int32
+	results, err := Run(context.Background(), make([]int, 10), func(ctx context.Context, input int) (int, error) {
+		if atomic.AddInt32(&ran, 1) == 1 {
+			return 0, errFailed
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, Options{Concurrency: 2, ErrorMode: FailFast})
+
+	require.ErrorIs(t, err, errFailed)
+	require.True(t, atomic.LoadInt32(&ran) <= 3)
+	var failed, canceled int
+	for _, result := range results {
+		switch {
+		case errors.Is(result.Err, errFailed):
+			failed++
+		case errors.Is(result.Err, context.Canceled):
+			canceled++
+		}
+	}
+	require.Equal(t, 1, failed)
+	require.Equal(t, 9, canceled)
+}
+
+func TestRunTimeoutAndPanic(t *testing.T) {
+	results, err := Run(context.Background(), []string{"slow", "panic", "ok"}, func(ctx context.Context, input string) (string, error) {
+		switch input {
+		case "slow":
+			time.Sleep(time.Second)
+		case "panic":
+			panic("boom")
+		}
+		return input, nil
+	}, Options{Timeout: 50 * time.Millisecond})
+
+	require.Error(t, err)
+	require.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+	require.True(t, results[0].Duration < time.Second)
+	require.EqualError(t, results[1].Err, "task panicked: boom")
+	require.NoError(t, results[2].Err)
+	require.Equal(t, "ok", results[2].Output)
+}
+
+func TestForEachEntries(t *testing.T) {
+	var keys []string
+	ch := make(chan string, 3)
+	err := ForEach(context.Background(), Entries(map[string]int{"a": 1, "b": 2, "c": 3}), func(ctx context.Context, entry Entry[string, int]) error {
+		ch <- fmt.Sprintf("%s=%d", entry.Key, entry.Value)
+		return nil
+	}, Options{})
+	require.NoError(t, err)
+	close(ch)
+	for key := range ch {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	require.Equal(t, []string{"a=1", "b=2", "c=3"}, keys)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = ForEach(ctx, []int{1, 2}, func(ctx context.Context, input int) error { return nil }, Options{})
+	require.ErrorIs(t, err, context.Canceled)
+}
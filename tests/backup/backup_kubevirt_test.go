@@ -13,6 +13,7 @@ import (
 	api "github.com/portworx/px-backup-api/pkg/apis/v1"
 	"github.com/portworx/sched-ops/k8s/core"
 	"github.com/portworx/sched-ops/task"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	"github.com/portworx/torpedo/drivers/backup"
 	"github.com/portworx/torpedo/drivers/node"
 	"github.com/portworx/torpedo/drivers/scheduler"
@@ -476,6 +477,7 @@ var _ = Describe("{KubevirtUpgradeTest}", Label(TestCaseLabelsMap[KubevirtUpgrad
 		providers            []string
 		labelSelectors       map[string]string
 		namespaceMapping     map[string]string
+		integritySnapshots   map[string][]*integrity.Snapshot
 		//controlChannel       chan string
 		//errorGroup           *errgroup.Group
 	)
@@ -516,6 +518,13 @@ var _ = Describe("{KubevirtUpgradeTest}", Label(TestCaseLabelsMap[KubevirtUpgrad
 			ValidateApplications(scheduledAppContexts)
 		})
 
+		Step("Writing integrity workload in the VMs", func() {
+			log.InfoD("Writing integrity workload in the VMs")
+			var err error
+			integritySnapshots, err = WriteIntegrityWorkloadInVMs(scheduledAppContexts, integrity.Workload{Name: "pre-upgrade", Seed: time.Now().UnixNano(), Count: 20})
+			log.FailOnError(err, "Writing integrity workload in the VMs")
+		})
+
 		Step("Creating backup location and cloud setting", func() {
 			log.InfoD("Creating backup location and cloud setting")
 			ctx, err := backup.GetAdminCtxFromSecret()
@@ -602,6 +611,24 @@ var _ = Describe("{KubevirtUpgradeTest}", Label(TestCaseLabelsMap[KubevirtUpgrad
 			dash.VerifyFatal(err, nil, fmt.Sprintf("Verifying creation of restore with namespace mapping %s from backup %s", restorePostUpgrade, backupPreUpgrade))
 		})
 
+		Step("Validating integrity workload in the VMs restored post-upgrade", func() {
+			log.InfoD("Validating integrity workload in the VMs restored post-upgrade")
+			err := SetDestinationKubeConfig()
+			log.FailOnError(err, "Switching context to destination cluster failed")
+			defer func() {
+				err := SetSourceKubeConfig()
+				log.FailOnError(err, "Switching context to source cluster failed")
+			}()
+			restoredAppContexts := make([]*scheduler.Context, 0)
+			for _, scheduledAppContext := range scheduledAppContexts {
+				restoredAppContext, err := CloneAppContextAndTransformWithMappings(scheduledAppContext, namespaceMapping, make(map[string]string), true)
+				log.FailOnError(err, "Transforming app context [%s] with namespace mapping", scheduledAppContext.App.Key)
+				restoredAppContexts = append(restoredAppContexts, restoredAppContext)
+			}
+			err = ValidateIntegrityWorkload(context1.TODO(), restoredAppContexts, integritySnapshots, namespaceMapping)
+			dash.VerifyFatal(err, nil, fmt.Sprintf("Verifying integrity workload in the VMs restored from backup %s", backupPreUpgrade))
+		})
+
 		Step("Taking backup of kubevirt application post-upgrade", func() {
 			log.InfoD("Taking backup of kubevirt application post-upgrade")
 			ctx, err := backup.GetAdminCtxFromSecret()
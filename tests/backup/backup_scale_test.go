@@ -1,8 +1,8 @@
 package tests
 
 import (
+	"context"
 	"fmt"
-	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +12,7 @@ import (
 	"github.com/portworx/torpedo/drivers/backup"
 	"github.com/portworx/torpedo/drivers/scheduler"
 	"github.com/portworx/torpedo/pkg/log"
+	"github.com/portworx/torpedo/pkg/workerpool"
 	. "github.com/portworx/torpedo/tests"
 	"golang.org/x/sync/errgroup"
 )
@@ -136,32 +137,26 @@ var _ = Describe("{MultipleBackupLocationWithSameEndpoint}", Label(TestCaseLabel
 			log.InfoD(fmt.Sprintf("Taking restore for each backups created from px-admin"))
 			ctx, err := backup.GetAdminCtxFromSecret()
 			log.FailOnError(err, "Fetching px-central-admin ctx")
-			var wg sync.WaitGroup
-			var mu sync.Mutex
-			errors := make([]string, 0)
+			var userBackups []workerpool.Entry[string, string]
 			for index := 0; index < numberOfBackups; index++ {
-				for backupName, namespace := range userBackupMap[index] {
-					wg.Add(1)
-					go func(backupName, namespace string) {
-						defer GinkgoRecover()
-						defer wg.Done()
-						mu.Lock()
-						restoreName := fmt.Sprintf("%s-%s-%s", RestoreNamePrefix, backupName, RandomString(5))
-						customNamespace := "custom-" + namespace + RandomString(5)
-						namespaceMapping := map[string]string{namespace: customNamespace}
-						restoreNsMapping[restoreName] = namespaceMapping
-						mu.Unlock()
-						err := CreateRestore(restoreName, backupName, namespaceMapping, SourceClusterName, BackupOrgID, ctx, make(map[string]string))
-						if err != nil {
-							mu.Lock()
-							errors = append(errors, fmt.Sprintf("Failed while taking restore [%s]. Error - [%s]", restoreName, err.Error()))
-							mu.Unlock()
-						}
-					}(backupName, namespace)
-				}
+				userBackups = append(userBackups, workerpool.Entries(userBackupMap[index])...)
 			}
-			wg.Wait()
-			dash.VerifyFatal(len(errors), 0, fmt.Sprintf("Creating restores : -\n%s", strings.Join(errors, "}\n{")))
+			var mu sync.Mutex
+			err = workerpool.ForEach(context.Background(), userBackups, func(_ context.Context, userBackup workerpool.Entry[string, string]) error {
+				backupName, namespace := userBackup.Key, userBackup.Value
+				mu.Lock()
+				restoreName := fmt.Sprintf("%s-%s-%s", RestoreNamePrefix, backupName, RandomString(5))
+				customNamespace := "custom-" + namespace + RandomString(5)
+				namespaceMapping := map[string]string{namespace: customNamespace}
+				restoreNsMapping[restoreName] = namespaceMapping
+				mu.Unlock()
+				err := CreateRestore(restoreName, backupName, namespaceMapping, SourceClusterName, BackupOrgID, ctx, make(map[string]string))
+				if err != nil {
+					return fmt.Errorf("failed while taking restore [%s]: %v", restoreName, err)
+				}
+				return nil
+			}, workerpool.Options{})
+			dash.VerifyFatal(err, nil, "Creating restores")
 			log.InfoD("All  mapping list %v", restoreNsMapping)
 
 		})
@@ -170,48 +165,34 @@ var _ = Describe("{MultipleBackupLocationWithSameEndpoint}", Label(TestCaseLabel
 			log.InfoD("Validating all restores")
 			ctx, err := backup.GetAdminCtxFromSecret()
 			log.FailOnError(err, "Fetching px-central-admin ctx")
-			var mutex sync.Mutex
-			errors := make([]string, 0)
-			var wg sync.WaitGroup
-			for restoreName, namespaceMapping := range restoreNsMapping {
-				wg.Add(1)
-				go func(restoreName string, namespaceMapping map[string]string) {
-					defer GinkgoRecover()
-					defer wg.Done()
-					log.InfoD("Validating restore [%s] with namespace mapping", restoreName)
-					expectedRestoredAppContext, _ := CloneAppContextAndTransformWithMappings(scheduledAppContexts[0], namespaceMapping, make(map[string]string), true)
-					if err != nil {
-						mutex.Lock()
-						errors = append(errors, fmt.Sprintf("Failed while context tranforming of restore [%s]. Error - [%s]", restoreName, err.Error()))
-						mutex.Unlock()
-						return
-					}
-					err = ValidateRestore(ctx, restoreName, BackupOrgID, []*scheduler.Context{expectedRestoredAppContext}, make([]string, 0))
-					if err != nil {
-						mutex.Lock()
-						errors = append(errors, fmt.Sprintf("Failed while validating restore [%s]. Error - [%s]", restoreName, err.Error()))
-						mutex.Unlock()
-					}
-				}(restoreName, namespaceMapping)
-			}
-			wg.Wait()
-			dash.VerifyFatal(len(errors), 0, fmt.Sprintf("Validating restores of individual backups -\n%s", strings.Join(errors, "}\n{")))
+			err = workerpool.ForEach(context.Background(), workerpool.Entries(restoreNsMapping), func(_ context.Context, restore workerpool.Entry[string, map[string]string]) error {
+				restoreName, namespaceMapping := restore.Key, restore.Value
+				log.InfoD("Validating restore [%s] with namespace mapping", restoreName)
+				expectedRestoredAppContext, err := CloneAppContextAndTransformWithMappings(scheduledAppContexts[0], namespaceMapping, make(map[string]string), true)
+				if err != nil {
+					return fmt.Errorf("failed while context tranforming of restore [%s]: %v", restoreName, err)
+				}
+				err = ValidateRestore(ctx, restoreName, BackupOrgID, []*scheduler.Context{expectedRestoredAppContext}, make([]string, 0))
+				if err != nil {
+					return fmt.Errorf("failed while validating restore [%s]: %v", restoreName, err)
+				}
+				return nil
+			}, workerpool.Options{})
+			dash.VerifyFatal(err, nil, "Validating restores of individual backups")
 
 		})
 		Step("Delete all Backup locations from px-admin", func() {
 			log.InfoD("Delete Backup locations from px-admin")
 			ctx, err := backup.GetAdminCtxFromSecret()
 			log.FailOnError(err, "failed to fetch ctx for admin")
-			for backupLocationUID, backupLocationName := range backupLocationMap {
-				wg.Add(1)
-				go func(backupLocationName, backupLocationUID string) {
-					defer GinkgoRecover()
-					defer wg.Done()
-					err := DeleteBackupLocationWithContext(backupLocationName, backupLocationUID, BackupOrgID, true, ctx)
-					Inst().Dash.VerifyFatal(err, nil, fmt.Sprintf("Verifying deletion of backup location [%s]", backupLocationName))
-				}(backupLocationName, backupLocationUID)
-			}
-			wg.Wait()
+			err = workerpool.ForEach(context.Background(), workerpool.Entries(backupLocationMap), func(_ context.Context, backupLocation workerpool.Entry[string, string]) error {
+				backupLocationUID, backupLocationName := backupLocation.Key, backupLocation.Value
+				if err := DeleteBackupLocationWithContext(backupLocationName, backupLocationUID, BackupOrgID, true, ctx); err != nil {
+					return fmt.Errorf("failed to delete backup location [%s]: %v", backupLocationName, err)
+				}
+				return nil
+			}, workerpool.Options{})
+			dash.VerifyFatal(err, nil, "Verifying deletion of backup locations")
 		})
 		Step("Wait for Backup location deletion", func() {
 			log.InfoD("Wait for Backup location deletion")
@@ -219,16 +200,15 @@ var _ = Describe("{MultipleBackupLocationWithSameEndpoint}", Label(TestCaseLabel
 			log.FailOnError(err, "failed to fetch ctx for admin")
 			AllBackupLocationMap, err := GetAllBackupLocations(ctx)
 			log.FailOnError(err, "Fetching all backup locations")
-			for backupLocationUID, backupLocationName := range AllBackupLocationMap {
-				wg.Add(1)
-				go func(backupLocationName, backupLocationUID string) {
-					defer GinkgoRecover()
-					defer wg.Done()
-					err := Inst().Backup.WaitForBackupLocationDeletion(ctx, backupLocationName, backupLocationUID, BackupOrgID, BackupLocationDeleteTimeout, BackupLocationDeleteRetryTime)
-					Inst().Dash.VerifyFatal(err, nil, fmt.Sprintf("Verifying waiting for backup location [%s] deletion", backupLocationName))
-				}(backupLocationName, backupLocationUID)
-			}
-			wg.Wait()
+			err = workerpool.ForEach(context.Background(), workerpool.Entries(AllBackupLocationMap), func(_ context.Context, backupLocation workerpool.Entry[string, string]) error {
+				backupLocationUID, backupLocationName := backupLocation.Key, backupLocation.Value
+				err := Inst().Backup.WaitForBackupLocationDeletion(ctx, backupLocationName, backupLocationUID, BackupOrgID, BackupLocationDeleteTimeout, BackupLocationDeleteRetryTime)
+				if err != nil {
+					return fmt.Errorf("failed waiting for backup location [%s] deletion: %v", backupLocationName, err)
+				}
+				return nil
+			}, workerpool.Options{})
+			dash.VerifyFatal(err, nil, "Verifying waiting for backup locations deletion")
 		})
 	})
 	JustAfterEach(func() {
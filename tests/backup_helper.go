@@ -40,6 +40,7 @@ import (
 
 	volsnapv1 "github.com/kubernetes-csi/external-snapshotter/client/v6/apis/volumesnapshot/v1"
 	appDriver "github.com/portworx/torpedo/drivers/applications/driver"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	appUtils "github.com/portworx/torpedo/drivers/utilities"
 
 	"github.com/pborman/uuid"
@@ -461,6 +462,10 @@ var (
 	dataAfterBackupSuffix = "-after-backup"
 )
 
+// observedBackupDurations holds the UIDs of the backups whose duration was observed,
+// so a backup checked several times is only observed once
+var observedBackupDurations sync.Map
+
 // CloudProviderProvisionerSnapshotMap maps cloud provider names to their corresponding sub-maps containing provisioner-snapshot class mappings
 // TODO: Need to update the map for gke,aks and aws
 var CloudProviderProvisionerSnapshotMap = map[string]map[string]struct {
@@ -2693,6 +2698,7 @@ func BackupSuccessCheck(backupName string, orgID string, retryDuration time.Dura
 		reason := resp.GetBackup().GetStatus().Reason
 		for _, status := range statusesExpected {
 			if actual == status {
+				observeBackupDuration(resp.GetBackup())
 				return "", false, nil
 			}
 		}
@@ -2713,6 +2719,23 @@ func BackupSuccessCheck(backupName string, orgID string, retryDuration time.Dura
 	return nil
 }
 
+// observeBackupDuration observes the time a successful backup took, from its creation
+// until its status was last updated to success
+func observeBackupDuration(backup *api.BackupObject) {
+	if backup == nil || backup.Metadata == nil {
+		return
+	}
+	created, completed := backup.GetCreateTime(), backup.GetLastUpdateTime()
+	if created == nil || completed == nil || completed.GetSeconds() < created.GetSeconds() {
+		return
+	}
+	if _, observed := observedBackupDurations.LoadOrStore(backup.GetUid(), true); observed {
+		return
+	}
+	duration := time.Unix(completed.GetSeconds(), int64(completed.GetNanos())).Sub(time.Unix(created.GetSeconds(), int64(created.GetNanos())))
+	Inst().M.ObserveHistogramMetric(BackupDuration, duration.Seconds(), CurrentSpecReport().LeafNodeText)
+}
+
 // BackupWithPartialSuccessCheck inspects backup task
 func BackupWithPartialSuccessCheck(backupName string, orgID string, retryDuration time.Duration, retryInterval time.Duration, ctx context1.Context) error {
 	bkpUid, err := Inst().Backup.GetBackupUID(ctx, backupName, orgID)
@@ -3653,6 +3676,113 @@ func ValidateDataAfterRestore(expectedRestoredAppContexts []*scheduler.Context,
 	return nil
 }
 
+// WriteIntegrityWorkload writes the integrity workload to the apps with data support in the namespaces
+// and returns their checksums keyed by namespace, to be validated after restore by ValidateIntegrityWorkload
+func WriteIntegrityWorkload(ctx context1.Context, namespaces []string, workload integrity.Workload) (map[string][]*integrity.Snapshot, error) {
+	var appHandlers []appDriver.ApplicationDriver
+	for _, namespace := range namespaces {
+		appHandlers = append(appHandlers, NamespaceAppWithDataMap[namespace]...)
+	}
+	return writeIntegrityWorkload(ctx, appHandlers, workload)
+}
+
+// WriteIntegrityWorkloadToApps writes the integrity workload to the apps with data support in the app contexts, in the
+// cluster of the current kubeconfig, and returns their checksums keyed by namespace
+func WriteIntegrityWorkloadToApps(ctx context1.Context, appContexts []*scheduler.Context, workload integrity.Workload) (map[string][]*integrity.Snapshot, error) {
+	appHandlers, err := GetAppDataHandlers(ctx, appContexts)
+	if err != nil {
+		return nil, err
+	}
+	var handlers []appDriver.ApplicationDriver
+	for _, appHandler := range appHandlers {
+		handlers = append(handlers, appHandler)
+	}
+	return writeIntegrityWorkload(ctx, handlers, workload)
+}
+
+func writeIntegrityWorkload(ctx context1.Context, appHandlers []appDriver.ApplicationDriver, workload integrity.Workload) (map[string][]*integrity.Snapshot, error) {
+	snapshots := make(map[string][]*integrity.Snapshot)
+	for _, appHandler := range appHandlers {
+		namespace := appHandler.GetNamespace()
+		err := appHandler.WriteWorkload(ctx, workload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write integrity workload [%s] to [%s] app in namespace [%s]: %v", workload.Name, appHandler.GetApplicationType(), namespace, err)
+		}
+		snapshot, err := appHandler.Checksum(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum [%s] app in namespace [%s]: %v", appHandler.GetApplicationType(), namespace, err)
+		}
+		snapshots[namespace] = append(snapshots[namespace], snapshot)
+	}
+	return snapshots, nil
+}
+
+// ValidateIntegrityWorkload compares the checksums of the apps with data support in the app contexts with the
+// expected snapshots, keyed by their source namespace, which is mapped to the app namespace by namespaceMapping
+func ValidateIntegrityWorkload(ctx context1.Context, appContexts []*scheduler.Context, expected map[string][]*integrity.Snapshot, namespaceMapping map[string]string) error {
+	appHandlers, err := GetAppDataHandlers(ctx, appContexts)
+	if err != nil {
+		return err
+	}
+	actual := make(map[string]*integrity.Snapshot)
+	for key, appHandler := range appHandlers {
+		snapshot, err := appHandler.Checksum(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to checksum [%s] app in namespace [%s]: %v", appHandler.GetApplicationType(), appHandler.GetNamespace(), err)
+		}
+		actual[key] = snapshot
+	}
+
+	var allErrors []string
+	for namespace, snapshots := range expected {
+		if mappedNamespace, ok := namespaceMapping[namespace]; ok {
+			namespace = mappedNamespace
+		}
+		for _, want := range snapshots {
+			got, ok := actual[namespace+"/"+want.AppType]
+			if !ok {
+				allErrors = append(allErrors, fmt.Sprintf("no [%s] app found in namespace [%s]", want.AppType, namespace))
+				continue
+			}
+			if err := got.Compare(want); err != nil {
+				allErrors = append(allErrors, fmt.Sprintf("[%s] app in namespace [%s]: %v", want.AppType, namespace, err))
+			}
+		}
+	}
+	if len(allErrors) != 0 {
+		return fmt.Errorf("data integrity validation failed - [%s]", strings.Join(allErrors, "\n"))
+	}
+	return nil
+}
+
+// GetAppDataHandlers returns the application drivers of the apps with data support in the app contexts,
+// in the cluster of the current kubeconfig, keyed by "<namespace>/<appType>"
+func GetAppDataHandlers(ctx context1.Context, appContexts []*scheduler.Context) (map[string]appDriver.ApplicationDriver, error) {
+	appHandlers := make(map[string]appDriver.ApplicationDriver)
+	for _, appContext := range appContexts {
+		appInfo, err := appUtils.ExtractConnectionInfo(appContext, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !appInfo.StartDataSupport {
+			continue
+		}
+		appHandler, err := appDriver.GetApplicationDriver(appInfo.AppType, appInfo.Hostname, appInfo.User, appInfo.Password,
+			appInfo.Port, appInfo.DBName, appInfo.NodePort, appInfo.Namespace, appInfo.IPAddress, Inst().N)
+		if err != nil {
+			return nil, err
+		}
+		if appInfo.AppType == appType.Kubevirt {
+			if err = appHandler.WaitForVMToBoot(); err != nil {
+				return nil, fmt.Errorf("unable to boot VM [%s] in namespace [%s]: %v", appInfo.Hostname, appInfo.Namespace, err)
+			}
+		}
+		// TODO: This needs to be enhanced to support multiple apps of the same type in one namespace
+		appHandlers[appInfo.Namespace+"/"+appInfo.AppType] = appHandler
+	}
+	return appHandlers, nil
+}
+
 func verifyDataPresentInApp(appHandler appDriver.ApplicationDriver, dataExpected [][]string, appContext context1.Context) error {
 	var isDataPresent = false
 	var allErrorMessage []string
@@ -5379,6 +5509,9 @@ func SafeAppend(mu *sync.Mutex, slice interface{}, elements ...interface{}) inte
 // * function for each input. To simplify this pattern and allow for concurrent execution of the 'task'
 // * function, you can replace the for loops with a call to TaskHandler(taskInputs, task, executionMode), where
 // * 'executionMode' is either 'Parallel' or 'Sequential'.
+//
+// Deprecated: TaskHandler does not return the errors of the tasks nor limit their parallelism,
+// use workerpool.ForEach or workerpool.Run instead.
 func TaskHandler(taskInputs interface{}, task interface{}, executionMode ExecutionMode) error {
 	v := reflect.ValueOf(taskInputs)
 	var keys []reflect.Value
@@ -514,7 +514,7 @@ func getGlobalLockedBucketName(provider string) string {
 // Trigger to create cred and bucket for backup
 func TriggerAddBackupCredAndBucket(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(SetupBackupBucketAndCreds)
 	startLongevityTest(SetupBackupBucketAndCreds)
 
 	event := &EventRecord{
@@ -527,7 +527,7 @@ func TriggerAddBackupCredAndBucket(contexts *[]*scheduler.Context, recordChan *c
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -554,7 +554,7 @@ func TriggerAddBackupCredAndBucket(contexts *[]*scheduler.Context, recordChan *c
 // Trigger to create cred and bucket for backup
 func TriggerAddLockedBackupCredAndBucket(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(SetupBackupLockedBucketAndCreds)
 	startLongevityTest(SetupBackupLockedBucketAndCreds)
 
 	event := &EventRecord{
@@ -567,7 +567,7 @@ func TriggerAddLockedBackupCredAndBucket(contexts *[]*scheduler.Context, recordC
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -593,7 +593,7 @@ func TriggerAddLockedBackupCredAndBucket(contexts *[]*scheduler.Context, recordC
 // Trigger to add a backup cluster
 func TriggerAddBackupCluster(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(AddBackupCluster)
 	startLongevityTest(AddBackupCluster)
 
 	event := &EventRecord{
@@ -606,7 +606,7 @@ func TriggerAddBackupCluster(contexts *[]*scheduler.Context, recordChan *chan *E
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -630,7 +630,7 @@ func TriggerAddBackupCluster(contexts *[]*scheduler.Context, recordChan *chan *E
 // Trigger to deploy backup apps with or without data validation
 func TriggerDeployBackupApps(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(DeployBackupApps)
 	startLongevityTest(DeployBackupApps)
 
 	event := &EventRecord{
@@ -643,7 +643,7 @@ func TriggerDeployBackupApps(contexts *[]*scheduler.Context, recordChan *chan *E
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	result := GetLongevityEventResponse()
@@ -669,7 +669,7 @@ func TriggerDeployBackupApps(contexts *[]*scheduler.Context, recordChan *chan *E
 // Trigger to create backup and validate
 func TriggerCreateBackup(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CreatePxBackup)
 	startLongevityTest(CreatePxBackup)
 
 	event := &EventRecord{
@@ -682,7 +682,7 @@ func TriggerCreateBackup(contexts *[]*scheduler.Context, recordChan *chan *Event
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -710,7 +710,7 @@ func TriggerCreateBackup(contexts *[]*scheduler.Context, recordChan *chan *Event
 // Trigger to create backup and validate
 func TriggerCreateLockedBackup(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CreatePxLockedBackup)
 	startLongevityTest(CreatePxLockedBackup)
 
 	event := &EventRecord{
@@ -723,7 +723,7 @@ func TriggerCreateLockedBackup(contexts *[]*scheduler.Context, recordChan *chan
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -750,7 +750,7 @@ func TriggerCreateLockedBackup(contexts *[]*scheduler.Context, recordChan *chan
 // Trigger to create backup and restore from same backup
 func TriggerCreateBackupAndRestore(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CreatePxBackupAndRestore)
 	startLongevityTest(CreatePxBackupAndRestore)
 
 	event := &EventRecord{
@@ -763,7 +763,7 @@ func TriggerCreateBackupAndRestore(contexts *[]*scheduler.Context, recordChan *c
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -794,7 +794,7 @@ func TriggerCreateBackupAndRestore(contexts *[]*scheduler.Context, recordChan *c
 func TriggerCreateRandomRestore(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 
 	defer GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CreateRandomRestore)
 	startLongevityTest(CreateRandomRestore)
 
 	event := &EventRecord{
@@ -807,7 +807,7 @@ func TriggerCreateRandomRestore(contexts *[]*scheduler.Context, recordChan *chan
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
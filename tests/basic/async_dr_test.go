@@ -1,8 +1,8 @@
 package tests
 
 import (
-	//"context"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -23,6 +23,7 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	"github.com/portworx/torpedo/drivers/node"
 	"github.com/portworx/torpedo/drivers/scheduler"
 	"github.com/portworx/torpedo/pkg/applicationbackup"
@@ -52,6 +53,7 @@ const (
 	migrationSchedKey         = "mig-sched-"
 	metromigrationKey         = "metro-dr-"
 	clusterwideNs             = "openshift-operators"
+	continuityMeasureTimeout  = 10 * time.Minute
 )
 
 var (
@@ -353,7 +355,7 @@ var _ = Describe("{StorkctlPerformFailoverFailbackDefaultAsyncIncludeNs}", func(
 		wantAllAfterSuiteActions = false
 	})
 	JustBeforeEach(func() {
-		StartTorpedoTest("StorkctlPerformFailoverFailbackDefaultAsyncIncludeNs", "Failover and Failback using storkctl on async cluster with Include Ns", nil, testrailID)
+		StartTorpedoTest("StorkctlPerformFailoverFailbackDefaultAsyncIncludeNs", "Failover and Failback using storkctl on async cluster with Include Ns, without RPO and RTO measurement as only the included Ns fails over", nil, testrailID)
 		runID = testrailuttils.AddRunsToMilestone(testrailID)
 	})
 
@@ -380,7 +382,7 @@ var _ = Describe("{StorkctlPerformFailoverFailbackDefaultAsyncExcludeNs}", func(
 		wantAllAfterSuiteActions = false
 	})
 	JustBeforeEach(func() {
-		StartTorpedoTest("StorkctlPerformFailoverFailbackDefaultAsyncExcludeNs", "Failover and Failback using storkctl on async cluster with Exclude Ns", nil, testrailID)
+		StartTorpedoTest("StorkctlPerformFailoverFailbackDefaultAsyncExcludeNs", "Failover and Failback using storkctl on async cluster with Exclude Ns, without RPO and RTO measurement as the excluded Ns does not fail over", nil, testrailID)
 		runID = testrailuttils.AddRunsToMilestone(testrailID)
 	})
 
@@ -994,6 +996,10 @@ func upgradePX(upgradeHop string, storageNodes []node.Node) (string, string, int
 	return upgradeStatus, updatedPXVersion, durationInMins
 }
 
+// validateFailoverFailback deploys the apps, migrates them with storkctl and fails them over and back, validating
+// the integrity workload written before the migration after the failover. The RPO and RTO of the failover and
+// failback are measured on the failed over apps, unless namespaces are excluded as the failback then leaves out
+// other apps than the failover.
 func validateFailoverFailback(clusterType, taskNamePrefix string, single, skipSourceOp, includeNs, excludeNs bool) {
 	defaultNs := "kube-system"
 	migrationNamespaces, contexts := initialSetupApps(taskNamePrefix, single)
@@ -1002,6 +1008,18 @@ func validateFailoverFailback(clusterType, taskNamePrefix string, single, skipSo
 	log.FailOnError(err, "Failed to get source configPath: %v", err)
 	kubeConfigPathDest, err := GetCustomClusterConfigPath(asyncdr.SecondCluster)
 	log.FailOnError(err, "Failed to get destination configPath: %v", err)
+	// Apps left out of the failover are never written to again, so the continuity workload only writes to the
+	// failed over apps. With excluded namespaces the failback leaves out another app, so it is not measured.
+	measureContinuity := !excludeNs
+	continuityContexts := failedOverContexts(contexts, single, includeNs, excludeNs)
+	// The integrity workload is written before the first migration, so it must be found in the failed over apps
+	integritySnapshots, err := WriteIntegrityWorkloadToApps(context.Background(), contexts, integrity.Workload{Name: taskNamePrefix, Seed: time.Now().UnixNano(), Count: 100})
+	log.FailOnError(err, "Failed to write integrity workload")
+	var continuityWorkload *ContinuityWorkload
+	if measureContinuity {
+		continuityWorkload, err = StartContinuityWorkload(context.Background(), continuityContexts)
+		log.FailOnError(err, "Failed to start continuity workload")
+	}
 	if single {
 		defaultNs = migrationNamespaces[0]
 		migNamespaces = defaultNs
@@ -1044,7 +1062,16 @@ func validateFailoverFailback(clusterType, taskNamePrefix string, single, skipSo
 		extraArgsFailoverFailback: extraArgsFailoverFailback,
 		contexts:                  contexts,
 	}
+	var failoverAt time.Time
+	if measureContinuity {
+		failoverAt = continuityWorkload.MarkFailover()
+	}
 	performFailoverFailback(failoverParam)
+	err = ValidateIntegrityWorkload(context.Background(), failedOverContexts(contexts, single, includeNs, excludeNs), integritySnapshots, nil)
+	dash.VerifyFatal(err, nil, "Validate integrity workload after failover")
+	if measureContinuity {
+		measureFailoverContinuity(continuityWorkload, clusterType, "failover", failoverAt, continuityContexts)
+	}
 	if skipSourceOp {
 		err = hardSetConfig(kubeConfigPathSrc)
 		log.FailOnError(err, "Error setting source config: %v", err)
@@ -1076,7 +1103,18 @@ func validateFailoverFailback(clusterType, taskNamePrefix string, single, skipSo
 			extraArgsFailoverFailback: extraArgsFailoverFailback,
 			contexts:                  contexts,
 		}
+		var failbackAt time.Time
+		if measureContinuity {
+			failbackAt = continuityWorkload.MarkFailover()
+		}
 		performFailoverFailback(failoverback)
+		if measureContinuity {
+			measureFailoverContinuity(continuityWorkload, clusterType, "failback", failbackAt, continuityContexts)
+		}
+	}
+	if measureContinuity {
+		err = continuityWorkload.Stop()
+		log.FailOnError(err, "Continuity workload failed")
 	}
 	err = asyncdr.WaitForNamespaceDeletion(migrationNamespaces)
 	if err != nil {
@@ -1092,6 +1130,27 @@ func validateFailoverFailback(clusterType, taskNamePrefix string, single, skipSo
 	}
 }
 
+// failedOverContexts returns the contexts of the apps which are failed over, in the same way as validatePodsRunning
+func failedOverContexts(contexts []*scheduler.Context, single, includeNs, excludeNs bool) []*scheduler.Context {
+	if includeNs || single {
+		return contexts[:1]
+	}
+	if excludeNs {
+		return contexts[1:]
+	}
+	return contexts
+}
+
+// measureFailoverContinuity switches the continuity writers to the apps in the cluster of the current kubeconfig,
+// where the apps were failed over or failed back to, and publishes their RPO and RTO
+func measureFailoverContinuity(continuityWorkload *ContinuityWorkload, clusterType, action string, failoverAt time.Time, contexts []*scheduler.Context) {
+	err := continuityWorkload.Resume(context.Background(), contexts, continuityMeasureTimeout)
+	log.FailOnError(err, "Failed to resume continuity workload after %s", action)
+	measurements, err := continuityWorkload.Measure(context.Background(), failoverAt, continuityMeasureTimeout)
+	log.FailOnError(err, "Failed to measure RPO and RTO of %s", action)
+	PublishContinuityStats(clusterType, action, measurements)
+}
+
 func getClusterDomainsInfo() bool {
 	skipFlag := false
 	listCdsTask := func() (interface{}, bool, error) {
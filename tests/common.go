@@ -14,7 +14,6 @@ import (
 	"io/ioutil"
 	"maps"
 	"math"
-	"math/rand"
 	"net/http"
 	"net/url"
 	"os"
@@ -68,6 +67,7 @@ import (
 	"github.com/portworx/sched-ops/task"
 	v1 "k8s.io/api/policy/v1"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/multierr"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/api/iterator"
@@ -118,6 +118,8 @@ import (
 	torpedovolume "github.com/portworx/torpedo/drivers/volume"
 	"github.com/portworx/torpedo/pkg/aetosutil"
 	"github.com/portworx/torpedo/pkg/asyncdr"
+	"github.com/portworx/torpedo/pkg/chaosrand"
+	"github.com/portworx/torpedo/pkg/eventjournal"
 	"github.com/portworx/torpedo/pkg/jirautils"
 	"github.com/portworx/torpedo/pkg/log"
 	"github.com/portworx/torpedo/pkg/osutils"
@@ -125,6 +127,7 @@ import (
 	"github.com/portworx/torpedo/pkg/s3utils"
 	"github.com/portworx/torpedo/pkg/stats"
 	"github.com/portworx/torpedo/pkg/testrailuttils"
+	"github.com/portworx/torpedo/pkg/tracing"
 	"github.com/portworx/torpedo/pkg/units"
 
 	// import ssh driver to invoke it's init
@@ -142,6 +145,8 @@ import (
 
 	// import oracle driver to invoke it's init
 	_ "github.com/portworx/torpedo/drivers/node/oracle"
+	// import local driver to invoke it's init
+	_ "github.com/portworx/torpedo/drivers/node/local"
 
 	// import ssh driver to invoke it's init
 	_ "github.com/portworx/torpedo/drivers/node/ssh"
@@ -149,6 +154,7 @@ import (
 
 	// import scheduler drivers to invoke it's init
 	_ "github.com/portworx/torpedo/drivers/scheduler/dcos"
+	_ "github.com/portworx/torpedo/drivers/scheduler/fake"
 	"github.com/portworx/torpedo/drivers/scheduler/k8s"
 	"github.com/portworx/torpedo/drivers/scheduler/spec"
 
@@ -178,6 +184,8 @@ import (
 	_ "github.com/portworx/torpedo/drivers/volume/aws"
 	// import azure driver to invoke it's init
 	_ "github.com/portworx/torpedo/drivers/volume/azure"
+	// import fakepx driver to invoke it's init
+	_ "github.com/portworx/torpedo/drivers/volume/fakepx"
 
 	// import generic csi driver to invoke it's init
 	_ "github.com/portworx/torpedo/drivers/volume/generic_csi"
@@ -334,6 +342,17 @@ const (
 	skipSystemCheckCliFlag           = "torpedo-skip-system-checks"
 	dataIntegrityValidationTestsFlag = "data-integrity-validation-tests"
 	faSecretCliFlag                  = "fa-secret"
+	eventJournalCliFlag              = "event-journal"
+	randomSeedCliFlag                = "random-seed"
+	replayJournalCliFlag             = "replay-journal"
+	replayRunCliFlag                 = "replay-run"
+	traceEndpointCliFlag             = "trace-endpoint"
+	traceFileCliFlag                 = "trace-file"
+	metricsPortCliFlag               = "metrics-port"
+	metricsPushGatewayCliFlag        = "metrics-pushgateway-url"
+	metricsRemoteWriteCliFlag        = "metrics-remote-write-url"
+	metricsPushIntervalCliFlag       = "metrics-push-interval"
+	specProfilesCliFlag              = "spec-profiles"
 
 	// PSA Specific
 	kubeApiServerConfigFilePath     = "/etc/kubernetes/manifests/kube-apiserver.yaml"
@@ -713,6 +732,7 @@ func InitInstance() {
 		AnthosAdminWorkStationNodeIP:     Inst().AnthosAdminWorkStationNodeIP,
 		AnthosInstancePath:               Inst().AnthosInstPath,
 		UpgradeHops:                      Inst().SchedUpgradeHops,
+		SpecProfiles:                     Inst().SpecProfiles,
 	})
 
 	log.FailOnError(err, "Error occured while Scheduler Driver Initialization")
@@ -737,7 +757,14 @@ func InitInstance() {
 	err = Inst().V.Init(Inst().S.String(), Inst().N.String(), token, Inst().Provisioner, Inst().CsiGenericDriverConfigMap)
 	log.FailOnError(err, "Error occurred while Volume Driver Initialization")
 
-	err = Inst().M.Init(Inst().JobName, Inst().JobType)
+	err = Inst().M.Init(monitor.InitOptions{
+		JobName:        Inst().JobName,
+		JobType:        Inst().JobType,
+		MetricsPort:    Inst().MetricsPort,
+		PushGatewayURL: Inst().MetricsPushGatewayURL,
+		RemoteWriteURL: Inst().MetricsRemoteWriteURL,
+		PushInterval:   Inst().MetricsPushInterval,
+	})
 	log.FailOnError(err, "Error occurred while monitor Initialization")
 
 	if Inst().Backup != nil {
@@ -1441,12 +1468,35 @@ func ValidateVolumes(ctx *scheduler.Context, errChan ...*chan error) {
 				if err != nil {
 					PrintDescribeContext(ctx)
 					processError(err, errChan...)
+				} else {
+					observeVolumeAttachTime(vol)
 				}
 			})
 		}
 	})
 }
 
+// attachTimeObserved holds the volumes whose attach time was observed, it is observed once per volume
+var attachTimeObserved sync.Map
+
+// observeVolumeAttachTime adds the time taken by the volume to be attached after its creation to the
+// volume attach time metric. Volumes not attached yet, or not inspectable by the volume driver, are skipped.
+func observeVolumeAttachTime(volumeName string) {
+	if _, ok := attachTimeObserved.Load(volumeName); ok {
+		return
+	}
+	vol, err := Inst().V.InspectVolume(volumeName)
+	if err != nil || vol.GetAttachTime().GetSeconds() == 0 || vol.GetCtime().GetSeconds() == 0 {
+		return
+	}
+	attachTime := vol.GetAttachTime().AsTime().Sub(vol.GetCtime().AsTime())
+	if attachTime < 0 {
+		return
+	}
+	attachTimeObserved.Store(volumeName, true)
+	Inst().M.ObserveHistogramMetric(VolumeAttachTime, attachTime.Seconds(), ginkgo.CurrentSpecReport().LeafNodeText)
+}
+
 // ValidatePureSnapshotsSDK is the ginkgo spec for validating Pure direct access volume snapshots using API for a context
 func ValidatePureSnapshotsSDK(ctx *scheduler.Context, errChan ...*chan error) {
 	Step("For validation of an app's volumes", func() {
@@ -6740,6 +6790,7 @@ func HaIncreaseErrorInjectionTargetNode(event *EventRecord, ctx *scheduler.Conte
 						}
 						dashStats := make(map[string]string)
 						dashStats["node"] = newReplNode.Name
+						event.AddTargetNode(newReplNode.Name)
 
 						stepLog = fmt.Sprintf("%s target node %s while repl increase is in-progres", action,
 							newReplNode.Hostname)
@@ -6901,6 +6952,7 @@ func HaIncreaseErrorInjectSourceNode(event *EventRecord, ctx *scheduler.Context,
 									replNodeToReboot := storageNodeMap[nID]
 									dashStats := make(map[string]string)
 									dashStats["node"] = replNodeToReboot.Name
+									event.AddTargetNode(replNodeToReboot.Name)
 									log.Infof("selected repl node: %s", replNodeToReboot.Name)
 									if errInj == PX_RESTART {
 										if event != nil {
@@ -7534,6 +7586,19 @@ func Inst() *Torpedo {
 	return instance
 }
 
+// NewInstance returns a copy of the Torpedo instance sharing its drivers, to be
+// set with SetInstance and initialized with InitInstance for another cluster
+func NewInstance() *Torpedo {
+	t := *instance
+	t.InstanceID = time.Now().Format("01-02-15h04m05s")
+	return &t
+}
+
+// SetInstance sets the Torpedo instance returned by Inst
+func SetInstance(t *Torpedo) {
+	instance = t
+}
+
 var instance *Torpedo
 var once sync.Once
 
@@ -7594,6 +7659,13 @@ type Torpedo struct {
 	AnthosInstPath                      string
 	SkipSystemChecks                    bool
 	FaSecret                            string
+	EventJournal                        *eventjournal.Writer
+	ReplayEvents                        []eventjournal.Entry
+	MetricsPort                         string
+	MetricsPushGatewayURL               string
+	MetricsRemoteWriteURL               string
+	MetricsPushInterval                 time.Duration
+	SpecProfiles                        []string
 }
 
 // ParseFlags parses command line flags
@@ -7655,6 +7727,13 @@ func ParseFlags() {
 	var anthosWsNodeIp string
 	var anthosInstPath string
 	var faSecret string
+	var eventJournalPath string
+	var randomSeed int64
+	var replayJournalPath, replayRunID string
+	var traceEndpoint, traceFile string
+	var metricsPort, metricsPushGatewayURL, metricsRemoteWriteURL string
+	var metricsPushInterval time.Duration
+	var specProfilesCSV string
 
 	log.Infof("The default scheduler is %v", defaultScheduler)
 	flag.StringVar(&s, schedulerCliFlag, defaultScheduler, "Name of the scheduler to use")
@@ -7731,6 +7810,17 @@ func ParseFlags() {
 	flag.StringVar(&anthosWsNodeIp, anthosWsNodeIpCliFlag, "", "Anthos admin work station node IP")
 	flag.StringVar(&anthosInstPath, anthosInstPathCliFlag, "", "Anthos config path where all conf files present")
 	flag.StringVar(&faSecret, faSecretCliFlag, "", "comma seperated list of famanagementip=tokenValue pairs")
+	flag.StringVar(&eventJournalPath, eventJournalCliFlag, "", "Path of the JSON-lines journal where longevity trigger executions are appended")
+	flag.Int64Var(&randomSeed, randomSeedCliFlag, 0, "Seed of the random decisions taken by triggers, a time based seed is used if not set")
+	flag.StringVar(&replayJournalPath, replayJournalCliFlag, "", "Path of an event journal to replay the trigger executions of, in order and with the same random decisions")
+	flag.StringVar(&replayRunID, replayRunCliFlag, "", "ID of the run to replay from the replay journal, defaults to the latest run")
+	flag.StringVar(&traceEndpoint, traceEndpointCliFlag, "", "OTLP/HTTP collector endpoint tests, triggers and driver calls are traced to, for e.g. http://otel-collector:4318")
+	flag.StringVar(&traceFile, traceFileCliFlag, "", "Path of a file tests, triggers and driver calls are traced to, one JSON encoded span per line")
+	flag.StringVar(&metricsPort, metricsPortCliFlag, "", "Port of the metrics scrape endpoint, 0 to disable it")
+	flag.StringVar(&metricsPushGatewayURL, metricsPushGatewayCliFlag, "", "URL of a Prometheus Pushgateway metrics are pushed to, so that they outlive the run")
+	flag.StringVar(&metricsRemoteWriteURL, metricsRemoteWriteCliFlag, "", "URL of a Prometheus remote-write endpoint metrics are pushed to, so that they outlive the run")
+	flag.DurationVar(&metricsPushInterval, metricsPushIntervalCliFlag, 0, "Interval at which metrics are pushed when a push URL is set")
+	flag.StringVar(&specProfilesCSV, specProfilesCliFlag, "", "Comma-separated list of environment profiles the app specs are rendered with, for e.g. ocp,restricted-psa")
 
 	// System checks https://github.com/portworx/torpedo/blob/86232cb195400d05a9f83d57856f8f29bdc9789d/tests/common.go#L2173
 	// should be skipped from AfterSuite() if this flag is set to true. This is to avoid distracting test failures due to
@@ -7757,6 +7847,11 @@ func ParseFlags() {
 		appList = append(appList, csiAppsList...)
 	}
 
+	specProfiles, err := splitCsv(specProfilesCSV)
+	if err != nil {
+		log.Fatalf("failed to parse spec profiles: %v. err: %v", specProfilesCSV, err)
+	}
+
 	secureAppList := make([]string, 0)
 
 	if secureAppsCSV == "all" {
@@ -7914,6 +8009,33 @@ func ParseFlags() {
 
 		dash.TestSet = &testSet
 
+		var eventJournal *eventjournal.Writer
+		if eventJournalPath != "" {
+			eventJournal, err = eventjournal.Open(eventJournalPath)
+			log.FailOnError(err, "failed to open event journal")
+		}
+
+		var replayEvents []eventjournal.Entry
+		if replayJournalPath != "" {
+			entries, err := eventjournal.Read(replayJournalPath)
+			log.FailOnError(err, "failed to read replay journal")
+			if replayRunID == "" {
+				replayRunID = eventjournal.LastRunID(entries)
+			}
+			replayEvents = eventjournal.Filter(entries, eventjournal.Query{RunIDs: []string{replayRunID}})
+			if len(replayEvents) == 0 {
+				log.Fatalf("no events of run [%s] found in replay journal [%s]", replayRunID, replayJournalPath)
+			}
+			TriggerRand = eventjournal.ReplaySource(replayEvents)
+			log.Infof("Replaying [%d] events of run [%s] with random seed [%d]", len(replayEvents), replayRunID, TriggerRand.Seed())
+		} else {
+			if randomSeed == 0 {
+				randomSeed = time.Now().UnixNano()
+			}
+			TriggerRand = chaosrand.NewSource(randomSeed)
+			log.Infof("Using random seed [%d] for triggers, set --%s to reproduce the run", randomSeed, randomSeedCliFlag)
+		}
+
 		once.Do(func() {
 			instance = &Torpedo{
 				InstanceID:                          time.Now().Format("01-02-15h04m05s"),
@@ -7969,11 +8091,25 @@ func ParseFlags() {
 				IsPDSApps:                           deployPDSApps,
 				SkipSystemChecks:                    skipSystemChecks,
 				FaSecret:                            faSecret,
+				EventJournal:                        eventJournal,
+				ReplayEvents:                        replayEvents,
+				MetricsPort:                         metricsPort,
+				MetricsPushGatewayURL:               metricsPushGatewayURL,
+				MetricsRemoteWriteURL:               metricsRemoteWriteURL,
+				MetricsPushInterval:                 metricsPushInterval,
+				SpecProfiles:                        specProfiles,
 			}
 			if instance.S.String() == "openshift" {
 				instance.LogLoc = "/mnt"
 			}
 		})
+
+		err = tracing.Init(tracing.Config{
+			Endpoint: traceEndpoint,
+			File:     traceFile,
+			Resource: []attribute.KeyValue{tracing.Run(instance.InstanceID)},
+		})
+		log.FailOnError(err, "failed to initialize tracing")
 	}
 
 	printFlags()
@@ -8880,6 +9016,7 @@ func StartTorpedoTest(testName, testDescription string, tags map[string]string,
 	tags["pureSANType"] = Inst().PureSANType
 	tags["pureFADAPod"] = Inst().PureFADAPod
 	dash.TestCaseBegin(testName, testDescription, strconv.Itoa(testRepoID), tags)
+	testSpan = tracing.StartActive("test "+testName, tracing.Test(testName))
 	if TestRailSetupSuccessful && testRepoID != 0 {
 		RunIdForSuite = testrailuttils.AddRunsToMilestone(testRepoID)
 		CurrentTestRailTestCaseId = testRepoID
@@ -8979,10 +9116,46 @@ func ToggleMgmtNetworkInterfaces(PureMgmtIpCounter int, PureMgmtIPList []string,
 	return LastDisabledInterface, nil
 }
 
+// testSpan is the span of the running test, the driver calls made during the test are its children
+var testSpan *tracing.Span
+
+// endTestSpan ends the span of the running test and exports the spans of the test
+func endTestSpan() {
+	if testSpan == nil {
+		return
+	}
+	var err error
+	if report := ginkgo.CurrentSpecReport(); report.Failed() {
+		err = fmt.Errorf("%s", report.FailureMessage())
+	}
+	testSpan.Finish(err)
+	testSpan = nil
+	if err = tracing.Flush(); err != nil {
+		log.Warnf("failed to export test spans. Err: %v", err)
+	}
+}
+
+// ShutdownTracing exports the remaining spans and stops tracing. Suites call it
+// from their AfterSuite, once no more spans are started.
+func ShutdownTracing() {
+	if err := tracing.Shutdown(); err != nil {
+		log.Warnf("failed to export the remaining spans. Err: %v", err)
+	}
+}
+
+// pushMetrics pushes the metrics of the test, in case the process exits before the next periodic push
+func pushMetrics() {
+	if err := Inst().M.Push(); err != nil {
+		log.Warnf("failed to push metrics. Err: %v", err)
+	}
+}
+
 // EndTorpedoTest ends the logging for torpedo test
 func EndTorpedoTest() {
 	CloseLogger(TestLogger)
 	dash.TestCaseEnd()
+	endTestSpan()
+	pushMetrics()
 	if os.Getenv("TOGGLE_PURE_MGMT_IP") != "" {
 		var err error
 		LastDisabledInterface, err = ToggleMgmtNetworkInterfaces(PureMgmtIpCounter, PureMgmtIPList, PureFaClientVif, LastDisabledInterface)
@@ -9007,6 +9180,8 @@ func EndPxBackupTorpedoTest(contexts []*scheduler.Context) {
 	}()
 	CloseLogger(TestLogger)
 	dash.TestCaseEnd()
+	endTestSpan()
+	pushMetrics()
 	if TestRailSetupSuccessful && CurrentTestRailTestCaseId != 0 && RunIdForSuite != 0 {
 		AfterEachTest(contexts, CurrentTestRailTestCaseId, RunIdForSuite)
 	}
@@ -9252,7 +9427,7 @@ outer:
 
 func GetRandomStorageLessNode(slNodes []node.Node) node.Node {
 	// pick a random storageless node
-	randomIndex := rand.Intn(len(slNodes))
+	randomIndex := helperRand().Intn(len(slNodes))
 	for _, slNode := range slNodes {
 		if randomIndex == 0 {
 			return slNode
@@ -9521,9 +9696,9 @@ func GetSubsetOfSlice[T any](items []T, length int) ([]T, error) {
 	randomItems := make([]T, length)
 	selected := make(map[int]bool)
 	for i := 0; i < length; i++ {
-		j := rand.Intn(len(items))
+		j := helperRand().Intn(len(items))
 		for selected[j] {
-			j = rand.Intn(len(items))
+			j = helperRand().Intn(len(items))
 		}
 		selected[j] = true
 		randomItems[i] = items[j]
@@ -9849,11 +10024,10 @@ func WaitForPoolStatusToUpdate(nodeSelected node.Node, expectedStatus string) er
 
 // RandomString generates a random lowercase string of length characters.
 func RandomString(length int) string {
-	rand.Seed(time.Now().UnixNano())
 	const letters = "abcdefghijklmnopqrstuvwxyz"
 	randomBytes := make([]byte, length)
 	for i := range randomBytes {
-		randomBytes[i] = letters[rand.Intn(len(letters))]
+		randomBytes[i] = letters[helperRand().Intn(len(letters))]
 	}
 	randomString := string(randomBytes)
 	return randomString
@@ -10261,7 +10435,7 @@ type KvdbNode struct {
 func GetAllKvdbNodes() ([]KvdbNode, error) {
 	type kvdbNodes []map[string]KvdbNode
 	storageNodes := node.GetStorageNodes()
-	randomIndex := rand.Intn(len(storageNodes))
+	randomIndex := helperRand().Intn(len(storageNodes))
 	randomNode := storageNodes[randomIndex]
 
 	jsonConvert := func(jsonString string) ([]KvdbNode, error) {
@@ -10772,8 +10946,7 @@ func GetPoolCapacityUsed(poolUUID string) (float64, error) {
 
 // GetRandomNode Gets Random node
 func GetRandomNode(pxNodes []node.Node) node.Node {
-	rand.Seed(time.Now().UnixNano())
-	randomIndex := rand.Intn(len(pxNodes))
+	randomIndex := helperRand().Intn(len(pxNodes))
 	randomNode := pxNodes[randomIndex]
 	return randomNode
 }
@@ -11725,7 +11898,7 @@ func GetClusterProvisionStatus() ([]ProvisionStatus, error) {
 	}
 
 	// Select Random Volumes for pool Expand
-	randomIndex := rand.Intn(len(selectedNode))
+	randomIndex := helperRand().Intn(len(selectedNode))
 	randomNode := selectedNode[randomIndex]
 
 	clusterProvision, err := GetClusterProvisionStatusOnSpecificNode(randomNode)
@@ -12091,7 +12264,7 @@ func GetRandomSubset(elements []string, subsetSize int) ([]string, error) {
 
 	shuffledElements := make([]string, len(elements))
 	copy(shuffledElements, elements)
-	rand.Shuffle(len(shuffledElements), func(i, j int) {
+	helperRand().Shuffle(len(shuffledElements), func(i, j int) {
 		shuffledElements[i], shuffledElements[j] = shuffledElements[j], shuffledElements[i]
 	})
 
@@ -13005,13 +13178,9 @@ func GetNodeIdToMoveReplica(volName string) (string, error) {
 
 // ShuffleSlice shuffles the elements of a slice in place.
 func ShuffleSlice[T any](slice []T) {
-	source := rand.NewSource(time.Now().UnixNano())
-	rng := rand.New(source) // Initialize the random number generator
-
-	for i := range slice {
-		j := rng.Intn(i + 1)                    // Generate a random index from 0 to i.
-		slice[i], slice[j] = slice[j], slice[i] // Swap the elements at indices i and j.
-	}
+	helperRand().Shuffle(len(slice), func(i, j int) {
+		slice[i], slice[j] = slice[j], slice[i]
+	})
 }
 
 func PrereqForNodeDecomm(nodeToDecommission node.Node, suspendedScheds []*storkapi.VolumeSnapshotSchedule) error {
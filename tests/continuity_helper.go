@@ -0,0 +1,151 @@
+package tests
+
+import (
+	context1 "context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/portworx/sched-ops/task"
+	appType "github.com/portworx/torpedo/drivers/applications/apptypes"
+	"github.com/portworx/torpedo/drivers/applications/continuity"
+	appDriver "github.com/portworx/torpedo/drivers/applications/driver"
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/pkg/log"
+	"github.com/portworx/torpedo/pkg/stats"
+	"golang.org/x/sync/errgroup"
+)
+
+// continuityApp is an app the continuity workload writes to
+type continuityApp struct {
+	writer  *continuity.Writer
+	command chan string
+	// done is closed when the writer returns
+	done   chan struct{}
+	target appDriver.ApplicationDriver
+}
+
+// ContinuityWorkload writes sequence numbered records to the apps with data support during migration,
+// failover and failback, to measure the RPO and RTO of the DR actions. It is run by the failover and
+// failback tests, the AsyncDR and MetroDR longevity triggers only migrating the apps without starting them
+// on the destination.
+type ContinuityWorkload struct {
+	apps     map[string]*continuityApp
+	errGroup *errgroup.Group
+}
+
+// StartContinuityWorkload starts writing records to the apps with data support in the app contexts,
+// in the cluster of the current kubeconfig
+func StartContinuityWorkload(ctx context1.Context, appContexts []*scheduler.Context) (*ContinuityWorkload, error) {
+	appHandlers, err := GetAppDataHandlers(ctx, appContexts)
+	if err != nil {
+		return nil, err
+	}
+	workload := &ContinuityWorkload{
+		apps:     make(map[string]*continuityApp),
+		errGroup: &errgroup.Group{},
+	}
+	for key, appHandler := range appHandlers {
+		app := &continuityApp{
+			writer:  continuity.NewWriter(strings.ReplaceAll(key, "/", "_"), appHandler, continuity.DefaultInterval),
+			command: make(chan string),
+			done:    make(chan struct{}),
+			target:  appHandler,
+		}
+		workload.apps[key] = app
+		workload.errGroup.Go(func() error {
+			defer close(app.done)
+			return app.writer.Run(app.command, ctx)
+		})
+		log.InfoD("Continuity writer started for [%s]", key)
+	}
+	return workload, nil
+}
+
+// MarkFailover returns the time of the failover or failback of the apps, to be called before it starts.
+// The writers keep writing to the apps, which stop acknowledging the writes once they are no longer
+// available, until Resume switches them.
+func (c *ContinuityWorkload) MarkFailover() time.Time {
+	failoverAt := time.Now()
+	log.InfoD("Continuity writers keep writing to the apps during the failover at [%s]", failoverAt.Format(time.RFC3339))
+	return failoverAt
+}
+
+// Resume waits for each app of the app contexts in the cluster of the current kubeconfig to be ready,
+// that is to serve the records migrated to it, and switches its writer to it
+func (c *ContinuityWorkload) Resume(ctx context1.Context, appContexts []*scheduler.Context, timeout time.Duration) error {
+	switched := make(map[string]bool)
+	t := func() (interface{}, bool, error) {
+		appHandlers, err := GetAppDataHandlers(ctx, appContexts)
+		if err != nil {
+			return nil, true, err
+		}
+		for key, app := range c.apps {
+			if switched[key] {
+				continue
+			}
+			appHandler, ok := appHandlers[key]
+			if !ok {
+				return nil, true, fmt.Errorf("no app found for continuity writer [%s]", key)
+			}
+			if _, err := appHandler.ReadRows(ctx, app.writer.ObjectName()); err != nil {
+				return nil, true, fmt.Errorf("[%s] app in namespace [%s] is not ready: %v", appHandler.GetApplicationType(), appHandler.GetNamespace(), err)
+			}
+			app.target = appHandler
+			switchedAt := app.writer.Switch(appHandler)
+			switched[key] = true
+			log.InfoD("Continuity writer [%s] switched at [%s]", key, switchedAt.Format(time.RFC3339))
+		}
+		return nil, false, nil
+	}
+	_, err := task.DoRetryWithTimeout(t, timeout, continuity.DefaultInterval)
+	return err
+}
+
+// Measure waits for each app to acknowledge a write after its writer was switched to it, then reads the records present
+// on the app and returns the RPO and RTO measurements, keyed by "<namespace>/<appType>"
+func (c *ContinuityWorkload) Measure(ctx context1.Context, failoverAt time.Time, timeout time.Duration) (map[string]*continuity.Measurement, error) {
+	waitCtx, cancel := context1.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	measurements := make(map[string]*continuity.Measurement)
+	for key, app := range c.apps {
+		if _, err := app.writer.WaitForAcknowledgement(waitCtx, app.writer.SwitchedAt()); err != nil {
+			return nil, err
+		}
+		measurement, err := app.writer.Measure(ctx, app.target, failoverAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to measure [%s] app in namespace [%s]: %v", app.target.GetApplicationType(), app.target.GetNamespace(), err)
+		}
+		log.InfoD("[%s] RPO [%v] with %d lost records, RTO [%v]", key, measurement.RPO, measurement.LostRecords, measurement.RTO)
+		measurements[key] = measurement
+	}
+	return measurements, nil
+}
+
+// Stop stops the writers and waits for them to return, the writers which already returned, for e.g. as
+// their context is done, are not waited for
+func (c *ContinuityWorkload) Stop() error {
+	for _, app := range c.apps {
+		select {
+		case app.command <- appType.DataStop:
+		case <-app.done:
+		}
+	}
+	return c.errGroup.Wait()
+}
+
+// PublishContinuityStats publishes the measurements of the DR action, a failover or a failback,
+// as the stats of the asyncdr or metrodr event
+func PublishContinuityStats(name, action string, measurements map[string]*continuity.Measurement) {
+	eventStatName := stats.AsyncDREventName
+	if name == MetroDR {
+		eventStatName = stats.MetroDREventName
+	}
+	for key, measurement := range measurements {
+		dashStats := measurement.Stats()
+		dashStats["Action"] = action
+		dashStats["App"] = key
+		updateLongevityStats(name, eventStatName, dashStats)
+	}
+}
@@ -0,0 +1,248 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	opsapi "github.com/libopenstorage/openstorage/api"
+	"github.com/portworx/torpedo/drivers/node"
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/pkg/invariant"
+	"github.com/portworx/torpedo/pkg/log"
+)
+
+const (
+	// PXNodesOnlineInvariant checks all storage driver nodes are online, except those under disruption
+	PXNodesOnlineInvariant = "pxNodesOnline"
+	// VolumeHALevelInvariant checks no app volume stays below its HA level
+	VolumeHALevelInvariant = "volumeHALevel"
+	// NoCrashLoopBackOffInvariant checks no app pod is in CrashLoopBackOff
+	NoCrashLoopBackOffInvariant = "noCrashLoopBackOff"
+	// KvdbQuorumInvariant checks kvdb has quorum
+	KvdbQuorumInvariant = "kvdbQuorum"
+)
+
+const (
+	defaultInvariantInterval      = 2 * time.Minute
+	defaultVolumeHALevelTolerance = 15 * time.Minute
+	crashLoopBackOffReason        = "CrashLoopBackOff"
+)
+
+// Invariants tracks the disruption windows of the triggers and runs the
+// invariant checkers registered through RegisterInvariants
+var Invariants = invariant.NewMonitor()
+
+// VolumeHALevelTolerance is how long a volume can stay below its HA level, for e.g.
+// while it resyncs, before it's a violation of the VolumeHALevelInvariant
+var VolumeHALevelTolerance = defaultVolumeHALevelTolerance
+
+// nodeDisruptiveTriggers take storage driver nodes offline. A node is considered under
+// disruption while one of them is active and has not registered its target nodes.
+var nodeDisruptiveTriggers = map[string]bool{
+	RestartVolDriver:         true,
+	RestartManyVolDriver:     true,
+	RestartKvdbVolDriver:     true,
+	CrashVolDriver:           true,
+	CrashPXDaemon:            true,
+	RebootNode:               true,
+	RebootManyNodes:          true,
+	CrashNode:                true,
+	BackupRestartPortworx:    true,
+	BackupRestartNode:        true,
+	AutoFsTrim:               true,
+	DetachDrives:             true,
+	NodeDecommission:         true,
+	NodeRejoin:               true,
+	KVDBFailover:             true,
+	AsyncDRPXRestartSource:   true,
+	AsyncDRPXRestartDest:     true,
+	AsyncDRPXRestartKvdb:     true,
+	StorkAppBkpPxRestart:     true,
+	HAIncreaseAndReboot:      true,
+	HAIncreaseAndRestartPX:   true,
+	HAIncreaseAndCrashPX:     true,
+	AddDrive:                 true,
+	AddDiskAndReboot:         true,
+	ResizeDiskAndReboot:      true,
+	VolumeCreatePxRestart:    true,
+	AddResizePoolMaintenance: true,
+	OCPStorageNodeRecycle:    true,
+	NodeMaintenanceCycle:     true,
+	PoolMaintenanceCycle:     true,
+	VolumeDriverDownVCluster: true,
+	RestartKubeletService:    true,
+	SVMotionSingleNode:       true,
+	SVMotionMultipleNodes:    true,
+}
+
+// clusterDisruptiveTriggers take any storage driver node offline while they are active
+var clusterDisruptiveTriggers = map[string]bool{
+	PowerOffAllVMs:                 true,
+	UpgradeVolumeDriver:            true,
+	UpgradeVolumeDriverFromCatalog: true,
+	UpgradeCluster:                 true,
+}
+
+// InvariantCheckers returns the built-in invariant checkers for the apps of the given contexts
+func InvariantCheckers(contexts *[]*scheduler.Context) map[string]invariant.Checker {
+	checkers := []invariant.Checker{
+		{
+			Name:  PXNodesOnlineInvariant,
+			Check: checkPXNodesOnline,
+		},
+		{
+			Name:      VolumeHALevelInvariant,
+			Tolerance: VolumeHALevelTolerance,
+			Check: func([]invariant.Window) error {
+				return checkVolumeHALevel(*contexts)
+			},
+		},
+		{
+			Name: NoCrashLoopBackOffInvariant,
+			Check: func(active []invariant.Window) error {
+				return checkNoCrashLoopBackOff(*contexts, active)
+			},
+		},
+		{
+			Name:  KvdbQuorumInvariant,
+			Check: checkKvdbQuorum,
+		},
+	}
+
+	byName := make(map[string]invariant.Checker)
+	for _, c := range checkers {
+		c.Interval = defaultInvariantInterval
+		byName[c.Name] = c
+	}
+	return byName
+}
+
+// RegisterInvariants registers the built-in invariant checkers with the given names
+func RegisterInvariants(contexts *[]*scheduler.Context, names []string) error {
+	checkers := InvariantCheckers(contexts)
+	for _, name := range names {
+		c, ok := checkers[name]
+		if !ok {
+			return fmt.Errorf("unknown invariant [%s]", name)
+		}
+		if err := Invariants.Register(c); err != nil {
+			return err
+		}
+		log.Infof("Registered invariant [%s]", name)
+	}
+	return nil
+}
+
+// nodeUnderDisruption returns true if the node is targeted by an active disruption window,
+// or may be because the trigger of the window does not tell which nodes it disrupts
+func nodeUnderDisruption(active []invariant.Window, nodeName string) bool {
+	for _, w := range active {
+		if w.HasNode(nodeName) || clusterDisruptiveTriggers[w.Trigger] {
+			return true
+		}
+		if len(w.Nodes) == 0 && nodeDisruptiveTriggers[w.Trigger] {
+			return true
+		}
+	}
+	return false
+}
+
+func checkPXNodesOnline(active []invariant.Window) error {
+	var offline []string
+	for _, n := range node.GetStorageDriverNodes() {
+		if nodeUnderDisruption(active, n.Name) {
+			continue
+		}
+		status, err := Inst().V.GetNodeStatus(n)
+		if err != nil {
+			offline = append(offline, fmt.Sprintf("%s: %v", n.Name, err))
+			continue
+		}
+		if *status != opsapi.Status_STATUS_OK {
+			offline = append(offline, fmt.Sprintf("%s: %v", n.Name, *status))
+		}
+	}
+	if len(offline) > 0 {
+		return fmt.Errorf("storage driver nodes not online: [%s]", strings.Join(offline, ", "))
+	}
+	return nil
+}
+
+func checkVolumeHALevel(contexts []*scheduler.Context) error {
+	var degraded []string
+	for _, ctx := range contexts {
+		vols, err := Inst().S.GetVolumes(ctx)
+		if err != nil {
+			return err
+		}
+		for _, vol := range vols {
+			apiVol, err := Inst().V.InspectVolume(vol.ID)
+			if err != nil {
+				return err
+			}
+			if len(apiVol.ReplicaSets) == 0 {
+				continue
+			}
+			replicas := len(apiVol.ReplicaSets[0].Nodes)
+			if int64(replicas) < apiVol.Spec.HaLevel {
+				degraded = append(degraded, fmt.Sprintf("%s: %d/%d", vol.Name, replicas, apiVol.Spec.HaLevel))
+			}
+		}
+	}
+	if len(degraded) > 0 {
+		return fmt.Errorf("volumes below their HA level: [%s]", strings.Join(degraded, ", "))
+	}
+	return nil
+}
+
+// checkNoCrashLoopBackOff checks the pods of the apps are not crash looping. Pods on nodes
+// under disruption are skipped, they are expected to crash loop until their node is back.
+func checkNoCrashLoopBackOff(contexts []*scheduler.Context, active []invariant.Window) error {
+	var crashing []string
+	namespaces := make(map[string]bool)
+	for _, ctx := range contexts {
+		namespace := ctx.App.NameSpace
+		if namespace == "" || namespaces[namespace] {
+			continue
+		}
+		namespaces[namespace] = true
+
+		pods, err := k8sCore.GetPods(namespace, nil)
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods.Items {
+			if pod.Spec.NodeName != "" && nodeUnderDisruption(active, pod.Spec.NodeName) {
+				continue
+			}
+			for _, status := range pod.Status.ContainerStatuses {
+				if status.State.Waiting != nil && status.State.Waiting.Reason == crashLoopBackOffReason {
+					crashing = append(crashing, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+					break
+				}
+			}
+		}
+	}
+	if len(crashing) > 0 {
+		return fmt.Errorf("pods in %s: [%s]", crashLoopBackOffReason, strings.Join(crashing, ", "))
+	}
+	return nil
+}
+
+func checkKvdbQuorum([]invariant.Window) error {
+	kvdbNodes, err := GetAllKvdbNodes()
+	if err != nil {
+		return err
+	}
+	healthy := 0
+	for _, n := range kvdbNodes {
+		if n.IsHealthy {
+			healthy++
+		}
+	}
+	if healthy <= len(kvdbNodes)/2 {
+		return fmt.Errorf("kvdb has no quorum, [%d] of [%d] members healthy", healthy, len(kvdbNodes))
+	}
+	return nil
+}
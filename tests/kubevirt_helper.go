@@ -11,6 +11,9 @@ import (
 	"github.com/portworx/sched-ops/k8s/core"
 	"github.com/portworx/sched-ops/k8s/kubevirt"
 	"github.com/portworx/sched-ops/task"
+	appType "github.com/portworx/torpedo/drivers/applications/apptypes"
+	appDriver "github.com/portworx/torpedo/drivers/applications/driver"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	"github.com/portworx/torpedo/drivers/node"
 	"github.com/portworx/torpedo/drivers/scheduler"
 	"github.com/portworx/torpedo/drivers/volume"
@@ -605,44 +608,22 @@ func CreateConfigMap() error {
 	return nil
 }
 
-// WriteFilesAndStoreMD5InVM write few files in the VM and calculates the md5sum
-func WriteFilesAndStoreMD5InVM(virtualMachines []*scheduler.Context, namespace string, fileCount int, maxFileSize int) error {
-	log.Infof("Creating %d files and storing their MD5 checksums in namespace %s", fileCount, namespace)
-	createFilesCmd := fmt.Sprintf("mkdir -p ~/testfiles && cd ~/testfiles && "+
-		"rm -f ~/file_checksums.md5 && for i in $(seq 1 %d); do "+
-		"head -c $(($RANDOM %% %d)) </dev/urandom >file$i; md5sum file$i >> ~/file_checksums.md5; done", fileCount, maxFileSize)
-	for _, appCtx := range virtualMachines {
-		vms, err := GetAllVMsFromScheduledContexts([]*scheduler.Context{appCtx})
-		if err != nil {
-			return err
-		}
-		for _, v := range vms {
-			_, err := RunCmdInVM(v, createFilesCmd, context1.TODO())
-			if err != nil {
-				return err
-			}
-		}
+// WriteIntegrityWorkloadInVMs writes the integrity workload in the VMs of the contexts and returns their
+// checksums keyed by namespace, to be validated by ValidateIntegrityWorkload
+func WriteIntegrityWorkloadInVMs(virtualMachines []*scheduler.Context, workload integrity.Workload) (map[string][]*integrity.Snapshot, error) {
+	ctx := context1.TODO()
+	appHandlers, err := GetAppDataHandlers(ctx, virtualMachines)
+	if err != nil {
+		return nil, err
 	}
-	return nil
-}
-
-// ValidateFileIntegrityInVM validates the md5sum of files that we wrote in the VM
-func ValidateFileIntegrityInVM(virtualMachines []*scheduler.Context, namespace string) error {
-	log.Infof("Validating file integrity in namespace %s", namespace)
-	validateFilesCmd := "cd ~/testfiles && md5sum -c ~/file_checksums.md5"
-	for _, appCtx := range virtualMachines {
-		vms, err := GetAllVMsFromScheduledContexts([]*scheduler.Context{appCtx})
-		if err != nil {
-			return err
-		}
-		for _, v := range vms {
-			_, err := RunCmdInVM(v, validateFilesCmd, context1.TODO())
-			if err != nil {
-				return err
-			}
+	var vmHandlers []appDriver.ApplicationDriver
+	for _, appHandler := range appHandlers {
+		if appHandler.GetApplicationType() == appType.Kubevirt {
+			vmHandlers = append(vmHandlers, appHandler)
 		}
 	}
-	return nil
+	log.Infof("Writing integrity workload [%s] in %d VMs", workload.Name, len(vmHandlers))
+	return writeIntegrityWorkload(ctx, vmHandlers, workload)
 }
 
 // ListEvents lists all events in a namespace in logs.
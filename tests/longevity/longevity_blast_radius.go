@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/portworx/torpedo/drivers/node"
+	"github.com/portworx/torpedo/pkg/blastradius"
+	"github.com/portworx/torpedo/pkg/log"
+	. "github.com/portworx/torpedo/tests"
+)
+
+// blastRadiusConfigMapField is the field of the longevity config map holding the
+// blast radius budget of the triggers. Triggers are not limited when it's not set.
+//
+// The longevity loop and the chaos plans run one trigger at a time under
+// triggerLoc, so leases never overlap and the budget only limits a trigger on
+// its own: a trigger claiming more than the limits, for e.g. rebootManyNodes with
+// a chaos level over a quorum of storage nodes, is skipped, and a trigger binding
+// more targets than the limits while running fails.
+const blastRadiusConfigMapField = "blastRadius"
+
+var (
+	// blastRadius is the budget manager of the triggers, nil when no budget is set
+	blastRadius *blastradius.Manager
+
+	blastRadiusLeasesLock sync.Mutex
+	// blastRadiusLeases are the leases of the running triggers by trigger type
+	blastRadiusLeases = make(map[string]*blastradius.Lease)
+	// blastRadiusBindErrs are the targets of the running triggers rejected by the budget
+	blastRadiusBindErrs = make(map[string][]error)
+)
+
+// upgradeLock is the lock of triggers which must not overlap with an upgrade
+const upgradeLock = "upgrade"
+
+// storageNodes claims n storage driver nodes
+func storageNodes(n int) map[blastradius.Kind]int {
+	return map[blastradius.Kind]int{blastradius.StorageNodes: n}
+}
+
+// triggerFootprints gives what each disruptive trigger disrupts at once. Triggers
+// pick their targets while running, so footprints claim counts which are bound to
+// the concrete nodes, kvdb members and volumes as the triggers register them, see
+// bindBlastRadiusTarget. Triggers not listed only disrupt apps and are never limited.
+var triggerFootprints = map[string]func() blastradius.Claim{
+	RebootNode:            singleStorageNodeFootprint,
+	CrashNode:             singleStorageNodeFootprint,
+	CrashPXDaemon:         singleStorageNodeFootprint,
+	RestartVolDriver:      singleStorageNodeFootprint,
+	CrashVolDriver:        singleStorageNodeFootprint,
+	NodeDecommission:      singleStorageNodeFootprint,
+	DetachDrives:          singleStorageNodeFootprint,
+	HAIncreaseAndReboot:   singleStorageNodeFootprint,
+	AddDiskAndReboot:      singleStorageNodeFootprint,
+	ResizeDiskAndReboot:   singleStorageNodeFootprint,
+	VolumeCreatePxRestart: singleStorageNodeFootprint,
+	OCPStorageNodeRecycle: singleStorageNodeFootprint,
+	RestartKubeletService: singleStorageNodeFootprint,
+	RebootManyNodes: func() blastradius.Claim {
+		return blastradius.Claim{Units: storageNodes(ChaosLevelNodeCount(RebootManyNodes))}
+	},
+	RestartManyVolDriver: func() blastradius.Claim {
+		return blastradius.Claim{Units: storageNodes(ChaosLevelNodeCount(RestartManyVolDriver))}
+	},
+	KVDBFailover:         kvdbMemberFootprint,
+	RestartKvdbVolDriver: kvdbMemberFootprint,
+	PoolDelete: func() blastradius.Claim {
+		return blastradius.Claim{Units: map[blastradius.Kind]int{blastradius.StorageNodes: 1, blastradius.Pools: 1}}
+	},
+	PowerOffAllVMs: func() blastradius.Claim {
+		return blastradius.Claim{Exclusive: true}
+	},
+	UpgradeVolumeDriver: func() blastradius.Claim {
+		return blastradius.Claim{Exclusive: true, Locks: []string{upgradeLock}}
+	},
+}
+
+func singleStorageNodeFootprint() blastradius.Claim {
+	return blastradius.Claim{Units: storageNodes(1)}
+}
+
+func kvdbMemberFootprint() blastradius.Claim {
+	return blastradius.Claim{Units: map[blastradius.Kind]int{blastradius.StorageNodes: 1, blastradius.KvdbMembers: 1}}
+}
+
+func setBlastRadius(configData *map[string]string) {
+	data, ok := (*configData)[blastRadiusConfigMapField]
+	if !ok {
+		return
+	}
+	delete(*configData, blastRadiusConfigMapField)
+
+	config, err := blastradius.ParseConfig([]byte(data))
+	if err != nil {
+		log.Errorf("Ignoring blast radius budget from config-map [%s] in namespace [%s]. Err: %v",
+			testTriggersConfigMap, configMapNS, err)
+		return
+	}
+	totals := map[blastradius.Kind]func() int{
+		blastradius.Nodes: func() int {
+			return len(node.GetNodes())
+		},
+		blastradius.StorageNodes: func() int {
+			return len(node.GetStorageDriverNodes())
+		},
+	}
+	if blastRadius == nil {
+		blastRadius = blastradius.NewManager()
+	}
+	if err = blastRadius.Configure(config, totals); err != nil {
+		log.Errorf("Ignoring blast radius budget from config-map [%s] in namespace [%s]. Err: %v",
+			testTriggersConfigMap, configMapNS, err)
+		return
+	}
+	EventTargetHook = bindBlastRadiusTarget
+	log.Infof("Blast radius budget set to %+v", config)
+}
+
+// bindBlastRadiusTarget binds a target registered by a running trigger to the
+// resources of its lease
+func bindBlastRadiusTarget(triggerType string, target blastradius.Kind, name string) {
+	blastRadiusLeasesLock.Lock()
+	lease := blastRadiusLeases[triggerType]
+	blastRadiusLeasesLock.Unlock()
+	if blastRadius == nil || lease == nil {
+		return
+	}
+	if target != blastradius.Nodes {
+		rejectBlastRadiusTarget(triggerType, blastRadius.Bind(lease, target, name))
+		return
+	}
+	n, err := node.GetNodeByName(name)
+	if err != nil {
+		log.Warnf("Failed to bind node [%s] to the blast radius of trigger [%s]. Err: %v", name, triggerType, err)
+		return
+	}
+	rejectBlastRadiusTarget(triggerType, blastRadius.Bind(lease, blastradius.Nodes, name))
+	if n.IsStorageDriverInstalled {
+		rejectBlastRadiusTarget(triggerType, blastRadius.Bind(lease, blastradius.StorageNodes, name))
+	}
+	if n.IsMetadataNode {
+		rejectBlastRadiusTarget(triggerType, blastRadius.Bind(lease, blastradius.KvdbMembers, name))
+	}
+}
+
+// rejectBlastRadiusTarget records a target of a running trigger which does not fit the
+// budget. The trigger already disrupts it, so it fails once done, see releaseBlastRadius.
+func rejectBlastRadiusTarget(triggerType string, err error) {
+	if err == nil {
+		return
+	}
+	log.Errorf("Trigger [%s] disrupts more than its blast radius. Err: %v", triggerType, err)
+	blastRadiusLeasesLock.Lock()
+	defer blastRadiusLeasesLock.Unlock()
+	blastRadiusBindErrs[triggerType] = append(blastRadiusBindErrs[triggerType], err)
+}
+
+// acquireBlastRadius claims the footprint of the trigger from the budget. It
+// returns false if the trigger must be skipped.
+func acquireBlastRadius(triggerType string) (*blastradius.Lease, bool) {
+	footprint, ok := triggerFootprints[triggerType]
+	if blastRadius == nil || !ok {
+		return nil, true
+	}
+	claim := footprint()
+	claim.Trigger = triggerType
+	lease, err := blastRadius.TryAcquire(claim)
+	if err != nil {
+		log.Warnf("Skipping trigger [%s]. Err: %v", triggerType, err)
+		return nil, false
+	}
+	blastRadiusLeasesLock.Lock()
+	blastRadiusLeases[triggerType] = lease
+	blastRadiusLeasesLock.Unlock()
+	return lease, true
+}
+
+// releaseBlastRadius returns the footprint of a trigger to the budget. It returns an
+// error if the trigger disrupted targets rejected by the budget while running.
+func releaseBlastRadius(lease *blastradius.Lease) error {
+	if lease == nil {
+		return nil
+	}
+	blastRadiusLeasesLock.Lock()
+	if blastRadiusLeases[lease.Claim.Trigger] == lease {
+		delete(blastRadiusLeases, lease.Claim.Trigger)
+	}
+	bindErrs := blastRadiusBindErrs[lease.Claim.Trigger]
+	delete(blastRadiusBindErrs, lease.Claim.Trigger)
+	blastRadiusLeasesLock.Unlock()
+	if blastRadius != nil {
+		blastRadius.Release(lease)
+	}
+	if len(bindErrs) > 0 {
+		return fmt.Errorf("trigger [%s] exceeded its blast radius: %v", lease.Claim.Trigger, bindErrs)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portworx/torpedo/drivers/node"
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/pkg/chaosplan"
+	"github.com/portworx/torpedo/pkg/log"
+	. "github.com/portworx/torpedo/tests"
+)
+
+const (
+	// chaosPlanConfigMapField is the field of the longevity config map holding a
+	// chaos plan. When set, the plan drives the triggers instead of chaos levels.
+	chaosPlanConfigMapField = "chaosPlan"
+
+	chaosPlanConditionTimeout       = 5 * time.Minute
+	chaosPlanConditionRetryInterval = 10 * time.Second
+)
+
+const (
+	// AppsRunningCondition checks all apps of the test are running
+	AppsRunningCondition = "appsRunning"
+	// NodesReadyCondition checks all nodes are ready in the scheduler
+	NodesReadyCondition = "nodesReady"
+	// VolumeDriverUpCondition checks the volume driver is up on all storage driver nodes
+	VolumeDriverUpCondition = "volumeDriverUp"
+	// StoragePoolsHealthyCondition checks all storage pools are healthy
+	StoragePoolsHealthyCondition = "storagePoolsHealthy"
+)
+
+// chaosPlan is the plan read from the config map, if any
+var chaosPlan *chaosplan.Plan
+
+func setChaosPlan(configData *map[string]string) {
+	data, ok := (*configData)[chaosPlanConfigMapField]
+	if !ok {
+		chaosPlan = nil
+		return
+	}
+	delete(*configData, chaosPlanConfigMapField)
+
+	plan, err := chaosplan.Parse([]byte(data))
+	if err != nil {
+		log.Errorf("Ignoring chaos plan from config-map [%s] in namespace [%s]. Err: %v",
+			testTriggersConfigMap, configMapNS, err)
+		return
+	}
+	log.Infof("Using chaos plan [%s] from config-map [%s] in namespace [%s]", plan.Name, testTriggersConfigMap, configMapNS)
+	chaosPlan = plan
+}
+
+// newChaosPlanExecutor returns an executor driving the longevity trigger functions.
+//
+// Each trigger function runs under triggerLoc like the triggers of the config
+// map driven loop, as trigger functions share the longevity logger, the
+// dashboard test case, the trigger span and the trigger random stream, and
+// running two of them at once would cross-attribute their logs, results and
+// random draws. The executor is therefore sequential and rejects plans with
+// parallel steps or more than one disruptive trigger at a time.
+func newChaosPlanExecutor(contexts *[]*scheduler.Context, triggerLoc *sync.Mutex, triggerEventsChan *chan *EventRecord) *chaosplan.Executor {
+	triggers := make(map[string]chaosplan.TriggerFunc)
+	for triggerType, triggerFunc := range triggerFunctions {
+		triggers[triggerType] = chaosPlanTrigger(contexts, triggerType, triggerFunc, triggerLoc, triggerEventsChan)
+	}
+
+	conditions := map[string]chaosplan.ConditionFunc{
+		AppsRunningCondition: func() error {
+			for _, ctx := range *contexts {
+				if err := Inst().S.WaitForRunning(ctx, chaosPlanConditionTimeout, chaosPlanConditionRetryInterval); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		NodesReadyCondition: func() error {
+			for _, n := range node.GetNodes() {
+				if err := Inst().S.IsNodeReady(n); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		VolumeDriverUpCondition: func() error {
+			for _, n := range node.GetStorageDriverNodes() {
+				if err := Inst().V.WaitDriverUpOnNode(n, chaosPlanConditionTimeout); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		StoragePoolsHealthyCondition: func() error {
+			return Inst().V.ValidateStoragePools()
+		},
+	}
+
+	e := chaosplan.NewExecutor(triggers, conditions, isDisruptiveTrigger)
+	e.SetSequential()
+	e.SetStopChan(StopLongevityChan)
+	return e
+}
+
+// chaosPlanTrigger adapts a trigger function to the chaos plan executor. The
+// event records of the trigger are forwarded to triggerEventsChan and the
+// trigger run fails if any of them has errors in its outcome, or if it disrupted
+// more than its blast radius. Runs skipped by the blast radius budget do not
+// fail. The trigger function holds triggerLoc while it runs.
+func chaosPlanTrigger(contexts *[]*scheduler.Context, triggerType string, triggerFunc TriggerFunction, triggerLoc *sync.Mutex, triggerEventsChan *chan *EventRecord) chaosplan.TriggerFunc {
+	return func() error {
+		log.Infof("Waiting for lock for chaos plan trigger [%s]", triggerType)
+		triggerLoc.Lock()
+		defer triggerLoc.Unlock()
+
+		lease, ok := acquireBlastRadius(triggerType)
+		if !ok {
+			return nil
+		}
+
+		recordChan := make(chan *EventRecord)
+		var errs []string
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for record := range recordChan {
+				for _, err := range record.Outcome {
+					errs = append(errs, strings.TrimSuffix(err.Error(), "<br>"))
+				}
+				*triggerEventsChan <- record
+			}
+		}()
+
+		triggerFunc(contexts, &recordChan)
+		close(recordChan)
+		wg.Wait()
+
+		if err := releaseBlastRadius(lease); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%s", strings.Join(errs, "; "))
+		}
+		return nil
+	}
+}
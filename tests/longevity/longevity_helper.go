@@ -243,6 +243,9 @@ func populateDataFromConfigMap(configData *map[string]string) error {
 	setUpgradeStorageDriverEndpointList(configData)
 	setVclusterFioRunOptions(configData)
 	setSchedUpgradeHops(configData)
+	setChaosPlan(configData)
+	setInvariants(configData)
+	setBlastRadius(configData)
 
 	err := populateTriggers(configData)
 	if err != nil {
@@ -306,7 +309,7 @@ func setMigrationInterval(configData *map[string]string) {
 			log.Errorf("Cannot set Migration interval value, getting error: %v", err)
 		}
 	} else {
-		MigrationInterval = 3
+		MigrationInterval = DefaultMigrationInterval
 	}
 }
 
@@ -318,7 +321,7 @@ func setMigrationsCount(configData *map[string]string) {
 			log.Errorf("Cannot set Migration Count value, getting error: %v", err)
 		}
 	} else {
-		MigrationsCount = 5
+		MigrationsCount = DefaultMigrationsCount
 	}
 }
 
@@ -625,6 +628,7 @@ func populateIntervals() {
 	triggerInterval[DefragSchedules] = make(map[int]time.Duration)
 	triggerInterval[SVMotionSingleNode] = make(map[int]time.Duration)
 	triggerInterval[SVMotionMultipleNodes] = make(map[int]time.Duration)
+	triggerInterval[HelmUpgradeRollback] = make(map[int]time.Duration)
 
 	baseInterval := 10 * time.Minute
 	triggerInterval[BackupScaleMongo][10] = 1 * baseInterval
@@ -1571,6 +1575,13 @@ func populateIntervals() {
 	triggerInterval[SVMotionMultipleNodes][2] = 9 * baseInterval
 	triggerInterval[SVMotionMultipleNodes][1] = 10 * baseInterval
 
+	triggerInterval[HelmUpgradeRollback][10] = 1 * baseInterval
+	triggerInterval[HelmUpgradeRollback][9] = 2 * baseInterval
+	triggerInterval[HelmUpgradeRollback][8] = 3 * baseInterval
+	triggerInterval[HelmUpgradeRollback][7] = 4 * baseInterval
+	triggerInterval[HelmUpgradeRollback][6] = 5 * baseInterval
+	triggerInterval[HelmUpgradeRollback][5] = 6 * baseInterval
+
 	triggerInterval[UpgradeStork][10] = 1 * baseInterval
 	triggerInterval[UpgradeStork][9] = 2 * baseInterval
 	triggerInterval[UpgradeStork][8] = 3 * baseInterval
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/pkg/log"
+	. "github.com/portworx/torpedo/tests"
+)
+
+// invariantsConfigMapField is the field of the longevity config map listing, comma
+// separated, the invariants checked during the run, for e.g. "pxNodesOnline,kvdbQuorum"
+const invariantsConfigMapField = "invariants"
+
+// volumeHALevelToleranceConfigMapField is the field of the longevity config map setting
+// how long a volume can stay below its HA level, as a duration, for e.g. "30m"
+const volumeHALevelToleranceConfigMapField = "volumeHALevelTolerance"
+
+// invariantNames are the invariants read from the config map
+var invariantNames []string
+
+func setInvariants(configData *map[string]string) {
+	if invariants, ok := (*configData)[invariantsConfigMapField]; ok {
+		invariantNames = nil
+		for _, name := range strings.Split(invariants, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				invariantNames = append(invariantNames, name)
+			}
+		}
+		log.Infof("Invariants set to %v", invariantNames)
+	}
+	delete(*configData, invariantsConfigMapField)
+
+	if tolerance, ok := (*configData)[volumeHALevelToleranceConfigMapField]; ok {
+		d, err := time.ParseDuration(strings.TrimSpace(tolerance))
+		if err != nil {
+			log.Errorf("Failed to parse [%s] of the config map: %v", volumeHALevelToleranceConfigMapField, err)
+		} else {
+			VolumeHALevelTolerance = d
+			log.Infof("Volume HA level tolerance set to %v", VolumeHALevelTolerance)
+		}
+	}
+	delete(*configData, volumeHALevelToleranceConfigMapField)
+}
+
+// startInvariants starts checking the invariants of the config map, concurrently with the triggers
+func startInvariants(contexts *[]*scheduler.Context) {
+	if len(invariantNames) == 0 {
+		return
+	}
+	err := RegisterInvariants(contexts, invariantNames)
+	log.FailOnError(err, "failed to register invariants %v", invariantNames)
+	Invariants.Start()
+}
+
+// stopInvariants stops the invariant checkers and reports their violations
+func stopInvariants() {
+	Invariants.Stop()
+	violations := Invariants.Violations()
+	for _, v := range violations {
+		log.Errorf("%v", v)
+	}
+	dash.VerifySafely(len(violations), 0, fmt.Sprintf("verify no invariant of %v was violated", invariantNames))
+}
@@ -0,0 +1,35 @@
+package tests
+
+import (
+	"github.com/portworx/torpedo/drivers/scheduler"
+	"github.com/portworx/torpedo/pkg/eventjournal"
+	"github.com/portworx/torpedo/pkg/log"
+	. "github.com/portworx/torpedo/tests"
+)
+
+// replayEvents re-executes the journaled trigger executions one at a time, in the
+// order they started in the recorded run. TriggerRand replays the recorded random
+// decisions, so each execution targets the same nodes, volumes and pools.
+func replayEvents(contexts *[]*scheduler.Context, triggerEventsChan *chan *EventRecord, events []eventjournal.Entry) {
+	for _, e := range events {
+		select {
+		case <-StopLongevityChan:
+			log.InfoD("Stopping replay, longevity stop requested")
+			return
+		default:
+		}
+
+		// executions already done outside of the replay, for e.g. the initial
+		// app deployment, are not run again
+		if TriggerRand.Executions(e.Trigger) > e.Execution {
+			continue
+		}
+		triggerFunc, ok := triggerFunctions[e.Trigger]
+		if !ok {
+			log.Warnf("Skipping event [%s] of unknown trigger [%s]", e.EventID, e.Trigger)
+			continue
+		}
+		log.InfoD("Replaying trigger [%s] execution [%d] of event [%s]", e.Trigger, e.Execution, e.EventID)
+		triggerFunc(contexts, triggerEventsChan)
+	}
+}
@@ -38,6 +38,7 @@ var _ = BeforeSuite(func() {
 })
 
 var _ = AfterSuite(func() {
+	defer ShutdownTracing()
 	_, exists := os.LookupEnv("NOMAD_ADDR")
 	if !exists {
 		TestLogger = CreateLogger("SystemCheck.log")
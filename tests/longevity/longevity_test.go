@@ -29,95 +29,7 @@ var _ = Describe("{Longevity}", func() {
 	var emailTriggerLock sync.Mutex
 	var populateDone bool
 	triggerEventsChan := make(chan *EventRecord, 100)
-	triggerFunctions = map[string]func(*[]*scheduler.Context, *chan *EventRecord){
-		DeployApps:                        TriggerDeployNewApps,
-		RebootNode:                        TriggerRebootNodes,
-		ValidatePdsApps:                   TriggerValidatePdsApps,
-		CrashNode:                         TriggerCrashNodes,
-		CrashPXDaemon:                     TriggerCrashPXDaemon,
-		RestartVolDriver:                  TriggerRestartVolDriver,
-		CrashVolDriver:                    TriggerCrashVolDriver,
-		HAIncrease:                        TriggerHAIncrease,
-		HADecrease:                        TriggerHADecrease,
-		VolumeClone:                       TriggerVolumeClone,
-		VolumeResize:                      TriggerVolumeResize,
-		AppTaskDown:                       TriggerAppTaskDown,
-		AppTasksDown:                      TriggerAppTasksDown,
-		AddDrive:                          TriggerAddDrive,
-		CoreChecker:                       TriggerCoreChecker,
-		CloudSnapShot:                     TriggerCloudSnapShot,
-		LocalSnapShot:                     TriggerLocalSnapShot,
-		DeleteLocalSnapShot:               TriggerDeleteLocalSnapShot,
-		MetadataPoolResizeDisk:            TriggerMetadataPoolResizeDisk,
-		PoolAddDisk:                       TriggerPoolAddDisk,
-		UpgradeStork:                      TriggerUpgradeStork,
-		VolumesDelete:                     TriggerVolumeDelete,
-		UpgradeVolumeDriver:               TriggerUpgradeVolumeDriver,
-		AutoFsTrim:                        TriggerAutoFsTrim,
-		UpdateVolume:                      TriggerVolumeUpdate,
-		UpdateIOProfile:                   TriggerVolumeIOProfileUpdate,
-		RestartManyVolDriver:              TriggerRestartManyVolDriver,
-		RebootManyNodes:                   TriggerRebootManyNodes,
-		NodeDecommission:                  TriggerNodeDecommission,
-		NodeRejoin:                        TriggerNodeRejoin,
-		CsiSnapShot:                       TriggerCsiSnapShot,
-		CsiSnapRestore:                    TriggerCsiSnapRestore,
-		RelaxedReclaim:                    TriggerRelaxedReclaim,
-		Trashcan:                          TriggerTrashcan,
-		KVDBFailover:                      TriggerKVDBFailover,
-		ValidateDeviceMapper:              TriggerValidateDeviceMapperCleanup,
-		MetroDR:                           TriggerMetroDR,
-		AsyncDR:                           TriggerAsyncDR,
-		AsyncDRMigrationSchedule:          TriggerAsyncDRMigrationSchedule,
-		ConfluentAsyncDR:                  TriggerConfluentAsyncDR,
-		KafkaAsyncDR:                      TriggerKafkaAsyncDR,
-		MongoAsyncDR:                      TriggerMongoAsyncDR,
-		AsyncDRVolumeOnly:                 TriggerAsyncDRVolumeOnly,
-		AutoFsTrimAsyncDR:                 TriggerAutoFsTrimAsyncDR,
-		DetachDrives:                      TriggerDetachDrives,
-		IopsBwAsyncDR:                     TriggerIopsBwAsyncDR,
-		StorkApplicationBackup:            TriggerStorkApplicationBackup,
-		StorkAppBkpVolResize:              TriggerStorkAppBkpVolResize,
-		StorkAppBkpHaUpdate:               TriggerStorkAppBkpHaUpdate,
-		StorkAppBkpPxRestart:              TriggerStorkAppBkpPxRestart,
-		StorkAppBkpPoolResize:             TriggerStorkAppBkpPoolResize,
-		StorkVolumeSnapshotSchedule:       TriggerStorkVolumeSnapshotSchedule,
-		StorkVolumeSnapshotScheduleLocal:  TriggerStorkVolumeSnapshotScheduleLocal,
-		RestartKvdbVolDriver:              TriggerRestartKvdbVolDriver,
-		HAIncreaseAndReboot:               TriggerHAIncreaseAndReboot,
-		AddDiskAndReboot:                  TriggerPoolAddDiskAndReboot,
-		ResizeDiskAndReboot:               TriggerPoolResizeDiskAndReboot,
-		AutopilotRebalance:                TriggerAutopilotPoolRebalance,
-		DeleteOldNamespaces:               TriggerDeleteOldNamespaces,
-		DeleteCloudsnaps:                  TriggerDeleteCloudsnaps,
-		MetroDRMigrationSchedule:          TriggerMetroDRMigrationSchedule,
-		CloudSnapShotRestore:              TriggerCloudSnapshotRestore,
-		LocalSnapShotRestore:              TriggerLocalSnapshotRestore,
-		AggrVolDepReplResizeOps:           TriggerAggrVolDepReplResizeOps,
-		AddStorageNode:                    TriggerAddOCPStorageNode,
-		AddStoragelessNode:                TriggerAddOCPStoragelessNode,
-		OCPStorageNodeRecycle:             TriggerOCPStorageNodeRecycle,
-		HAIncreaseAndCrashPX:              TriggerHAIncreaseAndCrashPX,
-		HAIncreaseAndRestartPX:            TriggerHAIncreaseAndPXRestart,
-		NodeMaintenanceCycle:              TriggerNodeMaintenanceCycle,
-		PoolMaintenanceCycle:              TriggerPoolMaintenanceCycle,
-		StorageFullPoolExpansion:          TriggerStorageFullPoolExpansion,
-		HAIncreaseWithPVCResize:           TriggerHAIncreasWithPVCResize,
-		ReallocateSharedMount:             TriggerReallocSharedMount,
-		CreateAndRunFioOnVcluster:         TriggerCreateAndRunFioOnVcluster,
-		CreateAndRunMultipleFioOnVcluster: TriggerCreateAndRunMultipleFioOnVcluster,
-		VolumeDriverDownVCluster:          TriggerVolumeDriverDownVCluster,
-		SetDiscardMounts:                  TriggerSetDiscardMounts,
-		PowerOffAllVMs:                    TriggerPowerOffAllVMs,
-		ResetDiscardMounts:                TriggerResetDiscardMounts,
-		ScaleFADAVolumeAttach:             TriggerScaleFADAVolumeAttach,
-		RestartKubeletService:             TriggerKubeletRestart,
-		PoolDelete:                        TriggerPoolDelete,
-		DefragScheduleCRUDOperations:      TriggerDefragScheduleCRUDOps,
-		DefragSchedules:                   TriggerDefragSchedules,
-		SVMotionSingleNode:                TriggerSvMotionSingleNode,
-		SVMotionMultipleNodes:             TriggerSvMotionMultipleNodes,
-	}
+	triggerFunctions = LongevityTriggers()
 	//Creating a distinct trigger to make sure email triggers at regular intervals
 	emailTriggerFunction = map[string]func(){
 		EmailReporter: TriggerEmailReporter,
@@ -162,8 +74,44 @@ var _ = Describe("{Longevity}", func() {
 
 		enableNFSProxyValidation()
 		TriggerDeployNewApps(&contexts, &triggerEventsChan)
+		startInvariants(&contexts)
 
 		var wg sync.WaitGroup
+		if len(Inst().ReplayEvents) > 0 {
+			Step("Replay recorded run", func() {
+				log.InfoD("Replaying [%d] recorded trigger executions", len(Inst().ReplayEvents))
+				go CollectEventRecords(&triggerEventsChan)
+				replayEvents(&contexts, &triggerEventsChan, Inst().ReplayEvents)
+			})
+			stopInvariants()
+			Step("teardown all apps", func() {
+				for _, ctx := range contexts {
+					TearDownContext(ctx, nil)
+				}
+			})
+			return
+		}
+		if chaosPlan != nil {
+			runChaosPlanLog := fmt.Sprintf("Run chaos plan [%s]", chaosPlan.Name)
+			Step(runChaosPlanLog, func() {
+				log.InfoD(runChaosPlanLog)
+				go CollectEventRecords(&triggerEventsChan)
+				report, err := newChaosPlanExecutor(&contexts, &triggerLock, &triggerEventsChan).Run(chaosPlan)
+				log.FailOnError(err, "failed to run chaos plan [%s]", chaosPlan.Name)
+				for id, result := range report.Steps {
+					log.InfoD("Chaos plan step [%s]: status [%s], runs [%d], errors %v", id, result.Status, result.Runs, result.Errors)
+				}
+				dash.VerifySafely(report.Failed(), false, fmt.Sprintf("verify chaos plan [%s] has no failed triggers", chaosPlan.Name))
+			})
+			stopInvariants()
+			Step("teardown all apps", func() {
+				for _, ctx := range contexts {
+					TearDownContext(ctx, nil)
+				}
+			})
+			return
+		}
+
 		Step("Register test triggers", func() {
 			for triggerType, triggerFunc := range triggerFunctions {
 				log.InfoD("Registering trigger: [%v]", triggerType)
@@ -188,6 +136,7 @@ var _ = Describe("{Longevity}", func() {
 		CollectEventRecords(&triggerEventsChan)
 		wg.Wait()
 		close(triggerEventsChan)
+		stopInvariants()
 		Step("teardown all apps", func() {
 			for _, ctx := range contexts {
 				TearDownContext(ctx, nil)
@@ -540,8 +489,13 @@ func testTrigger(wg *sync.WaitGroup,
 				log.Infof("===Releasing lock for non-disruptive event [%s]\n", triggerType)
 			}*/
 
-			triggerFunc(contexts, triggerEventsChan)
-			log.Infof("Trigger Function completed for [%s]\n", triggerType)
+			if lease, ok := acquireBlastRadius(triggerType); ok {
+				triggerFunc(contexts, triggerEventsChan)
+				log.Infof("Trigger Function completed for [%s]\n", triggerType)
+				if err := releaseBlastRadius(lease); err != nil {
+					log.Errorf("%v", err)
+				}
+			}
 
 			//if isDisruptiveTrigger(triggerType) {
 			triggerLoc.Unlock()
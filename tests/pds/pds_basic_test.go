@@ -142,6 +142,7 @@ var _ = BeforeSuite(func() {
 })
 
 var _ = AfterSuite(func() {
+	defer ShutdownTracing()
 	defer dash.TestSetEnd()
 	if params.BackUpAndRestore.RunBkpAndRestrTest {
 		switch params.BackUpAndRestore.TargetLocation {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/portworx/sched-ops/k8s/apiextensions"
 	"github.com/portworx/sched-ops/k8s/storage"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	"github.com/portworx/torpedo/drivers/node"
 	pdsbkp "github.com/portworx/torpedo/drivers/pds/pdsbackup"
 	restoreBkp "github.com/portworx/torpedo/drivers/pds/pdsrestore"
@@ -305,7 +306,7 @@ func ScaleUpDeployments(tenantID string, deployments map[PDSDataService]*pds.Mod
 	}
 }
 
-func CleanMapEntries(deleteMapEntries map[string]string) {
+func CleanMapEntries[V any](deleteMapEntries map[string]V) {
 	for hash := range deleteMapEntries {
 		delete(deleteMapEntries, hash)
 	}
@@ -455,21 +456,21 @@ func CleanUpBackUpTargets(projectID, objectStore, prefix string) error {
 
 // ValidateDataIntegrityPostRestore validates the md5hash for the given deployments and returns the workload pods
 func ValidateDataIntegrityPostRestore(dataServiceDeployments []*pds.ModelsDeployment,
-	pdsdeploymentsmd5Hash map[string]string) []*v1.Deployment {
+	pdsdeploymentsmd5Hash map[string]*integrity.Snapshot) []*v1.Deployment {
 	var (
 		wlDeploymentsToBeCleanedinDest []*v1.Deployment
-		restoredDeploymentsmd5Hash     = make(map[string]string)
+		restoredDeploymentsmd5Hash     = make(map[string]*integrity.Snapshot)
 	)
 	for _, pdsDeployment := range dataServiceDeployments {
-		ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+		snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 		wlDeploymentsToBeCleanedinDest = append(wlDeploymentsToBeCleanedinDest, wlDep)
 		log.FailOnError(err, "Error while Running workloads")
-		log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-		restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+		log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+		restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 	}
 
-	dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-		true, "Validate md5 hash after restore")
+	dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+		nil, "Validate data integrity after restore")
 
 	return wlDeploymentsToBeCleanedinDest
 }
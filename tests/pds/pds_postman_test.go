@@ -11,10 +11,10 @@ import (
 
 var _ = Describe("{RunPdsPostManApiLoadTests}", func() {
 	JustBeforeEach(func() {
-		StartTorpedoTest("RunPdsPostManApiLoadTests", "Run PDS Specific Api Load Tests using Postman-Newman ", pdsLabels, 0)
+		StartTorpedoTest("RunPdsPostManApiLoadTests", "Run PDS Specific Api Load Tests using Postman collections", pdsLabels, 0)
 	})
 	It("Deploy Dataservices", func() {
-		Step("Starting to execute Postman-Newman on PDS", func() {
+		Step("Starting to execute the Postman collection on PDS", func() {
 			currentTime := time.Now()
 			timeStamp := currentTime.Format("2006-01-02_15:04:05_MST")
 			resultsFileName := fmt.Sprintf("result_%s.json", timeStamp)
@@ -25,9 +25,11 @@ var _ = Describe("{RunPdsPostManApiLoadTests}", func() {
 				ResultType:      "cli,json",
 				Namespace:       params.InfraToTest.Namespace,
 				Iteration:       "2",
-				Kubeconfig:      ctx}
+				Kubeconfig:      ctx,
+				Monitor:         Inst().M,
+				TestName:        "RunPdsPostManApiLoadTests"}
 			err = postmanLib.GetProjectNameToExecutePostman("pds", &postmanParams)
-			log.FailOnError(err, "Postman-Newman Execution has failed due to- ")
+			log.FailOnError(err, "Postman Execution has failed due to- ")
 		})
 	})
 	JustAfterEach(func() {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	. "github.com/onsi/ginkgo/v2"
 	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	"github.com/portworx/torpedo/drivers/node"
 	pdsdriver "github.com/portworx/torpedo/drivers/pds"
 	"github.com/portworx/torpedo/drivers/pds/controlplane"
@@ -171,7 +172,7 @@ var _ = Describe("{RebootNodeDuringAppResourceUpdate}", func() {
 		)
 		stepLog := "Create Custom Templates , Deploy ds and Trigger Workload"
 		Step(stepLog, func() {
-			pdsdeploymentsmd5HashAfterResize := make(map[string]string)
+			pdsdeploymentsmd5HashAfterResize := make(map[string]*integrity.Snapshot)
 			for _, ds := range params.DataServiceToTest {
 				log.InfoD(stepLog)
 				CleanMapEntries(pdsdeploymentsmd5HashAfterResize)
@@ -377,9 +378,9 @@ var _ = Describe("{KillPdsAgentPodDuringAppScaleUp}", func() {
 		})
 		Step("Running Workloads", func() {
 			for _, deployment := range deployments {
-				ckSum2, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+				snapshot2, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 				log.FailOnError(err, "Error while Running workloads-%v", wlDep)
-				log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum2)
+				log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot2)
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 			}
 		})
@@ -1320,8 +1321,8 @@ var _ = Describe("{KillTeleportDuringWorkloadRun}", func() {
 // This testcase requires a cloud-drive setup
 var _ = Describe("{RestoreDSDuringPXPoolExpansion}", func() {
 	var deps []*pds.ModelsDeployment
-	pdsdeploymentsmd5Hash := make(map[string]string)
-	restoredDeploymentsmd5Hash := make(map[string]string)
+	pdsdeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
+	restoredDeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
 	var deploymentsToBeCleaned []*pds.ModelsDeployment
 	var wlDeploymentsToBeCleaned []*v1.Deployment
 	JustBeforeEach(func() {
@@ -1361,11 +1362,11 @@ var _ = Describe("{RestoreDSDuringPXPoolExpansion}", func() {
 
 		Step("Running Workloads before taking backups", func() {
 			for _, pdsDeployment := range deps {
-				ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(pdsDeployment, wkloadParams)
+				snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 				log.FailOnError(err, "Error while Running workloads")
-				log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-				pdsdeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+				log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+				pdsdeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 			}
 		})
 		Step("Perform adhoc backup and validate them", func() {
@@ -1427,11 +1428,11 @@ var _ = Describe("{RestoreDSDuringPXPoolExpansion}", func() {
 		})
 		Step("Validate md5hash for the restored deployments", func() {
 			for _, pdsDeployment := range deploymentsToBeCleaned {
-				ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+				snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 				log.FailOnError(err, "Error while Running workloads")
-				log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-				restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+				log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+				restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 			}
 			defer func() {
 				for _, wlDep := range wlDeploymentsToBeCleaned {
@@ -1439,8 +1440,8 @@ var _ = Describe("{RestoreDSDuringPXPoolExpansion}", func() {
 					log.FailOnError(err, "Failed while deleting the workload deployment")
 				}
 			}()
-			dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-				true, "Validate md5 hash after restore")
+			dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+				nil, "Validate data integrity after restore")
 		})
 		Step("Delete Deployments", func() {
 			CleanupDeployments(deploymentsToBeCleaned)
@@ -1453,8 +1454,8 @@ var _ = Describe("{RestoreDSDuringPXPoolExpansion}", func() {
 
 var _ = Describe("{RestoreDuringNodesAreRebooted}", func() {
 	var deps []*pds.ModelsDeployment
-	pdsdeploymentsmd5Hash := make(map[string]string)
-	restoredDeploymentsmd5Hash := make(map[string]string)
+	pdsdeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
+	restoredDeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
 	var deploymentsToBeCleaned []*pds.ModelsDeployment
 	var wlDeploymentsToBeCleaned []*v1.Deployment
 	JustBeforeEach(func() {
@@ -1494,11 +1495,11 @@ var _ = Describe("{RestoreDuringNodesAreRebooted}", func() {
 		})
 		Step("Running Workloads before taking backups", func() {
 			for _, pdsDeployment := range deps {
-				ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(pdsDeployment, wkloadParams)
+				snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 				log.FailOnError(err, "Error while Running workloads")
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
-				log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-				pdsdeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+				log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+				pdsdeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 			}
 		})
 		Step("Perform multiple adhoc backup and validate them", func() {
@@ -1570,13 +1571,13 @@ var _ = Describe("{RestoreDuringNodesAreRebooted}", func() {
 
 				Step("Validate md5hash for the restored deployments", func() {
 					for _, pdsDeployment := range newDeps {
-						ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+						snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 						wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 						log.FailOnError(err, "Error while Running workloads")
-						restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+						restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 					}
-					dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-						true, "Validate md5 hash after restore")
+					dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+						nil, "Validate data integrity after restore")
 				})
 			}
 		})
@@ -1630,13 +1631,13 @@ var _ = Describe("{RestoreDuringNodesAreRebooted}", func() {
 				}
 
 				Step("Validate md5hash for the restored deployments", func() {
-					ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(restoredDeployment, wkloadParams)
+					snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(restoredDeployment, wkloadParams)
 					wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 					log.FailOnError(err, "Error while Running workloads")
-					restoredDeploymentsmd5Hash[*restoredDeployment.ClusterResourceName] = ckSum
+					restoredDeploymentsmd5Hash[*restoredDeployment.ClusterResourceName] = snapshot
 
-					dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-						true, "Validate md5 hash after restore")
+					dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+						nil, "Validate data integrity after restore")
 				})
 			}
 		})
@@ -1663,8 +1664,8 @@ var _ = Describe("{RestoreDuringNodesAreRebooted}", func() {
 
 var _ = Describe("{RestoreDSDuringKVDBFailOver}", func() {
 	var deps []*pds.ModelsDeployment
-	pdsdeploymentsmd5Hash := make(map[string]string)
-	restoredDeploymentsmd5Hash := make(map[string]string)
+	pdsdeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
+	restoredDeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
 	var deploymentsToBeCleaned []*pds.ModelsDeployment
 	var wlDeploymentsToBeCleaned []*v1.Deployment
 	JustBeforeEach(func() {
@@ -1704,11 +1705,11 @@ var _ = Describe("{RestoreDSDuringKVDBFailOver}", func() {
 		})
 		Step("Running Workloads before taking backups", func() {
 			for _, pdsDeployment := range deps {
-				ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(pdsDeployment, wkloadParams)
+				snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 				log.FailOnError(err, "Error while Running workloads")
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
-				log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-				pdsdeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+				log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+				pdsdeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 			}
 		})
 		Step("Perform multiple adhoc backup and validate them", func() {
@@ -1771,11 +1772,11 @@ var _ = Describe("{RestoreDSDuringKVDBFailOver}", func() {
 		})
 		Step("Validate md5hash for the restored deployments", func() {
 			for _, pdsDeployment := range deploymentsToBeCleaned {
-				ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+				snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 				log.FailOnError(err, "Error while Running workloads")
-				log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-				restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+				log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+				restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 			}
 			defer func() {
 				for _, wlDep := range wlDeploymentsToBeCleaned {
@@ -1783,8 +1784,8 @@ var _ = Describe("{RestoreDSDuringKVDBFailOver}", func() {
 					log.FailOnError(err, "Failed while deleting the workload deployment")
 				}
 			}()
-			dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-				true, "Validate md5 hash after restore")
+			dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+				nil, "Validate data integrity after restore")
 		})
 		Step("Delete Deployments", func() {
 			dynamicDeps := pdslib.GetDynamicDeployments()
@@ -1864,9 +1865,9 @@ var _ = Describe("{StopPXDuringStorageResize}", func() {
 		})
 		Step("Running Workloads", func() {
 			for _, deployment := range deployments {
-				ckSum2, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+				snapshot2, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 				log.FailOnError(err, "Error while Running workloads-%v", wlDep)
-				log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum2)
+				log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot2)
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 			}
 		})
@@ -1956,9 +1957,9 @@ var _ = Describe("{KillDbMasterNodeDuringStorageResize}", func() {
 		Step("Running Workloads", func() {
 
 			for _, deployment := range deployments {
-				ckSum2, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+				snapshot2, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 				log.FailOnError(err, "Error while Running workloads-%v", wlDep)
-				log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum2)
+				log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot2)
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 			}
 		})
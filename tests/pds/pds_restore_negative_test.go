@@ -2,6 +2,7 @@ package tests
 
 import (
 	"fmt"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	"github.com/portworx/torpedo/drivers/node"
 	pdsdriver "github.com/portworx/torpedo/drivers/pds"
 	v1 "k8s.io/api/apps/v1"
@@ -367,7 +368,7 @@ var _ = Describe("{BringDownPXReplicaNodes}", func() {
 			nsName                        = params.InfraToTest.Namespace
 			flag                          bool
 			wlDeploymentsToBeCleanedinSrc []*v1.Deployment
-			pdsdeploymentsmd5Hash         = make(map[string]string)
+			pdsdeploymentsmd5Hash         = make(map[string]*integrity.Snapshot)
 			restoredDepsPostDriverStop    []*pds.ModelsDeployment
 			restoredDepsPostDriverStart   []*pds.ModelsDeployment
 			restoreClient                 restoreBkp.RestoreClient
@@ -403,11 +404,11 @@ var _ = Describe("{BringDownPXReplicaNodes}", func() {
 
 					stepLog = "Running Workloads before taking backups"
 					Step(stepLog, func() {
-						ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+						snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 						wlDeploymentsToBeCleanedinSrc = append(wlDeploymentsToBeCleanedinSrc, wlDep)
 						log.FailOnError(err, "Error while Running workloads")
-						log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum)
-						pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = ckSum
+						log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot)
+						pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = snapshot
 					})
 
 					stepLog = "Get the replica node and stop volume driver on the replica node"
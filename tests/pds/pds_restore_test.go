@@ -4,6 +4,7 @@ import (
 	"fmt"
 	. "github.com/onsi/ginkgo/v2"
 	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	pdsdriver "github.com/portworx/torpedo/drivers/pds"
 	"github.com/portworx/torpedo/drivers/pds/dataservice"
 	pdslib "github.com/portworx/torpedo/drivers/pds/lib"
@@ -52,8 +53,8 @@ var _ = Describe("{PerformRestoreToSameCluster}", func() {
 
 	It("Perform multiple restore within same cluster", func() {
 		var deps []*pds.ModelsDeployment
-		pdsdeploymentsmd5Hash := make(map[string]string)
-		restoredDeploymentsmd5Hash := make(map[string]string)
+		pdsdeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
+		restoredDeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
 		stepLog := "Deploy data service and take adhoc backup."
 		Step(stepLog, func() {
 			log.InfoD(stepLog)
@@ -87,11 +88,11 @@ var _ = Describe("{PerformRestoreToSameCluster}", func() {
 				})
 				stepLog = "Running Workloads before taking backups"
 				Step(stepLog, func() {
-					ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+					snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 					wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 					log.FailOnError(err, "Error while Running workloads")
-					log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum)
-					pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = ckSum
+					log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot)
+					pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = snapshot
 
 				})
 				stepLog = "Perform adhoc backup and validate them"
@@ -133,15 +134,15 @@ var _ = Describe("{PerformRestoreToSameCluster}", func() {
 					for _, pdsDeployment := range restoredDeployments {
 						err := dsTest.ValidateDataServiceDeployment(pdsDeployment, params.InfraToTest.Namespace)
 						log.FailOnError(err, "Error while validating deployment before validating checksum")
-						ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+						snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 						wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 						log.FailOnError(err, "Error while Running workloads")
-						log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-						restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+						log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+						restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 					}
 
-					dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-						true, "Validate md5 hash after restore")
+					dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+						nil, "Validate data integrity after restore")
 				})
 
 				Step("Clean up workload deployments", func() {
@@ -181,8 +182,8 @@ var _ = Describe("{PerformRestoreToDifferentCluster}", func() {
 
 	It("Perform multiple restore to different cluster", func() {
 		var deps []*pds.ModelsDeployment
-		pdsdeploymentsmd5Hash := make(map[string]string)
-		restoredDeploymentsmd5Hash := make(map[string]string)
+		pdsdeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
+		restoredDeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
 		stepLog := "Deploy data service and take adhoc backup."
 		Step(stepLog, func() {
 			log.InfoD(stepLog)
@@ -234,11 +235,11 @@ var _ = Describe("{PerformRestoreToDifferentCluster}", func() {
 				stepLog = "Running Workloads before taking backups"
 				Step(stepLog, func() {
 					if ds.Name != mongodb {
-						ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+						snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 						wlDeploymentsToBeCleanedinSrc = append(wlDeploymentsToBeCleanedinSrc, wlDep)
 						log.FailOnError(err, "Error while Running workloads")
-						log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum)
-						pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = ckSum
+						log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot)
+						pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = snapshot
 					}
 				})
 
@@ -302,15 +303,15 @@ var _ = Describe("{PerformRestoreToDifferentCluster}", func() {
 					log.InfoD(stepLog)
 					if ds.Name != mongodb {
 						for _, pdsDeployment := range restoredDeployments {
-							ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+							snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 							wlDeploymentsToBeCleanedinDest = append(wlDeploymentsToBeCleanedinDest, wlDep)
 							log.FailOnError(err, "Error while Running workloads")
-							log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-							restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+							log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+							restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 						}
 
-						dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-							true, "Validate md5 hash after restore")
+						dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+							nil, "Validate data integrity after restore")
 
 						log.InfoD("Cleaning up workload deployments")
 						for _, wlDep := range wlDeploymentsToBeCleanedinDest {
@@ -626,8 +627,8 @@ var _ = Describe("{PerformSimultaneousRestoresDifferentDataService}", func() {
 
 var _ = Describe("{PerformRestoreAfterHelmUpgrade}", func() {
 	var deps []*pds.ModelsDeployment
-	pdsdeploymentsmd5Hash := make(map[string]string)
-	restoredDeploymentsmd5Hash := make(map[string]string)
+	pdsdeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
+	restoredDeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
 	var deploymentsToBeCleaned []*pds.ModelsDeployment
 	var wlDeploymentsToBeCleaned []*v1.Deployment
 
@@ -683,11 +684,11 @@ var _ = Describe("{PerformRestoreAfterHelmUpgrade}", func() {
 		steplog = "Running Workloads before taking backups"
 		Step(steplog, func() {
 			for _, pdsDeployment := range deps {
-				ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(pdsDeployment, wkloadParams)
+				snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 				log.FailOnError(err, "Error while Running workloads")
-				log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-				pdsdeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+				log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+				pdsdeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 			}
 		})
 
@@ -761,11 +762,11 @@ var _ = Describe("{PerformRestoreAfterHelmUpgrade}", func() {
 		Step(steplog, func() {
 			log.InfoD(steplog)
 			for _, pdsDeployment := range deploymentsToBeCleaned {
-				ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+				snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 				wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 				log.FailOnError(err, "Error while Running workloads")
-				log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-				restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+				log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+				restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 			}
 
 			defer func() {
@@ -775,8 +776,8 @@ var _ = Describe("{PerformRestoreAfterHelmUpgrade}", func() {
 				}
 			}()
 
-			dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-				true, "Validate md5 hash after restore")
+			dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+				nil, "Validate data integrity after restore")
 		})
 	})
 
@@ -1044,7 +1045,7 @@ var _ = Describe("{PerformRestoreAfterDataServiceVersionUpdate}", func() {
 			restoredDep                   []*pds.ModelsDeployment
 			versionUpdatedDsEntity        restoreBkp.DSEntity
 			wlDeploymentsToBeCleanedinSrc []*v1.Deployment
-			pdsdeploymentsmd5Hash         = make(map[string]string)
+			pdsdeploymentsmd5Hash         = make(map[string]*integrity.Snapshot)
 			restoreClient                 restoreBkp.RestoreClient
 		)
 		stepLog := "Deploy data service and take adhoc backup."
@@ -1099,11 +1100,11 @@ var _ = Describe("{PerformRestoreAfterDataServiceVersionUpdate}", func() {
 
 					stepLog = "Running Workloads before taking backups"
 					Step(stepLog, func() {
-						ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+						snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 						wlDeploymentsToBeCleaned := append(wlDeploymentsToBeCleanedinSrc, wlDep)
 						log.FailOnError(err, "Error while Running workloads")
-						log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum)
-						pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = ckSum
+						log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot)
+						pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = snapshot
 						wlDeploymentsToBeCleanedinSrc = append(wlDeploymentsToBeCleanedinSrc, wlDeploymentsToBeCleaned...)
 					})
 
@@ -1236,7 +1237,7 @@ var _ = Describe("{PerformRestoreAfterEnablingTLSOnDataService}", func() {
 			originalDsEntity              restoreBkp.DSEntity
 			resourceTempUpdatedDsEntity   restoreBkp.DSEntity
 			wlDeploymentsToBeCleanedinSrc []*v1.Deployment
-			pdsdeploymentsmd5Hash         = make(map[string]string)
+			pdsdeploymentsmd5Hash         = make(map[string]*integrity.Snapshot)
 			restoreClient                 restoreBkp.RestoreClient
 		)
 		stepLog := "Deploy data service and take adhoc backup."
@@ -1282,11 +1283,11 @@ var _ = Describe("{PerformRestoreAfterEnablingTLSOnDataService}", func() {
 					stepLog = "Running Workloads before taking backups"
 					Step(stepLog, func() {
 						if ds.Name != mongodb {
-							ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+							snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 							wlDeploymentsToBeCleaned := append(wlDeploymentsToBeCleanedinSrc, wlDep)
 							log.FailOnError(err, "Error while Running workloads")
-							log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum)
-							pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = ckSum
+							log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot)
+							pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = snapshot
 							wlDeploymentsToBeCleanedinSrc = append(wlDeploymentsToBeCleanedinSrc, wlDeploymentsToBeCleaned...)
 						}
 					})
@@ -1483,7 +1484,7 @@ var _ = Describe("{PerformRestoreAfterDataServiceUpdate}", func() {
 			originalDsEntity                  restoreBkp.DSEntity
 			resourceTempUpdatedDsEntity       restoreBkp.DSEntity
 			wlDeploymentsToBeCleanedinSrc     []*v1.Deployment
-			pdsdeploymentsmd5Hash             = make(map[string]string)
+			pdsdeploymentsmd5Hash             = make(map[string]*integrity.Snapshot)
 			restoreClient                     restoreBkp.RestoreClient
 		)
 		stepLog := "Deploy data service and take adhoc backup."
@@ -1530,11 +1531,11 @@ var _ = Describe("{PerformRestoreAfterDataServiceUpdate}", func() {
 					stepLog = "Running Workloads before taking backups"
 					Step(stepLog, func() {
 						if ds.Name != mongodb {
-							ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+							snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 							wlDeploymentsToBeCleaned := append(wlDeploymentsToBeCleanedinSrc, wlDep)
 							log.FailOnError(err, "Error while Running workloads")
-							log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum)
-							pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = ckSum
+							log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot)
+							pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = snapshot
 							wlDeploymentsToBeCleanedinSrc = append(wlDeploymentsToBeCleanedinSrc, wlDeploymentsToBeCleaned...)
 						}
 					})
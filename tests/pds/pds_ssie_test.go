@@ -5,6 +5,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	pdsdriver "github.com/portworx/torpedo/drivers/pds"
 	pdslib "github.com/portworx/torpedo/drivers/pds/lib"
 	restoreBkp "github.com/portworx/torpedo/drivers/pds/pdsrestore"
@@ -34,8 +35,8 @@ var _ = Describe("{PerformPDSWorkflowMultipleIterations}", func() {
 	isRegistered := true
 	It("Perform multiple Iterations of deploy, backup, restore and deregister within same cluster", func() {
 		var deps []*pds.ModelsDeployment
-		pdsdeploymentsmd5Hash := make(map[string]string)
-		restoredDeploymentsmd5Hash := make(map[string]string)
+		pdsdeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
+		restoredDeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
 		backupSupportedDataServiceNameIDMap, err = bkpClient.GetAllBackupSupportedDataServices()
 		log.FailOnError(err, "Error while fetching the backup supported ds.")
 
@@ -71,11 +72,11 @@ var _ = Describe("{PerformPDSWorkflowMultipleIterations}", func() {
 					})
 					stepLog = "Running Workloads before taking backups"
 					Step(stepLog, func() {
-						ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+						snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 						wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 						log.FailOnError(err, "Error while Running workloads")
-						log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum)
-						pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = ckSum
+						log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot)
+						pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = snapshot
 
 					})
 					stepLog = "Perform adhoc backup and validate them"
@@ -117,15 +118,15 @@ var _ = Describe("{PerformPDSWorkflowMultipleIterations}", func() {
 						for _, pdsDeployment := range restoredDeployments {
 							err := dsTest.ValidateDataServiceDeployment(pdsDeployment, params.InfraToTest.Namespace)
 							log.FailOnError(err, "Error while validating deployment before validating checksum")
-							ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+							snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 							wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep)
 							log.FailOnError(err, "Error while Running workloads")
-							log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-							restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
+							log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+							restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
 						}
 
-						dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
-							true, "Validate md5 hash after restore")
+						dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5Hash, restoredDeploymentsmd5Hash),
+							nil, "Validate data integrity after restore")
 					})
 
 					Step("Clean up workload deployments", func() {
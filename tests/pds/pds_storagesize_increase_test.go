@@ -4,6 +4,7 @@ import (
 	"fmt"
 	. "github.com/onsi/ginkgo/v2"
 	pds "github.com/portworx/pds-api-go-client/pds/v1alpha1"
+	"github.com/portworx/torpedo/drivers/applications/integrity"
 	pdsdriver "github.com/portworx/torpedo/drivers/pds"
 	"github.com/portworx/torpedo/drivers/pds/controlplane"
 	_ "github.com/portworx/torpedo/drivers/pds/pdsbackup"
@@ -51,9 +52,9 @@ var _ = Describe("{ResizeStorageAndRestoreWithVariousFSandRepl}", func() {
 			increasedStorageSize     uint64
 			beforeResizePodAge       float64
 		)
-		pdsdeploymentsmd5HashUpdated := make(map[string]string)
-		restoredDeploymentsmd5Hash := make(map[string]string)
-		restoredDeploymentsmd5HashUpdated := make(map[string]string)
+		pdsdeploymentsmd5HashUpdated := make(map[string]*integrity.Snapshot)
+		restoredDeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
+		restoredDeploymentsmd5HashUpdated := make(map[string]*integrity.Snapshot)
 		stepLog := "Create Custom Templates , Deploy ds and Trigger Workload"
 		Step(stepLog, func() {
 			backupSupportedDataServiceNameIDMap, err = bkpClient.GetAllBackupSupportedDataServices()
@@ -144,10 +145,10 @@ var _ = Describe("{ResizeStorageAndRestoreWithVariousFSandRepl}", func() {
 							})
 							stepLog = "Validate Workload is running after storage resize by creating new workload"
 							Step(stepLog, func() {
-								ckSum2, wlDep2, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+								snapshot2, wlDep2, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 								log.FailOnError(err, "Error while Running workloads-%v", wlDep2)
-								log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum2)
-								pdsdeploymentsmd5HashUpdated[*deployment.ClusterResourceName] = ckSum2
+								log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot2)
+								pdsdeploymentsmd5HashUpdated[*deployment.ClusterResourceName] = snapshot2
 								wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep2)
 							})
 							stepLog = "Verify storage size before and after storage resize - Verify at STS, PV,PVC level"
@@ -204,19 +205,19 @@ var _ = Describe("{ResizeStorageAndRestoreWithVariousFSandRepl}", func() {
 							for _, pdsDeployment := range resDeployments {
 								err := dsTest.ValidateDataServiceDeployment(pdsDeployment, params.InfraToTest.Namespace)
 								log.FailOnError(err, "Error while validating deployment before validating checksum")
-								ckSum, wlDep, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParamsold)
-								ckSum2, wlDep2, err := dsTest.ReadDataAndReturnChecksum(pdsDeployment, wkloadParams)
+								snapshot, wlDep, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParamsold)
+								snapshot2, wlDep2, err := dsTest.ReadDataAndReturnSnapshot(pdsDeployment, wkloadParams)
 								wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep, wlDep2)
 								log.FailOnError(err, "Error while Running workloads")
-								log.Debugf("Checksum for the deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum)
-								log.Debugf("Checksum for the updated-deployment %s is %s", *pdsDeployment.ClusterResourceName, ckSum2)
-								restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = ckSum
-								restoredDeploymentsmd5HashUpdated[*pdsDeployment.ClusterResourceName] = ckSum2
+								log.Debugf("Snapshot of the deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot)
+								log.Debugf("Snapshot of the updated-deployment %s is %v", *pdsDeployment.ClusterResourceName, snapshot2)
+								restoredDeploymentsmd5Hash[*pdsDeployment.ClusterResourceName] = snapshot
+								restoredDeploymentsmd5HashUpdated[*pdsDeployment.ClusterResourceName] = snapshot2
 							}
-							dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsDeploymentHash, restoredDeploymentsmd5Hash),
-								true, "Validate md5 hash1 after restore")
-							dash.VerifyFatal(dsTest.ValidateDataMd5Hash(pdsdeploymentsmd5HashUpdated, restoredDeploymentsmd5HashUpdated),
-								true, "Validate md5 hash2 after restore")
+							dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsDeploymentHash, restoredDeploymentsmd5Hash),
+								nil, "Validate data integrity 1 after restore")
+							dash.VerifyFatal(dsTest.ValidateDataIntegrity(pdsdeploymentsmd5HashUpdated, restoredDeploymentsmd5HashUpdated),
+								nil, "Validate data integrity 2 after restore")
 						})
 
 						Step("Clean up workload deployments", func() {
@@ -285,7 +286,7 @@ var _ = Describe("{ScaleUpDsPostStorageSizeIncreaseVariousRepl}", func() {
 
 		stepLog := "Create Custom Templates , Deploy ds and Trigger Workload"
 		Step(stepLog, func() {
-			pdsdeploymentsmd5HashAfterResize := make(map[string]string)
+			pdsdeploymentsmd5HashAfterResize := make(map[string]*integrity.Snapshot)
 			for _, ds := range params.DataServiceToTest {
 				for _, repl := range params.StorageConfigurations.ReplFactor {
 					log.InfoD(stepLog)
@@ -362,10 +363,10 @@ var _ = Describe("{ScaleUpDsPostStorageSizeIncreaseVariousRepl}", func() {
 						stepLog = "Validate Workload is running after storage resize by creating new workload"
 						Step(stepLog, func() {
 
-							ckSum2, wlDep2, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+							snapshot2, wlDep2, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 							log.FailOnError(err, "Error while Running workloads-%v", wlDep2)
-							log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum2)
-							pdsdeploymentsmd5HashAfterResize[*deployment.ClusterResourceName] = ckSum2
+							log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot2)
+							pdsdeploymentsmd5HashAfterResize[*deployment.ClusterResourceName] = snapshot2
 							wlDeploymentsToBeCleaned = append(wlDeploymentsToBeCleaned, wlDep2)
 						})
 						stepLog = "Verify storage size before and after storage resize - Verify at STS, PV,PVC level"
@@ -605,7 +606,7 @@ var _ = Describe("{PerformStorageResizeBy1Gb100TimesAllDs}", func() {
 })
 
 // DeployDSWithCustomTemplatesRunWorkloads Deploy dataservice with custom templates and run workloads on them
-func DeployDSWithCustomTemplatesRunWorkloads(ds PDSDataService, tenantId string, templates controlplane.Templates) (*pds.ModelsDeployment, uint64, *pds.ModelsResourceSettingsTemplate, *pds.ModelsStorageOptionsTemplate, string, *v1.Deployment, map[string]string, pdsdriver.LoadGenParams, error) {
+func DeployDSWithCustomTemplatesRunWorkloads(ds PDSDataService, tenantId string, templates controlplane.Templates) (*pds.ModelsDeployment, uint64, *pds.ModelsResourceSettingsTemplate, *pds.ModelsStorageOptionsTemplate, string, *v1.Deployment, map[string]*integrity.Snapshot, pdsdriver.LoadGenParams, error) {
 	var (
 		dsVersions             = make(map[string]map[string][]string)
 		depList                []*pds.ModelsDeployment
@@ -627,7 +628,7 @@ func DeployDSWithCustomTemplatesRunWorkloads(ds PDSDataService, tenantId string,
 		FailOnError:    params.LoadGen.FailOnError,
 	}
 
-	pdsdeploymentsmd5Hash := make(map[string]string)
+	pdsdeploymentsmd5Hash := make(map[string]*integrity.Snapshot)
 	cusTempName := "autoTemp-" + strconv.Itoa(rand.Int())
 
 	dataserviceID, _ := dsTest.GetDataServiceID(ds.Name)
@@ -685,10 +686,10 @@ func DeployDSWithCustomTemplatesRunWorkloads(ds PDSDataService, tenantId string,
 		Deployment: deployment,
 	}
 	CleanMapEntries(pdsdeploymentsmd5Hash)
-	ckSum, wlDep, err := dsTest.InsertDataAndReturnChecksum(deployment, wkloadParams)
+	snapshot, wlDep, err := dsTest.InsertDataAndReturnSnapshot(deployment, wkloadParams)
 	workloadDep = wlDep
 	log.FailOnError(err, "Error while Running workloads")
-	log.Debugf("Checksum for the deployment %s is %s", *deployment.ClusterResourceName, ckSum)
-	pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = ckSum
+	log.Debugf("Snapshot of the deployment %s is %v", *deployment.ClusterResourceName, snapshot)
+	pdsdeploymentsmd5Hash[*deployment.ClusterResourceName] = snapshot
 	return deployment, initialCapacity, resConfigModel, stConfigModel, dataServiceAppConfigID, workloadDep, pdsdeploymentsmd5Hash, wkloadParams, nil
 }
@@ -6,7 +6,6 @@ import (
 	ctxt "context"
 	"fmt"
 	"math"
-	"math/rand"
 	"net/url"
 	"os"
 	"os/exec"
@@ -65,11 +64,15 @@ import (
 	"github.com/portworx/torpedo/pkg/applicationbackup"
 	"github.com/portworx/torpedo/pkg/asyncdr"
 	"github.com/portworx/torpedo/pkg/aututils"
+	"github.com/portworx/torpedo/pkg/blastradius"
+	"github.com/portworx/torpedo/pkg/chaosrand"
 	"github.com/portworx/torpedo/pkg/email"
 	"github.com/portworx/torpedo/pkg/errors"
+	"github.com/portworx/torpedo/pkg/eventjournal"
 	"github.com/portworx/torpedo/pkg/log"
 	"github.com/portworx/torpedo/pkg/pureutils"
 	"github.com/portworx/torpedo/pkg/stats"
+	"github.com/portworx/torpedo/pkg/tracing"
 	"github.com/portworx/torpedo/pkg/units"
 )
 
@@ -190,11 +193,24 @@ var EmailSubject string
 // CreatedBeforeTimeforNS to use for number of hours elapsed to get age of NS
 var CreatedBeforeTimeforNS int
 
+const (
+	// DefaultMigrationInterval is the interval of the schedule policy for migrations, in minutes
+	DefaultMigrationInterval = 3
+	// DefaultMigrationsCount is the number of migrations to be run
+	DefaultMigrationsCount = 5
+)
+
 // MigrationInterval to use for defining schedule policy for migrations
-var MigrationInterval int
+var MigrationInterval = DefaultMigrationInterval
 
 // MigrationsCount to use for number of migrations to be run
-var MigrationsCount int
+var MigrationsCount = DefaultMigrationsCount
+
+// TriggerTestCases makes each trigger execution a test case of its own: the torpedo log is
+// redirected to a log file of the execution and the dashboard records a test case. Runners
+// serving other requests while triggers run, for e.g. the API server, turn it off as the
+// log output and the dashboard test case are global.
+var TriggerTestCases = true
 
 // RunningTriggers map of events and corresponding interval
 var RunningTriggers map[string]time.Duration
@@ -259,6 +275,18 @@ var TestPassedCount = prometheus.TorpedoTestPassCount
 // FailedTestAlert is a flag to alert test failed
 var FailedTestAlert = prometheus.TorpedoAlertTestFailed
 
+// BackupDuration is histogram metric for the time taken by backups to succeed
+var BackupDuration = prometheus.TorpedoBackupDuration
+
+// VolumeAttachTime is histogram metric for the time taken by volumes to be attached after their creation
+var VolumeAttachTime = prometheus.TorpedoVolumeAttachTime
+
+// RebootToReadyTime is histogram metric for the time taken by nodes to be ready after a reboot
+var RebootToReadyTime = prometheus.TorpedoRebootToReadyTime
+
+// TriggerDuration is summary metric for the time taken by triggers
+var TriggerDuration = prometheus.TorpedoTriggerDuration
+
 // FioRunTime is default fio run time in seconds
 var VclusterFioRunTime = "86400"
 
@@ -287,11 +315,190 @@ type EventRecord struct {
 	Start   string
 	End     string
 	Outcome []error
+	// StartTime and EndTime are the times of Start and End, at full precision
+	StartTime time.Time
+	EndTime   time.Time
+	// Causes holds the original errors of Outcome, keeping their types
+	Causes []error
+	// Nodes and Volumes are the targets of the event
+	Nodes   []string
+	Volumes []string
+	// Cluster is the state of the cluster when the trigger started
+	Cluster eventjournal.ClusterSnapshot
+	// Execution is the execution number of the trigger, Draws the random decisions it
+	// took and Diverged is true if they stopped following a replayed run
+	Execution int
+	Draws     []chaosrand.Draw
+	Diverged  bool
+}
+
+// eventTargetsLock guards the targets of the event records, triggers add them
+// from several goroutines
+var eventTargetsLock sync.Mutex
+
+// EventTargetHook, if set, is called with the trigger type whenever a trigger
+// registers a target node or volume, for e.g. to bind the blast radius it claimed
+var EventTargetHook func(triggerType string, target blastradius.Kind, name string)
+
+// AddTargetNode records a node targeted by the event. The node is considered
+// under intentional disruption by the invariant checkers until the event ends.
+// It does nothing on a nil event, so helpers shared with non-longevity tests can call it.
+func (e *EventRecord) AddTargetNode(nodeName string) {
+	if e == nil || nodeName == "" {
+		return
+	}
+	eventTargetsLock.Lock()
+	for _, n := range e.Nodes {
+		if n == nodeName {
+			eventTargetsLock.Unlock()
+			return
+		}
+	}
+	e.Nodes = append(e.Nodes, nodeName)
+	eventTargetsLock.Unlock()
+	trigger := strings.Split(e.Event.Type, "<br>")[0]
+	Invariants.AddWindowNodes(trigger, nodeName)
+	if EventTargetHook != nil {
+		EventTargetHook(trigger, blastradius.Nodes, nodeName)
+	}
+}
+
+// AddTargetVolume records a volume targeted by the event. It does nothing on a nil event.
+func (e *EventRecord) AddTargetVolume(volumeID string) {
+	if e == nil || volumeID == "" {
+		return
+	}
+	eventTargetsLock.Lock()
+	for _, v := range e.Volumes {
+		if v == volumeID {
+			eventTargetsLock.Unlock()
+			return
+		}
+	}
+	e.Volumes = append(e.Volumes, volumeID)
+	eventTargetsLock.Unlock()
+	if EventTargetHook != nil {
+		EventTargetHook(strings.Split(e.Event.Type, "<br>")[0], blastradius.Volumes, volumeID)
+	}
+}
+
+// triggerExecution is the state of an execution of a trigger, from
+// startLongevityTest until its event record is sent
+type triggerExecution struct {
+	// stream holds the random decisions of the execution
+	stream *chaosrand.Stream
+	// span is the span of the execution, the driver calls made by the trigger are its children
+	span *tracing.Span
+	// cluster is the state of the cluster when the execution started
+	cluster eventjournal.ClusterSnapshot
+	// start is the time the execution started
+	start time.Time
+}
+
+var (
+	triggerExecutionsLock sync.Mutex
+	// triggerExecutions holds the current execution of each trigger type
+	triggerExecutions = make(map[string]*triggerExecution)
+	// runningTrigger is the execution between startLongevityTest and endLongevityTest
+	runningTrigger *triggerExecution
+)
+
+// helpersStream is the stream of the helpers called outside of a trigger
+const helpersStream = "Helpers"
+
+// beginTriggerExecution makes a new execution the current one of the trigger
+func beginTriggerExecution(triggerType string) *triggerExecution {
+	name := strings.Split(triggerType, "<br>")[0]
+	execution := &triggerExecution{
+		start:   time.Now(),
+		stream:  TriggerRand.Begin(name),
+		cluster: clusterSnapshot(),
+		span:    tracing.StartActive("trigger "+name, tracing.Trigger(name)),
+	}
+	triggerExecutionsLock.Lock()
+	defer triggerExecutionsLock.Unlock()
+	triggerExecutions[name] = execution
+	runningTrigger = execution
+	return execution
+}
+
+// endTriggerExecution ends the span of the current execution of the trigger. The
+// execution stays the current one of its trigger until the trigger starts again.
+func endTriggerExecution(triggerType string) {
+	execution := currentTriggerExecution(triggerType)
+	if execution == nil {
+		return
+	}
+	triggerExecutionsLock.Lock()
+	if runningTrigger == execution {
+		runningTrigger = nil
+	}
+	triggerExecutionsLock.Unlock()
+	execution.span.End()
+}
+
+// helperRand returns the random stream of the running trigger, for the helpers in
+// common.go that do not know which trigger calls them. Outside of a trigger it
+// returns the stream of the helpers.
+func helperRand() *chaosrand.Stream {
+	triggerExecutionsLock.Lock()
+	execution := runningTrigger
+	triggerExecutionsLock.Unlock()
+	if execution == nil {
+		return triggerRand(helpersStream)
+	}
+	return execution.stream
+}
+
+// currentTriggerExecution returns the current execution of the trigger, nil if it never started one
+func currentTriggerExecution(triggerType string) *triggerExecution {
+	triggerExecutionsLock.Lock()
+	defer triggerExecutionsLock.Unlock()
+	return triggerExecutions[strings.Split(triggerType, "<br>")[0]]
+}
+
+// endEventRecord sets the end time of the event and copies onto it the state of the
+// trigger execution it belongs to, including its start time. Triggers call it right
+// before sending the record, while their execution is still the current one.
+func endEventRecord(event *EventRecord) {
+	event.EndTime = time.Now()
+	event.End = event.EndTime.Format(time.RFC1123)
+	if execution := currentTriggerExecution(event.Event.Type); execution != nil {
+		event.StartTime = execution.start
+		event.Cluster = execution.cluster
+		event.Execution = execution.stream.Execution()
+		event.Draws = execution.stream.Draws()
+		event.Diverged = execution.stream.Diverged()
+	}
+}
+
+// clusterSnapshot returns the state of the cluster journaled along with the events.
+// It is only taken when an event journal is configured.
+func clusterSnapshot() eventjournal.ClusterSnapshot {
+	if Inst().EventJournal == nil {
+		return eventjournal.ClusterSnapshot{}
+	}
+	snapshot := eventjournal.ClusterSnapshot{
+		VolumeDriver: Inst().V.String(),
+		Scheduler:    Inst().S.String(),
+		NodeCount:    len(node.GetNodes()),
+	}
+	if version, err := Inst().V.GetDriverVersion(); err == nil {
+		snapshot.VolumeDriverVersion = version
+	}
+	if version, err := k8sCore.GetVersion(); err == nil {
+		snapshot.SchedulerVersion = version.String()
+	}
+	return snapshot
 }
 
 // eventRing is circular buffer to store
 // events for sending email notifications
-var eventRing *ring.Ring
+var eventRing = ring.New(100)
+
+// TriggerRand is the source of all random decisions taken by triggers. It is
+// seeded from the random-seed flag, or replays the draws of a journaled run.
+var TriggerRand = chaosrand.NewSource(time.Now().UnixNano())
 
 // decommissionedNode for rejoin test
 var decommissionedNode = node.Node{}
@@ -410,6 +617,10 @@ func UpdateOutcome(event *EventRecord, err error) {
 			er := fmt.Errorf(err.Error() + "<br>")
 			Inst().M.IncrementGaugeMetricsUsingAdditionalLabel(FailedTestAlert, event.Event.Type, err.Error())
 			event.Outcome = append(event.Outcome, er)
+			event.Causes = append(event.Causes, err)
+			if execution := currentTriggerExecution(event.Event.Type); execution != nil {
+				execution.span.Fail(err)
+			}
 			createLongevityJiraIssue(event, er)
 		} else {
 			log.FailOnError(err, "error in validation")
@@ -681,12 +892,15 @@ const (
 
 	// SVMotionMultipleNodes does storage vmotions for 50% of the worker nodes in parallel (Max 20 at a time)
 	SVMotionMultipleNodes = "svmotionMultipleNodes"
+
+	// HelmUpgradeRollback upgrades the helm releases of the apps deployed with helm and rolls them back
+	HelmUpgradeRollback = "helmUpgradeRollback"
 )
 
 // TriggerCoreChecker checks if any cores got generated
 func TriggerCoreChecker(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CoreChecker)
 	startLongevityTest(CoreChecker)
 	event := &EventRecord{
 		Event: Event{
@@ -697,7 +911,7 @@ func TriggerCoreChecker(contexts *[]*scheduler.Context, recordChan *chan *EventR
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	coresMap = nil
@@ -736,15 +950,22 @@ func TriggerCoreChecker(contexts *[]*scheduler.Context, recordChan *chan *EventR
 }
 
 func startLongevityTest(testName string) {
-	longevityLogger = CreateLogger(fmt.Sprintf("%s-%s.log", testName, time.Now().Format(time.RFC3339)))
-	log.SetTorpedoFileOutput(longevityLogger)
-	dash.TestCaseBegin(testName, fmt.Sprintf("validating %s in longevity cluster", testName), "", nil)
+	beginTriggerExecution(testName)
+	Invariants.BeginWindow(testName)
+	if TriggerTestCases {
+		longevityLogger = CreateLogger(fmt.Sprintf("%s-%s.log", testName, time.Now().Format(time.RFC3339)))
+		log.SetTorpedoFileOutput(longevityLogger)
+		dash.TestCaseBegin(testName, fmt.Sprintf("validating %s in longevity cluster", testName), "", nil)
+	}
 	PrintPxctlStatus()
 }
-func endLongevityTest() {
+func endLongevityTest(testName string) {
+	defer endTriggerExecution(testName)
 	PrintPxctlStatus()
-	dash.TestCaseEnd()
-	CloseLogger(longevityLogger)
+	if TriggerTestCases {
+		dash.TestCaseEnd()
+		CloseLogger(longevityLogger)
+	}
 }
 
 func updateLongevityStats(name, eventStatName string, dashStats map[string]string) {
@@ -772,7 +993,7 @@ func updateLongevityStats(name, eventStatName string, dashStats map[string]strin
 // TriggerDeployNewApps deploys applications in separate namespaces
 func TriggerDeployNewApps(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(DeployApps)
 	startLongevityTest(DeployApps)
 	event := &EventRecord{
 		Event: Event{
@@ -784,7 +1005,7 @@ func TriggerDeployNewApps(contexts *[]*scheduler.Context, recordChan *chan *Even
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -873,7 +1094,7 @@ func TriggerDeployNewApps(contexts *[]*scheduler.Context, recordChan *chan *Even
 // TriggerDetachDrives Detach all vsphere cloud drives, verify storage node will be recoevred after node maintenance cycle.
 func TriggerDetachDrives(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(DetachDrives)
 	startLongevityTest(DetachDrives)
 	event := &EventRecord{
 		Event: Event{
@@ -884,7 +1105,7 @@ func TriggerDetachDrives(contexts *[]*scheduler.Context, recordChan *chan *Event
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -918,6 +1139,7 @@ func TriggerDetachDrives(contexts *[]*scheduler.Context, recordChan *chan *Event
 				var nodeId string
 				storageNodes := node.GetStorageNodes()
 				nodeId = storageNodes[0].VolDriverNodeID
+				event.AddTargetNode(storageNodes[0].Name)
 				pxCloudDriveConfigMap, err := Inst().S.GetPXCloudDriveConfigMap(stc)
 				if err != nil {
 					UpdateOutcome(event, err)
@@ -1004,7 +1226,7 @@ func TriggerDetachDrives(contexts *[]*scheduler.Context, recordChan *chan *Event
 func TriggerVolumeCreatePXRestart(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(VolumeCreatePxRestart)
 	startLongevityTest(VolumeCreatePxRestart)
 	event := &EventRecord{
 		Event: Event{
@@ -1016,7 +1238,7 @@ func TriggerVolumeCreatePXRestart(contexts *[]*scheduler.Context, recordChan *ch
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -1029,9 +1251,10 @@ func TriggerVolumeCreatePXRestart(contexts *[]*scheduler.Context, recordChan *ch
 		log.InfoD(stepLog)
 
 		stNodes := node.GetStorageNodes()
-		index := randIntn(1, len(stNodes))[0]
+		index := randIntn(triggerRand(event.Event.Type), 1, len(stNodes))[0]
 
 		selectedNode := stNodes[index]
+		event.AddTargetNode(selectedNode.Name)
 
 		log.InfoD("Creating and attaching %d volumes on node %s", volCreateCount, selectedNode.Name)
 
@@ -1056,6 +1279,9 @@ func TriggerVolumeCreatePXRestart(contexts *[]*scheduler.Context, recordChan *ch
 			})
 		}(selectedNode)
 		wg.Wait()
+		for vol := range createdVolIDs {
+			event.AddTargetVolume(vol)
+		}
 
 	})
 
@@ -1114,7 +1340,7 @@ func TriggerVolumeCreatePXRestart(contexts *[]*scheduler.Context, recordChan *ch
 // TriggerHAIncreaseAndReboot triggers repl increase and reboots target and source nodes
 func TriggerHAIncreaseAndReboot(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(HAIncreaseAndReboot)
 	startLongevityTest(HAIncreaseAndReboot)
 	event := &EventRecord{
 		Event: Event{
@@ -1126,7 +1352,7 @@ func TriggerHAIncreaseAndReboot(contexts *[]*scheduler.Context, recordChan *chan
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -1138,7 +1364,7 @@ func TriggerHAIncreaseAndReboot(contexts *[]*scheduler.Context, recordChan *chan
 // TriggerHAIncreaseAndPXRestart triggers repl increase and restart PX on target and source nodes
 func TriggerHAIncreaseAndPXRestart(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(HAIncreaseAndRestartPX)
 	startLongevityTest(HAIncreaseAndRestartPX)
 	event := &EventRecord{
 		Event: Event{
@@ -1150,7 +1376,7 @@ func TriggerHAIncreaseAndPXRestart(contexts *[]*scheduler.Context, recordChan *c
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -1162,7 +1388,7 @@ func TriggerHAIncreaseAndPXRestart(contexts *[]*scheduler.Context, recordChan *c
 // TriggerHAIncreaseAndCrashPX triggers repl increase and crash PX on target and source nodes
 func TriggerHAIncreaseAndCrashPX(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(HAIncreaseAndCrashPX)
 	startLongevityTest(HAIncreaseAndCrashPX)
 	event := &EventRecord{
 		Event: Event{
@@ -1174,7 +1400,7 @@ func TriggerHAIncreaseAndCrashPX(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -1270,6 +1496,7 @@ func haIncreaseWithErrorInjection(event *EventRecord, contexts *[]*scheduler.Con
 					continue
 				}
 				initialRepls[v] = currRep
+				event.AddTargetVolume(v.ID)
 
 				if currRep != 0 {
 					for {
@@ -1344,7 +1571,7 @@ func haIncreaseWithErrorInjection(event *EventRecord, contexts *[]*scheduler.Con
 // TriggerHAIncrease performs repl-add on all volumes of given contexts
 func TriggerHAIncrease(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(HAIncrease)
 	startLongevityTest(HAIncrease)
 	event := &EventRecord{
 		Event: Event{
@@ -1356,7 +1583,7 @@ func TriggerHAIncrease(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -1399,6 +1626,7 @@ func TriggerHAIncrease(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 				if replStatus != "Up" {
 					continue
 				}
+				event.AddTargetVolume(v.ID)
 				MaxRF := Inst().V.GetMaxReplicationFactor()
 				stepLog = fmt.Sprintf("repl increase volume driver %s on app %s's volume: %v",
 					Inst().V.String(), ctx.App.Key, v)
@@ -1509,7 +1737,7 @@ func TriggerHAIncrease(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 // TriggerHAIncreasWithPVCResize performs repl-add on all volumes of given contexts and do pvc resize
 func TriggerHAIncreasWithPVCResize(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(HAIncreaseWithPVCResize)
 	startLongevityTest(HAIncreaseWithPVCResize)
 	event := &EventRecord{
 		Event: Event{
@@ -1521,7 +1749,7 @@ func TriggerHAIncreasWithPVCResize(contexts *[]*scheduler.Context, recordChan *c
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -1600,6 +1828,7 @@ func TriggerHAIncreasWithPVCResize(contexts *[]*scheduler.Context, recordChan *c
 				if replStatus != "Up" {
 					continue
 				}
+				event.AddTargetVolume(v.ID)
 				stepLog = fmt.Sprintf("repl increase volume driver %s on app %s's volume: %v",
 					Inst().V.String(), ctx.App.Key, v)
 				Step(stepLog,
@@ -1703,7 +1932,7 @@ func TriggerHAIncreasWithPVCResize(contexts *[]*scheduler.Context, recordChan *c
 // TriggerHADecrease performs repl-reduce on all volumes of given contexts
 func TriggerHADecrease(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(HADecrease)
 	startLongevityTest(HADecrease)
 	event := &EventRecord{
 		Event: Event{
@@ -1715,7 +1944,7 @@ func TriggerHADecrease(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -1749,6 +1978,7 @@ func TriggerHADecrease(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 					log.Warnf("Repl decrease on Pure DA volume:[%s] not supported.Skipping repl decrease operation in pure volume", v.Name)
 					continue
 				}
+				event.AddTargetVolume(v.ID)
 				MinRF := Inst().V.GetMinReplicationFactor()
 				stepLog = fmt.Sprintf("repl decrease volume driver %s on app %s's volume: %v",
 					Inst().V.String(), ctx.App.Key, v)
@@ -1832,7 +2062,7 @@ func TriggerHADecrease(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 // TriggerAppTaskDown deletes application task for all contexts
 func TriggerAppTaskDown(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(AppTaskDown)
 	startLongevityTest(AppTaskDown)
 	event := &EventRecord{
 		Event: Event{
@@ -1844,7 +2074,7 @@ func TriggerAppTaskDown(contexts *[]*scheduler.Context, recordChan *chan *EventR
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -1881,7 +2111,7 @@ func TriggerAppTaskDown(contexts *[]*scheduler.Context, recordChan *chan *EventR
 // TriggerCrashVolDriver crashes vol driver
 func TriggerCrashVolDriver(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CrashVolDriver)
 	startLongevityTest(CrashVolDriver)
 	event := &EventRecord{
 		Event: Event{
@@ -1893,7 +2123,7 @@ func TriggerCrashVolDriver(contexts *[]*scheduler.Context, recordChan *chan *Eve
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -1906,6 +2136,7 @@ func TriggerCrashVolDriver(contexts *[]*scheduler.Context, recordChan *chan *Eve
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 			stepLog = fmt.Sprintf("crash volume driver %s on node: %v",
 				Inst().V.String(), appNode.Name)
 			nodeContexts, err := GetContextsOnNode(contexts, &appNode)
@@ -1937,7 +2168,7 @@ func TriggerCrashVolDriver(contexts *[]*scheduler.Context, recordChan *chan *Eve
 // TriggerCrashPXDaemon crashes vol driver
 func TriggerCrashPXDaemon(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CrashPXDaemon)
 	startLongevityTest(CrashPXDaemon)
 	event := &EventRecord{
 		Event: Event{
@@ -1949,7 +2180,7 @@ func TriggerCrashPXDaemon(contexts *[]*scheduler.Context, recordChan *chan *Even
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -1962,6 +2193,7 @@ func TriggerCrashPXDaemon(contexts *[]*scheduler.Context, recordChan *chan *Even
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 			stepLog = fmt.Sprintf("crash volume driver %s on node: %v",
 				Inst().V.String(), appNode.Name)
 			nodeContexts, err := GetContextsOnNode(contexts, &appNode)
@@ -1993,7 +2225,7 @@ func TriggerCrashPXDaemon(contexts *[]*scheduler.Context, recordChan *chan *Even
 // TriggerKubeletRestart restarts kubelet on the nodes
 func TriggerKubeletRestart(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(RestartKubeletService)
 	startLongevityTest(RestartKubeletService)
 	event := &EventRecord{
 		Event: Event{
@@ -2005,7 +2237,7 @@ func TriggerKubeletRestart(contexts *[]*scheduler.Context, recordChan *chan *Eve
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -2018,6 +2250,7 @@ func TriggerKubeletRestart(contexts *[]*scheduler.Context, recordChan *chan *Eve
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 			stepLog = fmt.Sprintf("crash volume driver %s on node: %v",
 				Inst().V.String(), appNode.Name)
 			nodeContexts, err := GetContextsOnNode(contexts, &appNode)
@@ -2049,7 +2282,7 @@ func TriggerKubeletRestart(contexts *[]*scheduler.Context, recordChan *chan *Eve
 // TriggerRestartVolDriver restarts volume driver and validates app
 func TriggerRestartVolDriver(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(RestartVolDriver)
 	startLongevityTest(RestartVolDriver)
 	event := &EventRecord{
 		Event: Event{
@@ -2061,7 +2294,7 @@ func TriggerRestartVolDriver(contexts *[]*scheduler.Context, recordChan *chan *E
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -2075,6 +2308,7 @@ func TriggerRestartVolDriver(contexts *[]*scheduler.Context, recordChan *chan *E
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 
 			stepLog = fmt.Sprintf("stop volume driver %s on node: %s",
 				Inst().V.String(), appNode.Name)
@@ -2132,7 +2366,7 @@ func TriggerRestartVolDriver(contexts *[]*scheduler.Context, recordChan *chan *E
 // TriggerNodeMaintenanceCycle performs node maintenance enter and exit and validates app
 func TriggerNodeMaintenanceCycle(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(NodeMaintenanceCycle)
 	startLongevityTest(NodeMaintenanceCycle)
 	event := &EventRecord{
 		Event: Event{
@@ -2144,7 +2378,7 @@ func TriggerNodeMaintenanceCycle(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -2157,6 +2391,7 @@ func TriggerNodeMaintenanceCycle(contexts *[]*scheduler.Context, recordChan *cha
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 			stepLog = fmt.Sprintf("enter maintenance on node: %s", appNode.Name)
 			nodeContexts, err := GetContextsOnNode(contexts, &appNode)
 			UpdateOutcome(event, err)
@@ -2211,7 +2446,7 @@ func TriggerNodeMaintenanceCycle(contexts *[]*scheduler.Context, recordChan *cha
 // TriggerPoolMaintenanceCycle performs pool maintenance enter and exit and validates app
 func TriggerPoolMaintenanceCycle(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(PoolMaintenanceCycle)
 	startLongevityTest(PoolMaintenanceCycle)
 	event := &EventRecord{
 		Event: Event{
@@ -2223,7 +2458,7 @@ func TriggerPoolMaintenanceCycle(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -2236,6 +2471,7 @@ func TriggerPoolMaintenanceCycle(contexts *[]*scheduler.Context, recordChan *cha
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 
 			stepLog = fmt.Sprintf("enter pool maintenance on node: %s", appNode.Name)
 			nodeContexts, err := GetContextsOnNode(contexts, &appNode)
@@ -2308,7 +2544,7 @@ func isNodeHealthy(n node.Node, eventType string) error {
 // TriggerStorageFullPoolExpansion performs pool expansion on storageFull nodes
 func TriggerStorageFullPoolExpansion(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(StorageFullPoolExpansion)
 	startLongevityTest(StorageFullPoolExpansion)
 	event := &EventRecord{
 		Event: Event{
@@ -2320,7 +2556,7 @@ func TriggerStorageFullPoolExpansion(contexts *[]*scheduler.Context, recordChan
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -2336,13 +2572,14 @@ func TriggerStorageFullPoolExpansion(contexts *[]*scheduler.Context, recordChan
 			}
 			for poolUUID, v := range poolsStatus {
 				if v == "Offline" {
+					event.AddTargetNode(appNode.Name)
 					taskStep := fmt.Sprintf("expanding storagefull pool [%s] on node [%s]", poolUUID,
 						appNode.Name)
 					event.Event.Type += "<br>" + taskStep
 					log.InfoD("Pool [%s] on node [%s] is offline", v, appNode.Name)
 					poolResizeType := []opsapi.SdkStoragePool_ResizeOperationType{opsapi.SdkStoragePool_RESIZE_TYPE_AUTO,
 						opsapi.SdkStoragePool_RESIZE_TYPE_RESIZE_DISK, opsapi.SdkStoragePool_RESIZE_TYPE_ADD_DISK}
-					resizeOpType := poolResizeType[rand.Intn(len(poolResizeType))]
+					resizeOpType := poolResizeType[triggerRand(event.Event.Type).Intn(len(poolResizeType))]
 					selectedPool, err := GetStoragePoolByUUID(poolUUID)
 					if err != nil {
 						UpdateOutcome(event, err)
@@ -2478,7 +2715,7 @@ func validateContexts(event *EventRecord, contexts *[]*scheduler.Context) {
 // TriggerRestartManyVolDriver restarts one or more volume drivers and validates app
 func TriggerRestartManyVolDriver(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(RestartManyVolDriver)
 	startLongevityTest(RestartManyVolDriver)
 	event := &EventRecord{
 		Event: Event{
@@ -2490,7 +2727,7 @@ func TriggerRestartManyVolDriver(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -2507,6 +2744,7 @@ func TriggerRestartManyVolDriver(contexts *[]*scheduler.Context, recordChan *cha
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 			dashStats := make(map[string]string)
 			dashStats["node"] = appNode.Name
 			updateLongevityStats(RestartManyVolDriver, stats.PXRestartEventName, dashStats)
@@ -2570,7 +2808,7 @@ func TriggerRestartManyVolDriver(contexts *[]*scheduler.Context, recordChan *cha
 // TriggerRestartKvdbVolDriver restarts volume driver where kvdb resides and validates app
 func TriggerRestartKvdbVolDriver(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(RestartKvdbVolDriver)
 	startLongevityTest(RestartKvdbVolDriver)
 	event := &EventRecord{
 		Event: Event{
@@ -2582,7 +2820,7 @@ func TriggerRestartKvdbVolDriver(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -2612,6 +2850,7 @@ func TriggerRestartKvdbVolDriver(contexts *[]*scheduler.Context, recordChan *cha
 				}
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 
 			stepLog = fmt.Sprintf("stop volume driver %s on node: %s",
 				Inst().V.String(), appNode.Name)
@@ -2662,7 +2901,7 @@ func TriggerRestartKvdbVolDriver(contexts *[]*scheduler.Context, recordChan *cha
 
 func TriggerValidatePdsApps(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(ValidatePdsApps)
 	startLongevityTest(ValidatePdsApps)
 	event := &EventRecord{
 		Event: Event{
@@ -2674,7 +2913,7 @@ func TriggerValidatePdsApps(contexts *[]*scheduler.Context, recordChan *chan *Ev
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -2695,7 +2934,7 @@ func TriggerValidatePdsApps(contexts *[]*scheduler.Context, recordChan *chan *Ev
 // TriggerRebootNodes reboots node on which apps are running
 func TriggerRebootNodes(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(RebootNode)
 	startLongevityTest(RebootNode)
 	event := &EventRecord{
 		Event: Event{
@@ -2707,7 +2946,7 @@ func TriggerRebootNodes(contexts *[]*scheduler.Context, recordChan *chan *EventR
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -2730,6 +2969,8 @@ func TriggerRebootNodes(contexts *[]*scheduler.Context, recordChan *chan *EventR
 						UpdateOutcome(event, err)
 						continue
 					}
+					event.AddTargetNode(n.Name)
+					var rebootStart time.Time
 					stepLog = fmt.Sprintf("reboot node: %s", n.Name)
 					appNodeContexts, err := GetContextsOnNode(contexts, &n)
 					UpdateOutcome(event, err)
@@ -2741,6 +2982,7 @@ func TriggerRebootNodes(contexts *[]*scheduler.Context, recordChan *chan *EventR
 						dashStats := make(map[string]string)
 						dashStats["node"] = n.Name
 						updateLongevityStats(RebootNode, stats.NodeRebootEventName, dashStats)
+						rebootStart = time.Now()
 						err := Inst().N.RebootNode(n, node.RebootNodeOpts{
 							Force: true,
 							ConnectionOpts: node.ConnectionOpts{
@@ -2779,6 +3021,9 @@ func TriggerRebootNodes(contexts *[]*scheduler.Context, recordChan *chan *EventR
 
 						err = Inst().V.WaitDriverUpOnNode(n, Inst().DriverStartTimeout)
 						UpdateOutcome(event, err)
+						if err == nil && !rebootStart.IsZero() {
+							Inst().M.ObserveHistogramMetric(RebootToReadyTime, time.Since(rebootStart).Seconds(), RebootNode, n.Name)
+						}
 					})
 					validateContexts(event, contexts)
 				}
@@ -2793,7 +3038,7 @@ func TriggerRebootNodes(contexts *[]*scheduler.Context, recordChan *chan *EventR
 // TriggerRebootManyNodes reboots one or more nodes on which apps are running
 func TriggerRebootManyNodes(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(RebootManyNodes)
 	startLongevityTest(RebootManyNodes)
 	event := &EventRecord{
 		Event: Event{
@@ -2805,7 +3050,7 @@ func TriggerRebootManyNodes(contexts *[]*scheduler.Context, recordChan *chan *Ev
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -2822,6 +3067,7 @@ func TriggerRebootManyNodes(contexts *[]*scheduler.Context, recordChan *chan *Ev
 				continue
 			}
 			selectedNodes = append(selectedNodes, n)
+			event.AddTargetNode(n.Name)
 		}
 		// Reboot node and check driver status
 		stepLog = fmt.Sprintf("rebooting [%d] node(s)", len(selectedNodes))
@@ -2906,65 +3152,58 @@ func TriggerRebootManyNodes(contexts *[]*scheduler.Context, recordChan *chan *Ev
 	})
 }
 
-func randIntn(n, maxNo int) []int {
+// randIntn returns n distinct random values in [0, maxNo)
+func randIntn(r *chaosrand.Stream, n, maxNo int) []int {
 	if n > maxNo {
 		n = maxNo
 	}
-	rand.Seed(time.Now().UnixNano())
-	generated := make(map[int]bool)
-	for i := 0; i < n; i++ {
-		randUniq := rand.Intn(maxNo)
-		if !generated[randUniq] {
-			generated[randUniq] = true
-		}
-	}
-	generatedNs := make([]int, 0)
-	for key := range generated {
-		generatedNs = append(generatedNs, key)
-	}
-	return generatedNs
+	return r.Perm(maxNo)[:n]
+}
+
+// triggerRand returns the random stream of the current execution of the given trigger.
+// All random decisions of triggers go through it so runs can be replayed.
+func triggerRand(triggerType string) *chaosrand.Stream {
+	return TriggerRand.Stream(strings.Split(triggerType, "<br>")[0])
 }
 
 func getNodesByChaosLevel(triggerType string) []node.Node {
-	t := ChaosMap[triggerType]
 	stNodes := node.GetStorageDriverNodes()
-	stNodesLen := len(stNodes)
-	nodes := make([]node.Node, 0)
-	var nodeLen float32
-	switch t {
-	case 10:
-		index := randIntn(1, stNodesLen)[0]
-		return []node.Node{stNodes[index]}
-	case 9:
-		nodeLen = float32(stNodesLen) * 0.2
-	case 8:
-		nodeLen = float32(stNodesLen) * 0.3
-	case 7:
-		nodeLen = float32(stNodesLen) * 0.4
-	case 6:
-		nodeLen = float32(stNodesLen) * 0.5
-	case 5:
-		nodeLen = float32(stNodesLen) * 0.6
-	case 4:
-		nodeLen = float32(stNodesLen) * 0.7
-	case 3:
-		nodeLen = float32(stNodesLen) * 0.8
-	case 2:
-		nodeLen = float32(stNodesLen) * 0.9
-	case 1:
+	if ChaosMap[triggerType] == 1 {
 		return stNodes
 	}
-	generatedNodeIndexes := randIntn(int(nodeLen), stNodesLen)
-	for i := 0; i < len(generatedNodeIndexes); i++ {
-		nodes = append(nodes, stNodes[generatedNodeIndexes[i]])
+	nodes := make([]node.Node, 0)
+	nodeIndexes := randIntn(triggerRand(triggerType), chaosLevelNodeCount(ChaosMap[triggerType], len(stNodes)), len(stNodes))
+	for _, i := range nodeIndexes {
+		nodes = append(nodes, stNodes[i])
 	}
 	return nodes
 }
 
+// ChaosLevelNodeCount returns the number of storage driver nodes picked by the
+// trigger at its current chaos level
+func ChaosLevelNodeCount(triggerType string) int {
+	return chaosLevelNodeCount(ChaosMap[triggerType], len(node.GetStorageDriverNodes()))
+}
+
+// chaosLevelNodeCount returns the number of nodes out of stNodesLen to disrupt at
+// the chaos level: one node at level 10, then from 20% at level 9 up to all
+// nodes at level 1
+func chaosLevelNodeCount(chaosLevel, stNodesLen int) int {
+	switch {
+	case chaosLevel == 10 && stNodesLen > 0:
+		return 1
+	case chaosLevel >= 2 && chaosLevel <= 9:
+		return int(float32(stNodesLen) * float32(11-chaosLevel) / 10)
+	case chaosLevel == 1:
+		return stNodesLen
+	}
+	return 0
+}
+
 // TriggerCrashNodes crashes Worker nodes
 func TriggerCrashNodes(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CrashNode)
 	startLongevityTest(CrashNode)
 	event := &EventRecord{
 		Event: Event{
@@ -2976,7 +3215,7 @@ func TriggerCrashNodes(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	stepLog := "get all nodes and crash one by one"
@@ -2996,6 +3235,7 @@ func TriggerCrashNodes(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 						UpdateOutcome(event, err)
 						continue
 					}
+					event.AddTargetNode(n.Name)
 					stepLog = fmt.Sprintf("crash node: %s", n.Name)
 					Step(stepLog, func() {
 						log.InfoD(stepLog)
@@ -3043,7 +3283,7 @@ func TriggerCrashNodes(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 // TriggerVolumeClone clones all volumes, validates and destroys the clone
 func TriggerVolumeClone(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(VolumeClone)
 	startLongevityTest(VolumeClone)
 	event := &EventRecord{
 		Event: Event{
@@ -3054,7 +3294,7 @@ func TriggerVolumeClone(contexts *[]*scheduler.Context, recordChan *chan *EventR
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -3076,6 +3316,7 @@ func TriggerVolumeClone(contexts *[]*scheduler.Context, recordChan *chan *EventR
 			})
 			for _, vol := range appVolumes {
 				var clonedVolID string
+				event.AddTargetVolume(vol.ID)
 
 				// Skip clone trigger for Pure FB volumes
 				isPureFileVol, err := Inst().V.IsPureFileVolume(vol)
@@ -3127,7 +3368,7 @@ func TriggerVolumeClone(contexts *[]*scheduler.Context, recordChan *chan *EventR
 // TriggerVolumeResize increases all volumes size and validates app
 func TriggerVolumeResize(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(VolumeResize)
 	startLongevityTest(VolumeResize)
 	event := &EventRecord{
 		Event: Event{
@@ -3139,7 +3380,7 @@ func TriggerVolumeResize(contexts *[]*scheduler.Context, recordChan *chan *Event
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -3186,6 +3427,9 @@ func TriggerVolumeResize(contexts *[]*scheduler.Context, recordChan *chan *Event
 							continue
 						}
 						requestedVols = append(requestedVols, resizedVol)
+						if resizedVol != nil {
+							event.AddTargetVolume(resizedVol.ID)
+						}
 					}
 
 				})
@@ -3219,7 +3463,7 @@ func TriggerVolumeResize(contexts *[]*scheduler.Context, recordChan *chan *Event
 // TriggerLocalSnapShot takes local snapshots of the volumes and validates snapshot
 func TriggerLocalSnapShot(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(LocalSnapShot)
 	startLongevityTest(LocalSnapShot)
 	uuid := GenerateUUID()
 	event := &EventRecord{
@@ -3232,7 +3476,7 @@ func TriggerLocalSnapShot(contexts *[]*scheduler.Context, recordChan *chan *Even
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -3297,6 +3541,7 @@ func TriggerLocalSnapShot(contexts *[]*scheduler.Context, recordChan *chan *Even
 				snapshotScheduleRetryTimeout := 3 * time.Minute
 
 				for _, v := range appVolumes {
+					event.AddTargetVolume(v.ID)
 					snapshotScheduleName := v.Name + "-interval-schedule"
 					log.InfoD("snapshotScheduleName : %v for volume: %s", snapshotScheduleName, v.Name)
 					snapStatuses, err := storkops.Instance().ValidateSnapshotSchedule(snapshotScheduleName,
@@ -3354,7 +3599,7 @@ func TriggerLocalSnapShot(contexts *[]*scheduler.Context, recordChan *chan *Even
 // TriggerDeleteLocalSnapShot deletes local snapshots and snapshot schedules
 func TriggerDeleteLocalSnapShot(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(DeleteLocalSnapShot)
 	startLongevityTest(DeleteLocalSnapShot)
 	uuid := GenerateUUID()
 	event := &EventRecord{
@@ -3367,7 +3612,7 @@ func TriggerDeleteLocalSnapShot(contexts *[]*scheduler.Context, recordChan *chan
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -3399,6 +3644,7 @@ func TriggerDeleteLocalSnapShot(contexts *[]*scheduler.Context, recordChan *chan
 					})
 
 					for _, v := range appVolumes {
+						event.AddTargetVolume(v.ID)
 						snapshotScheduleName := v.Name + "-interval-schedule"
 						log.InfoD("snapshotScheduleName : %v for volume: %s", snapshotScheduleName, v.Name)
 						snapStatuses, err := storkops.Instance().ValidateSnapshotSchedule(snapshotScheduleName,
@@ -3449,7 +3695,7 @@ func TriggerDeleteLocalSnapShot(contexts *[]*scheduler.Context, recordChan *chan
 
 func TriggerLocalSnapshotRestore(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(LocalSnapShotRestore)
 	startLongevityTest(LocalSnapShotRestore)
 	uuid := GenerateUUID()
 	event := &EventRecord{
@@ -3462,7 +3708,7 @@ func TriggerLocalSnapshotRestore(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -3483,6 +3729,7 @@ func TriggerLocalSnapshotRestore(contexts *[]*scheduler.Context, recordChan *cha
 			})
 			log.Infof("Got volume count : %v", len(appVolumes))
 			for _, v := range appVolumes {
+				event.AddTargetVolume(v.ID)
 				snapshotScheduleName := v.Name + "-interval-schedule"
 				log.InfoD("snapshotScheduleName : %v for volume: %s", snapshotScheduleName, v.Name)
 				var volumeSnapshotStatus *storkv1.ScheduledVolumeSnapshotStatus
@@ -3553,7 +3800,7 @@ func TriggerLocalSnapshotRestore(contexts *[]*scheduler.Context, recordChan *cha
 // TriggerCloudSnapShot deploy Interval Policy and validates snapshot
 func TriggerCloudSnapShot(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CloudSnapShot)
 	startLongevityTest(CloudSnapShot)
 	uuid := GenerateUUID()
 	event := &EventRecord{
@@ -3566,7 +3813,7 @@ func TriggerCloudSnapShot(contexts *[]*scheduler.Context, recordChan *chan *Even
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -3667,6 +3914,7 @@ func TriggerCloudSnapShot(contexts *[]*scheduler.Context, recordChan *chan *Even
 							)
 							continue
 						}
+						event.AddTargetVolume(v.ID)
 						snapshotScheduleName := v.Name + "-interval-schedule"
 						log.InfoD("snapshotScheduleName : %v for volume: %s", snapshotScheduleName, v.Name)
 						var volumeSnapshotStatus *storkv1.ScheduledVolumeSnapshotStatus
@@ -3740,7 +3988,7 @@ func TriggerCloudSnapShot(contexts *[]*scheduler.Context, recordChan *chan *Even
 // TriggerDeleteCloudsnaps delete cloud snaps
 func TriggerDeleteCloudsnaps(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(DeleteCloudsnaps)
 	startLongevityTest(DeleteCloudsnaps)
 	event := &EventRecord{
 		Event: Event{
@@ -3751,7 +3999,7 @@ func TriggerDeleteCloudsnaps(contexts *[]*scheduler.Context, recordChan *chan *E
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -3766,6 +4014,7 @@ func TriggerDeleteCloudsnaps(contexts *[]*scheduler.Context, recordChan *chan *E
 			UpdateOutcome(event, err)
 			for vol, params := range vols {
 				inspectedVol, err := Inst().V.InspectVolume(vol)
+				event.AddTargetVolume(vol)
 				UpdateOutcome(event, err)
 				csBksps, err := Inst().V.GetCloudsnapsOfGivenVolume(vol, inspectedVol.Id, params)
 				UpdateOutcome(event, err)
@@ -3812,7 +4061,7 @@ func TriggerDeleteCloudsnaps(contexts *[]*scheduler.Context, recordChan *chan *E
 func TriggerCloudSnapshotRestore(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CloudSnapShotRestore)
 	startLongevityTest(CloudSnapShotRestore)
 	uuid := GenerateUUID()
 	event := &EventRecord{
@@ -3825,7 +4074,7 @@ func TriggerCloudSnapshotRestore(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -3853,6 +4102,7 @@ func TriggerCloudSnapshotRestore(contexts *[]*scheduler.Context, recordChan *cha
 				UpdateOutcome(event, err)
 
 				for _, vol := range vols {
+					event.AddTargetVolume(vol.ID)
 					var snapshotScheduleName string
 					for _, snap := range snapSchedList.Items {
 						snapshotScheduleName = snap.Name
@@ -3918,7 +4168,7 @@ func TriggerCloudSnapshotRestore(contexts *[]*scheduler.Context, recordChan *cha
 // TriggerVolumeDelete delete the volumes
 func TriggerVolumeDelete(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(VolumesDelete)
 	startLongevityTest(VolumesDelete)
 	uuid := GenerateUUID()
 	event := &EventRecord{
@@ -3931,7 +4181,7 @@ func TriggerVolumeDelete(contexts *[]*scheduler.Context, recordChan *chan *Event
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -3953,6 +4203,9 @@ func TriggerVolumeDelete(contexts *[]*scheduler.Context, recordChan *chan *Event
 				log.Errorf("error deleting volumes for ctx [%s], Err: %v", ctx.App.Key, err)
 				UpdateOutcome(event, err)
 			}
+			for _, v := range vols {
+				event.AddTargetVolume(v.ID)
+			}
 
 			// Tear down application
 			stepLog = fmt.Sprintf("start destroying %s app", ctx.App.Key)
@@ -4158,11 +4411,66 @@ func getCreateCloudCredentialRequest(uid string) (*api.CloudCredentialCreateRequ
 func CollectEventRecords(recordChan *chan *EventRecord) {
 	eventRing = ring.New(100)
 	for eventRecord := range *recordChan {
-		eventRing.Value = eventRecord
-		actualEvent := strings.Split(eventRecord.Event.Type, "<br>")[0]
-		TestExecutionCounter.Increment(actualEvent)
-		log.Infof("TestExecutionCountMap: %v", TestExecutionCounter.String())
-		eventRing = eventRing.Next()
+		RecordEvent(eventRecord)
+	}
+}
+
+// RecordEvent records the event of a trigger execution: it counts the execution, journals
+// the event and ends the disruption window of the trigger. Runners draining the records of
+// the triggers themselves, rather than through CollectEventRecords, call it for each record.
+func RecordEvent(eventRecord *EventRecord) {
+	eventRing.Value = eventRecord
+	actualEvent := strings.Split(eventRecord.Event.Type, "<br>")[0]
+	TestExecutionCounter.Increment(actualEvent)
+	log.Infof("TestExecutionCountMap: %v", TestExecutionCounter.String())
+	eventRing = eventRing.Next()
+	journalEventRecord(eventRecord)
+	Invariants.EndWindow(actualEvent)
+	observeTriggerDuration(eventRecord, actualEvent)
+}
+
+// observeTriggerDuration adds the duration of the trigger execution to the trigger duration metric
+func observeTriggerDuration(eventRecord *EventRecord, trigger string) {
+	if eventRecord.StartTime.IsZero() || eventRecord.EndTime.IsZero() {
+		return
+	}
+	Inst().M.ObserveSummaryMetric(TriggerDuration, eventRecord.EndTime.Sub(eventRecord.StartTime).Seconds(), trigger)
+}
+
+// journalEventRecord appends the event record to the event journal, if one is configured.
+// It must run right after the record is received, while the trigger execution is current.
+func journalEventRecord(eventRecord *EventRecord) {
+	trigger := strings.Split(eventRecord.Event.Type, "<br>")[0]
+	if eventRecord.Diverged {
+		log.Warnf("Trigger [%s] execution [%d] diverged from the replayed run", trigger, eventRecord.Execution)
+	}
+	if Inst().EventJournal == nil {
+		return
+	}
+	entry := eventjournal.Entry{
+		RunID:     Inst().InstanceID,
+		EventID:   eventRecord.Event.ID,
+		Trigger:   trigger,
+		Nodes:     eventRecord.Nodes,
+		Volumes:   eventRecord.Volumes,
+		Seed:      TriggerRand.Seed(),
+		Execution: eventRecord.Execution,
+		Draws:     eventRecord.Draws,
+		Cluster:   eventRecord.Cluster,
+		Start:     eventRecord.StartTime,
+		End:       eventRecord.EndTime,
+	}
+	// Causes are only set through UpdateOutcome, fall back to the outcome for
+	// errors appended directly
+	causes := eventRecord.Causes
+	if len(causes) < len(eventRecord.Outcome) {
+		causes = eventRecord.Outcome
+	}
+	for _, err := range causes {
+		entry.Outcomes = append(entry.Outcomes, eventjournal.NewOutcome(err))
+	}
+	if err := Inst().EventJournal.Write(entry); err != nil {
+		log.Errorf("Failed to journal event [%s]. Err: %v", eventRecord.Event.ID, err)
 	}
 }
 
@@ -4271,6 +4579,8 @@ func TriggerEmailReporter() {
 // TriggerBackupApps takes backups of all namespaces of deployed apps
 func TriggerBackupApps(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupAllApps)
+	startLongevityTest(BackupAllApps)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4281,7 +4591,7 @@ func TriggerBackupApps(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -4353,6 +4663,8 @@ func TriggerBackupApps(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 // TriggerScheduledBackupAll creates scheduled backup if it doesn't exist and makes sure backups are correct otherwise
 func TriggerScheduledBackupAll(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupScheduleAll)
+	startLongevityTest(BackupScheduleAll)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4363,7 +4675,7 @@ func TriggerScheduledBackupAll(contexts *[]*scheduler.Context, recordChan *chan
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -4430,6 +4742,8 @@ func TriggerScheduledBackupAll(contexts *[]*scheduler.Context, recordChan *chan
 // Creates config maps in the specified namespaces and backups up only these config maps
 func TriggerBackupSpecificResource(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupSpecificResource)
+	startLongevityTest(BackupSpecificResource)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4489,7 +4803,7 @@ func TriggerBackupSpecificResource(contexts *[]*scheduler.Context, recordChan *c
 				}
 			}
 		})
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	bkpNames := make([]string, 0)
@@ -4569,6 +4883,8 @@ func TriggerBackupSpecificResource(contexts *[]*scheduler.Context, recordChan *c
 // TriggerInspectBackup inspects backup and checks for errors
 func TriggerInspectBackup(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(TestInspectBackup)
+	startLongevityTest(TestInspectBackup)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4579,7 +4895,7 @@ func TriggerInspectBackup(contexts *[]*scheduler.Context, recordChan *chan *Even
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -4614,6 +4930,8 @@ func TriggerInspectBackup(contexts *[]*scheduler.Context, recordChan *chan *Even
 // TriggerInspectRestore inspects restore and checks for errors
 func TriggerInspectRestore(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(TestInspectRestore)
+	startLongevityTest(TestInspectRestore)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4624,7 +4942,7 @@ func TriggerInspectRestore(contexts *[]*scheduler.Context, recordChan *chan *Eve
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -4657,6 +4975,8 @@ func TriggerInspectRestore(contexts *[]*scheduler.Context, recordChan *chan *Eve
 // TriggerRestoreNamespace restores a namespace to a new namespace
 func TriggerRestoreNamespace(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(RestoreNamespace)
+	startLongevityTest(RestoreNamespace)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4672,7 +4992,7 @@ func TriggerRestoreNamespace(contexts *[]*scheduler.Context, recordChan *chan *E
 		sourceClusterConfigPath, err := GetSourceClusterConfigPath()
 		UpdateOutcome(event, err)
 		SetClusterContext(sourceClusterConfigPath)
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -4765,6 +5085,8 @@ func TriggerRestoreNamespace(contexts *[]*scheduler.Context, recordChan *chan *E
 // TriggerDeleteBackup deletes a backup
 func TriggerDeleteBackup(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(TestDeleteBackup)
+	startLongevityTest(TestDeleteBackup)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4775,7 +5097,7 @@ func TriggerDeleteBackup(contexts *[]*scheduler.Context, recordChan *chan *Event
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -4805,6 +5127,8 @@ func TriggerDeleteBackup(contexts *[]*scheduler.Context, recordChan *chan *Event
 // TriggerBackupSpecificResourceOnCluster backs up all PVCs on the source cluster
 func TriggerBackupSpecificResourceOnCluster(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupSpecificResourceOnCluster)
+	startLongevityTest(BackupSpecificResourceOnCluster)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4815,7 +5139,7 @@ func TriggerBackupSpecificResourceOnCluster(contexts *[]*scheduler.Context, reco
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -4907,6 +5231,8 @@ func TriggerBackupSpecificResourceOnCluster(contexts *[]*scheduler.Context, reco
 // TriggerBackupByLabel gives a label to random resources on the cluster and tries to back up only resources with that label
 func TriggerBackupByLabel(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupUsingLabelOnCluster)
+	startLongevityTest(BackupUsingLabelOnCluster)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -4951,7 +5277,7 @@ func TriggerBackupByLabel(contexts *[]*scheduler.Context, recordChan *chan *Even
 				}
 			}
 		})
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -4985,7 +5311,7 @@ func TriggerBackupByLabel(contexts *[]*scheduler.Context, recordChan *chan *Even
 				UpdateOutcome(event, err)
 				if err == nil {
 					// Randomly choose some pvcs to add labels to for backup
-					dice := rand.Intn(4)
+					dice := triggerRand(event.Event.Type).Intn(4)
 					if dice == 1 {
 						err = AddLabelToResource(pvcPointer, labelKey, labelValue)
 						UpdateOutcome(event, err)
@@ -5003,7 +5329,7 @@ func TriggerBackupByLabel(contexts *[]*scheduler.Context, recordChan *chan *Even
 				UpdateOutcome(event, err)
 				if err == nil {
 					// Randomly choose some configmaps to add labels to for backup
-					dice := rand.Intn(4)
+					dice := triggerRand(event.Event.Type).Intn(4)
 					if dice == 1 {
 						err = AddLabelToResource(cmPointer, labelKey, labelValue)
 						UpdateOutcome(event, err)
@@ -5021,7 +5347,7 @@ func TriggerBackupByLabel(contexts *[]*scheduler.Context, recordChan *chan *Even
 				UpdateOutcome(event, err)
 				if err == nil {
 					// Randomly choose some secrets to add labels to for backup
-					dice := rand.Intn(4)
+					dice := triggerRand(event.Event.Type).Intn(4)
 					if dice == 1 {
 						err = AddLabelToResource(secretPointer, labelKey, labelValue)
 						UpdateOutcome(event, err)
@@ -5087,6 +5413,8 @@ func TriggerBackupByLabel(contexts *[]*scheduler.Context, recordChan *chan *Even
 // TriggerScheduledBackupScale creates a scheduled backup and checks that scaling an app is reflected
 func TriggerScheduledBackupScale(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupScheduleScale)
+	startLongevityTest(BackupScheduleScale)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -5097,7 +5425,7 @@ func TriggerScheduledBackupScale(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -5241,6 +5569,8 @@ func TriggerScheduledBackupScale(contexts *[]*scheduler.Context, recordChan *cha
 // TriggerBackupRestartPX backs up an application and restarts Portworx during the backup
 func TriggerBackupRestartPX(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupRestartPortworx)
+	startLongevityTest(BackupRestartPortworx)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -5251,7 +5581,7 @@ func TriggerBackupRestartPX(contexts *[]*scheduler.Context, recordChan *chan *Ev
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -5271,7 +5601,7 @@ func TriggerBackupRestartPX(contexts *[]*scheduler.Context, recordChan *chan *Ev
 		namespace := ctx.GetID()
 		bkpNamespaces = append(bkpNamespaces, namespace)
 	}
-	nsIndex := rand.Intn(len(bkpNamespaces))
+	nsIndex := triggerRand(event.Event.Type).Intn(len(bkpNamespaces))
 	backupName := fmt.Sprintf("%s-%s-%d", BackupNamePrefix, bkpNamespaces[nsIndex], backupCounter)
 	bkpError := false
 	Step("Backup a single namespace", func() {
@@ -5289,7 +5619,8 @@ func TriggerBackupRestartPX(contexts *[]*scheduler.Context, recordChan *chan *Ev
 
 	Step("Restart Portworx", func() {
 		nodes := node.GetStorageDriverNodes()
-		nodeIndex := rand.Intn(len(nodes))
+		nodeIndex := triggerRand(event.Event.Type).Intn(len(nodes))
+		event.AddTargetNode(nodes[nodeIndex].Name)
 		log.Infof("Stop volume driver [%s] on node: [%s]", Inst().V.String(), nodes[nodeIndex].Name)
 		StopVolDriverAndWait([]node.Node{nodes[nodeIndex]})
 		log.Infof("Starting volume driver [%s] on node [%s]", Inst().V.String(), nodes[nodeIndex].Name)
@@ -5328,6 +5659,8 @@ func TriggerBackupRestartPX(contexts *[]*scheduler.Context, recordChan *chan *Ev
 // TriggerBackupRestartNode backs up an application and restarts a node with Portworx during the backup
 func TriggerBackupRestartNode(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupRestartNode)
+	startLongevityTest(BackupRestartNode)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -5338,7 +5671,7 @@ func TriggerBackupRestartNode(contexts *[]*scheduler.Context, recordChan *chan *
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -5359,7 +5692,7 @@ func TriggerBackupRestartNode(contexts *[]*scheduler.Context, recordChan *chan *
 		bkpNamespaces = append(bkpNamespaces, namespace)
 	}
 	// Choose a random namespace to back up
-	nsIndex := rand.Intn(len(bkpNamespaces))
+	nsIndex := triggerRand(event.Event.Type).Intn(len(bkpNamespaces))
 	backupName := fmt.Sprintf("%s-%s-%d", BackupNamePrefix, bkpNamespaces[nsIndex], backupCounter)
 	bkpError := false
 	Step("Backup a single namespace", func() {
@@ -5378,7 +5711,8 @@ func TriggerBackupRestartNode(contexts *[]*scheduler.Context, recordChan *chan *
 	Step("Restart a Portworx node", func() {
 		nodes := node.GetStorageDriverNodes()
 		// Choose a random node to reboot
-		nodeIndex := rand.Intn(len(nodes))
+		nodeIndex := triggerRand(event.Event.Type).Intn(len(nodes))
+		event.AddTargetNode(nodes[nodeIndex].Name)
 		Step(fmt.Sprintf("reboot node: %s", nodes[nodeIndex].Name), func() {
 			err := Inst().N.RebootNode(nodes[nodeIndex], node.RebootNodeOpts{
 				Force: true,
@@ -5470,6 +5804,8 @@ func TriggerBackupRestartNode(contexts *[]*scheduler.Context, recordChan *chan *
 // TriggerBackupDeleteBackupPod backs up an application and restarts px-backup pod during the backup
 func TriggerBackupDeleteBackupPod(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupDeleteBackupPod)
+	startLongevityTest(BackupDeleteBackupPod)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -5480,7 +5816,7 @@ func TriggerBackupDeleteBackupPod(contexts *[]*scheduler.Context, recordChan *ch
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -5553,6 +5889,8 @@ func TriggerBackupDeleteBackupPod(contexts *[]*scheduler.Context, recordChan *ch
 // TriggerBackupScaleMongo backs up an application and scales down Mongo pod during the backup
 func TriggerBackupScaleMongo(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(BackupScaleMongo)
+	startLongevityTest(BackupScaleMongo)
 	event := &EventRecord{
 		Event: Event{
 			ID:   GenerateUUID(),
@@ -5563,7 +5901,7 @@ func TriggerBackupScaleMongo(contexts *[]*scheduler.Context, recordChan *chan *E
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -5883,6 +6221,7 @@ func initiatePoolExpansion(event *EventRecord, wg *sync.WaitGroup, pool *opsapi.
 			UpdateOutcome(event, err)
 			return
 		}
+		event.AddTargetNode(pNode.Name)
 		if isDmthin && resizeOperationType == opsapi.SdkStoragePool_RESIZE_TYPE_ADD_DISK {
 			err = EnterPoolMaintenance(*pNode)
 			if err != nil {
@@ -5959,7 +6298,7 @@ func initiatePoolExpansion(event *EventRecord, wg *sync.WaitGroup, pool *opsapi.
 // TriggerMetadataPoolResizeDisk performs resize-disk on the storage pools for the given contexts
 func TriggerMetadataPoolResizeDisk(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(MetadataPoolResizeDisk)
 	startLongevityTest(MetadataPoolResizeDisk)
 	event := &EventRecord{
 		Event: Event{
@@ -5971,7 +6310,7 @@ func TriggerMetadataPoolResizeDisk(contexts *[]*scheduler.Context, recordChan *c
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6006,7 +6345,7 @@ func TriggerMetadataPoolResizeDisk(contexts *[]*scheduler.Context, recordChan *c
 // TriggerPoolResizeDiskAndReboot performs resize-disk on a storage pool and reboots the node
 func TriggerPoolResizeDiskAndReboot(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(ResizeDiskAndReboot)
 	startLongevityTest(ResizeDiskAndReboot)
 	event := &EventRecord{
 		Event: Event{
@@ -6018,7 +6357,7 @@ func TriggerPoolResizeDiskAndReboot(contexts *[]*scheduler.Context, recordChan *
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6038,7 +6377,7 @@ func TriggerPoolResizeDiskAndReboot(contexts *[]*scheduler.Context, recordChan *
 		var poolToBeResized *opsapi.StoragePool
 		nodeContexts := make([]*scheduler.Context, 0)
 		if len(poolsToBeResized) > 0 {
-			poolToBeResized = poolsToBeResized[rand.Intn(len(poolsToBeResized))]
+			poolToBeResized = poolsToBeResized[triggerRand(event.Event.Type).Intn(len(poolsToBeResized))]
 			log.InfoD("Pool to resize-disk [%v]", poolToBeResized)
 			storageNode, err := GetNodeWithGivenPoolID(poolToBeResized.Uuid)
 			UpdateOutcome(event, err)
@@ -6064,7 +6403,7 @@ func TriggerPoolResizeDiskAndReboot(contexts *[]*scheduler.Context, recordChan *
 // TriggerPoolAddDisk peforms add-disk on the storage pools for the given contexts
 func TriggerPoolAddDisk(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(PoolAddDisk)
 	startLongevityTest(PoolAddDisk)
 	event := &EventRecord{
 		Event: Event{
@@ -6076,7 +6415,7 @@ func TriggerPoolAddDisk(contexts *[]*scheduler.Context, recordChan *chan *EventR
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6111,7 +6450,7 @@ func TriggerPoolAddDisk(contexts *[]*scheduler.Context, recordChan *chan *EventR
 // TriggerPoolAddDiskAndReboot performs add-disk and reboots the node
 func TriggerPoolAddDiskAndReboot(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(AddDiskAndReboot)
 	startLongevityTest(AddDiskAndReboot)
 	event := &EventRecord{
 		Event: Event{
@@ -6123,7 +6462,7 @@ func TriggerPoolAddDiskAndReboot(contexts *[]*scheduler.Context, recordChan *cha
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6141,7 +6480,7 @@ func TriggerPoolAddDiskAndReboot(contexts *[]*scheduler.Context, recordChan *cha
 		}
 		var poolToBeResized *opsapi.StoragePool
 		if len(poolsToBeResized) > 0 {
-			poolToBeResized = poolsToBeResized[rand.Intn(len(poolsToBeResized))]
+			poolToBeResized = poolsToBeResized[triggerRand(event.Event.Type).Intn(len(poolsToBeResized))]
 			storageNode, err := GetNodeWithGivenPoolID(poolToBeResized.Uuid)
 			UpdateOutcome(event, err)
 			nodeContexts, err := GetContextsOnNode(contexts, storageNode)
@@ -6160,7 +6499,7 @@ func TriggerPoolAddDiskAndReboot(contexts *[]*scheduler.Context, recordChan *cha
 
 func TriggerAutopilotPoolRebalance(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(AutopilotRebalance)
 	startLongevityTest(AutopilotRebalance)
 	event := &EventRecord{
 		Event: Event{
@@ -6172,7 +6511,7 @@ func TriggerAutopilotPoolRebalance(contexts *[]*scheduler.Context, recordChan *c
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6223,7 +6562,7 @@ func TriggerAutopilotPoolRebalance(contexts *[]*scheduler.Context, recordChan *c
 // TriggerUpgradeVolumeDriver upgrades volume driver version to the latest build
 func TriggerUpgradeVolumeDriver(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(UpgradeVolumeDriver)
 	startLongevityTest(UpgradeVolumeDriver)
 	event := &EventRecord{
 		Event: Event{
@@ -6234,7 +6573,7 @@ func TriggerUpgradeVolumeDriver(contexts *[]*scheduler.Context, recordChan *chan
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6281,7 +6620,7 @@ func TriggerUpgradeVolumeDriver(contexts *[]*scheduler.Context, recordChan *chan
 // TriggerUpdateCluster upgrades the cluster and ensures everything is running fine
 func TriggerUpdateCluster(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(UpgradeCluster)
 	startLongevityTest(UpgradeCluster)
 	event := &EventRecord{
 		Event: Event{
@@ -6292,7 +6631,7 @@ func TriggerUpdateCluster(contexts *[]*scheduler.Context, recordChan *chan *Even
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -6434,7 +6773,7 @@ func TriggerUpdateCluster(contexts *[]*scheduler.Context, recordChan *chan *Even
 // TriggerUpgradeVolumeDriverFromCatalog performs upgrade hops of volume driver based on a given list of upgradeEndpoints from marketplace
 func TriggerUpgradeVolumeDriverFromCatalog(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(UpgradeVolumeDriverFromCatalog)
 	startLongevityTest(UpgradeVolumeDriverFromCatalog)
 	event := &EventRecord{
 		Event: Event{
@@ -6445,7 +6784,7 @@ func TriggerUpgradeVolumeDriverFromCatalog(contexts *[]*scheduler.Context, recor
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -6615,7 +6954,7 @@ func TriggerUpgradeVolumeDriverFromCatalog(contexts *[]*scheduler.Context, recor
 // TriggerUpgradeStork performs upgrade of the stork
 func TriggerUpgradeStork(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(UpgradeStork)
 	startLongevityTest(UpgradeStork)
 	event := &EventRecord{
 		Event: Event{
@@ -6627,7 +6966,7 @@ func TriggerUpgradeStork(contexts *[]*scheduler.Context, recordChan *chan *Event
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6654,7 +6993,7 @@ func TriggerUpgradeStork(contexts *[]*scheduler.Context, recordChan *chan *Event
 // TriggerAutoFsTrim enables Auto Fstrim in the PX Cluster
 func TriggerAutoFsTrim(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(AutoFsTrim)
 	startLongevityTest(AutoFsTrim)
 	event := &EventRecord{
 		Event: Event{
@@ -6666,7 +7005,7 @@ func TriggerAutoFsTrim(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6712,8 +7051,10 @@ func TriggerAutoFsTrim(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 						vols, _ := Inst().S.GetVolumes(ctx)
 						for _, vol := range vols {
 
+							event.AddTargetVolume(vol.ID)
 							n, err := Inst().V.GetNodeForVolume(vol, 1*time.Minute, 5*time.Second)
 							UpdateOutcome(event, err)
+							event.AddTargetNode(n.Name)
 							log.InfoD("volume %s is attached on node %s [%s]", vol.ID, n.SchedulerNodeName, n.Addresses[0])
 							err = Inst().S.DisableSchedulingOnNode(*n)
 							UpdateOutcome(event, err)
@@ -6761,7 +7102,7 @@ func TriggerAutoFsTrim(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 // TriggerVolumeUpdate enables to test volume update
 func TriggerVolumeUpdate(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(UpdateVolume)
 	startLongevityTest(UpdateVolume)
 	event := &EventRecord{
 		Event: Event{
@@ -6772,7 +7113,7 @@ func TriggerVolumeUpdate(contexts *[]*scheduler.Context, recordChan *chan *Event
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -6794,7 +7135,7 @@ func TriggerVolumeUpdate(contexts *[]*scheduler.Context, recordChan *chan *Event
 // TriggerPowerOffAllVMs power off all the Vms
 func TriggerPowerOffAllVMs(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(PowerOffAllVMs)
 	startLongevityTest(PowerOffAllVMs)
 	event := &EventRecord{
 		Event: Event{
@@ -6805,7 +7146,7 @@ func TriggerPowerOffAllVMs(contexts *[]*scheduler.Context, recordChan *chan *Eve
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -6813,6 +7154,9 @@ func TriggerPowerOffAllVMs(contexts *[]*scheduler.Context, recordChan *chan *Eve
 	Step(stepLog, func() {
 		log.Infof(stepLog)
 		workerNodes := node.GetWorkerNodes()
+		for _, n := range workerNodes {
+			event.AddTargetNode(n.Name)
+		}
 		numberOfThread := 5
 		var numberOfNodePerThread int
 		// If number of VMs to restarted is less than  numberOfThread then
@@ -6912,7 +7256,7 @@ func TriggerPowerOffAllVMs(contexts *[]*scheduler.Context, recordChan *chan *Eve
 // TriggerVolumeIOProfileUpdate enables to test volume update
 func TriggerVolumeIOProfileUpdate(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(UpdateIOProfile)
 	startLongevityTest(UpdateIOProfile)
 	event := &EventRecord{
 		Event: Event{
@@ -6923,7 +7267,7 @@ func TriggerVolumeIOProfileUpdate(contexts *[]*scheduler.Context, recordChan *ch
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -7046,6 +7390,7 @@ func updateIOProfile(event *EventRecord, pvcProfileMap map[string]VolumeIOProfil
 	//Get all volumes and change IO profile on those volumes.
 	var volumeSpec *apios.VolumeSpecUpdate
 	for pvcName, v := range pvcProfileMap {
+		event.AddTargetVolume(pvcName)
 		log.InfoD("Getting info from volume: %s", pvcName)
 		appVol, err := Inst().V.InspectVolume(pvcName)
 		if err != nil {
@@ -7098,6 +7443,7 @@ func updateIOPriorityOnVolumes(contexts *[]*scheduler.Context, event *EventRecor
 			UpdateOutcome(event, fmt.Errorf("found no volumes for app "))
 		}
 		for _, v := range appVolumes {
+			event.AddTargetVolume(v.ID)
 			log.InfoD("Getting info from volume: %s", v.ID)
 			appVol, err := Inst().V.InspectVolume(v.ID)
 			if err != nil {
@@ -7249,7 +7595,7 @@ func waitForFsTrimStatus(event *EventRecord, attachedNode, volumeID string) (ops
 // TriggerTrashcan enables trashcan feature in the PX Cluster and validates it
 func TriggerTrashcan(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(Trashcan)
 	startLongevityTest(Trashcan)
 	event := &EventRecord{
 		Event: Event{
@@ -7261,7 +7607,7 @@ func TriggerTrashcan(contexts *[]*scheduler.Context, recordChan *chan *EventReco
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -7341,7 +7687,7 @@ func TriggerTrashcan(contexts *[]*scheduler.Context, recordChan *chan *EventReco
 // TriggerRelaxedReclaim enables Relaxed Reclaim in the PX Cluster
 func TriggerRelaxedReclaim(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(RelaxedReclaim)
 	startLongevityTest(RelaxedReclaim)
 	event := &EventRecord{
 		Event: Event{
@@ -7353,7 +7699,7 @@ func TriggerRelaxedReclaim(contexts *[]*scheduler.Context, recordChan *chan *Eve
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -7422,7 +7768,7 @@ func TriggerRelaxedReclaim(contexts *[]*scheduler.Context, recordChan *chan *Eve
 // TriggerNodeDecommission decommission the node for the PX cluster
 func TriggerNodeDecommission(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(NodeDecommission)
 	startLongevityTest(NodeDecommission)
 	event := &EventRecord{
 		Event: Event{
@@ -7434,7 +7780,7 @@ func TriggerNodeDecommission(contexts *[]*scheduler.Context, recordChan *chan *E
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -7446,8 +7792,9 @@ func TriggerNodeDecommission(contexts *[]*scheduler.Context, recordChan *chan *E
 	Step(stepLog, func() {
 		log.InfoD(stepLog)
 		workerNodes = node.GetStorageDriverNodes()
-		index := rand.Intn(len(workerNodes))
+		index := triggerRand(event.Event.Type).Intn(len(workerNodes))
 		nodeToDecomm = workerNodes[index]
+		event.AddTargetNode(nodeToDecomm.Name)
 		stepLog = fmt.Sprintf("decommission node %s", nodeToDecomm.Name)
 		Step(stepLog, func() {
 			log.InfoD(stepLog)
@@ -7534,7 +7881,7 @@ func TriggerNodeDecommission(contexts *[]*scheduler.Context, recordChan *chan *E
 // TriggerNodeRejoin rejoins the decommissioned node
 func TriggerNodeRejoin(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(NodeRejoin)
 	startLongevityTest(NodeRejoin)
 	event := &EventRecord{
 		Event: Event{
@@ -7546,7 +7893,7 @@ func TriggerNodeRejoin(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -7559,6 +7906,7 @@ func TriggerNodeRejoin(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 		log.InfoD(stepLog)
 		if decommissionedNode.Name != "" {
 			decommissionedNodeName = decommissionedNode.Name
+			event.AddTargetNode(decommissionedNode.Name)
 			stepLog = fmt.Sprintf("Rejoin node %s", decommissionedNode.Name)
 			Step(stepLog, func() {
 				log.InfoD(stepLog)
@@ -7662,7 +8010,7 @@ func TriggerNodeRejoin(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 func TriggerCsiSnapShot(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	var err error
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CsiSnapShot)
 	startLongevityTest(CsiSnapShot)
 	uuid := GenerateUUID()
 	event := &EventRecord{
@@ -7675,7 +8023,7 @@ func TriggerCsiSnapShot(contexts *[]*scheduler.Context, recordChan *chan *EventR
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -7745,7 +8093,7 @@ func TriggerCsiSnapShot(contexts *[]*scheduler.Context, recordChan *chan *EventR
 // TriggerCsiSnapRestore create pvc from snapshot and validate the restored PVC
 func TriggerCsiSnapRestore(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CsiSnapRestore)
 	startLongevityTest(CsiSnapRestore)
 	uuid := GenerateUUID()
 	event := &EventRecord{
@@ -7758,7 +8106,7 @@ func TriggerCsiSnapRestore(contexts *[]*scheduler.Context, recordChan *chan *Eve
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -7920,7 +8268,7 @@ func createLongevityJiraIssue(event *EventRecord, err error) {
 // TriggerKVDBFailover performs kvdb failover in cyclic manner
 func TriggerKVDBFailover(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(KVDBFailover)
 	startLongevityTest(KVDBFailover)
 	event := &EventRecord{
 		Event: Event{
@@ -7931,7 +8279,7 @@ func TriggerKVDBFailover(contexts *[]*scheduler.Context, recordChan *chan *Event
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -7981,6 +8329,7 @@ func TriggerKVDBFailover(contexts *[]*scheduler.Context, recordChan *chan *Event
 						continue
 					}
 
+					event.AddTargetNode(kvdbNode.Name)
 					appNodeContexts, err := GetContextsOnNode(contexts, &kvdbNode)
 					nodeContexts = append(nodeContexts, appNodeContexts...)
 					errorChan := make(chan error, errorChannelSize)
@@ -8122,7 +8471,7 @@ func validateKVDBMembers(event *EventRecord, kvdbMembers map[string]*volume.Meta
 // TriggerAppTasksDown performs app scale up and down according to chaos level
 func TriggerAppTasksDown(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(AppTasksDown)
 	startLongevityTest(AppTasksDown)
 	event := &EventRecord{
 		Event: Event{
@@ -8133,7 +8482,7 @@ func TriggerAppTasksDown(contexts *[]*scheduler.Context, recordChan *chan *Event
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -8174,7 +8523,7 @@ func TriggerAppTasksDown(contexts *[]*scheduler.Context, recordChan *chan *Event
 // TriggerValidateDeviceMapperCleanup validate device mapper device cleaned up for FA setup
 func TriggerValidateDeviceMapperCleanup(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(ValidateDeviceMapper)
 	startLongevityTest(ValidateDeviceMapper)
 	event := &EventRecord{
 		Event: Event{
@@ -8185,7 +8534,7 @@ func TriggerValidateDeviceMapperCleanup(contexts *[]*scheduler.Context, recordCh
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -8234,7 +8583,7 @@ func TriggerValidateDeviceMapperCleanup(contexts *[]*scheduler.Context, recordCh
 // TriggerAddDrive performs add drive operation
 func TriggerAddDrive(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(AddDrive)
 	startLongevityTest(AddDrive)
 	event := &EventRecord{
 		Event: Event{
@@ -8246,7 +8595,7 @@ func TriggerAddDrive(contexts *[]*scheduler.Context, recordChan *chan *EventReco
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -8267,6 +8616,7 @@ func TriggerAddDrive(contexts *[]*scheduler.Context, recordChan *chan *EventReco
 		}
 		if err == nil && !isCloudDrive {
 			for _, storageNode := range storageNodes {
+				event.AddTargetNode(storageNode.Name)
 				log.InfoD("Get Block drives to add for node %s", storageNode.Name)
 				blockDrives, err := Inst().N.GetBlockDrives(storageNode, systemOpts)
 				UpdateOutcome(event, err)
@@ -8311,7 +8661,7 @@ func TriggerAddDrive(contexts *[]*scheduler.Context, recordChan *chan *EventReco
 // TriggerPoolDelete performs pool delete operation
 func TriggerPoolDelete(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(PoolDelete)
 	startLongevityTest(PoolDelete)
 	event := &EventRecord{
 		Event: Event{
@@ -8323,7 +8673,7 @@ func TriggerPoolDelete(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -8339,8 +8689,9 @@ func TriggerPoolDelete(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 			var nodeSelected node.Node
 			var nodePools []node.StoragePool
 
-			randomIndex := rand.Intn(len(stNodes))
+			randomIndex := triggerRand(event.Event.Type).Intn(len(stNodes))
 			nodeSelected = stNodes[randomIndex]
+			event.AddTargetNode(nodeSelected.Name)
 			nodePools = nodeSelected.StoragePools
 
 			isjournal, err := IsJournalEnabled()
@@ -8491,7 +8842,7 @@ func TriggerPoolDelete(contexts *[]*scheduler.Context, recordChan *chan *EventRe
 
 // TriggerAsyncDR triggers Async DR
 func TriggerAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(AsyncDR)
 	startLongevityTest(AsyncDR)
 	defer ginkgo.GinkgoRecover()
 	log.Infof("Async DR triggered at: %v", time.Now())
@@ -8505,7 +8856,7 @@ func TriggerAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecor
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -8582,7 +8933,7 @@ func TriggerAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecor
 }
 
 func TriggerMetroDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(MetroDR)
 	startLongevityTest(MetroDR)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Metro DR test triggered at: %v", time.Now())
@@ -8595,7 +8946,7 @@ func TriggerMetroDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecor
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -8695,7 +9046,7 @@ func TriggerMetroDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecor
 
 // TriggerAsyncDRPXRestartSource triggers Async DR with PX restart on source
 func TriggerAsyncDRPXRestartSource(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(AsyncDRPXRestartSource)
 	startLongevityTest(AsyncDRPXRestartSource)
 	defer ginkgo.GinkgoRecover()
 	log.Infof("Async DR PX restart on source trigger triggered at: %v", time.Now())
@@ -8709,7 +9060,7 @@ func TriggerAsyncDRPXRestartSource(contexts *[]*scheduler.Context, recordChan *c
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -8775,7 +9126,8 @@ func TriggerAsyncDRPXRestartSource(contexts *[]*scheduler.Context, recordChan *c
 			allMigrations = append(allMigrations, currMig)
 			Step("Restart Portworx", func() {
 				nodes := node.GetStorageDriverNodes()
-				nodeIndex := rand.Intn(len(nodes))
+				nodeIndex := triggerRand(event.Event.Type).Intn(len(nodes))
+				event.AddTargetNode(nodes[nodeIndex].Name)
 				log.Infof("Stop volume driver [%s] on node: [%s]", Inst().V.String(), nodes[nodeIndex].Name)
 				StopVolDriverAndWait([]node.Node{nodes[nodeIndex]})
 				log.Infof("Starting volume driver [%s] on node [%s]", Inst().V.String(), nodes[nodeIndex].Name)
@@ -8801,7 +9153,7 @@ func TriggerAsyncDRPXRestartSource(contexts *[]*scheduler.Context, recordChan *c
 
 // TriggerAsyncDRPXRestartDest triggers Async DR with PX restart on destination
 func TriggerAsyncDRPXRestartDest(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(AsyncDRPXRestartDest)
 	startLongevityTest(AsyncDRPXRestartDest)
 	defer ginkgo.GinkgoRecover()
 	log.Infof("Async DR PX restart on destination trigger triggered at: %v", time.Now())
@@ -8815,7 +9167,7 @@ func TriggerAsyncDRPXRestartDest(contexts *[]*scheduler.Context, recordChan *cha
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -8887,7 +9239,8 @@ func TriggerAsyncDRPXRestartDest(contexts *[]*scheduler.Context, recordChan *cha
 					return
 				}
 				nodes := node.GetStorageDriverNodes()
-				nodeIndex := rand.Intn(len(nodes))
+				nodeIndex := triggerRand(event.Event.Type).Intn(len(nodes))
+				event.AddTargetNode(nodes[nodeIndex].Name)
 				log.Infof("Stop volume driver [%s] on node: [%s]", Inst().V.String(), nodes[nodeIndex].Name)
 				StopVolDriverAndWait([]node.Node{nodes[nodeIndex]})
 				log.Infof("Starting volume driver [%s] on node [%s]", Inst().V.String(), nodes[nodeIndex].Name)
@@ -8918,7 +9271,7 @@ func TriggerAsyncDRPXRestartDest(contexts *[]*scheduler.Context, recordChan *cha
 
 // TriggerAsyncDRPXRestartKvdb triggers Async DR with kvdb restart
 func TriggerAsyncDRPXRestartKvdb(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(AsyncDRPXRestartKvdb)
 	startLongevityTest(AsyncDRPXRestartKvdb)
 	defer ginkgo.GinkgoRecover()
 	log.Infof("Async DR kvdb restart trigger triggered at: %v", time.Now())
@@ -8932,7 +9285,7 @@ func TriggerAsyncDRPXRestartKvdb(contexts *[]*scheduler.Context, recordChan *cha
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -9000,6 +9353,7 @@ func TriggerAsyncDRPXRestartKvdb(contexts *[]*scheduler.Context, recordChan *cha
 			break
 		}
 	}
+	event.AddTargetNode(appNode.Name)
 
 	log.InfoD("Start migration")
 
@@ -9036,7 +9390,7 @@ func TriggerAsyncDRPXRestartKvdb(contexts *[]*scheduler.Context, recordChan *cha
 
 // TriggerAsyncDRVolumeOnly triggers Async DR
 func TriggerAsyncDRVolumeOnly(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(AsyncDRVolumeOnly)
 	startLongevityTest(AsyncDRVolumeOnly)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Volume Only Async DR triggered at: %v", time.Now())
@@ -9049,7 +9403,7 @@ func TriggerAsyncDRVolumeOnly(contexts *[]*scheduler.Context, recordChan *chan *
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -9140,7 +9494,7 @@ func TriggerAsyncDRVolumeOnly(contexts *[]*scheduler.Context, recordChan *chan *
 }
 
 func TriggerStorkApplicationBackup(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(StorkApplicationBackup)
 	startLongevityTest(StorkApplicationBackup)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Stork Application Backup triggered at: %v", time.Now())
@@ -9153,7 +9507,7 @@ func TriggerStorkApplicationBackup(contexts *[]*scheduler.Context, recordChan *c
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -9223,7 +9577,7 @@ func TriggerStorkApplicationBackup(contexts *[]*scheduler.Context, recordChan *c
 }
 
 func TriggerStorkAppBkpVolResize(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(StorkAppBkpVolResize)
 	startLongevityTest(StorkAppBkpVolResize)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Stork Application Backup with volume resize triggered at: %v", time.Now())
@@ -9236,7 +9590,7 @@ func TriggerStorkAppBkpVolResize(contexts *[]*scheduler.Context, recordChan *cha
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -9305,6 +9659,7 @@ func TriggerStorkAppBkpVolResize(contexts *[]*scheduler.Context, recordChan *cha
 					}
 					log.Info("application backup successful, verify volume resize successful")
 					for _, v := range requestedVols {
+						event.AddTargetVolume(v.ID)
 						params := make(map[string]string)
 						err := Inst().V.ValidateUpdateVolume(v, params)
 						if err != nil {
@@ -9328,7 +9683,7 @@ func TriggerStorkAppBkpVolResize(contexts *[]*scheduler.Context, recordChan *cha
 }
 
 func TriggerStorkAppBkpHaUpdate(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(StorkAppBkpHaUpdate)
 	startLongevityTest(StorkAppBkpHaUpdate)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Stork Application Backup with HA update triggered at: %v", time.Now())
@@ -9341,7 +9696,7 @@ func TriggerStorkAppBkpHaUpdate(contexts *[]*scheduler.Context, recordChan *chan
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -9402,6 +9757,7 @@ func TriggerStorkAppBkpHaUpdate(contexts *[]*scheduler.Context, recordChan *chan
 				if bkp_start_err == nil {
 					failure := false
 					for _, v := range appVolumes {
+						event.AddTargetVolume(v.ID)
 						MaxRF := Inst().V.GetMaxReplicationFactor()
 						log.Infof("Maximum replication factor is: %v\n", MaxRF)
 						currAggr, err := Inst().V.GetAggregationLevel(v)
@@ -9473,7 +9829,7 @@ func TriggerStorkAppBkpHaUpdate(contexts *[]*scheduler.Context, recordChan *chan
 }
 
 func TriggerStorkAppBkpPxRestart(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(StorkAppBkpPxRestart)
 	startLongevityTest(StorkAppBkpPxRestart)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Stork Application Backup with PX restart triggered at: %v", time.Now())
@@ -9486,7 +9842,7 @@ func TriggerStorkAppBkpPxRestart(contexts *[]*scheduler.Context, recordChan *cha
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -9539,7 +9895,8 @@ func TriggerStorkAppBkpPxRestart(contexts *[]*scheduler.Context, recordChan *cha
 				if bkp_start_err == nil {
 					Step("Restart Portworx", func() {
 						nodes := node.GetStorageDriverNodes()
-						nodeIndex := rand.Intn(len(nodes))
+						nodeIndex := triggerRand(event.Event.Type).Intn(len(nodes))
+						event.AddTargetNode(nodes[nodeIndex].Name)
 						log.Infof("Stop volume driver [%s] on node: [%s]", Inst().V.String(), nodes[nodeIndex].Name)
 						StopVolDriverAndWait([]node.Node{nodes[nodeIndex]})
 						log.Infof("Starting volume driver [%s] on node [%s]", Inst().V.String(), nodes[nodeIndex].Name)
@@ -9569,7 +9926,7 @@ func TriggerStorkAppBkpPxRestart(contexts *[]*scheduler.Context, recordChan *cha
 }
 
 func TriggerStorkAppBkpPoolResize(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(StorkAppBkpPoolResize)
 	startLongevityTest(StorkAppBkpPoolResize)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Stork Application Backup with Pool resize triggered at: %v", time.Now())
@@ -9582,7 +9939,7 @@ func TriggerStorkAppBkpPoolResize(contexts *[]*scheduler.Context, recordChan *ch
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -9687,7 +10044,7 @@ func TriggerStorkAppBkpPoolResize(contexts *[]*scheduler.Context, recordChan *ch
 }
 
 func TriggerConfluentAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(ConfluentAsyncDR)
 	startLongevityTest(ConfluentAsyncDR)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Confluent CRD Async DR triggered at: %v", time.Now())
@@ -9700,7 +10057,7 @@ func TriggerConfluentAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *E
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -9750,7 +10107,7 @@ func TriggerConfluentAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *E
 }
 
 func TriggerKafkaAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(KafkaAsyncDR)
 	startLongevityTest(KafkaAsyncDR)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Kafka CRD Async DR triggered at: %v", time.Now())
@@ -9763,7 +10120,7 @@ func TriggerKafkaAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *Event
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -9813,7 +10170,7 @@ func TriggerKafkaAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *Event
 }
 
 func TriggerMongoAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(MongoAsyncDR)
 	startLongevityTest(MongoAsyncDR)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Mongo CRD Async DR triggered at: %v", time.Now())
@@ -9826,7 +10183,7 @@ func TriggerMongoAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *Event
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -9876,7 +10233,7 @@ func TriggerMongoAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *Event
 }
 
 func TriggerAutoFsTrimAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(AutoFsTrimAsyncDR)
 	startLongevityTest(AutoFsTrimAsyncDR)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Async DR with autofstrim volumes triggered at: %v", time.Now())
@@ -9889,7 +10246,7 @@ func TriggerAutoFsTrimAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -10003,7 +10360,7 @@ func TriggerAutoFsTrimAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *
 }
 
 func TriggerIopsBwAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(IopsBwAsyncDR)
 	startLongevityTest(IopsBwAsyncDR)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Async DR with volumes having Iops and BW  triggered at: %v", time.Now())
@@ -10016,7 +10373,7 @@ func TriggerIopsBwAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *Even
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -10137,7 +10494,7 @@ func TriggerIopsBwAsyncDR(contexts *[]*scheduler.Context, recordChan *chan *Even
 }
 
 func TriggerDeleteOldNamespaces(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(DeleteOldNamespaces)
 	startLongevityTest(DeleteOldNamespaces)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("DeleteOldNamespaces triggered at: %v", time.Now())
@@ -10150,7 +10507,7 @@ func TriggerDeleteOldNamespaces(contexts *[]*scheduler.Context, recordChan *chan
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -10174,7 +10531,7 @@ func TriggerDeleteOldNamespaces(contexts *[]*scheduler.Context, recordChan *chan
 }
 
 func TriggerAsyncDRMigrationSchedule(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(AsyncDRMigrationSchedule)
 	startLongevityTest(AsyncDRMigrationSchedule)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Async DR Migrationschedule triggered at: %v", time.Now())
@@ -10187,7 +10544,7 @@ func TriggerAsyncDRMigrationSchedule(contexts *[]*scheduler.Context, recordChan
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -10310,7 +10667,7 @@ func TriggerAsyncDRMigrationSchedule(contexts *[]*scheduler.Context, recordChan
 }
 
 func TriggerStorkVolumeSnapshotSchedule(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(StorkVolumeSnapshotSchedule)
 	startLongevityTest(StorkVolumeSnapshotSchedule)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Stork volume snapshot schedule triggered at: %v", time.Now())
@@ -10323,7 +10680,7 @@ func TriggerStorkVolumeSnapshotSchedule(contexts *[]*scheduler.Context, recordCh
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -10374,7 +10731,7 @@ func TriggerStorkVolumeSnapshotSchedule(contexts *[]*scheduler.Context, recordCh
 }
 
 func TriggerStorkVolumeSnapshotScheduleLocal(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(StorkVolumeSnapshotScheduleLocal)
 	startLongevityTest(StorkVolumeSnapshotScheduleLocal)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Stork volume snapshot schedule triggered at: %v", time.Now())
@@ -10387,7 +10744,7 @@ func TriggerStorkVolumeSnapshotScheduleLocal(contexts *[]*scheduler.Context, rec
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -10438,7 +10795,7 @@ func TriggerStorkVolumeSnapshotScheduleLocal(contexts *[]*scheduler.Context, rec
 }
 
 func TriggerMetroDRMigrationSchedule(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(MetroDRMigrationSchedule)
 	startLongevityTest(MetroDRMigrationSchedule)
 	defer ginkgo.GinkgoRecover()
 	log.InfoD("Metro DR Migrationschedule triggered at: %v", time.Now())
@@ -10451,7 +10808,7 @@ func TriggerMetroDRMigrationSchedule(contexts *[]*scheduler.Context, recordChan
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -10603,7 +10960,7 @@ func TriggerAggrVolDepReplResizeOps(contexts *[]*scheduler.Context, recordChan *
 	   TO run this test please make sure to run the applications which creates aggr volumes
 	*/
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(AggrVolDepReplResizeOps)
 	startLongevityTest(AggrVolDepReplResizeOps)
 	event := &EventRecord{
 		Event: Event{
@@ -10615,7 +10972,7 @@ func TriggerAggrVolDepReplResizeOps(contexts *[]*scheduler.Context, recordChan *
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -10647,6 +11004,7 @@ func TriggerAggrVolDepReplResizeOps(contexts *[]*scheduler.Context, recordChan *
 				// Pick volumes with aggr level > 1
 				if aggrLevel > 1 {
 					allVolsCreated = append(allVolsCreated, eachVol)
+					event.AddTargetVolume(eachVol.ID)
 				}
 			}
 		}
@@ -10788,7 +11146,7 @@ func TriggerAggrVolDepReplResizeOps(contexts *[]*scheduler.Context, recordChan *
 }
 func TriggerAddOCPStorageNode(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 
-	defer endLongevityTest()
+	defer endLongevityTest(AddStorageNode)
 	startLongevityTest(AddStorageNode)
 	defer ginkgo.GinkgoRecover()
 
@@ -10802,7 +11160,7 @@ func TriggerAddOCPStorageNode(contexts *[]*scheduler.Context, recordChan *chan *
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -10930,7 +11288,7 @@ func TriggerAddOCPStorageNode(contexts *[]*scheduler.Context, recordChan *chan *
 
 func TriggerAddOCPStoragelessNode(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 
-	defer endLongevityTest()
+	defer endLongevityTest(AddStoragelessNode)
 	startLongevityTest(AddStoragelessNode)
 	defer ginkgo.GinkgoRecover()
 
@@ -10944,7 +11302,7 @@ func TriggerAddOCPStoragelessNode(contexts *[]*scheduler.Context, recordChan *ch
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -11047,7 +11405,7 @@ func TriggerAddOCPStoragelessNode(contexts *[]*scheduler.Context, recordChan *ch
 
 func TriggerOCPStorageNodeRecycle(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 
-	defer endLongevityTest()
+	defer endLongevityTest(OCPStorageNodeRecycle)
 	startLongevityTest(OCPStorageNodeRecycle)
 	defer ginkgo.GinkgoRecover()
 
@@ -11061,7 +11419,7 @@ func TriggerOCPStorageNodeRecycle(contexts *[]*scheduler.Context, recordChan *ch
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -11084,8 +11442,9 @@ func TriggerOCPStorageNodeRecycle(contexts *[]*scheduler.Context, recordChan *ch
 	Step(stepLog, func() {
 		log.InfoD(stepLog)
 
-		index := randIntn(1, len(stNodes))[0]
+		index := randIntn(triggerRand(event.Event.Type), 1, len(stNodes))[0]
 		delNode := stNodes[index]
+		event.AddTargetNode(delNode.Name)
 		Step(
 			fmt.Sprintf("Recycle a storage node: [%s] and validating the drives", delNode.Name),
 			func() {
@@ -11149,7 +11508,7 @@ func TriggerOCPStorageNodeRecycle(contexts *[]*scheduler.Context, recordChan *ch
 // TriggerReallocSharedMount peforms sharedv4 and sharedv4_svc volumes reallocation
 func TriggerReallocSharedMount(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(ReallocateSharedMount)
 	startLongevityTest(ReallocateSharedMount)
 	event := &EventRecord{
 		Event: Event{
@@ -11161,7 +11520,7 @@ func TriggerReallocSharedMount(contexts *[]*scheduler.Context, recordChan *chan
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	setMetrics(*event)
@@ -11214,6 +11573,8 @@ func TriggerReallocSharedMount(contexts *[]*scheduler.Context, recordChan *chan
 						UpdateOutcome(event, err)
 					})
 
+					event.AddTargetNode(n.Name)
+					event.AddTargetVolume(vol.ID)
 					dashStats := make(map[string]string)
 					dashStats["node"] = n.Name
 					dashStats["volume"] = vol.Name
@@ -11276,7 +11637,7 @@ func TriggerReallocSharedMount(contexts *[]*scheduler.Context, recordChan *chan
 // TriggerCreateAndRunFioOnVcluster creates and runs fio on vcluster
 func TriggerCreateAndRunFioOnVcluster(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CreateAndRunFioOnVcluster)
 	startLongevityTest(CreateAndRunFioOnVcluster)
 	event := &EventRecord{
 		Event: Event{
@@ -11287,7 +11648,7 @@ func TriggerCreateAndRunFioOnVcluster(contexts *[]*scheduler.Context, recordChan
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -11373,7 +11734,7 @@ func TriggerCreateAndRunFioOnVcluster(contexts *[]*scheduler.Context, recordChan
 // TriggerCreateAndRunMultipleFioOnVcluster creates and runs multiple fio on vcluster
 func TriggerCreateAndRunMultipleFioOnVcluster(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(CreateAndRunMultipleFioOnVcluster)
 	startLongevityTest(CreateAndRunMultipleFioOnVcluster)
 	event := &EventRecord{
 		Event: Event{
@@ -11384,7 +11745,7 @@ func TriggerCreateAndRunMultipleFioOnVcluster(contexts *[]*scheduler.Context, re
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -11517,7 +11878,7 @@ func TriggerCreateAndRunMultipleFioOnVcluster(contexts *[]*scheduler.Context, re
 
 func TriggerVolumeDriverDownVCluster(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(VolumeDriverDownVCluster)
 	startLongevityTest(VolumeDriverDownVCluster)
 	event := &EventRecord{
 		Event: Event{
@@ -11528,7 +11889,7 @@ func TriggerVolumeDriverDownVCluster(contexts *[]*scheduler.Context, recordChan
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 	vc := &vcluster.VCluster{}
@@ -11606,6 +11967,7 @@ func TriggerVolumeDriverDownVCluster(contexts *[]*scheduler.Context, recordChan
 			for _, podNode := range podNodes {
 				if appNode.Name == podNode {
 					nodesToReboot = append(nodesToReboot, appNode.Name)
+					event.AddTargetNode(appNode.Name)
 				}
 			}
 		}
@@ -11665,7 +12027,7 @@ func TriggerVolumeDriverDownVCluster(contexts *[]*scheduler.Context, recordChan
 
 func TriggerSetDiscardMounts(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(SetDiscardMounts)
 	startLongevityTest(SetDiscardMounts)
 	event := &EventRecord{
 		Event: Event{
@@ -11676,7 +12038,7 @@ func TriggerSetDiscardMounts(contexts *[]*scheduler.Context, recordChan *chan *E
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -11693,6 +12055,7 @@ func TriggerSetDiscardMounts(contexts *[]*scheduler.Context, recordChan *chan *E
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 			// Setting discard-mount-option on the node
 			err = SetUnSetDiscardMountRTOptions(&appNode, false)
 			if err != nil {
@@ -11712,7 +12075,7 @@ func TriggerSetDiscardMounts(contexts *[]*scheduler.Context, recordChan *chan *E
 
 func TriggerResetDiscardMounts(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
 	defer ginkgo.GinkgoRecover()
-	defer endLongevityTest()
+	defer endLongevityTest(ResetDiscardMounts)
 	startLongevityTest(ResetDiscardMounts)
 	event := &EventRecord{
 		Event: Event{
@@ -11723,7 +12086,7 @@ func TriggerResetDiscardMounts(contexts *[]*scheduler.Context, recordChan *chan
 		Outcome: []error{},
 	}
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -11740,6 +12103,7 @@ func TriggerResetDiscardMounts(contexts *[]*scheduler.Context, recordChan *chan
 				UpdateOutcome(event, err)
 				continue
 			}
+			event.AddTargetNode(appNode.Name)
 			// Setting discard-mount-option on the node
 			err = SetUnSetDiscardMountRTOptions(&appNode, true)
 			if err != nil {
@@ -11758,7 +12122,7 @@ func TriggerResetDiscardMounts(contexts *[]*scheduler.Context, recordChan *chan
 }
 
 func TriggerScaleFADAVolumeAttach(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(ScaleFADAVolumeAttach)
 	startLongevityTest(ScaleFADAVolumeAttach)
 	defer ginkgo.GinkgoRecover()
 	var wg sync.WaitGroup
@@ -11773,7 +12137,7 @@ func TriggerScaleFADAVolumeAttach(contexts *[]*scheduler.Context, recordChan *ch
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -11838,7 +12202,7 @@ func TriggerScaleFADAVolumeAttach(contexts *[]*scheduler.Context, recordChan *ch
 }
 
 func TriggerDefragScheduleCRUDOps(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(DefragScheduleCRUDOperations)
 	startLongevityTest(DefragScheduleCRUDOperations)
 	defer ginkgo.GinkgoRecover()
 
@@ -11852,7 +12216,7 @@ func TriggerDefragScheduleCRUDOps(contexts *[]*scheduler.Context, recordChan *ch
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -11946,7 +12310,7 @@ func TriggerDefragScheduleCRUDOps(contexts *[]*scheduler.Context, recordChan *ch
 }
 
 func TriggerDefragSchedules(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(DefragSchedules)
 	startLongevityTest(DefragSchedules)
 	defer ginkgo.GinkgoRecover()
 
@@ -11960,7 +12324,7 @@ func TriggerDefragSchedules(contexts *[]*scheduler.Context, recordChan *chan *Ev
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -12040,7 +12404,7 @@ func TriggerDefragSchedules(contexts *[]*scheduler.Context, recordChan *chan *Ev
 }
 
 func TriggerSvMotionSingleNode(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(SVMotionSingleNode)
 	startLongevityTest(SVMotionSingleNode)
 	defer ginkgo.GinkgoRecover()
 
@@ -12054,7 +12418,7 @@ func TriggerSvMotionSingleNode(contexts *[]*scheduler.Context, recordChan *chan
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -12067,14 +12431,15 @@ func TriggerSvMotionSingleNode(contexts *[]*scheduler.Context, recordChan *chan
 
 		workerNodes := node.GetStorageNodes()
 		if len(workerNodes) > 0 {
-			randomIndex = rand.Intn(len(workerNodes))
+			randomIndex = triggerRand(event.Event.Type).Intn(len(workerNodes))
+			event.AddTargetNode(workerNodes[randomIndex].Name)
 			log.Infof("Selected worker node %v for storage vmotion", workerNodes[randomIndex].Name)
 		} else {
 			log.Infof("No worker nodes available")
 			UpdateOutcome(event, fmt.Errorf("No worker nodes available for svmotion"))
 			return
 		}
-		moveAllDisks = rand.Intn(2) == 0
+		moveAllDisks = triggerRand(event.Event.Type).Intn(2) == 0
 		if moveAllDisks {
 			log.Infof("Moving all disks on worker node %v", workerNodes[randomIndex].Name)
 		} else {
@@ -12123,7 +12488,7 @@ func TriggerSvMotionSingleNode(contexts *[]*scheduler.Context, recordChan *chan
 }
 
 func TriggerSvMotionMultipleNodes(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
-	defer endLongevityTest()
+	defer endLongevityTest(SVMotionMultipleNodes)
 	startLongevityTest(SVMotionMultipleNodes)
 	defer ginkgo.GinkgoRecover()
 
@@ -12137,7 +12502,7 @@ func TriggerSvMotionMultipleNodes(contexts *[]*scheduler.Context, recordChan *ch
 	}
 
 	defer func() {
-		event.End = time.Now().Format(time.RFC1123)
+		endEventRecord(event)
 		*recordChan <- event
 	}()
 
@@ -12156,7 +12521,7 @@ func TriggerSvMotionMultipleNodes(contexts *[]*scheduler.Context, recordChan *ch
 			return
 		}
 
-		rand.Shuffle(len(workerNodes), func(i, j int) {
+		triggerRand(event.Event.Type).Shuffle(len(workerNodes), func(i, j int) {
 			workerNodes[i], workerNodes[j] = workerNodes[j], workerNodes[i]
 		})
 
@@ -12191,10 +12556,12 @@ func TriggerSvMotionMultipleNodes(contexts *[]*scheduler.Context, recordChan *ch
 		}
 
 		for i := 0; i < numSelectedNodes; i++ {
-			go func(node node.Node) {
+			event.AddTargetNode(workerNodes[i].Name)
+			// draw before spawning so the decisions are taken in node order
+			moveAllDisks := triggerRand(event.Event.Type).Intn(2) == 0
+			go func(node node.Node, moveAllDisks bool) {
 				defer wg.Done()
 
-				moveAllDisks := rand.Intn(2) == 0
 				if moveAllDisks {
 					log.Infof("Moving all disks on worker node %v", node.Name)
 				} else {
@@ -12216,7 +12583,7 @@ func TriggerSvMotionMultipleNodes(contexts *[]*scheduler.Context, recordChan *ch
 				err = ValidateDatastoreUpdate(preData, postData, node.VolDriverNodeID, targetDatastore)
 				UpdateOutcome(event, err)
 
-			}(workerNodes[i])
+			}(workerNodes[i], moveAllDisks)
 		}
 
 		wg.Wait()
@@ -12764,3 +13131,68 @@ $('#pxtable tr td').each(function(){
 </table>
 </body>
 </html>`
+
+// TriggerHelmUpgradeRollback upgrades the helm release of each app deployed with helm, then rolls it back
+// to the revision it was deployed with, validating the app after the upgrade and after the downgrade
+func TriggerHelmUpgradeRollback(contexts *[]*scheduler.Context, recordChan *chan *EventRecord) {
+	defer ginkgo.GinkgoRecover()
+	defer endLongevityTest(HelmUpgradeRollback)
+	startLongevityTest(HelmUpgradeRollback)
+	event := &EventRecord{
+		Event: Event{
+			ID:   GenerateUUID(),
+			Type: HelmUpgradeRollback,
+		},
+		Start:   time.Now().Format(time.RFC1123),
+		Outcome: []error{},
+	}
+	defer func() {
+		endEventRecord(event)
+		*recordChan <- event
+	}()
+
+	setMetrics(*event)
+	validate := func(ctx *scheduler.Context, action string) {
+		ctx.SkipVolumeValidation = false
+		errorChan := make(chan error, errorChannelSize)
+		ValidateContext(ctx, &errorChan)
+		for err := range errorChan {
+			UpdateOutcome(event, fmt.Errorf("failed to validate app [%s] after the %s: %v", ctx.App.Key, action, err))
+		}
+	}
+	for _, ctx := range *contexts {
+		for _, appSpec := range ctx.App.SpecList {
+			repoInfo, ok := appSpec.(*scheduler.HelmRepo)
+			if !ok {
+				continue
+			}
+			stepLog := fmt.Sprintf("Upgrade and roll back release [%s] of app [%s]", repoInfo.ReleaseName, ctx.App.Key)
+			Step(stepLog, func() {
+				log.InfoD(stepLog)
+				history, err := Inst().S.HelmHistory(repoInfo)
+				if err == nil && len(history) == 0 {
+					err = fmt.Errorf("release has no revision")
+				}
+				if err != nil {
+					UpdateOutcome(event, fmt.Errorf("failed to get the history of release [%s]: %v", repoInfo.ReleaseName, err))
+					return
+				}
+				deployed := history[len(history)-1]
+				log.Infof("Upgrading release [%s] from revision [%d] of chart version [%s]", repoInfo.ReleaseName, deployed.Revision, deployed.ChartVersion)
+				if _, err = Inst().S.UpgradeChart(repoInfo); err != nil {
+					UpdateOutcome(event, fmt.Errorf("failed to upgrade release [%s]: %v", repoInfo.ReleaseName, err))
+					return
+				}
+				validate(ctx, "upgrade")
+
+				log.Infof("Rolling back release [%s] to revision [%d]", repoInfo.ReleaseName, deployed.Revision)
+				if _, err = Inst().S.RollbackChart(repoInfo, deployed.Revision); err != nil {
+					UpdateOutcome(event, fmt.Errorf("failed to roll back release [%s]: %v", repoInfo.ReleaseName, err))
+					return
+				}
+				validate(ctx, "rollback")
+			})
+		}
+	}
+	updateMetrics(*event)
+}
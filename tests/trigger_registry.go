@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/portworx/torpedo/drivers/scheduler"
+)
+
+// LongevityTriggers returns the test triggers run by the longevity test, by
+// name. It returns a new map on each call, so callers may change it.
+func LongevityTriggers() map[string]func(*[]*scheduler.Context, *chan *EventRecord) {
+	return map[string]func(*[]*scheduler.Context, *chan *EventRecord){
+		DeployApps:                        TriggerDeployNewApps,
+		RebootNode:                        TriggerRebootNodes,
+		ValidatePdsApps:                   TriggerValidatePdsApps,
+		CrashNode:                         TriggerCrashNodes,
+		CrashPXDaemon:                     TriggerCrashPXDaemon,
+		RestartVolDriver:                  TriggerRestartVolDriver,
+		CrashVolDriver:                    TriggerCrashVolDriver,
+		HAIncrease:                        TriggerHAIncrease,
+		HADecrease:                        TriggerHADecrease,
+		VolumeClone:                       TriggerVolumeClone,
+		VolumeResize:                      TriggerVolumeResize,
+		AppTaskDown:                       TriggerAppTaskDown,
+		AppTasksDown:                      TriggerAppTasksDown,
+		AddDrive:                          TriggerAddDrive,
+		CoreChecker:                       TriggerCoreChecker,
+		CloudSnapShot:                     TriggerCloudSnapShot,
+		LocalSnapShot:                     TriggerLocalSnapShot,
+		DeleteLocalSnapShot:               TriggerDeleteLocalSnapShot,
+		MetadataPoolResizeDisk:            TriggerMetadataPoolResizeDisk,
+		PoolAddDisk:                       TriggerPoolAddDisk,
+		UpgradeStork:                      TriggerUpgradeStork,
+		VolumesDelete:                     TriggerVolumeDelete,
+		UpgradeVolumeDriver:               TriggerUpgradeVolumeDriver,
+		AutoFsTrim:                        TriggerAutoFsTrim,
+		UpdateVolume:                      TriggerVolumeUpdate,
+		UpdateIOProfile:                   TriggerVolumeIOProfileUpdate,
+		RestartManyVolDriver:              TriggerRestartManyVolDriver,
+		RebootManyNodes:                   TriggerRebootManyNodes,
+		NodeDecommission:                  TriggerNodeDecommission,
+		NodeRejoin:                        TriggerNodeRejoin,
+		CsiSnapShot:                       TriggerCsiSnapShot,
+		CsiSnapRestore:                    TriggerCsiSnapRestore,
+		RelaxedReclaim:                    TriggerRelaxedReclaim,
+		Trashcan:                          TriggerTrashcan,
+		KVDBFailover:                      TriggerKVDBFailover,
+		ValidateDeviceMapper:              TriggerValidateDeviceMapperCleanup,
+		MetroDR:                           TriggerMetroDR,
+		AsyncDR:                           TriggerAsyncDR,
+		AsyncDRMigrationSchedule:          TriggerAsyncDRMigrationSchedule,
+		ConfluentAsyncDR:                  TriggerConfluentAsyncDR,
+		KafkaAsyncDR:                      TriggerKafkaAsyncDR,
+		MongoAsyncDR:                      TriggerMongoAsyncDR,
+		AsyncDRVolumeOnly:                 TriggerAsyncDRVolumeOnly,
+		AutoFsTrimAsyncDR:                 TriggerAutoFsTrimAsyncDR,
+		DetachDrives:                      TriggerDetachDrives,
+		IopsBwAsyncDR:                     TriggerIopsBwAsyncDR,
+		StorkApplicationBackup:            TriggerStorkApplicationBackup,
+		StorkAppBkpVolResize:              TriggerStorkAppBkpVolResize,
+		StorkAppBkpHaUpdate:               TriggerStorkAppBkpHaUpdate,
+		StorkAppBkpPxRestart:              TriggerStorkAppBkpPxRestart,
+		StorkAppBkpPoolResize:             TriggerStorkAppBkpPoolResize,
+		StorkVolumeSnapshotSchedule:       TriggerStorkVolumeSnapshotSchedule,
+		StorkVolumeSnapshotScheduleLocal:  TriggerStorkVolumeSnapshotScheduleLocal,
+		RestartKvdbVolDriver:              TriggerRestartKvdbVolDriver,
+		HAIncreaseAndReboot:               TriggerHAIncreaseAndReboot,
+		AddDiskAndReboot:                  TriggerPoolAddDiskAndReboot,
+		ResizeDiskAndReboot:               TriggerPoolResizeDiskAndReboot,
+		AutopilotRebalance:                TriggerAutopilotPoolRebalance,
+		DeleteOldNamespaces:               TriggerDeleteOldNamespaces,
+		DeleteCloudsnaps:                  TriggerDeleteCloudsnaps,
+		MetroDRMigrationSchedule:          TriggerMetroDRMigrationSchedule,
+		CloudSnapShotRestore:              TriggerCloudSnapshotRestore,
+		LocalSnapShotRestore:              TriggerLocalSnapshotRestore,
+		AggrVolDepReplResizeOps:           TriggerAggrVolDepReplResizeOps,
+		AddStorageNode:                    TriggerAddOCPStorageNode,
+		AddStoragelessNode:                TriggerAddOCPStoragelessNode,
+		OCPStorageNodeRecycle:             TriggerOCPStorageNodeRecycle,
+		HAIncreaseAndCrashPX:              TriggerHAIncreaseAndCrashPX,
+		HAIncreaseAndRestartPX:            TriggerHAIncreaseAndPXRestart,
+		NodeMaintenanceCycle:              TriggerNodeMaintenanceCycle,
+		PoolMaintenanceCycle:              TriggerPoolMaintenanceCycle,
+		StorageFullPoolExpansion:          TriggerStorageFullPoolExpansion,
+		HAIncreaseWithPVCResize:           TriggerHAIncreasWithPVCResize,
+		ReallocateSharedMount:             TriggerReallocSharedMount,
+		CreateAndRunFioOnVcluster:         TriggerCreateAndRunFioOnVcluster,
+		CreateAndRunMultipleFioOnVcluster: TriggerCreateAndRunMultipleFioOnVcluster,
+		VolumeDriverDownVCluster:          TriggerVolumeDriverDownVCluster,
+		SetDiscardMounts:                  TriggerSetDiscardMounts,
+		PowerOffAllVMs:                    TriggerPowerOffAllVMs,
+		ResetDiscardMounts:                TriggerResetDiscardMounts,
+		ScaleFADAVolumeAttach:             TriggerScaleFADAVolumeAttach,
+		RestartKubeletService:             TriggerKubeletRestart,
+		PoolDelete:                        TriggerPoolDelete,
+		DefragScheduleCRUDOperations:      TriggerDefragScheduleCRUDOps,
+		DefragSchedules:                   TriggerDefragSchedules,
+		SVMotionSingleNode:                TriggerSvMotionSingleNode,
+		SVMotionMultipleNodes:             TriggerSvMotionMultipleNodes,
+		HelmUpgradeRollback:               TriggerHelmUpgradeRollback,
+	}
+}
+
+// LongevityTriggerNames returns the sorted names of the longevity test triggers
+func LongevityTriggerNames() []string {
+	var names []string
+	for name := range LongevityTriggers() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateTriggerSettings returns an error if the trigger reads settings which are not set.
+// The longevity test fills them from its config map and the torpedo flags, other runners of
+// the triggers, for e.g. the API server, validate them before running the trigger.
+func ValidateTriggerSettings(name string) error {
+	switch name {
+	case UpgradeVolumeDriver, UpgradeVolumeDriverFromCatalog, UpgradeStork:
+		if Inst().UpgradeStorageDriverEndpointList == "" {
+			return fmt.Errorf("trigger [%s] needs the upgrade endpoints set with --%s", name, upgradeStorageDriverEndpointListFlag)
+		}
+	case UpgradeCluster:
+		if Inst().SchedUpgradeHops == "" {
+			return fmt.Errorf("trigger [%s] needs the upgrade hops set with --sched-upgrade-hops", name)
+		}
+	case AsyncDRMigrationSchedule, MetroDRMigrationSchedule:
+		if MigrationInterval <= 0 || MigrationsCount <= 0 {
+			return fmt.Errorf("trigger [%s] needs a positive [%s] and [%s]", name, MigrationIntervalField, MigrationsCountField)
+		}
+	}
+	return nil
+}